@@ -1,53 +1,237 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/pattyshack/bad/debugger"
+	. "github.com/pattyshack/bad/debugger/common"
 )
 
 func backtrace(db *debugger.Debugger, args string) error {
-	args = strings.TrimSpace(args)
-	if strings.HasPrefix("up", args) {
-		db.InspectCalleeFrame()
-	} else if strings.HasPrefix("down", args) {
-		db.InspectCallerFrame()
+	showArgs := false
+	verbose := false
+	direction := ""
+	for _, arg := range splitAllArgs(args) {
+		if arg == "-args" {
+			showArgs = true
+		} else if arg == "-verbose" {
+			verbose = true
+		} else {
+			direction = arg
+		}
+	}
+
+	if direction != "" {
+		if strings.HasPrefix("up", direction) {
+			db.InspectCalleeFrame()
+		} else if strings.HasPrefix("down", direction) {
+			db.InspectCallerFrame()
+		}
 	}
 
 	inspectFrame, backtraceStack := db.BacktraceStack()
 
 	fmt.Println("Backtrace:")
 	for idx, frame := range backtraceStack {
-		prefix := "  "
-		if inspectFrame == frame {
-			prefix = " *"
+		printFrame(db, inspectFrame, idx, frame, showArgs, verbose)
+	}
+
+	return nil
+}
+
+func inspectCalleeFrame(db *debugger.Debugger, args string) error {
+	return backtrace(db, "up "+args)
+}
+
+func inspectCallerFrame(db *debugger.Debugger, args string) error {
+	return backtrace(db, "down "+args)
+}
+
+func selectFrame(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+
+	subCmd, remaining := splitArg(args)
+	if subCmd == "apply" {
+		return applyToAllFrames(db, remaining)
+	}
+
+	if args != "" {
+		idx, err := strconv.ParseInt(args, 10, 32)
+		if err != nil {
+			fmt.Println("invalid frame index:", args)
+			return nil
 		}
 
-		inlinedStr := ""
-		if frame.IsInlined() {
-			inlinedStr = fmt.Sprintf("(inlined in %s) ", frame.BaseFrame.Name)
+		err = db.InspectFrame(int(idx))
+		if err != nil {
+			if errors.Is(err, ErrInvalidInput) {
+				fmt.Println(err)
+				return nil
+			}
+			return err
 		}
+	}
 
-		libStr := ""
-		elfFileName := ""
-		if frame.SourceFile != nil {
-			elfFileName = frame.SourceFile.CompileUnit.File.FileName
+	inspectFrame, backtraceStack := db.BacktraceStack()
+	for idx, frame := range backtraceStack {
+		if frame == inspectFrame {
+			printFrame(db, inspectFrame, idx, frame, true, false)
+			return nil
 		}
-		if elfFileName != "" {
-			libStr = fmt.Sprintf(" [%s]", path.Base(elfFileName))
+	}
+
+	fmt.Println("no frame selected")
+	return nil
+}
+
+func printFrame(
+	db *debugger.Debugger,
+	inspectFrame *debugger.CallFrame,
+	idx int,
+	frame *debugger.CallFrame,
+	showArgs bool,
+	verbose bool,
+) {
+	prefix := "  "
+	if inspectFrame == frame {
+		prefix = " *"
+	}
+
+	baseFrame := frame
+	if frame.IsInlined() {
+		baseFrame = frame.BaseFrame
+	}
+
+	marker := ""
+	if verbose {
+		marker = " "
+		if baseFrame.UnwindWarning != "" {
+			marker = "?"
 		}
+	}
+
+	inlinedStr := ""
+	if frame.IsInlined() {
+		inlinedStr = fmt.Sprintf("(inlined in %s) ", frame.BaseFrame.Name)
+	}
+
+	libStr := ""
+	elfFileName := ""
+	if frame.SourceFile != nil {
+		elfFileName = frame.SourceFile.CompileUnit.File.FileName
+	}
+	if elfFileName != "" {
+		libStr = fmt.Sprintf(" [%s]", path.Base(elfFileName))
+	}
 
-		fmt.Printf(
-			"%s%2d. %s %s%s\n",
-			prefix,
-			idx,
-			frame.BacktraceProgramCounter,
-			inlinedStr,
-			frame.Name)
-		fmt.Printf("        %s:%d%s\n", frame.SourceFile, frame.SourceLine, libStr)
+	argsStr := ""
+	if showArgs {
+		argsStr = formatFrameArguments(db, frame)
+	}
+
+	fmt.Printf(
+		"%s%2d.%s %s %s%s%s\n",
+		prefix,
+		idx,
+		marker,
+		frame.BacktraceProgramCounter,
+		inlinedStr,
+		frame.Name,
+		argsStr)
+	fmt.Printf("        %s:%d%s\n", frame.SourceFile, frame.SourceLine, libStr)
+
+	if verbose {
+		printUnwindProvenance(frame, baseFrame)
+	}
+}
+
+func printUnwindProvenance(frame *debugger.CallFrame, baseFrame *debugger.CallFrame) {
+	if frame.IsInlined() {
+		fmt.Printf("        inlined: shares %s's unwound frame\n", baseFrame.Name)
+		return
+	}
+
+	cfaStr := "unknown"
+	cfa, err := frame.CanonicalFrameAddress()
+	if err == nil {
+		cfaStr = VirtualAddress(cfa).String()
+	}
+
+	returnStr := "unknown"
+	if frame.ReturnAddress != 0 {
+		returnStr = frame.ReturnAddress.String()
+	}
+
+	fmt.Printf(
+		"        unwound from: %s, cfa: %s, return address: %s\n",
+		frame.UnwindSource,
+		cfaStr,
+		returnStr)
+
+	if frame.UnwindWarning != "" {
+		fmt.Printf("        unreliable: %s\n", frame.UnwindWarning)
+	}
+}
+
+func applyToAllFrames(db *debugger.Debugger, args string) error {
+	scope, expr := splitArg(args)
+	expr = strings.TrimSpace(expr)
+	if scope != "all" || expr == "" {
+		fmt.Println("expected: frame apply all <expression>")
+		return nil
+	}
+
+	results, err := db.EvaluateInAllFrames(expr)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("expression not resolved in any frame:", expr)
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("%2d. %s\n", result.Index, result.Name)
+		fmt.Println(result.Value.Format("      "))
+	}
+
+	return nil
+}
+
+func formatFrameArguments(db *debugger.Debugger, frame *debugger.CallFrame) string {
+	args, err := db.FrameArguments(frame)
+	if err != nil {
+		return fmt.Sprintf("(<unavailable: %s>)", err)
+	}
+
+	argStrs := make([]string, 0, len(args))
+	for _, arg := range args {
+		argStrs = append(argStrs, arg.Format(db.FrameArgumentsMode))
+	}
+
+	return "(" + strings.Join(argStrs, ", ") + ")"
+}
+
+func setPrintFrameArguments(db *debugger.Debugger, args string) error {
+	mode := debugger.FrameArgumentsMode(strings.TrimSpace(args))
+	if mode == "" {
+		fmt.Println("frame arguments mode (scalars/all/none) not specified")
+		return nil
+	}
+
+	for _, valid := range debugger.FrameArgumentsModes {
+		if mode == valid {
+			db.FrameArgumentsMode = mode
+			fmt.Println("frame arguments mode set to", mode)
+			return nil
+		}
 	}
 
+	fmt.Println("invalid frame arguments mode:", mode)
 	return nil
 }
@@ -1,10 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
+	"syscall"
 
+	"golang.org/x/sys/unix"
+
+	"github.com/pattyshack/bad/debugger"
 	"github.com/pattyshack/bad/debugger/catchpoint"
+	. "github.com/pattyshack/bad/debugger/common"
 )
 
 type syscallCatchPolicyCommands struct {
@@ -29,9 +36,16 @@ func (cmd syscallCatchPolicyCommands) SubCommands() subCommands {
 			command:     runCmd(cmd.CatchAll),
 		},
 		{
-			name:        "list",
-			description: " <syscall name/number>+ - catch listed syscalls",
-			command:     runCmd(cmd.CatchList),
+			name: "list",
+			description: ":\n" +
+				"    list <syscall name/number>+\n" +
+				"        - catch listed syscalls\n" +
+				"    list <syscall name/number>+ if <arg0-5|ret> <op> <value>\n" +
+				"        - catch listed syscalls when the condition holds.  op is one\n" +
+				"          of ==, !=, <, <=, >, >=, & (any bits set) or contains\n" +
+				"          (the argument is a string pointer, e.g., " +
+				"arg1 contains \"secret\")",
+			command: runCmd(cmd.CatchList),
 		},
 	}
 }
@@ -52,6 +66,17 @@ func (cmd syscallCatchPolicyCommands) CatchAll(args string) error {
 }
 
 func (cmd syscallCatchPolicyCommands) CatchList(argsStr string) error {
+	var condition *catchpoint.SyscallCondition
+	argsStr, conditionStr, hasCondition := strings.Cut(argsStr, " if ")
+	if hasCondition {
+		var err error
+		condition, err = catchpoint.ParseSyscallCondition(conditionStr)
+		if err != nil {
+			fmt.Println(err)
+			return nil
+		}
+	}
+
 	args := splitAllArgs(argsStr)
 
 	if len(args) == 0 {
@@ -82,6 +107,207 @@ func (cmd syscallCatchPolicyCommands) CatchList(argsStr string) error {
 		ids = append(ids, id)
 	}
 
-	cmd.policy.CatchList(ids)
+	cmd.policy.CatchListIf(ids, condition)
+	return nil
+}
+
+type exceptionCatchPointCommands struct {
+	debugger *debugger.Debugger
+}
+
+func (cmd exceptionCatchPointCommands) SubCommands() subCommands {
+	return subCommands{
+		{
+			name:        "current",
+			description: "     - print current exception catch points",
+			command:     runCmd(cmd.PrintCurrent),
+		},
+		{
+			name:        "throw",
+			description: " [off] - catch (or stop catching) thrown exceptions",
+			command:     runCmd(cmd.eventCmd(catchpoint.ThrowEvent)),
+		},
+		{
+			name:        "catch",
+			description: " [off] - catch (or stop catching) caught exceptions",
+			command:     runCmd(cmd.eventCmd(catchpoint.CatchEvent)),
+		},
+	}
+}
+
+func (cmd exceptionCatchPointCommands) PrintCurrent(args string) error {
+	for _, event := range catchpoint.ExceptionEvents {
+		status := "off"
+		if cmd.debugger.IsCatchingException(event) {
+			status = "on"
+		}
+		fmt.Printf("catch %s: %s\n", event, status)
+	}
+	return nil
+}
+
+func (cmd exceptionCatchPointCommands) eventCmd(
+	event catchpoint.ExceptionEvent,
+) func(string) error {
+	return func(argsStr string) error {
+		args := splitAllArgs(argsStr)
+
+		switch {
+		case len(args) == 0:
+			return cmd.debugger.CatchException(event)
+		case len(args) == 1 && args[0] == "off":
+			return cmd.debugger.IgnoreException(event)
+		default:
+			fmt.Println("invalid argument:", argsStr)
+			return nil
+		}
+	}
+}
+
+func catchSignals(db *debugger.Debugger, argsStr string) error {
+	args := splitAllArgs(argsStr)
+	if len(args) == 0 {
+		fmt.Println(formatSignalCatchPolicy(db))
+		return nil
+	}
+
+	if len(args) == 1 && args[0] == "all" {
+		db.SignalCatchPolicy.CatchAll()
+		return nil
+	}
+
+	off := false
+	if args[len(args)-1] == "off" {
+		off = true
+		args = args[:len(args)-1]
+	}
+
+	signals := []syscall.Signal{}
+	for _, arg := range args {
+		signal, ok := parseSignal(arg)
+		if !ok {
+			fmt.Println("invalid signal:", arg)
+			return nil
+		}
+		signals = append(signals, signal)
+	}
+
+	for _, signal := range signals {
+		if !off {
+			db.SignalCatchPolicy.Catch(signal)
+			continue
+		}
+
+		err := db.SignalCatchPolicy.Ignore(signal)
+		if err != nil {
+			if errors.Is(err, ErrInvalidInput) {
+				fmt.Println(err)
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatSignalCatchPolicy(db *debugger.Debugger) string {
+	ignored := db.SignalCatchPolicy.IgnoredSignals()
+	if len(ignored) == 0 {
+		return "catch all signals"
+	}
+
+	result := "catch all signals except:"
+	for _, signal := range ignored {
+		result += " " + unix.SignalName(signal)
+	}
+	return result
+}
+
+func parseCatchToggle(kind string, argsStr string) (bool, bool) {
+	args := splitAllArgs(argsStr)
+
+	switch {
+	case len(args) == 0:
+		return true, true
+	case len(args) == 1 && args[0] == "off":
+		return false, true
+	default:
+		fmt.Printf("invalid catch %s argument: %s\n", kind, argsStr)
+		return false, false
+	}
+}
+
+func catchExec(db *debugger.Debugger, argsStr string) error {
+	enabled, ok := parseCatchToggle("exec", argsStr)
+	if ok {
+		db.CatchExec = enabled
+	}
+	return nil
+}
+
+func catchFork(db *debugger.Debugger, argsStr string) error {
+	enabled, ok := parseCatchToggle("fork", argsStr)
+	if ok {
+		db.CatchFork = enabled
+	}
+	return nil
+}
+
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
+
+func printCatchPoints(db *debugger.Debugger, args string) error {
+	fmt.Println("Catch points:")
+	fmt.Println("  syscall:", db.SyscallCatchPolicy)
+	fmt.Println("  signal:", formatSignalCatchPolicy(db))
+	fmt.Println("  exec:", onOff(db.CatchExec))
+	fmt.Println("  fork:", onOff(db.CatchFork))
+	for _, event := range catchpoint.ExceptionEvents {
+		fmt.Printf(
+			"  exception %s: %s\n",
+			event,
+			onOff(db.IsCatchingException(event)))
+	}
+	return nil
+}
+
+func setStopOnExit(db *debugger.Debugger, args string) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		db.StopOnExit = true
+		fmt.Println("stop on exit enabled")
+	case "off":
+		db.StopOnExit = false
+		fmt.Println("stop on exit disabled")
+	case "":
+		fmt.Println("stop on exit mode (on/off) not specified")
+	default:
+		fmt.Println("invalid stop on exit mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
+
+func setFollowExecMode(db *debugger.Debugger, args string) error {
+	mode := debugger.FollowExecMode(strings.TrimSpace(args))
+	if mode == "" {
+		fmt.Println("follow exec mode (same/new) not specified")
+		return nil
+	}
+
+	for _, valid := range debugger.FollowExecModes {
+		if mode == valid {
+			db.FollowExecMode = mode
+			fmt.Println("follow exec mode set to", mode)
+			return nil
+		}
+	}
+
+	fmt.Println("invalid follow exec mode:", mode)
 	return nil
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+func checkpoint(db *debugger.Debugger, args string) error {
+	subCmd, remaining := splitArg(args)
+	if subCmd == "delete" {
+		return deleteCheckpoint(db, remaining)
+	} else if subCmd != "" {
+		fmt.Println("Invalid checkpoint arguments:", args)
+		return nil
+	}
+
+	checkpoint, err := db.Checkpoint()
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrProcessExited) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Printf(
+		"checkpoint (id=%d) created. forked process %d\n",
+		checkpoint.Id,
+		checkpoint.Pid)
+	return nil
+}
+
+func parseCheckpointId(args string) (int64, bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		fmt.Println("Expected one argument: <checkpoint id>")
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		fmt.Println("failed to parse checkpoint id:", err)
+		return 0, false
+	}
+
+	return id, true
+}
+
+func deleteCheckpoint(db *debugger.Debugger, args string) error {
+	id, ok := parseCheckpointId(args)
+	if !ok {
+		return nil
+	}
+
+	err := db.DeleteCheckpoint(id)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Printf("checkpoint (id=%d) deleted\n", id)
+	return nil
+}
+
+func restartCheckpoint(db *debugger.Debugger, args string) error {
+	id, ok := parseCheckpointId(args)
+	if !ok {
+		return nil
+	}
+
+	original := db.Pid
+	status, err := db.RestartCheckpoint(id)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Printf(
+		"restarted checkpoint (id=%d). switched from process %d to process %d\n",
+		id,
+		original,
+		db.Pid)
+	printThreadStatus(db, status)
+	return nil
+}
+
+func listCheckpoints(db *debugger.Debugger, args string) error {
+	checkpoints := db.Checkpoints.List()
+	fmt.Println("Checkpoints:")
+	if len(checkpoints) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	for _, checkpoint := range checkpoints {
+		status := checkpoint.Status
+
+		location := status.NextInstructionAddress.String()
+		if status.FileEntry != nil {
+			location += fmt.Sprintf(" %s:%d", status.FileEntry.Path(), status.Line)
+		}
+		if status.FunctionName != "" {
+			location += " (" + status.FunctionName + ")"
+		}
+
+		fmt.Printf(
+			"  %d: process %d (forked from thread %d) at %s\n",
+			checkpoint.Id,
+			checkpoint.Pid,
+			status.Tid,
+			location)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+func display(db *debugger.Debugger, args string) error {
+	format, args, ok := splitIntegerFormat(args)
+	if !ok {
+		return nil
+	}
+
+	args = strings.TrimSpace(args)
+	if args == "" {
+		printDisplays(db)
+		return nil
+	}
+
+	added, err := db.AddDisplay(args, format)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	printDisplayValue(db.EvaluateDisplay(added))
+	return nil
+}
+
+func undisplay(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		fmt.Println("Expected one argument: <id>")
+		return nil
+	}
+
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		fmt.Println("failed to parse display id:", err)
+		return nil
+	}
+
+	if !db.Displays.Remove(id) {
+		fmt.Printf("display (id=%d) not found\n", id)
+		return nil
+	}
+
+	fmt.Printf("display (id=%d) deleted\n", id)
+	return nil
+}
+
+func listDisplays(db *debugger.Debugger, args string) error {
+	displays := db.Displays.List()
+	fmt.Println("Displays:")
+	if len(displays) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, display := range displays {
+		fmt.Println("  " + display.String())
+	}
+	return nil
+}
+
+// NOTE: displays which can't be evaluated in the current context (e.g., out
+// of scope variables) are reported, but do not prevent other displays from
+// being printed.
+func printDisplays(db *debugger.Debugger) {
+	for _, value := range db.EvaluateDisplays() {
+		printDisplayValue(value)
+	}
+}
+
+func printDisplayValue(value debugger.DisplayValue) {
+	if value.Err != nil {
+		fmt.Printf("display %s (skipped: %s)\n", value.Display, value.Err)
+		return
+	}
+
+	fmt.Printf("display %s\n", value.Display)
+	fmt.Println(value.Value.FormatWithIntegerFormat("  ", 0, value.Format))
+}
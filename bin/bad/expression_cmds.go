@@ -2,15 +2,81 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pattyshack/bad/debugger"
-	"github.com/pattyshack/bad/debugger/registers"
-	"github.com/pattyshack/bad/dwarf"
 )
 
-func printLocalVariables(db *debugger.Debugger, args string) error {
-	locals, err := db.ListInspectFrameLocalVariables()
+type expressionCommands struct {
+	debugger *debugger.Debugger
+
+	// The maximum number of pointer levels dereferenced when printing values.
+	printDepth int
+}
+
+func (cmd *expressionCommands) SubCommands() subCommands {
+	return subCommands{
+		{
+			name:        "locals",
+			description: "                - print all local variable values",
+			command:     runCmd(cmd.printLocalVariables),
+		},
+		{
+			name:        "results",
+			description: "               - print previously evaluated results",
+			command:     runCmd(cmd.printEvaluatedResults),
+		},
+		{
+			name: "evaluate",
+			description: ":\n" +
+				"    evaluate <expression>           - print the evaluated value\n" +
+				"    evaluate/<x|d|o|t> <expression> " +
+				"- print integer values in hex / decimal / octal / binary",
+			command: runCmd(cmd.resolveVariableExpression),
+		},
+		{
+			name: "assign",
+			description: " <variable> = <value> " +
+				"- write the evaluated value to the variable",
+			command: runCmd(cmd.assignVariable),
+		},
+		{
+			name: "locate",
+			description: " <name>         " +
+				"- print the variable's dwarf evaluated location",
+			command: newFuncCmd(cmd.debugger, printVariableLocation),
+		},
+		{
+			name: "print-depth",
+			description: " [<n>]     " +
+				"- print / set the number of pointer levels dereferenced when " +
+				"printing values (default=0)",
+			command: runCmd(cmd.setPrintDepth),
+		},
+	}
+}
+
+func (cmd *expressionCommands) setPrintDepth(args string) error {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		fmt.Println("Print depth:", cmd.printDepth)
+		return nil
+	}
+
+	depth, err := strconv.ParseInt(args, 10, 32)
+	if err != nil || depth < 0 {
+		fmt.Println("invalid print depth:", args)
+		return nil
+	}
+
+	cmd.printDepth = int(depth)
+	return nil
+}
+
+func (cmd *expressionCommands) printLocalVariables(args string) error {
+	locals, err := cmd.debugger.ListInspectFrameLocalVariables()
 	if err != nil {
 		fmt.Println(err)
 		return nil
@@ -25,42 +91,97 @@ func printLocalVariables(db *debugger.Debugger, args string) error {
 		if idx > 0 {
 			fmt.Println()
 		}
-		fmt.Println(local.Format("  "))
+		fmt.Println(local.FormatWithDepth("  ", cmd.printDepth))
 	}
 
 	return nil
 }
 
-func printEvaluatedResults(db *debugger.Debugger, args string) error {
+func (cmd *expressionCommands) printEvaluatedResults(args string) error {
+	results := cmd.debugger.EvaluatedResults.List()
+
 	fmt.Println("Evaluated results:")
-	if len(db.EvaluatedResults.List()) == 0 {
+	if len(results) == 0 {
 		fmt.Println("  (none)")
 	}
-	for _, result := range db.EvaluatedResults.List() {
+	for _, result := range results {
 		if result.Index > 0 {
 			fmt.Println()
 		}
 		fmt.Printf("  $%d: %s\n", result.Index, result.Expression)
-		fmt.Println(result.Format("    "))
+		fmt.Println(result.FormatWithDepth("    ", cmd.printDepth))
 	}
 	return nil
 }
 
-func resolveVariableExpression(db *debugger.Debugger, args string) error {
+func (cmd *expressionCommands) resolveVariableExpression(args string) error {
+	format, args, ok := splitIntegerFormat(args)
+	if !ok {
+		return nil
+	}
+
 	args = strings.TrimSpace(args)
 	if args == "" {
 		fmt.Println("expected variable expression")
 		return nil
 	}
 
-	data, err := db.ResolveVariableExpression(args)
+	data, err := cmd.debugger.ResolveVariableExpression(args)
 	if err != nil {
 		fmt.Println(err)
 		return nil
 	}
 
 	fmt.Printf("$%d: %s\n", data.Index, data.Expression)
-	fmt.Println(data.Format("  "))
+	fmt.Println(data.FormatWithIntegerFormat("  ", cmd.printDepth, format))
+	return nil
+}
+
+func (cmd *expressionCommands) resolveThreadVariableExpression(
+	args string,
+) error {
+	tidStr, expression := splitArg(args)
+	expression = strings.TrimSpace(expression)
+	if tidStr == "" || expression == "" {
+		fmt.Println("expected <tid> <expression>")
+		return nil
+	}
+
+	tid, err := strconv.ParseInt(tidStr, 10, 32)
+	if err != nil {
+		fmt.Println("invalid tid:", err)
+		return nil
+	}
+
+	data, err := cmd.debugger.ResolveVariableExpressionInThread(
+		int(tid),
+		expression)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	fmt.Printf("$%d: %s (thread %d)\n", data.Index, data.Expression, tid)
+	fmt.Println(data.FormatWithDepth("  ", cmd.printDepth))
+	return nil
+}
+
+func (cmd *expressionCommands) assignVariable(args string) error {
+	variable, value, ok := strings.Cut(args, "=")
+	variable = strings.TrimSpace(variable)
+	value = strings.TrimSpace(value)
+	if !ok || variable == "" || value == "" {
+		fmt.Println("expected <variable expression> = <value expression>")
+		return nil
+	}
+
+	data, err := cmd.debugger.AssignVariable(variable, value)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	fmt.Println(data.FormatWithDepth("  ", cmd.printDepth))
 	return nil
 }
 
@@ -77,41 +198,59 @@ func printVariableLocation(db *debugger.Debugger, args string) error {
 		return nil
 	}
 
-	fmt.Println("Dwarf evaluated location")
-	if len(data.Location) == 0 {
-		fmt.Println("  (none)")
+	fmt.Println("Dwarf evaluated location of", args)
+	for _, line := range debugger.DescribeLocation(data.Location) {
+		fmt.Println(" ", line)
 	}
 
-	for idx, chunk := range data.Location {
-		fmt.Printf("  ")
-		if len(data.Location) > 1 {
-			fmt.Printf("%d. ", idx)
-		}
-		switch chunk.Kind {
-		case dwarf.UnavailableLocation:
-			fmt.Printf("(unavailable)")
-		case dwarf.AddressLocation:
-			fmt.Printf("address: 0x%x", chunk.Value)
-		case dwarf.RegisterLocation:
-			spec, ok := registers.ById(dwarf.RegisterId(chunk.Value))
-			name := spec.Name
-			if !ok {
-				name = fmt.Sprintf("<unknown %d>", chunk.Value)
-			}
-			fmt.Printf("register: %s", name)
-		case dwarf.ImplicitLiteralLocation, dwarf.ImplicitDataLocation:
-			fmt.Printf("(implicit)")
-		default:
-			panic(fmt.Sprintf("unknown location kind %s", chunk.Kind))
-		}
+	return nil
+}
 
-		if chunk.BitSize != 0 || chunk.BitOffset != 0 {
-			fmt.Printf(" BitSize: %d BitOffset: %d\n",
-				chunk.BitSize,
-				chunk.BitOffset)
+func setCallTimeout(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+	switch args {
+	case "":
+		if db.CallTimeout <= 0 {
+			fmt.Println("function call timeout: off")
 		} else {
-			fmt.Printf("\n")
+			fmt.Println("function call timeout:", db.CallTimeout)
 		}
+		return nil
+	case "off":
+		db.CallTimeout = 0
+		fmt.Println("function call timeout disabled")
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(args)
+	if err != nil || timeout <= 0 {
+		fmt.Println("invalid function call timeout:", args)
+		return nil
+	}
+
+	db.CallTimeout = timeout
+	fmt.Println("function call timeout set to", timeout)
+	return nil
+}
+
+func setUnwindOnTerminate(
+	db *debugger.Debugger,
+	args string,
+) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		db.UnwindOnTerminate = true
+		fmt.Println("unwind on terminating exception enabled")
+	case "off":
+		db.UnwindOnTerminate = false
+		fmt.Println("unwind on terminating exception disabled")
+	case "":
+		fmt.Println(
+			"unwind on terminating exception mode (on/off) not specified")
+	default:
+		fmt.Println(
+			"invalid unwind on terminating exception mode:",
+			strings.TrimSpace(args))
 	}
 
 	return nil
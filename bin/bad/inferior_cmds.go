@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+)
+
+// inferior is a debugged process, with its own debugger state (break / watch
+// points, catch points, settings, etc.) and its own command tree bound to
+// that state.
+type inferior struct {
+	id int
+
+	debugger *debugger.Debugger
+	monitor  *outputMonitor // nil if output is not redirected
+
+	// The command line at the time the inferior was added (the process'
+	// command line is unavailable once the process exits).
+	cmdline string
+
+	commands command
+}
+
+func (inf *inferior) close() error {
+	err := inf.debugger.Close()
+	if inf.monitor != nil {
+		_ = inf.monitor.Close()
+	}
+	return err
+}
+
+// inferiors is the registry of debugged processes.  Every command (other than
+// inferior) operates on the current inferior.
+//
+// NOTE: break / watch points are per inferior, i.e., a break point set in one
+// inferior is not inserted into the other inferiors.
+type inferiors struct {
+	nextId  int
+	list    []*inferior // ordered by id
+	current *inferior
+
+	// Settings for starting new inferiors (see the -cd,
+	// -disable-randomization and -redirect-output flags).
+	dir                  string
+	disableRandomization bool
+	redirectOutput       bool
+
+	// Session wide state shared by all inferiors' command trees.
+	sessionLog   *transcript
+	replPrompt   *prompt
+	quitCmd      *quitCommand
+	inferiorCmds subCommands
+}
+
+func newInferiors(
+	sessionLog *transcript,
+	dir string,
+	disableRandomization bool,
+	redirectOutput bool,
+) *inferiors {
+	infs := &inferiors{
+		nextId:               1,
+		dir:                  dir,
+		disableRandomization: disableRandomization,
+		redirectOutput:       redirectOutput,
+		sessionLog:           sessionLog,
+	}
+
+	infs.replPrompt = newPrompt(infs)
+	infs.quitCmd = newQuitCommand(infs)
+	infs.inferiorCmds = infs.SubCommands()
+	return infs
+}
+
+func (infs *inferiors) SubCommands() subCommands {
+	return subCommands{
+		{
+			name:        "list",
+			description: "                   - list all inferiors",
+			command:     runCmd(infs.listInferiors),
+		},
+		{
+			name:        "select",
+			description: " <id>            - switch to the inferior",
+			command:     runCmd(infs.selectInferior),
+		},
+		{
+			name: "start",
+			description: " <program> [<args>...] " +
+				"- start the program as a new inferior, and switch to it",
+			command: runCmd(infs.startInferior),
+		},
+		{
+			name: "attach",
+			description: " <pid>           " +
+				"- attach to the process as a new inferior, and switch to it",
+			command: runCmd(infs.attachInferior),
+		},
+		{
+			name: "remove",
+			description: " <id>            " +
+				"- kill (if started by the debugger) or detach the inferior's " +
+				"process, and remove the inferior.  The current inferior cannot " +
+				"be removed",
+			command: runCmd(infs.removeInferior),
+		},
+	}
+}
+
+// Starts the program (args[0]) as a new inferior, which becomes the current
+// inferior.
+func (infs *inferiors) start(args []string) (*inferior, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = infs.dir
+
+	// NOTE: the inferior writes directly to the debugger's stdout / stderr
+	// (i.e., the terminal) unless redirection is requested, since pipes change
+	// the inferior's behavior (e.g., isatty, buffering).  The inferior's output
+	// is never recorded by the transcript.
+	var monitor *outputMonitor
+	if infs.redirectOutput {
+		var err error
+		monitor, err = newOutputMonitor(cmd, infs.sessionLog.originalStdout())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cmd.Stdout = infs.sessionLog.originalStdout()
+		cmd.Stderr = os.Stderr
+	}
+
+	var db *debugger.Debugger
+	var err error
+	if infs.disableRandomization {
+		db, err = debugger.StartWithoutRandomizationAndAttachTo(cmd)
+	} else {
+		db, err = debugger.StartAndAttachTo(cmd)
+	}
+	if err != nil {
+		if monitor != nil {
+			_ = monitor.Close()
+		}
+		return nil, err
+	}
+
+	if monitor != nil {
+		monitor.Start()
+	}
+	return infs.add(db, monitor), nil
+}
+
+// Attaches to the process as a new inferior, which becomes the current
+// inferior.
+func (infs *inferiors) attach(pid int) (*inferior, error) {
+	db, err := debugger.AttachTo(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	return infs.add(db, nil), nil
+}
+
+func (infs *inferiors) add(
+	db *debugger.Debugger,
+	monitor *outputMonitor,
+) *inferior {
+	db.WatchThreadLifeCycle(printThreadLifeCycle)
+
+	inf := &inferior{
+		id:       infs.nextId,
+		debugger: db,
+		monitor:  monitor,
+	}
+	infs.nextId++
+
+	cmdline, _, err := db.ProcessCommandLine()
+	if err == nil {
+		inf.cmdline = quoteCommandLine(cmdline)
+	}
+
+	inf.commands = initializeCommands(db, monitor, infs)
+
+	infs.list = append(infs.list, inf)
+	infs.current = inf
+	return inf
+}
+
+// Kills / detaches all inferiors' processes.
+func (infs *inferiors) close() error {
+	var firstErr error
+	for _, inf := range infs.list {
+		err := inf.close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	infs.list = nil
+	return firstErr
+}
+
+func (infs *inferiors) parseInferiorId(args string) (*inferior, bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		fmt.Println("Expected one argument: <inferior id>")
+		return nil, false
+	}
+
+	id, err := strconv.ParseInt(args, 10, 32)
+	if err != nil {
+		fmt.Println("failed to parse inferior id:", err)
+		return nil, false
+	}
+
+	for _, inf := range infs.list {
+		if inf.id == int(id) {
+			return inf, true
+		}
+	}
+
+	fmt.Printf("inferior (id=%d) not found\n", id)
+	return nil, false
+}
+
+func (infs *inferiors) listInferiors(args string) error {
+	fmt.Println("Inferiors:")
+	for _, inf := range infs.list {
+		prefix := " "
+		if inf == infs.current {
+			prefix = "*"
+		}
+
+		db := inf.debugger
+
+		state := "started"
+		if !db.OwnsProcess() {
+			state = "attached"
+		}
+		if db.Terminated() {
+			state += ", exited"
+		}
+
+		fmt.Printf(
+			"%s %d: process %d (%s) %s\n",
+			prefix,
+			inf.id,
+			db.Pid,
+			state,
+			inf.cmdline)
+		fmt.Printf(
+			"     break points: %d  watch points: %d\n",
+			len(db.BreakPoints.List()),
+			len(db.WatchPoints.List()))
+	}
+
+	return nil
+}
+
+func (infs *inferiors) selectInferior(args string) error {
+	inf, ok := infs.parseInferiorId(args)
+	if !ok {
+		return nil
+	}
+
+	infs.current = inf
+	fmt.Printf(
+		"switched to inferior %d (process %d)\n",
+		inf.id,
+		inf.debugger.Pid)
+	fmt.Println(inf.debugger.CurrentStatus())
+	return nil
+}
+
+func (infs *inferiors) startInferior(args string) error {
+	cmdArgs := splitAllArgs(args)
+	if len(cmdArgs) == 0 {
+		fmt.Println("Expected arguments: <program> [<args>...]")
+		return nil
+	}
+
+	inf, err := infs.start(cmdArgs)
+	if err != nil {
+		fmt.Println("failed to start inferior:", err)
+		return nil
+	}
+
+	infs.printAdded(inf)
+	return nil
+}
+
+func (infs *inferiors) attachInferior(args string) error {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		fmt.Println("Expected one argument: <pid>")
+		return nil
+	}
+
+	pid, err := strconv.ParseInt(args, 10, 32)
+	if err != nil {
+		fmt.Println("failed to parse pid:", err)
+		return nil
+	}
+
+	for _, inf := range infs.list {
+		if inf.debugger.Pid == int(pid) && !inf.debugger.Terminated() {
+			fmt.Printf("process %d is already inferior %d\n", pid, inf.id)
+			return nil
+		}
+	}
+
+	inf, err := infs.attach(int(pid))
+	if err != nil {
+		fmt.Println("failed to attach inferior:", err)
+		return nil
+	}
+
+	infs.printAdded(inf)
+	return nil
+}
+
+func (infs *inferiors) printAdded(inf *inferior) {
+	fmt.Printf(
+		"inferior %d added (process %d). switched to inferior %d\n",
+		inf.id,
+		inf.debugger.Pid,
+		inf.id)
+	printProducerWarnings(inf.debugger)
+}
+
+func (infs *inferiors) removeInferior(args string) error {
+	inf, ok := infs.parseInferiorId(args)
+	if !ok {
+		return nil
+	}
+
+	if inf == infs.current {
+		fmt.Println("cannot remove the current inferior")
+		return nil
+	}
+
+	for idx, other := range infs.list {
+		if other == inf {
+			infs.list = append(infs.list[:idx], infs.list[idx+1:]...)
+			break
+		}
+	}
+
+	message := fmt.Sprintf("inferior %d removed", inf.id)
+	if !inf.debugger.Terminated() {
+		action := "detached"
+		if inf.debugger.OwnsProcess() {
+			action = "killed"
+		}
+		message += fmt.Sprintf(" (process %d %s)", inf.debugger.Pid, action)
+	}
+
+	err := inf.close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(message)
+	return nil
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+func printDeclarations(
+	header string,
+	list func(string) ([]debugger.Declaration, error),
+	args string,
+) error {
+	decls, err := list(strings.TrimSpace(args))
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println(header + ":")
+	if len(decls) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	for _, decl := range decls {
+		fmt.Println("  " + decl.String())
+	}
+
+	return nil
+}
+
+func printFunctionDeclarations(db *debugger.Debugger, args string) error {
+	return printDeclarations("Functions", db.ListFunctionDeclarations, args)
+}
+
+func printVariableDeclarations(db *debugger.Debugger, args string) error {
+	return printDeclarations("Variables", db.ListVariableDeclarations, args)
+}
+
+func printTypeDeclarations(db *debugger.Debugger, args string) error {
+	return printDeclarations("Types", db.ListTypeDeclarations, args)
+}
+
+func printCompileUnits(db *debugger.Debugger, args string) error {
+	units, err := db.ListCompileUnits()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Compile units:")
+	if len(units) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	for _, unit := range units {
+		fmt.Println("  " + unit.String())
+	}
+
+	return nil
+}
+
+func printBuildInfo(db *debugger.Debugger, args string) error {
+	fmt.Println("Build info:")
+	for _, file := range db.LoadedElves.Files() {
+		name := file.FileName
+		if name == "" {
+			name = "(executable)"
+		}
+		fmt.Println("  " + name)
+
+		buildId := file.BuildId()
+		if buildId == "" {
+			buildId = "(none)"
+		}
+		fmt.Println("    build id:", buildId)
+
+		if file.DebugFilePath != "" {
+			fmt.Println("    debug file:", file.DebugFilePath)
+		}
+
+		abiTag, err := file.ABITag()
+		if err != nil {
+			fmt.Println("    abi tag: <invalid:", err, ">")
+		} else if abiTag != nil {
+			fmt.Println("    abi tag:", abiTag)
+		} else {
+			fmt.Println("    abi tag: (none)")
+		}
+
+		comments, err := file.Comments()
+		if err != nil {
+			fmt.Println("    comments: <invalid:", err, ">")
+		} else if len(comments) == 0 {
+			fmt.Println("    comments: (none)")
+		} else {
+			fmt.Println("    comments:")
+			for _, comment := range comments {
+				fmt.Println("      " + comment)
+			}
+		}
+	}
+
+	return nil
+}
+
+func printProducerWarnings(db *debugger.Debugger) {
+	warnings, err := db.NewProducerWarnings()
+	if err != nil {
+		fmt.Println("failed to check compile unit producers:", err)
+		return
+	}
+
+	for _, warning := range warnings {
+		fmt.Println("Warning:", warning)
+	}
+}
+
+func printStepWarnings(db *debugger.Debugger) {
+	for _, warning := range db.NewStepWarnings() {
+		fmt.Println("Warning:", warning)
+	}
+}
+
+func setProducerWarnings(db *debugger.Debugger, args string) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		db.SuppressProducerWarnings = false
+		fmt.Println("producer warnings enabled")
+	case "off":
+		db.SuppressProducerWarnings = true
+		fmt.Println("producer warnings disabled")
+	case "":
+		fmt.Println("producer warnings mode (on/off) not specified")
+	default:
+		fmt.Println("invalid producer warnings mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
+
+func printDebugInfoReports(db *debugger.Debugger) {
+	for _, report := range db.NewDebugInfoReports() {
+		fmt.Println(report)
+	}
+}
+
+func setSeparateDebugInfo(db *debugger.Debugger, args string) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		db.LoadedElves.LoadSeparateDebugInfo = true
+		fmt.Println("separate debug info auto-loading enabled")
+	case "off":
+		db.LoadedElves.LoadSeparateDebugInfo = false
+		fmt.Println("separate debug info auto-loading disabled")
+	case "":
+		fmt.Println("separate debug info mode (on/off) not specified")
+	default:
+		fmt.Println("invalid separate debug info mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
+
+func printProcessCommandLine(db *debugger.Debugger, args string) error {
+	cmdline, cwd, err := db.ProcessCommandLine()
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	fmt.Println("process", db.Pid)
+	fmt.Println("  cmdline:", quoteCommandLine(cmdline))
+	fmt.Println("  cwd:", cwd)
+	return nil
+}
+
+// Joins the command line arguments, quoting arguments which are empty or
+// contain whitespace / quotes.
+func quoteCommandLine(cmdline []string) string {
+	quoted := make([]string, 0, len(cmdline))
+	for _, arg := range cmdline {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\") {
+			arg = strconv.Quote(arg)
+		}
+		quoted = append(quoted, arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func printModuleMappings(db *debugger.Debugger, args string) error {
+	mappings, err := db.ListModuleMappings()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Mappings:")
+	for _, mapping := range mappings {
+		name := mapping.ElfFileName
+		if name == "" {
+			name = "(executable)"
+		}
+		fmt.Printf("  %s (load bias: 0x%x)\n", name, mapping.LoadBias)
+
+		if !mapping.HasDebugInfo {
+			fmt.Println("    (no debug info)")
+		}
+
+		for _, unit := range mapping.CompileUnits {
+			for _, addrRange := range unit.Ranges {
+				fmt.Printf(
+					"    %s-%s %s\n",
+					addrRange.Low,
+					addrRange.High,
+					unit.Name)
+			}
+		}
+
+		if len(mapping.Unmapped) > 0 {
+			fmt.Println("    code without debug info:")
+		}
+		for _, unmapped := range mapping.Unmapped {
+			fmt.Printf(
+				"      %s-%s %s\n",
+				unmapped.Low,
+				unmapped.High,
+				unmapped.SectionName)
+		}
+	}
+
+	return nil
+}
+
+func printLastStop(db *debugger.Debugger, args string) error {
+	status := db.LastStopStatus()
+	if status == nil {
+		fmt.Println("process has not stopped since it was started / attached")
+		return nil
+	}
+
+	fmt.Println("last stop:", status.StopSummary())
+	return nil
+}
+
+func printScope(db *debugger.Debugger, args string) error {
+	location := strings.TrimSpace(args)
+	if location == "" {
+		fmt.Println("Expected one argument: <function> or <path>:<line>")
+		return nil
+	}
+
+	resolver := db.NewFunctionResolver(location)
+	path, lineStr, ok := strings.Cut(location, ":")
+	if ok {
+		line, err := strconv.ParseInt(lineStr, 10, 32)
+		if err != nil {
+			fmt.Println("invalid line:", lineStr)
+			return nil
+		}
+		resolver = db.NewLineResolver(path, int(line))
+	}
+
+	addresses, err := resolver.ResolveAddresses()
+	if err != nil {
+		fmt.Println("failed to resolve location:", err)
+		return nil
+	}
+
+	if len(addresses) == 0 {
+		fmt.Println("No code found for", location)
+		return nil
+	}
+
+	// NOTE: a line may resolve to multiple addresses within the same scope.
+	// Only the first address of each distinct scope is printed.
+	printed := map[string]struct{}{}
+	for _, address := range addresses {
+		variables, err := db.ListScopeVariables(address)
+		if err != nil {
+			return err
+		}
+
+		signature, err := db.FunctionSignatureAt(address)
+		if err != nil {
+			return err
+		}
+
+		body := ""
+		if len(variables) == 0 {
+			body = "  (none)\n"
+		}
+		for _, variable := range variables {
+			body += "  " + variable.String() + "\n"
+		}
+
+		key := signature + "\n" + body
+		_, ok := printed[key]
+		if ok {
+			continue
+		}
+
+		if len(printed) > 0 {
+			fmt.Println()
+		}
+		printed[key] = struct{}{}
+
+		if signature != "" {
+			fmt.Printf("Scope at %s (%s):\n", address, signature)
+		} else {
+			fmt.Printf("Scope at %s:\n", address)
+		}
+		fmt.Print(body)
+	}
+
+	return nil
+}
+
+func printAddressSymbol(db *debugger.Debugger, args string) error {
+	if strings.TrimSpace(args) == "" {
+		fmt.Println("Expected one argument: <address>")
+		return nil
+	}
+
+	address, err := db.EvaluateAddress(args)
+	if err != nil {
+		fmt.Println("failed to evaluate address:", err)
+		return nil
+	}
+
+	symbol, err := db.SymbolizeAddress(address)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(symbol)
+	return nil
+}
@@ -9,11 +9,14 @@ import (
 	_ "net/http/pprof"
 	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/chzyer/readline"
 
 	"github.com/pattyshack/bad/debugger"
 	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/stoppoint"
 )
 
 func splitArg(args string) (string, string) {
@@ -28,6 +31,26 @@ func splitArg(args string) (string, string) {
 	return first, remaining
 }
 
+// Splits the leading "/<x|d|o|t>" integer format argument (if any) from the
+// remaining arguments.  This returns false (after printing the error) if the
+// format is invalid.
+func splitIntegerFormat(args string) (IntegerFormat, string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(args), "/") {
+		return NaturalFormat, args, true
+	}
+
+	formatStr, remaining := splitArg(args)
+	if len(formatStr) == 2 {
+		format, ok := ParseIntegerFormat(formatStr[1])
+		if ok {
+			return format, remaining, true
+		}
+	}
+
+	fmt.Println("invalid integer format (/x, /d, /o or /t):", formatStr)
+	return NaturalFormat, "", false
+}
+
 func splitAllArgs(argsStr string) []string {
 	args := []string{}
 	remaining := argsStr
@@ -61,14 +84,41 @@ func (cmds subCommands) run(args string) error {
 		return nil
 	}
 
+	// NOTE: a "/<format>" suffix (e.g., "read/w") is passed to the command as
+	// its first argument.
+	base, format, ok := strings.Cut(name, "/")
+	if ok && base != "" {
+		name = base
+		remaining = "/" + format + " " + remaining
+	}
+
+	cmd, ok := cmds.lookup(name)
+	if !ok {
+		fmt.Println("Invalid subcommand:", args)
+		return nil
+	}
+
+	return cmd.run(remaining)
+}
+
+// Returns the command matching the name.  Exact matches take precedence over
+// prefix matches (e.g., "f" is an alias for "frame" rather than a prefix of
+// "finish").  Otherwise, the first command (in declaration order) prefixed by
+// the name is returned.
+func (cmds subCommands) lookup(name string) (namedCommand, bool) {
+	for _, cmd := range cmds {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
 	for _, cmd := range cmds {
 		if strings.HasPrefix(cmd.name, name) {
-			return cmd.run(remaining)
+			return cmd, true
 		}
 	}
 
-	fmt.Println("Invalid subcommand:", args)
-	return nil
+	return namedCommand{}, false
 }
 
 func (cmds subCommands) printAvailableCommands() {
@@ -102,7 +152,19 @@ func (f runCmd) run(args string) error {
 	return f(args)
 }
 
-func initializeCommands(debugger *debugger.Debugger) command {
+// Returns the inferior's command tree.  The commands operate on the given
+// debugger (except for the session wide commands, e.g., inferior, quit).
+func initializeCommands(
+	debugger *debugger.Debugger,
+	monitor *outputMonitor,
+	infs *inferiors,
+) command {
+	expressionCmds := &expressionCommands{
+		debugger: debugger,
+	}
+
+	sharedLibraryCmds := newSharedLibraryCommands(debugger)
+
 	threadCmds := subCommands{
 		{
 			name:        "list",
@@ -114,6 +176,13 @@ func initializeCommands(debugger *debugger.Debugger) command {
 			description: " <tid> - list all threads",
 			command:     newFuncCmd(debugger, setThread),
 		},
+		{
+			name: "evaluate",
+			description: " <tid> <expression> " +
+				"- print the expression evaluated in the thread's context " +
+				"(without changing the current thread)",
+			command: runCmd(expressionCmds.resolveThreadVariableExpression),
+		},
 	}
 
 	registerCmds := subCommands{
@@ -122,7 +191,11 @@ func initializeCommands(debugger *debugger.Debugger) command {
 			description: ":\n" +
 				"    read                   - read general registers\n" +
 				"    read all               - read all registers\n" +
-				"    read <register>        - read the named register",
+				"    read <register> ...    - read the named registers\n" +
+				"    read <first>-<last>    - read the numbered registers in " +
+				"range (e.g., r8-r12)\n" +
+				"    read/<x|d|o|t> ...     - read registers in hex / decimal / " +
+				"octal / binary",
 			command: newFuncCmd(debugger, readRegister),
 		},
 		{
@@ -130,6 +203,22 @@ func initializeCommands(debugger *debugger.Debugger) command {
 			description: " <register> <value> - write value to the named register",
 			command:     newFuncCmd(debugger, writeRegister),
 		},
+		{
+			name:        "dump",
+			description: " <path>             - dump all registers to file",
+			command:     newFuncCmd(debugger, dumpRegisters),
+		},
+		{
+			name:        "restore-file",
+			description: " <path>     - restore all registers from dump file",
+			command:     newFuncCmd(debugger, restoreRegisters),
+		},
+		{
+			name: "diff",
+			description: " <old path> <new path> " +
+				"- print registers which differ between two dump files",
+			command: newFuncCmd(debugger, diffRegisters),
+		},
 	}
 
 	breakPointCmds := stopPointCommands{
@@ -141,6 +230,7 @@ func initializeCommands(debugger *debugger.Debugger) command {
 		debugger:   debugger,
 		stopPoints: debugger.WatchPoints,
 	}
+	debugger.WatchWatchPointScopeExits(printWatchPointScopeExit)
 
 	memoryCmds := subCommands{
 		{
@@ -149,50 +239,407 @@ func initializeCommands(debugger *debugger.Debugger) command {
 				"    read <address>                      " +
 				"- read 32 bytes from address\n" +
 				"    read <address> <n>                  " +
-				"- read n bytes from address",
+				"- read n bytes from address\n" +
+				"    read/<b|h|w|g> <address>            " +
+				"- read a 1/2/4/8 bytes integer from address\n" +
+				"    read/<b|h|w|g><x|d|o|t> <address>   " +
+				"- read the integer in hex / decimal / octal / binary",
 			command: newFuncCmd(debugger, readMemory),
 		},
+		{
+			name: "read-typed",
+			description: " <address> <count> <type>  " +
+				"- read count elements of the named type from address",
+			command: newFuncCmd(debugger, readTypedMemory),
+		},
 		{
 			name: "write",
-			description: " <address> <byte 1> ... <byte n> " +
-				"- write space separated bytes to address",
+			description: ":\n" +
+				"    write <address> <byte 1> ... <byte n> " +
+				"- write space separated bytes to address\n" +
+				"    write/<b|h|w|g> <address> <value>     " +
+				"- write a 1/2/4/8 bytes integer to address",
 			command: newFuncCmd(debugger, writeMemory),
 		},
+		{
+			name: "watch-diff",
+			description: ":\n" +
+				"    watch-diff                    " +
+				"- list memory regions diffed on every stop\n" +
+				"    watch-diff <address> <n>      " +
+				"- print changes to the n bytes region on every stop\n" +
+				"    watch-diff delete <id>        " +
+				"- stop diffing the memory region",
+			command: newFuncCmd(debugger, watchMemoryDiff),
+		},
+		{
+			name: "compare",
+			description: ":\n" +
+				"    compare <address 1> <address 2> <n> " +
+				"- diff the n bytes regions at the two addresses\n" +
+				"    compare <address> <file>            " +
+				"- diff the region at address against the file's content",
+			command: newFuncCmd(debugger, compareMemory),
+		},
 	}
 
 	syscallCatchPolicyCmds := syscallCatchPolicyCommands{
 		policy: debugger.SyscallCatchPolicy,
 	}
 
+	exceptionCatchPointCmds := exceptionCatchPointCommands{
+		debugger: debugger,
+	}
+
 	catchPointCmds := subCommands{
 		{
 			name:        "syscall",
-			description: " - commands for operating on syscall catch policy",
+			description: "   - commands for operating on syscall catch policy",
 			command:     syscallCatchPolicyCmds.SubCommands(),
 		},
+		{
+			name:        "exception",
+			description: " - commands for operating on c++ exception catch points",
+			command:     exceptionCatchPointCmds.SubCommands(),
+		},
+		{
+			name: "signal",
+			description: ":\n" +
+				"    signal                  - print current signal catch policy\n" +
+				"    signal all              - stop on every (non-internal) signal\n" +
+				"    signal <sig 1> ... <sig n> [off]\n" +
+				"                            - catch (or stop catching) the signals",
+			command: newFuncCmd(debugger, catchSignals),
+		},
+		{
+			name:        "exec",
+			description: " [off] - stop (or don't stop) after the process execs",
+			command:     newFuncCmd(debugger, catchExec),
+		},
+		{
+			name:        "fork",
+			description: " [off] - stop (or don't stop) after the process forks",
+			command:     newFuncCmd(debugger, catchFork),
+		},
 	}
 
-	expressionCmds := subCommands{
+	printSettingCmds := subCommands{
+		{
+			name: "frame-arguments",
+			description: ":\n" +
+				"    frame-arguments scalars  " +
+				"- print scalar arguments, abbreviate aggregates\n" +
+				"    frame-arguments all      " +
+				"- print all arguments, including aggregates\n" +
+				"    frame-arguments none     " +
+				"- print argument names only",
+			command: newFuncCmd(debugger, setPrintFrameArguments),
+		},
+	}
+
+	settingCmds := subCommands{
+		{
+			name: "source-trace",
+			description: ":\n" +
+				"    source-trace on [<size>] " +
+				"- record source lines visited by step / next\n" +
+				"    source-trace off         " +
+				"- stop recording source lines",
+			command: newFuncCmd(debugger, setSourceTrace),
+		},
+		{
+			name: "stop-on-exit",
+			description: ":\n" +
+				"    stop-on-exit on          " +
+				"- stop threads right before they exit\n" +
+				"    stop-on-exit off         " +
+				"- let threads exit without stopping",
+			command: newFuncCmd(debugger, setStopOnExit),
+		},
 		{
-			name:        "locals",
-			description: "                - print all local variable values",
-			command:     newFuncCmd(debugger, printLocalVariables),
+			name: "follow-exec-mode",
+			description: ":\n" +
+				"    follow-exec-mode same    " +
+				"- keep break / watch points after exec\n" +
+				"    follow-exec-mode new     " +
+				"- discard break / watch points after exec",
+			command: newFuncCmd(debugger, setFollowExecMode),
 		},
 		{
-			name:        "results",
-			description: "               - print previously evaluated results",
-			command:     newFuncCmd(debugger, printEvaluatedResults),
+			name: "producer-warnings",
+			description: ":\n" +
+				"    producer-warnings on     " +
+				"- warn about compilers with known debug info issues\n" +
+				"    producer-warnings off    " +
+				"- suppress compiler debug info issue warnings",
+			command: newFuncCmd(debugger, setProducerWarnings),
 		},
 		{
-			name:        "evaluate",
-			description: " <expression> - print the evaluated value",
-			command:     newFuncCmd(debugger, resolveVariableExpression),
+			name: "separate-debug-info",
+			description: ":\n" +
+				"    separate-debug-info on   " +
+				"- load shared libraries' separate debug files (via build id " +
+				"or debuglink)\n" +
+				"    separate-debug-info off  " +
+				"- only use debug info embedded in shared libraries",
+			command: newFuncCmd(debugger, setSeparateDebugInfo),
 		},
 		{
-			name: "locate",
-			description: " <name>         " +
-				"- print the variable's dwarf evaluated location",
-			command: newFuncCmd(debugger, printVariableLocation),
+			name: "library-events",
+			description: ":\n" +
+				"    library-events on        " +
+				"- print shared library load / unload events as they happen\n" +
+				"    library-events off       " +
+				"- only record shared library events (see info dll-load-order)",
+			command: runCmd(sharedLibraryCmds.setNotify),
+		},
+		{
+			name: "logging",
+			description: ":\n" +
+				"    logging on <file>        " +
+				"- record entered commands and their output to the transcript " +
+				"file (replay the commands with -x <file>)\n" +
+				"    logging off              " +
+				"- stop recording",
+			command: runCmd(infs.sessionLog.setLogging),
+		},
+		{
+			name: "prompt",
+			description: ":\n" +
+				"    prompt                   " +
+				"- print the prompt\n" +
+				"    prompt <text>            " +
+				"- use text as the prompt\n" +
+				"    prompt -context on       " +
+				"- prefix the prompt with the current thread's id and function\n" +
+				"    prompt -context off      " +
+				"- do not show the current thread's context\n" +
+				"    prompt -default          " +
+				"- restore the default prompt",
+			command: runCmd(infs.replPrompt.setPrompt),
+		},
+		{
+			name: "confirm",
+			description: ":\n" +
+				"    confirm on               " +
+				"- ask for confirmation before quitting while the process is " +
+				"alive\n" +
+				"    confirm off              " +
+				"- quit without asking",
+			command: runCmd(infs.quitCmd.setConfirm),
+		},
+		{
+			name: "memory-cache",
+			description: ":\n" +
+				"    memory-cache on          " +
+				"- cache memory reads until the process resumes\n" +
+				"    memory-cache off         " +
+				"- always read memory directly from the process",
+			command: newFuncCmd(debugger, setMemoryCache),
+		},
+		{
+			name: "memory-compare-limit",
+			description: ":\n" +
+				"    memory-compare-limit     " +
+				"- print the maximum number of differences printed by memory " +
+				"compare\n" +
+				"    memory-compare-limit <n> " +
+				"- print at most n differences\n" +
+				"    memory-compare-limit off " +
+				"- print all differences",
+			command: newFuncCmd(debugger, setMemoryCompareLimit),
+		},
+		{
+			name: "signal-pass",
+			description: ":\n" +
+				"    signal-pass              " +
+				"- list signals not passed to the process\n" +
+				"    signal-pass <sig> pass   " +
+				"- deliver the received signal when the process resumes\n" +
+				"    signal-pass <sig> nopass " +
+				"- discard the received signal",
+			command: newFuncCmd(debugger, setSignalPass),
+		},
+		{
+			name: "call-timeout",
+			description: ":\n" +
+				"    call-timeout             " +
+				"- print the function call timeout\n" +
+				"    call-timeout <duration>  " +
+				"- abort function calls running longer than duration (e.g., 5s)\n" +
+				"    call-timeout off         " +
+				"- let function calls run indefinitely",
+			command: newFuncCmd(debugger, setCallTimeout),
+		},
+		{
+			name: "condition-calls",
+			description: ":\n" +
+				"    condition-calls          " +
+				"- print which break points are disabled while a stop point's " +
+				"condition calls functions\n" +
+				"    condition-calls own      " +
+				"- only disable the stop point whose condition is evaluated\n" +
+				"    condition-calls all      " +
+				"- disable all break points (avoids trapping on every skipped " +
+				"hit, but patches every break site twice per call)",
+			command: newFuncCmd(debugger, setConditionCalls),
+		},
+		{
+			name: "step-limit",
+			description: ":\n" +
+				"    step-limit               " +
+				"- print the maximum number of single steps per step / next\n" +
+				"    step-limit <n>           " +
+				"- stop step / next after n single steps\n" +
+				"    step-limit off           " +
+				"- let step / next run until reaching a different line",
+			command: newFuncCmd(debugger, setStepLimit),
+		},
+		{
+			name: "unwind-on-terminating-exception",
+			description: ":\n" +
+				"    unwind-on-terminating-exception on  " +
+				"- abort function calls which terminate with uncaught exceptions\n" +
+				"    unwind-on-terminating-exception off " +
+				"- let std::terminate run (which aborts the process)",
+			command: newFuncCmd(debugger, setUnwindOnTerminate),
+		},
+		{
+			name: "watchpoint-scope",
+			description: ":\n" +
+				"    watchpoint-scope auto    " +
+				"- remove scoped watch points (releasing their debug registers) " +
+				"once their frames return\n" +
+				"    watchpoint-scope manual  " +
+				"- keep out of scope watch points until they are removed",
+			command: newFuncCmd(debugger, setWatchPointScopeMode),
+		},
+		{
+			name:        "print",
+			description: "            - commands for updating print settings",
+			command:     printSettingCmds,
+		},
+	}
+
+	procInfoCmds := subCommands{
+		{
+			name: "cmdline",
+			description: " " +
+				"- print the process' command line arguments and working directory",
+			command: newFuncCmd(debugger, printProcessCommandLine),
+		},
+		{
+			name: "mappings",
+			description: " " +
+				"- print each loaded elf's code address ranges covered by its " +
+				"compile units, and code without debug info",
+			command: newFuncCmd(debugger, printModuleMappings),
+		},
+	}
+
+	infoCmds := subCommands{
+		{
+			name: "functions",
+			description: " [<regexp>] " +
+				"- list functions and their declaration locations",
+			command: newFuncCmd(debugger, printFunctionDeclarations),
+		},
+		{
+			name: "variables",
+			description: " [<regexp>] " +
+				"- list global variables and their declaration locations",
+			command: newFuncCmd(debugger, printVariableDeclarations),
+		},
+		{
+			name: "types",
+			description: " [<regexp>]     " +
+				"- list types and their declaration locations",
+			command: newFuncCmd(debugger, printTypeDeclarations),
+		},
+		{
+			name: "comp-units",
+			description: "           " +
+				"- list compile units and their producers",
+			command: newFuncCmd(debugger, printCompileUnits),
+		},
+		{
+			name: "build",
+			description: "                " +
+				"- list loaded elves' build ids, abi tags and compiler versions",
+			command: newFuncCmd(debugger, printBuildInfo),
+		},
+		{
+			name: "dll-load-order",
+			description: "       " +
+				"- list shared library load / unload events in detection order",
+			command: runCmd(sharedLibraryCmds.printLoadOrder),
+		},
+		{
+			name: "watchpoints",
+			description: "          " +
+				"- list watch points and available hardware stop sites",
+			command: runCmd(watchPointCmds.info),
+		},
+		{
+			name: "watchpoint-registers",
+			description: " " +
+				"- decode the debug registers (dr0-dr3 addresses, dr7 enable / " +
+				"condition / size per slot, dr6 triggered status) and map each " +
+				"slot to its installed stop site",
+			command: newFuncCmd(debugger, printDebugRegisters),
+		},
+		{
+			name: "catchpoints",
+			description: "          " +
+				"- list syscall, signal, exec, fork and exception catch points",
+			command: newFuncCmd(debugger, printCatchPoints),
+		},
+		{
+			name: "stop",
+			description: "                 " +
+				"- summarize why the debugger last stopped (thread, reason, " +
+				"location and watched value changes)",
+			command: newFuncCmd(debugger, printLastStop),
+		},
+		{
+			name: "scope",
+			description: " <location>     " +
+				"- list parameters / local variables visible at <location> " +
+				"(<function> or <path>:<line>) without stopping there",
+			command: newFuncCmd(debugger, printScope),
+		},
+		{
+			name: "symbol",
+			description: " <address>     " +
+				"- print the symbol, section and module containing the address " +
+				"(e.g., 0x401000, $pc, main+8, or a pointer variable)",
+			command: newFuncCmd(debugger, printAddressSymbol),
+		},
+		{
+			name:        "display",
+			description: "              - list displayed expressions",
+			command:     newFuncCmd(debugger, listDisplays),
+		},
+		{
+			name:        "checkpoints",
+			description: "          - list checkpoints (forked process copies)",
+			command:     newFuncCmd(debugger, listCheckpoints),
+		},
+		{
+			name: "registers",
+			description: "            " +
+				"- alias for register read (e.g., info registers rax r8-r12)",
+			command: newFuncCmd(debugger, readRegister),
+		},
+		{
+			name:        "inferiors",
+			description: "            - alias for inferior list",
+			command:     runCmd(infs.listInferiors),
+		},
+		{
+			name:        "proc",
+			description: "                 - commands for printing process information",
+			command:     procInfoCmds,
 		},
 	}
 
@@ -200,9 +647,17 @@ func initializeCommands(debugger *debugger.Debugger) command {
 		{
 			name: "continue",
 			description: ":\n" +
-				"    continue         - resume all process threads\n" +
-				"    continue current - resume only the current thread",
-			command: newFuncCmd(debugger, resume),
+				"    continue               - resume all process threads\n" +
+				"    continue current       - resume only the current thread\n" +
+				"    continue -until-output - resume all process threads " +
+				"until the process writes to stdout/stderr (requires " +
+				"-redirect-output)\n" +
+				"    continue -ignore <id> <n> - resume, skipping the next n hits " +
+				"of the break / watch point (b<id> / w<id> disambiguates the " +
+				"id).  The stop point's ignore count is restored afterward",
+			command: runCmd(func(args string) error {
+				return resume(debugger, monitor, args)
+			}),
 		},
 		{
 			name:        "next",
@@ -210,20 +665,39 @@ func initializeCommands(debugger *debugger.Debugger) command {
 			command:     newFuncCmd(debugger, stepOver),
 		},
 		{
-			name:        "finish",
-			description: "   - step out",
-			command:     newFuncCmd(debugger, stepOut),
+			name: "finish",
+			description: ":\n" +
+				"    finish     - step out\n" +
+				"    finish <n> - step out of n frames",
+			command: newFuncCmd(debugger, stepOut),
 		},
 		{
-			name:        "step",
-			description: "     - step in",
-			command:     newFuncCmd(debugger, stepIn),
+			name: "finish-to",
+			description: " <function> - resume until control returns to " +
+				"the innermost caller frame of the named function",
+			command: newFuncCmd(debugger, stepOutToCaller),
+		},
+		{
+			name: "step",
+			description: ":\n" +
+				"    step     - step in\n" +
+				"    step <n> - step into the n-th call on the current line",
+			command: newFuncCmd(debugger, stepIn),
 		},
 		{
 			name:        "single",
 			description: "   - single instruction step",
 			command:     newFuncCmd(debugger, stepInstruction),
 		},
+		// NOTE: strace must be declared after step and single since s / st
+		// resolve to the first prefix match.
+		{
+			name: "strace",
+			description: "   - resume all process threads, logging every syscall " +
+				"entry / exit until the process stops for any other reason " +
+				"(e.g., break point, signal, or ctrl-c)",
+			command: newFuncCmd(debugger, straceResume),
+		},
 		{
 			name:        "register",
 			description: " - commands for operating on registers",
@@ -236,9 +710,13 @@ func initializeCommands(debugger *debugger.Debugger) command {
 		},
 		{
 			name: "disassemble",
-			description: " [<n=5>] [@<addr=pc>]\n" +
-				"    - disassemble <n> (default=5) instructions " +
-				"at @<addr> (default=pc)",
+			description: ":\n" +
+				"    disassemble [<n=5>] [@<addr=pc>] " +
+				"- disassemble <n> (default=5) instructions " +
+				"at @<addr> (default=pc)\n" +
+				"    disassemble <start> <end>        " +
+				"- disassemble instructions in [<start>, <end>), " +
+				"where <start>/<end> is either an address or <symbol>[+<offset>]",
 			command: newFuncCmd(debugger, disassemble),
 		},
 		{
@@ -251,17 +729,43 @@ func initializeCommands(debugger *debugger.Debugger) command {
 			description: " - commands for operating on watch points",
 			command:     watchPointCmds.SubCommands(),
 		},
+		{
+			name:        "catch",
+			description: "      - commands for operating on catch points",
+			command:     catchPointCmds,
+		},
 		{
 			name:        "catchpoint",
-			description: " - commands for operating on catch points",
+			description: " - alias for catch",
 			command:     catchPointCmds,
 		},
+		{
+			name: "checkpoint",
+			description: ":\n" +
+				"    checkpoint             - fork the stopped process into a " +
+				"frozen copy, which can be restarted later.  Only memory and the " +
+				"current thread's registers are snapshotted (other threads, open " +
+				"file descriptors' offsets and external state are not)\n" +
+				"    checkpoint delete <id> - kill the checkpoint's frozen copy",
+			command: newFuncCmd(debugger, checkpoint),
+		},
+		{
+			name: "restart",
+			description: " <id>  - kill the current process and switch debugging " +
+				"to the checkpoint's frozen copy",
+			command: newFuncCmd(debugger, restartCheckpoint),
+		},
 		{
 			name: "backtrace",
 			description: ":\n" +
 				"    backtrace      - print backtrace\n" +
 				"    backtrace up   - inspect callee frame and print backtrace\n" +
-				"    backtrace down - inspect caller frame and print backtrace",
+				"    backtrace down - inspect caller frame and print backtrace\n" +
+				"    backtrace -args [up|down] " +
+				"- print backtrace with frame argument values\n" +
+				"    backtrace -verbose [up|down] " +
+				"- print backtrace with unwind provenance.  Likely unreliable " +
+				"frames are marked with ?",
 			command: newFuncCmd(debugger, backtrace),
 		},
 		{
@@ -282,7 +786,92 @@ func initializeCommands(debugger *debugger.Debugger) command {
 		{
 			name:        "expression",
 			description: "  - commands for operating on global/local variables",
-			command:     expressionCmds,
+			command:     expressionCmds.SubCommands(),
+		},
+		{
+			name:        "set",
+			description: "         - commands for updating debugger settings",
+			command:     settingCmds,
+		},
+		{
+			name: "source-trace",
+			description: ":\n" +
+				"    source-trace       - print source lines visited by step / next\n" +
+				"    source-trace clear - clear recorded source lines",
+			command: newFuncCmd(debugger, printSourceTrace),
+		},
+		{
+			name:        "info",
+			description: "        - commands for listing program information",
+			command:     infoCmds,
+		},
+		{
+			name: "inferior",
+			description: "    - commands for operating on inferiors (debugged " +
+				"processes).  Other commands operate on the current inferior, " +
+				"and break / watch points are per inferior",
+			command: infs.inferiorCmds,
+		},
+		{
+			name:        "where",
+			description: "       - alias for backtrace",
+			command:     newFuncCmd(debugger, backtrace),
+		},
+		{
+			name:        "bt",
+			description: "          - alias for backtrace",
+			command:     newFuncCmd(debugger, backtrace),
+		},
+		{
+			name:        "up",
+			description: "          - alias for backtrace up",
+			command:     newFuncCmd(debugger, inspectCalleeFrame),
+		},
+		{
+			name:        "down",
+			description: "        - alias for backtrace down",
+			command:     newFuncCmd(debugger, inspectCallerFrame),
+		},
+		{
+			name: "frame",
+			description: ":\n" +
+				"    frame                  - print the inspected frame\n" +
+				"    frame <n>              - inspect the n-th backtrace frame\n" +
+				"    frame apply all <expr> - evaluate the expression in every " +
+				"frame",
+			command: newFuncCmd(debugger, selectFrame),
+		},
+		{
+			name:        "f",
+			description: "           - alias for frame",
+			command:     newFuncCmd(debugger, selectFrame),
+		},
+		{
+			name: "display",
+			description: ":\n" +
+				"    display                        " +
+				"- print all displayed expressions\n" +
+				"    display <expression>           " +
+				"- print the expression every time the process stops\n" +
+				"    display/<x|d|o|t> <expression> " +
+				"- display integer values in hex / decimal / octal / binary",
+			command: newFuncCmd(debugger, display),
+		},
+		{
+			name:        "undisplay",
+			description: " <id> - stop displaying the expression",
+			command:     newFuncCmd(debugger, undisplay),
+		},
+		{
+			name: "quit",
+			description: " - end the session (the process is killed if it was " +
+				"started by the debugger, detached otherwise)",
+			command: runCmd(infs.quitCmd.quit),
+		},
+		{
+			name:        "exit",
+			description: " - alias for quit",
+			command:     runCmd(infs.quitCmd.quit),
 		},
 	}
 }
@@ -293,20 +882,219 @@ func (noOpCmd) run(args string) error {
 	return nil
 }
 
-func resume(db *debugger.Debugger, args string) error {
-	args = strings.TrimSpace(args)
-
+func resume(
+	db *debugger.Debugger,
+	monitor *outputMonitor, // nil if output is not redirected
+	args string,
+) error {
 	resume := db.ResumeAllUntilSignal
-	if args != "" {
-		if strings.HasPrefix("current", args) {
+	untilOutput := false
+	var ignorePoint *stoppoint.StopPoint
+	ignoreCount := 0
+
+	remaining := splitAllArgs(args)
+	for len(remaining) > 0 {
+		arg := remaining[0]
+		remaining = remaining[1:]
+
+		if arg == "-until-output" {
+			if monitor == nil {
+				fmt.Println(
+					"cannot resume until output. process's output is not " +
+						"redirected (see the -redirect-output flag)")
+				return nil
+			}
+			untilOutput = true
+		} else if arg == "-ignore" {
+			if len(remaining) < 2 {
+				fmt.Println("expected -ignore <stop point id> <count>")
+				return nil
+			}
+
+			point, ok := lookupStopPoint(db, remaining[0])
+			if !ok {
+				return nil
+			}
+
+			count, err := strconv.ParseInt(remaining[1], 10, 32)
+			if err != nil || count < 0 {
+				fmt.Println("invalid ignore count:", remaining[1])
+				return nil
+			}
+			remaining = remaining[2:]
+
+			ignorePoint = point
+			ignoreCount = int(count)
+		} else if strings.HasPrefix("current", arg) {
 			resume = db.ResumeCurrentUntilSignal
 		} else {
-			fmt.Println("unexpected argument:", args)
+			fmt.Println("unexpected argument:", arg)
 			return nil
 		}
 	}
 
+	if ignorePoint != nil {
+		// NOTE: the ignore count only applies to this resume.  The stop point's
+		// previous (persistent) ignore count is restored once the resume
+		// returns.
+		previousCount := ignorePoint.IgnoreCount()
+		err := ignorePoint.SetIgnoreCount(ignoreCount)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = ignorePoint.SetIgnoreCount(previousCount)
+		}()
+	}
+
+	var status *debugger.ThreadStatus
+	var err error
+	stoppedByOutput := false
+	if untilOutput {
+		status, stoppedByOutput, err = resumeUntilOutput(db, monitor, resume)
+	} else {
+		status, err = resume()
+	}
+	if err != nil {
+		if errors.Is(err, ErrProcessExited) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	if untilOutput {
+		if stoppedByOutput &&
+			status.Stopped &&
+			status.StopSignal == syscall.SIGSTOP {
+
+			fmt.Println("Stopped after process produced output")
+		} else {
+			fmt.Println("Stopped before process produced output")
+		}
+	}
+
+	printThreadStatus(db, status)
+	return nil
+}
+
+// Looks up the break / watch point by id.  Since break points and watch
+// points are numbered independently, the id may be prefixed with b (break
+// point) or w (watch point) to disambiguate the id.
+func lookupStopPoint(
+	db *debugger.Debugger,
+	arg string,
+) (
+	*stoppoint.StopPoint,
+	bool,
+) {
+	sets := []*stoppoint.StopPointSet{db.BreakPoints, db.WatchPoints}
+	if strings.HasPrefix(arg, "b") {
+		sets = sets[:1]
+		arg = arg[1:]
+	} else if strings.HasPrefix(arg, "w") {
+		sets = sets[1:]
+		arg = arg[1:]
+	}
+
+	id, err := strconv.ParseInt(arg, 10, 32)
+	if err != nil {
+		fmt.Println("failed to parse stop point id:", err)
+		return nil, false
+	}
+
+	var found []*stoppoint.StopPoint
+	for _, set := range sets {
+		point, ok := set.Get(id)
+		if ok {
+			found = append(found, point)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		fmt.Printf("stop point (id=%d) not found\n", id)
+		return nil, false
+	case 1:
+		return found[0], true
+	default:
+		fmt.Printf(
+			"ambiguous stop point id (%d). use b%d (break point) or "+
+				"w%d (watch point)\n",
+			id,
+			id,
+			id)
+		return nil, false
+	}
+}
+
+// Resumes the process until it stops on its own, or until it writes to
+// stdout / stderr, in which case the process is interrupted (and the stop is
+// reported as a SIGSTOP signal stop).  The returned bool is true if the
+// process was interrupted.
+//
+// NOTE: the interrupting goroutine is joined before returning, i.e., the
+// process is never interrupted after the resume call returned.
+func resumeUntilOutput(
+	db *debugger.Debugger,
+	monitor *outputMonitor,
+	resume func() (*debugger.ThreadStatus, error),
+) (
+	*debugger.ThreadStatus,
+	bool,
+	error,
+) {
+	mutex := sync.Mutex{}
+	isResumed := true
+	interrupted := false
+
+	done := make(chan struct{})
+	joined := make(chan struct{})
+	output := monitor.Watch()
+	go func() {
+		defer close(joined)
+
+		select {
+		case <-done:
+		case <-output:
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if !isResumed {
+				return
+			}
+
+			interrupted = true
+			err := db.Interrupt()
+			if err != nil {
+				fmt.Println("failed to interrupt process:", err)
+			}
+		}
+	}()
+
 	status, err := resume()
+
+	mutex.Lock()
+	isResumed = false
+	mutex.Unlock()
+
+	close(done)
+	<-joined
+
+	return status, interrupted, err
+}
+
+func straceResume(db *debugger.Debugger, args string) error {
+	if strings.TrimSpace(args) != "" {
+		fmt.Println("unexpected argument:", args)
+		return nil
+	}
+
+	status, err := db.ResumeAllAndTraceSyscalls(
+		func(status *debugger.ThreadStatus) {
+			fmt.Printf("[%d] %s\n", status.Tid, status.SyscallTrapInfo)
+		})
 	if err != nil {
 		if errors.Is(err, ErrProcessExited) {
 			fmt.Println(err)
@@ -320,9 +1108,44 @@ func resume(db *debugger.Debugger, args string) error {
 }
 
 func stepOut(db *debugger.Debugger, args string) error {
-	status, err := db.StepOut()
+	args = strings.TrimSpace(args)
+
+	stepOut := db.StepOut
+	if args != "" {
+		numFrames, err := strconv.ParseInt(args, 10, 32)
+		if err != nil {
+			fmt.Println("Invalid number of frames:", err)
+			return nil
+		}
+
+		stepOut = func() (*debugger.ThreadStatus, error) {
+			return db.StepOutFrames(int(numFrames))
+		}
+	}
+
+	status, err := stepOut()
 	if err != nil {
-		if errors.Is(err, ErrProcessExited) {
+		if errors.Is(err, ErrProcessExited) || errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	printThreadStatus(db, status)
+	return nil
+}
+
+func stepOutToCaller(db *debugger.Debugger, args string) error {
+	name := strings.TrimSpace(args)
+	if name == "" {
+		fmt.Println("caller function name not specified")
+		return nil
+	}
+
+	status, err := db.StepOutToCaller(name)
+	if err != nil {
+		if errors.Is(err, ErrProcessExited) || errors.Is(err, ErrInvalidInput) {
 			fmt.Println(err)
 			return nil
 		}
@@ -348,9 +1171,24 @@ func stepOver(db *debugger.Debugger, args string) error {
 }
 
 func stepIn(db *debugger.Debugger, args string) error {
-	status, err := db.StepIn()
+	args = strings.TrimSpace(args)
+
+	stepIn := db.StepIn
+	if args != "" {
+		callIndex, err := strconv.ParseInt(args, 10, 32)
+		if err != nil {
+			fmt.Println("Invalid call index:", err)
+			return nil
+		}
+
+		stepIn = func() (*debugger.ThreadStatus, error) {
+			return db.StepIntoCall(int(callIndex))
+		}
+	}
+
+	status, err := stepIn()
 	if err != nil {
-		if errors.Is(err, ErrProcessExited) {
+		if errors.Is(err, ErrProcessExited) || errors.Is(err, ErrInvalidInput) {
 			fmt.Println(err)
 			return nil
 		}
@@ -361,6 +1199,33 @@ func stepIn(db *debugger.Debugger, args string) error {
 	return nil
 }
 
+func setStepLimit(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+	switch args {
+	case "":
+		if db.StepLimit <= 0 {
+			fmt.Println("step limit: off")
+		} else {
+			fmt.Println("step limit:", db.StepLimit)
+		}
+		return nil
+	case "off":
+		db.StepLimit = 0
+		fmt.Println("step limit disabled")
+		return nil
+	}
+
+	limit, err := strconv.ParseInt(args, 10, 32)
+	if err != nil || limit <= 0 {
+		fmt.Println("invalid step limit:", args)
+		return nil
+	}
+
+	db.StepLimit = int(limit)
+	fmt.Println("step limit set to", limit)
+	return nil
+}
+
 func stepInstruction(db *debugger.Debugger, args string) error {
 	status, err := db.StepInstruction()
 	if err != nil {
@@ -436,6 +1301,48 @@ func main() {
 	port := 0
 	flag.IntVar(&port, "port", 0, "start http server (for pprof)")
 
+	script := ""
+	flag.StringVar(
+		&script,
+		"x",
+		"",
+		"execute commands from the file (a command per line, or a transcript "+
+			"recorded by set logging) before reading commands from stdin")
+
+	dir := ""
+	flag.StringVar(
+		&dir,
+		"cd",
+		"",
+		"run the program in the directory instead of the current directory")
+
+	disableRandomization := true
+	flag.BoolVar(
+		&disableRandomization,
+		"disable-randomization",
+		true,
+		"run the program with address space layout randomization disabled, "+
+			"i.e., addresses are stable across runs.  Use "+
+			"-disable-randomization=false to reproduce ASLR dependent bugs")
+
+	redirectOutput := false
+	flag.BoolVar(
+		&redirectOutput,
+		"redirect-output",
+		false,
+		"relay the program's stdout / stderr through pipes, which is required "+
+			"by continue -until-output.  Note that the program's output is then "+
+			"no longer a terminal (e.g., isatty is false, stdout is fully "+
+			"buffered)")
+
+	batch := false
+	flag.BoolVar(
+		&batch,
+		"batch",
+		false,
+		"exit after executing the -x commands instead of reading commands from "+
+			"stdin.  quit does not ask for confirmation in batch mode")
+
 	flag.Parse()
 	args := flag.Args()
 
@@ -451,18 +1358,25 @@ func main() {
 		}()
 	}
 
-	var db *debugger.Debugger
+	sessionLog := &transcript{}
+	infs := newInferiors(
+		sessionLog,
+		dir,
+		disableRandomization,
+		redirectOutput)
+
+	var inf *inferior
 	var err error
 	if pid != 0 {
-		if len(args) != 0 {
+		if len(args) != 0 || dir != "" {
 			panic("unexpected arguments")
 		}
 
-		db, err = debugger.AttachTo(pid)
+		inf, err = infs.attach(pid)
 	} else if len(args) == 0 {
 		panic("no arguments given")
 	} else {
-		db, err = debugger.StartCmdAndAttachTo(args[0], args[1:]...)
+		inf, err = infs.start(args)
 	}
 
 	if err != nil {
@@ -470,30 +1384,80 @@ func main() {
 	}
 
 	defer func() {
-		err := db.Close()
+		err := infs.close()
 		if err != nil {
 			panic(err)
 		}
 	}()
 
-	db.WatchThreadLifeCycle(printThreadLifeCycle)
+	defer func() {
+		_ = sessionLog.stop()
+	}()
 
-	topCmds := initializeCommands(db)
+	fmt.Printf("attached to process %d\n", inf.debugger.Pid)
+	printProducerWarnings(inf.debugger)
 
-	fmt.Printf("attached to process %d\n", db.Pid)
+	// Returns true if the session should end.
+	runLine := func(line string) bool {
+		sessionLog.recordCommand(line)
 
-	rl, err := readline.New("bad > ")
-	if err != nil {
-		panic(err)
+		// NOTE: the current inferior may change between commands.
+		err := infs.current.commands.run(line)
+		if errors.Is(err, errQuit) {
+			return true
+		} else if err != nil {
+			panic(err)
+		}
+
+		sessionLog.flush()
+		return false
+	}
+
+	// NOTE: readline is not used in batch mode (quit's confirmation is also
+	// read via readline).
+	var rl *readline.Instance
+	if !batch {
+		rl, err = readline.New(infs.replPrompt.String())
+		if err != nil {
+			panic(err)
+		}
+		defer rl.Close()
+
+		infs.quitCmd.rl = rl
+	}
+
+	if script != "" {
+		commands, err := readCommandScript(script)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, line := range commands {
+			fmt.Println(transcriptCommandPrefix + line)
+			if runLine(line) {
+				return
+			}
+		}
+	}
+
+	if batch {
+		return
 	}
-	defer rl.Close()
 
 	lastLine := ""
 	for {
+		rl.SetPrompt(infs.replPrompt.String())
+
 		line, err := rl.Readline()
 		if err != nil {
-			if err == io.EOF || err == readline.ErrInterrupt {
+			if err == io.EOF {
 				break
+			} else if err == readline.ErrInterrupt {
+				// Discard the current line instead of exiting.  Note that the
+				// interrupt is forwarded to the process while the process is
+				// resumed.
+				lastLine = ""
+				continue
 			}
 			panic(err)
 		}
@@ -508,9 +1472,8 @@ func main() {
 			continue
 		}
 
-		err = topCmds.run(line)
-		if err != nil {
-			panic(err)
+		if runLine(line) {
+			break
 		}
 	}
 }
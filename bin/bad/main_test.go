@@ -0,0 +1,208 @@
+package main
+
+import (
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+
+	"github.com/pattyshack/bad/debugger"
+	"github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+type CommandSuite struct{}
+
+func TestCommands(t *testing.T) {
+	suite.RunTests(t, &CommandSuite{})
+}
+
+func startTestInferior(
+	t *testing.T,
+	target string,
+	redirectOutput bool,
+) (
+	*inferiors,
+	subCommands,
+) {
+	infs := newInferiors(&transcript{}, "", true, redirectOutput)
+	inf, err := infs.start([]string{"../../debugger/test_targets/" + target})
+	expect.Nil(t, err)
+
+	cmds, ok := inf.commands.(subCommands)
+	expect.True(t, ok)
+
+	return infs, cmds
+}
+
+func (CommandSuite) TestTopLevelCommandDispatch(t *testing.T) {
+	infs, cmds := startTestInferior(t, "counter", false)
+	defer infs.close()
+
+	expected := map[string]string{
+		"s":    "step",
+		"st":   "step",
+		"str":  "strace",
+		"si":   "single",
+		"c":    "continue",
+		"n":    "next",
+		"f":    "f",
+		"fi":   "finish",
+		"b":    "breakpoint",
+		"bt":   "bt",
+		"i":    "info",
+		"inf":  "info",
+		"infe": "inferior",
+		"q":    "quit",
+	}
+
+	for name, expectedName := range expected {
+		cmd, ok := cmds.lookup(name)
+		expect.True(t, ok)
+		expect.Equal(t, expectedName, cmd.name)
+	}
+
+	_, ok := cmds.lookup("bogus")
+	expect.False(t, ok)
+}
+
+func (CommandSuite) TestOutputIsNotRedirectedByDefault(t *testing.T) {
+	infs, _ := startTestInferior(t, "counter", false)
+	defer infs.close()
+
+	expect.Nil(t, infs.current.monitor)
+
+	infs.redirectOutput = true
+	inf, err := infs.start([]string{"../../debugger/test_targets/counter"})
+	expect.Nil(t, err)
+	expect.NotNil(t, inf.monitor)
+}
+
+func (CommandSuite) TestResumeUntilOutput(t *testing.T) {
+	infs, _ := startTestInferior(t, "hello_world", true)
+	defer infs.close()
+
+	inf := infs.current
+	db := inf.debugger
+
+	// Every SIGSTOP stop is reported by the resume which sent the interrupt,
+	// i.e., the interrupt never leaks into a subsequent resume.
+	numInterrupts := 0
+	for {
+		status, interrupted, err := resumeUntilOutput(
+			db,
+			inf.monitor,
+			db.ResumeAllUntilSignal)
+		expect.Nil(t, err)
+
+		if status.Exited {
+			expect.Equal(t, 0, status.ExitStatus)
+			break
+		}
+
+		expect.True(t, status.Stopped)
+		if status.StopSignal == syscall.SIGSTOP {
+			expect.True(t, interrupted)
+			numInterrupts++
+		}
+
+		expect.True(t, numInterrupts <= 1)
+	}
+}
+
+func (CommandSuite) TestSetBreakPointAfterExecInNewMode(t *testing.T) {
+	infs, cmds := startTestInferior(t, "exec_self", false)
+	defer infs.close()
+
+	db := infs.current.debugger
+
+	err := cmds.run("set follow-exec-mode new")
+	expect.Nil(t, err)
+	expect.Equal(t, debugger.FollowExecNew, db.FollowExecMode)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, common.ExecTrap, status.TrapKind)
+
+	// The commands operate on the debugger's (reset) break point set, which is
+	// bound to the new program image.
+	err = cmds.run("breakpoint set function run_helper")
+	expect.Nil(t, err)
+
+	points := db.BreakPoints.List()
+	expect.Equal(t, 1, len(points))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, common.SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "run_helper", status.FunctionName)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, points[0].Id(), status.StopPoints[0].Id())
+
+	err = cmds.run("breakpoint remove " + strconv.FormatInt(points[0].Id(), 10))
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(db.BreakPoints.List()))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (CommandSuite) TestResumeIgnoringWatchPoint(t *testing.T) {
+	infs, cmds := startTestInferior(t, "counter", false)
+	defer infs.close()
+
+	db := infs.current.debugger
+
+	mainPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	counter, err := db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	watchPoint, err := db.WatchPoints.Set(
+		db.NewAddressResolver(counter.Address),
+		stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+		true)
+	expect.Nil(t, err)
+
+	err = watchPoint.SetIgnoreCount(1)
+	expect.Nil(t, err)
+
+	readCounter := func() string {
+		counter, err := db.ResolveVariableExpression("g_counter")
+		expect.Nil(t, err)
+		return counter.FormatValue()
+	}
+
+	// The break point and the watch point share the same id.
+	expect.Equal(t, mainPoint.Id(), watchPoint.Id())
+	id := strconv.FormatInt(watchPoint.Id(), 10)
+
+	err = cmds.run("continue -ignore " + id + " 3")
+	expect.Nil(t, err)
+	expect.Equal(t, "0", readCounter())
+
+	err = cmds.run("continue -ignore w" + id + " 3")
+	expect.Nil(t, err)
+	expect.Equal(t, "4", readCounter())
+
+	// The watch point's ignore count is restored after the resume.
+	expect.Equal(t, 1, watchPoint.IgnoreCount())
+
+	err = cmds.run("continue")
+	expect.Nil(t, err)
+	expect.Equal(t, "6", readCounter())
+}
@@ -1,15 +1,57 @@
 package main
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/pattyshack/bad/debugger"
 	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
 )
 
+// Returns the integer width (in bytes) and the optional integer format for
+// the "/<b|h|w|g>[x|d|o|t]" argument.
+func parseMemoryWidth(format string) (int, IntegerFormat, bool) {
+	integerFormat := NaturalFormat
+	if len(format) == 3 {
+		var ok bool
+		integerFormat, ok = ParseIntegerFormat(format[2])
+		if !ok {
+			fmt.Println("invalid integer format (x, d, o or t):", format)
+			return 0, NaturalFormat, false
+		}
+		format = format[:2]
+	}
+
+	switch format {
+	case "/b":
+		return 1, integerFormat, true
+	case "/h":
+		return 2, integerFormat, true
+	case "/w":
+		return 4, integerFormat, true
+	case "/g":
+		return 8, integerFormat, true
+	default:
+		fmt.Println("invalid memory width (/b, /h, /w or /g):", format)
+		return 0, NaturalFormat, false
+	}
+}
+
 func readMemory(db *debugger.Debugger, argsStr string) error {
+	if strings.HasPrefix(strings.TrimSpace(argsStr), "/") {
+		format, remaining := splitArg(argsStr)
+		width, integerFormat, ok := parseMemoryWidth(format)
+		if !ok {
+			return nil
+		}
+		return readMemoryInteger(db, width, integerFormat, remaining)
+	}
+
 	addrStr, sizeStr := splitArg(argsStr)
 	sizeStr = strings.TrimSpace(sizeStr)
 
@@ -73,8 +115,123 @@ func readMemory(db *debugger.Debugger, argsStr string) error {
 	return nil
 }
 
+func readMemoryInteger(
+	db *debugger.Debugger,
+	width int,
+	format IntegerFormat,
+	argsStr string,
+) error {
+	args := splitAllArgs(argsStr)
+	if len(args) != 1 {
+		fmt.Println("Expected argument: <address>")
+		return nil
+	}
+
+	addr, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		fmt.Println("failed to parse memory address:", err)
+		return nil
+	}
+
+	out := make([]byte, width)
+	numRead, err := db.VirtualMemory.Read(VirtualAddress(addr), out)
+	if err != nil {
+		fmt.Println("failed to read from memory:", err)
+		return nil
+	}
+
+	if numRead < width {
+		fmt.Printf(
+			"failed to read from memory. requested %d bytes but only read %d\n",
+			width,
+			numRead)
+		return nil
+	}
+
+	if format != NaturalFormat {
+		fmt.Printf("0x%016x: %s\n", addr, format.FormatBytes(out, false))
+		return nil
+	}
+
+	value := registers.FromBytes(out)
+	fmt.Printf("0x%016x: %s (%d)\n", addr, value, value.ToUint64())
+	return nil
+}
+
+func readTypedMemory(db *debugger.Debugger, argsStr string) error {
+	addrStr, remaining := splitArg(argsStr)
+	countStr, typeName := splitArg(remaining)
+	typeName = strings.TrimSpace(typeName)
+
+	if addrStr == "" || countStr == "" || typeName == "" {
+		fmt.Println("Expected arguments: <address> <count> <type>")
+		return nil
+	}
+
+	addr, err := strconv.ParseUint(addrStr, 0, 64)
+	if err != nil {
+		fmt.Println("failed to parse memory address:", err)
+		return nil
+	}
+
+	count, err := strconv.ParseInt(countStr, 0, 32)
+	if err != nil {
+		fmt.Println("failed to parse element count:", err)
+		return nil
+	}
+
+	data, err := db.ReadTypedMemory(VirtualAddress(addr), int(count), typeName)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	elements := []string{}
+	for i := 0; i < data.NumElements; i++ {
+		element, err := data.Index(i)
+		if err != nil {
+			return err
+		}
+
+		_, err = element.Bytes()
+		if err != nil {
+			fmt.Printf("failed to read element %d: %s\n", i, err)
+			break
+		}
+
+		elements = append(elements, element.Format("  "))
+	}
+
+	fmt.Printf("%s (%s): [\n", data.FormatPrefix, data.TypeName())
+	for _, element := range elements {
+		fmt.Println(element + ",")
+	}
+	fmt.Println("]")
+
+	return nil
+}
+
 func writeMemory(db *debugger.Debugger, argsStr string) error {
 	args := splitAllArgs(argsStr)
+
+	width := 0
+	if len(args) > 0 && strings.HasPrefix(args[0], "/") {
+		var format IntegerFormat
+		var ok bool
+		width, format, ok = parseMemoryWidth(args[0])
+		if !ok {
+			return nil
+		}
+		if format != NaturalFormat {
+			fmt.Println("integer format is not supported by write:", args[0])
+			return nil
+		}
+		args = args[1:]
+	}
+
 	if len(args) == 0 {
 		fmt.Println("failed to write to memory. address not specified.")
 		return nil
@@ -86,8 +243,64 @@ func writeMemory(db *debugger.Debugger, argsStr string) error {
 		return nil
 	}
 
+	var data []byte
+	if width > 0 {
+		data = parseMemoryInteger(width, args[1:])
+	} else {
+		data = parseMemoryBytes(args[1:])
+	}
+
+	if data == nil {
+		return nil
+	}
+
+	numWritten, err := db.VirtualMemory.Write(VirtualAddress(addr), data)
+	if err != nil {
+		fmt.Println("failed to write to memory:", err)
+		return nil
+	}
+
+	if numWritten < len(data) {
+		fmt.Printf(
+			"WARNING: provided %d bytes but only written %d bytes.\n",
+			len(data),
+			numWritten)
+	}
+
+	return nil
+}
+
+// Returns the little endian encoded integer, or nil on error.
+func parseMemoryInteger(width int, args []string) []byte {
+	if len(args) != 1 {
+		fmt.Println("failed to write to memory. expected a single value.")
+		return nil
+	}
+
+	// NOTE: negative values are written in two's complement.
+	var value uint64
+	var err error
+	if strings.HasPrefix(args[0], "-") {
+		var signed int64
+		signed, err = strconv.ParseInt(args[0], 0, width*8)
+		value = uint64(signed)
+	} else {
+		value, err = strconv.ParseUint(args[0], 0, width*8)
+	}
+
+	if err != nil {
+		fmt.Println("failed to parse value:", err)
+		return nil
+	}
+
+	data := binary.LittleEndian.AppendUint64(nil, value)
+	return data[:width]
+}
+
+// Returns the parsed bytes, or nil on error.
+func parseMemoryBytes(args []string) []byte {
 	data := []byte{}
-	for idx, arg := range args[1:] {
+	for idx, arg := range args {
 		val, err := strconv.ParseUint(arg, 0, 8)
 		if err != nil {
 			fmt.Printf(
@@ -105,18 +318,219 @@ func writeMemory(db *debugger.Debugger, argsStr string) error {
 		return nil
 	}
 
-	numWritten, err := db.VirtualMemory.Write(VirtualAddress(addr), data)
+	return data
+}
+
+func compareMemory(db *debugger.Debugger, argsStr string) error {
+	args := splitAllArgs(argsStr)
+	if len(args) != 2 && len(args) != 3 {
+		fmt.Println(
+			"Expected arguments: <address 1> <address 2> <n> | <address> <file>")
+		return nil
+	}
+
+	addr, err := strconv.ParseUint(args[0], 0, 64)
 	if err != nil {
-		fmt.Println("failed to write to memory:", err)
+		fmt.Println("failed to parse memory address:", err)
 		return nil
 	}
 
-	if numWritten < len(data) {
+	var comparison *debugger.MemoryComparison
+	var rightName string
+	if len(args) == 3 {
+		other, err := strconv.ParseUint(args[1], 0, 64)
+		if err != nil {
+			fmt.Println("failed to parse memory address:", err)
+			return nil
+		}
+
+		size, err := strconv.ParseInt(args[2], 0, 32)
+		if err != nil {
+			fmt.Println("failed to parse compare size:", err)
+			return nil
+		}
+
+		comparison, err = db.CompareMemory(
+			VirtualAddress(addr),
+			VirtualAddress(other),
+			int(size))
+		if err != nil {
+			if errors.Is(err, ErrInvalidInput) {
+				fmt.Println(err)
+				return nil
+			}
+			return err
+		}
+		rightName = VirtualAddress(other).String()
+	} else {
+		content, err := os.ReadFile(args[1])
+		if err != nil {
+			fmt.Println("failed to read file:", err)
+			return nil
+		}
+
+		comparison, err = db.CompareMemoryWithBytes(VirtualAddress(addr), content)
+		if err != nil {
+			if errors.Is(err, ErrInvalidInput) {
+				fmt.Println(err)
+				return nil
+			}
+			return err
+		}
+		rightName = args[1]
+	}
+
+	fmt.Printf(
+		"compared %d bytes (%s vs %s): %d differences\n",
+		comparison.Size,
+		comparison.Address,
+		rightName,
+		comparison.NumDiffs)
+	for _, diff := range comparison.Diffs {
 		fmt.Printf(
-			"WARNING: provided %d bytes but only written %d bytes.\n",
-			len(data),
-			numWritten)
+			"  +%d (%s): 0x%02x vs 0x%02x\n",
+			diff.Offset,
+			comparison.Address+VirtualAddress(diff.Offset),
+			diff.Left,
+			diff.Right)
+	}
+
+	if comparison.NumDiffs > len(comparison.Diffs) {
+		fmt.Printf(
+			"  ... %d more differences (see set memory-compare-limit)\n",
+			comparison.NumDiffs-len(comparison.Diffs))
+	}
+
+	return nil
+}
+
+func setMemoryCompareLimit(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+	switch args {
+	case "":
+		if db.MemoryCompareLimit <= 0 {
+			fmt.Println("memory compare limit: off")
+		} else {
+			fmt.Println("memory compare limit:", db.MemoryCompareLimit)
+		}
+		return nil
+	case "off":
+		db.MemoryCompareLimit = 0
+		fmt.Println("memory compare limit disabled")
+		return nil
+	}
+
+	limit, err := strconv.ParseInt(args, 10, 32)
+	if err != nil || limit <= 0 {
+		fmt.Println("invalid memory compare limit:", args)
+		return nil
+	}
+
+	db.MemoryCompareLimit = int(limit)
+	fmt.Println("memory compare limit set to", limit)
+	return nil
+}
+
+func setMemoryCache(db *debugger.Debugger, args string) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		db.VirtualMemory.SetCacheEnabled(true)
+		fmt.Println("memory cache enabled")
+	case "off":
+		db.VirtualMemory.SetCacheEnabled(false)
+		fmt.Println("memory cache disabled")
+	case "":
+		fmt.Println("memory cache mode (on/off) not specified")
+	default:
+		fmt.Println("invalid memory cache mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
+
+func watchMemoryDiff(db *debugger.Debugger, argsStr string) error {
+	args := splitAllArgs(argsStr)
+	if len(args) == 0 {
+		watches := db.MemoryWatches.List()
+		fmt.Println("Memory watches:")
+		if len(watches) == 0 {
+			fmt.Println("  (none)")
+		}
+		for _, watch := range watches {
+			fmt.Println("  " + watch.String())
+		}
+		return nil
+	}
+
+	if args[0] == "delete" {
+		if len(args) != 2 {
+			fmt.Println("Expected arguments: delete <id>")
+			return nil
+		}
+
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Println("failed to parse memory watch id:", err)
+			return nil
+		}
+
+		if !db.MemoryWatches.Remove(id) {
+			fmt.Printf("memory watch (id=%d) not found\n", id)
+			return nil
+		}
+
+		fmt.Printf("memory watch (id=%d) deleted\n", id)
+		return nil
 	}
 
+	if len(args) != 2 {
+		fmt.Println("Expected arguments: <address> <n>")
+		return nil
+	}
+
+	addr, err := strconv.ParseUint(args[0], 0, 64)
+	if err != nil {
+		fmt.Println("failed to parse memory address:", err)
+		return nil
+	}
+
+	size, err := strconv.ParseInt(args[1], 0, 32)
+	if err != nil {
+		fmt.Println("failed to parse watch size:", err)
+		return nil
+	}
+
+	watch, err := db.AddMemoryWatch(VirtualAddress(addr), int(size))
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println("added memory watch", watch)
 	return nil
 }
+
+func printMemoryWatchDiffs(db *debugger.Debugger) {
+	for _, diff := range db.DiffMemoryWatches() {
+		if diff.Err != nil {
+			fmt.Printf(
+				"memory watch %s is unreadable: %s\n",
+				diff.MemoryWatch,
+				diff.Err)
+			continue
+		}
+
+		fmt.Printf("memory watch %s changed:\n", diff.MemoryWatch)
+		for _, byteDiff := range diff.Bytes {
+			fmt.Printf(
+				"  +%d (%s): 0x%02x -> 0x%02x\n",
+				byteDiff.Offset,
+				diff.Address+VirtualAddress(byteDiff.Offset),
+				byteDiff.Old,
+				byteDiff.New)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// outputMonitor relays the inferior's stdout / stderr (via pipes) to the
+// debugger's stdout / stderr, and notifies the watcher whenever the inferior
+// writes output.
+type outputMonitor struct {
+	writers []*os.File
+	relays  []relay
+
+	mutex   sync.Mutex
+	watcher chan struct{}
+}
+
+type relay struct {
+	from *os.File
+	to   io.Writer
+}
+
+func newOutputMonitor(
+	cmd *exec.Cmd,
+	stdout *os.File,
+) (
+	*outputMonitor,
+	error,
+) {
+	monitor := &outputMonitor{}
+
+	for _, dest := range []*os.File{stdout, os.Stderr} {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			_ = monitor.Close()
+			return nil, err
+		}
+
+		monitor.writers = append(monitor.writers, writer)
+		monitor.relays = append(
+			monitor.relays,
+			relay{
+				from: reader,
+				to:   dest,
+			})
+	}
+
+	cmd.Stdout = monitor.writers[0]
+	cmd.Stderr = monitor.writers[1]
+
+	return monitor, nil
+}
+
+// Start must be called after the inferior started.
+func (monitor *outputMonitor) Start() {
+	// The inferior has its own copies of the pipe's write ends.
+	for _, writer := range monitor.writers {
+		_ = writer.Close()
+	}
+	monitor.writers = nil
+
+	for _, r := range monitor.relays {
+		go monitor.relay(r)
+	}
+}
+
+func (monitor *outputMonitor) relay(r relay) {
+	buffer := make([]byte, 4096)
+	for {
+		n, err := r.from.Read(buffer)
+		if n > 0 {
+			_, _ = r.to.Write(buffer[:n])
+			monitor.notify()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (monitor *outputMonitor) notify() {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if monitor.watcher != nil {
+		close(monitor.watcher)
+		monitor.watcher = nil
+	}
+}
+
+// The returned channel is closed the next time the inferior writes output.
+func (monitor *outputMonitor) Watch() <-chan struct{} {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+
+	if monitor.watcher == nil {
+		monitor.watcher = make(chan struct{})
+	}
+
+	return monitor.watcher
+}
+
+func (monitor *outputMonitor) Close() error {
+	for _, writer := range monitor.writers {
+		_ = writer.Close()
+	}
+
+	for _, r := range monitor.relays {
+		_ = r.from.Close()
+	}
+
+	return nil
+}
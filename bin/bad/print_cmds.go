@@ -9,14 +9,103 @@ import (
 	. "github.com/pattyshack/bad/debugger/common"
 )
 
+// Parse either a numeric address, or a symbol relative address of the form
+// <symbol>[+<offset>]
+func parseCodeAddress(
+	db *debugger.Debugger,
+	value string,
+) (
+	VirtualAddress,
+	error,
+) {
+	base, offsetStr, hasOffset := strings.Cut(value, "+")
+
+	offset := uint64(0)
+	if hasOffset {
+		val, err := strconv.ParseUint(offsetStr, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid offset (%s): %w", offsetStr, err)
+		}
+		offset = val
+	}
+
+	if base == "" {
+		return 0, fmt.Errorf("address not specified")
+	} else if '0' <= base[0] && base[0] <= '9' {
+		val, err := strconv.ParseUint(base, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid address (%s): %w", base, err)
+		}
+
+		return VirtualAddress(val + offset), nil
+	}
+
+	for _, symbol := range db.LoadedElves.SymbolsByName(base) {
+		_, _, ok := symbol.AddressRange()
+		if !ok {
+			continue
+		}
+
+		addr, err := db.LoadedElves.SymbolToVirtualAddress(symbol)
+		if err != nil {
+			return 0, err
+		}
+
+		return addr + VirtualAddress(offset), nil
+	}
+
+	return 0, fmt.Errorf("symbol (%s) not found", base)
+}
+
+func disassembleRange(
+	db *debugger.Debugger,
+	startStr string,
+	endStr string,
+) error {
+	start, err := parseCodeAddress(db, startStr)
+	if err != nil {
+		fmt.Println("Invalid <start> argument:", err)
+		return nil
+	}
+
+	end, err := parseCodeAddress(db, endStr)
+	if err != nil {
+		fmt.Println("Invalid <end> argument:", err)
+		return nil
+	}
+
+	instructions, err := db.DisassembleRange(start, end)
+	if err != nil {
+		fmt.Printf(
+			"failed to disassemble instructions in [%s, %s): %s\n",
+			start,
+			end,
+			err)
+		return nil
+	}
+
+	for _, inst := range instructions {
+		fmt.Println(inst)
+	}
+
+	return nil
+}
+
 func disassemble(db *debugger.Debugger, argsStr string) error {
+	args := splitAllArgs(argsStr)
+	if len(args) == 2 &&
+		!strings.HasPrefix(args[0], "@") &&
+		!strings.HasPrefix(args[1], "@") {
+
+		return disassembleRange(db, args[0], args[1])
+	}
 
 	addrStr := ""
 	addr := db.CurrentStatus().NextInstructionAddress
 
 	numInstStr := ""
 	numInst := 5
-	for _, arg := range splitAllArgs(argsStr) {
+	for _, arg := range args {
 		if strings.HasPrefix(arg, "@") {
 			if addrStr == "" {
 				addrStr = arg
@@ -71,11 +160,19 @@ func disassemble(db *debugger.Debugger, argsStr string) error {
 }
 
 func printThreadStatus(db *debugger.Debugger, status *debugger.ThreadStatus) {
+	// NOTE: shared libraries may have been loaded since the last stop.
+	printDebugInfoReports(db)
+	printProducerWarnings(db)
+	printStepWarnings(db)
+
 	fmt.Println(status)
 	if !status.Stopped {
 		return
 	}
 
+	printMemoryWatchDiffs(db)
+	printDisplays(db)
+
 	if status.FileEntry != nil {
 		snippet, err := db.SourceFiles.GetSnippet(
 			status.FileEntry.Path(),
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultPrompt = "bad > "
+
+// prompt is the REPL's (readline) prompt.  The prompt is recomputed before
+// every Readline call since the context (current thread id / function)
+// changes as the process resumes and the user switches threads / inferiors.
+//
+// NOTE: the transcript always records commands with transcriptCommandPrefix
+// (regardless of the prompt) so that transcripts remain replayable.
+type prompt struct {
+	inferiors *inferiors
+
+	text string
+
+	// When true, the prompt is prefixed by the current thread's id and
+	// function, e.g., "[tid 1234 main] bad > ".  The current inferior's id is
+	// also included when there are multiple inferiors, e.g.,
+	// "[inferior 2 tid 1234 main] bad > ".
+	showContext bool
+}
+
+func newPrompt(infs *inferiors) *prompt {
+	return &prompt{
+		inferiors: infs,
+		text:      defaultPrompt,
+	}
+}
+
+func (p *prompt) String() string {
+	if !p.showContext {
+		return p.text
+	}
+
+	return "[" + p.context() + "] " + p.text
+}
+
+func (p *prompt) context() string {
+	inferior := ""
+	if len(p.inferiors.list) > 1 {
+		inferior = fmt.Sprintf("inferior %d ", p.inferiors.current.id)
+	}
+
+	status := p.inferiors.current.debugger.CurrentStatus()
+	if status.Exited || status.Signaled {
+		return fmt.Sprintf("%stid %d exited", inferior, status.Tid)
+	}
+
+	if status.FunctionName == "" {
+		return fmt.Sprintf("%stid %d", inferior, status.Tid)
+	}
+
+	return fmt.Sprintf("%stid %d %s", inferior, status.Tid, status.FunctionName)
+}
+
+func (p *prompt) setPrompt(args string) error {
+	arg, remaining := splitArg(args)
+	switch arg {
+	case "":
+		fmt.Printf("prompt: %q (context %s)\n", p.text, onOff(p.showContext))
+	case "-default":
+		p.text = defaultPrompt
+		p.showContext = false
+		fmt.Printf("prompt reset to %q\n", defaultPrompt)
+	case "-context":
+		switch strings.TrimSpace(remaining) {
+		case "on":
+			p.showContext = true
+			fmt.Println("prompt context enabled")
+		case "off":
+			p.showContext = false
+			fmt.Println("prompt context disabled")
+		case "":
+			fmt.Println("prompt context mode (on/off) not specified")
+		default:
+			fmt.Println("invalid prompt context mode:", strings.TrimSpace(remaining))
+		}
+	default:
+		p.text = strings.TrimSpace(args) + " "
+		fmt.Printf("prompt set to %q\n", p.text)
+	}
+
+	return nil
+}
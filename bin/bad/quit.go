@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// Returned by the quit command to end the session.  The inferiors' processes
+// are killed / detached by the deferred inferiors.close.
+var errQuit = errors.New("quit")
+
+// quitCommand asks for confirmation before ending the session while any
+// inferior's process is still alive (unless confirmation is disabled via set
+// confirm, or the session runs in batch mode).
+type quitCommand struct {
+	inferiors *inferiors
+
+	confirm bool
+
+	// nil in batch mode, in which case quit does not ask for confirmation.
+	rl *readline.Instance
+}
+
+func newQuitCommand(infs *inferiors) *quitCommand {
+	return &quitCommand{
+		inferiors: infs,
+		confirm:   true,
+	}
+}
+
+func (cmd *quitCommand) quit(args string) error {
+	if strings.TrimSpace(args) != "" {
+		fmt.Println("unexpected arguments:", strings.TrimSpace(args))
+		return nil
+	}
+
+	if !cmd.confirm || cmd.rl == nil {
+		return errQuit
+	}
+
+	alive := false
+	for _, inf := range cmd.inferiors.list {
+		if inf.debugger.Terminated() {
+			continue
+		}
+		alive = true
+
+		action := "detached"
+		if inf.debugger.OwnsProcess() {
+			action = "killed"
+		}
+
+		fmt.Printf("process %d will be %s\n", inf.debugger.Pid, action)
+	}
+
+	if !alive {
+		return errQuit
+	}
+
+	if !cmd.ask("Quit anyway? (y or n) ") {
+		fmt.Println("quit not confirmed")
+		return nil
+	}
+
+	return errQuit
+}
+
+// NOTE: similar to gdb, end of input is treated as confirmation since the
+// input is not from the terminal.
+func (cmd *quitCommand) ask(question string) bool {
+	cmd.rl.SetPrompt(question)
+	for {
+		line, err := cmd.rl.Readline()
+		if err == io.EOF {
+			fmt.Println("EOF [answered y; input not from terminal]")
+			return true
+		} else if err != nil { // i.e., readline.ErrInterrupt
+			return false
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+
+		fmt.Println("please answer y or n")
+	}
+}
+
+func (cmd *quitCommand) setConfirm(args string) error {
+	switch strings.TrimSpace(args) {
+	case "":
+		fmt.Println("confirm:", onOff(cmd.confirm))
+	case "on":
+		cmd.confirm = true
+		fmt.Println("confirmation enabled")
+	case "off":
+		cmd.confirm = false
+		fmt.Println("confirmation disabled")
+	default:
+		fmt.Println("invalid confirm mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
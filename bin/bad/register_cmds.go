@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/pattyshack/bad/debugger"
@@ -10,23 +13,51 @@ import (
 	"github.com/pattyshack/bad/debugger/registers"
 )
 
+func formatValue(
+	reg registers.Spec,
+	value registers.Value,
+	format IntegerFormat,
+) string {
+	if format != NaturalFormat {
+		return format.FormatBytes(value.ToBytes(), false)
+	}
+
+	u128, ok := value.(registers.Uint128)
+	if !ok || !strings.HasPrefix(reg.Name, "st") {
+		return value.String()
+	}
+
+	// NOTE: the long double value is approximated by a double, and may lose
+	// precision.
+	return fmt.Sprintf("%s (long double ~ %g)", u128, u128.ToLongDouble())
+}
+
 func printRegisters(
 	indent string,
 	state registers.State,
-	match string, // "", "all", or "<name>"
+	match string, // "", "all", or "<name|first-last> ..."
+	format IntegerFormat,
 ) {
 	if match != "" && match != "all" {
-		reg, ok := registers.ByName(match)
-		if !ok {
-			fmt.Printf("%sInvalid register: %s", indent, match)
-			return
-		}
+		for _, arg := range splitAllArgs(match) {
+			regs, ok := parseRegisterRange(arg)
+			if !ok {
+				fmt.Printf("%sInvalid register: %s\n", indent, arg)
+				continue
+			}
 
-		value := state.Value(reg)
-		if value == nil {
-			fmt.Printf("%s%-8s (undefined)\n", indent, reg.Name)
-		} else {
-			fmt.Printf("%s%-8s %s\n", indent, reg.Name, value)
+			for _, reg := range regs {
+				value := state.Value(reg)
+				if value == nil {
+					fmt.Printf("%s%-8s (undefined)\n", indent, reg.Name)
+				} else {
+					fmt.Printf(
+						"%s%-8s %s\n",
+						indent,
+						reg.Name,
+						formatValue(reg, value, format))
+				}
+			}
 		}
 		return
 	}
@@ -52,7 +83,7 @@ func printRegisters(
 		value := state.Value(reg)
 		valueStr := "(undefined)"
 		if value != nil {
-			valueStr = value.String()
+			valueStr = formatValue(reg, value, format)
 		}
 
 		format := "%s%-8s %s\n"
@@ -60,16 +91,76 @@ func printRegisters(
 	}
 }
 
+// Parses either a register name, or an inclusive range of numbered registers
+// (e.g., r8-r12, r8d-r11d, xmm0-xmm3).
+func parseRegisterRange(arg string) ([]registers.Spec, bool) {
+	first, last, isRange := strings.Cut(arg, "-")
+	if !isRange {
+		reg, ok := registers.ByName(arg)
+		if !ok {
+			return nil, false
+		}
+		return []registers.Spec{reg}, true
+	}
+
+	prefix, low, suffix, ok := splitRegisterNumber(first)
+	if !ok {
+		return nil, false
+	}
+
+	lastPrefix, high, lastSuffix, ok := splitRegisterNumber(last)
+	if !ok || prefix != lastPrefix || suffix != lastSuffix || low > high {
+		return nil, false
+	}
+
+	result := []registers.Spec{}
+	for number := low; number <= high; number++ {
+		reg, ok := registers.ByName(fmt.Sprintf("%s%d%s", prefix, number, suffix))
+		if !ok {
+			return nil, false
+		}
+		result = append(result, reg)
+	}
+
+	return result, true
+}
+
+// Splits a numbered register name (e.g., r8d) into its prefix (r), number (8)
+// and suffix (d).
+func splitRegisterNumber(name string) (string, int, string, bool) {
+	start := strings.IndexAny(name, "0123456789")
+	if start == -1 {
+		return "", 0, "", false
+	}
+
+	end := start
+	for end < len(name) && '0' <= name[end] && name[end] <= '9' {
+		end++
+	}
+
+	number, err := strconv.Atoi(name[start:end])
+	if err != nil {
+		return "", 0, "", false
+	}
+
+	return name[:start], number, name[end:], true
+}
+
 func readRegister(db *debugger.Debugger, args string) error {
 	state, err := db.GetInspectFrameRegisterState()
 	if err != nil {
 		return err
 	}
 
+	format, args, ok := splitIntegerFormat(args)
+	if !ok {
+		return nil
+	}
+
 	args = strings.TrimSpace(args)
 
 	fmt.Println("Registers:", args)
-	printRegisters("  ", state, args)
+	printRegisters("  ", state, args, format)
 	return nil
 }
 
@@ -115,3 +206,222 @@ func writeRegister(db *debugger.Debugger, argsStr string) error {
 
 	return nil
 }
+
+func dumpRegisters(db *debugger.Debugger, args string) error {
+	path := strings.TrimSpace(args)
+	if path == "" {
+		fmt.Println("Expected one argument: <path>")
+		return nil
+	}
+
+	state, err := db.GetInspectFrameRegisterState()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Println("Failed to create register dump file:", err)
+		return nil
+	}
+	defer file.Close()
+
+	err = state.Dump(file)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println("Dumped registers to", path)
+	return nil
+}
+
+func restoreRegisters(db *debugger.Debugger, args string) error {
+	path := strings.TrimSpace(args)
+	if path == "" {
+		fmt.Println("Expected one argument: <path>")
+		return nil
+	}
+
+	state, ok, err := loadRegisterDump(path)
+	if !ok || err != nil {
+		return err
+	}
+
+	err = db.SetInspectFrameRegisterState(state)
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	fmt.Println("Restored registers from", path)
+	return nil
+}
+
+// Returns false if the dump file could not be loaded due to user error (the
+// error is printed).
+func loadRegisterDump(path string) (registers.State, bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println("Failed to open register dump file:", err)
+		return registers.State{}, false, nil
+	}
+	defer file.Close()
+
+	state, err := registers.LoadDump(bufio.NewReader(file))
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			fmt.Printf("Failed to load %s: %s\n", path, err)
+			return registers.State{}, false, nil
+		}
+		return registers.State{}, false, err
+	}
+
+	return state, true, nil
+}
+
+func diffRegisters(db *debugger.Debugger, args string) error {
+	paths := splitAllArgs(args)
+	if len(paths) != 2 {
+		fmt.Println("Expected two arguments: <old path> <new path>")
+		return nil
+	}
+
+	oldState, ok, err := loadRegisterDump(paths[0])
+	if !ok || err != nil {
+		return err
+	}
+
+	newState, ok, err := loadRegisterDump(paths[1])
+	if !ok || err != nil {
+		return err
+	}
+
+	diffs := oldState.Diff(newState)
+	if len(diffs) == 0 {
+		fmt.Println("No register differences")
+		return nil
+	}
+
+	fmt.Printf("Register differences (%s -> %s):\n", paths[0], paths[1])
+	for _, diff := range diffs {
+		oldStr := "(undefined)"
+		if diff.Old != nil {
+			oldStr = formatValue(diff.Spec, diff.Old, NaturalFormat)
+		}
+
+		newStr := "(undefined)"
+		if diff.New != nil {
+			newStr = formatValue(diff.Spec, diff.New, NaturalFormat)
+		}
+
+		fmt.Printf("  %-8s %s -> %s\n", diff.Name, oldStr, newStr)
+
+		if diff.Name == "eflags" && diff.Old != nil && diff.New != nil {
+			oldFlags := diff.Old.ToUint64()
+			newFlags := diff.New.ToUint64()
+			fmt.Printf(
+				"  %-8s %s -> %s (%s)\n",
+				"",
+				registers.FormatEflags(oldFlags),
+				registers.FormatEflags(newFlags),
+				registers.FormatEflagsChanges(oldFlags, newFlags))
+		}
+	}
+
+	return nil
+}
+
+func printDebugRegisters(db *debugger.Debugger, args string) error {
+	info, err := db.DebugRegisters()
+	if err != nil {
+		if errors.Is(err, ErrProcessExited) {
+			fmt.Println(err)
+			return nil
+		}
+		return err
+	}
+
+	statusBits := []string{}
+	if info.SingleStepped {
+		statusBits = append(statusBits, "single step")
+	}
+	if info.AccessDetected {
+		statusBits = append(statusBits, "debug register access")
+	}
+	if info.TaskSwitched {
+		statusBits = append(statusBits, "task switch")
+	}
+
+	status := ""
+	if len(statusBits) > 0 {
+		status = " (" + strings.Join(statusBits, ", ") + ")"
+	}
+
+	fmt.Printf("Debug registers (thread %d):\n", info.Tid)
+	fmt.Printf("  dr7 (control): 0x%016x\n", info.Control)
+	fmt.Printf("  dr6 (status):  0x%016x%s\n", info.Status, status)
+	fmt.Printf(
+		"  %-4s  %-18s  %-12s  %-13s  %-4s  %-9s  %s\n",
+		"slot",
+		"address",
+		"enabled",
+		"condition",
+		"size",
+		"triggered",
+		"installed")
+
+	for idx, slot := range info.Slots {
+		enabled := "no"
+		if slot.IsLocallyEnabled && slot.IsGloballyEnabled {
+			enabled = "local+global"
+		} else if slot.IsLocallyEnabled {
+			enabled = "local"
+		} else if slot.IsGloballyEnabled {
+			enabled = "global"
+		}
+
+		triggered := "no"
+		if slot.Triggered {
+			triggered = "yes"
+		}
+
+		installed := "(unoccupied)"
+		site := info.Sites[idx]
+		if site != nil {
+			points := []string{}
+			for _, point := range info.StopPoints[idx] {
+				kind := "break point"
+				if point.Type().IsWatchPoint {
+					kind = "watch point"
+				}
+				points = append(points, fmt.Sprintf("%s %d", kind, point.Id()))
+			}
+
+			installed = "(internal)"
+			if len(points) > 0 {
+				installed = strings.Join(points, ", ")
+			}
+
+			installed = fmt.Sprintf("%s: %s", site.Key(), installed)
+		}
+
+		fmt.Printf(
+			"  dr%-2d  %-18s  %-12s  %-13s  %-4d  %-9s  %s\n",
+			slot.Index,
+			slot.Address,
+			enabled,
+			slot.Mode,
+			slot.WatchSize,
+			triggered,
+			installed)
+	}
+
+	return nil
+}
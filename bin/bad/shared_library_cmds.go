@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+)
+
+type sharedLibraryCommands struct {
+	debugger *debugger.Debugger
+
+	// When true, shared library load / unload events are printed as they are
+	// detected.
+	notify bool
+}
+
+func newSharedLibraryCommands(db *debugger.Debugger) *sharedLibraryCommands {
+	cmd := &sharedLibraryCommands{
+		debugger: db,
+	}
+	db.WatchSharedLibraryEvents(cmd.printEvent)
+	return cmd
+}
+
+func (cmd *sharedLibraryCommands) printEvent(
+	event debugger.SharedLibraryEvent,
+) {
+	if cmd.notify {
+		fmt.Println("Shared library event:", event)
+	}
+}
+
+func (cmd *sharedLibraryCommands) printLoadOrder(args string) error {
+	events := cmd.debugger.SharedLibraryEvents()
+
+	fmt.Println("Shared library events:")
+	if len(events) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, event := range events {
+		fmt.Println("  " + event.String())
+	}
+
+	return nil
+}
+
+func (cmd *sharedLibraryCommands) setNotify(args string) error {
+	switch strings.TrimSpace(args) {
+	case "on":
+		cmd.notify = true
+		fmt.Println("shared library event notifications enabled")
+	case "off":
+		cmd.notify = false
+		fmt.Println("shared library event notifications disabled")
+	case "":
+		fmt.Println("shared library event notification mode (on/off) not specified")
+	default:
+		fmt.Println(
+			"invalid shared library event notification mode:",
+			strings.TrimSpace(args))
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/pattyshack/bad/debugger"
+)
+
+func parseSignal(name string) (syscall.Signal, bool) {
+	num, err := strconv.ParseInt(name, 10, 32)
+	if err == nil {
+		signal := syscall.Signal(num)
+		return signal, unix.SignalName(signal) != ""
+	}
+
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	signal := unix.SignalNum(name)
+	return signal, signal != 0
+}
+
+func setSignalPass(db *debugger.Debugger, args string) error {
+	name, mode := splitArg(args)
+	mode = strings.TrimSpace(mode)
+
+	if name == "" {
+		fmt.Print("signals not passed to the process:")
+		for _, signal := range db.SignalPassPolicy.NoPassSignals() {
+			fmt.Print(" ", unix.SignalName(signal))
+		}
+		fmt.Println()
+		return nil
+	}
+
+	signal, ok := parseSignal(name)
+	if !ok {
+		fmt.Println("invalid signal:", name)
+		return nil
+	}
+
+	switch mode {
+	case "pass":
+		db.SignalPassPolicy.Pass(signal)
+	case "nopass":
+		db.SignalPassPolicy.NoPass(signal)
+	default:
+		fmt.Println("invalid signal pass mode (pass/nopass):", mode)
+		return nil
+	}
+
+	fmt.Printf("%s set to %s\n", unix.SignalName(signal), mode)
+	return nil
+}
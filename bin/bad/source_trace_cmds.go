@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pattyshack/bad/debugger"
+)
+
+func setSourceTrace(db *debugger.Debugger, argsStr string) error {
+	args := splitAllArgs(argsStr)
+	if len(args) == 0 {
+		fmt.Println("source trace mode (on/off) not specified")
+		return nil
+	}
+
+	switch args[0] {
+	case "on":
+		capacity := debugger.DefaultSourceTraceCapacity
+		if len(args) > 1 {
+			value, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil || value <= 0 {
+				fmt.Println("invalid source trace size:", args[1])
+				return nil
+			}
+			capacity = int(value)
+		}
+
+		err := db.SourceTrace.Enable(capacity)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("source trace enabled (size=%d)\n", capacity)
+	case "off":
+		db.SourceTrace.Disable()
+		fmt.Println("source trace disabled")
+	default:
+		fmt.Println("invalid source trace mode:", args[0])
+	}
+
+	return nil
+}
+
+func printSourceTrace(db *debugger.Debugger, args string) error {
+	args = strings.TrimSpace(args)
+	if args == "clear" {
+		db.SourceTrace.Clear()
+		return nil
+	} else if args != "" {
+		fmt.Println("unexpected argument:", args)
+		return nil
+	}
+
+	entries := db.SourceTrace.Entries()
+	if len(entries) == 0 {
+		if !db.SourceTrace.IsEnabled() {
+			fmt.Println("source trace is disabled (enable via set source-trace on)")
+		} else {
+			fmt.Println("no source line recorded")
+		}
+		return nil
+	}
+
+	for idx, entry := range entries {
+		fmt.Printf("%d: %s\n", idx, entry)
+	}
+
+	return nil
+}
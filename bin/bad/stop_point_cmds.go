@@ -25,22 +25,25 @@ type stopPointCommands struct {
 func (cmd stopPointCommands) setBreakpointSubCommands() subCommands {
 	return subCommands{
 		{
-			name:        "function",
-			description: " [-h] <name>      - set function break point",
+			name: "function",
+			description: " [-h] <name> [if <condition>]\n" +
+				"    - set function break point",
 			command: runCmd(func(args string) error {
 				return cmd.setBreakPoint(functionBreakPoint, args)
 			}),
 		},
 		{
-			name:        "line",
-			description: " [-h] <path> <line>   - set line break point",
+			name: "line",
+			description: " [-h] [-a] <path> <line> [if <condition>]\n" +
+				"    - set line break point. -a advances to the next line with code",
 			command: runCmd(func(args string) error {
 				return cmd.setBreakPoint(lineBreakPoint, args)
 			}),
 		},
 		{
-			name:        "addresses",
-			description: " [-h] <address>+ - set addresses break point",
+			name: "addresses",
+			description: " [-h] <address>+ [if <condition>]\n" +
+				"    - set addresses break point",
 			command: runCmd(func(args string) error {
 				return cmd.setBreakPoint(addressesBreakPoint, args)
 			}),
@@ -52,11 +55,22 @@ func (cmd stopPointCommands) SubCommands() subCommands {
 	var setCmd command
 	setDesc := ""
 	if cmd.stopPoints.IsWatchPoints() {
-		setDesc = " <address> <mode=w|rw|e> <size=1|2|4|8>\n" +
-			"    - create watch point"
+		setDesc = " [-scope] <address> <mode=w|rw|e> <size=1|2|4|8> " +
+			"[if <condition>]\n" +
+			"    - create watch point.  When a condition is specified, the watch\n" +
+			"      point only stops when the condition (<expr> or <expr> <op> <expr>\n" +
+			"      where op is one of ==, !=, <, <=, >, >=) is satisfied.  -scope\n" +
+			"      scopes the watch point to the inspect frame (see set\n" +
+			"      watchpoint-scope)"
 		setCmd = runCmd(cmd.setWatchPoint)
 	} else {
-		setDesc = "                      - subcommands for setting break points"
+		setDesc = "                      - subcommands for setting break points.\n" +
+			"    -h uses a debug register (triggered on instruction fetch) instead\n" +
+			"    of patching the code with int3 (at most 4, shared with watch\n" +
+			"    points).  When a condition is specified, the break point only\n" +
+			"    stops when the condition (see watchpoint set) is satisfied.  The\n" +
+			"    condition may call functions (see set condition-calls), which is\n" +
+			"    costly since the functions are invoked on every hit"
 		setCmd = cmd.setBreakpointSubCommands()
 	}
 
@@ -114,9 +128,26 @@ func (cmd stopPointCommands) list(args string) error {
 			point.Type(),
 			point.IsEnabled())
 		fmt.Printf("     resolver: %s\n", point.Resolver())
+		scope, ok := cmd.debugger.WatchPointScope(point.Id())
+		if ok {
+			fmt.Printf("     scope: %s\n", scope)
+		}
+		if point.Condition() != "" {
+			fmt.Printf("     condition: %s\n", point.Condition())
+		}
+		if point.IgnoreCount() > 0 {
+			fmt.Printf("     ignore next %d hits\n", point.IgnoreCount())
+		}
 		fmt.Println("     resolved sites:")
 		for idx, site := range point.Sites() {
 			fmt.Printf("       %d. %s\n", idx, site.Key())
+			if !point.Type().IsWatchPoint {
+				signature, err := cmd.debugger.FunctionSignatureAt(site.Address())
+				if err == nil && signature != "" {
+					fmt.Printf("          function: %s\n", signature)
+				}
+			}
+			fmt.Printf("          mechanism: %s\n", site.Mechanism())
 			fmt.Printf(
 				"          enabled = %v (ref count = %d)\n",
 				site.IsEnabled(),
@@ -127,6 +158,18 @@ func (cmd stopPointCommands) list(args string) error {
 	return nil
 }
 
+func (cmd stopPointCommands) printOverlappingPoints(point *stoppoint.StopPoint) {
+	for _, other := range point.OverlappingPoints() {
+		fmt.Printf(
+			"Note: %s (id=%d) shares sites with %s (id=%d, resolver: %s)\n",
+			cmd.name(),
+			point.Id(),
+			other.Type(),
+			other.Id(),
+			other.Resolver())
+	}
+}
+
 func (cmd stopPointCommands) parseAddressesBreakPoint(
 	argsStr string,
 ) (
@@ -172,8 +215,15 @@ func (cmd stopPointCommands) parseLineBreakPoint(
 	args := splitAllArgs(argsStr)
 
 	siteType := stoppoint.NewBreakSiteType(false)
-	if len(args) > 0 && args[0] == "-h" {
-		siteType.IsHardware = true
+	autoAdvance := false
+	for len(args) > 0 {
+		if args[0] == "-h" {
+			siteType.IsHardware = true
+		} else if args[0] == "-a" {
+			autoAdvance = true
+		} else {
+			break
+		}
 		args = args[1:]
 	}
 
@@ -189,6 +239,11 @@ func (cmd stopPointCommands) parseLineBreakPoint(
 			"failed to set break point. invalid line: %w", err)
 	}
 
+	if autoAdvance {
+		return cmd.debugger.NewAutoAdvanceLineResolver(path, int(line)),
+			siteType,
+			nil
+	}
 	return cmd.debugger.NewLineResolver(path, int(line)), siteType, nil
 }
 
@@ -216,6 +271,13 @@ func (cmd stopPointCommands) parseFunctionBreakPoint(
 }
 
 func (cmd stopPointCommands) setBreakPoint(kind int, args string) error {
+	args, condition, hasCondition := strings.Cut(args, " if ")
+	condition = strings.TrimSpace(condition)
+	if hasCondition && condition == "" {
+		fmt.Println("failed to set break point. empty condition")
+		return nil
+	}
+
 	var resolver stoppoint.StopSiteResolver
 	var siteType stoppoint.StopSiteType
 	var err error
@@ -235,7 +297,13 @@ func (cmd stopPointCommands) setBreakPoint(kind int, args string) error {
 		return nil
 	}
 
-	_, err = cmd.stopPoints.Set(resolver, siteType, true)
+	point, err := cmd.stopPoints.SetWithOptions(
+		resolver,
+		siteType,
+		true,
+		stoppoint.StopPointOptions{
+			Condition: condition,
+		})
 	if err != nil {
 		if errors.Is(err, ErrInvalidInput) {
 			fmt.Println(err)
@@ -244,6 +312,27 @@ func (cmd stopPointCommands) setBreakPoint(kind int, args string) error {
 		return err
 	}
 
+	lineResolver, ok := resolver.(*stoppoint.LineStopSiteResolver)
+	if ok {
+		if lineResolver.IsAdvanced() {
+			fmt.Printf(
+				"No code at %s:%d. %s (id=%d) moved to line %d\n",
+				lineResolver.Path,
+				lineResolver.Line,
+				cmd.name(),
+				point.Id(),
+				lineResolver.ResolvedLine)
+		} else if len(point.Sites()) == 0 {
+			fmt.Printf(
+				"Warning: no code at %s:%d. %s (id=%d) is pending\n",
+				lineResolver.Path,
+				lineResolver.Line,
+				cmd.name(),
+				point.Id())
+		}
+	}
+
+	cmd.printOverlappingPoints(point)
 	return nil
 }
 
@@ -294,13 +383,42 @@ func (cmd stopPointCommands) parseWatchPoint(
 }
 
 func (cmd stopPointCommands) setWatchPoint(args string) error {
+	args, condition, hasCondition := strings.Cut(args, " if ")
+	condition = strings.TrimSpace(condition)
+	if hasCondition && condition == "" {
+		fmt.Println("failed to set watch point. empty condition")
+		return nil
+	}
+
+	isScoped := false
+	flag, remaining := splitArg(args)
+	if flag == "-scope" {
+		isScoped = true
+		args = remaining
+	}
+
 	resolver, siteType, err := cmd.parseWatchPoint(args)
 	if err != nil {
 		fmt.Println(err)
 		return nil
 	}
 
-	_, err = cmd.stopPoints.Set(resolver, siteType, true)
+	var point *stoppoint.StopPoint
+	if isScoped {
+		point, err = cmd.debugger.SetScopedWatchPoint(
+			resolver,
+			siteType,
+			true,
+			condition)
+	} else {
+		point, err = cmd.stopPoints.SetWithOptions(
+			resolver,
+			siteType,
+			true,
+			stoppoint.StopPointOptions{
+				Condition: condition,
+			})
+	}
 	if err != nil {
 		if errors.Is(err, ErrInvalidInput) {
 			fmt.Println(err)
@@ -309,6 +427,7 @@ func (cmd stopPointCommands) setWatchPoint(args string) error {
 		return err
 	}
 
+	cmd.printOverlappingPoints(point)
 	return nil
 }
 
@@ -418,3 +537,63 @@ func (cmd stopPointCommands) disable(args string) error {
 
 	return sites[siteIdx].Disable()
 }
+
+// Lists the stop points, followed by the number of available debug registers
+// (shared by hardware break points and watch points).
+func (cmd stopPointCommands) info(args string) error {
+	err := cmd.list(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(
+		"Available hardware stop sites (debug registers): %d of %d\n",
+		cmd.debugger.AvailableHardwareStopSites(),
+		stoppoint.NumHardwareStopSites)
+	return nil
+}
+
+func printWatchPointScopeExit(exit debugger.WatchPointScopeExit) {
+	fmt.Println("Note:", exit)
+}
+
+func setWatchPointScopeMode(db *debugger.Debugger, args string) error {
+	mode := debugger.WatchPointScopeMode(strings.TrimSpace(args))
+	if mode == "" {
+		fmt.Println("watch point scope mode (auto/manual) not specified")
+		return nil
+	}
+
+	for _, valid := range debugger.WatchPointScopeModes {
+		if mode == valid {
+			db.WatchPointScopeMode = mode
+			fmt.Println("watch point scope mode set to", mode)
+			return nil
+		}
+	}
+
+	fmt.Println("invalid watch point scope mode:", mode)
+	return nil
+}
+
+func setConditionCalls(db *debugger.Debugger, args string) error {
+	switch strings.TrimSpace(args) {
+	case "":
+		if db.DisableAllBreakPointsInConditionCalls {
+			fmt.Println("condition calls: all")
+		} else {
+			fmt.Println("condition calls: own")
+		}
+	case "own":
+		db.DisableAllBreakPointsInConditionCalls = false
+		fmt.Println(
+			"only the triggered stop point is disabled during condition calls")
+	case "all":
+		db.DisableAllBreakPointsInConditionCalls = true
+		fmt.Println("all break points are disabled during condition calls")
+	default:
+		fmt.Println("invalid condition calls mode:", strings.TrimSpace(args))
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// Transcript line prefixes.  Replay (see -x) only extracts the command
+	// lines.
+	transcriptCommandPrefix = "bad > "
+	transcriptOutputPrefix  = "| "
+
+	// Control markers written through the output pipe.  The command marker
+	// keeps the command echo ordered relative to the previous command's
+	// output.  The flush marker is acknowledged once all prior output has been
+	// relayed.
+	transcriptCommandMarker = "\x00command:"
+	transcriptFlushMarker   = "\x00flush"
+)
+
+// transcript records every entered command and the debugger's output into a
+// file while logging is on.  The debugger's stdout is redirected to a pipe,
+// which is relayed to both the original stdout and the file.  Note that the
+// inferior's output is relayed directly to the original stdout (see
+// outputMonitor), and is not recorded.
+type transcript struct {
+	path string
+
+	stdout  *os.File // the original stdout. nil when logging is off
+	file    *os.File
+	writer  *os.File
+	flushed chan struct{}
+	done    chan struct{}
+}
+
+func (log *transcript) isOn() bool {
+	return log.stdout != nil
+}
+
+// Returns the original stdout, i.e., not the transcript's pipe.
+func (log *transcript) originalStdout() *os.File {
+	if log.isOn() {
+		return log.stdout
+	}
+	return os.Stdout
+}
+
+func (log *transcript) start(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	log.path = path
+	log.stdout = os.Stdout
+	log.file = file
+	log.writer = writer
+	log.flushed = make(chan struct{})
+	log.done = make(chan struct{})
+
+	os.Stdout = writer
+
+	go log.relay(reader)
+	return nil
+}
+
+func (log *transcript) stop() error {
+	if !log.isOn() {
+		return nil
+	}
+
+	os.Stdout = log.stdout
+	log.stdout = nil
+
+	_ = log.writer.Close()
+	<-log.done
+
+	return log.file.Close()
+}
+
+func (log *transcript) relay(reader *os.File) {
+	defer close(log.done)
+	defer reader.Close()
+
+	stdout := log.stdout
+	buffered := bufio.NewReader(reader)
+	for {
+		line, err := buffered.ReadString('\n')
+
+		// NOTE: a control marker may follow a partial output line (i.e., output
+		// without a trailing newline).
+		output, control, hasControl := strings.Cut(line, "\x00")
+		if output != "" {
+			_, _ = stdout.WriteString(output)
+
+			if !strings.HasSuffix(output, "\n") {
+				output += "\n"
+			}
+			_, _ = log.file.WriteString(transcriptOutputPrefix + output)
+		}
+
+		if hasControl {
+			control = "\x00" + control
+			command, isCommand := strings.CutPrefix(control, transcriptCommandMarker)
+			if isCommand {
+				_, _ = log.file.WriteString(transcriptCommandPrefix + command)
+			} else if strings.HasPrefix(control, transcriptFlushMarker) {
+				log.flushed <- struct{}{}
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Records the command line.  This must be called before the command runs.
+func (log *transcript) recordCommand(line string) {
+	if !log.isOn() {
+		return
+	}
+
+	_, _ = fmt.Fprintln(log.writer, transcriptCommandMarker+line)
+}
+
+// Waits until all prior output has been relayed.
+func (log *transcript) flush() {
+	if !log.isOn() {
+		return
+	}
+
+	_, _ = fmt.Fprintln(log.writer, transcriptFlushMarker)
+	<-log.flushed
+}
+
+func (log *transcript) setLogging(args string) error {
+	mode, path := splitArg(args)
+	path = strings.TrimSpace(path)
+
+	switch mode {
+	case "on":
+		if path == "" {
+			fmt.Println("logging transcript file not specified")
+			return nil
+		}
+
+		if log.isOn() {
+			fmt.Println("already logging to", log.path)
+			return nil
+		}
+
+		err := log.start(path)
+		if err != nil {
+			fmt.Println("failed to start logging:", err)
+			return nil
+		}
+
+		fmt.Println("logging commands and output to", path)
+	case "off":
+		if !log.isOn() {
+			fmt.Println("logging is not on")
+			return nil
+		}
+
+		// NOTE: print before stopping so that the message is recorded.
+		fmt.Println("stopped logging to", log.path)
+
+		err := log.stop()
+		if err != nil {
+			return fmt.Errorf("failed to stop logging: %w", err)
+		}
+	case "":
+		fmt.Println("logging mode (on <file>/off) not specified")
+	default:
+		fmt.Println("invalid logging mode:", mode)
+	}
+
+	return nil
+}
+
+// Returns the commands in the script.  When the script is a transcript
+// (recorded by set logging), only the command lines are returned.  Otherwise,
+// every non-empty line (excluding # comments) is a command.
+func readCommandScript(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	isTranscript := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, transcriptCommandPrefix) {
+			isTranscript = true
+			break
+		}
+	}
+
+	commands := []string{}
+	for _, line := range lines {
+		if isTranscript {
+			command, ok := strings.CutPrefix(line, transcriptCommandPrefix)
+			if !ok {
+				continue
+			}
+			line = command
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		commands = append(commands, line)
+	}
+
+	return commands, nil
+}
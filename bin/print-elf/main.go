@@ -53,7 +53,7 @@ func main() {
 				fmt.Printf(
 					"    %d: Name = %s Type = %d Description length = %d\n",
 					noteIdx,
-					entry.Name,
+					entry.OwnerName(),
 					entry.Type,
 					len(entry.Description))
 			}
@@ -64,4 +64,25 @@ func main() {
 	for headerIdx, header := range file.ProgramHeaders {
 		fmt.Printf("  [%d] %v\n", headerIdx, header)
 	}
+
+	fmt.Println("Build info:")
+	buildId := file.BuildId()
+	if buildId != "" {
+		fmt.Println("  Build id:", buildId)
+	}
+
+	abiTag, err := file.ABITag()
+	if err != nil {
+		fmt.Println("  ABI tag: <invalid:", err, ">")
+	} else if abiTag != nil {
+		fmt.Println("  ABI tag:", abiTag)
+	}
+
+	comments, err := file.Comments()
+	if err != nil {
+		fmt.Println("  Comments: <invalid:", err, ">")
+	}
+	for _, comment := range comments {
+		fmt.Println("  Comment:", comment)
+	}
 }
@@ -0,0 +1,228 @@
+package arch
+
+import (
+	"bytes"
+	"fmt"
+
+	"golang.org/x/arch/x86/x86asm"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/memory"
+)
+
+const (
+	maxX64InstructionLength = 15
+)
+
+var (
+	AMD64 Arch = amd64{}
+
+	endbr64 = []byte{0xf3, 0x0f, 0x01e, 0xfa}
+	endbr32 = []byte{0xf3, 0x0f, 0x01e, 0xfb}
+
+	int3Instruction = []byte{0xcc}
+
+	syscallInstruction = []byte{0x0f, 0x05}
+)
+
+type amd64 struct{}
+
+func (amd64) Name() string {
+	return "amd64"
+}
+
+func (amd64) SyscallInstruction() []byte {
+	return syscallInstruction
+}
+
+func (amd64) SoftwareBreakInstruction() []byte {
+	return int3Instruction
+}
+
+// The int3 trap reports the pc after the int3 instruction.
+func (amd64) SoftwareBreakSiteAddress(pc VirtualAddress) VirtualAddress {
+	return pc - VirtualAddress(len(int3Instruction))
+}
+
+func (amd64) MaxInstructionLength() int {
+	return maxX64InstructionLength
+}
+
+func (amd64) DecodeInstruction(data []byte) (memory.Instruction, error) {
+	if len(data) >= len(endbr64) && bytes.Equal(data[:len(endbr64)], endbr64) {
+		return amd64Instruction{IsEndbr64: true}, nil
+	} else if len(data) >= len(endbr32) &&
+		bytes.Equal(data[:len(endbr32)], endbr32) {
+
+		return amd64Instruction{IsEndbr32: true}, nil
+	}
+
+	inst, err := x86asm.Decode(data, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return amd64Instruction{Inst: inst}, nil
+}
+
+type amd64Instruction struct {
+	IsEndbr64 bool
+	IsEndbr32 bool
+
+	x86asm.Inst
+}
+
+func (inst amd64Instruction) Length() int {
+	if inst.IsEndbr64 {
+		return len(endbr64)
+	} else if inst.IsEndbr32 {
+		return len(endbr32)
+	}
+
+	return inst.Len
+}
+
+func (inst amd64Instruction) IsCall() bool {
+	return !inst.IsEndbr64 && !inst.IsEndbr32 && inst.Op == x86asm.CALL
+}
+
+func (inst amd64Instruction) IsBranch() bool {
+	if inst.IsEndbr64 || inst.IsEndbr32 {
+		return false
+	}
+
+	switch inst.Op {
+	case x86asm.CALL, x86asm.JMP, x86asm.LCALL, x86asm.LJMP,
+		x86asm.RET, x86asm.LRET, x86asm.IRET, x86asm.IRETD, x86asm.IRETQ,
+		x86asm.JA, x86asm.JAE, x86asm.JB, x86asm.JBE, x86asm.JCXZ, x86asm.JE,
+		x86asm.JECXZ, x86asm.JG, x86asm.JGE, x86asm.JL, x86asm.JLE, x86asm.JNE,
+		x86asm.JNO, x86asm.JNP, x86asm.JNS, x86asm.JO, x86asm.JP, x86asm.JRCXZ,
+		x86asm.JS, x86asm.LOOP, x86asm.LOOPE, x86asm.LOOPNE,
+		x86asm.SYSCALL, x86asm.SYSENTER, x86asm.SYSEXIT, x86asm.SYSRET,
+		x86asm.INT, x86asm.INTO, x86asm.UD1, x86asm.UD2, x86asm.HLT:
+
+		return true
+	}
+
+	return false
+}
+
+func (inst amd64Instruction) Format(address VirtualAddress) string {
+	if inst.IsEndbr64 {
+		return "endbr64"
+	} else if inst.IsEndbr32 {
+		return "endbr32"
+	}
+
+	return x86asm.GNUSyntax(inst.Inst, uint64(address), nil)
+}
+
+// System V AMD64 ABI parameter / return value assignment.
+func (amd64) AssignStackAndRegisters(
+	signature *expression.SignatureDescriptor,
+) error {
+	paramIntRegs := []string{
+		"rdi",
+		"rsi",
+		"rdx",
+		"rcx",
+		"r8",
+		"r9",
+	}
+	paramSSERegs := []string{
+		"xmm0",
+		"xmm1",
+		"xmm2",
+		"xmm3",
+		"xmm4",
+		"xmm5",
+		"xmm6",
+		"xmm7",
+	}
+
+	classes, err := signature.Return.ParameterClasses()
+	if err != nil {
+		return fmt.Errorf("return value: %w", err)
+	}
+
+	if len(classes) == 1 && classes[0] == expression.MemoryClass {
+		signature.ReturnInMemory = true
+		paramIntRegs = paramIntRegs[1:] // return value's address is in rdi
+	} else {
+		retIntRegs := []string{"rax", "rdx"}
+		retSSERegs := []string{"xmm0", "xmm1"}
+
+		for _, class := range classes {
+			if class == expression.IntegerClass {
+				signature.ReturnOnRegisters = append(
+					signature.ReturnOnRegisters,
+					retIntRegs[0])
+				retIntRegs = retIntRegs[1:]
+			} else if class == expression.SSEClass {
+				signature.ReturnOnRegisters = append(
+					signature.ReturnOnRegisters,
+					retSSERegs[0])
+				retSSERegs = retSSERegs[1:]
+			} else {
+				panic("should never happen")
+			}
+		}
+	}
+
+	maybeAllocateRegisters := func(classes []expression.ParameterClass) []string {
+		if len(classes) == 1 && classes[0] == expression.MemoryClass {
+			return nil
+		}
+
+		numInts := 0
+		numSSEs := 0
+		for _, class := range classes {
+			if class == expression.IntegerClass {
+				numInts += 1
+			} else if class == expression.SSEClass {
+				numSSEs += 1
+			} else {
+				panic("should never happen")
+			}
+		}
+
+		if len(paramIntRegs) < numInts || len(paramSSERegs) < numSSEs {
+			return nil
+		}
+
+		regs := []string{}
+		for _, class := range classes {
+			if class == expression.IntegerClass {
+				regs = append(regs, paramIntRegs[0])
+				paramIntRegs = paramIntRegs[1:]
+			} else {
+				regs = append(regs, paramSSERegs[0])
+				paramSSERegs = paramSSERegs[1:]
+			}
+		}
+
+		return regs
+	}
+
+	paramStackSize := 0
+	for idx, param := range signature.Parameters {
+		classes, err := param.ParameterClasses()
+		if err != nil {
+			return fmt.Errorf("parameter %d: %w", idx, err)
+		}
+
+		registers := maybeAllocateRegisters(classes)
+		if len(registers) == 0 { // in memory
+			param.StackOffset = uint64(8 + paramStackSize)
+			paramStackSize += param.ByteSize
+		} else {
+			param.Registers = registers
+		}
+	}
+
+	signature.ParameterStackSize = uint64(paramStackSize)
+	signature.NumSSERegistersUsed = uint64(8 - len(paramSSERegs))
+
+	return nil
+}
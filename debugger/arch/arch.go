@@ -0,0 +1,21 @@
+package arch
+
+import (
+	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+// Arch captures the architecture specific aspects of the debugger: calling
+// convention, syscall / software breakpoint trap instructions, and
+// instruction decoding.
+type Arch interface {
+	Name() string
+
+	// The instruction used for injecting syscalls into the process.
+	SyscallInstruction() []byte
+
+	memory.InstructionDecoder
+	expression.CallingConvention
+	stoppoint.SoftwareBreakInstruction
+}
@@ -3,7 +3,8 @@ package debugger
 import (
 	"encoding/binary"
 	"fmt"
-	"sort"
+
+	"github.com/ianlancetaylor/demangle"
 
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/expression"
@@ -11,6 +12,20 @@ import (
 	"github.com/pattyshack/bad/debugger/memory"
 	"github.com/pattyshack/bad/debugger/registers"
 	"github.com/pattyshack/bad/dwarf"
+	"github.com/pattyshack/bad/elf"
+)
+
+// Describes how a frame's register state (and thus its program counter) was
+// recovered.
+type UnwindSource string
+
+const (
+	// The frame's register state is the thread's current register state.
+	UnwindFromRegisters = UnwindSource("registers")
+
+	// The frame's register state is restored using the callee frame's call
+	// frame information.
+	UnwindFromCFI = UnwindSource("cfi")
 )
 
 type CallFrame struct {
@@ -37,10 +52,27 @@ type CallFrame struct {
 	// the registers have if the call frame above it returned immediately.
 	Registers registers.State
 
+	stack  *CallStack
 	memory *memory.VirtualMemory
 
 	// NOTE: canonical frame address is only populated in the base frame.
 	cfa registers.Value
+
+	// NOTE: the following unwind provenance fields are only populated in the
+	// base frame.
+
+	UnwindSource UnwindSource
+
+	// The (unadjusted) return address recovered by unwinding this frame.  This
+	// is zero when the caller is unknown (e.g., outermost frame).
+	ReturnAddress VirtualAddress
+
+	// Non-empty when the frame is likely unreliable.
+	UnwindWarning string
+
+	// The caller's inner most (possibly inlined) frame.  nil when the caller
+	// is unknown.  This is used for recovering entry values.
+	caller *CallFrame
 }
 
 func (frame *CallFrame) IsInlined() bool {
@@ -116,6 +148,128 @@ func (frame *CallFrame) CanonicalFrameAddress() (uint64, error) {
 	return cfa.ToUint64(), nil
 }
 
+// The entry value is recovered from the caller's call site parameter whose
+// location matches the register (DW_AT_call_value is evaluated in the
+// caller's frame context).
+func (frame *CallFrame) EntryRegisterValue(
+	id dwarf.RegisterId,
+) (
+	uint64,
+	error,
+) {
+	base := frame
+	if frame.BaseFrame != nil {
+		base = frame.BaseFrame
+	}
+
+	caller := base.caller
+	if caller == nil || base.ReturnAddress == 0 {
+		return 0, fmt.Errorf("caller frame unavailable")
+	}
+
+	callSite, err := caller.findCallSite(base.ReturnAddress)
+	if err != nil {
+		return 0, err
+	}
+	if callSite == nil {
+		return 0, fmt.Errorf(
+			"call site (return address %s) not found in %s",
+			base.ReturnAddress,
+			caller.Name)
+	}
+
+	for _, param := range callSite.Children {
+		if param.Tag != dwarf.DW_TAG_call_site_parameter &&
+			param.Tag != dwarf.DW_TAG_GNU_call_site_parameter {
+			continue
+		}
+
+		location, ok := param.Bytes(dwarf.DW_AT_location)
+		if !ok {
+			continue
+		}
+
+		regId, ok := dwarf.SingleRegisterExpression(caller.ByteOrder(), location)
+		if !ok || regId != id {
+			continue
+		}
+
+		value, ok := param.Bytes(dwarf.DW_AT_call_value)
+		if !ok {
+			value, ok = param.Bytes(dwarf.DW_AT_GNU_call_site_value)
+			if !ok {
+				break
+			}
+		}
+
+		result, err := dwarf.EvaluateExpression(caller, false, value, false)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(result) != 1 {
+			return 0, fmt.Errorf("unsupported call site value location")
+		}
+
+		switch result[0].Kind {
+		case dwarf.AddressLocation, dwarf.ImplicitLiteralLocation:
+			return result[0].Value, nil
+		case dwarf.RegisterLocation:
+			return caller.RegisterValue(dwarf.RegisterId(result[0].Value))
+		default:
+			return 0, fmt.Errorf(
+				"unsupported call site value location kind (%s)",
+				result[0].Kind)
+		}
+	}
+
+	return 0, fmt.Errorf(
+		"call site value for register (%d) not found in %s",
+		id,
+		caller.Name)
+}
+
+// Returns the function's call site entry whose return address matches.
+func (frame *CallFrame) findCallSite(
+	returnAddress VirtualAddress,
+) (
+	*dwarf.DebugInfoEntry,
+	error,
+) {
+	var result *dwarf.DebugInfoEntry
+	err := frame.BaseFrameFunctionEntry().Visit(
+		func(entry *dwarf.DebugInfoEntry) error {
+			if result != nil {
+				return dwarf.ErrSkipVisitingChildren
+			}
+
+			var address elf.FileAddress
+			var ok bool
+			switch entry.Tag {
+			case dwarf.DW_TAG_call_site:
+				address, ok = entry.Address(dwarf.DW_AT_call_return_pc)
+			case dwarf.DW_TAG_GNU_call_site:
+				// NOTE: the gnu extension's low pc is the return address.
+				address, ok = entry.Address(dwarf.DW_AT_low_pc)
+			default:
+				return nil
+			}
+
+			if ok &&
+				VirtualAddress(uint64(address)+frame.LoadBias()) == returnAddress {
+
+				result = entry
+			}
+			return dwarf.ErrSkipVisitingChildren
+		},
+		nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (frame *CallFrame) readLocationData(
 	location dwarf.Location,
 	byteSize int,
@@ -195,6 +349,14 @@ func newCallStack(thread *ThreadState) *CallStack {
 	}
 }
 
+// Discards the frames (e.g., after the process exited).
+func (stack *CallStack) reset() {
+	stack.currentPC = 0
+	stack.executingFrame = 0
+	stack.currentInspectFrame = 0
+	stack.frames = nil
+}
+
 func (stack *CallStack) InspectCalleeFrame() {
 	if stack.currentInspectFrame > stack.executingFrame {
 		stack.currentInspectFrame -= 1
@@ -207,6 +369,28 @@ func (stack *CallStack) InspectCallerFrame() {
 	}
 }
 
+// Inspect the idx-th frame of the executing stack (0 is the executing
+// frame).
+func (stack *CallStack) InspectFrame(idx int) error {
+	numFrames := len(stack.frames) - stack.executingFrame
+	if idx < 0 || idx >= numFrames {
+		return fmt.Errorf(
+			"%w. frame %d out of bound [0, %d)",
+			ErrInvalidInput,
+			idx,
+			numFrames)
+	}
+
+	stack.currentInspectFrame = stack.executingFrame + idx
+	return nil
+}
+
+// Returns the inspect frame's index relative to the executing stack (i.e.,
+// the index accepted by InspectFrame).
+func (stack *CallStack) InspectFrameIndex() int {
+	return stack.currentInspectFrame - stack.executingFrame
+}
+
 func (stack *CallStack) CurrentInspectFrame() *CallFrame {
 	if len(stack.frames) > 0 {
 		return stack.frames[stack.currentInspectFrame]
@@ -250,15 +434,36 @@ func (stack *CallStack) ListInspectFrameLocalVariables() (
 		return nil, fmt.Errorf("call stack frame unavailable")
 	}
 
-	entries, err := stack.LoadedElves.LocalVariableEntries(
+	return frame.LocalVariables()
+}
+
+// Returns the variables (including arguments) which are in scope at the
+// frame's program counter, inner most block's variables first.  Shadowed
+// variables are excluded.  Variables which are not in memory (e.g., register
+// variables) are copied into memory allocated in the inferior.
+//
+// The variables are evaluated in this frame's register / cfa context,
+// independent of the inspect frame.  Note that expressions (e.g.,
+// Debugger.EvaluateExpression) are always evaluated in the current thread's
+// inspect frame; use InspectFrame to select the frame before evaluating
+// expressions which reference the frame's variables.
+//
+// The frame (and its values) is only valid until the thread resumes.
+func (frame *CallFrame) LocalVariables() ([]*expression.TypedData, error) {
+	entries, err := frame.stack.LoadedElves.LocalVariableEntries(
 		frame.Registers.ProgramCounter())
 	if err != nil {
 		return nil, err
 	}
 
 	result := []*expression.TypedData{}
-	for name, entry := range entries {
-		variable, err := stack.readVariable(frame, name, entry)
+	for _, entry := range entries {
+		name, _, err := entry.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		variable, err := frame.stack.readVariable(frame, name, entry)
 		if err != nil {
 			return nil, err
 		}
@@ -266,14 +471,177 @@ func (stack *CallStack) ListInspectFrameLocalVariables() (
 		result = append(result, variable)
 	}
 
-	sort.Slice(
-		result,
-		func(i int, j int) bool {
-			return result[i].FormatPrefix < result[j].FormatPrefix
-		})
 	return result, nil
 }
 
+// Returns the frame's function arguments, evaluated in this frame's
+// context (see LocalVariables and CallStack.FrameArguments).
+func (frame *CallFrame) Arguments() ([]FrameArgument, error) {
+	return frame.stack.FrameArguments(frame)
+}
+
+// Controls how frame argument values are rendered.
+type FrameArgumentsMode string
+
+const (
+	// Print all argument values, including the aggregates' fields / elements.
+	FrameArgumentsAll = FrameArgumentsMode("all")
+
+	// Print scalar argument values.  Aggregates are abbreviated to "...".
+	FrameArgumentsScalars = FrameArgumentsMode("scalars")
+
+	// Only print argument names.  All values are abbreviated to "...".
+	FrameArgumentsNone = FrameArgumentsMode("none")
+)
+
+var FrameArgumentsModes = []FrameArgumentsMode{
+	FrameArgumentsScalars,
+	FrameArgumentsAll,
+	FrameArgumentsNone,
+}
+
+type FrameArgument struct {
+	Name string
+
+	// nil if the argument's value is unreadable.  See also
+	// expression.TypedData.Unavailable
+	Value *expression.TypedData
+}
+
+func (arg FrameArgument) String() string {
+	return arg.Format(FrameArgumentsScalars)
+}
+
+func (arg FrameArgument) Format(mode FrameArgumentsMode) string {
+	if mode == FrameArgumentsNone {
+		return arg.Name + "=..."
+	}
+
+	if arg.Value == nil {
+		return arg.Name + "=<optimized out>"
+	}
+
+	if mode == FrameArgumentsAll {
+		return arg.Name + "=" + arg.Value.FormatExpandedValue()
+	}
+
+	return arg.Name + "=" + arg.Value.FormatValue()
+}
+
+// Returns the frame's function arguments, evaluated using the frame's
+// register / cfa context.  Unlike readVariable, this does not allocate
+// memory in the inferior for arguments that are not in memory.
+func (stack *CallStack) FrameArguments(
+	frame *CallFrame,
+) (
+	[]FrameArgument,
+	error,
+) {
+	if frame.DebugInfoEntry == nil {
+		return nil, nil
+	}
+
+	baseFrame := frame
+	if frame.BaseFrame != nil {
+		baseFrame = frame.BaseFrame
+	}
+
+	result := []FrameArgument{}
+	for _, param := range frame.DebugInfoEntry.Children {
+		if param.Tag != dwarf.DW_TAG_formal_parameter {
+			continue
+		}
+
+		name, _, err := param.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := stack.readFrameArgument(baseFrame, name, param)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(
+			result,
+			FrameArgument{
+				Name:  name,
+				Value: value,
+			})
+	}
+
+	return result, nil
+}
+
+// This returns nil if the argument's value is unreadable.
+func (stack *CallStack) readFrameArgument(
+	baseFrame *CallFrame,
+	name string,
+	param *dwarf.DebugInfoEntry,
+) (
+	*expression.TypedData,
+	error,
+) {
+	typeDie, err := param.TypeEntry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get type info for %s: %w", name, err)
+	}
+
+	descriptor, err := stack.descriptorPool.GetVariableDescriptor(typeDie)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get descriptor for %s: %w", name, err)
+	}
+
+	location, err := param.EvaluateLocation(
+		dwarf.DW_AT_location,
+		baseFrame,
+		false, // in frame info
+		false) // push cfa
+	if err != nil {
+		return nil, nil
+	}
+
+	unavailable := locationAvailability(param, location)
+	if unavailable != expression.Available {
+		return &expression.TypedData{
+			VirtualMemory:  stack.VirtualMemory,
+			FormatPrefix:   name,
+			DataDescriptor: descriptor,
+			BitSize:        8 * descriptor.ByteSize,
+			Location:       location,
+			Unavailable:    unavailable,
+		}, nil
+	}
+
+	if len(location) == 1 && location[0].Kind == dwarf.AddressLocation {
+		return &expression.TypedData{
+			VirtualMemory:  stack.VirtualMemory,
+			FormatPrefix:   name,
+			DataDescriptor: descriptor,
+			Address:        VirtualAddress(location[0].Value),
+			BitOffset:      0,
+			BitSize:        8 * descriptor.ByteSize,
+			Location:       location,
+		}, nil
+	}
+
+	if !descriptor.IsSimpleValue() {
+		return nil, nil
+	}
+
+	data, err := baseFrame.readLocationData(location, descriptor.ByteSize)
+	if err != nil {
+		return nil, nil
+	}
+
+	value, err := descriptor.NewImplicitData(name, data)
+	if err != nil {
+		return nil, nil
+	}
+
+	return value, nil
+}
+
 func (stack *CallStack) ReadInspectFrameVariableOrFunction(
 	name string,
 ) (
@@ -292,7 +660,9 @@ func (stack *CallStack) ReadInspectFrameVariableOrFunction(
 		return nil, err
 	}
 	if variable != nil {
-		return stack.readVariable(frame, name, variable)
+		// NOTE: variable referenced by linkage name is shown with its
+		// demangled name.
+		return stack.readVariable(frame, demangle.Filter(name), variable)
 	}
 
 	functionData, err := stack.descriptorPool.GetFunction(name)
@@ -301,12 +671,187 @@ func (stack *CallStack) ReadInspectFrameVariableOrFunction(
 	}
 
 	if functionData == nil {
-		return nil, fmt.Errorf("%w. variable %s not found", ErrInvalidInput, name)
+		return nil, fmt.Errorf("%w (%s)", ErrVariableNotFound, name)
 	}
 
 	return functionData, err
 }
 
+// Write the data to the variable's (dwarf evaluated) location.  Data without
+// dwarf location information (e.g., fields / elements) are written to memory.
+func (stack *CallStack) WriteInspectFrameVariable(
+	variable *expression.TypedData,
+	data []byte,
+) error {
+	if variable.Unavailable != expression.Available {
+		return fmt.Errorf(
+			"%w. cannot write to variable (%s)",
+			ErrInvalidInput,
+			variable.Unavailable)
+	}
+
+	if variable.ImplicitValue != nil {
+		return fmt.Errorf(
+			"%w. cannot write to implicit value",
+			ErrInvalidInput)
+	}
+
+	if len(data)*8 < variable.BitSize {
+		panic("should never happen")
+	}
+
+	location := variable.Location
+	if len(location) == 0 {
+		return stack.writeMemoryBits(
+			variable.Address,
+			variable.BitOffset,
+			data,
+			0,
+			variable.BitSize)
+	}
+
+	for _, chunk := range location {
+		switch chunk.Kind {
+		case dwarf.AddressLocation:
+		case dwarf.RegisterLocation:
+			if stack.currentInspectFrame != stack.executingFrame {
+				return fmt.Errorf(
+					"%w. cannot write register-resident variable in "+
+						"non-current backtraced frame",
+					ErrInvalidInput)
+			}
+		default:
+			return fmt.Errorf(
+				"%w. cannot write to %s location",
+				ErrInvalidInput,
+				chunk.Kind)
+		}
+	}
+
+	var state registers.State
+	modifiedRegisters := false
+	if stack.currentInspectFrame == stack.executingFrame {
+		var err error
+		state, err = stack.Registers.GetState()
+		if err != nil {
+			return err
+		}
+	}
+
+	dataBitOffset := 0
+	for _, chunk := range location {
+		remaining := variable.BitSize - dataBitOffset
+		if remaining <= 0 {
+			break
+		}
+
+		bitSize := int(chunk.BitSize)
+		if bitSize == 0 || bitSize > remaining {
+			bitSize = remaining
+		}
+
+		switch chunk.Kind {
+		case dwarf.AddressLocation:
+			err := stack.writeMemoryBits(
+				VirtualAddress(chunk.Value),
+				int(chunk.BitOffset),
+				data,
+				dataBitOffset,
+				bitSize)
+			if err != nil {
+				return err
+			}
+		case dwarf.RegisterLocation:
+			id := dwarf.RegisterId(chunk.Value)
+			spec, ok := registers.ById(id)
+			if !ok {
+				return fmt.Errorf("invalid register id %d", id)
+			}
+
+			value := state.Value(spec)
+			if value == nil {
+				return fmt.Errorf("register (%d) value unavailable", id)
+			}
+
+			regData := value.ToBytes()
+			if int(chunk.BitOffset)+bitSize > 8*len(regData) {
+				return fmt.Errorf(
+					"%w. variable piece does not fit in register %s",
+					ErrInvalidInput,
+					spec.Name)
+			}
+
+			CopyBits(regData, int(chunk.BitOffset), data, dataBitOffset, bitSize)
+
+			var err error
+			state, err = state.WithValue(spec, registers.FromBytes(regData))
+			if err != nil {
+				return err
+			}
+			modifiedRegisters = true
+		default:
+			panic("should never happen")
+		}
+
+		dataBitOffset += bitSize
+	}
+
+	if !modifiedRegisters {
+		return nil
+	}
+
+	err := stack.Registers.SetState(state)
+	if err != nil {
+		return err
+	}
+
+	// The executing frame (and its inlined frames) must reflect the modified
+	// register state.
+	baseFrame := stack.ExecutingFrame()
+	if baseFrame.BaseFrame != nil {
+		baseFrame = baseFrame.BaseFrame
+	}
+
+	for _, frame := range stack.frames {
+		if frame == baseFrame || frame.BaseFrame == baseFrame {
+			frame.Registers = state
+		}
+	}
+
+	return nil
+}
+
+func (stack *CallStack) writeMemoryBits(
+	address VirtualAddress,
+	bitOffset int,
+	data []byte,
+	dataBitOffset int,
+	bitSize int,
+) error {
+	storage := make([]byte, (bitOffset+bitSize+7)/8)
+	if bitOffset != 0 || bitSize%8 != 0 {
+		n, err := stack.VirtualMemory.Read(address, storage)
+		if err != nil {
+			return err
+		}
+		if n != len(storage) {
+			return fmt.Errorf("failed to read all variable data from memory")
+		}
+	}
+
+	CopyBits(storage, bitOffset, data, dataBitOffset, bitSize)
+
+	n, err := stack.VirtualMemory.Write(address, storage)
+	if err != nil {
+		return err
+	}
+	if n != len(storage) {
+		return fmt.Errorf("failed to write all variable data into memory")
+	}
+
+	return nil
+}
+
 func (stack *CallStack) readVariable(
 	frame *CallFrame,
 	name string,
@@ -338,6 +883,40 @@ func (stack *CallStack) readVariable(
 		return nil, fmt.Errorf("failed to get descriptor for %s: %w", name, err)
 	}
 
+	if descriptor.IsVariableLengthArray() {
+		descriptor, err = descriptor.WithDynamicBounds(
+			func(subrange *dwarf.DebugInfoEntry) (int64, error) {
+				return stack.evaluateArrayDimension(frame, subrange)
+			})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+	}
+
+	if variable.SpecIndex(dwarf.DW_AT_location) == -1 {
+		constValue, ok := variable.Any(dwarf.DW_AT_const_value)
+		if ok {
+			content, err := encodeConstValue(constValue, descriptor.ByteSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+
+			return descriptor.NewImplicitData(name, content)
+		}
+	}
+
+	unavailable := locationAvailability(variable, location)
+	if unavailable != expression.Available {
+		return &expression.TypedData{
+			VirtualMemory:  stack.VirtualMemory,
+			FormatPrefix:   name,
+			DataDescriptor: descriptor,
+			BitSize:        8 * descriptor.ByteSize,
+			Location:       location,
+			Unavailable:    unavailable,
+		}, nil
+	}
+
 	var address VirtualAddress
 	if len(location) == 1 && location[0].Kind == dwarf.AddressLocation {
 		address = VirtualAddress(location[0].Value)
@@ -375,6 +954,165 @@ func (stack *CallStack) readVariable(
 	}, nil
 }
 
+// Encode the variable's DW_AT_const_value as the variable's in-memory
+// representation.  Constant data values are sign / zero extended to the
+// variable's size, whereas block values are used as is.
+func encodeConstValue(constValue interface{}, byteSize int) ([]byte, error) {
+	content := make([]byte, 8)
+	switch value := constValue.(type) {
+	case uint64:
+		binary.LittleEndian.PutUint64(content, value)
+	case int64:
+		binary.LittleEndian.PutUint64(content, uint64(value))
+		if value < 0 {
+			for len(content) < byteSize {
+				content = append(content, 0xff)
+			}
+		}
+	case []byte:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("invalid constant value (%v)", constValue)
+	}
+
+	for len(content) < byteSize {
+		content = append(content, 0)
+	}
+
+	return content, nil
+}
+
+// Returns the reason the variable's evaluated location cannot be read, or
+// expression.Available if the location is readable.
+//
+// NOTE: composite locations with some unavailable pieces are treated as
+// entirely optimized out.
+func locationAvailability(
+	variable *dwarf.DebugInfoEntry,
+	location dwarf.Location,
+) expression.UnavailableReason {
+	if len(location) == 0 {
+		if variable.IsLocationList(dwarf.DW_AT_location) {
+			return expression.NotAvailableAtCurrentPC
+		}
+		return expression.OptimizedOut
+	}
+
+	for _, chunk := range location {
+		if chunk.Kind == dwarf.UnavailableLocation {
+			return expression.OptimizedOut
+		}
+	}
+
+	return expression.Available
+}
+
+// Evaluate the variable length array subrange's number of elements in the
+// frame.  The number of elements is either DW_AT_count, or DW_AT_upper_bound
+// plus one.
+func (stack *CallStack) evaluateArrayDimension(
+	frame *CallFrame,
+	subrange *dwarf.DebugInfoEntry,
+) (
+	int64,
+	error,
+) {
+	_, ok := subrange.Any(dwarf.DW_AT_count)
+	if ok {
+		return stack.evaluateSubrangeBound(frame, subrange, dwarf.DW_AT_count)
+	}
+
+	bound, err := stack.evaluateSubrangeBound(
+		frame,
+		subrange,
+		dwarf.DW_AT_upper_bound)
+	if err != nil {
+		return 0, err
+	}
+
+	return bound + 1, nil
+}
+
+// Evaluate the subrange's count / bound attribute, which is either a
+// reference to a variable holding the value, or a dwarf expression which
+// computes the value, in the frame.
+func (stack *CallStack) evaluateSubrangeBound(
+	frame *CallFrame,
+	subrange *dwarf.DebugInfoEntry,
+	attribute dwarf.Attribute,
+) (
+	int64,
+	error,
+) {
+	bound, _ := subrange.Any(attribute)
+	ref, ok := bound.(*dwarf.DebugInfoEntryReference)
+	if !ok {
+		location, err := subrange.EvaluateLocation(
+			attribute,
+			frame,
+			false, // in frame info
+			false) // push cfa
+		if err != nil {
+			return 0, err
+		}
+
+		// NOTE: the expression's result is the bound value rather than the
+		// bound's location.
+		if len(location) != 1 ||
+			(location[0].Kind != dwarf.AddressLocation &&
+				location[0].Kind != dwarf.ImplicitLiteralLocation) {
+
+			return 0, fmt.Errorf("%s unavailable", attribute)
+		}
+
+		return int64(location[0].Value), nil
+	}
+
+	variable, err := ref.Get()
+	if err != nil {
+		return 0, err
+	}
+
+	name, _, err := variable.Name()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := stack.readVariable(frame, name, variable)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := data.DecodeSimpleValue()
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := value.(type) {
+	case int8:
+		return int64(value), nil
+	case int16:
+		return int64(value), nil
+	case int32:
+		return int64(value), nil
+	case int64:
+		return value, nil
+	case uint8:
+		return int64(value), nil
+	case uint16:
+		return int64(value), nil
+	case uint32:
+		return int64(value), nil
+	case uint64:
+		return int64(value), nil
+	default:
+		return 0, fmt.Errorf(
+			"unsupported %s type (%s)",
+			attribute,
+			data.TypeName())
+	}
+}
+
 func (stack *CallStack) MaybeStepIntoInlinedFunction(
 	status *ThreadStatus,
 ) (
@@ -471,7 +1209,10 @@ func (stack *CallStack) updateStack(
 	stack.currentInspectFrame = 0
 	stack.frames = []*CallFrame{}
 
+	source := UnwindFromRegisters
+	warning := ""
 	for {
+		numFrames := len(stack.frames)
 		hasPushed, err := stack.pushCallFrames(pc, currentState)
 		if err != nil {
 			return err
@@ -480,11 +1221,18 @@ func (stack *CallStack) updateStack(
 			break
 		}
 
+		baseFrame := stack.frames[numFrames]
+		baseFrame.UnwindSource = source
+		baseFrame.UnwindWarning = warning
+
 		rules, err := stack.LoadedElves.ComputeUnwindRulesAt(pc)
 		if err != nil {
 			return err
 		}
 		if rules == nil {
+			baseFrame.UnwindWarning = joinUnwindWarnings(
+				baseFrame.UnwindWarning,
+				"no call frame information (backtrace may be truncated)")
 			break
 		}
 
@@ -500,12 +1248,33 @@ func (stack *CallStack) updateStack(
 			break
 		}
 
+		baseFrame.ReturnAddress = VirtualAddress(pcValue.ToUint64())
+		source = UnwindFromCFI
+		warning = ""
+
+		// NOTE: the stack grows downward.  A caller frame whose stack pointer
+		// is not above the callee's is most likely garbage.
+		calleeSP := baseFrame.Registers.Value(registers.StackPointer)
+		callerSP := currentState.Value(registers.StackPointer)
+		if calleeSP != nil && callerSP != nil &&
+			callerSP.ToUint64() <= calleeSP.ToUint64() {
+
+			warning = "stack pointer did not increase while unwinding"
+		}
+
 		// NOTE: pcValue points to the return address, which is one instruction
 		// after the call instruction.  Subtract one to position the pc somewhere
 		// in the call instruction bytes.
 		pc = VirtualAddress(pcValue.ToUint64() - 1)
 	}
 
+	// NOTE: a base frame's caller frames immediately follow the base frame.
+	for idx := 0; idx < len(stack.frames)-1; idx++ {
+		if !stack.frames[idx].IsInlined() {
+			stack.frames[idx].caller = stack.frames[idx+1]
+		}
+	}
+
 	for idx, frame := range stack.frames {
 		if !frame.IsInlined() || frame.CodeRanges[0].Low < stack.currentPC {
 			stack.executingFrame = idx
@@ -517,6 +1286,13 @@ func (stack *CallStack) updateStack(
 	return nil
 }
 
+func joinUnwindWarnings(existing string, warning string) string {
+	if existing == "" {
+		return warning
+	}
+	return existing + "; " + warning
+}
+
 func (stack *CallStack) pushCallFrames(
 	pc VirtualAddress,
 	state registers.State,
@@ -569,6 +1345,7 @@ func (stack *CallStack) pushCallFrames(
 		CodeRanges:              codeRanges,
 		BacktraceProgramCounter: pc,
 		Registers:               state,
+		stack:                   stack,
 		memory:                  stack.VirtualMemory,
 	}
 
@@ -612,6 +1389,7 @@ func (stack *CallStack) pushCallFrames(
 					CodeRanges:              codeRanges,
 					BacktraceProgramCounter: pc,
 					Registers:               state,
+					stack:                   stack,
 					memory:                  stack.VirtualMemory,
 				}
 				frames = append(frames, currentFrame)
@@ -721,6 +1499,9 @@ func (stack *CallStack) unwind(
 					rule.RegisterId)
 			}
 			value = currentFrame.Registers.Value(otherRegister)
+			if value != nil && otherRegister.Size != register.Size {
+				value = resizeRegisterValue(value, register.Size)
+			}
 		case dwarf.SameValueRule:
 			value = currentFrame.Registers.Value(register)
 		case dwarf.OffsetRule, dwarf.ValueOffsetRule:
@@ -753,31 +1534,25 @@ func (stack *CallStack) unwind(
 		if value != nil &&
 			(rule.Kind == dwarf.OffsetRule || rule.Kind == dwarf.ExpressionRule) {
 
-			if register.Size > 8 {
-				return registers.State{}, fmt.Errorf("unexpected register size")
-			}
-
-			out := make([]byte, 8)
+			// NOTE: the saved value occupies the register's full size (e.g., 16
+			// bytes for sse registers).
+			out := make([]byte, register.Size)
 			n, err := stack.VirtualMemory.Read(VirtualAddress(value.ToUint64()), out)
 			if err != nil {
 				return registers.State{}, err
 			}
-			if n != 8 {
-				panic("should never happen")
-			}
-
-			uintVal := uint64(0)
-			n, err = binary.Decode(out, binary.LittleEndian, &uintVal)
-			if err != nil {
+			if n != len(out) {
 				return registers.State{}, fmt.Errorf(
-					"failed to decode register value: %w",
-					err)
-			}
-			if n != 8 {
-				panic("should never happen")
+					"failed to read saved register (%s) value",
+					register.Name)
 			}
 
-			value = registers.U64(uintVal)
+			value = registers.FromBytes(out)
+		} else if value != nil && register.Size != 8 &&
+			(rule.Kind == dwarf.ValueOffsetRule ||
+				rule.Kind == dwarf.ValueExpressionRule) {
+
+			value = resizeRegisterValue(value, register.Size)
 		}
 
 		if value == nil {
@@ -794,3 +1569,17 @@ func (stack *CallStack) unwind(
 
 	return previousState, nil
 }
+
+// Zero extends / truncates the register value to the given byte size.
+func resizeRegisterValue(value registers.Value, size uintptr) registers.Value {
+	data := value.ToBytes()
+	if uintptr(len(data)) > size {
+		data = data[:size]
+	}
+
+	for uintptr(len(data)) < size {
+		data = append(data, 0)
+	}
+
+	return registers.FromBytes(data)
+}
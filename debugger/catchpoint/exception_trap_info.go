@@ -0,0 +1,242 @@
+package catchpoint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/ianlancetaylor/demangle"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
+)
+
+const (
+	// NOTE: The following offsets are relative to the _Unwind_Exception header
+	// passed to __cxa_begin_catch, and are specific to libstdc++'s x86_64
+	// __cxa_exception layout:
+	//
+	//  __cxa_exception (exceptionType is 80 bytes before the header)
+	//  _Unwind_Exception (header, 32 bytes)
+	//  thrown object
+	exceptionTypeOffset  = 80
+	unwindHeaderByteSize = 32
+
+	maxTypeNameLength = 1024
+)
+
+var (
+	// Exception class for native (non-dependent) c++ exceptions.  NOTE: the
+	// characters are packed into an uint64 with the first character as the
+	// most significant byte.
+	gnuCppExceptionClass = binary.BigEndian.Uint64([]byte("GNUCC++\x00"))
+)
+
+type ExceptionEvent string
+
+const (
+	ThrowEvent = ExceptionEvent("throw")
+	CatchEvent = ExceptionEvent("catch")
+)
+
+var ExceptionEvents = []ExceptionEvent{ThrowEvent, CatchEvent}
+
+// The c++ runtime function which signals the event.
+func (event ExceptionEvent) FunctionName() string {
+	switch event {
+	case ThrowEvent:
+		return "__cxa_throw"
+	case CatchEvent:
+		return "__cxa_begin_catch"
+	default:
+		panic("should never happen")
+	}
+}
+
+type memoryReader interface {
+	Read(VirtualAddress, []byte) (int, error)
+}
+
+type ExceptionTrapInfo struct {
+	Event ExceptionEvent
+
+	ObjectAddress VirtualAddress
+
+	// Empty if the exception's type info is unavailable (e.g., foreign or
+	// dependent exception)
+	TypeName string
+}
+
+// The register state must be at the event function's entry point.
+func NewExceptionTrapInfo(
+	event ExceptionEvent,
+	registerState registers.State,
+	mem memoryReader,
+) (
+	*ExceptionTrapInfo,
+	error,
+) {
+	arg := registerState.Value(registers.FunctionArgs[0]).ToUint64()
+
+	info := &ExceptionTrapInfo{
+		Event: event,
+	}
+
+	var typeInfoAddress VirtualAddress
+	switch event {
+	case ThrowEvent:
+		// __cxa_throw(void* object, std::type_info* tinfo, void (*dest)(void*))
+		info.ObjectAddress = VirtualAddress(arg)
+		typeInfoAddress = VirtualAddress(
+			registerState.Value(registers.FunctionArgs[1]).ToUint64())
+	case CatchEvent:
+		// __cxa_begin_catch(void* unwindHeader)
+		header := VirtualAddress(arg)
+		info.ObjectAddress = header + unwindHeaderByteSize
+
+		exceptionClass, err := readUint64(mem, header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exception class: %w", err)
+		}
+
+		if exceptionClass != gnuCppExceptionClass {
+			return info, nil
+		}
+
+		typeInfoAddress, err = readAddress(
+			mem,
+			header-exceptionTypeOffset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exception type: %w", err)
+		}
+	default:
+		panic("should never happen")
+	}
+
+	if typeInfoAddress == 0 {
+		return info, nil
+	}
+
+	// std::type_info's layout is {vtable pointer, const char* name}
+	nameAddress, err := readAddress(mem, typeInfoAddress+8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read type info name: %w", err)
+	}
+
+	name, err := readCString(mem, nameAddress, maxTypeNameLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read type info name: %w", err)
+	}
+
+	info.TypeName = demangleTypeName(name)
+	return info, nil
+}
+
+func (info ExceptionTrapInfo) String() string {
+	typeName := info.TypeName
+	if typeName == "" {
+		typeName = "<unknown type>"
+	}
+
+	return fmt.Sprintf(
+		"exception %s: %s (object: %s)",
+		info.Event,
+		typeName,
+		info.ObjectAddress)
+}
+
+// The type info name is the mangled type name without the "_Z" prefix.
+func demangleTypeName(name string) string {
+	const prefix = "typeinfo name for "
+
+	demangled, err := demangle.ToString("_ZTS" + name)
+	if err != nil || !strings.HasPrefix(demangled, prefix) {
+		return name
+	}
+
+	return strings.TrimPrefix(demangled, prefix)
+}
+
+func readFull(mem memoryReader, addr VirtualAddress, out []byte) error {
+	n, err := mem.Read(addr, out)
+	if err != nil {
+		return err
+	}
+	if n != len(out) {
+		return fmt.Errorf("incorrect read size (%d != %d)", n, len(out))
+	}
+	return nil
+}
+
+func readUint64(mem memoryReader, addr VirtualAddress) (uint64, error) {
+	buffer := make([]byte, 8)
+	err := readFull(mem, addr, buffer)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(buffer), nil
+}
+
+func readAddress(mem memoryReader, addr VirtualAddress) (VirtualAddress, error) {
+	value, err := readUint64(mem, addr)
+	return VirtualAddress(value), err
+}
+
+func readCString(
+	mem memoryReader,
+	addr VirtualAddress,
+	maxLength int,
+) (
+	string,
+	error,
+) {
+	result, truncated, err := readTruncatedCString(mem, addr, maxLength)
+	if err != nil {
+		return "", err
+	}
+	if truncated {
+		return "", fmt.Errorf("c string too long (> %d)", maxLength)
+	}
+	return result, nil
+}
+
+// Reads up to maxLength bytes of the c string.  The returned bool is true if
+// the string is longer than maxLength.
+func readTruncatedCString(
+	mem memoryReader,
+	addr VirtualAddress,
+	maxLength int,
+) (
+	string,
+	bool,
+	error,
+) {
+	result := []byte{}
+	buffer := make([]byte, 64)
+	for len(result) < maxLength {
+		n, err := mem.Read(addr, buffer)
+		if err != nil {
+			return "", false, err
+		}
+		if n == 0 {
+			return "", false, fmt.Errorf("read zero bytes")
+		}
+
+		chunk := buffer[:n]
+		idx := bytes.IndexByte(chunk, 0)
+		if idx != -1 {
+			result = append(result, chunk[:idx]...)
+			if len(result) > maxLength {
+				return string(result[:maxLength]), true, nil
+			}
+			return string(result), false, nil
+		}
+
+		result = append(result, chunk...)
+		addr += VirtualAddress(n)
+	}
+
+	return string(result[:maxLength]), true, nil
+}
@@ -11,6 +11,9 @@ const (
 type SyscallCatchPolicy struct {
 	mode catchMode
 	ids  []SyscallId
+
+	// nil if the listed syscalls are caught unconditionally.
+	condition *SyscallCondition
 }
 
 func NewSyscallCatchPolicy() *SyscallCatchPolicy {
@@ -27,16 +30,33 @@ func (policy *SyscallCatchPolicy) IsEnabled() bool {
 func (policy *SyscallCatchPolicy) CatchNone() {
 	policy.mode = catchNone
 	policy.ids = nil
+	policy.condition = nil
 }
 
 func (policy *SyscallCatchPolicy) CatchAll() {
 	policy.mode = catchAll
 	policy.ids = nil
+	policy.condition = nil
 }
 
 func (policy *SyscallCatchPolicy) CatchList(ids []SyscallId) {
+	policy.CatchListIf(ids, nil)
+}
+
+// Catch the listed syscalls only when the condition holds.  The condition is
+// optional.
+func (policy *SyscallCatchPolicy) CatchListIf(
+	ids []SyscallId,
+	condition *SyscallCondition,
+) {
 	policy.mode = catchList
 	policy.ids = ids
+	policy.condition = condition
+}
+
+// Returns nil if matching syscalls are caught unconditionally.
+func (policy *SyscallCatchPolicy) Condition() *SyscallCondition {
+	return policy.condition
 }
 
 func (policy *SyscallCatchPolicy) Matches(id SyscallId) bool {
@@ -64,6 +84,9 @@ func (policy *SyscallCatchPolicy) String() string {
 		for _, id := range policy.ids {
 			result += " " + id.Name
 		}
+		if policy.condition != nil {
+			result += " if " + policy.condition.String()
+		}
 		return result
 	default:
 		panic("should never happen")
@@ -0,0 +1,204 @@
+package catchpoint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const (
+	maxSyscallStringArgLength = 4096
+
+	returnValueIndex = -1
+)
+
+var (
+	syscallConditionOperators = map[string]struct{}{
+		"==":       struct{}{},
+		"!=":       struct{}{},
+		"<":        struct{}{},
+		"<=":       struct{}{},
+		">":        struct{}{},
+		">=":       struct{}{},
+		"&":        struct{}{}, // true if any of the bits are set
+		"contains": struct{}{}, // the argument is a c string pointer
+	}
+)
+
+// SyscallCondition filters syscall catch points by comparing either a single
+// syscall argument (arg0 to arg5) or the return value (ret) against a value.
+//
+// Argument conditions are evaluated at syscall entry, and the syscall exit is
+// caught only if its entry was caught.  Return value conditions are evaluated
+// at syscall exit, and the syscall entry is never caught.
+type SyscallCondition struct {
+	// The argument index, or returnValueIndex for the return value.
+	argIndex int
+
+	Operator string
+
+	// Integer operand, compared as signed integer.  Unused by "contains".
+	Value int64
+
+	// Only used by "contains".
+	Substring string
+}
+
+// Parses "<arg0-5|ret> <operator> <value>".  The value is a (optionally
+// quoted) string for "contains", and an integer otherwise.
+func ParseSyscallCondition(condition string) (*SyscallCondition, error) {
+	fields := strings.SplitN(strings.TrimSpace(condition), " ", 3)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf(
+			"%w. invalid syscall condition (%s). "+
+				"expected <arg0-5|ret> <operator> <value>",
+			ErrInvalidInput,
+			condition)
+	}
+
+	result := &SyscallCondition{
+		Operator: fields[1],
+	}
+
+	operand := fields[0]
+	if operand == "ret" {
+		result.argIndex = returnValueIndex
+	} else {
+		idx, err := strconv.Atoi(strings.TrimPrefix(operand, "arg"))
+		if err != nil ||
+			!strings.HasPrefix(operand, "arg") ||
+			idx < 0 ||
+			idx > 5 {
+
+			return nil, fmt.Errorf(
+				"%w. invalid syscall condition operand (%s). expected arg0-5 or ret",
+				ErrInvalidInput,
+				operand)
+		}
+		result.argIndex = idx
+	}
+
+	_, ok := syscallConditionOperators[result.Operator]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w. invalid syscall condition operator (%s)",
+			ErrInvalidInput,
+			result.Operator)
+	}
+
+	value := strings.TrimSpace(fields[2])
+	if result.Operator == "contains" {
+		if result.argIndex == returnValueIndex {
+			return nil, fmt.Errorf(
+				"%w. contains is not applicable to the return value",
+				ErrInvalidInput)
+		}
+
+		if strings.HasPrefix(value, "\"") {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"%w. invalid syscall condition string (%s): %w",
+					ErrInvalidInput,
+					value,
+					err)
+			}
+			value = unquoted
+		}
+
+		result.Substring = value
+		return result, nil
+	}
+
+	// NOTE: accept both signed (e.g., -1) and unsigned (e.g., 0xffffffffffffffff)
+	// representations.
+	signed, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		unsigned, uerr := strconv.ParseUint(value, 0, 64)
+		if uerr != nil {
+			return nil, fmt.Errorf(
+				"%w. invalid syscall condition value (%s): %w",
+				ErrInvalidInput,
+				value,
+				err)
+		}
+		signed = int64(unsigned)
+	}
+
+	result.Value = signed
+	return result, nil
+}
+
+func (cond *SyscallCondition) IsReturnValueCondition() bool {
+	return cond.argIndex == returnValueIndex
+}
+
+func (cond *SyscallCondition) String() string {
+	operand := "ret"
+	if cond.argIndex != returnValueIndex {
+		operand = fmt.Sprintf("arg%d", cond.argIndex)
+	}
+
+	if cond.Operator == "contains" {
+		return fmt.Sprintf("%s contains %q", operand, cond.Substring)
+	}
+
+	return fmt.Sprintf("%s %s %d", operand, cond.Operator, cond.Value)
+}
+
+// The info must be a syscall entry for argument conditions, and a syscall
+// exit for return value conditions.
+func (cond *SyscallCondition) Evaluate(
+	info *SyscallTrapInfo,
+	mem memoryReader,
+) (
+	bool,
+	error,
+) {
+	var operand uint64
+	if cond.argIndex == returnValueIndex {
+		if info.IsEntry {
+			return false, fmt.Errorf("return value is unavailable at syscall entry")
+		}
+		operand = info.Ret
+	} else {
+		if !info.IsEntry {
+			return false, fmt.Errorf("arguments are unavailable at syscall exit")
+		}
+		operand = info.Args[cond.argIndex]
+	}
+
+	value := int64(operand)
+	switch cond.Operator {
+	case "==":
+		return value == cond.Value, nil
+	case "!=":
+		return value != cond.Value, nil
+	case "<":
+		return value < cond.Value, nil
+	case "<=":
+		return value <= cond.Value, nil
+	case ">":
+		return value > cond.Value, nil
+	case ">=":
+		return value >= cond.Value, nil
+	case "&":
+		return value&cond.Value != 0, nil
+	case "contains":
+		str, err := readCString(
+			mem,
+			VirtualAddress(operand),
+			maxSyscallStringArgLength)
+		if err != nil {
+			return false, fmt.Errorf(
+				"failed to read arg%d string: %w",
+				cond.argIndex,
+				err)
+		}
+		return strings.Contains(str, cond.Substring), nil
+	default:
+		panic("should never happen")
+	}
+}
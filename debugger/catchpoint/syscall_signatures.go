@@ -0,0 +1,480 @@
+package catchpoint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const maxDisplayedStringArgLength = 64
+
+type syscallArgKind int
+
+const (
+	intArg       = syscallArgKind(iota) // 32-bit signed integer
+	longArg                             // 64-bit signed integer
+	uintArg                             // 64-bit unsigned integer
+	hexArg                              // pointers / opaque values
+	fdArg                               // file descriptor, including AT_FDCWD
+	stringArg                           // pointer to null terminated string
+	octalArg                            // file mode
+	signalArg                           // signal number
+	openFlagsArg                        // O_* flags
+	protArg                             // PROT_* flags
+	mmapFlagsArg                        // MAP_* flags
+)
+
+type syscallArg struct {
+	Name string
+	Kind syscallArgKind
+}
+
+type syscallSignature struct {
+	Args []syscallArg
+
+	// Only intArg (decoded with errno name on failure) and hexArg are used.
+	Return syscallArgKind
+}
+
+func sig(args ...syscallArg) syscallSignature {
+	return syscallSignature{
+		Args:   args,
+		Return: intArg,
+	}
+}
+
+func ptrSig(args ...syscallArg) syscallSignature {
+	return syscallSignature{
+		Args:   args,
+		Return: hexArg,
+	}
+}
+
+func arg(name string, kind syscallArgKind) syscallArg {
+	return syscallArg{
+		Name: name,
+		Kind: kind,
+	}
+}
+
+var (
+	// Signatures (see man 2 <syscall>) of commonly used syscalls.  Syscalls
+	// not listed here are displayed with raw hex arguments.
+	syscallSignatures = map[string]syscallSignature{
+		"read": sig(
+			arg("fd", fdArg),
+			arg("buf", hexArg),
+			arg("count", uintArg)),
+		"write": sig(
+			arg("fd", fdArg),
+			arg("buf", hexArg),
+			arg("count", uintArg)),
+		"open": sig(
+			arg("pathname", stringArg),
+			arg("flags", openFlagsArg),
+			arg("mode", octalArg)),
+		"close": sig(arg("fd", fdArg)),
+		"stat": sig(
+			arg("pathname", stringArg),
+			arg("statbuf", hexArg)),
+		"fstat": sig(
+			arg("fd", fdArg),
+			arg("statbuf", hexArg)),
+		"lstat": sig(
+			arg("pathname", stringArg),
+			arg("statbuf", hexArg)),
+		"poll": sig(
+			arg("fds", hexArg),
+			arg("nfds", uintArg),
+			arg("timeout", intArg)),
+		"lseek": sig(
+			arg("fd", fdArg),
+			arg("offset", longArg),
+			arg("whence", intArg)),
+		"mmap": ptrSig(
+			arg("addr", hexArg),
+			arg("length", uintArg),
+			arg("prot", protArg),
+			arg("flags", mmapFlagsArg),
+			arg("fd", fdArg),
+			arg("offset", longArg)),
+		"mprotect": sig(
+			arg("addr", hexArg),
+			arg("len", uintArg),
+			arg("prot", protArg)),
+		"munmap": sig(
+			arg("addr", hexArg),
+			arg("length", uintArg)),
+		"brk": ptrSig(arg("addr", hexArg)),
+		"rt_sigaction": sig(
+			arg("signum", signalArg),
+			arg("act", hexArg),
+			arg("oldact", hexArg),
+			arg("sigsetsize", uintArg)),
+		"rt_sigprocmask": sig(
+			arg("how", intArg),
+			arg("set", hexArg),
+			arg("oldset", hexArg),
+			arg("sigsetsize", uintArg)),
+		"ioctl": sig(
+			arg("fd", fdArg),
+			arg("request", hexArg),
+			arg("arg", hexArg)),
+		"pread64": sig(
+			arg("fd", fdArg),
+			arg("buf", hexArg),
+			arg("count", uintArg),
+			arg("offset", longArg)),
+		"pwrite64": sig(
+			arg("fd", fdArg),
+			arg("buf", hexArg),
+			arg("count", uintArg),
+			arg("offset", longArg)),
+		"readv": sig(
+			arg("fd", fdArg),
+			arg("iov", hexArg),
+			arg("iovcnt", intArg)),
+		"writev": sig(
+			arg("fd", fdArg),
+			arg("iov", hexArg),
+			arg("iovcnt", intArg)),
+		"access": sig(
+			arg("pathname", stringArg),
+			arg("mode", intArg)),
+		"pipe":        sig(arg("pipefd", hexArg)),
+		"sched_yield": sig(),
+		"dup":         sig(arg("oldfd", fdArg)),
+		"dup2": sig(
+			arg("oldfd", fdArg),
+			arg("newfd", fdArg)),
+		"nanosleep": sig(
+			arg("req", hexArg),
+			arg("rem", hexArg)),
+		"getpid": sig(),
+		"clone": sig(
+			arg("flags", hexArg),
+			arg("stack", hexArg),
+			arg("parent_tid", hexArg),
+			arg("child_tid", hexArg),
+			arg("tls", hexArg)),
+		"fork":  sig(),
+		"vfork": sig(),
+		"execve": sig(
+			arg("pathname", stringArg),
+			arg("argv", hexArg),
+			arg("envp", hexArg)),
+		"exit": sig(arg("status", intArg)),
+		"wait4": sig(
+			arg("pid", intArg),
+			arg("wstatus", hexArg),
+			arg("options", intArg),
+			arg("rusage", hexArg)),
+		"kill": sig(
+			arg("pid", intArg),
+			arg("sig", signalArg)),
+		"fcntl": sig(
+			arg("fd", fdArg),
+			arg("cmd", intArg),
+			arg("arg", hexArg)),
+		"getcwd": ptrSig(
+			arg("buf", hexArg),
+			arg("size", uintArg)),
+		"chdir": sig(arg("path", stringArg)),
+		"rename": sig(
+			arg("oldpath", stringArg),
+			arg("newpath", stringArg)),
+		"mkdir": sig(
+			arg("pathname", stringArg),
+			arg("mode", octalArg)),
+		"rmdir":  sig(arg("pathname", stringArg)),
+		"unlink": sig(arg("pathname", stringArg)),
+		"readlink": sig(
+			arg("pathname", stringArg),
+			arg("buf", hexArg),
+			arg("bufsiz", uintArg)),
+		"gettid": sig(),
+		"futex": sig(
+			arg("uaddr", hexArg),
+			arg("futex_op", intArg),
+			arg("val", intArg),
+			arg("timeout", hexArg),
+			arg("uaddr2", hexArg),
+			arg("val3", intArg)),
+		"set_tid_address": sig(arg("tidptr", hexArg)),
+		"arch_prctl": sig(
+			arg("code", hexArg),
+			arg("addr", hexArg)),
+		"clock_nanosleep": sig(
+			arg("clockid", intArg),
+			arg("flags", intArg),
+			arg("request", hexArg),
+			arg("remain", hexArg)),
+		"exit_group": sig(arg("status", intArg)),
+		"tgkill": sig(
+			arg("tgid", intArg),
+			arg("tid", intArg),
+			arg("sig", signalArg)),
+		"openat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("flags", openFlagsArg),
+			arg("mode", octalArg)),
+		"mkdirat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("mode", octalArg)),
+		"newfstatat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("statbuf", hexArg),
+			arg("flags", hexArg)),
+		"unlinkat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("flags", hexArg)),
+		"readlinkat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("buf", hexArg),
+			arg("bufsiz", uintArg)),
+		"faccessat": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("mode", intArg)),
+		"set_robust_list": sig(
+			arg("head", hexArg),
+			arg("len", uintArg)),
+		"pipe2": sig(
+			arg("pipefd", hexArg),
+			arg("flags", openFlagsArg)),
+		"dup3": sig(
+			arg("oldfd", fdArg),
+			arg("newfd", fdArg),
+			arg("flags", openFlagsArg)),
+		"prlimit64": sig(
+			arg("pid", intArg),
+			arg("resource", intArg),
+			arg("new_limit", hexArg),
+			arg("old_limit", hexArg)),
+		"getrandom": sig(
+			arg("buf", hexArg),
+			arg("buflen", uintArg),
+			arg("flags", hexArg)),
+		"statx": sig(
+			arg("dirfd", fdArg),
+			arg("pathname", stringArg),
+			arg("flags", hexArg),
+			arg("mask", hexArg),
+			arg("statxbuf", hexArg)),
+		"rseq": sig(
+			arg("rseq", hexArg),
+			arg("rseq_len", uintArg),
+			arg("flags", hexArg),
+			arg("sig", hexArg)),
+	}
+)
+
+type flagName struct {
+	Value uint64
+	Name  string
+}
+
+var (
+	// NOTE: flags which include other flags (e.g., O_SYNC includes O_DSYNC)
+	// must be listed first.
+	openFlagNames = []flagName{
+		{unix.O_TMPFILE, "O_TMPFILE"},
+		{unix.O_SYNC, "O_SYNC"},
+		{unix.O_CREAT, "O_CREAT"},
+		{unix.O_EXCL, "O_EXCL"},
+		{unix.O_NOCTTY, "O_NOCTTY"},
+		{unix.O_TRUNC, "O_TRUNC"},
+		{unix.O_APPEND, "O_APPEND"},
+		{unix.O_NONBLOCK, "O_NONBLOCK"},
+		{unix.O_DSYNC, "O_DSYNC"},
+		{unix.O_ASYNC, "O_ASYNC"},
+		{unix.O_DIRECT, "O_DIRECT"},
+		{0100000, "O_LARGEFILE"},
+		{unix.O_DIRECTORY, "O_DIRECTORY"},
+		{unix.O_NOFOLLOW, "O_NOFOLLOW"},
+		{unix.O_NOATIME, "O_NOATIME"},
+		{unix.O_CLOEXEC, "O_CLOEXEC"},
+		{unix.O_PATH, "O_PATH"},
+	}
+
+	protFlagNames = []flagName{
+		{unix.PROT_READ, "PROT_READ"},
+		{unix.PROT_WRITE, "PROT_WRITE"},
+		{unix.PROT_EXEC, "PROT_EXEC"},
+	}
+
+	mmapFlagNames = []flagName{
+		{unix.MAP_FIXED_NOREPLACE, "MAP_FIXED_NOREPLACE"},
+		{unix.MAP_FIXED, "MAP_FIXED"},
+		{unix.MAP_ANONYMOUS, "MAP_ANONYMOUS"},
+		{unix.MAP_GROWSDOWN, "MAP_GROWSDOWN"},
+		{unix.MAP_DENYWRITE, "MAP_DENYWRITE"},
+		{unix.MAP_EXECUTABLE, "MAP_EXECUTABLE"},
+		{unix.MAP_LOCKED, "MAP_LOCKED"},
+		{unix.MAP_NORESERVE, "MAP_NORESERVE"},
+		{unix.MAP_POPULATE, "MAP_POPULATE"},
+		{unix.MAP_NONBLOCK, "MAP_NONBLOCK"},
+		{unix.MAP_STACK, "MAP_STACK"},
+		{unix.MAP_HUGETLB, "MAP_HUGETLB"},
+	}
+)
+
+// Returns the flags' names joined by "|".  Unnamed bits are appended in hex.
+func formatFlags(value uint64, names []flagName, prefix []string) string {
+	parts := prefix
+	for _, flag := range names {
+		if flag.Value != 0 && value&flag.Value == flag.Value {
+			parts = append(parts, flag.Name)
+			value &^= flag.Value
+		}
+	}
+
+	if value != 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("0x%x", value))
+	}
+
+	return strings.Join(parts, "|")
+}
+
+func formatOpenFlags(value uint64) string {
+	accessMode := "O_RDONLY"
+	switch value & unix.O_ACCMODE {
+	case unix.O_WRONLY:
+		accessMode = "O_WRONLY"
+	case unix.O_RDWR:
+		accessMode = "O_RDWR"
+	case unix.O_ACCMODE:
+		accessMode = "O_ACCMODE"
+	}
+
+	return formatFlags(
+		value&^unix.O_ACCMODE,
+		openFlagNames,
+		[]string{accessMode})
+}
+
+func formatMmapFlags(value uint64) string {
+	mapType := fmt.Sprintf("0x%x", value&0x3)
+	switch value & 0x3 {
+	case unix.MAP_SHARED:
+		mapType = "MAP_SHARED"
+	case unix.MAP_PRIVATE:
+		mapType = "MAP_PRIVATE"
+	case unix.MAP_SHARED_VALIDATE:
+		mapType = "MAP_SHARED_VALIDATE"
+	}
+
+	return formatFlags(value&^0x3, mmapFlagNames, []string{mapType})
+}
+
+func formatSyscallArg(
+	kind syscallArgKind,
+	value uint64,
+	mem memoryReader,
+) string {
+	switch kind {
+	case intArg:
+		return strconv.FormatInt(int64(int32(value)), 10)
+	case longArg:
+		return strconv.FormatInt(int64(value), 10)
+	case uintArg:
+		return strconv.FormatUint(value, 10)
+	case fdArg:
+		fd := int32(value)
+		if fd == unix.AT_FDCWD {
+			return "AT_FDCWD"
+		}
+		return strconv.FormatInt(int64(fd), 10)
+	case stringArg:
+		if value == 0 {
+			return "NULL"
+		}
+
+		str, truncated, err := readTruncatedCString(
+			mem,
+			VirtualAddress(value),
+			maxDisplayedStringArgLength)
+		if err != nil {
+			return fmt.Sprintf("0x%x", value)
+		}
+
+		result := strconv.Quote(str)
+		if truncated {
+			result += "..."
+		}
+		return result
+	case octalArg:
+		return fmt.Sprintf("%#o", value)
+	case signalArg:
+		name := unix.SignalName(syscall.Signal(value))
+		if name == "" {
+			return strconv.FormatUint(value, 10)
+		}
+		return name
+	case openFlagsArg:
+		return formatOpenFlags(value)
+	case protArg:
+		if value == unix.PROT_NONE {
+			return "PROT_NONE"
+		}
+		return formatFlags(value, protFlagNames, nil)
+	case mmapFlagsArg:
+		return formatMmapFlags(value)
+	default: // hexArg
+		return fmt.Sprintf("0x%x", value)
+	}
+}
+
+// Returns the decoded "name=value" arguments.  Unknown syscalls are decoded
+// as raw hex values.
+func decodeSyscallArgs(
+	id SyscallId,
+	args [6]uint64,
+	mem memoryReader,
+) []string {
+	signature, ok := syscallSignatures[id.Name]
+	if !ok {
+		result := []string{}
+		for _, value := range args {
+			result = append(result, fmt.Sprintf("0x%x", value))
+		}
+		return result
+	}
+
+	result := []string{}
+	for idx, arg := range signature.Args {
+		result = append(
+			result,
+			arg.Name+"="+formatSyscallArg(arg.Kind, args[idx], mem))
+	}
+	return result
+}
+
+func formatSyscallReturn(id SyscallId, ret uint64) string {
+	// NOTE: the kernel returns -errno on failure.
+	signed := int64(ret)
+	if -4095 <= signed && signed < 0 {
+		name := unix.ErrnoName(syscall.Errno(-signed))
+		if name != "" {
+			return fmt.Sprintf("%d (%s)", signed, name)
+		}
+		return strconv.FormatInt(signed, 10)
+	}
+
+	signature, ok := syscallSignatures[id.Name]
+	if !ok || signature.Return == hexArg {
+		return fmt.Sprintf("0x%x", ret)
+	}
+
+	return strconv.FormatInt(signed, 10)
+}
@@ -1,7 +1,7 @@
 package catchpoint
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/pattyshack/bad/debugger/registers"
 )
@@ -12,9 +12,18 @@ type SyscallTrapInfo struct {
 	Id   SyscallId
 	Args [6]uint64
 	Ret  uint64
+
+	// The entry's "name=value" arguments, decoded according to the syscall's
+	// signature.  String arguments are read from memory at entry since the
+	// memory may be modified by the time the syscall returns.
+	decodedArgs []string
 }
 
-func NewSyscallTrapEntryInfo(registerState registers.State) *SyscallTrapInfo {
+// The memory is used for reading string arguments.
+func NewSyscallTrapEntryInfo(
+	registerState registers.State,
+	mem memoryReader,
+) *SyscallTrapInfo {
 	sysNum := int(registerState.Value(registers.SyscallNum).ToUint32())
 	id, _ := SyscallIdByNumber(sysNum)
 
@@ -27,6 +36,7 @@ func NewSyscallTrapEntryInfo(registerState registers.State) *SyscallTrapInfo {
 		info.Args[idx] = registerState.Value(reg).ToUint64()
 	}
 
+	info.decodedArgs = decodeSyscallArgs(id, info.Args, mem)
 	return info
 }
 
@@ -42,14 +52,32 @@ func NewSyscallTrapExitInfo(registerState registers.State) *SyscallTrapInfo {
 }
 
 func (info SyscallTrapInfo) String() string {
-	result := "syscall " + info.Id.Name
 	if info.IsEntry {
-		result += " entry:"
-		for _, arg := range info.Args {
-			result += fmt.Sprintf(" 0x%x", arg)
-		}
-	} else {
-		result += fmt.Sprintf(" returned: 0x%x", info.Ret)
+		return "syscall entry: " + info.Id.Name +
+			"(" + strings.Join(info.decodedArgs, ", ") + ")"
+	}
+
+	return "syscall exit: " + info.Id.Name +
+		" returned " + formatSyscallReturn(info.Id, info.Ret)
+}
+
+const cloneThreadFlag = 0x00010000 // CLONE_THREAD
+
+// Returns true if the syscall entry creates a new process (as opposed to a
+// new thread).
+//
+// NOTE: clone3's flags are passed in memory and are not inspected.
+func (info SyscallTrapInfo) IsForkEntry() bool {
+	if !info.IsEntry {
+		return false
+	}
+
+	switch info.Id.Name {
+	case "fork", "vfork":
+		return true
+	case "clone":
+		return info.Args[0]&cloneThreadFlag == 0
+	default:
+		return false
 	}
-	return result
 }
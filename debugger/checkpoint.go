@@ -0,0 +1,668 @@
+package debugger
+
+import (
+	"fmt"
+	"slices"
+	"syscall"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/loadedelves"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+	"github.com/pattyshack/bad/ptrace"
+)
+
+// Checkpoint is a frozen copy of the process, forked at the current thread's
+// stop (see Debugger.Checkpoint).  Restarting the checkpoint switches
+// debugging to the copy, which resumes from the checkpointed stop.
+//
+// NOTE: only the process' memory and the checkpointed thread's registers are
+// snapshotted.  In particular:
+//   - other threads are not copied (fork only copies the calling thread).
+//   - open file descriptors are shared with (not snapshotted from) the
+//     original process, e.g., file offsets are not restored.
+//   - external state (files, sockets, pipes, child processes, etc.) is not
+//     snapshotted.
+//   - the copy has a different pid, and is placed in its own process group.
+//   - pthread_atfork handlers are not run since fork is invoked directly via
+//     syscall.
+type Checkpoint struct {
+	Id int64
+
+	// The frozen copy's process id.
+	Pid int
+
+	// The checkpointed thread's status at the time of the checkpoint.
+	Status ThreadStatus
+
+	tracer     *ptrace.Tracer
+	signal     *Signaler
+	waitStatus syscall.WaitStatus
+
+	// The loaded elf files at the time of the checkpoint.
+	files []*loadedelves.File
+}
+
+type Checkpoints struct {
+	nextId      int64
+	checkpoints []*Checkpoint
+}
+
+func (checkpoints *Checkpoints) List() []*Checkpoint {
+	return append([]*Checkpoint{}, checkpoints.checkpoints...)
+}
+
+func (checkpoints *Checkpoints) Get(id int64) (*Checkpoint, bool) {
+	for _, checkpoint := range checkpoints.checkpoints {
+		if checkpoint.Id == id {
+			return checkpoint, true
+		}
+	}
+
+	return nil, false
+}
+
+func (checkpoints *Checkpoints) remove(id int64) {
+	checkpoints.checkpoints = slices.DeleteFunc(
+		checkpoints.checkpoints,
+		func(checkpoint *Checkpoint) bool {
+			return checkpoint.Id == id
+		})
+}
+
+// Forks the process at the current thread's stop, and keeps the forked copy
+// stopped (see Checkpoint for what is / isn't snapshotted).
+//
+// NOTE: the copy is forked with CLONE_PARENT, i.e., the copy is a sibling of
+// the process rather than its child, to avoid delivering SIGCHLD to the
+// process when the copy is discarded.
+func (db *Debugger) Checkpoint() (*Checkpoint, error) {
+	if db.processTerminated() {
+		return nil, fmt.Errorf(
+			"failed to checkpoint process %d: %w",
+			db.Pid,
+			ErrProcessExited)
+	}
+
+	// NOTE: the shared libraries loaded prior to the entry point aren't
+	// tracked yet.
+	if db.entryPointRendezvousSite != nil {
+		return nil, fmt.Errorf(
+			"%w. cannot checkpoint before the process reaches its entry point",
+			ErrInvalidInput)
+	}
+
+	thread := db.currentThread()
+	if !thread.status.Stopped {
+		return nil, fmt.Errorf(
+			"%w. cannot checkpoint. thread %d is not stopped",
+			ErrInvalidInput,
+			thread.Tid)
+	}
+
+	if thread.expectsSyscallExit || thread.status.PendingExitStatus != nil {
+		return nil, fmt.Errorf(
+			"%w. cannot checkpoint. thread %d is in a syscall or exiting",
+			ErrInvalidInput,
+			thread.Tid)
+	}
+
+	err := thread.maybeSwallowInternalSigStop()
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: software stop sites are disabled while forking to ensure the copy's
+	// memory is unpatched (the copy's sites are written on restart), and
+	// hardware stop sites at the current pc are disabled to ensure the
+	// injected syscall instruction is not trapped.
+	sites := db.stopSites.AllocatedSites()
+	sites = slices.DeleteFunc(
+		sites,
+		func(site stoppoint.StopSite) bool {
+			return !site.IsEnabled() ||
+				(site.Type().IsHardware &&
+					site.Address() != thread.status.NextInstructionAddress)
+		})
+
+	err = sites.Disable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint: %w", err)
+	}
+
+	checkpoint, err := thread.fork()
+
+	enableErr := sites.Enable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to checkpoint: %w", err)
+	}
+	if enableErr != nil {
+		_ = checkpoint.kill()
+		return nil, fmt.Errorf("failed to checkpoint: %w", enableErr)
+	}
+
+	db.Checkpoints.nextId++
+	checkpoint.Id = db.Checkpoints.nextId
+	checkpoint.Status = *thread.status
+	checkpoint.files = db.LoadedElves.Files()
+
+	db.Checkpoints.checkpoints = append(
+		db.Checkpoints.checkpoints,
+		checkpoint)
+	return checkpoint, nil
+}
+
+func (thread *ThreadState) fork() (*Checkpoint, error) {
+	options := ptraceOptions(thread.ownsProcess)
+	err := thread.threadTracer.SetOptions(options | ptrace.O_TRACEFORK)
+	if err != nil {
+		return nil, err
+	}
+
+	result, signals, err := thread.injectSyscall(
+		thread.Tid,
+		thread.threadTracer,
+		thread.VirtualMemory,
+		syscall.SYS_CLONE,
+		syscall.CLONE_PARENT|uint64(syscall.SIGCHLD))
+
+	for _, signal := range signals {
+		if thread.SignalPassPolicy.ShouldPass(signal) {
+			thread.heldSignals = append(thread.heldSignals, signal)
+		}
+	}
+
+	optionsErr := thread.threadTracer.SetOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	if optionsErr != nil {
+		return nil, optionsErr
+	}
+
+	if int64(result) < 0 {
+		return nil, fmt.Errorf(
+			"failed to fork process %d: %w",
+			thread.Pid,
+			syscall.Errno(-int64(result)))
+	}
+
+	pid := int(result)
+	checkpoint := &Checkpoint{
+		Pid:    pid,
+		tracer: thread.processTracer.TraceForkedProcess(pid),
+		signal: NewSignaler(pid),
+	}
+
+	err = checkpoint.initialize(thread)
+	if err != nil {
+		_ = checkpoint.kill()
+		return nil, fmt.Errorf(
+			"failed to initialize forked process %d: %w",
+			pid,
+			err)
+	}
+
+	return checkpoint, nil
+}
+
+// Restores the forked copy's registers and patched syscall instruction from
+// the (restored) thread, and moves the copy into its own process group to
+// ensure the copy is not waited on / signaled along with the process.
+func (checkpoint *Checkpoint) initialize(thread *ThreadState) error {
+	waitStatus, err := checkpoint.signal.FromThread(checkpoint.Pid)
+	if err != nil {
+		return err
+	}
+
+	if !waitStatus.Stopped() {
+		return fmt.Errorf(
+			"forked process %d unexpectedly terminated",
+			checkpoint.Pid)
+	}
+	checkpoint.waitStatus = waitStatus
+
+	err = checkpoint.tracer.SetOptions(ptraceOptions(true))
+	if err != nil {
+		return err
+	}
+
+	state, err := thread.Registers.GetState()
+	if err != nil {
+		return err
+	}
+
+	pc := state.ProgramCounter()
+	instruction := make([]byte, len(thread.Arch.SyscallInstruction()))
+	err = readFully(thread.VirtualMemory, pc, instruction)
+	if err != nil {
+		return err
+	}
+
+	mem := memory.New(checkpoint.Pid, checkpoint.tracer)
+	err = writeFully(mem, pc, instruction)
+	if err != nil {
+		return err
+	}
+
+	err = registers.New(checkpoint.tracer).SetState(state)
+	if err != nil {
+		return err
+	}
+
+	result, _, err := thread.injectSyscall(
+		checkpoint.Pid,
+		checkpoint.tracer,
+		mem,
+		syscall.SYS_SETPGID,
+		0,
+		0)
+	if err != nil {
+		return err
+	}
+
+	if result != 0 {
+		return fmt.Errorf(
+			"failed to set process group: %w",
+			syscall.Errno(-int64(result)))
+	}
+
+	return nil
+}
+
+// Kills the forked copy, and waits for its termination.
+func (checkpoint *Checkpoint) kill() error {
+	defer func() {
+		_ = checkpoint.signal.Close()
+	}()
+
+	err := checkpoint.signal.KillToProcess()
+	if err != nil {
+		return err
+	}
+
+	_, err = reapKilled(checkpoint.Pid, checkpoint.tracer, checkpoint.signal)
+	return err
+}
+
+// Waits for the SIGKILL-ed task's termination.  The task may report an exit
+// event stop (see O_TRACEEXIT) before terminating, in which case the task is
+// resumed.
+func reapKilled(
+	tid int,
+	tracer ThreadTracer,
+	signal processSignaler,
+) (
+	syscall.WaitStatus,
+	error,
+) {
+	for {
+		waitStatus, err := signal.FromThread(tid)
+		if err != nil {
+			return 0, err
+		}
+
+		if !waitStatus.Stopped() {
+			return waitStatus, nil
+		}
+
+		err = tracer.Resume(0)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Kills the forked copy, and discards the checkpoint.
+func (db *Debugger) DeleteCheckpoint(id int64) error {
+	checkpoint, ok := db.Checkpoints.Get(id)
+	if !ok {
+		return fmt.Errorf(
+			"%w. checkpoint (id=%d) not found",
+			ErrInvalidInput,
+			id)
+	}
+
+	db.Checkpoints.remove(id)
+	return checkpoint.kill()
+}
+
+func (db *Debugger) discardCheckpoints() error {
+	for _, checkpoint := range db.Checkpoints.List() {
+		err := db.DeleteCheckpoint(checkpoint.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Kills the current process (regardless of whether the debugger owns the
+// process), and switches debugging to the checkpoint's frozen copy, which is
+// stopped at the checkpointed stop.  The checkpoint is consumed by the
+// restart; checkpoint again to keep a copy of the restarted state.
+//
+// Break points, watch points, catch points, displays and settings carry
+// over to the copy.  Scoped watch points' scopes are exited since the
+// process' threads are replaced.
+//
+// NOTE: the checkpoint can't be restarted once the loaded elf files changed
+// (e.g., execve / dlopen / dlclose) since the checkpoint.
+func (db *Debugger) RestartCheckpoint(id int64) (*ThreadStatus, error) {
+	checkpoint, ok := db.Checkpoints.Get(id)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w. checkpoint (id=%d) not found",
+			ErrInvalidInput,
+			id)
+	}
+
+	if !slices.Equal(checkpoint.files, db.LoadedElves.Files()) {
+		return nil, fmt.Errorf(
+			"%w. cannot restart checkpoint (id=%d). "+
+				"loaded elf files changed since the checkpoint",
+			ErrInvalidInput,
+			id)
+	}
+
+	err := db.killProcess()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to kill process %d for restart: %w",
+			db.Pid,
+			err)
+	}
+
+	db.Checkpoints.remove(id)
+
+	_ = db.signal.Close()
+
+	db.Pid = checkpoint.Pid
+	db.ownsProcess = true
+	db.processTracer = checkpoint.tracer
+	db.signal = checkpoint.signal
+	db.VirtualMemory.Rebind(checkpoint.Pid, checkpoint.tracer)
+
+	db.currentTid = checkpoint.Pid
+	db.lastStopStatus = nil
+	db.conditionStopPoint = nil
+
+	thread, err := db.addThread(
+		checkpoint.Pid,
+		checkpoint.tracer.TraceThread(checkpoint.Pid),
+		checkpoint.waitStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	db.signal.ForwardInterruptToProcess()
+
+	// The copy's memory is unpatched (see Checkpoint).  Note that disabling the
+	// software sites writes back the same original bytes.
+	sites := slices.DeleteFunc(
+		db.stopSites.AllocatedSites(),
+		func(site stoppoint.StopSite) bool {
+			return !site.IsEnabled() || site.Type().IsHardware
+		})
+
+	err = sites.Disable()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sites.Enable()
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the copy's debug registers.
+	err = db.stopSites.RefreshSites()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.exitAllWatchPointScopes(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return thread.status, nil
+}
+
+// Kills the process, and removes all its threads.
+func (db *Debugger) killProcess() error {
+	if !db.processTerminated() {
+		err := db.signal.KillToProcess()
+		if err != nil {
+			return err
+		}
+
+		// NOTE: the main thread's termination is only reported once all other
+		// threads are reaped.
+		threads := slices.Clone(db.sortedThreads())
+		slices.SortStableFunc(
+			threads,
+			func(a *ThreadState, b *ThreadState) int {
+				if a.Tid == db.Pid {
+					return 1
+				} else if b.Tid == db.Pid {
+					return -1
+				}
+				return 0
+			})
+
+		for _, thread := range threads {
+			if !thread.status.Stopped && !thread.status.Running() {
+				continue
+			}
+
+			waitStatus, err := reapKilled(
+				thread.Tid,
+				thread.threadTracer,
+				db.signal)
+			if err != nil {
+				return err
+			}
+
+			thread.status = newSimpleWaitingStatus(thread.Tid, waitStatus)
+		}
+	}
+
+	// NOTE: the threads are already gone, and can't be detached.
+	for _, thread := range db.sortedThreads() {
+		delete(db.threads, thread.Tid)
+
+		for _, notify := range db.threadLifeCycleWatchers {
+			notify(thread.status)
+		}
+	}
+	db.threadList = nil
+
+	return nil
+}
+
+// Executes the syscall on behalf of the stopped task (a thread, or a forked
+// copy of the process) by temporarily patching a syscall instruction at the
+// task's program counter, and returns the syscall's return value.  The task's
+// registers and the patched instruction are restored afterward.  Non-trap
+// signals received by the task while executing the syscall are returned for
+// the caller to hold.
+func (db *Debugger) injectSyscall(
+	tid int,
+	tracer ThreadTracer,
+	mem *memory.VirtualMemory,
+	number uint64,
+	args ...uint64,
+) (
+	uint64,
+	[]syscall.Signal,
+	error,
+) {
+	regs := registers.New(tracer)
+	originalState, err := regs.GetState()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// NOTE: the syscall number is passed via the return value register (rax),
+	// and orig_rax is reset to prevent syscall restart.
+	state, err := originalState.WithValue(
+		registers.SyscallRet,
+		registers.U64(number))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	state, err = state.WithValue(
+		registers.SyscallNum,
+		registers.U64(^uint64(0)))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for idx, arg := range args {
+		state, err = state.WithValue(
+			registers.SyscallArgs[idx],
+			registers.U64(arg))
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	pc := originalState.ProgramCounter()
+	instruction := db.Arch.SyscallInstruction()
+	original := make([]byte, len(instruction))
+	err = readFully(mem, pc, original)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = writeFully(mem, pc, instruction)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	result, signals, err := db.stepInjectedSyscall(tid, tracer, regs, state)
+
+	restoreErr := writeFully(mem, pc, original)
+	if restoreErr == nil {
+		restoreErr = regs.SetState(originalState)
+	}
+
+	if err != nil {
+		return 0, nil, err
+	}
+	if restoreErr != nil {
+		return 0, nil, restoreErr
+	}
+
+	return result, signals, nil
+}
+
+func (db *Debugger) stepInjectedSyscall(
+	tid int,
+	tracer ThreadTracer,
+	regs *registers.Registers,
+	state registers.State,
+) (
+	uint64,
+	[]syscall.Signal,
+	error,
+) {
+	err := regs.SetState(state)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	signals := []syscall.Signal{}
+	for {
+		err := tracer.SingleStep()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		waitStatus, err := db.signal.FromThread(tid)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if !waitStatus.Stopped() {
+			return 0, nil, fmt.Errorf(
+				"task %d unexpectedly terminated during injected syscall",
+				tid)
+		}
+
+		if waitStatus.StopSignal() != syscall.SIGTRAP {
+			// The signal is delivered before the instruction is stepped.  Retry
+			// the step.
+			signals = append(signals, waitStatus.StopSignal())
+			continue
+		}
+
+		// ptrace event stops (e.g., the fork event) are reported in the middle
+		// of the syscall.
+		if waitStatus.TrapCause() == 0 {
+			break
+		}
+	}
+
+	result, err := regs.GetState()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	expected := state.ProgramCounter() +
+		VirtualAddress(len(db.Arch.SyscallInstruction()))
+	if result.ProgramCounter() != expected {
+		return 0, nil, fmt.Errorf(
+			"injected syscall stopped at unexpected address %s (expected %s)",
+			result.ProgramCounter(),
+			expected)
+	}
+
+	return result.Value(registers.SyscallRet).ToUint64(), signals, nil
+}
+
+func readFully(
+	mem *memory.VirtualMemory,
+	address VirtualAddress,
+	out []byte,
+) error {
+	n, err := mem.Read(address, out)
+	if err != nil {
+		return err
+	}
+
+	if n != len(out) {
+		return fmt.Errorf(
+			"failed to read memory at %s. read %d out of %d bytes",
+			address,
+			n,
+			len(out))
+	}
+
+	return nil
+}
+
+func writeFully(
+	mem *memory.VirtualMemory,
+	address VirtualAddress,
+	data []byte,
+) error {
+	n, err := mem.Write(address, data)
+	if err != nil {
+		return err
+	}
+
+	if n != len(data) {
+		return fmt.Errorf(
+			"failed to write memory at %s. wrote %d out of %d bytes",
+			address,
+			n,
+			len(data))
+	}
+
+	return nil
+}
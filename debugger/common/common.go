@@ -6,7 +6,10 @@ import (
 
 var (
 	ErrInvalidInput              = fmt.Errorf("invalid input")
+	ErrVariableNotFound          = fmt.Errorf("%w. variable not found", ErrInvalidInput)
 	ErrProcessExited             = fmt.Errorf("process exited")
+	ErrCallTimedOut              = fmt.Errorf("function call timed out")
+	ErrCallUnwound               = fmt.Errorf("function call unwound")
 	ErrRendezvousAddressNotFound = fmt.Errorf(
 		"dynamic linker rendezvous address not found")
 )
@@ -23,6 +26,22 @@ const (
 	// A debugger internal software trap that should not be exposed to the user
 	RendezvousTrap = TrapKind("rendezvous trap")
 	CloneTrap      = TrapKind("clone")
+
+	// The thread is about to exit (the exit status is available, but the
+	// thread has not fully disappeared yet).
+	ExitTrap = TrapKind("exit")
+
+	// The thread successfully executed a new program (execve).  The process'
+	// address space has been replaced.
+	ExecTrap = TrapKind("exec")
+
+	// A debugger internal software trap on a c++ exception catch point.
+	ExceptionTrap = TrapKind("exception")
+
+	// A debugger internal trap used for tracking scoped watch points' frames
+	// (e.g., a software trap on the frame's return address).  This should not
+	// be exposed to the user.
+	WatchPointScopeTrap = TrapKind("watch point scope")
 )
 
 func TrapCodeToKind(code int32) TrapKind {
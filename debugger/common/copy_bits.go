@@ -0,0 +1,21 @@
+package common
+
+// Copy bitSize bits from src (starting at srcBitOffset) into dst (starting at
+// dstBitOffset).  Bits in dst outside of the copied range are unmodified.
+func CopyBits(
+	dst []byte,
+	dstBitOffset int,
+	src []byte,
+	srcBitOffset int,
+	bitSize int,
+) {
+	for i := 0; i < bitSize; i++ {
+		srcPos := srcBitOffset + i
+		dstPos := dstBitOffset + i
+
+		bit := (src[srcPos/8] >> (srcPos % 8)) & 1
+
+		dst[dstPos/8] &^= 1 << (dstPos % 8)
+		dst[dstPos/8] |= bit << (dstPos % 8)
+	}
+}
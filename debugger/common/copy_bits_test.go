@@ -0,0 +1,26 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+)
+
+type CopyBitsSuite struct{}
+
+func TestCopyBits(t *testing.T) {
+	suite.RunTests(t, &CopyBitsSuite{})
+}
+
+func (CopyBitsSuite) TestByteAligned(t *testing.T) {
+	dst := []byte("Hello sam")
+	CopyBits(dst, 6*8, []byte(" bob "), 8, 3*8)
+	expect.Equal(t, "Hello bob", string(dst))
+}
+
+func (CopyBitsSuite) TestMisalignedBits(t *testing.T) {
+	dst := []byte{0b1111_1111, 0b1111_1111}
+	CopyBits(dst, 6, []byte{0b0101_0000}, 3, 5)
+	expect.Equal(t, []byte{0b1011_1111, 0b1111_1010}, dst)
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// The radix used for printing integer values (print/x, print/d, print/o and
+// print/t).
+type IntegerFormat byte
+
+const (
+	// The value's natural representation (e.g., chars are quoted, enums are
+	// printed as enumerator names)
+	NaturalFormat = IntegerFormat(0)
+
+	HexFormat     = IntegerFormat('x')
+	DecimalFormat = IntegerFormat('d')
+	OctalFormat   = IntegerFormat('o')
+	BinaryFormat  = IntegerFormat('t')
+)
+
+func ParseIntegerFormat(letter byte) (IntegerFormat, bool) {
+	switch format := IntegerFormat(letter); format {
+	case HexFormat, DecimalFormat, OctalFormat, BinaryFormat:
+		return format, true
+	default:
+		return NaturalFormat, false
+	}
+}
+
+// Formats the little endian encoded integer.  Hex, octal and binary outputs
+// are zero padded to the content's bit width (e.g., a 4 bytes value is
+// printed as 32 binary digits).  Decimal output is sign-extended when
+// isSigned is true.  NaturalFormat is treated as DecimalFormat.
+func (format IntegerFormat) FormatBytes(content []byte, isSigned bool) string {
+	bigEndian := make([]byte, len(content))
+	for idx, b := range content {
+		bigEndian[len(content)-1-idx] = b
+	}
+
+	value := new(big.Int).SetBytes(bigEndian)
+	numBits := 8 * len(content)
+
+	switch format {
+	case HexFormat:
+		return "0x" + padDigits(value.Text(16), (numBits+3)/4)
+	case OctalFormat:
+		return "0" + padDigits(value.Text(8), (numBits+2)/3)
+	case BinaryFormat:
+		return "0b" + padDigits(value.Text(2), numBits)
+	default:
+		if isSigned && numBits > 0 && value.Bit(numBits-1) == 1 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(numBits)))
+		}
+		return value.Text(10)
+	}
+}
+
+func (format IntegerFormat) String() string {
+	if format == NaturalFormat {
+		return "natural"
+	}
+	return fmt.Sprintf("/%c", byte(format))
+}
+
+func padDigits(digits string, width int) string {
+	if len(digits) >= width {
+		return digits
+	}
+	return strings.Repeat("0", width-len(digits)) + digits
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+)
+
+type IntegerFormatSuite struct{}
+
+func TestIntegerFormat(t *testing.T) {
+	suite.RunTests(t, &IntegerFormatSuite{})
+}
+
+func (IntegerFormatSuite) TestParse(t *testing.T) {
+	format, ok := ParseIntegerFormat('t')
+	expect.True(t, ok)
+	expect.Equal(t, BinaryFormat, format)
+
+	format, ok = ParseIntegerFormat('o')
+	expect.True(t, ok)
+	expect.Equal(t, OctalFormat, format)
+
+	_, ok = ParseIntegerFormat('q')
+	expect.False(t, ok)
+}
+
+func (IntegerFormatSuite) TestBinary(t *testing.T) {
+	expect.Equal(t, "0b00000101", BinaryFormat.FormatBytes([]byte{5}, false))
+	expect.Equal(
+		t,
+		"0b00000000000000000000000100000011",
+		BinaryFormat.FormatBytes([]byte{3, 1, 0, 0}, false))
+}
+
+func (IntegerFormatSuite) TestOctal(t *testing.T) {
+	expect.Equal(t, "0000", OctalFormat.FormatBytes([]byte{0}, false))
+	expect.Equal(
+		t,
+		"000000000755",
+		OctalFormat.FormatBytes([]byte{0xed, 0x01, 0, 0}, false))
+	expect.Equal(
+		t,
+		"01777777777777777777777",
+		OctalFormat.FormatBytes(
+			[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			false))
+}
+
+func (IntegerFormatSuite) TestHex(t *testing.T) {
+	expect.Equal(t, "0x00ff", HexFormat.FormatBytes([]byte{0xff, 0}, false))
+	expect.Equal(
+		t,
+		"0x000000000000000000000000000000ab",
+		HexFormat.FormatBytes(append([]byte{0xab}, make([]byte, 15)...), false))
+}
+
+func (IntegerFormatSuite) TestDecimal(t *testing.T) {
+	expect.Equal(t, "255", DecimalFormat.FormatBytes([]byte{0xff}, false))
+	expect.Equal(t, "-1", DecimalFormat.FormatBytes([]byte{0xff}, true))
+	expect.Equal(t, "127", DecimalFormat.FormatBytes([]byte{0x7f}, true))
+	expect.Equal(
+		t,
+		"-2",
+		DecimalFormat.FormatBytes([]byte{0xfe, 0xff, 0xff, 0xff}, true))
+}
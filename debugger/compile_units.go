@@ -0,0 +1,506 @@
+package debugger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/loadedelves"
+	"github.com/pattyshack/bad/dwarf"
+	"github.com/pattyshack/bad/elf"
+)
+
+var (
+	gccProducerPattern = regexp.MustCompile(`^GNU [^ ]+ (\d+)\.(\d+)`)
+	iccProducerPattern = regexp.MustCompile(`^Intel\(R\) .* Version (\d+)\.`)
+)
+
+type knownProducerIssue struct {
+	isAffected func(producer string) bool
+	issue      string
+}
+
+// Compiler versions which are known to emit inaccurate debug info.
+var knownProducerIssues = []knownProducerIssue{
+	{
+		isAffected: func(producer string) bool {
+			major, minor, ok := parseProducerVersion(gccProducerPattern, producer)
+			return ok && (major < 4 || (major == 4 && minor < 5))
+		},
+		issue: "gcc < 4.5 may emit inaccurate address ranges (DW_AT_high_pc) " +
+			"and variable locations",
+	},
+	{
+		isAffected: func(producer string) bool {
+			major, _, ok := parseProducerVersion(iccProducerPattern, producer)
+			return ok && major < 14
+		},
+		issue: "icc < 14 omits DW_AT_declaration on incomplete types",
+	},
+}
+
+func parseProducerVersion(
+	pattern *regexp.Regexp,
+	producer string,
+) (
+	int,
+	int,
+	bool,
+) {
+	match := pattern.FindStringSubmatch(producer)
+	if match == nil {
+		return 0, 0, false
+	}
+
+	major, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	minor := 0
+	if len(match) > 2 {
+		minor, err = strconv.Atoi(match[2])
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+
+	return major, minor, true
+}
+
+// Returns a description of the producer's known debug info issue, or empty
+// string if there's no known issue.
+func producerIssue(producer string) string {
+	for _, known := range knownProducerIssues {
+		if known.isAffected(producer) {
+			return known.issue
+		}
+	}
+
+	return ""
+}
+
+type CompileUnitInfo struct {
+	// The loaded elf file's name (empty for the executable)
+	ElfFileName string
+
+	Name     string
+	Producer string
+
+	// Non-empty if the producer has known debug info issues.
+	KnownIssue string
+}
+
+func (info CompileUnitInfo) String() string {
+	name := info.Name
+	if info.ElfFileName != "" {
+		name = info.ElfFileName + "|" + name
+	}
+
+	producer := info.Producer
+	if producer == "" {
+		producer = "<unknown producer>"
+	}
+
+	result := fmt.Sprintf("%s (producer: %s)", name, producer)
+	if info.KnownIssue != "" {
+		result += " (known issue: " + info.KnownIssue + ")"
+	}
+
+	return result
+}
+
+// Returns compile units from all loaded elf files (with debug info).
+func (db *Debugger) ListCompileUnits() ([]CompileUnitInfo, error) {
+	result := []CompileUnitInfo{}
+	for _, file := range db.LoadedElves.Files() {
+		units, err := listFileCompileUnits(file)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, units...)
+	}
+
+	return result, nil
+}
+
+func listFileCompileUnits(file *loadedelves.File) ([]CompileUnitInfo, error) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	result := []CompileUnitInfo{}
+	for _, unit := range file.Dwarf.CompileUnits {
+		root, err := unit.Root()
+		if err != nil {
+			return nil, err
+		}
+
+		name, _, err := root.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		producer, err := unit.Producer()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(
+			result,
+			CompileUnitInfo{
+				ElfFileName: file.FileName,
+				Name:        name,
+				Producer:    producer,
+				KnownIssue:  producerIssue(producer),
+			})
+	}
+
+	return result, nil
+}
+
+// Returns warnings for newly loaded compile units produced by compilers with
+// known debug info issues.  Each producer is only reported once.  This
+// returns nil when producer warnings are suppressed.
+func (db *Debugger) NewProducerWarnings() ([]string, error) {
+	if db.SuppressProducerWarnings {
+		return nil, nil
+	}
+
+	warnings := []string{}
+	for _, file := range db.LoadedElves.Files() {
+		_, ok := db.producerCheckedFiles[file]
+		if ok {
+			continue
+		}
+		db.producerCheckedFiles[file] = struct{}{}
+
+		units, err := listFileCompileUnits(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unit := range units {
+			if unit.KnownIssue == "" {
+				continue
+			}
+
+			_, ok := db.warnedProducers[unit.Producer]
+			if ok {
+				continue
+			}
+			db.warnedProducers[unit.Producer] = struct{}{}
+
+			warnings = append(
+				warnings,
+				fmt.Sprintf(
+					"%s was compiled by %s. debug info may be inaccurate: %s",
+					unit.Name,
+					unit.Producer,
+					unit.KnownIssue))
+		}
+	}
+
+	return warnings, nil
+}
+
+// Returns reports on whether newly loaded shared libraries without embedded
+// debug info got separate debug info (see
+// loadedelves.Files.LoadSeparateDebugInfo).  Each library is only reported
+// once.
+func (db *Debugger) NewDebugInfoReports() []string {
+	reports := []string{}
+	for _, file := range db.LoadedElves.Files() {
+		if file.FileName == "" || file.FileName == loadedelves.VDSOFileName {
+			continue
+		}
+
+		_, ok := db.debugInfoReportedFiles[file]
+		if ok {
+			continue
+		}
+		db.debugInfoReportedFiles[file] = struct{}{}
+
+		if file.DebugFilePath != "" {
+			reports = append(
+				reports,
+				fmt.Sprintf(
+					"loaded debug info for %s from %s",
+					file.FileName,
+					file.DebugFilePath))
+		} else if file.Dwarf == nil {
+			reports = append(
+				reports,
+				fmt.Sprintf("no debug info found for %s", file.FileName))
+		}
+	}
+
+	return reports
+}
+
+// A compile unit's code address ranges, aggregated from its function
+// definitions' and line table sequences' address ranges.
+type CompileUnitMapping struct {
+	Name   string
+	Ranges AddressRanges
+}
+
+// An executable section's address range not covered by any compile unit.
+type UnmappedCodeRange struct {
+	SectionName string
+	AddressRange
+}
+
+// A loaded elf file's code regions, correlated to its compile units.
+type ModuleMapping struct {
+	// The loaded elf file's name (empty for the executable)
+	ElfFileName string
+	LoadBias    uint64
+
+	HasDebugInfo bool
+
+	CompileUnits []CompileUnitMapping
+
+	// Executable section ranges without debug info.
+	Unmapped []UnmappedCodeRange
+}
+
+// Returns the code address ranges covered by each loaded elf file's compile
+// units, along with the executable section ranges not covered by any compile
+// unit (i.e., code without debug info).
+func (db *Debugger) ListModuleMappings() ([]ModuleMapping, error) {
+	result := []ModuleMapping{}
+	for _, file := range db.LoadedElves.Files() {
+		mapping, err := newModuleMapping(file)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to compute %s mappings: %w",
+				file.FileName,
+				err)
+		}
+
+		result = append(result, mapping)
+	}
+
+	return result, nil
+}
+
+func newModuleMapping(file *loadedelves.File) (ModuleMapping, error) {
+	mapping := ModuleMapping{
+		ElfFileName:  file.FileName,
+		LoadBias:     file.LoadBias,
+		HasDebugInfo: file.Dwarf != nil,
+	}
+
+	covered := AddressRanges{}
+	if file.Dwarf != nil {
+		for _, unit := range file.Dwarf.CompileUnits {
+			unitMapping, err := newCompileUnitMapping(file, unit)
+			if err != nil {
+				return ModuleMapping{}, err
+			}
+
+			if len(unitMapping.Ranges) == 0 {
+				continue
+			}
+
+			mapping.CompileUnits = append(mapping.CompileUnits, unitMapping)
+			covered = append(covered, unitMapping.Ranges...)
+		}
+	}
+
+	sort.Slice(
+		mapping.CompileUnits,
+		func(i int, j int) bool {
+			return mapping.CompileUnits[i].Ranges[0].Low <
+				mapping.CompileUnits[j].Ranges[0].Low
+		})
+
+	covered = mergeAddressRanges(covered)
+
+	for _, section := range file.Sections {
+		header := section.Header()
+		if header.SectionFlags&elf.SectionContainsInstructions == 0 ||
+			header.SectionFlags&elf.SectionOccupiesMemory == 0 ||
+			header.Size == 0 {
+
+			continue
+		}
+
+		low := file.ToVirtualAddress(elf.FileAddress(header.Address))
+		high := low + VirtualAddress(header.Size)
+		for _, uncovered := range subtractAddressRanges(low, high, covered) {
+			mapping.Unmapped = append(
+				mapping.Unmapped,
+				UnmappedCodeRange{
+					SectionName:  section.Name(),
+					AddressRange: uncovered,
+				})
+		}
+	}
+
+	return mapping, nil
+}
+
+func newCompileUnitMapping(
+	file *loadedelves.File,
+	unit *dwarf.CompileUnit,
+) (
+	CompileUnitMapping,
+	error,
+) {
+	root, err := unit.Root()
+	if err != nil {
+		return CompileUnitMapping{}, err
+	}
+
+	name, _, err := root.Name()
+	if err != nil {
+		return CompileUnitMapping{}, err
+	}
+
+	ranges := AddressRanges{}
+	addFileRanges := func(fileRanges dwarf.AddressRanges) {
+		for _, fileRange := range fileRanges {
+			if fileRange.Low >= fileRange.High {
+				continue
+			}
+
+			ranges = append(
+				ranges,
+				AddressRange{
+					Low:  file.ToVirtualAddress(fileRange.Low),
+					High: file.ToVirtualAddress(fileRange.High),
+				})
+		}
+	}
+
+	err = unit.ForEach(
+		func(entry *dwarf.DebugInfoEntry) error {
+			if entry.Tag != dwarf.DW_TAG_subprogram {
+				return nil
+			}
+
+			fileRanges, err := entry.AddressRanges()
+			if err != nil {
+				return err
+			}
+
+			addFileRanges(fileRanges)
+			return nil
+		})
+	if err != nil {
+		return CompileUnitMapping{}, err
+	}
+
+	// Each line table sequence covers [first entry, end sequence entry).
+	var entry *dwarf.LineEntry
+	_, hasLineTable := root.Offset(dwarf.DW_AT_stmt_list)
+	if hasLineTable {
+		entry, err = unit.LineIterator()
+		if err != nil {
+			return CompileUnitMapping{}, err
+		}
+	}
+
+	var sequenceStart *dwarf.LineEntry
+	for entry != nil {
+		if sequenceStart == nil {
+			sequenceStart = entry
+		}
+
+		if entry.EndSequence {
+			addFileRanges(
+				dwarf.AddressRanges{
+					{
+						Low:  sequenceStart.FileAddress,
+						High: entry.FileAddress,
+					},
+				})
+			sequenceStart = nil
+		}
+
+		entry, err = entry.Next()
+		if err != nil {
+			return CompileUnitMapping{}, err
+		}
+	}
+
+	return CompileUnitMapping{
+		Name:   name,
+		Ranges: mergeAddressRanges(ranges),
+	}, nil
+}
+
+// Returns the sorted union of the ranges, with overlapping / adjacent ranges
+// merged.
+func mergeAddressRanges(ranges AddressRanges) AddressRanges {
+	sorted := make(AddressRanges, len(ranges))
+	copy(sorted, ranges)
+
+	sort.Slice(
+		sorted,
+		func(i int, j int) bool {
+			return sorted[i].Low < sorted[j].Low
+		})
+
+	result := AddressRanges{}
+	for _, addrRange := range sorted {
+		if len(result) > 0 && addrRange.Low <= result[len(result)-1].High {
+			last := &result[len(result)-1]
+			if addrRange.High > last.High {
+				last.High = addrRange.High
+			}
+			continue
+		}
+
+		result = append(result, addrRange)
+	}
+
+	return result
+}
+
+// Returns the portions of [low, high) not covered by the merged ranges.
+func subtractAddressRanges(
+	low VirtualAddress,
+	high VirtualAddress,
+	merged AddressRanges,
+) AddressRanges {
+	result := AddressRanges{}
+	for _, addrRange := range merged {
+		if addrRange.High <= low {
+			continue
+		}
+		if addrRange.Low >= high {
+			break
+		}
+
+		if low < addrRange.Low {
+			result = append(
+				result,
+				AddressRange{
+					Low:  low,
+					High: addrRange.Low,
+				})
+		}
+
+		low = addrRange.High
+		if low >= high {
+			return result
+		}
+	}
+
+	if low < high {
+		result = append(
+			result,
+			AddressRange{
+				Low:  low,
+				High: high,
+			})
+	}
+
+	return result
+}
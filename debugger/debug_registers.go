@@ -0,0 +1,67 @@
+package debugger
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+type DebugRegistersInfo struct {
+	Tid int
+
+	stoppoint.DebugRegisters
+
+	// Indexed by debug address register slot.  The site is nil if the slot is
+	// unoccupied.
+	Sites [stoppoint.NumHardwareStopSites]stoppoint.StopSite
+
+	// Indexed by debug address register slot.  The break / watch points which
+	// use the slot's site.  Internal sites (e.g., watch point scope return
+	// sites) are not associated with any stop point.
+	StopPoints [stoppoint.NumHardwareStopSites][]*stoppoint.StopPoint
+}
+
+// Decodes the current thread's debug registers, and maps each debug address
+// register slot to its installed hardware stop site.
+//
+// NOTE: the debug address / control registers are identical across threads,
+// but the debug status register is per thread.
+func (db *Debugger) DebugRegisters() (*DebugRegistersInfo, error) {
+	if db.processTerminated() {
+		return nil, fmt.Errorf(
+			"failed to read debug registers: %w",
+			ErrProcessExited)
+	}
+
+	thread := db.currentThread()
+	state, err := thread.Registers.GetState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debug registers: %w", err)
+	}
+
+	info := &DebugRegistersInfo{
+		Tid:            thread.Tid,
+		DebugRegisters: stoppoint.DecodeDebugRegisters(state),
+	}
+
+	points := append(db.BreakPoints.List(), db.WatchPoints.List()...)
+	for idx := range info.Sites {
+		site := db.stopSites.HardwareStopSiteAt(idx)
+		if site == nil {
+			continue
+		}
+
+		info.Sites[idx] = site
+		for _, point := range points {
+			for _, pointSite := range point.Sites() {
+				if pointSite == site {
+					info.StopPoints[idx] = append(info.StopPoints[idx], point)
+					break
+				}
+			}
+		}
+	}
+
+	return info, nil
+}
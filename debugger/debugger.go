@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"sort"
 	"syscall"
+	"time"
 
+	"github.com/pattyshack/bad/debugger/arch"
 	"github.com/pattyshack/bad/debugger/catchpoint"
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/expression"
@@ -20,12 +23,18 @@ import (
 	"github.com/pattyshack/bad/ptrace"
 )
 
+const DefaultCallTimeout = 10 * time.Second
+
+const DefaultStepLimit = 1000000
+
 type Debugger struct {
 	Pid           int
 	ownsProcess   bool
 	processTracer *ptrace.Tracer
 
-	signal *Signaler
+	Arch arch.Arch
+
+	signal processSignaler
 
 	LoadedElves *loadedelves.Files
 	*SourceFiles
@@ -44,51 +53,204 @@ type Debugger struct {
 
 	SyscallCatchPolicy *catchpoint.SyscallCatchPolicy
 
+	SignalPassPolicy  *SignalPassPolicy
+	SignalCatchPolicy *SignalCatchPolicy
+
+	// When true, exec events stop the process.  Enabled by default.
+	CatchExec bool
+
+	// When true, the process stops when a fork / vfork / (non-thread) clone
+	// syscall returns in the parent.  Disabled by default.
+	//
+	// NOTE: the child process is not traced.
+	CatchFork bool
+
+	// Internal break points on the c++ runtime's exception functions.
+	exceptionCatchPoints      *stoppoint.StopPointSet
+	exceptionCatchPointEvents map[int64]catchpoint.ExceptionEvent
+
 	EvaluatedResults *expression.EvaluatedResultPool
 
+	SourceTrace *SourceTrace
+
+	// Memory regions diffed (without stopping the process) on every stop.
+	MemoryWatches *MemoryWatches
+
+	// Expressions evaluated (and printed) on every stop.
+	Displays *Displays
+
+	// Frozen copies of the process (see Checkpoint).
+	Checkpoints *Checkpoints
+
+	// When true, threads stopped by PTRACE_EVENT_EXIT (i.e., ExitTrap) are
+	// reported to the user, which gives the user a chance to inspect the
+	// thread's final state before the thread is gone.  Disabled by default.
+	StopOnExit bool
+
+	FollowExecMode FollowExecMode
+
+	// Controls how frame arguments are rendered in backtraces.  Defaults to
+	// FrameArgumentsScalars.
+	FrameArgumentsMode FrameArgumentsMode
+
+	// The maximum duration of an inferior function call.  The call is aborted,
+	// and the thread's state is restored, when the call does not return in
+	// time.  Zero disables the timeout.  Defaults to DefaultCallTimeout.
+	CallTimeout time.Duration
+
+	// When true, an inferior function call which terminates with an uncaught
+	// exception (i.e., the call reaches std::terminate) is aborted, and the
+	// thread's state is restored.  Otherwise, std::terminate runs, which aborts
+	// the process by default.  Enabled by default.
+	UnwindOnTerminate bool
+
+	// When true, all enabled break points are disabled while a stop point's
+	// condition calls functions.  Otherwise, only the stop point whose condition
+	// is evaluated is disabled.  Disabled by default.
+	//
+	// NOTE: stop points triggered by condition function calls are silently
+	// skipped regardless of this setting.  Disabling all break points avoids
+	// trapping into the debugger on every skipped hit, at the cost of patching
+	// every break site twice per call.
+	DisableAllBreakPointsInConditionCalls bool
+
+	// The maximum number of single steps (instruction steps / resumes to an
+	// address) taken by a single step in / step over.  When the limit is
+	// reached, stepping stops on the current instruction (see
+	// ThreadStatus.ExhaustedStepLimit).  This guards against apparent hangs
+	// when stepping through tight same-line loops or code without line
+	// information.  Zero disables the limit.  Defaults to DefaultStepLimit.
+	StepLimit int
+
+	// When true, compile units produced by compilers with known debug info
+	// issues are not reported by NewProducerWarnings.
+	SuppressProducerWarnings bool
+
+	// Controls whether scoped watch points are removed once their frames
+	// return.  Defaults to WatchPointScopeAuto.
+	WatchPointScopeMode WatchPointScopeMode
+
+	// The maximum number of differences reported by CompareMemory /
+	// CompareMemoryWithBytes.  Zero disables the limit.  Defaults to
+	// DefaultMemoryCompareLimit.
+	MemoryCompareLimit int
+
+	// Instruction length mismatches detected while stepping, which have not
+	// been reported yet (see NewStepWarnings).
+	stepWarnings []string
+
+	producerCheckedFiles map[*loadedelves.File]struct{}
+	warnedProducers      map[string]struct{}
+
+	debugInfoReportedFiles map[*loadedelves.File]struct{}
+
 	entryPointRendezvousSite stoppoint.StopSite
 	rendezvousNotifySite     stoppoint.StopSite
 	rendezvousAddresses      map[VirtualAddress]struct{}
 
+	// True once a thread destroyed by execve is released, until the exec event
+	// is reported (see preprocessExecEvent).
+	isExecInProgress bool
+
+	// Keyed by watch point id.
+	watchPointScopes        map[int64]*watchPointScope
+	watchPointScopeWatchers []func(WatchPointScopeExit)
+
+	// The stop point whose condition is being evaluated, or nil.
+	conditionStopPoint *stoppoint.StopPoint
+
 	currentTid int
 	threads    map[int]*ThreadState
 
+	// The focused thread's status reported by the last resume / step.  nil
+	// if the process has not been resumed yet.
+	lastStopStatus *ThreadStatus
+
+	// Threads sorted by tid.  This is nil when the list is invalidated by
+	// thread creation / exit, and is lazily rebuilt by sortedThreads.
+	threadList []*ThreadState
+
 	threadLifeCycleWatchers []func(*ThreadStatus)
+
+	sharedLibraryEvents   []SharedLibraryEvent
+	sharedLibraryWatchers []func(SharedLibraryEvent)
 }
 
-func newDebugger(
+// Returns the debugger's initial state, without any thread.  processTracer is
+// nil when the process is faked (e.g., in unit tests).
+func newDebuggerState(
+	pid int,
 	processTracer *ptrace.Tracer,
+	mem *memory.VirtualMemory,
+	signaler processSignaler,
 	ownsProcess bool,
-) (
-	*Debugger,
-	error,
-) {
-	mem := memory.New(processTracer)
+) *Debugger {
 	loadedElves := loadedelves.NewFiles(mem)
 
 	db := &Debugger{
-		Pid:                     processTracer.Pid,
-		ownsProcess:             ownsProcess,
-		processTracer:           processTracer,
-		signal:                  NewSignaler(processTracer.Pid),
-		LoadedElves:             loadedElves,
-		SourceFiles:             NewSourceFiles(),
-		VirtualMemory:           mem,
-		descriptorPool:          expression.NewDataDescriptorPool(loadedElves, mem),
-		StopSiteResolverFactory: stoppoint.NewStopSiteResolverFactory(loadedElves),
-		SyscallCatchPolicy:      catchpoint.NewSyscallCatchPolicy(),
-		EvaluatedResults:        &expression.EvaluatedResultPool{},
-		rendezvousAddresses:     map[VirtualAddress]struct{}{},
-		currentTid:              processTracer.Pid,
-		threads:                 map[int]*ThreadState{},
-	}
-
-	stopSites := stoppoint.NewStopSitePool(db)
+		Pid:           pid,
+		Arch:          arch.AMD64,
+		ownsProcess:   ownsProcess,
+		processTracer: processTracer,
+		signal:        signaler,
+		LoadedElves:   loadedElves,
+		SourceFiles:   NewSourceFiles(),
+		VirtualMemory: mem,
+		descriptorPool: expression.NewDataDescriptorPool(
+			loadedElves,
+			mem,
+			arch.AMD64),
+		StopSiteResolverFactory:   stoppoint.NewStopSiteResolverFactory(loadedElves),
+		SyscallCatchPolicy:        catchpoint.NewSyscallCatchPolicy(),
+		SignalPassPolicy:          NewSignalPassPolicy(),
+		SignalCatchPolicy:         NewSignalCatchPolicy(),
+		CatchExec:                 true,
+		exceptionCatchPointEvents: map[int64]catchpoint.ExceptionEvent{},
+		EvaluatedResults:          &expression.EvaluatedResultPool{},
+		SourceTrace:               &SourceTrace{},
+		MemoryWatches:             &MemoryWatches{},
+		Displays:                  &Displays{},
+		Checkpoints:               &Checkpoints{},
+		FollowExecMode:            FollowExecSame,
+		FrameArgumentsMode:        FrameArgumentsScalars,
+		CallTimeout:               DefaultCallTimeout,
+		UnwindOnTerminate:         true,
+		StepLimit:                 DefaultStepLimit,
+		WatchPointScopeMode:       WatchPointScopeAuto,
+		MemoryCompareLimit:        DefaultMemoryCompareLimit,
+		producerCheckedFiles:      map[*loadedelves.File]struct{}{},
+		warnedProducers:           map[string]struct{}{},
+		debugInfoReportedFiles:    map[*loadedelves.File]struct{}{},
+		rendezvousAddresses:       map[VirtualAddress]struct{}{},
+		watchPointScopes:          map[int64]*watchPointScope{},
+		currentTid:                pid,
+		threads:                   map[int]*ThreadState{},
+	}
+
+	stopSites := stoppoint.NewStopSitePool(db, db.Arch)
 
 	db.stopSites = stopSites
 	db.BreakPoints = stoppoint.NewBreakPointSet(stopSites)
 	db.WatchPoints = stoppoint.NewWatchPointSet(stopSites)
-	db.Disassembler = memory.NewDisassembler(mem, stopSites)
+	db.exceptionCatchPoints = stoppoint.NewBreakPointSet(stopSites)
+	db.Disassembler = memory.NewDisassembler(mem, stopSites, db.Arch)
+
+	return db
+}
+
+func newDebugger(
+	processTracer *ptrace.Tracer,
+	ownsProcess bool,
+) (
+	*Debugger,
+	error,
+) {
+	db := newDebuggerState(
+		processTracer.Pid,
+		processTracer,
+		memory.New(processTracer.Pid, processTracer),
+		NewSignaler(processTracer.Pid),
+		ownsProcess)
 
 	if !ownsProcess {
 		// Sig stop the process to prevent threads creation / termination while
@@ -128,10 +290,7 @@ func newDebugger(
 			err)
 	}
 
-	options := ptrace.O_TRACESYSGOOD | ptrace.O_TRACECLONE
-	if ownsProcess {
-		options |= ptrace.O_EXITKILL
-	}
+	options := ptraceOptions(ownsProcess)
 
 	for _, tid := range existingTids {
 		var threadTracer *ptrace.Tracer
@@ -184,6 +343,11 @@ func newDebugger(
 
 	db.signal.ForwardInterruptToProcess()
 
+	// NOTE: a started process' initial stop is the post-execve stop, i.e., the
+	// executable is mapped (and its load bias is known) even though the
+	// program has not executed any instruction.  Hence, stop points set prior
+	// to the first resume are resolved against the real image.  Shared library
+	// stop points are resolved at the entry point rendezvous stop.
 	entryPointSite, err := db.stopSites.Allocate(
 		db.LoadedElves.EntryPoint(),
 		stoppoint.NewBreakSiteType(false))
@@ -204,6 +368,18 @@ func newDebugger(
 	return db, nil
 }
 
+func ptraceOptions(ownsProcess bool) ptrace.Options {
+	options := ptrace.O_TRACESYSGOOD |
+		ptrace.O_TRACECLONE |
+		ptrace.O_TRACEEXIT |
+		ptrace.O_TRACEEXEC
+	if ownsProcess {
+		options |= ptrace.O_EXITKILL
+	}
+
+	return options
+}
+
 func AttachTo(pid int) (*Debugger, error) {
 	tracer, err := ptrace.AttachToProcess(pid)
 	if err != nil {
@@ -222,6 +398,17 @@ func StartAndAttachTo(cmd *exec.Cmd) (*Debugger, error) {
 	return newDebugger(tracer, true)
 }
 
+// Similar to StartAndAttachTo, but the process is started with address space
+// layout randomization disabled.
+func StartWithoutRandomizationAndAttachTo(cmd *exec.Cmd) (*Debugger, error) {
+	tracer, err := ptrace.StartAndAttachToProcessWithoutRandomization(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDebugger(tracer, true)
+}
+
 func StartCmdAndAttachTo(name string, args ...string) (*Debugger, error) {
 	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
@@ -236,8 +423,15 @@ func (db *Debugger) Close() error {
 		_ = db.processTracer.Close()
 	}()
 
+	// NOTE: the checkpoints must be discarded before detaching since they are
+	// traced by the process tracer's os thread.
+	err := db.discardCheckpoints()
+	if err != nil {
+		return err
+	}
+
 	if db.mainThread().status.Running() {
-		err := db.signal.StopToProcess()
+		err = db.signal.StopToProcess()
 		if err != nil {
 			return err
 		}
@@ -252,7 +446,7 @@ func (db *Debugger) Close() error {
 		return nil
 	}
 
-	err := db.processTracer.Detach()
+	err = db.processTracer.Detach()
 	if err != nil {
 		return err
 	}
@@ -278,8 +472,20 @@ func (db *Debugger) WatchThreadLifeCycle(notify func(*ThreadStatus)) {
 		notify)
 }
 
+// Returns the current thread and all threads sorted by tid.  The returned
+// list is owned by the caller.
 func (db *Debugger) ListThreads() (*ThreadState, []*ThreadState) {
-	threads := []*ThreadState{}
+	return db.currentThread(), slices.Clone(db.sortedThreads())
+}
+
+// The returned list is shared and must not be modified.  Note that the list
+// remains valid (but stale) after thread creation / exit.
+func (db *Debugger) sortedThreads() []*ThreadState {
+	if db.threadList != nil {
+		return db.threadList
+	}
+
+	threads := make([]*ThreadState, 0, len(db.threads))
 	for _, thread := range db.threads {
 		threads = append(threads, thread)
 	}
@@ -290,7 +496,8 @@ func (db *Debugger) ListThreads() (*ThreadState, []*ThreadState) {
 			return threads[i].Tid < threads[j].Tid
 		})
 
-	return db.currentThread(), threads
+	db.threadList = threads
+	return threads
 }
 
 func (db *Debugger) SetCurrentThread(tid int) error {
@@ -315,15 +522,90 @@ func (db *Debugger) CurrentStatus() *ThreadStatus {
 	return db.currentThread().Status()
 }
 
+// Returns the status reported by the last resume / step (i.e., the status of
+// the thread the debugger focused on after the stop), which may differ from
+// the current thread's status when the user switched threads.  nil if the
+// process has not been resumed yet.
+func (db *Debugger) LastStopStatus() *ThreadStatus {
+	return db.lastStopStatus
+}
+
+func (db *Debugger) recordStop(
+	status *ThreadStatus,
+	err error,
+) (
+	*ThreadStatus,
+	error,
+) {
+	if err == nil && status != nil {
+		db.lastStopStatus = status
+	}
+	return status, err
+}
+
 func (db *Debugger) Exited() bool {
 	return db.mainThread().status.Exited
 }
 
+// True when the process exited or was terminated by a signal.
+func (db *Debugger) Terminated() bool {
+	return db.processTerminated()
+}
+
+// True when the debugger started the process, in which case Close kills the
+// process.  Otherwise, Close detaches from the (attached) process.
+func (db *Debugger) OwnsProcess() bool {
+	return db.ownsProcess
+}
+
+// Returns (and clears) the instruction length mismatches detected while
+// stepping since the last call.
+func (db *Debugger) NewStepWarnings() []string {
+	warnings := db.stepWarnings
+	db.stepWarnings = nil
+	return warnings
+}
+
+// Returns the process' command line arguments (argv) and current working
+// directory.  Both are read from procfs on every call since the process may
+// change them (e.g., via prctl / chdir).
+func (db *Debugger) ProcessCommandLine() ([]string, string, error) {
+	if db.processTerminated() {
+		return nil, "", fmt.Errorf(
+			"failed to read process %d command line: %w",
+			db.Pid,
+			ErrProcessExited)
+	}
+
+	args, err := procfs.GetCommandLine(db.Pid)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cwd, err := procfs.GetCurrentWorkingDirectory(db.Pid)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return args, cwd, nil
+}
+
+// True when the process exited or was terminated by a signal, in which case
+// the process' memory is no longer accessible.
+func (db *Debugger) processTerminated() bool {
+	status := db.mainThread().status
+	return status.Exited || status.Signaled
+}
+
 func (db *Debugger) BacktraceStack() (*CallFrame, []*CallFrame) {
 	stack := db.currentThread().CallStack
 	return stack.CurrentInspectFrame(), stack.ExecutingStack()
 }
 
+func (db *Debugger) FrameArguments(frame *CallFrame) ([]FrameArgument, error) {
+	return db.currentThread().CallStack.FrameArguments(frame)
+}
+
 func (db *Debugger) InspectCalleeFrame() {
 	db.currentThread().CallStack.InspectCalleeFrame()
 }
@@ -332,6 +614,10 @@ func (db *Debugger) InspectCallerFrame() {
 	db.currentThread().CallStack.InspectCallerFrame()
 }
 
+func (db *Debugger) InspectFrame(idx int) error {
+	return db.currentThread().CallStack.InspectFrame(idx)
+}
+
 func (db *Debugger) GetInspectFrameRegisterState() (registers.State, error) {
 	return db.currentThread().CallStack.GetInspectFrameRegisterState()
 }
@@ -395,7 +681,7 @@ func (db *Debugger) GetEvaluatedResult(
 
 func (db *Debugger) AllRegisters() []*registers.Registers {
 	all := []*registers.Registers{}
-	for _, thread := range db.threads {
+	for _, thread := range db.sortedThreads() {
 		if thread.status.Exited || thread.status.Signaled {
 			continue
 		}
@@ -407,7 +693,7 @@ func (db *Debugger) AllRegisters() []*registers.Registers {
 
 func (db *Debugger) addThread(
 	tid int,
-	threadTracer *ptrace.Tracer,
+	threadTracer ThreadTracer,
 	waitStatus syscall.WaitStatus,
 ) (
 	*ThreadState,
@@ -427,6 +713,7 @@ func (db *Debugger) addThread(
 	}
 	thread.CallStack = newCallStack(thread)
 	db.threads[tid] = thread
+	db.threadList = nil
 
 	err := thread.updateStatus(waitStatus, true)
 	if err != nil {
@@ -452,6 +739,7 @@ func (db *Debugger) removeThread(tid int) error {
 	}
 
 	delete(db.threads, tid)
+	db.threadList = nil
 
 	for _, notify := range db.threadLifeCycleWatchers {
 		notify(thread.status)
@@ -460,6 +748,31 @@ func (db *Debugger) removeThread(tid int) error {
 	return nil
 }
 
+// Removes the thread which no longer exists (e.g., destroyed by another
+// thread's execve).  Unlike removeThread, the thread is not detached.
+func (db *Debugger) dropThread(tid int) {
+	thread, ok := db.threads[tid]
+	if !ok {
+		return
+	}
+
+	delete(db.threads, tid)
+	db.threadList = nil
+
+	if db.currentTid == tid {
+		db.currentTid = db.Pid
+	}
+
+	thread.status = &ThreadStatus{
+		Tid:    tid,
+		Exited: true,
+	}
+
+	for _, notify := range db.threadLifeCycleWatchers {
+		notify(thread.status)
+	}
+}
+
 func (db *Debugger) shouldUpdateSharedLibraries(
 	status *ThreadStatus,
 ) bool {
@@ -498,7 +811,7 @@ func (db *Debugger) shouldUpdateSharedLibraries(
 }
 
 func (db *Debugger) updateSharedLibraries() error {
-	notifyAddress, modified, err := db.LoadedElves.UpdateFiles()
+	notifyAddress, loaded, unloaded, err := db.LoadedElves.UpdateFiles()
 	if err != nil {
 		if errors.Is(err, ErrRendezvousAddressNotFound) {
 			return nil
@@ -532,7 +845,9 @@ func (db *Debugger) updateSharedLibraries() error {
 		db.entryPointRendezvousSite = nil
 	}
 
-	if modified {
+	db.recordSharedLibraryEvents(loaded, unloaded)
+
+	if len(loaded) > 0 || len(unloaded) > 0 {
 		err := db.BreakPoints.ResolveStopSites()
 		if err != nil {
 			return err
@@ -542,6 +857,11 @@ func (db *Debugger) updateSharedLibraries() error {
 		if err != nil {
 			return err
 		}
+
+		err = db.exceptionCatchPoints.ResolveStopSites()
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -550,9 +870,12 @@ func (db *Debugger) updateSharedLibraries() error {
 func (db *Debugger) _stopRunningThreads(
 	stopped map[int]syscall.WaitStatus,
 ) error {
-	numRunning := 0
+	waiting := map[int]struct{}{}
 	for tid, thread := range db.threads {
-		if !thread.status.Running() {
+		// NOTE: the single stepping thread's status is not updated to running,
+		// but the thread may not have stopped yet (e.g., when a different
+		// thread's exit event or a new thread's sig stop is reported first).
+		if !thread.status.Running() && !thread.hasPendingSingleStepTrap {
 			continue
 		}
 
@@ -562,7 +885,7 @@ func (db *Debugger) _stopRunningThreads(
 			continue
 		}
 
-		numRunning += 1
+		waiting[tid] = struct{}{}
 
 		if !thread.hasPendingSigStop && !thread.hasPendingSingleStepTrap {
 			err := db.signal.StopToThread(tid)
@@ -573,12 +896,30 @@ func (db *Debugger) _stopRunningThreads(
 		}
 	}
 
-	for numRunning > 0 {
+	for len(waiting) > 0 {
 		tid, waitStatus, err := db.signal.FromProcessThreads()
 		if err != nil {
 			return err
 		}
 
+		consumed, dropped, err := db.preprocessExecEvent(tid, waitStatus)
+		if err != nil {
+			return err
+		}
+
+		// The dropped threads will never report again.
+		for _, droppedTid := range dropped {
+			delete(waiting, droppedTid)
+			delete(stopped, droppedTid)
+		}
+
+		if consumed {
+			// The released thread group leader's earlier report (if any) is
+			// stale.  The leader's next report is the exec event.
+			delete(stopped, tid)
+			continue
+		}
+
 		_, ok := stopped[tid]
 		if ok {
 			panic("should never happen")
@@ -591,11 +932,11 @@ func (db *Debugger) _stopRunningThreads(
 			continue
 		}
 
-		if !thread.status.Running() {
+		if !thread.status.Running() && !thread.hasPendingSingleStepTrap {
 			panic("should never happen")
 		}
 
-		numRunning -= 1
+		delete(waiting, tid)
 	}
 
 	return nil
@@ -623,6 +964,13 @@ func (db *Debugger) _updateStoppedThreads(
 
 			shouldRefresh = true
 		} else {
+			if isExecEvent(waitStatus) {
+				err := db.handleExec(thread)
+				if err != nil {
+					return nil, err
+				}
+			}
+
 			err := thread.updateStatus(waitStatus, !ok)
 			if err != nil {
 				return nil, err
@@ -655,16 +1003,30 @@ func (db *Debugger) waitForSignalFromAnyThread() (
 	map[int]*ThreadState,
 	error,
 ) {
-	tid, waitStatus, err := db.signal.FromProcessThreads()
-	if err != nil {
-		return nil, err
+	var tid int
+	var waitStatus syscall.WaitStatus
+	for {
+		var err error
+		tid, waitStatus, err = db.signal.FromProcessThreads()
+		if err != nil {
+			return nil, err
+		}
+
+		consumed, _, err := db.preprocessExecEvent(tid, waitStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		if !consumed {
+			break
+		}
 	}
 
 	stopped := map[int]syscall.WaitStatus{
 		tid: waitStatus,
 	}
 
-	err = db._stopRunningThreads(stopped)
+	err := db._stopRunningThreads(stopped)
 	if err != nil {
 		return nil, err
 	}
@@ -684,48 +1046,67 @@ func (db *Debugger) resumeUntilSignal(
 	*ThreadStatus,
 	error,
 ) {
-	resume := func() error {
-		resumeThreads := []*ThreadState{}
+	// This returns a status if a thread stopped for other reasons (e.g., an
+	// interrupt) while stepping over its break site.
+	resume := func() (*ThreadStatus, error) {
+		resumeThreads := db.sortedThreads()
 		if resumeThread != nil {
-			resumeThreads = append(resumeThreads, resumeThread)
-		} else {
-			for _, thread := range db.threads {
-				resumeThreads = append(resumeThreads, thread)
-			}
+			resumeThreads = []*ThreadState{resumeThread}
 		}
 
-		// NOTE: all rendezvous must be stepped over before resuming threads since
-		// the resumed threads may accidently bypass temporarily disabled sites.
+		// NOTE: all rendezvous / skipped break points must be stepped over before
+		// resuming threads since the resumed threads may accidently bypass
+		// temporarily disabled sites.
 		for _, thread := range resumeThreads {
-			if thread.status.TrapKind == RendezvousTrap {
+			if thread.status.TrapKind == RendezvousTrap ||
+				thread.status.TrapKind == WatchPointScopeTrap ||
+				thread.status.shouldBypassBreakSite {
+
 				err := thread.stepInstruction(true, false)
 				if err != nil {
-					return fmt.Errorf(
+					return nil, fmt.Errorf(
 						"failed to resume until signal. "+
-							"cannot step over rendezvous for thread %d: %w",
+							"cannot step over break site for thread %d: %w",
 						thread.Tid,
 						err)
 				}
+
+				if thread.status.TrapKind != SingleStepTrap {
+					reportStatus := db.focusOnImportantStatus(
+						resumeThread,
+						map[int]*ThreadState{thread.Tid: thread})
+					if reportStatus != nil {
+						return reportStatus, nil
+					}
+				}
 			}
 		}
 
 		for _, thread := range resumeThreads {
 			err := thread.resume()
 			if err != nil {
-				return fmt.Errorf(
+				return nil, fmt.Errorf(
 					"failed to resume until signal. cannot resume thread %d: %w",
 					thread.Tid,
 					err)
 			}
 		}
 
-		return nil
+		return nil, nil
 	}
 
+	// NOTE: the resumed state is restored (rather than cleared) since
+	// resumeUntilSignal is reentered when a stop point's condition calls
+	// functions.
+	wasResumed := db.signal.setResumed(true)
+	defer db.signal.setResumed(wasResumed)
+
 	for {
-		err := resume()
+		reportStatus, err := resume()
 		if err != nil {
 			return nil, err
+		} else if reportStatus != nil {
+			return reportStatus, nil
 		}
 
 		stoppedThreads, err := db.waitForSignalFromAnyThread()
@@ -733,7 +1114,12 @@ func (db *Debugger) resumeUntilSignal(
 			return nil, err
 		}
 
-		reportStatus := db.focusOnImportantStatus(resumeThread, stoppedThreads)
+		err = db.updateWatchPointScopes(stoppedThreads)
+		if err != nil {
+			return nil, err
+		}
+
+		reportStatus = db.focusOnImportantStatus(resumeThread, stoppedThreads)
 		if reportStatus != nil {
 			return reportStatus, nil
 		}
@@ -751,21 +1137,47 @@ func (db *Debugger) focusOnImportantStatus(
 		}
 
 		if thread.status.StopSignal != syscall.SIGTRAP {
+			if !db.SignalCatchPolicy.ShouldStop(thread.status.StopSignal) {
+				continue
+			}
+
 			db.currentTid = thread.Tid
 			return thread.status
 		}
 
 		switch thread.status.TrapKind {
 		case SyscallTrap:
-			if db.SyscallCatchPolicy.Matches(
-				thread.status.SyscallTrapInfo.Id) {
+			info := thread.status.SyscallTrapInfo
+			if db.shouldCatchSyscall(thread, info) {
+				db.currentTid = thread.Tid
+				return thread.status
+			}
+
+			if info.IsEntry {
+				thread.isForking = db.CatchFork && info.IsForkEntry()
+			} else if thread.isForking {
+				thread.isForking = false
 
 				db.currentTid = thread.Tid
 				return thread.status
 			}
-		case RendezvousTrap, CloneTrap:
+		case ExecTrap:
+			if db.CatchExec {
+				db.currentTid = thread.Tid
+				return thread.status
+			}
+		case ExitTrap:
+			if db.StopOnExit {
+				db.currentTid = thread.Tid
+				return thread.status
+			}
+		case RendezvousTrap, CloneTrap, WatchPointScopeTrap:
 			// do nothing
 		default:
+			if db.filterSkippedStopPoints(thread) {
+				continue
+			}
+
 			db.currentTid = thread.Tid
 			return thread.status
 		}
@@ -791,13 +1203,48 @@ func (db *Debugger) focusOnImportantStatus(
 	return nil
 }
 
+func (db *Debugger) shouldCatchSyscall(
+	thread *ThreadState,
+	info *catchpoint.SyscallTrapInfo,
+) bool {
+	if !db.SyscallCatchPolicy.Matches(info.Id) {
+		return false
+	}
+
+	condition := db.SyscallCatchPolicy.Condition()
+	if condition == nil {
+		return true
+	}
+
+	if info.IsEntry == condition.IsReturnValueCondition() {
+		if info.IsEntry { // return value is not available yet
+			return false
+		}
+
+		// The argument condition was evaluated at syscall entry.
+		caught := thread.isCatchingSyscall
+		thread.isCatchingSyscall = false
+		return caught
+	}
+
+	// NOTE: unreadable string arguments (e.g., null pointer) never match.
+	matched, err := condition.Evaluate(info, db.VirtualMemory)
+	matched = err == nil && matched
+
+	if info.IsEntry {
+		thread.isCatchingSyscall = matched
+	}
+
+	return matched
+}
+
 func (db *Debugger) ResumeAllUntilSignal() (*ThreadStatus, error) {
 	if db.Exited() {
 		return nil, fmt.Errorf("failed to resume all threads: %w", ErrProcessExited)
 	}
 
 	// Ensure all threads have advance by at least one instruction
-	for _, thread := range db.threads {
+	for _, thread := range db.sortedThreads() {
 		err := thread.maybeBypassCurrentPCBreakSite()
 		if err != nil {
 			return nil, err
@@ -805,32 +1252,143 @@ func (db *Debugger) ResumeAllUntilSignal() (*ThreadStatus, error) {
 	}
 
 	// Note that the current thread may have been updated by resumeUntilSignal.
-	status, err := db.resumeUntilSignal(nil)
-	if err != nil {
-		return nil, err
+	return db.recordStop(db.resumeUntilSignal(nil))
+}
+
+// Resumes all threads while catching every syscall (similar to strace).  Each
+// syscall entry / exit stop is passed to the trace callback, and the process
+// is resumed automatically.  This returns the first non-syscall stop (e.g.,
+// break point, signal, or exit).  The syscall catch policy is restored before
+// returning.
+func (db *Debugger) ResumeAllAndTraceSyscalls(
+	trace func(*ThreadStatus),
+) (
+	*ThreadStatus,
+	error,
+) {
+	original := *db.SyscallCatchPolicy
+	db.SyscallCatchPolicy.CatchAll()
+	defer func() {
+		*db.SyscallCatchPolicy = original
+	}()
+
+	for {
+		status, err := db.ResumeAllUntilSignal()
+		if err != nil {
+			return nil, err
+		}
+
+		if !status.Stopped || status.TrapKind != SyscallTrap {
+			return status, nil
+		}
+
+		trace(status)
 	}
+}
 
-	return status, nil
+// Stop the resumed process.  This is safe to call from a different goroutine
+// while the process is resumed, and the resume call will report the stop as
+// a SIGSTOP signal stop.
+//
+// NOTE: if the process stopped on its own before the SIGSTOP is delivered,
+// the SIGSTOP is reported by the next resume call instead.
+func (db *Debugger) Interrupt() error {
+	return db.signal.StopToProcess()
 }
 
 func (db *Debugger) ResumeCurrentUntilSignal() (*ThreadStatus, error) {
-	return db.currentThread().ResumeUntilSignal()
+	return db.recordStop(db.currentThread().ResumeUntilSignal())
 }
 
 func (db *Debugger) StepInstruction() (*ThreadStatus, error) {
-	return db.currentThread().StepInstruction()
+	return db.recordStop(db.currentThread().StepInstruction())
 }
 
 func (db *Debugger) StepIn() (*ThreadStatus, error) {
-	return db.currentThread().StepIn()
+	return db.recordStop(db.currentThread().StepIn())
+}
+
+func (db *Debugger) StepIntoCall(callIndex int) (*ThreadStatus, error) {
+	return db.recordStop(db.currentThread().StepIntoCall(callIndex))
 }
 
 func (db *Debugger) StepOver() (*ThreadStatus, error) {
-	return db.currentThread().StepOver()
+	return db.recordStop(db.currentThread().StepOver())
 }
 
 func (db *Debugger) StepOut() (*ThreadStatus, error) {
-	return db.currentThread().StepOut()
+	return db.recordStop(db.currentThread().StepOut())
+}
+
+func (db *Debugger) StepOutFrames(numFrames int) (*ThreadStatus, error) {
+	return db.recordStop(db.currentThread().StepOutFrames(numFrames))
+}
+
+func (db *Debugger) StepOutToCaller(name string) (*ThreadStatus, error) {
+	return db.recordStop(db.currentThread().StepOutToCaller(name))
+}
+
+// threadEvaluationContext evaluates expressions using the thread's registers
+// and call stack (e.g., for locating locals and frame bases) rather than the
+// current thread's.
+type threadEvaluationContext struct {
+	*Debugger
+	thread *ThreadState
+}
+
+func (ctx threadEvaluationContext) ReadInspectFrameVariableOrFunction(
+	name string,
+) (
+	*expression.TypedData,
+	error,
+) {
+	return ctx.thread.CallStack.ReadInspectFrameVariableOrFunction(name)
+}
+
+func (ctx threadEvaluationContext) InvokeMallocInCurrentThread(
+	size int,
+) (
+	VirtualAddress,
+	error,
+) {
+	return ctx.thread.InvokeMalloc(size)
+}
+
+func (ctx threadEvaluationContext) InvokeInCurrentThread(
+	functionOrMethod *expression.TypedData,
+	arguments []*expression.TypedData,
+) (
+	*expression.TypedData,
+	error,
+) {
+	return ctx.thread.Invoke(functionOrMethod, arguments)
+}
+
+func (db *Debugger) newThreadEvaluationContext(
+	tid int,
+) (
+	threadEvaluationContext,
+	error,
+) {
+	thread, ok := db.threads[tid]
+	if !ok {
+		return threadEvaluationContext{}, fmt.Errorf(
+			"%w. no such thread (%d)",
+			ErrInvalidInput,
+			tid)
+	}
+
+	if !thread.status.Stopped {
+		return threadEvaluationContext{}, fmt.Errorf(
+			"%w. thread %d is not stopped",
+			ErrInvalidInput,
+			tid)
+	}
+
+	return threadEvaluationContext{
+		Debugger: db,
+		thread:   thread,
+	}, nil
 }
 
 func (db *Debugger) ResolveVariableExpression(
@@ -839,13 +1397,41 @@ func (db *Debugger) ResolveVariableExpression(
 	*expression.EvaluatedResult,
 	error,
 ) {
-	value, err := expression.Evaluate(db, expressionString)
+	return db.resolveVariableExpression(db, expressionString)
+}
+
+// Similar to ResolveVariableExpression, but the expression is evaluated in
+// the thread's context (its inspect frame) without changing the current
+// thread.
+func (db *Debugger) ResolveVariableExpressionInThread(
+	tid int,
+	expressionString string,
+) (
+	*expression.EvaluatedResult,
+	error,
+) {
+	ctx, err := db.newThreadEvaluationContext(tid)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.resolveVariableExpression(ctx, expressionString)
+}
+
+func (db *Debugger) resolveVariableExpression(
+	ctx expression.EvaluationContext,
+	expressionString string,
+) (
+	*expression.EvaluatedResult,
+	error,
+) {
+	value, err := expression.Evaluate(ctx, expressionString)
 	if err != nil {
 		return nil, err
 	}
 
 	if value.ImplicitValue != nil {
-		addr, err := db.InvokeMallocInCurrentThread(value.ByteSize)
+		addr, err := ctx.InvokeMallocInCurrentThread(value.ByteSize)
 		if err != nil {
 			return nil, err
 		}
@@ -870,3 +1456,161 @@ func (db *Debugger) ResolveVariableExpression(
 
 	return db.EvaluatedResults.Save(expressionString, value), nil
 }
+
+type FrameEvaluation struct {
+	// The frame's backtrace index.
+	Index int
+
+	*CallFrame
+
+	Value *expression.TypedData
+}
+
+// Evaluate the expression in each executing frame's scope.  The expression is
+// parsed once up front; syntax errors are returned.  Frames where a variable
+// in the expression does not resolve are skipped, but all other evaluation
+// errors are returned.  The inspect frame is restored once all frames are
+// evaluated.
+func (db *Debugger) EvaluateInAllFrames(
+	expressionString string,
+) (
+	[]FrameEvaluation,
+	error,
+) {
+	err := expression.CheckSyntax(expressionString)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w. invalid expression (%s): %w",
+			ErrInvalidInput,
+			expressionString,
+			err)
+	}
+
+	stack := db.currentThread().CallStack
+	frames := stack.ExecutingStack()
+	if len(frames) == 0 {
+		return nil, nil
+	}
+
+	originalIdx := stack.InspectFrameIndex()
+
+	result := []FrameEvaluation{}
+	for idx, frame := range frames {
+		err := stack.InspectFrame(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := expression.Evaluate(db, expressionString)
+		if errors.Is(err, ErrVariableNotFound) {
+			continue
+		} else if err != nil {
+			// NOTE: the evaluation error takes precedence over the restore error.
+			_ = stack.InspectFrame(originalIdx)
+			return nil, fmt.Errorf("frame %d: %w", idx, err)
+		}
+
+		result = append(
+			result,
+			FrameEvaluation{
+				Index:     idx,
+				CallFrame: frame,
+				Value:     value,
+			})
+	}
+
+	err = stack.InspectFrame(originalIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Evaluate the value expression and write the value to the variable
+// expression's location.  This returns the updated variable.
+func (db *Debugger) AssignVariable(
+	variableExpression string,
+	valueExpression string,
+) (
+	*expression.TypedData,
+	error,
+) {
+	variable, err := expression.Evaluate(db, variableExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := expression.Evaluate(db, valueExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := value.EncodeAs(variable.DataDescriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.currentThread().CallStack.WriteInspectFrameVariable(variable, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return expression.Evaluate(db, variableExpression)
+}
+
+// Unlike ResolveVariableExpression, the result is not saved into the
+// evaluated result history, and implicit values are not materialized in the
+// process' memory.
+func (db *Debugger) EvaluateExpression(
+	expressionString string,
+) (
+	*expression.DecodedValue,
+	error,
+) {
+	value, err := expression.Evaluate(db, expressionString)
+	if err != nil {
+		return nil, err
+	}
+
+	return value.Decode()
+}
+
+// Interpret the memory starting at the address as an array of numElements
+// values of the named type.
+func (db *Debugger) ReadTypedMemory(
+	address VirtualAddress,
+	numElements int,
+	typeName string,
+) (
+	*expression.TypedData,
+	error,
+) {
+	if numElements < 1 {
+		return nil, fmt.Errorf(
+			"%w. invalid number of elements (%d)",
+			ErrInvalidInput,
+			numElements)
+	}
+
+	valueType, err := db.descriptorPool.GetTypeDescriptorByName(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	if valueType.Kind == expression.VoidKind || valueType.ByteSize == 0 {
+		return nil, fmt.Errorf(
+			"%w. cannot read values of zero-sized type (%s)",
+			ErrInvalidInput,
+			typeName)
+	}
+
+	arrayType := db.descriptorPool.NewArrayType(valueType, numElements)
+	return &expression.TypedData{
+		VirtualMemory:  db.VirtualMemory,
+		FormatPrefix:   address.String(),
+		DataDescriptor: arrayType,
+		Address:        address,
+		BitSize:        8 * arrayType.ByteSize,
+	}, nil
+}
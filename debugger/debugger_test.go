@@ -3,13 +3,18 @@ package debugger
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"math"
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/pattyshack/gt/testing/expect"
 	"github.com/pattyshack/gt/testing/suite"
@@ -17,10 +22,13 @@ import (
 	"github.com/pattyshack/bad/debugger/catchpoint"
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/loadedelves"
+	"github.com/pattyshack/bad/debugger/memory"
 	"github.com/pattyshack/bad/debugger/registers"
 	"github.com/pattyshack/bad/debugger/stoppoint"
 	"github.com/pattyshack/bad/dwarf"
 	"github.com/pattyshack/bad/procfs"
+	"github.com/pattyshack/bad/ptrace"
 )
 
 func processExists(pid int) bool {
@@ -70,6 +78,57 @@ func (DebuggerSuite) TestAttachSuccess(t *testing.T) {
 	expect.Equal(t, procfs.TracingStop, status.State)
 }
 
+func (DebuggerSuite) TestProcessCommandLine(t *testing.T) {
+	program, err := filepath.Abs("test_targets/hello_world")
+	expect.Nil(t, err)
+
+	dir := t.TempDir()
+
+	cmd := exec.Command(program, "meow", "", "purr purr")
+	cmd.Dir = dir
+
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	args, cwd, err := db.ProcessCommandLine()
+	expect.Nil(t, err)
+	expect.Equal(t, []string{program, "meow", "", "purr purr"}, args)
+	expect.Equal(t, dir, cwd)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	_, _, err = db.ProcessCommandLine()
+	expect.True(t, errors.Is(err, ErrProcessExited))
+}
+
+func (DebuggerSuite) TestStartWithoutRandomization(t *testing.T) {
+	loadBias := func() uint64 {
+		cmd := exec.Command("test_targets/hello_world")
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		db, err := StartWithoutRandomizationAndAttachTo(cmd)
+		expect.Nil(t, err)
+		defer db.Close()
+
+		for _, file := range db.LoadedElves.Files() {
+			if file.FileName == "" {
+				return file.LoadBias
+			}
+		}
+
+		t.Fatal("executable not found")
+		return 0
+	}
+
+	first := loadBias()
+	expect.NotEqual(t, 0, first)
+	expect.Equal(t, first, loadBias())
+}
+
 func (DebuggerSuite) TestAttachInvalidPid(t *testing.T) {
 	_, err := AttachTo(0)
 	expect.Error(t, err, "failed to attach to process 0")
@@ -130,6 +189,93 @@ func (DebuggerSuite) TestResumeAlreadyTerminated(t *testing.T) {
 	expect.Error(t, err, "process exited")
 }
 
+func (DebuggerSuite) TestNonZeroExitStatus(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exit_code")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.False(t, status.Stopped)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 42, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestStopOnExit(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exit_code")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	db.StopOnExit = true
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.False(t, status.Exited)
+	expect.Equal(t, ExitTrap, status.TrapKind)
+	expect.Equal(t, db.Pid, status.Tid)
+	expect.NotNil(t, status.PendingExitStatus)
+	expect.True(t, status.PendingExitStatus.Exited())
+	expect.Equal(t, 42, status.PendingExitStatus.ExitStatus())
+
+	// The thread's final state is still inspectable.
+	state, err := db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+	expect.Equal(t, status.NextInstructionAddress, state.ProgramCounter())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.False(t, status.Stopped)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 42, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestCatchException(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exception")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	err = db.CatchException(catchpoint.ThrowEvent)
+	expect.Nil(t, err)
+
+	err = db.CatchException(catchpoint.CatchEvent)
+	expect.Nil(t, err)
+
+	expect.True(t, db.IsCatchingException(catchpoint.ThrowEvent))
+	expect.True(t, db.IsCatchingException(catchpoint.CatchEvent))
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, ExceptionTrap, status.TrapKind)
+	expect.NotNil(t, status.ExceptionTrapInfo)
+
+	thrown := *status.ExceptionTrapInfo
+	expect.Equal(t, catchpoint.ThrowEvent, thrown.Event)
+	expect.True(t, strings.Contains(thrown.TypeName, "runtime_error"))
+	expect.NotEqual(t, 0, thrown.ObjectAddress)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, ExceptionTrap, status.TrapKind)
+	expect.NotNil(t, status.ExceptionTrapInfo)
+
+	caught := *status.ExceptionTrapInfo
+	expect.Equal(t, catchpoint.CatchEvent, caught.Event)
+	expect.Equal(t, thrown.TypeName, caught.TypeName)
+	expect.Equal(t, thrown.ObjectAddress, caught.ObjectAddress)
+
+	err = db.IgnoreException(catchpoint.ThrowEvent)
+	expect.Nil(t, err)
+	expect.False(t, db.IsCatchingException(catchpoint.ThrowEvent))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
 func (DebuggerSuite) TestSetRegisterState(t *testing.T) {
 	reader, writer, err := os.Pipe()
 	expect.Nil(t, err)
@@ -269,6 +415,62 @@ func (DebuggerSuite) TestSetRegisterState(t *testing.T) {
 	expect.Equal(t, "42.24", string(content[:n]))
 }
 
+func (DebuggerSuite) TestSetFsBase(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	fsBase, ok := registers.ByName("fs_base")
+	expect.True(t, ok)
+
+	regState, err := db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+
+	threadPointer := regState.Value(fsBase).ToUint64()
+	expect.True(t, threadPointer != 0)
+
+	// The thread control block's first word points to itself (see glibc's
+	// x86_64 tcbhead_t).
+	content := make([]byte, 8)
+	n, err := db.VirtualMemory.Read(VirtualAddress(threadPointer), content)
+	expect.Nil(t, err)
+	expect.Equal(t, 8, n)
+	expect.Equal(t, threadPointer, binary.LittleEndian.Uint64(content))
+
+	modified, err := regState.WithValue(
+		fsBase,
+		registers.U64(threadPointer+0x1000))
+	expect.Nil(t, err)
+
+	err = db.SetInspectFrameRegisterState(modified)
+	expect.Nil(t, err)
+
+	regState, err = db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+	expect.Equal(t, threadPointer+0x1000, regState.Value(fsBase).ToUint64())
+
+	restored, err := regState.WithValue(fsBase, registers.U64(threadPointer))
+	expect.Nil(t, err)
+
+	err = db.SetInspectFrameRegisterState(restored)
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
 func (DebuggerSuite) TestGetRegisterState(t *testing.T) {
 	db, err := StartCmdAndAttachTo("test_targets/reg_read")
 	expect.Nil(t, err)
@@ -362,6 +564,70 @@ func (DebuggerSuite) TestGetRegisterState(t *testing.T) {
 	expect.Equal(t, 0x40_05, u128.High)
 }
 
+func (DebuggerSuite) TestReadBitFields(t *testing.T) {
+	// gcc only emits the deprecated DW_AT_bit_offset encoding.
+	checkReadBitFields(t, "test_targets/bitfields")
+}
+
+func (DebuggerSuite) TestReadDataBitOffsetBitFields(t *testing.T) {
+	checkReadBitFields(t, "test_targets/bitfields_data_bit_offset")
+}
+
+func (DebuggerSuite) TestGetAndSetRegset(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/reg_read")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	status, err := db.ResumeCurrentUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	tracer := db.currentThread().threadTracer
+
+	// The read is truncated to user_regs_struct's size.
+	gprs := make([]byte, 1024)
+	n, err := tracer.GetRegset(ptrace.NT_PRSTATUS, gprs)
+	expect.Nil(t, err)
+	expect.Equal(t, 27*8, n)
+
+	userRegs, err := tracer.GetGeneralRegisters()
+	expect.Nil(t, err)
+	expect.Equal(t, 0xcafecafe, binary.LittleEndian.Uint64(gprs[2*8:]))
+	expect.Equal(t, userRegs.Rip, binary.LittleEndian.Uint64(gprs[16*8:]))
+
+	fprs := make([]byte, 512)
+	n, err = tracer.GetRegset(ptrace.NT_PRFPREG, fprs)
+	expect.Nil(t, err)
+	expect.Equal(t, 512, n)
+
+	fpRegs, err := tracer.GetFloatingPointRegisters()
+	expect.Nil(t, err)
+	expect.Equal(t, fpRegs.Cwd, binary.LittleEndian.Uint16(fprs))
+	expect.Equal(t, fpRegs.Mxcsr, binary.LittleEndian.Uint32(fprs[24:]))
+
+	// Write xmm0 via the raw regset.
+	binary.LittleEndian.PutUint64(fprs[160:], 0x0123456789abcdef)
+	binary.LittleEndian.PutUint64(fprs[168:], 0xfedcba9876543210)
+	n, err = tracer.SetRegset(ptrace.NT_PRFPREG, fprs)
+	expect.Nil(t, err)
+	expect.Equal(t, 512, n)
+
+	fpRegs, err = tracer.GetFloatingPointRegisters()
+	expect.Nil(t, err)
+	expect.Equal(t, 0x0123456789abcdef, fpRegs.XmmSpace[0])
+	expect.Equal(t, 0xfedcba9876543210, fpRegs.XmmSpace[1])
+
+	// The xsave area's legacy region mirrors the fxsave (fp regs) layout.
+	xstate := make([]byte, 4096)
+	n, err = tracer.GetRegset(ptrace.NT_X86_XSTATE, xstate)
+	expect.Nil(t, err)
+	expect.True(t, n > 512)
+	expect.Equal(t, fprs[160:176], xstate[160:176])
+
+	_, err = tracer.GetRegset(ptrace.Regset(0xdead), gprs)
+	expect.Error(t, err, "failed to get regset")
+}
+
 func (DebuggerSuite) TestSoftwareBreakPointSite(t *testing.T) {
 	reader, writer, err := os.Pipe()
 	expect.Nil(t, err)
@@ -407,6 +673,163 @@ func (DebuggerSuite) TestSoftwareBreakPointSite(t *testing.T) {
 	expect.Equal(t, "Hello world!\n", string(content))
 }
 
+func (DebuggerSuite) TestBreakPointSetBeforeFirstResume(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	// hello_world is position independent, i.e., the break point must be
+	// resolved against the mapped image's load bias.
+	expect.True(t, db.LoadedElves.Executable.LoadBias != 0)
+
+	point, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(point.Sites()))
+
+	address := point.Sites()[0].Address()
+
+	symbol := db.LoadedElves.SymbolSpans(address)
+	expect.NotNil(t, symbol)
+	expect.Equal(t, "main", symbol.Name)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, address, status.NextInstructionAddress)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestDuplicateBreakPoint(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	point, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(point.Sites()))
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Error(t, err, "already set")
+
+	address := point.Sites()[0].Address()
+
+	_, err = db.BreakPoints.Set(
+		db.NewAddressResolver(address),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Error(t, err, "already set")
+
+	hardwarePoint, err := db.BreakPoints.Set(
+		db.NewAddressResolver(address),
+		stoppoint.NewBreakSiteType(true),
+		true)
+	expect.Nil(t, err)
+
+	expect.Equal(t, "int3", point.Sites()[0].Mechanism())
+	expect.Equal(
+		t,
+		"debug register dr0",
+		hardwarePoint.Sites()[0].Mechanism())
+
+	overlapping := hardwarePoint.OverlappingPoints()
+	expect.Equal(t, 1, len(overlapping))
+	expect.Equal(t, point.Id(), overlapping[0].Id())
+
+	expect.Equal(t, 2, len(db.BreakPoints.List()))
+}
+
+func (DebuggerSuite) TestDuplicateConditionalStopPoint(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	setBreakPoint := func(condition string) (*stoppoint.StopPoint, error) {
+		return db.BreakPoints.SetWithOptions(
+			db.NewLineResolver("counter.cpp", 5),
+			stoppoint.NewBreakSiteType(false),
+			true,
+			stoppoint.StopPointOptions{
+				Condition: condition,
+			})
+	}
+
+	first, err := setBreakPoint("g_counter == 1")
+	expect.Nil(t, err)
+	expect.Equal(t, "g_counter == 1", first.Condition())
+
+	second, err := setBreakPoint("g_counter == 2")
+	expect.Nil(t, err)
+	expect.Equal(t, "g_counter == 2", second.Condition())
+
+	_, err = setBreakPoint("g_counter == 2")
+	expect.Error(t, err, "already set")
+
+	unconditional, err := setBreakPoint("")
+	expect.Nil(t, err)
+
+	// Points with pending ignored hits are not duplicates.
+	err = unconditional.SetIgnoreCount(3)
+	expect.Nil(t, err)
+
+	_, err = setBreakPoint("")
+	expect.Nil(t, err)
+
+	_, err = setBreakPoint("")
+	expect.Error(t, err, "already set")
+
+	expect.Equal(t, 4, len(db.BreakPoints.List()))
+
+	// The break points share the same site.
+	expect.Equal(t, 3, len(second.OverlappingPoints()))
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	counter, err := db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	setWatchPoint := func(condition string) (*stoppoint.StopPoint, error) {
+		return db.WatchPoints.SetWithOptions(
+			db.NewAddressResolver(counter.Address),
+			stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+			true,
+			stoppoint.StopPointOptions{
+				Condition: condition,
+			})
+	}
+
+	_, err = setWatchPoint("g_counter > 5")
+	expect.Nil(t, err)
+
+	_, err = setWatchPoint("g_counter > 7")
+	expect.Nil(t, err)
+
+	_, err = setWatchPoint("g_counter > 7")
+	expect.Error(t, err, "already set")
+
+	// The watch points share the same debug register.
+	expect.Equal(t, 2, len(db.WatchPoints.List()))
+	expect.Equal(
+		t,
+		stoppoint.NumHardwareStopSites-1,
+		db.AvailableHardwareStopSites())
+}
+
 func (DebuggerSuite) TestHardwareBreakPointEvadesMemoryChecksum(t *testing.T) {
 	reader, writer, err := os.Pipe()
 	expect.Nil(t, err)
@@ -628,37 +1051,293 @@ func (DebuggerSuite) TestSyscallCatchpoint(t *testing.T) {
 	expect.False(t, state.SyscallTrapInfo.IsEntry)
 }
 
-func (DebuggerSuite) TestSourceLevelBreakPoints(t *testing.T) {
-	cmd := exec.Command("test_targets/overloaded")
-	db, err := StartAndAttachTo(cmd)
-	expect.Nil(t, err)
-	defer db.Close()
+func (DebuggerSuite) TestSyscallCatchpointCondition(t *testing.T) {
+	_, err := catchpoint.ParseSyscallCondition("arg6 == 1")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
 
-	_, err = db.BreakPoints.Set(
-		db.NewLineResolver("overloaded.cpp", 17),
-		stoppoint.NewBreakSiteType(false),
-		true)
-	expect.Nil(t, err)
+	_, err = catchpoint.ParseSyscallCondition("arg0 =~ 1")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
 
-	status, err := db.ResumeAllUntilSignal()
-	expect.Nil(t, err)
-	expect.True(t, status.Stopped)
-	expect.Equal(t, syscall.SIGTRAP, status.StopSignal)
-	expect.Equal(t, SoftwareTrap, status.TrapKind)
-	expect.Equal(t, "overloaded.cpp", status.FileEntry.Name)
-	expect.Equal(t, 17, status.Line)
+	_, err = catchpoint.ParseSyscallCondition("ret contains \"x\"")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
 
-	point, err := db.BreakPoints.Set(
-		db.NewFunctionResolver("print_type"),
-		stoppoint.NewBreakSiteType(false),
-		true)
-	expect.Nil(t, err)
+	_, err = catchpoint.ParseSyscallCondition("arg0 == x")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
 
-	sites := point.Sites()
-	expect.Equal(t, 3, len(sites))
+	openatSyscall, ok := catchpoint.SyscallIdByName("openat")
+	expect.True(t, ok)
 
-	err = sites[0].Disable()
-	expect.Nil(t, err)
+	closeSyscall, ok := catchpoint.SyscallIdByName("close")
+	expect.True(t, ok)
+
+	writeSyscall, ok := catchpoint.SyscallIdByName("write")
+	expect.True(t, ok)
+
+	run := func(
+		ids []catchpoint.SyscallId,
+		conditionStr string,
+	) []*catchpoint.SyscallTrapInfo {
+		db, err := StartCmdAndAttachTo("test_targets/syscall_filter")
+		expect.Nil(t, err)
+		defer db.Close()
+
+		condition, err := catchpoint.ParseSyscallCondition(conditionStr)
+		expect.Nil(t, err)
+		expect.Equal(t, conditionStr, condition.String())
+
+		db.SyscallCatchPolicy.CatchListIf(ids, condition)
+
+		caught := []*catchpoint.SyscallTrapInfo{}
+		for {
+			status, err := db.ResumeAllUntilSignal()
+			expect.Nil(t, err)
+			if !status.Stopped {
+				return caught
+			}
+
+			expect.Equal(t, SyscallTrap, status.TrapKind)
+			caught = append(caught, status.SyscallTrapInfo)
+		}
+	}
+
+	caught := run(
+		[]catchpoint.SyscallId{openatSyscall},
+		"arg1 contains \"secret\"")
+	expect.Equal(t, 2, len(caught))
+	expect.True(t, caught[0].IsEntry)
+	expect.Equal(t, openatSyscall, caught[0].Id)
+	expect.False(t, caught[1].IsEntry)
+	expect.Equal(t, openatSyscall, caught[1].Id)
+	expect.Equal(t, -int64(syscall.ENOENT), int64(caught[1].Ret))
+
+	caught = run([]catchpoint.SyscallId{writeSyscall}, "arg0 == 2")
+	expect.Equal(t, 2, len(caught))
+	expect.True(t, caught[0].IsEntry)
+	expect.Equal(t, uint64(2), caught[0].Args[0])
+	expect.False(t, caught[1].IsEntry)
+	expect.Equal(t, uint64(4), caught[1].Ret)
+
+	// Return value conditions only catch syscall exits.
+	caught = run([]catchpoint.SyscallId{closeSyscall}, "ret < 0")
+	expect.Equal(t, 1, len(caught))
+	expect.False(t, caught[0].IsEntry)
+	expect.Equal(t, closeSyscall, caught[0].Id)
+	expect.Equal(t, -int64(syscall.EBADF), int64(caught[0].Ret))
+}
+
+func (DebuggerSuite) TestSyscallArgumentDecoding(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/syscall_filter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	ids := []catchpoint.SyscallId{}
+	for _, name := range []string{"openat", "close", "write"} {
+		id, ok := catchpoint.SyscallIdByName(name)
+		expect.True(t, ok)
+		ids = append(ids, id)
+	}
+
+	db.SyscallCatchPolicy.CatchList(ids)
+
+	caught := []string{}
+	for {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		if !status.Stopped {
+			break
+		}
+
+		expect.Equal(t, SyscallTrap, status.TrapKind)
+		caught = append(caught, status.SyscallTrapInfo.String())
+	}
+
+	// NOTE: the dynamic loader also opens / closes files before main.
+	expected := []string{
+		"syscall entry: openat(dirfd=AT_FDCWD, pathname=\"/dev/null\", " +
+			"flags=O_RDONLY, mode=0)",
+		"syscall exit: openat returned 3",
+		"syscall entry: openat(dirfd=AT_FDCWD, " +
+			"pathname=\"/nonexistent/secret\", flags=O_RDONLY, mode=0)",
+		"syscall exit: openat returned -2 (ENOENT)",
+		"syscall entry: close(fd=-1)",
+		"syscall exit: close returned -9 (EBADF)",
+		"syscall entry: write(fd=2, buf=",
+		"syscall exit: write returned 4",
+	}
+
+	idx := 0
+	for _, info := range caught {
+		if idx < len(expected) && strings.HasPrefix(info, expected[idx]) {
+			idx++
+		}
+	}
+	expect.Equal(t, len(expected), idx, caught)
+}
+
+func (DebuggerSuite) TestResumeAllAndTraceSyscalls(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/syscall_filter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	traced := []string{}
+	status, err := db.ResumeAllAndTraceSyscalls(
+		func(status *ThreadStatus) {
+			expect.Equal(t, SyscallTrap, status.TrapKind)
+			traced = append(traced, status.SyscallTrapInfo.String())
+		})
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	// The original catch policy is restored.
+	expect.False(t, db.SyscallCatchPolicy.IsEnabled())
+
+	expected := []string{
+		"syscall entry: openat(dirfd=AT_FDCWD, " +
+			"pathname=\"/nonexistent/secret\", flags=O_RDONLY, mode=0)",
+		"syscall exit: openat returned -2 (ENOENT)",
+		"syscall entry: write(fd=2, buf=",
+		"syscall exit: write returned 4",
+		"syscall entry: exit_group(status=0)",
+	}
+
+	idx := 0
+	for _, line := range traced {
+		if idx < len(expected) && strings.HasPrefix(line, expected[idx]) {
+			idx++
+		}
+	}
+	expect.Equal(t, len(expected), idx, traced)
+}
+
+func (DebuggerSuite) TestAutoAdvanceLineBreakPoint(t *testing.T) {
+	cmd := exec.Command("test_targets/overloaded")
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	point, err := db.BreakPoints.Set(
+		db.NewLineResolver("overloaded.cpp", 15),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(point.Sites()))
+
+	resolver := db.NewAutoAdvanceLineResolver("overloaded.cpp", 15)
+	point, err = db.BreakPoints.Set(
+		resolver,
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(point.Sites()))
+
+	lineResolver := resolver.(*stoppoint.LineStopSiteResolver)
+	expect.True(t, lineResolver.IsAdvanced())
+	expect.Equal(t, 16, lineResolver.ResolvedLine)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, 17, status.Line)
+}
+
+func (DebuggerSuite) TestInlinedCallSiteLineBreakPoint(t *testing.T) {
+	cmd := exec.Command("test_targets/step")
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	// find_happiness's line 16 only exists as pet_cat's inlined call site.
+	resolver := db.NewLineResolver("step.cpp", 16)
+	point, err := db.BreakPoints.Set(
+		resolver,
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(point.Sites()))
+
+	lineResolver := resolver.(*stoppoint.LineStopSiteResolver)
+	expect.False(t, lineResolver.IsAdvanced())
+	expect.Equal(t, 16, lineResolver.ResolvedLine)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, point.Sites()[0].Address(), status.NextInstructionAddress)
+	expect.Equal(t, "find_happiness", status.FunctionName)
+	expect.Equal(t, 16, status.Line)
+}
+
+func (DebuggerSuite) TestLineBreakPointWithInlinedCallSite(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/inlined_lines")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	// Line 13 has line entries of its own, as well as twice's inlined call
+	// site.  The inlined instance's entry is not a stop site.
+	point, err := db.BreakPoints.Set(
+		db.NewLineResolver("inlined_lines.cpp", 13),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+	expect.True(t, len(point.Sites()) > 0)
+
+	numStops := 0
+	for {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+
+		if status.Exited {
+			expect.Equal(t, 0, status.ExitStatus)
+			break
+		}
+
+		numStops++
+		expect.True(t, status.Stopped)
+		expect.Equal(t, SoftwareTrap, status.TrapKind)
+		expect.Equal(t, "compute", status.FunctionName)
+		expect.Equal(t, 13, status.Line)
+		expect.Equal(
+			t,
+			0,
+			db.currentThread().CallStack.NumUnexecutedInlinedFunctions())
+	}
+
+	expect.Equal(t, len(point.Sites()), numStops)
+}
+
+func (DebuggerSuite) TestSourceLevelBreakPoints(t *testing.T) {
+	cmd := exec.Command("test_targets/overloaded")
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("overloaded.cpp", 17),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, syscall.SIGTRAP, status.StopSignal)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "overloaded.cpp", status.FileEntry.Name)
+	expect.Equal(t, 17, status.Line)
+
+	point, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("print_type"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	sites := point.Sites()
+	expect.Equal(t, 3, len(sites))
+
+	err = sites[0].Disable()
+	expect.Nil(t, err)
 
 	status, err = db.ResumeAllUntilSignal()
 	expect.Nil(t, err)
@@ -681,6 +1360,59 @@ func (DebuggerSuite) TestSourceLevelBreakPoints(t *testing.T) {
 	expect.True(t, status.Exited)
 }
 
+func (DebuggerSuite) TestOverloadedBreakPointReporting(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/overloaded")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	point, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("print_type"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	sites := point.Sites()
+	expect.Equal(t, 3, len(sites))
+
+	siteSignatures := map[string]int{}
+	for idx, site := range sites {
+		signature, err := db.FunctionSignatureAt(site.Address())
+		expect.Nil(t, err)
+		siteSignatures[signature] = idx
+	}
+	expect.Equal(t, 3, len(siteSignatures))
+
+	for _, expected := range []string{"print_type(int32)", "print_type(float64)"} {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, SoftwareTrap, status.TrapKind)
+		expect.Equal(t, expected, status.FunctionSignature)
+
+		siteIdx, ok := siteSignatures[expected]
+		expect.True(t, ok)
+
+		expect.Equal(t, 1, len(status.StopPoints))
+		expect.Equal(t, point, status.StopPoints[0].StopPoint)
+		expect.Equal(t, siteIdx, status.StopPoints[0].SiteIndex)
+
+		str := status.String()
+		expect.True(
+			t,
+			strings.Contains(str, fmt.Sprintf("(site %d of 3)", siteIdx)))
+		expect.True(t, strings.Contains(str, "function: "+expected))
+	}
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.True(t, strings.HasPrefix(status.FunctionSignature, "print_type("))
+	expect.Equal(
+		t,
+		siteSignatures[status.FunctionSignature],
+		status.StopPoints[0].SiteIndex)
+}
+
 func (DebuggerSuite) TestSourceLevelStepping(t *testing.T) {
 	cmd := exec.Command("test_targets/step")
 	db, err := StartAndAttachTo(cmd)
@@ -838,6 +1570,98 @@ func (DebuggerSuite) TestSharedLibraryTracing(t *testing.T) {
 	expect.Equal(t, []string{"libmeow.so", ""}, libs)
 }
 
+func (DebuggerSuite) TestSeparateDebugInfo(t *testing.T) {
+	cmd := exec.Command("test_targets/kitten")
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("libpurr_volume"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.NotNil(t, status.FileEntry)
+
+	var purr *loadedelves.File
+	for _, file := range db.LoadedElves.Files() {
+		if path.Base(file.FileName) == "libpurr.so" {
+			purr = file
+		}
+	}
+	expect.NotNil(t, purr)
+	expect.NotNil(t, purr.Dwarf)
+	expect.Equal(t, "libpurr.so.debug", path.Base(purr.DebugFilePath))
+
+	reports := db.NewDebugInfoReports()
+	expect.True(
+		t,
+		slices.Contains(
+			reports,
+			"loaded debug info for "+purr.FileName+" from "+purr.DebugFilePath))
+	expect.Equal(t, 0, len(db.NewDebugInfoReports()))
+
+	data, err := db.ResolveVariableExpression("loudness")
+	expect.Nil(t, err)
+
+	loudness, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(21), loudness.(int32))
+}
+
+func (DebuggerSuite) TestSharedLibraryEvents(t *testing.T) {
+	cmd := exec.Command("test_targets/marshmallow")
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	notified := []SharedLibraryEvent{}
+	db.WatchSharedLibraryEvents(func(event SharedLibraryEvent) {
+		notified = append(notified, event)
+	})
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("libmeow_client_is_cute"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	events := db.SharedLibraryEvents()
+	expect.Equal(t, notified, events)
+
+	libs := map[string]SharedLibraryEvent{}
+	for idx, event := range events {
+		expect.Equal(t, idx+1, event.Sequence)
+		expect.True(t, event.Loaded)
+		libs[path.Base(event.Name)] = event
+	}
+
+	meow, ok := libs["libmeow.so"]
+	expect.True(t, ok)
+
+	found := false
+	for _, file := range db.LoadedElves.Files() {
+		if file.FileName == meow.Name {
+			found = true
+			expect.Equal(t, VirtualAddress(file.LoadBias), meow.LoadAddress)
+		}
+	}
+	expect.True(t, found)
+
+	_, ok = libs["libc.so.6"]
+	expect.True(t, ok)
+}
+
 func (DebuggerSuite) TestMultiThreading(t *testing.T) {
 	cmd := exec.Command("test_targets/multi_threaded")
 	db, err := StartAndAttachTo(cmd)
@@ -936,134 +1760,3779 @@ func (DebuggerSuite) TestDwarfExpression(t *testing.T) {
 	expect.Equal(t, 0xffffffff, chunk.Value)
 	expect.Equal(t, 5, chunk.BitSize)
 	expect.Equal(t, 12, chunk.BitOffset)
+
+	expect.Equal(
+		t,
+		[]string{
+			"location: composite (3 chunks)",
+			"  chunk 0: register: rip (dwarf register 16) " +
+				"(variable bits [0, 32), bit size: 32, bit offset: 0)",
+			"  chunk 1: unavailable (optimized out) " +
+				"(variable bits [32, 96), bit size: 64, bit offset: 0)",
+			"  chunk 2: memory address: 0x00000000ffffffff " +
+				"(variable bits [96, 101), bit size: 5, bit offset: 12)",
+		},
+		DescribeLocation(location))
+
+	expect.Equal(
+		t,
+		[]string{"location: none (address and contents are unknown)"},
+		DescribeLocation(nil))
+
+	expect.Equal(
+		t,
+		[]string{
+			"location: simple",
+			"  implicit data: [01 ab] (2 bytes)",
+		},
+		DescribeLocation(dwarf.Location{
+			{
+				Kind: dwarf.ImplicitDataLocation,
+				Data: []byte{0x01, 0xab},
+			},
+		}))
+
+	variable, err := db.ReadInspectFrameVariableOrFunction("i")
+	expect.Nil(t, err)
+
+	description := DescribeLocation(variable.Location)
+	expect.Equal(t, 2, len(description))
+	expect.Equal(t, "location: simple", description[0])
+	expect.Equal(
+		t,
+		"  memory address: "+variable.Address.String(),
+		description[1])
 }
 
-func (DebuggerSuite) TestReadGlobalVariable(t *testing.T) {
-	db, err := StartCmdAndAttachTo("test_targets/global_variable")
-	expect.Nil(t, err)
+func (DebuggerSuite) TestDwarfExpressionLiterals(t *testing.T) {
+	db := startStepAtMain(t)
 	defer db.Close()
 
-	_, err = db.BreakPoints.Set(
-		db.NewFunctionResolver("main"),
-		stoppoint.NewBreakSiteType(false),
-		true)
+	value, err := evaluateDwarfStackValue(db, byte(dwarf.DW_OP_lit0))
 	expect.Nil(t, err)
+	expect.Equal(t, 0, value)
 
-	status, err := db.ResumeAllUntilSignal()
+	value, err = evaluateDwarfStackValue(db, byte(dwarf.DW_OP_lit5))
 	expect.Nil(t, err)
-	expect.True(t, status.Stopped)
-	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, 5, value)
 
-	checkVar := func(expected uint64) {
-		globalVar, err := db.ResolveVariableExpression("g_int")
-		expect.Nil(t, err)
-		expect.Equal(t, expression.UintKind, globalVar.Kind)
-		expect.Equal(t, 8, globalVar.ByteSize)
+	value, err = evaluateDwarfStackValue(db, byte(dwarf.DW_OP_lit31))
+	expect.Nil(t, err)
+	expect.Equal(t, 31, value)
 
-		val, err := globalVar.DecodeSimpleValue()
-		expect.Nil(t, err)
-		expect.Equal(t, expected, val.(uint64))
-	}
+	_, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit1),
+		byte(dwarf.DW_OP_lit2))
+	expect.Error(t, err, "stack not empty after evaluation")
+}
 
-	checkVar(0)
+func (DebuggerSuite) TestDwarfExpressionArithmetic(t *testing.T) {
+	db := startStepAtMain(t)
+	defer db.Close()
 
-	status, err = db.StepOver()
-	expect.Nil(t, err)
-	expect.True(t, status.Stopped)
+	value, err := evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit10),
+		byte(dwarf.DW_OP_lit5),
+		byte(dwarf.DW_OP_plus))
+	expect.Nil(t, err)
+	expect.Equal(t, 15, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit20),
+		byte(dwarf.DW_OP_lit3),
+		byte(dwarf.DW_OP_minus))
+	expect.Nil(t, err)
+	expect.Equal(t, 17, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit3),
+		byte(dwarf.DW_OP_lit20),
+		byte(dwarf.DW_OP_minus))
+	expect.Nil(t, err)
+	expect.Equal(t, -17, int64(value))
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit6),
+		byte(dwarf.DW_OP_lit7),
+		byte(dwarf.DW_OP_mul))
+	expect.Nil(t, err)
+	expect.Equal(t, 42, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit12),
+		byte(dwarf.DW_OP_lit10),
+		byte(dwarf.DW_OP_and))
+	expect.Nil(t, err)
+	expect.Equal(t, 8, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit1),
+		byte(dwarf.DW_OP_lit4),
+		byte(dwarf.DW_OP_shl))
+	expect.Nil(t, err)
+	expect.Equal(t, 16, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit10),
+		byte(dwarf.DW_OP_plus_uconst), 0x80, 0x01) // uleb128 encoded 128
+	expect.Nil(t, err)
+	expect.Equal(t, 138, value)
+
+	_, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit1),
+		byte(dwarf.DW_OP_lit0),
+		byte(dwarf.DW_OP_div))
+	expect.Error(t, err, "division by zero")
+
+	_, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit1),
+		byte(dwarf.DW_OP_lit0),
+		byte(dwarf.DW_OP_mod))
+	expect.Error(t, err, "division by zero")
+
+	_, err = evaluateDwarfStackValue(
+		db,
+		byte(dwarf.DW_OP_lit1),
+		byte(dwarf.DW_OP_plus))
+	expect.Error(t, err, "cannot pop empty stack")
+}
+
+func (DebuggerSuite) TestDwarfExpressionDeref(t *testing.T) {
+	db := startStepAtMain(t)
+	defer db.Close()
+
+	variable, err := db.ReadInspectFrameVariableOrFunction("i")
+	expect.Nil(t, err)
+
+	n, err := db.VirtualMemory.Write(
+		variable.Address,
+		[]byte{0x78, 0x56, 0x34, 0x12})
+	expect.Nil(t, err)
+	expect.Equal(t, 4, n)
+
+	pushAddress := func(address VirtualAddress) []byte {
+		return binary.LittleEndian.AppendUint64(
+			[]byte{byte(dwarf.DW_OP_const8u)},
+			uint64(address))
+	}
+
+	value, err := evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref_size), 4)...)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x12345678, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref_size), 2)...)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x5678, value)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref_size), 1)...)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x78, value)
+
+	// address computed by arithmetic
+	value, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address-2),
+			byte(dwarf.DW_OP_plus_uconst), 2,
+			byte(dwarf.DW_OP_deref_size), 4)...)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x12345678, value)
+
+	content := make([]byte, 8)
+	n, err = db.VirtualMemory.Read(variable.Address, content)
+	expect.Nil(t, err)
+	expect.Equal(t, 8, n)
+
+	value, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref))...)
+	expect.Nil(t, err)
+	expect.Equal(t, binary.LittleEndian.Uint64(content), value)
+
+	_, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref_size), 0)...)
+	expect.Error(t, err, "invalid deref size 0")
+
+	_, err = evaluateDwarfStackValue(
+		db,
+		append(
+			pushAddress(variable.Address),
+			byte(dwarf.DW_OP_deref_size), 9)...)
+	expect.Error(t, err, "invalid deref size 9")
+}
+
+func (DebuggerSuite) TestInspectFrame(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/frame_args")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("leaf"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	inspectFrame, frames := db.BacktraceStack()
+	expect.Equal(t, 3, len(frames))
+	expect.Equal(t, frames[0], inspectFrame)
+
+	err = db.InspectFrame(2)
+	expect.Nil(t, err)
+
+	inspectFrame, _ = db.BacktraceStack()
+	expect.Equal(t, frames[2], inspectFrame)
+	expect.Equal(t, "main", inspectFrame.Name)
+
+	err = db.InspectFrame(3)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	err = db.InspectFrame(-1)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	inspectFrame, _ = db.BacktraceStack()
+	expect.Equal(t, frames[2], inspectFrame)
+
+	db.InspectCalleeFrame()
+
+	inspectFrame, _ = db.BacktraceStack()
+	expect.Equal(t, frames[1], inspectFrame)
+}
+
+func (DebuggerSuite) TestFrameArguments(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/frame_args")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("leaf"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	_, frames := db.BacktraceStack()
+	expect.Equal(t, 3, len(frames))
+
+	formatArgs := func(frame *CallFrame) []string {
+		args, err := db.FrameArguments(frame)
+		expect.Nil(t, err)
+
+		result := []string{}
+		for _, arg := range args {
+			result = append(result, arg.String())
+		}
+		return result
+	}
+
+	expect.Equal(t, "leaf", frames[0].Name)
+	leafArgs := formatArgs(frames[0])
+	expect.Equal(t, 3, len(leafArgs))
+	expect.Equal(t, "depth=2", leafArgs[0])
+	expect.True(t, strings.HasSuffix(leafArgs[1], " (leaf)"))
+	expect.Equal(t, "p=...", leafArgs[2])
+
+	leafFrameArgs, err := db.FrameArguments(frames[0])
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		"p={x=1, y=2}",
+		leafFrameArgs[2].Format(FrameArgumentsAll))
+	expect.Equal(t, "depth=2", leafFrameArgs[0].Format(FrameArgumentsAll))
+	expect.Equal(t, "depth=...", leafFrameArgs[0].Format(FrameArgumentsNone))
+	expect.Equal(t, "p=...", leafFrameArgs[2].Format(FrameArgumentsNone))
+	expect.Equal(t, FrameArgumentsScalars, db.FrameArgumentsMode)
+
+	err = db.InspectFrame(1)
+	expect.Nil(t, err)
+
+	evaluations, err := db.EvaluateInAllFrames("depth")
+	expect.Nil(t, err)
+	expect.Equal(t, 2, len(evaluations))
+	expect.Equal(t, 0, evaluations[0].Index)
+	expect.Equal(t, "leaf", evaluations[0].Name)
+	expect.Equal(t, "2", evaluations[0].Value.FormatValue())
+	expect.Equal(t, 1, evaluations[1].Index)
+	expect.Equal(t, "middle", evaluations[1].Name)
+	expect.Equal(t, "1", evaluations[1].Value.FormatValue())
+
+	inspectFrame, _ := db.BacktraceStack()
+	expect.Equal(t, frames[1], inspectFrame)
+
+	evaluations, err = db.EvaluateInAllFrames("scale")
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(evaluations))
+	expect.Equal(t, "middle", evaluations[0].Name)
+	expect.Equal(t, "2.5", evaluations[0].Value.FormatValue())
+
+	evaluations, err = db.EvaluateInAllFrames("no_such_variable")
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(evaluations))
+
+	_, err = db.EvaluateInAllFrames("depth +")
+	expect.Error(t, err, "invalid expression (depth +)")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.EvaluateInAllFrames("depth.no_such_field")
+	expect.Error(t, err, "cannot access field/method for non-struct/union (int)")
+
+	inspectFrame, _ = db.BacktraceStack()
+	expect.Equal(t, frames[1], inspectFrame)
+
+	err = db.InspectFrame(0)
+	expect.Nil(t, err)
+
+	expect.Equal(t, "middle", frames[1].Name)
+	middleArgs := formatArgs(frames[1])
+	expect.Equal(t, 3, len(middleArgs))
+	expect.Equal(t, "depth=1", middleArgs[0])
+	expect.Equal(t, "scale=2.5", middleArgs[1])
+	expect.True(t, strings.HasPrefix(middleArgs[2], "p=0x"))
+
+	expect.Equal(t, "main", frames[2].Name)
+	expect.Equal(t, []string{}, formatArgs(frames[2]))
+
+	expect.Equal(t, UnwindFromRegisters, frames[0].UnwindSource)
+	expect.Equal(t, UnwindFromCFI, frames[1].UnwindSource)
+	expect.Equal(t, UnwindFromCFI, frames[2].UnwindSource)
+	for idx, frame := range frames {
+		expect.Equal(t, "", frame.UnwindWarning)
+		expect.NotEqual(t, VirtualAddress(0), frame.ReturnAddress)
+
+		if idx > 0 {
+			// The callee's return address is one byte past the caller's call
+			// instruction.
+			expect.True(
+				t,
+				frame.CodeRanges.Contains(frames[idx-1].ReturnAddress-1))
+
+			calleeCFA, err := frames[idx-1].CanonicalFrameAddress()
+			expect.Nil(t, err)
+			callerCFA, err := frame.CanonicalFrameAddress()
+			expect.Nil(t, err)
+			expect.True(t, calleeCFA < callerCFA)
+		}
+	}
+}
+
+func (DebuggerSuite) TestFrameLocalVariables(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/frame_args")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("leaf"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	thread, _ := db.ListThreads()
+	frames := thread.CallStack.ExecutingStack()
+	expect.Equal(t, 3, len(frames))
+
+	formatLocals := func(frame *CallFrame) []string {
+		locals, err := frame.LocalVariables()
+		expect.Nil(t, err)
+
+		result := []string{}
+		for _, local := range locals {
+			result = append(result, local.FormatPrefix)
+		}
+		return result
+	}
+
+	expect.Equal(t, []string{"depth", "name", "p"}, formatLocals(frames[0]))
+	expect.Equal(t, []string{"depth", "scale", "p"}, formatLocals(frames[1]))
+	expect.Equal(t, []string{"p"}, formatLocals(frames[2]))
+
+	// Variables are evaluated in their own frame, independent of the inspect
+	// frame.
+	expect.Equal(t, 0, thread.CallStack.InspectFrameIndex())
+
+	locals, err := frames[2].LocalVariables()
+	expect.Nil(t, err)
+	expect.Equal(t, "{x=1, y=2}", locals[0].FormatExpandedValue())
+
+	locals, err = frames[1].LocalVariables()
+	expect.Nil(t, err)
+	expect.Equal(t, "1", locals[0].FormatValue())
+	expect.Equal(t, "2.5", locals[1].FormatValue())
+
+	args, err := frames[0].Arguments()
+	expect.Nil(t, err)
+	expect.Equal(t, 3, len(args))
+	expect.Equal(t, "depth=2", args[0].String())
+	expect.Equal(t, "p={x=1, y=2}", args[2].Format(FrameArgumentsAll))
+
+	args, err = frames[1].Arguments()
+	expect.Nil(t, err)
+	expect.Equal(t, 3, len(args))
+	expect.Equal(t, "depth=1", args[0].String())
+
+	// Expressions are evaluated in the inspect frame.
+	depth, err := db.ResolveVariableExpression("depth")
+	expect.Nil(t, err)
+	expect.Equal(t, "2", depth.FormatValue())
+
+	err = db.InspectFrame(1)
+	expect.Nil(t, err)
+
+	depth, err = db.ResolveVariableExpression("depth")
+	expect.Nil(t, err)
+	expect.Equal(t, "1", depth.FormatValue())
+}
+func (DebuggerSuite) TestSourceTrace(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/step")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 22, status.Line)
+
+	expect.False(t, db.SourceTrace.IsEnabled())
+
+	err = db.SourceTrace.Enable(0)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	err = db.SourceTrace.Enable(2)
+	expect.Nil(t, err)
+
+	lines := func() []int64 {
+		result := []int64{}
+		for _, entry := range db.SourceTrace.Entries() {
+			expect.Equal(t, db.Pid, entry.Tid)
+			expect.Equal(t, "step.cpp", path.Base(entry.Path))
+			result = append(result, entry.Line)
+		}
+		return result
+	}
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.Equal(t, 23, status.Line)
+	expect.Equal(t, []int64{22, 23}, lines())
+
+	// The oldest entry is evicted.
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.Equal(t, 24, status.Line)
+	expect.Equal(t, []int64{23, 24}, lines())
+
+	db.SourceTrace.Disable()
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.Equal(t, 25, status.Line)
+	expect.Equal(t, []int64{23, 24}, lines())
+
+	db.SourceTrace.Clear()
+	expect.Equal(t, 0, len(db.SourceTrace.Entries()))
+}
+
+func (DebuggerSuite) TestReadQualifiedVariables(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/qualifiers")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	// _Atomic int
+	counter, err := db.ResolveVariableExpression("counter")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, counter.Kind)
+	expect.Equal(t, 4, counter.ByteSize)
+
+	val, err := counter.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(42), val.(int32))
+
+	// int* restrict
+	restricted, err := db.ResolveVariableExpression("restricted")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.PointerKind, restricted.Kind)
+
+	value, err := db.ResolveVariableExpression("value")
+	expect.Nil(t, err)
+
+	val, err = restricted.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, value.Address, val.(VirtualAddress))
+}
+
+func (DebuggerSuite) TestReadVirtualBaseClassMember(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/virtual_base")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	platypus, err := db.ResolveVariableExpression("g_platypus")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.StructKind, platypus.Kind)
+
+	eggs, err := db.ResolveVariableExpression("g_platypus.eggs")
+	expect.Nil(t, err)
+
+	val, err := eggs.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(3), val.(int32))
+
+	fur, err := db.ResolveVariableExpression("g_platypus.mammal.fur")
+	expect.Nil(t, err)
+
+	val, err = fur.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(1), val.(int32))
+
+	feathers, err := db.ResolveVariableExpression("g_platypus.bird.feathers")
+	expect.Nil(t, err)
+
+	val, err = feathers.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(2), val.(int32))
+
+	// The virtual base class' location is computed via the vtable.
+	mammalLegs, err := db.ResolveVariableExpression(
+		"g_platypus.mammal.animal.legs")
+	expect.Nil(t, err)
+
+	val, err = mammalLegs.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(4), val.(int32))
+
+	birdLegs, err := db.ResolveVariableExpression("g_platypus.bird.animal.legs")
+	expect.Nil(t, err)
+
+	// Both paths must refer to the shared virtual base class subobject.
+	expect.Equal(t, mammalLegs.Address, birdLegs.Address)
+
+	// The shared virtual base class' member is not ambiguous.
+	legs, err := db.ResolveVariableExpression("g_platypus.legs")
+	expect.Nil(t, err)
+	expect.Equal(t, mammalLegs.Address, legs.Address)
+	expect.True(t, mammalLegs.Address >= platypus.Address)
+	expect.True(
+		t,
+		mammalLegs.Address < platypus.Address+VirtualAddress(platypus.ByteSize))
+}
+
+func (DebuggerSuite) TestMultipleInheritanceMemberAccess(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/multiple_inheritance")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	derived, err := db.ResolveVariableExpression("g_derived")
+	expect.Nil(t, err)
+
+	second, err := db.ResolveVariableExpression("g_derived.second")
+	expect.Nil(t, err)
+	expect.True(t, second.Address > derived.Address)
+
+	// Inherited field from the second base class is read at the base class
+	// subobject's offset.
+	b, err := db.ResolveVariableExpression("g_derived.b")
+	expect.Nil(t, err)
+	expect.Equal(t, second.Address, b.Address)
+	expect.Equal(t, "2", b.FormatValue())
+
+	a, err := db.ResolveVariableExpression("g_derived.a")
+	expect.Nil(t, err)
+	expect.Equal(t, derived.Address, a.Address)
+	expect.Equal(t, "1", a.FormatValue())
+
+	// The method receiver is adjusted to the base class subobject.
+	result, err := db.ResolveVariableExpression("g_derived.get_b()")
+	expect.Nil(t, err)
+	expect.Equal(t, "2", result.FormatValue())
+
+	result, err = db.ResolveVariableExpression("g_derived.get_a()")
+	expect.Nil(t, err)
+	expect.Equal(t, "1", result.FormatValue())
+
+	_, err = db.ResolveVariableExpression("g_derived.d")
+	expect.Error(t, err, "field/method (d) not found")
+}
+
+func (DebuggerSuite) TestDynamicType(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/dynamic_type")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	square, err := db.ResolveVariableExpression("g_square")
+	expect.Nil(t, err)
+
+	// Pointers to polymorphic objects are annotated with the dynamic type.
+	shape, err := db.ResolveVariableExpression("g_shape")
+	expect.Nil(t, err)
+	expect.True(
+		t,
+		strings.HasPrefix(
+			shape.Format(""),
+			"g_shape (*shape (dynamic square)): "))
+
+	formatted := shape.FormatWithDepth("", 1)
+	expect.True(t, strings.Contains(formatted, ".width (int32): 7"))
+
+	cast, err := db.ResolveVariableExpression("dynamic_cast(g_shape)")
+	expect.Nil(t, err)
+	expect.Equal(t, "*square", cast.TypeName())
+
+	value, err := cast.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, square.Address, value.(VirtualAddress))
+
+	width, err := db.ResolveVariableExpression("dynamic_cast(g_shape)->width")
+	expect.Nil(t, err)
+	expect.Equal(t, "7", width.FormatValue())
+
+	// The non-primary base class pointer is adjusted to the complete object.
+	named, err := db.ResolveVariableExpression("g_named")
+	expect.Nil(t, err)
+
+	value, err = named.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.True(t, value.(VirtualAddress) > square.Address)
+
+	cast, err = db.ResolveVariableExpression("dynamic_cast(g_named)")
+	expect.Nil(t, err)
+	expect.Equal(t, "*square", cast.TypeName())
+
+	value, err = cast.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, square.Address, value.(VirtualAddress))
+
+	object, err := db.ResolveVariableExpression("dynamic_cast(g_named[0])")
+	expect.Nil(t, err)
+	expect.Equal(t, "square", object.TypeName())
+	expect.Equal(t, square.Address, object.Address)
+
+	// The dynamic type matches the static type.
+	base, err := db.ResolveVariableExpression("g_base")
+	expect.Nil(t, err)
+	expect.True(
+		t,
+		strings.HasPrefix(base.Format(""), "g_base (*shape): "))
+
+	cast, err = db.ResolveVariableExpression("dynamic_cast(g_base)")
+	expect.Nil(t, err)
+	expect.Equal(t, "*shape", cast.TypeName())
+
+	cast, err = db.ResolveVariableExpression("dynamic_cast(g_null)")
+	expect.Nil(t, err)
+	expect.Equal(t, "*shape", cast.TypeName())
+
+	_, err = db.ResolveVariableExpression("dynamic_cast(g_plain_ptr)")
+	expect.Error(t, err, "not a pointer to polymorphic class")
+}
+
+func (DebuggerSuite) TestListDeclarations(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/virtual_base")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	functions, err := db.ListFunctionDeclarations("^main$")
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(functions))
+	expect.Equal(t, "main", functions[0].Name)
+	expect.Equal(t, "", functions[0].LinkageName)
+	expect.NotNil(t, functions[0].FileEntry)
+	expect.Equal(t, "virtual_base.cpp", path.Base(functions[0].Path()))
+	expect.Equal(t, 21, functions[0].Line)
+	expect.True(
+		t,
+		strings.HasSuffix(
+			functions[0].String(),
+			"virtual_base.cpp:21)"))
+
+	variables, err := db.ListVariableDeclarations("platypus")
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(variables))
+	expect.Equal(t, "g_platypus", variables[0].Name)
+	expect.Equal(t, 19, variables[0].Line)
+
+	types, err := db.ListTypeDeclarations("^(bird|mammal|int)$")
+	expect.Nil(t, err)
+	expect.Equal(t, 3, len(types))
+
+	expect.Equal(t, "bird", types[0].Name)
+	expect.Equal(t, 11, types[0].Line)
+
+	// base types have no declaration location
+	expect.Equal(t, "int", types[1].Name)
+	expect.Nil(t, types[1].FileEntry)
+	expect.Equal(t, "int", types[1].String())
+
+	expect.Equal(t, "mammal", types[2].Name)
+	expect.Equal(t, 7, types[2].Line)
+
+	_, err = db.ListTypeDeclarations("(")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+}
+
+func (DebuggerSuite) TestListDeclarationLinkageNames(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/expr")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	functions, err := db.ListFunctionDeclarations("^add_ages$")
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(functions))
+	expect.Equal(t, "add_ages", functions[0].Name)
+	expect.Equal(t, "_ZN3cat8add_agesEii", functions[0].LinkageName)
+	expect.True(
+		t,
+		strings.HasPrefix(
+			functions[0].String(),
+			"add_ages [_ZN3cat8add_agesEii] (declared at "))
+
+	symbolName, ok, err := functions[0].Entry.SymbolName()
+	expect.Nil(t, err)
+	expect.True(t, ok)
+	expect.Equal(t, "_ZN3cat8add_agesEii", symbolName)
+
+	symbols := db.LoadedElves.SymbolsByName(symbolName)
+	expect.Equal(t, 1, len(symbols))
+	expect.Equal(t, symbolName, symbols[0].Name)
+}
+
+func (DebuggerSuite) TestMemoryWatchDiff(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	globalVar, err := db.ResolveVariableExpression("g_int")
+	expect.Nil(t, err)
+
+	_, err = db.AddMemoryWatch(globalVar.Address, 0)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.AddMemoryWatch(globalVar.Address, MaxMemoryWatchSize+1)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.AddMemoryWatch(0, 8)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	watch, err := db.AddMemoryWatch(globalVar.Address, 8)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(db.MemoryWatches.List()))
+
+	expect.Equal(t, 0, len(db.DiffMemoryWatches()))
+
+	_, err = db.StepOver()
+	expect.Nil(t, err)
+
+	diffs := db.DiffMemoryWatches()
+	expect.Equal(t, 1, len(diffs))
+	expect.Equal(t, watch, diffs[0].MemoryWatch)
+	expect.Nil(t, diffs[0].Err)
+	expect.Equal(
+		t,
+		[]MemoryByteDiff{{Offset: 0, Old: 0, New: 1}},
+		diffs[0].Bytes)
+
+	// The snapshot is updated after each diff.
+	expect.Equal(t, 0, len(db.DiffMemoryWatches()))
+
+	_, err = db.StepOver()
+	expect.Nil(t, err)
+
+	diffs = db.DiffMemoryWatches()
+	expect.Equal(t, 1, len(diffs))
+	expect.Equal(
+		t,
+		[]MemoryByteDiff{{Offset: 0, Old: 1, New: 42}},
+		diffs[0].Bytes)
+
+	expect.True(t, db.MemoryWatches.Remove(watch.Id))
+	expect.False(t, db.MemoryWatches.Remove(watch.Id))
+	expect.Equal(t, 0, len(db.MemoryWatches.List()))
+}
+
+func (DebuggerSuite) TestCompareMemory(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	marshmallow, err := db.ResolveVariableExpression("marshmallow")
+	expect.Nil(t, err)
+
+	milkshake, err := db.ResolveVariableExpression("milkshake")
+	expect.Nil(t, err)
+
+	firstCat, err := db.ResolveVariableExpression("cats[0]")
+	expect.Nil(t, err)
+
+	_, err = db.CompareMemory(marshmallow.Address, milkshake.Address, 0)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.CompareMemory(0, milkshake.Address, 16)
+	expect.Error(t, err, "failed to read memory")
+
+	comparison, err := db.CompareMemory(
+		marshmallow.Address,
+		firstCat.Address,
+		16)
+	expect.Nil(t, err)
+	expect.Equal(t, 16, comparison.Size)
+	expect.Equal(t, 0, comparison.NumDiffs)
+	expect.Equal(t, 0, len(comparison.Diffs))
+
+	// The names' addresses differ, as well as the color bit field (age 4 with
+	// color 1 vs color 3).
+	comparison, err = db.CompareMemory(
+		marshmallow.Address,
+		milkshake.Address,
+		16)
+	expect.Nil(t, err)
+	expect.True(t, comparison.NumDiffs >= 2)
+	expect.Equal(t, comparison.NumDiffs, len(comparison.Diffs))
+	expect.Equal(
+		t,
+		MemoryCompareDiff{Offset: 8, Left: 0x24, Right: 0x64},
+		comparison.Diffs[len(comparison.Diffs)-1])
+
+	db.MemoryCompareLimit = 1
+	limited, err := db.CompareMemory(
+		marshmallow.Address,
+		milkshake.Address,
+		16)
+	expect.Nil(t, err)
+	expect.Equal(t, comparison.NumDiffs, limited.NumDiffs)
+	expect.Equal(t, 1, len(limited.Diffs))
+	expect.Equal(t, comparison.Diffs[0], limited.Diffs[0])
+
+	expected, err := milkshake.Bytes()
+	expect.Nil(t, err)
+
+	comparison, err = db.CompareMemoryWithBytes(milkshake.Address, expected)
+	expect.Nil(t, err)
+	expect.Equal(t, 0, comparison.NumDiffs)
+
+	expected = append([]byte{}, expected...)
+	expected[8] ^= 0xff
+	comparison, err = db.CompareMemoryWithBytes(milkshake.Address, expected)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, comparison.NumDiffs)
+	expect.Equal(
+		t,
+		[]MemoryCompareDiff{{Offset: 8, Left: 0x64, Right: 0x9b}},
+		comparison.Diffs)
+}
+
+func (DebuggerSuite) TestDisplays(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.AddDisplay(" ", NaturalFormat)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	display, err := db.AddDisplay("g_int", HexFormat)
+	expect.Nil(t, err)
+	expect.Equal(t, "1: /x g_int", display.String())
+
+	missing, err := db.AddDisplay("no_such_variable", NaturalFormat)
+	expect.Nil(t, err)
+	expect.Equal(t, "2: no_such_variable", missing.String())
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	checkDisplays := func(expected uint64) {
+		values := db.EvaluateDisplays()
+		expect.Equal(t, 2, len(values))
+
+		expect.Equal(t, display, values[0].Display)
+		expect.Nil(t, values[0].Err)
+
+		val, err := values[0].Value.DecodeSimpleValue()
+		expect.Nil(t, err)
+		expect.Equal(t, expected, val.(uint64))
+
+		// An invalid display does not affect the other displays.
+		expect.Equal(t, missing, values[1].Display)
+		expect.NotNil(t, values[1].Err)
+		expect.Nil(t, values[1].Value)
+	}
+
+	checkDisplays(0)
+
+	_, err = db.StepOver()
+	expect.Nil(t, err)
+
+	checkDisplays(1)
+
+	// Display evaluations are not recorded as evaluated results.
+	expect.Equal(t, 0, len(db.EvaluatedResults.List()))
+
+	expect.True(t, db.Displays.Remove(missing.Id))
+	expect.False(t, db.Displays.Remove(missing.Id))
+	expect.Equal(t, 1, len(db.Displays.List()))
+	expect.Equal(t, 1, len(db.EvaluateDisplays()))
+}
+
+func (DebuggerSuite) TestReadGlobalVariable(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	checkVar := func(expected uint64) {
+		globalVar, err := db.ResolveVariableExpression("g_int")
+		expect.Nil(t, err)
+		expect.Equal(t, expression.UintKind, globalVar.Kind)
+		expect.Equal(t, 8, globalVar.ByteSize)
+
+		val, err := globalVar.DecodeSimpleValue()
+		expect.Nil(t, err)
+		expect.Equal(t, expected, val.(uint64))
+	}
+
+	checkVar(0)
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+
+	checkVar(1)
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+
+	checkVar(42)
+
+	data, err := db.ResolveVariableExpression("someone->pets[0].name")
+	expect.Nil(t, err)
+	expect.True(t, data.IsCharPointer())
+
+	name, err := data.ReadCString()
+	expect.Nil(t, err)
+	expect.Equal(t, "Marshmallow", name)
+
+	data, err = db.ResolveVariableExpression("sy.pets[2].name[3]")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.CharKind, data.Kind)
+
+	char, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, 'k', char.(byte))
+	expect.True(t, strings.HasSuffix(data.Format(""), "(char): 'k' (107)"))
+
+	decoded, err := db.EvaluateExpression("cats[1]")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.StructKind, decoded.Type.Kind)
+	expect.Equal(t, "cat", decoded.TypeName)
+	expect.True(t, decoded.HasAddress)
+	expect.Equal(t, decoded.Type.ByteSize, len(decoded.Bytes))
+
+	fields := decoded.Value.(map[string]interface{})
+	expect.Equal(t, int32(8), fields["age"].(int32))
+	expect.Equal(t, int32(2), fields["color"].(int32))
+
+	decoded, err = db.EvaluateExpression("g_int")
+	expect.Nil(t, err)
+	expect.Equal(t, uint64(42), decoded.Value.(uint64))
+
+	data, err = db.ResolveVariableExpression("cats[1].age")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, data.Kind)
+	expect.Equal(t, 4, data.ByteSize)
+
+	age, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, 8, age.(int32))
+
+	data, err = db.ResolveVariableExpression("cats[1].color")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, data.Kind)
+	expect.Equal(t, 4, data.ByteSize)
+
+	color, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, 2, color.(int32))
+}
+
+func (DebuggerSuite) TestReadHeaderDefinedGlobals(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/comdat")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("bump_from_other"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	// inline variables (single definition shared by both compile units)
+
+	decoded, err := db.EvaluateExpression("g_shared_counter")
+	expect.Nil(t, err)
+	expect.Equal(t, int32(10), decoded.Value.(int32))
+
+	decoded, err = db.EvaluateExpression("instances")
+	expect.Nil(t, err)
+	expect.Equal(t, int32(3), decoded.Value.(int32))
+
+	decoded, err = db.EvaluateExpression("g_header_limit")
+	expect.Nil(t, err)
+	expect.Equal(t, int32(42), decoded.Value.(int32))
+
+	// constexpr static data members without storage
+
+	data, err := db.ResolveVariableExpression("max_size")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, data.Kind)
+	expect.Equal(t, 8, data.ByteSize)
+
+	size, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int64(1024), size.(int64))
+
+	data, err = db.ResolveVariableExpression("default_level")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, data.Kind)
+	expect.True(t, strings.HasSuffix(data.Format(""), "(level): mid"))
+
+	variables, err := db.ListVariableDeclarations("")
+	expect.Nil(t, err)
+
+	names := []string{}
+	for _, variable := range variables {
+		names = append(names, variable.Name)
+	}
+	expect.Equal(
+		t,
+		[]string{
+			"default_level",
+			"g_header_limit",
+			"g_shared_counter",
+			"instances",
+			"max_size",
+		},
+		names)
+}
+
+func (DebuggerSuite) TestConditionalWatchPoint(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	counter, err := db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	_, err = db.EvaluateCondition("g_counter ==")
+	expect.Error(t, err, "malformed condition")
+
+	satisfied, err := db.EvaluateCondition("g_counter")
+	expect.Nil(t, err)
+	expect.False(t, satisfied)
+
+	satisfied, err = db.EvaluateCondition("g_counter <= 0")
+	expect.Nil(t, err)
+	expect.True(t, satisfied)
+
+	watchPoint, err := db.WatchPoints.Set(
+		db.NewAddressResolver(counter.Address),
+		stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+		true)
+	expect.Nil(t, err)
+
+	// The condition is validated when set.
+	err = watchPoint.SetCondition("g_counter ==")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+	expect.Error(t, err, "malformed condition")
+
+	err = watchPoint.SetCondition("g_counter > (1")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+	expect.Error(t, err, "invalid condition operand")
+	expect.Equal(t, "", watchPoint.Condition())
+
+	numBreakPoints := len(db.BreakPoints.List())
+	_, err = db.BreakPoints.SetWithOptions(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true,
+		stoppoint.StopPointOptions{
+			Condition: "g_counter.",
+		})
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+	expect.Equal(t, numBreakPoints, len(db.BreakPoints.List()))
+
+	err = watchPoint.SetCondition("g_counter > 6")
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, watchPoint.Id(), status.StopPoints[0].Id())
+
+	value, err := db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	decoded, err := value.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, 7, decoded.(int32))
+
+	err = watchPoint.SetCondition("g_counter == 9")
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+
+	value, err = db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	decoded, err = value.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, 9, decoded.(int32))
+
+	err = watchPoint.SetCondition("g_counter < 0")
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+}
+
+func (DebuggerSuite) TestDebugRegisters(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	info, err := db.DebugRegisters()
+	expect.Nil(t, err)
+	expect.Equal(t, 0, info.Control)
+	for idx, slot := range info.Slots {
+		expect.False(t, slot.IsEnabled())
+		expect.Nil(t, info.Sites[idx])
+		expect.Equal(t, 0, len(info.StopPoints[idx]))
+	}
+
+	counter, err := db.ResolveVariableExpression("g_counter")
+	expect.Nil(t, err)
+
+	watchPoint, err := db.WatchPoints.Set(
+		db.NewAddressResolver(counter.Address),
+		stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+		true)
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, watchPoint.Id(), status.StopPoints[0].Id())
+
+	info, err = db.DebugRegisters()
+	expect.Nil(t, err)
+	expect.Equal(t, db.currentThread().Tid, info.Tid)
+
+	slot := info.Slots[0]
+	expect.Equal(t, counter.Address, slot.Address)
+	expect.True(t, slot.IsEnabled())
+	expect.Equal(t, stoppoint.WriteMode, slot.Mode)
+	expect.Equal(t, 4, slot.WatchSize)
+	expect.True(t, slot.Triggered)
+
+	expect.NotNil(t, info.Sites[0])
+	expect.Equal(t, counter.Address, info.Sites[0].Key().VirtualAddress)
+	expect.Equal(t, 1, len(info.StopPoints[0]))
+	expect.Equal(t, watchPoint.Id(), info.StopPoints[0][0].Id())
+
+	for idx := 1; idx < stoppoint.NumHardwareStopSites; idx++ {
+		expect.False(t, info.Slots[idx].IsEnabled())
+		expect.False(t, info.Slots[idx].Triggered)
+		expect.Nil(t, info.Sites[idx])
+	}
+
+	err = db.WatchPoints.Remove(watchPoint.Id())
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	_, err = db.DebugRegisters()
+	expect.True(t, errors.Is(err, ErrProcessExited))
+}
+
+func (DebuggerSuite) TestConditionalBreakPointWithFunctionCall(
+	t *testing.T,
+) {
+	for _, disableAll := range []bool{false, true} {
+		db, err := StartCmdAndAttachTo("test_targets/recursion")
+		expect.Nil(t, err)
+		defer db.Close()
+
+		db.DisableAllBreakPointsInConditionCalls = disableAll
+
+		descend, err := db.BreakPoints.Set(
+			db.NewFunctionResolver("descend"),
+			stoppoint.NewBreakSiteType(false),
+			true)
+		expect.Nil(t, err)
+
+		// descend(2) == 2 + 1 + 0.  Each call recursively triggers the break
+		// point whose condition is being evaluated.
+		err = descend.SetCondition("descend(depth) == 3")
+		expect.Nil(t, err)
+
+		bottom, err := db.BreakPoints.Set(
+			db.NewLineResolver("recursion.cpp", 7),
+			stoppoint.NewBreakSiteType(false),
+			true)
+		expect.Nil(t, err)
+
+		err = bottom.SetIgnoreCount(1)
+		expect.Nil(t, err)
+
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, 1, len(status.StopPoints))
+		expect.Equal(t, descend.Id(), status.StopPoints[0].Id())
+
+		depth, err := db.ResolveVariableExpression("depth")
+		expect.Nil(t, err)
+
+		decoded, err := depth.DecodeSimpleValue()
+		expect.Nil(t, err)
+		expect.Equal(t, 2, decoded.(int32))
+
+		// The break points are re-enabled after the calls, and hits during the
+		// calls do not count towards the ignore count.
+		expect.True(t, descend.IsEnabled())
+		expect.True(t, bottom.IsEnabled())
+		expect.Equal(t, 1, bottom.IgnoreCount())
+
+		// descend(1) / descend(0) do not satisfy the condition, and the bottom
+		// hit is ignored.
+		status, err = db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Exited)
+		expect.Equal(t, 0, bottom.IgnoreCount())
+	}
+}
+
+func (DebuggerSuite) TestScopedWatchPoint(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/scoped_watch")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	exits := []WatchPointScopeExit{}
+	db.WatchWatchPointScopeExits(func(exit WatchPointScopeExit) {
+		exits = append(exits, exit)
+	})
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("scoped_watch.cpp", 4),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	resumeUntilAccumulate := func() {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, SoftwareTrap, status.TrapKind)
+		expect.Equal(t, 4, status.Line)
+	}
+
+	setScopedWatchPoint := func() *stoppoint.StopPoint {
+		local, err := db.ResolveVariableExpression("local")
+		expect.Nil(t, err)
+
+		point, err := db.SetScopedWatchPoint(
+			db.NewAddressResolver(local.Address),
+			stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+			true,
+			"")
+		expect.Nil(t, err)
+
+		scope, ok := db.WatchPointScope(point.Id())
+		expect.True(t, ok)
+		expect.Equal(t, "accumulate", scope.FunctionName)
+
+		// int local = value; local = local * 2;
+		for i := 0; i < 2; i++ {
+			status, err := db.ResumeAllUntilSignal()
+			expect.Nil(t, err)
+			expect.True(t, status.Stopped)
+			expect.Equal(t, HardwareTrap, status.TrapKind)
+			expect.Equal(t, 1, len(status.StopPoints))
+			expect.Equal(t, point.Id(), status.StopPoints[0].Id())
+		}
+
+		return point
+	}
+
+	// Each scoped watch point is removed once accumulate returns, which
+	// releases its debug register for the next call's watch point.
+	numAuto := 2 * stoppoint.NumHardwareStopSites
+	var previous *stoppoint.StopPoint
+	for i := 0; i <= numAuto; i++ {
+		resumeUntilAccumulate()
+
+		if previous != nil {
+			expect.Equal(t, i, len(exits))
+			expect.Equal(t, previous.Id(), exits[i-1].WatchPoint.Id())
+			expect.True(t, exits[i-1].Removed)
+
+			_, ok := db.WatchPoints.Get(previous.Id())
+			expect.False(t, ok)
+
+			_, ok = db.WatchPointScope(previous.Id())
+			expect.False(t, ok)
+		}
+
+		expect.Equal(
+			t,
+			stoppoint.NumHardwareStopSites,
+			db.AvailableHardwareStopSites())
+
+		if i == numAuto {
+			break
+		}
+
+		previous = setScopedWatchPoint()
+		expect.Equal(
+			t,
+			stoppoint.NumHardwareStopSites-1,
+			db.AvailableHardwareStopSites())
+	}
+
+	db.WatchPointScopeMode = WatchPointScopeManual
+
+	point := setScopedWatchPoint()
+	resumeUntilAccumulate()
+
+	// The out of scope watch point is kept (and still occupies its debug
+	// register) in manual mode.
+	expect.Equal(t, numAuto+1, len(exits))
+	expect.Equal(t, point.Id(), exits[numAuto].WatchPoint.Id())
+	expect.False(t, exits[numAuto].Removed)
+
+	_, ok := db.WatchPoints.Get(point.Id())
+	expect.True(t, ok)
+
+	_, ok = db.WatchPointScope(point.Id())
+	expect.False(t, ok)
+
+	expect.Equal(
+		t,
+		stoppoint.NumHardwareStopSites-1,
+		db.AvailableHardwareStopSites())
+
+	// The kept watch point triggers on the next accumulate call's local.
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, HardwareTrap, status.TrapKind)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, point.Id(), status.StopPoints[0].Id())
+}
+
+func (DebuggerSuite) TestIgnoreCount(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	breakPoint, err := db.BreakPoints.Set(
+		db.NewLineResolver("counter.cpp", 5),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	readCounter := func() int32 {
+		value, err := db.ResolveVariableExpression("g_counter")
+		expect.Nil(t, err)
+
+		decoded, err := value.DecodeSimpleValue()
+		expect.Nil(t, err)
+		return decoded.(int32)
+	}
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, 0, readCounter())
+
+	err = breakPoint.SetIgnoreCount(-1)
+	expect.Error(t, err, "invalid ignore count")
+
+	err = breakPoint.SetIgnoreCount(3)
+	expect.Nil(t, err)
+	expect.Equal(t, 3, breakPoint.IgnoreCount())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, breakPoint.Id(), status.StopPoints[0].Id())
+	expect.Equal(t, 4, readCounter())
+	expect.Equal(t, 0, breakPoint.IgnoreCount())
+
+	// The ignore count is reset after it's consumed.
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 5, readCounter())
+
+	err = breakPoint.SetIgnoreCount(100)
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 96, breakPoint.IgnoreCount())
+}
+
+func (DebuggerSuite) TestCheckpoint(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.Checkpoint()
+	expect.Error(t, err, "before the process reaches its entry point")
+
+	breakPoint, err := db.BreakPoints.Set(
+		db.NewLineResolver("counter.cpp", 5),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	readCounter := func() int32 {
+		value, err := db.ResolveVariableExpression("g_counter")
+		expect.Nil(t, err)
+
+		decoded, err := value.DecodeSimpleValue()
+		expect.Nil(t, err)
+		return decoded.(int32)
+	}
+
+	for i := 0; i < 3; i++ {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+	}
+	expect.Equal(t, 2, readCounter())
+
+	originalPid := db.Pid
+
+	first, err := db.Checkpoint()
+	expect.Nil(t, err)
+	expect.Equal(t, 1, first.Id)
+	expect.NotEqual(t, originalPid, first.Pid)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 3, readCounter())
+
+	second, err := db.Checkpoint()
+	expect.Nil(t, err)
+	expect.Equal(t, 2, second.Id)
+	expect.Equal(t, 2, len(db.Checkpoints.List()))
+
+	err = db.DeleteCheckpoint(second.Id)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(db.Checkpoints.List()))
+
+	err = db.DeleteCheckpoint(second.Id)
+	expect.Error(t, err, "not found")
+
+	// Run the original process to completion.
+	err = db.BreakPoints.Remove(breakPoint.Id())
+	expect.Nil(t, err)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	status, err = db.RestartCheckpoint(first.Id)
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, first.Pid, db.Pid)
+	expect.Equal(t, first.Pid, status.Tid)
+	expect.Equal(t, 0, len(db.Checkpoints.List()))
+
+	// The restarted process resumes from the checkpoint's frozen state.
+	expect.Equal(t, 2, readCounter())
+
+	_, err = db.RestartCheckpoint(first.Id)
+	expect.Error(t, err, "not found")
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestMultipleDebuggers(t *testing.T) {
+	first, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer first.Close()
+
+	second, err := StartCmdAndAttachTo("test_targets/counter")
+	expect.Nil(t, err)
+	defer second.Close()
+
+	expect.NotEqual(t, first.Pid, second.Pid)
+
+	mainPoint, err := first.BreakPoints.Set(
+		first.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	returnPoint, err := second.BreakPoints.Set(
+		second.NewLineResolver("counter.cpp", 7),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	// Break points are not shared between the debuggers.
+	expect.Equal(t, 1, len(first.BreakPoints.List()))
+	expect.Equal(t, 1, len(second.BreakPoints.List()))
+
+	readCounter := func(db *Debugger) int32 {
+		value, err := db.ResolveVariableExpression("g_counter")
+		expect.Nil(t, err)
+
+		decoded, err := value.DecodeSimpleValue()
+		expect.Nil(t, err)
+		return decoded.(int32)
+	}
+
+	status, err := second.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, second.Pid, status.Tid)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, returnPoint.Id(), status.StopPoints[0].Id())
+	expect.Equal(t, 10, readCounter(second))
+
+	status, err = first.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, first.Pid, status.Tid)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, mainPoint.Id(), status.StopPoints[0].Id())
+	expect.Equal(t, 0, readCounter(first))
+
+	status, err = second.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	// The first process is unaffected by the second process' exit.
+	expect.False(t, first.Terminated())
+	expect.Equal(t, 0, readCounter(first))
+
+	status, err = first.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+}
+
+func (DebuggerSuite) TestSignalQueuedWithBreakPointTrap(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/queued_signal")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	waitBreakPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("wait_for_signal"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	checkpointBreakPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("checkpoint"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	readHandled := func() int32 {
+		value, err := db.ResolveVariableExpression("g_handled")
+		expect.Nil(t, err)
+
+		decoded, err := value.DecodeSimpleValue()
+		expect.Nil(t, err)
+		return decoded.(int32)
+	}
+
+	resumeUntilBreakPoint := func(point *stoppoint.StopPoint) {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, syscall.SIGTRAP, status.StopSignal)
+		expect.Equal(t, SoftwareTrap, status.TrapKind)
+		expect.Equal(t, 1, len(status.StopPoints))
+		expect.Equal(t, point.Id(), status.StopPoints[0].Id())
+		expect.Equal(t, syscall.Signal(0), status.PendingSignal)
+	}
+
+	// The signal is queued while the thread is stopped at the break point.
+	resumeUntilBreakPoint(waitBreakPoint)
+	expect.Equal(t, 0, readHandled())
+
+	err = syscall.Tgkill(db.Pid, db.Pid, syscall.SIGUSR1)
+	expect.Nil(t, err)
+
+	resumeUntilBreakPoint(checkpointBreakPoint)
+	expect.Equal(t, 1, readHandled())
+
+	// The signal is discarded when the signal is not passed.
+	resumeUntilBreakPoint(waitBreakPoint)
+
+	db.SignalPassPolicy.NoPass(syscall.SIGUSR1)
+	expect.False(t, db.SignalPassPolicy.ShouldPass(syscall.SIGUSR1))
+
+	err = syscall.Tgkill(db.Pid, db.Pid, syscall.SIGUSR1)
+	expect.Nil(t, err)
+
+	resumeUntilBreakPoint(checkpointBreakPoint)
+	expect.Equal(t, 1, readHandled())
+
+	db.SignalPassPolicy.Pass(syscall.SIGUSR1)
+	expect.True(t, db.SignalPassPolicy.ShouldPass(syscall.SIGUSR1))
+
+	// The signal stop is reported with the held signal.
+	resumeUntilBreakPoint(waitBreakPoint)
+
+	err = syscall.Tgkill(db.Pid, db.Pid, syscall.SIGUSR1)
+	expect.Nil(t, err)
+
+	status, err := db.StepInstruction()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, syscall.SIGUSR1, status.StopSignal)
+	expect.Equal(t, syscall.SIGUSR1, status.PendingSignal)
+	expect.True(t, strings.Contains(status.String(), "pending signal"))
+
+	resumeUntilBreakPoint(checkpointBreakPoint)
+	expect.Equal(t, 2, readHandled())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 2, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestMemoryCache(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/large_struct")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	expect.True(t, db.VirtualMemory.IsCacheEnabled())
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	first, err := db.ResolveVariableExpression("g_large.elements[0].id")
+	expect.Nil(t, err)
+	expect.Equal(t, "0", first.FormatValue())
+
+	second, err := db.ResolveVariableExpression("g_large.elements[1].id")
+	expect.Nil(t, err)
+	expect.Equal(t, "0", second.FormatValue())
+
+	// Writes must invalidate the cached page
+	updated, err := db.AssignVariable("g_large.elements[1].id", "7")
+	expect.Nil(t, err)
+	expect.Equal(t, "7", updated.FormatValue())
+	expect.Equal(t, "7", second.FormatValue())
+
+	_, err = db.WatchPoints.Set(
+		db.NewAddressResolver(first.Address),
+		stoppoint.NewWatchSiteType(stoppoint.WriteMode, 4),
+		true)
+	expect.Nil(t, err)
+
+	// The first write is in the initialization loop, and the second write is
+	// after the loop.
+	for i := 0; i < 2; i++ {
+		status, err = db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, 1, len(status.StopPoints))
+	}
+
+	// Resume must invalidate the cache
+	expect.Equal(t, "-1", first.FormatValue())
+	expect.Equal(t, "1", second.FormatValue())
+
+	last, err := db.ResolveVariableExpression("g_large.elements[1023]")
+	expect.Nil(t, err)
+
+	decoded, err := last.ToGoValue()
+	expect.Nil(t, err)
+
+	fields := decoded.(map[string]interface{})
+	expect.Equal(t, int32(1023), fields["id"].(int32))
+	expect.Equal(t, 511.5, fields["weight"].(float64))
+	expect.Equal(t, byte('a'+1023%26), fields["tag"].(byte))
+
+	db.VirtualMemory.SetCacheEnabled(false)
+	expect.False(t, db.VirtualMemory.IsCacheEnabled())
+	expect.Equal(t, "-1", first.FormatValue())
+}
+
+func (DebuggerSuite) TestCompileUnitProducers(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	units, err := db.ListCompileUnits()
+	expect.Nil(t, err)
+
+	found := false
+	for _, unit := range units {
+		if strings.HasSuffix(unit.Name, "hello_world.cpp") {
+			found = true
+			expect.True(t, strings.HasPrefix(unit.Producer, "GNU C++"))
+			expect.Equal(t, "", unit.KnownIssue)
+		}
+	}
+	expect.True(t, found)
+
+	warnings, err := db.NewProducerWarnings()
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(warnings))
+
+	expect.Equal(t, "", producerIssue("GNU C++17 12.2.0 -mtune=generic -g"))
+	expect.Equal(t, "", producerIssue("GNU C 4.5.1 -g"))
+	expect.NotEqual(t, "", producerIssue("GNU C 4.4.7 -g"))
+	expect.NotEqual(t, "", producerIssue("GNU C++ 3.4.6"))
+	expect.NotEqual(
+		t,
+		"",
+		producerIssue(
+			"Intel(R) C++ Intel(R) 64 Compiler XE for applications running on "+
+				"Intel(R) 64, Version 13.1.3.192 Build 20130607"))
+	expect.Equal(
+		t,
+		"",
+		producerIssue(
+			"Intel(R) C++ Intel(R) 64 Compiler XE for applications running on "+
+				"Intel(R) 64, Version 14.0.0.080 Build 20130728"))
+	expect.Equal(t, "", producerIssue("clang version 15.0.7"))
+}
+
+func (DebuggerSuite) TestModuleMappings(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	mappings, err := db.ListModuleMappings()
+	expect.Nil(t, err)
+
+	var executable *ModuleMapping
+	for idx, mapping := range mappings {
+		if mapping.ElfFileName == "" {
+			executable = &mappings[idx]
+		}
+	}
+	expect.NotNil(t, executable)
+	expect.True(t, executable.HasDebugInfo)
+	expect.Equal(t, 1, len(executable.CompileUnits))
+
+	unit := executable.CompileUnits[0]
+	expect.True(t, strings.HasSuffix(unit.Name, "hello_world.cpp"))
+	expect.Equal(t, 1, len(unit.Ranges))
+
+	mainAddress, err := db.LoadedElves.SymbolToVirtualAddress(
+		db.LoadedElves.SymbolsByName("main")[0])
+	expect.Nil(t, err)
+	expect.True(t, unit.Ranges.Contains(mainAddress))
+
+	// crt startup code (e.g., _start) has no debug info.
+	entryPoint := db.LoadedElves.EntryPoint()
+	expect.False(t, unit.Ranges.Contains(entryPoint))
+
+	found := false
+	for _, unmapped := range executable.Unmapped {
+		if unmapped.Contains(entryPoint) {
+			found = true
+			expect.Equal(t, ".text", unmapped.SectionName)
+		}
+		expect.False(t, unmapped.Contains(mainAddress))
+	}
+	expect.True(t, found)
+}
+
+func (DebuggerSuite) TestFollowExecSameMode(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exec_self")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("before_exec"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	helperPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("run_helper"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, "before_exec", status.FunctionName)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, ExecTrap, status.TrapKind)
+	expect.True(t, strings.HasSuffix(status.ExecutedProgram, "exec_self"))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "run_helper", status.FunctionName)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, helperPoint.Id(), status.StopPoints[0].Id())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestFollowExecNewMode(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exec_self")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	db.FollowExecMode = FollowExecNew
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("run_helper"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, ExecTrap, status.TrapKind)
+	expect.Equal(t, 0, len(db.BreakPoints.List()))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestCatchExecOff(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exec_self")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	db.CatchExec = false
+
+	helperPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("run_helper"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "run_helper", status.FunctionName)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, helperPoint.Id(), status.StopPoints[0].Id())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+}
+
+func (DebuggerSuite) TestExecFromMultiThreadedProcess(t *testing.T) {
+	// The helper is executed from a non-leader thread, and then from the leader
+	// thread, while the idle thread is running.
+	for _, args := range [][]string{nil, {"leader"}} {
+		db, err := StartCmdAndAttachTo("test_targets/exec_threaded", args...)
+		expect.Nil(t, err)
+		defer db.Close()
+
+		helperPoint, err := db.BreakPoints.Set(
+			db.NewFunctionResolver("run_helper"),
+			stoppoint.NewBreakSiteType(false),
+			true)
+		expect.Nil(t, err)
+
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, ExecTrap, status.TrapKind)
+		expect.Equal(t, db.Pid, status.Tid)
+		expect.True(
+			t,
+			strings.HasSuffix(status.ExecutedProgram, "exec_threaded"))
+
+		// Only the executing thread (re-keyed to the pid) survives the exec.
+		current, threads := db.ListThreads()
+		expect.Equal(t, db.Pid, current.Tid)
+		expect.Equal(t, 1, len(threads))
+		expect.Equal(t, db.Pid, threads[0].Tid)
+
+		status, err = db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, SoftwareTrap, status.TrapKind)
+		expect.Equal(t, "run_helper", status.FunctionName)
+		expect.Equal(t, 1, len(status.StopPoints))
+		expect.Equal(t, helperPoint.Id(), status.StopPoints[0].Id())
+
+		status, err = db.ResumeAllUntilSignal()
+		expect.Nil(t, err)
+		expect.True(t, status.Exited)
+		expect.Equal(t, 0, status.ExitStatus)
+	}
+}
+
+func (DebuggerSuite) TestForwardInterruptToResumedProcess(t *testing.T) {
+	resumed, err := StartCmdAndAttachTo("test_targets/run_endlessly")
+	expect.Nil(t, err)
+	defer resumed.Close()
+
+	stopped, err := StartCmdAndAttachTo("test_targets/hello_world")
+	expect.Nil(t, err)
+	defer stopped.Close()
+
+	mainBreakPoint, err := stopped.BreakPoints.Set(
+		stopped.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	type result struct {
+		status *ThreadStatus
+		err    error
+	}
+
+	resultChan := make(chan result, 1)
+	go func() {
+		status, err := resumed.ResumeAllUntilSignal()
+		resultChan <- result{status, err}
+	}()
+
+	for !resumed.signal.(*Signaler).isResumed.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Simulates the terminal's interrupt, which is only delivered to the
+	// debugger's process group.
+	err = syscall.Kill(os.Getpid(), syscall.SIGINT)
+	expect.Nil(t, err)
+
+	select {
+	case res := <-resultChan:
+		expect.Nil(t, res.err)
+		expect.True(t, res.status.Stopped)
+		expect.Equal(t, syscall.SIGINT, res.status.StopSignal)
+	case <-time.After(10 * time.Second):
+		t.Fatal("interrupt was not forwarded to the resumed process")
+	}
+
+	// The interrupt was not forwarded to the stopped process, i.e., there's no
+	// pending SIGINT.
+	status, err := stopped.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, syscall.SIGTRAP, status.StopSignal)
+	expect.Equal(t, mainBreakPoint.Id(), status.StopPoints[0].Id())
+}
+
+func (DebuggerSuite) TestSignalCatchPolicy(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/queued_signal")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	err = db.SignalCatchPolicy.Ignore(syscall.SIGINT)
+	expect.Error(t, err, "cannot ignore")
+
+	err = db.SignalCatchPolicy.Ignore(syscall.SIGUSR1)
+	expect.Nil(t, err)
+	expect.False(t, db.SignalCatchPolicy.ShouldStop(syscall.SIGUSR1))
+	expect.Equal(
+		t,
+		[]syscall.Signal{syscall.SIGUSR1},
+		db.SignalCatchPolicy.IgnoredSignals())
+
+	waitBreakPoint, err := db.BreakPoints.Set(
+		db.NewFunctionResolver("wait_for_signal"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, waitBreakPoint.Id(), status.StopPoints[0].Id())
+
+	err = syscall.Tgkill(db.Pid, db.Pid, syscall.SIGUSR1)
+	expect.Nil(t, err)
+
+	// The ignored signal is delivered to the handler without stopping.
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, syscall.SIGTRAP, status.StopSignal)
+	expect.Equal(t, waitBreakPoint.Id(), status.StopPoints[0].Id())
+
+	value, err := db.ResolveVariableExpression("g_handled")
+	expect.Nil(t, err)
+
+	decoded, err := value.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(1), decoded.(int32))
+
+	db.SignalCatchPolicy.CatchAll()
+	expect.True(t, db.SignalCatchPolicy.ShouldStop(syscall.SIGUSR1))
+}
+
+func (DebuggerSuite) TestIndexBoundsCheck(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	_, err = db.ResolveVariableExpression("cats[3]")
+	expect.Error(t, err, "index 3 out of bounds for [3]cat")
+
+	_, err = db.ResolveVariableExpression("cats[-1]")
+	expect.Error(t, err, "index -1 out of bounds for [3]cat")
+
+	_, err = db.ResolveVariableExpression("cats[sy.num_pets]")
+	expect.Error(t, err, "index 3 out of bounds for [3]cat")
+
+	// g_int is an uint64 (0 before main's first statement)
+	data, err := db.ResolveVariableExpression("cats[g_int].name")
+	expect.Nil(t, err)
+
+	name, err := data.ReadCString()
+	expect.Nil(t, err)
+	expect.Equal(t, "Marshmallow", name)
+
+	// pointers are unbounded
+	data, err = db.ResolveVariableExpression("sy.pets[2].name")
+	expect.Nil(t, err)
+
+	name, err = data.ReadCString()
+	expect.Nil(t, err)
+	expect.Equal(t, "Milkshake", name)
+}
+
+func (DebuggerSuite) TestReadInt128(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/int128")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	data, err := db.ResolveVariableExpression("g_int128")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, data.Kind)
+	expect.Equal(t, 16, data.ByteSize)
+	expect.Equal(t, "-1267650600228229401496703205418", data.FormatValue())
+
+	value, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		expression.Int128{High: 0xffffffefffffffff, Low: 0xffffffffffffffd6},
+		value.(expression.Int128))
+	expect.Equal(t, "-0x1000000000000000000000002a", fmt.Sprintf("%#x", value))
+
+	decoded, err := db.EvaluateExpression("g_uint128")
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		"340282366920938463463374607431768211455",
+		fmt.Sprintf("%v", decoded.Value))
+	expect.Equal(
+		t,
+		"ffffffffffffffffffffffffffffffff",
+		fmt.Sprintf("%x", decoded.Value))
+
+	data, err = db.ResolveVariableExpression("g_values[g_small_int128]")
+	expect.Nil(t, err)
+	expect.Equal(t, "30", data.FormatValue())
+
+	_, err = db.ResolveVariableExpression("g_values[g_int128]")
+	expect.Error(
+		t,
+		err,
+		"index -1267650600228229401496703205418 out of bounds for [3]int")
+
+	satisfied, err := db.EvaluateCondition("g_uint128 > g_int128")
+	expect.Nil(t, err)
+	expect.True(t, satisfied)
+
+	updated, err := db.AssignVariable("g_small_int128", "-5")
+	expect.Nil(t, err)
+	expect.Equal(t, "-5", updated.FormatValue())
+
+	// ABI support for passing __int128 arguments is out of scope.
+	_, err = db.ResolveVariableExpression("add_one(g_small_int128)")
+	expect.Error(t, err, "unsupported integer size (16)")
+}
+
+func (DebuggerSuite) TestVariableLengthArray(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/vla")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("checkpoint"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	err = db.InspectFrame(1)
+	expect.Nil(t, err)
+
+	values, err := db.ResolveVariableExpression("values")
+	expect.Nil(t, err)
+	expect.Equal(t, "[3]int32", values.TypeName())
+	expect.Equal(t, 12, values.ByteSize)
+
+	decoded, err := values.ToGoValue()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		[]interface{}{int32(0), int32(100), int32(200)},
+		decoded.([]interface{}))
+
+	matrix, err := db.ResolveVariableExpression("matrix")
+	expect.Nil(t, err)
+	expect.Equal(t, "[3][4]int32", matrix.TypeName())
+	expect.Equal(t, 48, matrix.ByteSize)
+
+	element, err := db.ResolveVariableExpression("matrix[2][3]")
+	expect.Nil(t, err)
+	expect.Equal(t, "23", element.FormatValue())
+
+	_, err = db.ResolveVariableExpression("values[3]")
+	expect.Error(t, err, "index 3 out of bounds for [3]int32")
+}
+
+func (DebuggerSuite) TestVariableLengthArrayUninitializedBound(
+	t *testing.T,
+) {
+	db, err := StartCmdAndAttachTo("test_targets/vla")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("first"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, "first", status.FunctionName)
+
+	// items' bound is garbage (left behind by scribble) at function entry, and
+	// the implausible dimension is treated as unbounded.
+	items, err := db.ResolveVariableExpression("items")
+	expect.Nil(t, err)
+	expect.Equal(t, "[0]int32", items.TypeName())
+	expect.Equal(t, 0, items.ByteSize)
+
+	_, err = db.ResolveVariableExpression("items[0]")
+	expect.Error(t, err, "index 0 out of bounds for [0]int32")
+}
+
+func (DebuggerSuite) TestVariableLengthArrayCount(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/vla_count")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("checkpoint"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	err = db.InspectFrame(1)
+	expect.Nil(t, err)
+
+	// squares' DW_AT_count references the n parameter.
+	squares, err := db.ResolveVariableExpression("squares")
+	expect.Nil(t, err)
+	expect.Equal(t, "[5]int32", squares.TypeName())
+	expect.Equal(t, 20, squares.ByteSize)
+
+	decoded, err := squares.ToGoValue()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		[]interface{}{int32(0), int32(1), int32(4), int32(9), int32(16)},
+		decoded.([]interface{}))
+
+	element, err := db.ResolveVariableExpression("squares[4]")
+	expect.Nil(t, err)
+	expect.Equal(t, "16", element.FormatValue())
+
+	// cubes' DW_AT_count is unreadable, hence cubes is treated as unbounded.
+	cubes, err := db.ResolveVariableExpression("cubes")
+	expect.Nil(t, err)
+	expect.Equal(t, "[0]int32", cubes.TypeName())
+	expect.Equal(t, 0, cubes.ByteSize)
+
+	_, err = db.ResolveVariableExpression("cubes[0]")
+	expect.Error(t, err, "index 0 out of bounds for [0]int32")
+}
+
+func (DebuggerSuite) TestLinkageNameLookup(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/namespaced")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("_ZN5outer5inner5twiceEi"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 1, len(status.StopPoints))
+
+	inner, err := db.ResolveVariableExpression("_ZN5outer5inner5valueE")
+	expect.Nil(t, err)
+	expect.Equal(t, "42", inner.FormatValue())
+	expect.Equal(t, "outer::inner::value (int32): 42", inner.Format(""))
+
+	outer, err := db.ResolveVariableExpression("_ZN5outer5valueE")
+	expect.Nil(t, err)
+	expect.Equal(t, "7", outer.FormatValue())
+
+	result, err := db.ResolveVariableExpression("_ZN5outer5inner5twiceEi(3)")
+	expect.Nil(t, err)
+	expect.Equal(t, "6", result.FormatValue())
+
+	_, err = db.ResolveVariableExpression("_ZN5outer5otherE")
+	expect.Error(t, err, "variable not found (_ZN5outer5otherE)")
+}
+
+func (DebuggerSuite) TestCallStaticMemberFunction(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/expr")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	result, err := db.ResolveVariableExpression("cat::add_ages(4, 8)")
+	expect.Nil(t, err)
+	expect.Equal(t, "12", result.FormatValue())
+
+	// static member function called through an instance
+	result, err = db.ResolveVariableExpression("lexa.add_ages(1, 2)")
+	expect.Nil(t, err)
+	expect.Equal(t, "3", result.FormatValue())
+
+	result, err = db.ResolveVariableExpression("lexa.increase_age()")
+	expect.Nil(t, err)
+	expect.Equal(t, "9", result.FormatValue())
+
+	_, err = db.ResolveVariableExpression("cat::increase_age()")
+	expect.Error(t, err, "variable not found (cat::increase_age)")
+}
+
+func (DebuggerSuite) TestConditionalExpression(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/expr")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	evaluate := func(expr string) string {
+		result, err := db.ResolveVariableExpression(expr)
+		expect.Nil(t, err)
+		return result.FormatValue()
+	}
+
+	expect.Equal(t, "1", evaluate("true ? 1 : 2"))
+	expect.Equal(t, "2", evaluate("false ? 1 : 2"))
+	expect.Equal(t, "2", evaluate("lexa.age ? 2 : 3"))
+
+	// right associative
+	expect.Equal(t, "2", evaluate("false ? 1 : true ? 2 : 3"))
+	expect.Equal(t, "3", evaluate("false ? 1 : false ? 2 : 3"))
+	expect.Equal(t, "2", evaluate("true ? false ? 1 : 2 : 3"))
+
+	expect.Equal(t, "8", evaluate("(true ? lexa : milkshake).age"))
+	expect.Equal(t, "7", evaluate("cat::add_ages(true ? 3 : 0, 4)"))
+
+	// scalar branches are unified
+	expect.Equal(t, "1", evaluate("true ? 1 : 2.5"))
+	result, err := db.ResolveVariableExpression("true ? 1 : 2.5")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.FloatKind, result.Kind)
+	expect.Equal(t, 8, result.ByteSize)
+
+	result, err = db.ResolveVariableExpression("true ? 'a' : 2")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, result.Kind)
+	expect.Equal(t, "97", result.FormatValue())
+
+	result, err = db.ResolveVariableExpression("true ? 1 : 4294967296")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.IntKind, result.Kind)
+	expect.Equal(t, 8, result.ByteSize)
+
+	// only the selected branch's function is called
+	expect.Equal(
+		t,
+		"5",
+		evaluate("false ? lexa.increase_age() : milkshake.increase_age()"))
+	expect.Equal(t, "8", evaluate("lexa.age"))
+	expect.Equal(t, "5", evaluate("milkshake.age"))
+
+	expect.Equal(
+		t,
+		"9",
+		evaluate("lexa.age ? lexa.increase_age() : milkshake.increase_age()"))
+	expect.Equal(t, "9", evaluate("lexa.age"))
+	expect.Equal(t, "5", evaluate("milkshake.age"))
+
+	// errors in the unselected branch are ignored
+	expect.Equal(t, "1", evaluate("true ? 1 : no_such_cat.age"))
+	expect.Equal(t, "1", evaluate("true ? 1 : false ? no_such_cat : \"meow\""))
+
+	_, err = db.ResolveVariableExpression("false ? 1 : no_such_cat.age")
+	expect.Error(t, err, "variable not found (no_such_cat)")
+
+	_, err = db.ResolveVariableExpression("lexa ? 1 : 2")
+	expect.Error(t, err, "invalid condition (cat)")
+
+	_, err = db.ResolveVariableExpression("true ? 1")
+	expect.NotNil(t, err)
+
+	_, err = db.ResolveVariableExpression("true ? 1 : ")
+	expect.NotNil(t, err)
+}
+
+func (DebuggerSuite) TestFormatScalarFields(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/scalar_fields")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	settings, err := db.ResolveVariableExpression("g_settings")
+	expect.Nil(t, err)
+
+	expect.Equal(
+		t,
+		"g_settings: {\n"+
+			"  .enabled (bool): true,\n"+
+			"  .initial (char): 'x' (120),\n"+
+			"  .background (color): blue,\n"+
+			"  .volume (level): high,\n"+
+			"  .palette: [\n"+
+			"    [0] (color): green,\n"+
+			"    [1] (color): red,\n"+
+			"    [2] (color): blue,\n"+
+			"  ],\n"+
+			"}",
+		settings.Format(""))
+	expect.Equal(
+		t,
+		"{enabled=true, initial='x' (120), background=blue, volume=high, "+
+			"palette={green, red, blue}}",
+		settings.FormatExpandedValue())
+
+	color, err := db.ResolveVariableExpression("g_color")
+	expect.Nil(t, err)
+	expect.Equal(t, "g_color (color): green", color.Format(""))
+
+	// values without matching enumerator are printed as numeric values.
+	unnamed, err := db.ResolveVariableExpression("g_unnamed_color")
+	expect.Nil(t, err)
+	expect.Equal(t, "(color)7", unnamed.FormatValue())
+
+	// binary / octal output is zero padded to the value's byte size.
+	expect.Equal(
+		t,
+		"g_settings: {\n"+
+			"  .enabled (bool): 0b00000001,\n"+
+			"  .initial (char): 0b01111000,\n"+
+			"  .background (color): 0b"+strings.Repeat("0", 30)+"10,\n"+
+			"  .volume (level): 0b11001000,\n"+
+			"  .palette: [\n"+
+			"    [0] (color): 0b"+strings.Repeat("0", 31)+"1,\n"+
+			"    [1] (color): 0b"+strings.Repeat("0", 32)+",\n"+
+			"    [2] (color): 0b"+strings.Repeat("0", 30)+"10,\n"+
+			"  ],\n"+
+			"}",
+		settings.FormatWithIntegerFormat("", 0, BinaryFormat))
+	expect.Equal(
+		t,
+		"g_unnamed_color (color): 000000000007",
+		unnamed.FormatWithIntegerFormat("", 0, OctalFormat))
+}
+
+func (DebuggerSuite) TestCallTimeout(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/expr")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	pc := status.NextInstructionAddress
+
+	db.CallTimeout = 100 * time.Millisecond
+
+	_, err = db.ResolveVariableExpression("spin()")
+	expect.True(t, errors.Is(err, ErrCallTimedOut))
+
+	// The thread's state is restored after the timeout.
+	state, err := db.currentThread().Registers.GetState()
+	expect.Nil(t, err)
+	expect.Equal(t, pc, state.ProgramCounter())
+
+	result, err := db.ResolveVariableExpression("cat::add_ages(1, 2)")
+	expect.Nil(t, err)
+	expect.Equal(t, "3", result.FormatValue())
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+}
+
+func (DebuggerSuite) TestUnwindOnTerminate(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exit_on_return")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	expect.True(t, db.UnwindOnTerminate)
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("compute"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	pc := status.NextInstructionAddress
+	numSites := len(db.stopSites.AllocatedSites())
+
+	_, err = db.ResolveVariableExpression("explode()")
+	expect.True(t, errors.Is(err, ErrCallUnwound))
+	expect.Equal(t, numSites, len(db.stopSites.AllocatedSites()))
+
+	// The thread's state is restored after the unwind.
+	state, err := db.currentThread().Registers.GetState()
+	expect.Nil(t, err)
+	expect.Equal(t, pc, state.ProgramCounter())
+
+	result, err := db.ResolveVariableExpression("x")
+	expect.Nil(t, err)
+	expect.Equal(t, "3", result.FormatValue())
+
+	db.UnwindOnTerminate = false
+
+	_, err = db.ResolveVariableExpression("explode()")
+	expect.Error(t, err, "thread unexpectedly exited during function invocation")
+	expect.True(t, db.processTerminated())
+
+	// The call's internal (entry point / std::terminate) sites are released
+	// even when the call does not return.
+	expect.Equal(t, numSites, len(db.stopSites.AllocatedSites()))
+}
+
+func (DebuggerSuite) TestStepOutUntilProcessExit(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/exit_on_return")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("compute"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	// compute never returns since leave exits the process.
+	_, err = db.StepOut()
+	expect.True(t, errors.Is(err, ErrProcessExited))
+	expect.Error(t, err, "exited with status: 3")
+
+	expect.True(t, db.Exited())
+
+	_, frames := db.BacktraceStack()
+	expect.Equal(t, 0, len(frames))
+
+	_, err = db.StepOut()
+	expect.True(t, errors.Is(err, ErrProcessExited))
+}
+
+func (DebuggerSuite) TestStepOutFrames(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/recursion")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("recursion.cpp", 7),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	_, frames := db.BacktraceStack()
+	expect.Equal(t, 8, len(frames)) // 6 descend, start, main
+
+	_, err = db.StepOutFrames(0)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.StepOutFrames(len(frames))
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	// The recursive frames share the same return address.
+	status, err = db.StepOutFrames(3)
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "descend", status.FunctionName)
+
+	depth, err := db.ResolveVariableExpression("depth")
+	expect.Nil(t, err)
+	expect.Equal(t, "3", depth.FormatValue())
+
+	_, frames = db.BacktraceStack()
+	expect.Equal(t, 5, len(frames))
+
+	_, err = db.StepOutToCaller("no_such_function")
+	expect.Error(t, err, "caller frame (no_such_function) not found")
+
+	status, err = db.StepOutToCaller("start")
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "start", status.FunctionName)
+
+	_, frames = db.BacktraceStack()
+	expect.Equal(t, 2, len(frames))
+
+	// The executing frame is not a caller frame.
+	_, err = db.StepOutToCaller("start")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	status, err = db.StepOutToCaller("main")
+	expect.Nil(t, err)
+	expect.Equal(t, "main", status.FunctionName)
+}
+
+func (DebuggerSuite) TestStepIntoCall(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/calls_on_line")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	point, err := db.BreakPoints.Set(
+		db.NewLineResolver("calls_on_line.cpp", 17),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, int64(17), status.Line)
+
+	// The line's branches may have their own break sites.
+	err = db.BreakPoints.Remove(point.Id())
+	expect.Nil(t, err)
+
+	// selected ? inc(1) : dec(2)
+	_, err = db.StepIntoCall(0)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, err = db.StepIntoCall(3)
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	// inc(1) is skipped by the control flow.  The step stops once the thread
+	// leaves the line, rather than resuming freely.
+	status, err = db.StepIntoCall(1)
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "main", status.FunctionName)
+	expect.Equal(t, int64(18), status.Line)
+
+	a, err := db.ResolveVariableExpression("a")
+	expect.Nil(t, err)
+	expect.Equal(t, "1", a.FormatValue())
+
+	// twice(inc(3)) + dec(4).  inc(3) is stepped over.
+	status, err = db.StepIntoCall(2)
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "twice", status.FunctionName)
+
+	x, err := db.ResolveVariableExpression("x")
+	expect.Nil(t, err)
+	expect.Equal(t, "4", x.FormatValue())
+
+	status, err = db.StepOut()
+	expect.Nil(t, err)
+	expect.Equal(t, "main", status.FunctionName)
+
+	// The remaining calls on the line are counted from the current pc.
+	status, err = db.StepIntoCall(1)
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "dec", status.FunctionName)
+
+	x, err = db.ResolveVariableExpression("x")
+	expect.Nil(t, err)
+	expect.Equal(t, "4", x.FormatValue())
+}
+
+func (DebuggerSuite) TestStepLimit(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/spin_line")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	expect.Equal(t, DefaultStepLimit, db.StepLimit)
+
+	point, err := db.BreakPoints.Set(
+		db.NewLineResolver("spin_line.cpp", 5),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, int64(5), status.Line)
+
+	err = db.BreakPoints.Remove(point.Id())
+	expect.Nil(t, err)
+
+	// The loop is on a single line.
+	db.StepLimit = 100
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
 	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, int64(5), status.Line)
+	expect.Equal(t, 100, status.ExhaustedStepLimit)
+	expect.True(t, strings.Contains(status.String(), "step limit (100"))
+
+	status, err = db.StepIn()
+	expect.Nil(t, err)
+	expect.Equal(t, int64(5), status.Line)
+	expect.Equal(t, 100, status.ExhaustedStepLimit)
+
+	db.StepLimit = 0
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, int64(6), status.Line)
+	expect.Equal(t, 0, status.ExhaustedStepLimit)
+}
+
+// Simulates a disassembler which mis-decodes the instruction's length.
+type misdecodedInstruction struct {
+	memory.Instruction
+
+	length int
+}
+
+func (inst misdecodedInstruction) Length() int {
+	return inst.length
+}
+
+func (DebuggerSuite) TestCheckSteppedInstructionLength(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/recursion")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	// int total = descend(depth);
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("recursion.cpp", 16),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	thread := db.currentThread()
+
+	calls, err := thread.callInstructionsOnCurrentLine()
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(calls))
+
+	// Non-branch instruction (the call's argument setup)
+	instructions, err := db.Disassemble(status.NextInstructionAddress, 1)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(instructions))
+	expect.False(t, instructions[0].IsBranch())
+
+	_, err = db.StepInstruction()
+	expect.Nil(t, err)
+
+	stepOverAddress, err := thread.checkSteppedInstruction(instructions[0])
+	expect.Nil(t, err)
+	expect.Equal(t, VirtualAddress(0), stepOverAddress)
+	expect.Equal(t, 0, len(db.NewStepWarnings()))
+
+	misdecoded := instructions[0]
+	misdecoded.Instruction = misdecodedInstruction{
+		Instruction: instructions[0].Instruction,
+		length:      instructions[0].Length() - 1,
+	}
+
+	_, err = thread.checkSteppedInstruction(misdecoded)
+	expect.Nil(t, err)
+
+	warnings := db.NewStepWarnings()
+	expect.Equal(t, 1, len(warnings))
+	expect.True(
+		t,
+		strings.Contains(
+			warnings[0],
+			fmt.Sprintf(
+				"length (%d) at %s does not match the actual length (%d)",
+				instructions[0].Length()-1,
+				instructions[0].Address,
+				instructions[0].Length())))
+
+	// The warnings are cleared once returned.
+	expect.Equal(t, 0, len(db.NewStepWarnings()))
+
+	// Call instruction.  The step over address is the actual return address
+	// pushed onto the stack, rather than the misdecoded next address.
+	for db.currentThread().status.NextInstructionAddress != calls[0] {
+		_, err = db.StepInstruction()
+		expect.Nil(t, err)
+	}
+
+	instructions, err = db.Disassemble(calls[0], 1)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(instructions))
+	expect.True(t, instructions[0].IsCall())
+	expect.True(t, instructions[0].IsBranch())
+
+	_, err = db.StepInstruction()
+	expect.Nil(t, err)
+
+	returnAddress := calls[0] + VirtualAddress(instructions[0].Length())
+
+	misdecoded = instructions[0]
+	misdecoded.Instruction = misdecodedInstruction{
+		Instruction: instructions[0].Instruction,
+		length:      instructions[0].Length() + 2,
+	}
+
+	stepOverAddress, err = thread.checkSteppedInstruction(misdecoded)
+	expect.Nil(t, err)
+	expect.Equal(t, returnAddress, stepOverAddress)
+	expect.Equal(t, 1, len(db.NewStepWarnings()))
+
+	stepOverAddress, err = thread.checkSteppedInstruction(instructions[0])
+	expect.Nil(t, err)
+	expect.Equal(t, returnAddress, stepOverAddress)
+	expect.Equal(t, 0, len(db.NewStepWarnings()))
+}
+
+func (DebuggerSuite) TestLastStopStatus(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/spin_line")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	expect.Nil(t, db.LastStopStatus())
+
+	point, err := db.BreakPoints.Set(
+		db.NewLineResolver("spin_line.cpp", 5),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.Equal(t, status, db.LastStopStatus())
+
+	summary := status.StopSummary()
+	expect.True(
+		t,
+		strings.Contains(
+			summary,
+			fmt.Sprintf("reason: break point (id=%d)", point.Id())))
+	expect.True(t, strings.Contains(summary, "spin_line.cpp:5 (main)"))
+
+	err = db.BreakPoints.Remove(point.Id())
+	expect.Nil(t, err)
+
+	status, err = db.StepOver()
+	expect.Nil(t, err)
+	expect.Equal(t, status, db.LastStopStatus())
+
+	summary = status.StopSummary()
+	expect.True(t, strings.Contains(summary, "reason: step\n"))
+	expect.True(t, strings.Contains(summary, "spin_line.cpp:6 (main)"))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, status, db.LastStopStatus())
+	expect.True(
+		t,
+		strings.Contains(status.StopSummary(), "reason: exited with status: 0"))
+
+	// Failed resumes do not replace the last stop.
+	_, err = db.StepOver()
+	expect.NotNil(t, err)
+	expect.Equal(t, status, db.LastStopStatus())
+}
+
+func (DebuggerSuite) TestReadTypedMemory(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/global_variable")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	cats, err := db.ResolveVariableExpression("cats")
+	expect.Nil(t, err)
+
+	data, err := db.ReadTypedMemory(cats.Address, 3, "cat")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.ArrayKind, data.Kind)
+	expect.Equal(t, 3, data.NumElements)
+
+	element, err := data.Index(2)
+	expect.Nil(t, err)
+
+	field, err := element.FieldOrMethodByName("name")
+	expect.Nil(t, err)
+
+	name, err := field.ReadCString()
+	expect.Nil(t, err)
+	expect.Equal(t, "Milkshake", name)
+
+	data, err = db.ReadTypedMemory(cats.Address, 2, "char*")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.PointerKind, data.Value.Kind)
+
+	element, err = data.Index(0)
+	expect.Nil(t, err)
+
+	name, err = element.ReadCString()
+	expect.Nil(t, err)
+	expect.Equal(t, "Marshmallow", name)
+
+	_, err = db.ReadTypedMemory(cats.Address, 2, "no_such_type")
+	expect.Error(t, err, "type (no_such_type) not found")
+
+	_, err = db.ReadTypedMemory(cats.Address, 0, "cat")
+	expect.Error(t, err, "invalid number of elements")
+}
+
+func (DebuggerSuite) TestFormatSelfReferentialList(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/linked_list")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	data, err := db.ResolveVariableExpression("head")
+	expect.Nil(t, err)
+
+	// Without dereferencing, the pointer is printed as a bare address.
+	expect.Equal(t, 0, strings.Count(data.Format(""), "(int32)"))
+
+	formatted := data.FormatWithDepth("", 100)
+	expect.Equal(t, 1, strings.Count(formatted, "(int32): 1,"))
+	expect.Equal(t, 1, strings.Count(formatted, "(int32): 2,"))
+	expect.Equal(t, 1, strings.Count(formatted, "(int32): 3,"))
+	expect.Equal(t, 1, strings.Count(formatted, "(<visited>)"))
+
+	formatted = data.FormatWithDepth("", 1)
+	expect.Equal(t, 1, strings.Count(formatted, "(int32): 1,"))
+	expect.Equal(t, 0, strings.Count(formatted, "(int32): 2,"))
+	expect.Equal(t, 0, strings.Count(formatted, "(<visited>)"))
+}
+
+func (DebuggerSuite) TestFormatArrayOfPointers(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/pointer_array")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	// char pointer elements are printed with their strings, regardless of
+	// depth.
+	data, err := db.ResolveVariableExpression("names")
+	expect.Nil(t, err)
+
+	formatted := data.Format("")
+	expect.True(t, strings.Contains(formatted, "(Marshmallow),\n"))
+	expect.True(t, strings.Contains(formatted, "(Milkshake),\n"))
+	expect.True(
+		t,
+		strings.Contains(formatted, "[2] (*char): 0x0000000000000000,\n"))
+
+	data, err = db.ResolveVariableExpression("values")
+	expect.Nil(t, err)
+
+	// Without dereferencing, the pointers are printed as bare addresses.
+	expect.Equal(t, 0, strings.Count(data.Format(""), "(int32)"))
+
+	// null pointers are not dereferenced.
+	formatted = data.FormatWithDepth("", 1)
+	expect.Equal(t, 1, strings.Count(formatted, "* (int32): 1,"))
+	expect.Equal(t, 1, strings.Count(formatted, "* (int32): 2,"))
+	expect.True(
+		t,
+		strings.Contains(formatted, "[2] (*int32): 0x0000000000000000,\n"))
+
+	data, err = db.ResolveVariableExpression("cats")
+	expect.Nil(t, err)
+
+	formatted = data.FormatWithDepth("", 1)
+	expect.Equal(t, 1, strings.Count(formatted, ".age (int32): 4,"))
+	expect.Equal(t, 1, strings.Count(formatted, ".age (int32): 8,"))
+	expect.Equal(t, 1, strings.Count(formatted, "(<visited>)"))
+}
+
+func (DebuggerSuite) TestSymbolizeAddress(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/pointer_array")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	mainAddress, err := db.EvaluateAddress("main")
+	expect.Nil(t, err)
+
+	address, err := db.EvaluateAddress("main + 8")
+	expect.Nil(t, err)
+	expect.Equal(t, mainAddress+8, address)
+
+	symbol, err := db.SymbolizeAddress(address)
+	expect.Nil(t, err)
+	expect.NotNil(t, symbol.Symbol)
+	expect.Equal(t, "main", symbol.Symbol.Name)
+	expect.Equal(t, 8, symbol.Offset)
+	expect.Equal(t, ".text", symbol.SectionName)
+	expect.Equal(t, "", symbol.File.FileName)
+
+	pc, err := db.EvaluateAddress("$pc")
+	expect.Nil(t, err)
+	expect.Equal(t, status.NextInstructionAddress, pc)
+
+	// A pointer variable's value, rather than the variable's address.
+	address, err = db.EvaluateAddress("values[1]")
+	expect.Nil(t, err)
+
+	symbol, err = db.SymbolizeAddress(address)
+	expect.Nil(t, err)
+	expect.NotNil(t, symbol.Symbol)
+	expect.Equal(t, "second", symbol.Symbol.Name)
+	expect.Equal(t, 0, symbol.Offset)
+	expect.Equal(t, ".data", symbol.SectionName)
+
+	// Shared library function
+	address, err = db.EvaluateAddress("puts")
+	expect.Nil(t, err)
+
+	symbol, err = db.SymbolizeAddress(address)
+	expect.Nil(t, err)
+	expect.NotNil(t, symbol.Symbol)
+	expect.True(t, strings.Contains(symbol.File.FileName, "libc"))
+
+	// Stack addresses are not in any elf file.
+	address, err = db.EvaluateAddress("$sp")
+	expect.Nil(t, err)
+
+	symbol, err = db.SymbolizeAddress(address)
+	expect.Nil(t, err)
+	expect.Nil(t, symbol.File)
+	expect.NotNil(t, symbol.Mapping)
+	expect.Equal(t, "[stack]", symbol.Mapping.Pathname)
+
+	symbol, err = db.SymbolizeAddress(0x10)
+	expect.Nil(t, err)
+	expect.Nil(t, symbol.File)
+	expect.Nil(t, symbol.Mapping)
+	expect.Equal(t, "0x0000000000000010: address is not mapped", symbol.String())
+
+	_, err = db.EvaluateAddress("$xmm0")
+	expect.Error(t, err, "invalid general register")
+
+	_, err = db.EvaluateAddress("bogus")
+	expect.NotNil(t, err)
+}
+
+func (DebuggerSuite) TestListScopeVariables(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/blocks")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	// NOTE: the process is not stopped in main.
+	addresses, err := db.NewLineResolver("blocks.cpp", 16).ResolveAddresses()
+	expect.Nil(t, err)
+	expect.True(t, len(addresses) > 0)
+
+	variables, err := db.ListScopeVariables(addresses[0])
+	expect.Nil(t, err)
+
+	// The outer blocks' i are shadowed by the innermost block's i.
+	expect.Equal(t, 3, len(variables))
+
+	expect.Equal(t, "i", variables[0].Name)
+	expect.Equal(t, "int32", variables[0].TypeName)
+	expect.False(t, variables[0].IsParameter)
+	expect.True(
+		t,
+		strings.HasPrefix(variables[0].Location, "frame base offset "))
+
+	expect.Equal(t, "argc", variables[1].Name)
+	expect.True(t, variables[1].IsParameter)
+
+	expect.Equal(t, "argv", variables[2].Name)
+	expect.Equal(t, "**char", variables[2].TypeName)
+	expect.True(t, variables[2].IsParameter)
+	expect.True(
+		t,
+		strings.HasPrefix(
+			variables[2].String(),
+			"argv (**char): parameter, frame base offset "))
+
+	addresses, err = db.NewFunctionResolver("ref").ResolveAddresses()
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(addresses))
+
+	variables, err = db.ListScopeVariables(addresses[0])
+	expect.Nil(t, err)
+	expect.Equal(t, 2, len(variables))
+	expect.Equal(t, "i", variables[0].Name)
+	expect.True(t, variables[0].IsParameter)
+	expect.Equal(t, "j", variables[1].Name)
+	expect.False(t, variables[1].IsParameter)
+
+	// Addresses without debug info have no variables in scope.
+	variables, err = db.ListScopeVariables(db.LoadedElves.EntryPoint())
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(variables))
+}
+
+func (DebuggerSuite) TestReadLocalVariable(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/blocks")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	step := func() {
+		status, err := db.StepOver()
+		expect.Nil(t, err)
+		expect.True(t, status.Stopped)
+		expect.Equal(t, SingleStepTrap, status.TrapKind)
+	}
+
+	expects := func(expected int32) {
+		data, err := db.ResolveVariableExpression("i")
+		expect.Nil(t, err)
+		expect.Equal(t, expression.IntKind, data.Kind)
+		expect.Equal(t, 4, data.ByteSize)
+
+		i, err := data.DecodeSimpleValue()
+		expect.Nil(t, err)
+		expect.Equal(t, expected, i.(int32))
+	}
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	step()
+	step()
+
+	expects(1)
+
+	step()
+	step()
+
+	expects(2)
+
+	step()
+	step()
+
+	expects(3)
+}
+
+func (DebuggerSuite) TestListLocalVariablesOrder(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/blocks")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("blocks.cpp", 16),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+
+	locals, err := db.ListInspectFrameLocalVariables()
+	expect.Nil(t, err)
+
+	// The innermost block's i shadows the outer blocks' i.
+	names := []string{}
+	for _, local := range locals {
+		names = append(names, local.FormatPrefix)
+	}
+	expect.Equal(t, []string{"i", "argc", "argv"}, names)
+
+	value, err := locals[0].DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(3), value.(int32))
+}
+
+func (DebuggerSuite) TestReadEntryValueArgument(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/entry_value")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("entry_value.cpp", 11),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "compute", status.FunctionName)
+
+	// base's register is clobbered by sink's argument.  Its value is recovered
+	// from main's call site.
+	data, err := db.ReadInspectFrameVariableOrFunction("base")
+	expect.Nil(t, err)
+	value, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(42), value.(int32))
+
+	data, err = db.ReadInspectFrameVariableOrFunction("scale")
+	expect.Nil(t, err)
+	value, err = data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(7), value.(int32))
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+}
+
+func (DebuggerSuite) TestReadOptimizedOutVariables(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/optimized_out")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("optimized_out.cpp", 29),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, "compute", status.FunctionName)
+
+	data, err := db.ReadInspectFrameVariableOrFunction("input")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.Available, data.Unavailable)
+	expect.Equal(t, "input (int32): 21", data.Format(""))
+
+	// first's location list ends before the current pc.
+	data, err = db.ReadInspectFrameVariableOrFunction("first")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.NotAvailableAtCurrentPC, data.Unavailable)
+	expect.Equal(
+		t,
+		"first (int32): <not available at current PC>",
+		data.Format(""))
+
+	_, err = data.DecodeSimpleValue()
+	expect.Error(t, err, "first is not available at current PC")
+
+	// second has no location.
+	data, err = db.ReadInspectFrameVariableOrFunction("second")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.OptimizedOut, data.Unavailable)
+	expect.Equal(t, "second (int32): <optimized out>", data.Format(""))
+	expect.Equal(t, "<optimized out>", data.FormatValue())
+
+	// pair.b is an empty piece.
+	data, err = db.ReadInspectFrameVariableOrFunction("pair")
+	expect.Nil(t, err)
+	expect.Equal(t, expression.OptimizedOut, data.Unavailable)
+	expect.Equal(t, "pair (Pair): <optimized out>", data.Format(""))
+
+	field, err := data.FieldOrMethodByName("a")
+	expect.Nil(t, err)
+	expect.Equal(t, ".a (int32): <optimized out>", field.Format(""))
+
+	_, err = db.EvaluateExpression("second")
+	expect.Error(t, err, "second is optimized out")
+
+	_, err = db.EvaluateExpression("sink(first)")
+	expect.Error(t, err, "first is not available at current PC")
+
+	_, err = db.AssignVariable("second", "1")
+	expect.Error(t, err, "cannot write to variable (optimized out)")
+
+	result, err := db.ResolveVariableExpression("first")
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		"first (int32): <not available at current PC>",
+		result.Format(""))
+
+	locals, err := db.ListInspectFrameLocalVariables()
+	expect.Nil(t, err)
+	expect.Equal(t, 4, len(locals))
+
+	// NOTE: line 29 spans two line table rows.
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, 29, status.Line)
+
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+}
+
+func (DebuggerSuite) TestReadCallerRegisterVariable(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/callee_saved")
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionEntryResolver("marker"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	_, err = db.BreakPoints.Set(
+		db.NewLineResolver("callee_saved.cpp", 7),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	xmm0, ok := registers.ByName("xmm0")
+	expect.True(t, ok)
+
+	rbx, ok := registers.ByName("rbx")
+	expect.True(t, ok)
 
-	checkVar(1)
+	// xmm0 is zeroed by spill_xmm0, after saving main's xmm0 on the stack.
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, "marker", status.FunctionName)
 
-	status, err = db.StepOver()
+	state, err := db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+	expect.Equal(t, registers.Value(registers.U128(0, 0)), state.Value(xmm0))
+
+	db.InspectCallerFrame()
+	db.InspectCallerFrame()
+	inspectFrame, _ := db.BacktraceStack()
+	expect.Equal(t, "main", inspectFrame.Name)
+
+	state, err = db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		registers.Value(registers.U128(0, math.Float64bits(1.5))),
+		state.Value(xmm0))
+
+	// rbx is clobbered by clobber, after saving accumulate's rbx (total) on
+	// the stack.
+	status, err = db.ResumeAllUntilSignal()
 	expect.Nil(t, err)
 	expect.True(t, status.Stopped)
-	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, "clobber", status.FunctionName)
 
-	checkVar(42)
+	state, err = db.GetInspectFrameRegisterState()
+	expect.Nil(t, err)
+	expect.Equal(t, registers.U64(0xdead), state.Value(rbx))
 
-	data, err := db.ResolveVariableExpression("someone->pets[0].name")
+	db.InspectCallerFrame()
+	inspectFrame, _ = db.BacktraceStack()
+	expect.Equal(t, "accumulate", inspectFrame.Name)
+
+	state, err = db.GetInspectFrameRegisterState()
 	expect.Nil(t, err)
-	expect.True(t, data.IsCharPointer())
+	expect.Equal(t, registers.U64(42), state.Value(rbx))
 
-	name, err := data.ReadCString()
+	data, err := db.ReadInspectFrameVariableOrFunction("total")
 	expect.Nil(t, err)
-	expect.Equal(t, "Marshmallow", name)
+	expect.Equal(t, 1, len(data.Location))
+	expect.Equal(t, dwarf.RegisterLocation, data.Location[0].Kind)
 
-	data, err = db.ResolveVariableExpression("sy.pets[2].name[3]")
+	value, err := data.DecodeSimpleValue()
 	expect.Nil(t, err)
-	expect.Equal(t, expression.CharKind, data.Kind)
+	expect.Equal(t, int32(42), value.(int32))
 
-	char, err := data.DecodeSimpleValue()
+	status, err = db.ResumeAllUntilSignal()
 	expect.Nil(t, err)
-	expect.Equal(t, 'k', char.(byte))
+	expect.True(t, status.Exited)
+}
 
-	data, err = db.ResolveVariableExpression("cats[1].age")
+func (DebuggerSuite) TestReadWideString(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/wide_string")
 	expect.Nil(t, err)
-	expect.Equal(t, expression.IntKind, data.Kind)
-	expect.Equal(t, 4, data.ByteSize)
+	defer db.Close()
 
-	age, err := data.DecodeSimpleValue()
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
 	expect.Nil(t, err)
-	expect.Equal(t, 8, age.(int32))
 
-	data, err = db.ResolveVariableExpression("cats[1].color")
+	status, err := db.ResumeAllUntilSignal()
 	expect.Nil(t, err)
-	expect.Equal(t, expression.IntKind, data.Kind)
-	expect.Equal(t, 4, data.ByteSize)
+	expect.True(t, status.Stopped)
 
-	color, err := data.DecodeSimpleValue()
+	data, err := db.ResolveVariableExpression("utf16_str")
 	expect.Nil(t, err)
-	expect.Equal(t, 2, color.(int32))
+	expect.True(t, data.IsWideCharPointer())
+	expect.False(t, data.IsCharPointer())
+
+	str, err := data.ReadWideString()
+	expect.Nil(t, err)
+	expect.Equal(t, "café \U0001F431", str)
+	expect.True(t, strings.HasSuffix(data.FormatValue(), " (u\"café \U0001F431\")"))
+
+	data, err = db.ResolveVariableExpression("utf32_str")
+	expect.Nil(t, err)
+
+	str, err = data.ReadWideString()
+	expect.Nil(t, err)
+	expect.Equal(t, "naïve \U0001F431", str)
+	expect.True(t, strings.HasSuffix(data.FormatValue(), " (U\"naïve \U0001F431\")"))
+
+	data, err = db.ResolveVariableExpression("wide_str")
+	expect.Nil(t, err)
+	expect.True(
+		t,
+		strings.HasSuffix(data.Format(""), " (L\"wide\\tstr\")"),
+		data.Format(""))
+
+	data, err = db.ResolveVariableExpression("utf16_array")
+	expect.Nil(t, err)
+	expect.True(t, data.IsWideCharArray())
+	expect.True(
+		t,
+		strings.HasSuffix(data.Format(""), ": u\"meow\""),
+		data.Format(""))
+	expect.Equal(t, "u\"meow\"", data.FormatExpandedValue())
+
+	// Unpaired surrogates are escaped.
+	data, err = db.ResolveVariableExpression("invalid_utf16")
+	expect.Nil(t, err)
+	expect.Equal(t, "u\"\\ud800x\"", data.FormatExpandedValue())
+
+	data, err = db.ResolveVariableExpression("utf32_char")
+	expect.Nil(t, err)
+	expect.Equal(t, "U'é' (233)", data.FormatValue())
 }
 
-func (DebuggerSuite) TestReadLocalVariable(t *testing.T) {
-	db, err := StartCmdAndAttachTo("test_targets/blocks")
+func (DebuggerSuite) TestResolveVariableExpressionInThread(t *testing.T) {
+	db, err := StartCmdAndAttachTo("test_targets/per_thread")
 	expect.Nil(t, err)
 	defer db.Close()
 
 	_, err = db.BreakPoints.Set(
-		db.NewFunctionResolver("main"),
+		db.NewFunctionResolver("all_started"),
 		stoppoint.NewBreakSiteType(false),
 		true)
 	expect.Nil(t, err)
 
-	step := func() {
-		status, err := db.StepOver()
-		expect.Nil(t, err)
-		expect.True(t, status.Stopped)
-		expect.Equal(t, SingleStepTrap, status.TrapKind)
-	}
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, db.Pid, status.Tid)
 
-	expects := func(expected int32) {
-		data, err := db.ResolveVariableExpression("i")
+	_, err = db.ResolveVariableExpressionInThread(db.Pid, "id")
+	expect.NotNil(t, err)
+
+	_, err = db.ResolveVariableExpressionInThread(-1, "id")
+	expect.True(t, errors.Is(err, ErrInvalidInput))
+
+	_, threads := db.ListThreads()
+	expect.Equal(t, 4, len(threads))
+
+	ids := []int64{}
+	for _, thread := range threads {
+		tid := thread.Status().Tid
+		if tid == db.Pid {
+			continue
+		}
+
+		result, err := db.ResolveVariableExpressionInThread(tid, "id")
 		expect.Nil(t, err)
-		expect.Equal(t, expression.IntKind, data.Kind)
-		expect.Equal(t, 4, data.ByteSize)
 
-		i, err := data.DecodeSimpleValue()
+		value, err := result.DecodeSimpleValue()
 		expect.Nil(t, err)
-		expect.Equal(t, expected, i.(int32))
+		ids = append(ids, value.(int64))
+
+		// The current thread is unchanged.
+		current, _ := db.ListThreads()
+		expect.Equal(t, db.Pid, current.Status().Tid)
 	}
 
+	slices.Sort(ids)
+	expect.Equal(t, []int64{1, 11, 21}, ids)
+}
+
+func (DebuggerSuite) TestAssignRegisterResidentVariable(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	expect.Nil(t, err)
+
+	defer reader.Close()
+
+	cmd := exec.Command("test_targets/reg_local")
+	cmd.Stderr = os.Stderr
+	cmd.Stdout = writer
+
+	db, err := StartAndAttachTo(cmd)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	err = writer.Close()
+	expect.Nil(t, err)
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("identity"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
 	status, err := db.ResumeAllUntilSignal()
 	expect.Nil(t, err)
 	expect.True(t, status.Stopped)
 	expect.Equal(t, SoftwareTrap, status.TrapKind)
 
-	step()
-	step()
+	// Register-resident argument
+	inspectFrame, _ := db.BacktraceStack()
+	args, err := db.FrameArguments(inspectFrame)
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(args))
+	expect.Equal(t, "value=7", args[0].String())
+	expect.NotNil(t, args[0].Value.ImplicitValue)
 
-	expects(1)
+	data, err := db.ReadInspectFrameVariableOrFunction("value")
+	expect.Nil(t, err)
+	expect.Equal(t, 1, len(data.Location))
+	expect.Equal(t, dwarf.RegisterLocation, data.Location[0].Kind)
 
-	step()
-	step()
+	data, err = db.AssignVariable("value", "42")
+	expect.Nil(t, err)
 
-	expects(2)
+	value, err := data.DecodeSimpleValue()
+	expect.Nil(t, err)
+	expect.Equal(t, int32(42), value.(int32))
 
-	step()
-	step()
+	_, err = db.AssignVariable("1", "42")
+	expect.Error(t, err, "cannot write to implicit value")
 
-	expects(3)
+	status, err = db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+
+	content := make([]byte, 1024)
+	n, err := reader.Read(content)
+	expect.Nil(t, err)
+	expect.Equal(t, "42\n", string(content[:n]))
 }
 
 func (DebuggerSuite) TestReadMemberPointer(t *testing.T) {
@@ -1102,3 +5571,338 @@ func (DebuggerSuite) TestReadMemberPointer(t *testing.T) {
 	expect.NotEqual(t, dataAddr, funcAddr)
 
 }
+
+func startStepAtMain(tb testing.TB) *Debugger {
+	db, err := StartCmdAndAttachTo("test_targets/step")
+	expect.Nil(tb, err)
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(tb, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(tb, err)
+	expect.True(tb, status.Stopped)
+	expect.Equal(tb, SoftwareTrap, status.TrapKind)
+
+	return db
+}
+
+// Evaluates the instructions (terminated by DW_OP_stack_value) in the
+// executing frame, and returns the resulting stack value.
+func evaluateDwarfStackValue(
+	db *Debugger,
+	instructions ...byte,
+) (
+	uint64,
+	error,
+) {
+	location, err := dwarf.EvaluateExpression(
+		db.currentThread().CallStack.ExecutingFrame(),
+		false, // in frame info
+		append(instructions, byte(dwarf.DW_OP_stack_value)),
+		false) // push cfa
+	if err != nil {
+		return 0, err
+	}
+
+	if len(location) != 1 || location[0].Kind != dwarf.ImplicitLiteralLocation {
+		return 0, fmt.Errorf("unexpected location: %v", location)
+	}
+
+	return location[0].Value, nil
+}
+
+// Verifies the bit-packed fields' layout and values.  The layout is the same
+// for both dwarf4 bit-packed field encodings.
+func checkReadBitFields(t *testing.T, target string) {
+	db, err := StartCmdAndAttachTo(target)
+	expect.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+
+	type layout struct {
+		name       string
+		byteOffset int
+		bitOffset  int
+		bitSize    int
+	}
+
+	checkLayout := func(variable string, expected []layout) {
+		data, err := db.ResolveVariableExpression(variable)
+		expect.Nil(t, err)
+		expect.Equal(t, len(expected), len(data.Fields))
+
+		for idx, field := range data.Fields {
+			expect.Equal(t, expected[idx].name, field.Name)
+			expect.Equal(t, expected[idx].byteOffset, field.ByteOffset)
+			expect.Equal(t, expected[idx].bitOffset, field.BitOffset)
+			expect.Equal(t, expected[idx].bitSize, field.BitSize)
+			expect.True(t, field.IsBitPacked())
+		}
+	}
+
+	checkLayout(
+		"g_mixed",
+		[]layout{
+			{"u1", 0, 0, 1},
+			{"u3", 0, 1, 3},
+			{"s5", 0, 4, 5},
+			{"u7", 1, 1, 7}, // straddles byte boundary
+			{"s12", 2, 0, 12},
+			{"u4", 3, 4, 4},
+			{"s33", 8, 0, 33},
+			{"u31", 12, 1, 31},
+			{"s3", 16, 0, 3},
+			{"u9", 16, 3, 9},
+			{"s1", 17, 4, 1},
+			{"b", 17, 5, 1},
+		})
+
+	// s30 straddles beyond its storage unit (gcc encodes a negative
+	// DW_AT_bit_offset).
+	checkLayout(
+		"g_packed_mixed",
+		[]layout{
+			{"u5", 0, 0, 5},
+			{"s30", 0, 5, 30},
+			{"u29", 4, 3, 29},
+		})
+
+	data, err := db.ResolveVariableExpression("g_mixed")
+	expect.Nil(t, err)
+	expect.False(t, data.HasUnalignedFields())
+
+	data, err = db.ResolveVariableExpression("g_packed_mixed")
+	expect.Nil(t, err)
+	expect.True(t, data.HasUnalignedFields())
+
+	checkValues := func(variable string, expected ...interface{}) {
+		data, err := db.ResolveVariableExpression(variable)
+		expect.Nil(t, err)
+		expect.Equal(t, len(expected), len(data.Fields))
+
+		for idx, field := range data.Fields {
+			value, err := data.FieldOrMethodByName(field.Name)
+			expect.Nil(t, err)
+
+			decoded, err := value.DecodeSimpleValue()
+			expect.Nil(t, err)
+			expect.Equal(t, expected[idx], decoded)
+		}
+	}
+
+	checkValues(
+		"g_min",
+		uint32(0),
+		uint32(0),
+		int32(-16),
+		uint32(0),
+		int32(-2048),
+		uint32(0),
+		int64(-4294967296),
+		uint64(0),
+		byte(0xfc), // -4
+		uint16(0),
+		int32(-1),
+		false)
+
+	checkValues(
+		"g_max",
+		uint32(1),
+		uint32(7),
+		int32(15),
+		uint32(127),
+		int32(2047),
+		uint32(15),
+		int64(4294967295),
+		uint64(2147483647),
+		byte(3),
+		uint16(511),
+		int32(0),
+		true)
+
+	checkValues(
+		"g_mixed",
+		uint32(1),
+		uint32(5),
+		int32(-11),
+		uint32(85),
+		int32(-1234),
+		uint32(9),
+		int64(-123456789),
+		uint64(1234567),
+		byte(0xfd), // -3
+		uint16(300),
+		int32(-1),
+		true)
+
+	checkValues("g_packed_min", byte(0), int32(-536870912), uint32(0))
+
+	checkValues(
+		"g_packed_mixed",
+		byte(21),
+		int32(-12345678),
+		uint32(305419896))
+
+	format := func(expr string) string {
+		result, err := db.ResolveVariableExpression(expr)
+		expect.Nil(t, err)
+		return result.FormatValue()
+	}
+
+	expect.Equal(t, "-1234", format("g_mixed.s12"))
+	expect.Equal(t, "-123456789", format("g_mixed.s33"))
+	expect.Equal(t, "'\\xfd' (-3)", format("g_mixed.s3"))
+	expect.Equal(t, "-12345678", format("g_packed_mixed.s30"))
+
+	// Sign extended values are used in conditions.
+	for _, condition := range []string{
+		"g_min.s1 < 0",
+		"g_min.s5 < g_max.s5",
+		"g_mixed.s33 < g_max.s33",
+		"g_packed_min.s30 < g_packed_mixed.s30",
+	} {
+		satisfied, err := db.EvaluateCondition(condition)
+		expect.Nil(t, err)
+		expect.True(t, satisfied)
+	}
+}
+
+func startManyThreads(tb testing.TB) *Debugger {
+	db, err := StartCmdAndAttachTo("test_targets/many_threads")
+	expect.Nil(tb, err)
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("all_started"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(tb, err)
+
+	for {
+		status, err := db.ResumeAllUntilSignal()
+		expect.Nil(tb, err)
+		expect.True(tb, status.Stopped)
+
+		if status.TrapKind == SoftwareTrap && len(status.StopPoints) > 0 {
+			break
+		}
+	}
+
+	return db
+}
+
+func (DebuggerSuite) TestListManyThreads(t *testing.T) {
+	db := startManyThreads(t)
+	defer db.Close()
+
+	current, threads := db.ListThreads()
+	expect.Equal(t, db.Pid, current.Tid)
+	expect.Equal(t, 65, len(threads))
+	expect.Equal(t, 65, len(db.AllRegisters()))
+
+	for idx := 1; idx < len(threads); idx++ {
+		expect.True(t, threads[idx-1].Tid < threads[idx].Tid)
+	}
+
+	// The returned list is owned by the caller.
+	threads[0] = nil
+
+	_, threads = db.ListThreads()
+	expect.NotNil(t, threads[0])
+}
+
+func BenchmarkListThreads(b *testing.B) {
+	db := startManyThreads(b)
+	defer db.Close()
+
+	for b.Loop() {
+		_, threads := db.ListThreads()
+		for _, thread := range threads {
+			_ = thread.Status()
+		}
+	}
+}
+
+func BenchmarkAllRegisters(b *testing.B) {
+	db := startManyThreads(b)
+	defer db.Close()
+
+	for b.Loop() {
+		_ = db.AllRegisters()
+	}
+}
+
+// Start the large_struct target and stop at main.
+func startLargeStruct(tb testing.TB) (*Debugger, *expression.TypedData) {
+	db, err := StartCmdAndAttachTo("test_targets/large_struct")
+	expect.Nil(tb, err)
+
+	_, err = db.BreakPoints.Set(
+		db.NewFunctionResolver("main"),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(tb, err)
+
+	status, err := db.ResumeAllUntilSignal()
+	expect.Nil(tb, err)
+	expect.True(tb, status.Stopped)
+
+	large, err := db.ResolveVariableExpression("g_large")
+	expect.Nil(tb, err)
+
+	elements, err := large.FieldOrMethodByName("elements")
+	expect.Nil(tb, err)
+
+	return db, elements
+}
+
+func benchmarkReadLargeStructFields(b *testing.B, cacheEnabled bool) {
+	db, elements := startLargeStruct(b)
+	defer db.Close()
+
+	db.VirtualMemory.SetCacheEnabled(cacheEnabled)
+
+	for b.Loop() {
+		// Simulate a new stop
+		db.VirtualMemory.InvalidateCache()
+
+		for i := 0; i < elements.NumElements; i++ {
+			element, err := elements.Index(i)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			for _, name := range []string{"id", "weight", "tag"} {
+				field, err := element.FieldOrMethodByName(name)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				_, err = field.DecodeSimpleValue()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkReadLargeStructFields(b *testing.B) {
+	benchmarkReadLargeStructFields(b, true)
+}
+
+func BenchmarkReadLargeStructFieldsUncached(b *testing.B) {
+	benchmarkReadLargeStructFields(b, false)
+}
@@ -0,0 +1,173 @@
+package debugger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/dwarf"
+)
+
+// Declaration is a named function / global variable / type, along with its
+// declaration location (DW_AT_decl_file / DW_AT_decl_line).
+type Declaration struct {
+	Name string
+
+	// The mangled (DW_AT_linkage_name) name.  Empty if the entry has no
+	// linkage name, or if the linkage name is identical to the source name.
+	LinkageName string
+
+	// nil if the declaration location is unavailable.
+	*dwarf.FileEntry
+	Line int64
+
+	Entry *dwarf.DebugInfoEntry
+}
+
+func (decl Declaration) Location() string {
+	if decl.FileEntry == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", decl.FileEntry.Path(), decl.Line)
+}
+
+func (decl Declaration) String() string {
+	result := decl.Name
+	if decl.LinkageName != "" {
+		result += " [" + decl.LinkageName + "]"
+	}
+
+	location := decl.Location()
+	if location == "" {
+		return result
+	}
+
+	return fmt.Sprintf("%s (declared at %s)", result, location)
+}
+
+// Returns function definitions whose name matches the regular expression
+// (empty pattern matches all), sorted by name.
+func (db *Debugger) ListFunctionDeclarations(
+	pattern string,
+) (
+	[]Declaration,
+	error,
+) {
+	return db.listDeclarations(pattern, db.LoadedElves.FunctionDefinitionEntries)
+}
+
+// Returns global variables whose name matches the regular expression (empty
+// pattern matches all), sorted by name.
+func (db *Debugger) ListVariableDeclarations(
+	pattern string,
+) (
+	[]Declaration,
+	error,
+) {
+	return db.listDeclarations(pattern, db.LoadedElves.GlobalVariableEntries)
+}
+
+// Returns type definitions whose name matches the regular expression (empty
+// pattern matches all), sorted by name.
+func (db *Debugger) ListTypeDeclarations(
+	pattern string,
+) (
+	[]Declaration,
+	error,
+) {
+	return db.listDeclarations(pattern, db.LoadedElves.TypeEntries)
+}
+
+func (db *Debugger) listDeclarations(
+	pattern string,
+	listEntries func() ([]*dwarf.DebugInfoEntry, error),
+) (
+	[]Declaration,
+	error,
+) {
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w. invalid pattern: %w", ErrInvalidInput, err)
+	}
+
+	entries, err := listEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: the same declaration (e.g., types / inline functions defined in
+	// header files) may appear in multiple compile units.
+	type declKey struct {
+		name     string
+		location string
+	}
+	seen := map[declKey]struct{}{}
+
+	result := []Declaration{}
+	for _, entry := range entries {
+		name, _, err := entry.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		if !matcher.MatchString(name) {
+			continue
+		}
+
+		linkageName, _, err := entry.LinkageName()
+		if err != nil {
+			return nil, err
+		}
+		if linkageName == name {
+			linkageName = ""
+		}
+
+		fileEntry, line, err := entry.DeclarationLocation()
+		if err != nil {
+			return nil, err
+		}
+
+		decl := Declaration{
+			Name:        name,
+			LinkageName: linkageName,
+			FileEntry:   fileEntry,
+			Line:        line,
+			Entry:       entry,
+		}
+
+		key := declKey{
+			name:     name,
+			location: decl.Location(),
+		}
+		_, ok := seen[key]
+		if ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		result = append(result, decl)
+	}
+
+	sort.SliceStable(
+		result,
+		func(i int, j int) bool {
+			return result[i].Name < result[j].Name
+		})
+
+	return result, nil
+}
+
+// Returns the name and parameter types (e.g., "print_type(int32)") of the
+// function containing the address, or an empty string if the function has no
+// debug info.
+func (db *Debugger) FunctionSignatureAt(address VirtualAddress) (string, error) {
+	_, funcEntry, err := db.LoadedElves.FunctionDefinitionEntryContainingAddress(
+		address)
+	if err != nil || funcEntry == nil {
+		return "", err
+	}
+
+	return db.descriptorPool.FunctionSignatureName(funcEntry)
+}
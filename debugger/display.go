@@ -0,0 +1,109 @@
+package debugger
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
+)
+
+// Display is an expression which is re-evaluated (in the current thread's
+// inspect frame) every time the process stops.
+type Display struct {
+	Id         int64
+	Expression string
+	Format     IntegerFormat
+}
+
+func (display *Display) String() string {
+	if display.Format == NaturalFormat {
+		return fmt.Sprintf("%d: %s", display.Id, display.Expression)
+	}
+	return fmt.Sprintf("%d: %s %s", display.Id, display.Format, display.Expression)
+}
+
+type DisplayValue struct {
+	*Display
+
+	// Set when the expression can't be evaluated in the current context (e.g.,
+	// the variable is out of scope), in which case Value is nil.
+	Err error
+
+	Value *expression.TypedData
+}
+
+type Displays struct {
+	nextId   int64
+	displays []*Display
+}
+
+func (displays *Displays) List() []*Display {
+	return append([]*Display{}, displays.displays...)
+}
+
+func (displays *Displays) Remove(id int64) bool {
+	for idx, display := range displays.displays {
+		if display.Id == id {
+			displays.displays = append(
+				displays.displays[:idx],
+				displays.displays[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Registers the expression for evaluation on every stop.  Note that the
+// expression is not evaluated (the expression may only be valid in frames
+// which are not yet active).
+func (db *Debugger) AddDisplay(
+	expressionString string,
+	format IntegerFormat,
+) (
+	*Display,
+	error,
+) {
+	expressionString = strings.TrimSpace(expressionString)
+	if expressionString == "" {
+		return nil, fmt.Errorf("%w. empty display expression", ErrInvalidInput)
+	}
+
+	db.Displays.nextId++
+	display := &Display{
+		Id:         db.Displays.nextId,
+		Expression: expressionString,
+		Format:     format,
+	}
+
+	db.Displays.displays = append(db.Displays.displays, display)
+	return display, nil
+}
+
+// Evaluates the display in the current thread's inspect frame.  Unlike
+// ResolveVariableExpression, the value is not recorded in EvaluatedResults.
+func (db *Debugger) EvaluateDisplay(display *Display) DisplayValue {
+	value, err := expression.Evaluate(db, display.Expression)
+	if err != nil {
+		return DisplayValue{
+			Display: display,
+			Err:     err,
+		}
+	}
+
+	return DisplayValue{
+		Display: display,
+		Value:   value,
+	}
+}
+
+// Evaluates all displays, in the order they were added.
+func (db *Debugger) EvaluateDisplays() []DisplayValue {
+	result := make([]DisplayValue, 0, len(db.Displays.displays))
+	for _, display := range db.Displays.displays {
+		result = append(result, db.EvaluateDisplay(display))
+	}
+
+	return result
+}
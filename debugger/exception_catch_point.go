@@ -0,0 +1,68 @@
+package debugger
+
+import (
+	"fmt"
+
+	"github.com/pattyshack/bad/debugger/catchpoint"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+func (db *Debugger) IsCatchingException(event catchpoint.ExceptionEvent) bool {
+	for _, catchEvent := range db.exceptionCatchPointEvents {
+		if catchEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NOTE: The catch point is pending until the c++ runtime library is loaded.
+func (db *Debugger) CatchException(event catchpoint.ExceptionEvent) error {
+	if db.IsCatchingException(event) {
+		return nil
+	}
+
+	point, err := db.exceptionCatchPoints.Set(
+		db.NewFunctionEntryResolver(event.FunctionName()),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	if err != nil {
+		return fmt.Errorf("failed to catch exception %s: %w", event, err)
+	}
+
+	db.exceptionCatchPointEvents[point.Id()] = event
+	return nil
+}
+
+func (db *Debugger) IgnoreException(event catchpoint.ExceptionEvent) error {
+	for id, catchEvent := range db.exceptionCatchPointEvents {
+		if catchEvent != event {
+			continue
+		}
+
+		err := db.exceptionCatchPoints.Remove(id)
+		if err != nil {
+			return fmt.Errorf("failed to ignore exception %s: %w", event, err)
+		}
+
+		delete(db.exceptionCatchPointEvents, id)
+	}
+
+	return nil
+}
+
+func (db *Debugger) matchExceptionCatchPoint(
+	siteKeys map[stoppoint.StopSiteKey]struct{},
+) (
+	catchpoint.ExceptionEvent,
+	bool,
+) {
+	for _, triggered := range db.exceptionCatchPoints.Match(siteKeys) {
+		event, ok := db.exceptionCatchPointEvents[triggered.Id()]
+		if ok {
+			return event, true
+		}
+	}
+
+	return "", false
+}
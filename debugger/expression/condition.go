@@ -0,0 +1,300 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+// NOTE: two-character operators must be matched before their one-character
+// prefixes.
+var conditionOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// A parsed (but not yet evaluated) stop point condition.  A condition is
+// either a single expression (Op and Rhs are empty), or a comparison of the
+// form
+//
+//	<expression> <op> <expression>
+//
+// where op is one of ==, !=, <, <=, >, >=.
+type Condition struct {
+	Lhs string
+	Op  string
+	Rhs string
+}
+
+// Splits the condition into its operands, and checks the operands' syntax.
+// The operands are not evaluated, i.e., names are not resolved and functions
+// are not called.
+func ParseCondition(condition string) (*Condition, error) {
+	lhs, op, rhs := splitCondition(condition)
+	lhs = strings.TrimSpace(lhs)
+	rhs = strings.TrimSpace(rhs)
+	if lhs == "" || (op != "" && rhs == "") {
+		return nil, fmt.Errorf(
+			"%w. malformed condition (%s)",
+			ErrInvalidInput,
+			condition)
+	}
+
+	operands := []string{lhs}
+	if op != "" {
+		operands = append(operands, rhs)
+	}
+
+	for _, operand := range operands {
+		_, err := Parse(newLexer(operand), syntaxChecker{})
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w. invalid condition operand (%s): %w",
+				ErrInvalidInput,
+				operand,
+				err)
+		}
+	}
+
+	return &Condition{
+		Lhs: lhs,
+		Op:  op,
+		Rhs: rhs,
+	}, nil
+}
+
+// Split the condition at the first comparison operator that is not nested
+// inside brackets / parentheses / quotes.
+func splitCondition(condition string) (string, string, string) {
+	depth := 0
+	var quote byte
+	for idx := 0; idx < len(condition); idx++ {
+		char := condition[idx]
+
+		if quote != 0 {
+			if char == '\\' {
+				idx++
+			} else if char == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch char {
+		case '"', '\'':
+			quote = char
+			continue
+		case '(', '[':
+			depth++
+			continue
+		case ')', ']':
+			depth--
+			continue
+		}
+
+		if depth != 0 {
+			continue
+		}
+
+		if strings.HasPrefix(condition[idx:], "->") {
+			idx++
+			continue
+		}
+
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(condition[idx:], op) {
+				return condition[:idx], op, condition[idx+len(op):]
+			}
+		}
+	}
+
+	return condition, "", ""
+}
+
+// A reducer which only checks the expression's syntax.  Every expression
+// reduces to nil.
+type syntaxChecker struct{}
+
+func (syntaxChecker) TrueToLiteralExpr(*TokenValue) (*TypedData, error) {
+	return nil, nil
+}
+
+func (syntaxChecker) FalseToLiteralExpr(*TokenValue) (*TypedData, error) {
+	return nil, nil
+}
+
+func (syntaxChecker) IntegerLiteralToLiteralExpr(
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) FloatLiteralToLiteralExpr(
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) RuneLiteralToLiteralExpr(
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) StringLiteralToLiteralExpr(
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToNamedExpr(*TokenValue) (*TypedData, error) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToPreviousResultExpr(*TokenValue) (*TypedData, error) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToGroupedExpr(
+	*TokenValue,
+	*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToDynamicCastExpr(
+	*TokenValue,
+	*TokenValue,
+	*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToDirectAccessExpr(
+	*TypedData,
+	*TokenValue,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToIndirectAccessExpr(
+	*TypedData,
+	*TokenValue,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToIndexExpr(
+	*TypedData,
+	*TokenValue,
+	*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToCallExpr(
+	*TypedData,
+	*TokenValue,
+	[]*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToConditionalTrueBranch(
+	*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToConditionalFalseBranch(
+	*TypedData,
+	*TypedData,
+	*TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) ToConditionalExpr(
+	*TypedData,
+	*TypedData,
+) (
+	*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) EmptyListToArguments() ([]*TypedData, error) {
+	return nil, nil
+}
+
+func (syntaxChecker) ImproperListToArguments(
+	[]*TypedData,
+	*TokenValue,
+) (
+	[]*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) NewToNonEmptyArguments(
+	*TypedData,
+) (
+	[]*TypedData,
+	error,
+) {
+	return nil, nil
+}
+
+func (syntaxChecker) AppendToNonEmptyArguments(
+	[]*TypedData,
+	*TokenValue,
+	*TypedData,
+) (
+	[]*TypedData,
+	error,
+) {
+	return nil, nil
+}
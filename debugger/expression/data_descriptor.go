@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ianlancetaylor/demangle"
+
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/loadedelves"
 	"github.com/pattyshack/bad/debugger/memory"
@@ -45,6 +47,12 @@ const (
 	MemoryClass  = ParameterClass("memory class")
 )
 
+// The maximum number of elements (across all dimensions) in a variable length
+// array.  A larger dynamic dimension is almost certainly evaluated from a
+// garbage / uninitialized bound (e.g., at function entry, before the bound is
+// assigned).
+const MaxDynamicArrayElements = 1 << 20
+
 type DataDescriptor struct {
 	Pool *DataDescriptorPool
 
@@ -60,12 +68,21 @@ type DataDescriptor struct {
 	// Only applicable to arrays
 	NumElements int
 
-	// Only applicable to functions, methods, structs and unions
+	// Only applicable to variable length arrays.  When non-nil, the number of
+	// elements is determined at access time by evaluating this subrange DIE's
+	// DW_AT_count / DW_AT_upper_bound in the variable's frame (see
+	// WithDynamicBounds), and NumElements is not applicable.
+	DynamicBound *dwarf.DebugInfoEntry
+
+	// Only applicable to functions, methods, structs, unions and enums
 	Name string
 
 	// Only applicable to structs and unions
 	Fields []*FieldDescriptor
 
+	// Only applicable to enums (int / uint kind with enumeration type DIE)
+	Enumerators []Enumerator
+
 	// Only applicable to functions/methods
 	Signatures []*SignatureDescriptor
 
@@ -77,6 +94,13 @@ type DataDescriptor struct {
 	resolved bool
 }
 
+type Enumerator struct {
+	Name string
+
+	// NOTE: unsigned enumerator values are stored in two's complement.
+	Value int64
+}
+
 // See x86-64 SYS V ABI, section 3.2.3 Parameter Passing for additional details
 func (descriptor *DataDescriptor) ParameterClasses() ([]ParameterClass, error) {
 	switch descriptor.Kind {
@@ -139,7 +163,9 @@ func (descriptor *DataDescriptor) ParameterClasses() ([]ParameterClass, error) {
 			} else if len(valueClasses) == 2 {
 				classes = valueClasses
 			} else {
-				panic("should never happen")
+				return nil, fmt.Errorf(
+					"unexpected number of array value classes (%d)",
+					len(valueClasses))
 			}
 		} else { // class / struct / union
 			for _, field := range descriptor.Fields {
@@ -149,7 +175,9 @@ func (descriptor *DataDescriptor) ParameterClasses() ([]ParameterClass, error) {
 
 		for _, class := range classes {
 			if class == NoClass {
-				panic("should never happen")
+				return nil, fmt.Errorf(
+					"failed to classify %s eightbyte",
+					descriptor.TypeName())
 			}
 		}
 
@@ -345,7 +373,16 @@ func (descriptor *DataDescriptor) HasUnalignedFields() bool {
 
 	for _, field := range descriptor.Fields {
 		fieldAlignment := field.Value.Alignment()
-		if field.ByteOffset%fieldAlignment != 0 {
+		if field.IsBitPacked() {
+			// A bit-packed field is unaligned only if it straddles its type's
+			// alignment boundary (e.g., packed struct).
+			unitBitSize := 8 * fieldAlignment
+			start := 8*field.ByteOffset + field.BitOffset
+			end := start + field.BitSize - 1
+			if start/unitBitSize != end/unitBitSize {
+				return true
+			}
+		} else if field.ByteOffset%fieldAlignment != 0 {
 			return true
 		}
 
@@ -417,6 +454,22 @@ func mergeFieldClasses(
 	return SSEClass
 }
 
+// True for signed integer-like kinds (int / signed enum / signed char).
+func (descriptor *DataDescriptor) isSignedInteger() bool {
+	return descriptor.Kind == IntKind ||
+		(descriptor.Kind == CharKind && !descriptor.IsUnsignedChar())
+}
+
+// NOTE: plain char is signed on x64.
+func (descriptor *DataDescriptor) IsUnsignedChar() bool {
+	if descriptor.Kind != CharKind || descriptor.DIE == nil {
+		return false
+	}
+
+	encoding, ok := descriptor.DIE.Uint(dwarf.DW_AT_encoding)
+	return ok && encoding == dwarf.DW_ATE_unsigned_char
+}
+
 func (descriptor *DataDescriptor) TypeName() string {
 	if descriptor.Kind == PointerKind {
 		return "*" + descriptor.Value.TypeName()
@@ -437,6 +490,14 @@ func (descriptor *DataDescriptor) TypeName() string {
 		return descriptor.Name
 	}
 
+	if descriptor.Enumerators != nil {
+		if descriptor.Name == "" {
+			return "<unnamed enum>"
+		}
+
+		return descriptor.Name
+	}
+
 	kind := string(descriptor.Kind)
 	if descriptor.Kind == IntKind ||
 		descriptor.Kind == UintKind ||
@@ -488,6 +549,79 @@ func (descriptor *DataDescriptor) Equals(other *DataDescriptor) bool {
 	return true
 }
 
+// Returns true if any of the array's dimensions is dynamically bounded.
+func (descriptor *DataDescriptor) IsVariableLengthArray() bool {
+	for desc := descriptor; desc != nil; desc = desc.Value {
+		if desc.Kind != ArrayKind {
+			return false
+		}
+
+		if desc.DynamicBound != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns a copy of the variable length array descriptor with its dynamic
+// dimensions resolved.  dimension evaluates the subrange DIE's number of
+// elements (from either DW_AT_count or DW_AT_upper_bound).  Statically
+// bounded dimensions are left as is.
+//
+// NOTE: a dynamic dimension is treated as unbounded (i.e., the dimension has
+// no elements) when its count / bound cannot be evaluated (e.g., the
+// referenced variable is optimized out), or when the count is implausible
+// (i.e., negative, or the array would exceed MaxDynamicArrayElements).
+func (descriptor *DataDescriptor) WithDynamicBounds(
+	dimension func(subrange *dwarf.DebugInfoEntry) (int64, error),
+) (
+	*DataDescriptor,
+	error,
+) {
+	if !descriptor.IsVariableLengthArray() {
+		return descriptor, nil
+	}
+
+	value, err := descriptor.Value.WithDynamicBounds(dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	numElements := descriptor.NumElements
+	if descriptor.DynamicBound != nil {
+		numElements = 0
+
+		maxCount := int64(MaxDynamicArrayElements / max(1, value.totalElements()))
+
+		count, err := dimension(descriptor.DynamicBound)
+		if err == nil && 0 <= count && count <= maxCount {
+			numElements = int(count)
+		}
+	}
+
+	return &DataDescriptor{
+		Pool:        descriptor.Pool,
+		Kind:        ArrayKind,
+		ByteSize:    numElements * value.ByteSize,
+		Value:       value,
+		NumElements: numElements,
+		DIE:         descriptor.DIE,
+		resolved:    true,
+	}, nil
+}
+
+// Returns the total number of elements across all (nested) array dimensions,
+// or 1 if the descriptor is not an array.
+func (descriptor *DataDescriptor) totalElements() int {
+	total := 1
+	for desc := descriptor; desc.Kind == ArrayKind; desc = desc.Value {
+		total *= desc.NumElements
+	}
+
+	return total
+}
+
 func (descriptor *DataDescriptor) IsCharPointer() bool {
 	return descriptor.Kind == PointerKind && descriptor.Value.Kind == CharKind
 }
@@ -499,11 +633,29 @@ func (descriptor *DataDescriptor) resolveSizeAndValueDescriptor() error {
 	descriptor.resolved = true
 
 	if descriptor.Kind == PointerKind {
+		hasValueType, err := descriptor.DIE.HasTypeEntry()
+		if err != nil {
+			return fmt.Errorf("invalid pointer type: %w", err)
+		}
+
+		if !hasValueType { // void pointer
+			descriptor.Value = descriptor.Pool.NewVoidType()
+			return nil
+		}
+
 		valueDIE, err := descriptor.DIE.TypeEntry()
 		if err != nil {
 			return fmt.Errorf("invalid pointer type: %w", err)
 		}
 
+		// NOTE: function pointers (e.g., the vtable pointer in classes with
+		// virtual methods / virtual base classes) are treated as opaque void
+		// pointers.
+		if valueDIE.Tag == dwarf.DW_TAG_subroutine_type {
+			descriptor.Value = descriptor.Pool.NewVoidType()
+			return nil
+		}
+
 		valueDesc, err := descriptor.Pool.GetVariableDescriptor(valueDIE)
 		if err != nil {
 			return fmt.Errorf("invalid pointer type: %w", err)
@@ -552,7 +704,7 @@ func (descriptor *DataDescriptor) resolveSizeAndValueDescriptor() error {
 
 			field.Value = valueDesc
 
-			if field.BitOffset < 0 { // dwarf4's deprecated bit-packed field encoding
+			if field.hasDeprecatedBitOffset {
 				size, ok := field.DIE.Uint(dwarf.DW_AT_byte_size)
 
 				byteSize := int(size)
@@ -560,7 +712,19 @@ func (descriptor *DataDescriptor) resolveSizeAndValueDescriptor() error {
 					byteSize = valueDesc.ByteSize
 				}
 
-				field.BitOffset = 8*byteSize + field.BitOffset
+				// NOTE: normalize the bit offset to match dwarf4's preferred
+				// bit-packed field encoding (i.e., 0 <= BitOffset < 8).
+				dataBitOffset := 8*(field.ByteOffset+byteSize) + field.BitOffset
+				if dataBitOffset < 0 {
+					return fmt.Errorf(
+						"invalid bit-packed field (%s). negative bit offset (%d)",
+						field.Name,
+						dataBitOffset)
+				}
+
+				field.ByteOffset = dataBitOffset / 8
+				field.BitOffset = dataBitOffset % 8
+				field.hasDeprecatedBitOffset = false
 
 			} else if field.BitSize == 0 { // non-bit-packed field
 				field.BitSize = 8 * valueDesc.ByteSize
@@ -577,14 +741,31 @@ type FieldDescriptor struct {
 	Name  string
 	Value *DataDescriptor
 
+	// True if the field is a base class subobject (the field's name is the
+	// base class' name).
+	IsBaseClass bool
+
 	// Physical data representation
 	ByteOffset int // relative to the beginning of the struct
 	BitOffset  int // relative to the beginning of the field byte
 	BitSize    int
 
+	// When non-nil, the field's location is computed at access time by
+	// evaluating this DW_AT_data_member_location expression (e.g., virtual base
+	// class), and ByteOffset is not applicable.
+	LocationExpression []byte
+
+	// When true, BitOffset is relative to the end of the field's storage unit,
+	// and is defer resolved (dwarf4's deprecated bit-packed field encoding).
+	hasDeprecatedBitOffset bool
+
 	DIE *dwarf.DebugInfoEntry
 }
 
+func (field *FieldDescriptor) IsBitPacked() bool {
+	return field.BitOffset != 0 || field.BitSize != 8*field.Value.ByteSize
+}
+
 type Parameter struct {
 	*DataDescriptor
 
@@ -627,110 +808,11 @@ type SignatureDescriptor struct {
 	DIE *dwarf.DebugInfoEntry
 }
 
-func (signature *SignatureDescriptor) AssignStackAndRegisters() error {
-	paramIntRegs := []string{
-		"rdi",
-		"rsi",
-		"rdx",
-		"rcx",
-		"r8",
-		"r9",
-	}
-	paramSSERegs := []string{
-		"xmm0",
-		"xmm1",
-		"xmm2",
-		"xmm3",
-		"xmm4",
-		"xmm5",
-		"xmm6",
-		"xmm7",
-	}
-
-	classes, err := signature.Return.ParameterClasses()
-	if err != nil {
-		return fmt.Errorf("return value: %w", err)
-	}
-
-	if len(classes) == 1 && classes[0] == MemoryClass {
-		signature.ReturnInMemory = true
-		paramIntRegs = paramIntRegs[1:] // return value's address is in rdi
-	} else {
-		retIntRegs := []string{"rax", "rdx"}
-		retSSERegs := []string{"xmm0", "xmm1"}
-
-		for _, class := range classes {
-			if class == IntegerClass {
-				signature.ReturnOnRegisters = append(
-					signature.ReturnOnRegisters,
-					retIntRegs[0])
-				retIntRegs = retIntRegs[1:]
-			} else if class == SSEClass {
-				signature.ReturnOnRegisters = append(
-					signature.ReturnOnRegisters,
-					retSSERegs[0])
-				retSSERegs = retSSERegs[1:]
-			} else {
-				panic("should never happen")
-			}
-		}
-	}
-
-	maybeAllocateRegisters := func(classes []ParameterClass) []string {
-		if len(classes) == 1 && classes[0] == MemoryClass {
-			return nil
-		}
-
-		numInts := 0
-		numSSEs := 0
-		for _, class := range classes {
-			if class == IntegerClass {
-				numInts += 1
-			} else if class == SSEClass {
-				numSSEs += 1
-			} else {
-				panic("should never happen")
-			}
-		}
-
-		if len(paramIntRegs) < numInts || len(paramSSERegs) < numSSEs {
-			return nil
-		}
-
-		regs := []string{}
-		for _, class := range classes {
-			if class == IntegerClass {
-				regs = append(regs, paramIntRegs[0])
-				paramIntRegs = paramIntRegs[1:]
-			} else {
-				regs = append(regs, paramSSERegs[0])
-				paramSSERegs = paramSSERegs[1:]
-			}
-		}
-
-		return regs
-	}
-
-	paramStackSize := 0
-	for idx, param := range signature.Parameters {
-		classes, err := param.ParameterClasses()
-		if err != nil {
-			return fmt.Errorf("parameter %d: %w", idx, err)
-		}
-
-		registers := maybeAllocateRegisters(classes)
-		if len(registers) == 0 { // in memory
-			param.StackOffset = uint64(8 + paramStackSize)
-			paramStackSize += param.ByteSize
-		} else {
-			param.Registers = registers
-		}
-	}
-
-	signature.ParameterStackSize = uint64(paramStackSize)
-	signature.NumSSERegistersUsed = uint64(8 - len(paramSSERegs))
-
-	return nil
+// NOTE: The architecture (see arch package) implements this interface.
+type CallingConvention interface {
+	// Populates the signature's parameter / return value stack and register
+	// assignments.
+	AssignStackAndRegisters(signature *SignatureDescriptor) error
 }
 
 func (signature *SignatureDescriptor) Matches(arguments []*TypedData) bool {
@@ -763,8 +845,9 @@ type unboundMethod struct {
 }
 
 type DataDescriptorPool struct {
-	loadedElves *loadedelves.Files
-	memory      *memory.VirtualMemory
+	loadedElves       *loadedelves.Files
+	memory            *memory.VirtualMemory
+	callingConvention CallingConvention
 
 	// For non-function kinds
 	variableDescriptors map[*dwarf.DebugInfoEntry]*DataDescriptor
@@ -777,10 +860,12 @@ type DataDescriptorPool struct {
 func NewDataDescriptorPool(
 	loadedElves *loadedelves.Files,
 	mem *memory.VirtualMemory,
+	callingConvention CallingConvention,
 ) *DataDescriptorPool {
 	return &DataDescriptorPool{
 		loadedElves:         loadedElves,
 		memory:              mem,
+		callingConvention:   callingConvention,
 		variableDescriptors: map[*dwarf.DebugInfoEntry]*DataDescriptor{},
 		functions:           map[string]*TypedData{},
 		methods:             map[methodKey]unboundMethod{},
@@ -809,12 +894,46 @@ func (pool *DataDescriptorPool) GetVariableDescriptor(
 
 	err = descriptor.resolveSizeAndValueDescriptor()
 	if err != nil {
+		// Don't cache the partially resolved descriptor.
+		delete(pool.variableDescriptors, typeDie)
 		return nil, err
 	}
 
 	return descriptor, nil
 }
 
+// Resolve a named type (base type, typedef, struct/class/union or enum).  The
+// name may be suffixed by one or more "*" to denote pointer types.
+func (pool *DataDescriptorPool) GetTypeDescriptorByName(
+	name string,
+) (
+	*DataDescriptor,
+	error,
+) {
+	name = strings.TrimSpace(name)
+	if strings.HasSuffix(name, "*") {
+		value, err := pool.GetTypeDescriptorByName(name[:len(name)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		return pool.NewPointerType(value), nil
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%w. type name not specified", ErrInvalidInput)
+	}
+
+	die, err := pool.loadedElves.TypeEntryWithName(name)
+	if err != nil {
+		return nil, err
+	} else if die == nil {
+		return nil, fmt.Errorf("%w. type (%s) not found", ErrInvalidInput, name)
+	}
+
+	return pool.GetVariableDescriptor(die)
+}
+
 func (pool *DataDescriptorPool) parseDataTypeDIE(
 	die *dwarf.DebugInfoEntry,
 ) (
@@ -845,10 +964,15 @@ func (pool *DataDescriptorPool) parseDataTypeDIE(
 
 		return pool.parseStructType(die)
 
-	case dwarf.DW_TAG_enumeration_type,
-		dwarf.DW_TAG_typedef,
+	case dwarf.DW_TAG_enumeration_type:
+		return pool.parseEnumType(die)
+
+	case dwarf.DW_TAG_typedef,
 		dwarf.DW_TAG_const_type,
-		dwarf.DW_TAG_volatile_type:
+		dwarf.DW_TAG_volatile_type,
+		dwarf.DW_TAG_restrict_type,
+		dwarf.DW_TAG_atomic_type,
+		dwarf.DW_TAG_immutable_type:
 
 		// NOTE: We'll ignore type qualifiers that don't impact data representation
 
@@ -893,14 +1017,23 @@ func (pool *DataDescriptorPool) parseBaseType(
 		}
 	case dwarf.DW_ATE_signed:
 		kind = IntKind
-		if byteSize != 1 && byteSize != 2 && byteSize != 4 && byteSize != 8 {
+		if byteSize != 1 && byteSize != 2 && byteSize != 4 && byteSize != 8 &&
+			byteSize != 16 {
+
 			return nil, fmt.Errorf("unsupported int size (%d)", byteSize)
 		}
 	case dwarf.DW_ATE_unsigned:
 		kind = UintKind
-		if byteSize != 1 && byteSize != 2 && byteSize != 4 && byteSize != 8 {
+		if byteSize != 1 && byteSize != 2 && byteSize != 4 && byteSize != 8 &&
+			byteSize != 16 {
+
 			return nil, fmt.Errorf("unsupported uint size (%d)", byteSize)
 		}
+	case dwarf.DW_ATE_UTF: // char8_t / char16_t / char32_t
+		kind = UintKind
+		if byteSize != 1 && byteSize != 2 && byteSize != 4 {
+			return nil, fmt.Errorf("unsupported utf char size (%d)", byteSize)
+		}
 	case dwarf.DW_ATE_float:
 		kind = FloatKind
 		if byteSize != 4 && byteSize != 8 {
@@ -919,6 +1052,103 @@ func (pool *DataDescriptorPool) parseBaseType(
 	}, nil
 }
 
+func (pool *DataDescriptorPool) parseEnumType(
+	die *dwarf.DebugInfoEntry,
+) (
+	*DataDescriptor,
+	error,
+) {
+	hasBase, err := die.HasTypeEntry()
+	if err != nil {
+		return nil, fmt.Errorf("invalid enum type: %w", err)
+	}
+
+	descriptor := &DataDescriptor{}
+	if hasBase {
+		baseDie, err := die.TypeEntry()
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum type: %w", err)
+		}
+
+		base, err := pool.GetVariableDescriptor(baseDie)
+		if err != nil {
+			return nil, err
+		}
+
+		*descriptor = *base
+
+		switch base.Kind {
+		case IntKind, UintKind:
+		case CharKind: // e.g., enum class level : unsigned char
+			descriptor.Kind = IntKind
+			if base.IsUnsignedChar() {
+				descriptor.Kind = UintKind
+			}
+		default:
+			return nil, fmt.Errorf("unsupported enum base type (%s)", base.Kind)
+		}
+	} else {
+		// NOTE: pre-dwarf 3 enums do not specify the underlying type.
+		byteSize, ok := die.Uint(dwarf.DW_AT_byte_size)
+		if !ok {
+			return nil, fmt.Errorf("enum byte size not found")
+		}
+
+		descriptor.Pool = pool
+		descriptor.Kind = IntKind
+		descriptor.ByteSize = int(byteSize)
+	}
+
+	name, _, err := die.Name()
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor.Name = name
+	descriptor.DIE = die
+	descriptor.Enumerators = []Enumerator{}
+
+	for _, child := range die.Children {
+		if child.Tag != dwarf.DW_TAG_enumerator {
+			continue
+		}
+
+		enumeratorName, _, err := child.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		constValue, ok := child.Any(dwarf.DW_AT_const_value)
+		if !ok {
+			return nil, fmt.Errorf(
+				"enumerator (%s) value not found",
+				enumeratorName)
+		}
+
+		var value int64
+		switch constValue := constValue.(type) {
+		case uint64:
+			value = int64(constValue)
+		case int64:
+			value = constValue
+		default:
+			return nil, fmt.Errorf(
+				"invalid enumerator (%s) value (%v)",
+				enumeratorName,
+				constValue)
+		}
+
+		descriptor.Enumerators = append(
+			descriptor.Enumerators,
+			Enumerator{
+				Name:  enumeratorName,
+				Value: value,
+			})
+	}
+
+	return descriptor, nil
+}
+
 func (pool *DataDescriptorPool) parseMemberPointerType(
 	die *dwarf.DebugInfoEntry,
 ) (
@@ -954,15 +1184,40 @@ func (pool *DataDescriptorPool) parseArrayType(
 	var prev *DataDescriptor
 	for _, child := range die.Children {
 		if child.Tag == dwarf.DW_TAG_subrange_type {
-			dim, ok := child.Uint(dwarf.DW_AT_upper_bound)
-			if !ok {
-				return nil, fmt.Errorf("invalid array dimension")
+			current := &DataDescriptor{
+				Pool: pool,
+				Kind: ArrayKind,
 			}
 
-			current := &DataDescriptor{
-				Pool:        pool,
-				Kind:        ArrayKind,
-				NumElements: int(dim + 1),
+			// NOTE: DW_AT_count takes precedence over DW_AT_upper_bound.  A
+			// subrange without either attribute (e.g., a flexible array member)
+			// is unbounded, i.e., the array has no (known) elements.
+			bound, isCount := child.Any(dwarf.DW_AT_count)
+			if !isCount {
+				bound, _ = child.Any(dwarf.DW_AT_upper_bound)
+			}
+
+			switch bound := bound.(type) {
+			case nil:
+				// unbounded
+			case uint64:
+				if !isCount {
+					bound++
+				}
+				current.NumElements = int(bound)
+			case int64:
+				if !isCount {
+					bound++
+				}
+				if bound < 0 {
+					return nil, fmt.Errorf("invalid array dimension (%d)", bound)
+				}
+				current.NumElements = int(bound)
+			case []byte, *dwarf.DebugInfoEntryReference:
+				// variable length array
+				current.DynamicBound = child
+			default:
+				return nil, fmt.Errorf("unsupported array dimension (%T)", bound)
 			}
 
 			if outerMost == nil {
@@ -997,26 +1252,65 @@ func (pool *DataDescriptorPool) parseStructType(
 
 	fields := []*FieldDescriptor{}
 	for _, child := range die.Children {
-		if child.Tag != dwarf.DW_TAG_member {
+		if child.Tag != dwarf.DW_TAG_member &&
+			child.Tag != dwarf.DW_TAG_inheritance {
+
 			continue
 		}
 
-		location, ok := child.Uint(dwarf.DW_AT_data_member_location)
-		if !ok { // static field member
-			continue
+		// NOTE: the location is either a constant offset, or a location
+		// expression (e.g., virtual base class).  Bit-packed fields using
+		// dwarf4's preferred encoding (e.g., clang) may only specify
+		// DW_AT_data_bit_offset.
+		location := uint64(0)
+		var locationExpression []byte
+		value, ok := child.Any(dwarf.DW_AT_data_member_location)
+		if !ok {
+			_, isBitPacked := child.Any(dwarf.DW_AT_data_bit_offset)
+			if child.Tag == dwarf.DW_TAG_member && !isBitPacked { // static field
+				continue
+			}
+		} else {
+			switch v := value.(type) {
+			case uint64:
+				location = v
+			case []byte:
+				locationExpression = v
+			default:
+				return nil, fmt.Errorf(
+					"unsupported data member location (%T)",
+					value)
+			}
 		}
 
-		name, _, err := child.Name()
-		if err != nil {
-			return nil, err
+		var name string
+		isBaseClass := child.Tag == dwarf.DW_TAG_inheritance
+		if isBaseClass {
+			baseClass, err := child.TypeEntry()
+			if err != nil {
+				return nil, fmt.Errorf("invalid base class: %w", err)
+			}
+
+			name, _, err = baseClass.Name()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			name, _, err = child.Name()
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		// NOTE: field's data descriptor and non-bit-packed bit size are defer
 		// resolved.
 		field := &FieldDescriptor{
-			Pool: pool,
-			Name: name,
-			DIE:  child,
+			Pool:               pool,
+			Name:               name,
+			IsBaseClass:        isBaseClass,
+			LocationExpression: locationExpression,
+			DIE:                child,
 		}
 
 		fields = append(fields, field)
@@ -1041,9 +1335,29 @@ func (pool *DataDescriptorPool) parseStructType(
 		// NOTE: BitSize for non-bit-packed field is uninitialized for now
 		field.ByteOffset = int(location)
 
-		// dwarf4's deprecated bit-packed field encoding
-		bitOffset, ok := child.Uint(dwarf.DW_AT_bit_offset)
+		// dwarf4's deprecated bit-packed field encoding.  The bit offset is the
+		// number of bits from the storage unit's most significant bit to the
+		// field's most significant bit.
+		//
+		// NOTE: gcc encodes the bit offset as a negative signed value when the
+		// field straddles beyond the storage unit (e.g., packed struct).
+		//
+		// NOTE: this assumes little-endian bit numbering (x64).
+		value, ok = child.Any(dwarf.DW_AT_bit_offset)
 		if ok {
+			var bitOffset int
+			switch v := value.(type) {
+			case uint64:
+				bitOffset = int(v)
+			case int64:
+				bitOffset = int(v)
+			default:
+				return nil, fmt.Errorf(
+					"invalid bit-packed field (%s). unsupported bit offset (%T)",
+					name,
+					value)
+			}
+
 			bitSize, ok := child.Uint(dwarf.DW_AT_bit_size)
 			if !ok {
 				return nil, fmt.Errorf(
@@ -1051,11 +1365,13 @@ func (pool *DataDescriptorPool) parseStructType(
 					name)
 			}
 
-			// NOTE: The bit offset computed here is relative to the end of the field
-			// byte.  The real bit off (relative to the beginning of field byte) is
-			// resolved later.
-			field.BitOffset = int(-bitOffset - bitSize)
+			// NOTE: The bit offset computed here is relative to the end of the
+			// field's storage unit.  The real bit offset (relative to the
+			// beginning of field byte) is resolved later, once the storage unit's
+			// size is known.
+			field.BitOffset = -bitOffset - int(bitSize)
 			field.BitSize = int(bitSize)
+			field.hasDeprecatedBitOffset = true
 		}
 	}
 
@@ -1205,6 +1521,20 @@ func (pool *DataDescriptorPool) NewPointerType(
 	}
 }
 
+func (pool *DataDescriptorPool) NewArrayType(
+	valueType *DataDescriptor,
+	numElements int,
+) *DataDescriptor {
+	return &DataDescriptor{
+		Pool:        pool,
+		Kind:        ArrayKind,
+		ByteSize:    numElements * valueType.ByteSize,
+		Value:       valueType,
+		NumElements: numElements,
+		resolved:    true,
+	}
+}
+
 func (pool *DataDescriptorPool) NewCString(
 	context EvaluationContext,
 	formatPrefix string,
@@ -1275,7 +1605,7 @@ func (pool *DataDescriptorPool) GetMalloc() (*TypedData, error) {
 		Return: pool.NewPointerType(pool.NewCharType()),
 	}
 
-	err := signature.AssignStackAndRegisters()
+	err := pool.callingConvention.AssignStackAndRegisters(signature)
 	if err != nil {
 		return nil, err
 	}
@@ -1320,6 +1650,13 @@ func (pool *DataDescriptorPool) GetFunction(
 		return nil, err
 	}
 
+	if len(functionDefs) == 0 {
+		functionDefs, err = pool.staticMethodDefinitionEntries(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(functionDefs) == 0 {
 		return nil, nil
 	}
@@ -1339,8 +1676,10 @@ func (pool *DataDescriptorPool) GetFunction(
 	}
 
 	function = &TypedData{
-		VirtualMemory:     pool.memory,
-		FormatPrefix:      name,
+		VirtualMemory: pool.memory,
+		// NOTE: function referenced by linkage name is shown with its
+		// demangled name.
+		FormatPrefix:      demangle.Filter(name),
 		DataDescriptor:    descriptor,
 		FunctionAddresses: addresses,
 	}
@@ -1367,34 +1706,14 @@ func (pool *DataDescriptorPool) GetMethod(
 		return method.DataDescriptor, method.Addresses, nil
 	}
 
-	methodDefs := []*dwarf.DebugInfoEntry{}
-	for _, child := range receiverTypeDie.Children {
-		if child.Tag != dwarf.DW_TAG_subprogram {
-			continue
-		}
-
-		if child.SpecIndex(dwarf.DW_AT_object_pointer) == -1 {
-			continue
-		}
-
-		name, ok, err := child.Name()
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if !ok || name != methodName {
-			continue
-		}
-
-		methodDef, err := child.FindMethodDefinitionEntry()
-		if err != nil {
-			return nil, nil, err
-		}
-
-		methodDefs = append(methodDefs, methodDef)
+	methodDefs, staticDefs, err := methodDefinitionEntries(
+		receiverTypeDie,
+		methodName)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if len(methodDefs) == 0 {
+	if len(methodDefs) == 0 && len(staticDefs) == 0 {
 		return nil, nil, nil
 	}
 
@@ -1408,6 +1727,17 @@ func (pool *DataDescriptorPool) GetMethod(
 		return nil, nil, err
 	}
 
+	// NOTE: static member functions are invoked without the receiver.
+	staticSignatures, staticAddresses, err := pool.parseSignatures(
+		false,
+		staticDefs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signatures = append(signatures, staticSignatures...)
+	addresses = append(addresses, staticAddresses...)
+
 	descriptor := &DataDescriptor{
 		Pool:       pool,
 		Kind:       MethodKind,
@@ -1425,6 +1755,114 @@ func (pool *DataDescriptorPool) GetMethod(
 	return descriptor, addresses, nil
 }
 
+// Returns the named instance methods' and static member functions'
+// definition entries.  Static member functions are identified by the lack of
+// DW_AT_object_pointer (i.e., no implicit this parameter).
+func methodDefinitionEntries(
+	receiverTypeDie *dwarf.DebugInfoEntry,
+	methodName string,
+) (
+	[]*dwarf.DebugInfoEntry,
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	methodDefs := []*dwarf.DebugInfoEntry{}
+	staticDefs := []*dwarf.DebugInfoEntry{}
+	for _, child := range receiverTypeDie.Children {
+		if child.Tag != dwarf.DW_TAG_subprogram {
+			continue
+		}
+
+		name, ok, err := child.Name()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !ok || name != methodName {
+			continue
+		}
+
+		def, err := child.FindMethodDefinitionEntry()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if child.SpecIndex(dwarf.DW_AT_object_pointer) == -1 &&
+			def.SpecIndex(dwarf.DW_AT_object_pointer) == -1 {
+
+			staticDefs = append(staticDefs, def)
+		} else {
+			methodDefs = append(methodDefs, def)
+		}
+	}
+
+	return methodDefs, staticDefs, nil
+}
+
+// Returns the static member function definition entries for the qualified
+// <type>::<function> name.
+func (pool *DataDescriptorPool) staticMethodDefinitionEntries(
+	qualifiedName string,
+) (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	idx := strings.LastIndex(qualifiedName, "::")
+	if idx == -1 {
+		return nil, nil
+	}
+
+	typeDie, err := pool.loadedElves.TypeEntryWithName(qualifiedName[:idx])
+	if err != nil || typeDie == nil {
+		return nil, err
+	}
+
+	_, staticDefs, err := methodDefinitionEntries(
+		typeDie,
+		qualifiedName[idx+2:])
+	return staticDefs, err
+}
+
+// Returns the function's name followed by its (non-artificial) parameter
+// types, e.g., "print_type(int32)", which distinguishes overloaded functions.
+func (pool *DataDescriptorPool) FunctionSignatureName(
+	funcDie *dwarf.DebugInfoEntry,
+) (
+	string,
+	error,
+) {
+	name, _, err := funcDie.Name()
+	if err != nil {
+		return "", err
+	}
+
+	paramTypes := []string{}
+	for _, child := range funcDie.Children {
+		if child.Tag != dwarf.DW_TAG_formal_parameter {
+			continue
+		}
+
+		isArtificial, _ := child.Bool(dwarf.DW_AT_artificial)
+		if isArtificial { // e.g., the "this" pointer
+			continue
+		}
+
+		paramTypeDie, err := child.TypeEntry()
+		if err != nil {
+			return "", fmt.Errorf("parameter type error: %w", err)
+		}
+
+		paramDescriptor, err := pool.GetVariableDescriptor(paramTypeDie)
+		if err != nil {
+			return "", err
+		}
+
+		paramTypes = append(paramTypes, paramDescriptor.TypeName())
+	}
+
+	return name + "(" + strings.Join(paramTypes, ", ") + ")", nil
+}
+
 func (pool *DataDescriptorPool) parseSignatures(
 	isMethod bool,
 	functionDies []*dwarf.DebugInfoEntry,
@@ -1459,9 +1897,13 @@ func (pool *DataDescriptorPool) parseSignatures(
 				})
 		}
 
-		var retDescriptor *DataDescriptor
-		if funcDie.SpecIndex(dwarf.DW_AT_type) != -1 { // has return value
+		hasReturnValue, err := funcDie.HasTypeEntry()
+		if err != nil {
+			return nil, nil, fmt.Errorf("return type error: %w", err)
+		}
 
+		var retDescriptor *DataDescriptor
+		if hasReturnValue {
 			retTypeDie, err := funcDie.TypeEntry()
 			if err != nil {
 				return nil, nil, fmt.Errorf("return type error: %w", err)
@@ -1487,7 +1929,7 @@ func (pool *DataDescriptorPool) parseSignatures(
 			DIE:        funcDie,
 		}
 
-		err = signature.AssignStackAndRegisters()
+		err = pool.callingConvention.AssignStackAndRegisters(signature)
 		if err != nil {
 			return nil, nil, err
 		}
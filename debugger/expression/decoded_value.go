@@ -0,0 +1,111 @@
+package expression
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+// DecodedValue is a formatting independent representation of TypedData,
+// intended for programmatic consumers.
+type DecodedValue struct {
+	Type     *DataDescriptor
+	TypeName string
+
+	// Only populated for data with a physical location (i.e., not an implicit
+	// value, function, or method).
+	HasAddress bool
+	Address    VirtualAddress
+
+	// Raw (materialized) bytes.  Not populated for void / function / method
+	// kinds.
+	Bytes []byte
+
+	// See TypedData.ToGoValue for the mapping.
+	Value interface{}
+}
+
+func (data *TypedData) Decode() (*DecodedValue, error) {
+	result := &DecodedValue{
+		Type:     data.DataDescriptor,
+		TypeName: data.TypeName(),
+	}
+
+	switch data.Kind {
+	case VoidKind, FunctionKind, MethodKind:
+	default:
+		result.HasAddress = data.ImplicitValue == nil
+		if result.HasAddress {
+			result.Address = data.Address
+		}
+
+		bytes, err := data.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		result.Bytes = bytes
+	}
+
+	value, err := data.ToGoValue()
+	if err != nil {
+		return nil, err
+	}
+	result.Value = value
+
+	return result, nil
+}
+
+// This converts the data into a golang value.  The mapping is:
+//   - void: nil
+//   - bool / char / int / uint / float: the correctly sized golang value (see
+//     DecodeSimpleValue)
+//   - pointer / member pointer: VirtualAddress
+//   - array: []interface{}, one entry per element
+//   - struct / union: map[string]interface{}, keyed by field name
+//   - function / method: []VirtualAddress, one entry per signature
+func (data *TypedData) ToGoValue() (interface{}, error) {
+	switch data.Kind {
+	case VoidKind:
+		return nil, nil
+	case FunctionKind, MethodKind:
+		return data.FunctionAddresses, nil
+	case ArrayKind:
+		result := make([]interface{}, 0, data.NumElements)
+		for i := 0; i < data.NumElements; i++ {
+			element, err := data.Index(i)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := element.ToGoValue()
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, value)
+		}
+		return result, nil
+	case StructKind, UnionKind:
+		result := make(map[string]interface{}, len(data.Fields))
+		for _, field := range data.Fields {
+			element, err := data.fieldData(field)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := element.ToGoValue()
+			if err != nil {
+				return nil, err
+			}
+
+			result[field.Name] = value
+		}
+		return result, nil
+	default:
+		value, err := data.DecodeSimpleValue()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert to go value: %w", err)
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,190 @@
+package expression
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/ianlancetaylor/demangle"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const (
+	vtableSymbolPrefix          = "_ZTV"
+	vtableDemangledSymbolPrefix = "vtable for "
+
+	// The vtable pointer points past the vtable's offset to top and type info
+	// entries, i.e., the offset to top is 16 bytes before the pointed-to
+	// address.  See Itanium C++ ABI, section 2.5 Virtual Table Layout.
+	vtableOffsetToTopOffset = 16
+)
+
+// True if the struct has a vtable pointer, i.e., the struct (or one of its
+// base classes) has virtual methods or virtual base classes.
+//
+// NOTE: we rely on the compiler generated artificial vtable pointer member
+// (gcc names it _vptr.<class>, clang names it _vptr$<class>).  The vtable
+// pointer is always at offset 0 of a dynamic class.
+func (descriptor *DataDescriptor) IsPolymorphic() bool {
+	if descriptor.Kind != StructKind {
+		return false
+	}
+
+	for _, field := range descriptor.Fields {
+		if strings.HasPrefix(field.Name, "_vptr") {
+			return true
+		}
+
+		if field.IsBaseClass &&
+			field.Value != nil &&
+			field.Value.IsPolymorphic() {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the polymorphic data's dynamic (most-derived) type.  The dynamic
+// type is determined by reading the object's vtable pointer, and mapping the
+// vtable symbol (_ZTV<mangled class name>) back to the class type.
+//
+// For a polymorphic object, the returned data refers to the complete object
+// (i.e., the address is adjusted by the vtable's offset to top).  For a
+// pointer to polymorphic object, the returned data is a pointer to the
+// complete object.  Null pointers are returned as is.
+func (data *TypedData) DynamicType() (*TypedData, error) {
+	if data.Unavailable != Available {
+		return nil, data.unavailableError()
+	}
+
+	if data.Kind == PointerKind {
+		if !data.Value.IsPolymorphic() {
+			return nil, fmt.Errorf(
+				"%w. cannot resolve dynamic type. %s is not a pointer to "+
+					"polymorphic class",
+				ErrInvalidInput,
+				data.TypeName())
+		}
+
+		pointee, err := data.Dereference()
+		if err != nil {
+			return nil, err
+		}
+
+		if pointee.Address == 0 {
+			return data, nil
+		}
+
+		object, err := pointee.dynamicObject()
+		if err != nil {
+			return nil, err
+		}
+
+		content := make([]byte, 8)
+		binary.LittleEndian.PutUint64(content, uint64(object.Address))
+
+		return data.Pool.NewPointerType(object.DataDescriptor).NewImplicitData(
+			data.FormatPrefix,
+			content)
+	}
+
+	if !data.IsPolymorphic() {
+		return nil, fmt.Errorf(
+			"%w. cannot resolve dynamic type. %s is not a polymorphic class",
+			ErrInvalidInput,
+			data.TypeName())
+	}
+
+	return data.dynamicObject()
+}
+
+// Returns the dynamic type's name if the pointer points to a polymorphic
+// object whose dynamic type differs from the static pointee type.  Otherwise,
+// this returns empty string.
+func (data *TypedData) dynamicPointeeTypeName() string {
+	if data.Kind != PointerKind || !data.Value.IsPolymorphic() {
+		return ""
+	}
+
+	pointee, err := data.Dereference()
+	if err != nil || pointee.Address == 0 {
+		return ""
+	}
+
+	object, err := pointee.dynamicObject()
+	if err != nil || object.Name == pointee.Name {
+		return ""
+	}
+
+	return object.TypeName()
+}
+
+func (data *TypedData) dynamicObject() (*TypedData, error) {
+	if data.ImplicitValue != nil || data.Address == 0 {
+		return nil, fmt.Errorf(
+			"cannot resolve dynamic type. %s has no address",
+			data.FormatPrefix)
+	}
+
+	vptr, err := data.readUint64(data.Address)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot resolve dynamic type. failed to read vtable pointer: %w",
+			err)
+	}
+
+	symbol := data.Pool.loadedElves.SymbolSpans(VirtualAddress(vptr))
+	if symbol == nil || !strings.HasPrefix(symbol.Name, vtableSymbolPrefix) {
+		return nil, fmt.Errorf(
+			"cannot resolve dynamic type. vtable not found at %s",
+			VirtualAddress(vptr))
+	}
+
+	// NOTE: the symbol's demangled name is not populated for data symbols.
+	typeName, ok := strings.CutPrefix(
+		demangle.Filter(symbol.Name),
+		vtableDemangledSymbolPrefix)
+	if !ok {
+		return nil, fmt.Errorf(
+			"cannot resolve dynamic type. failed to demangle %s",
+			symbol.Name)
+	}
+
+	offsetToTop, err := data.readUint64(
+		VirtualAddress(vptr) - vtableOffsetToTopOffset)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"cannot resolve dynamic type. failed to read offset to top: %w",
+			err)
+	}
+
+	descriptor, err := data.Pool.GetTypeDescriptorByName(typeName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve dynamic type: %w", err)
+	}
+
+	return &TypedData{
+		VirtualMemory:  data.VirtualMemory,
+		FormatPrefix:   data.FormatPrefix,
+		DataDescriptor: descriptor,
+		Address:        data.Address + VirtualAddress(offsetToTop),
+		BitOffset:      0,
+		BitSize:        8 * descriptor.ByteSize,
+	}, nil
+}
+
+func (data *TypedData) readUint64(address VirtualAddress) (uint64, error) {
+	content := make([]byte, 8)
+	n, err := data.Read(address, content)
+	if err != nil {
+		return 0, err
+	}
+	if n != len(content) {
+		return 0, fmt.Errorf("incorrect number of bytes read (%d)", n)
+	}
+
+	return binary.LittleEndian.Uint64(content), nil
+}
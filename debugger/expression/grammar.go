@@ -17,93 +17,120 @@ const (
 	StringLiteralToken  = SymbolId(259)
 	TrueToken           = SymbolId(260)
 	FalseToken          = SymbolId(261)
-	IdentifierToken     = SymbolId(262)
-	DollarIntegerToken  = SymbolId(263)
-	DotToken            = SymbolId(264)
-	CommaToken          = SymbolId(265)
-	ArrowToken          = SymbolId(266)
-	LparenToken         = SymbolId(267)
-	RparenToken         = SymbolId(268)
-	LbracketToken       = SymbolId(269)
-	RbracketToken       = SymbolId(270)
+	DynamicCastToken    = SymbolId(262)
+	IdentifierToken     = SymbolId(263)
+	DollarIntegerToken  = SymbolId(264)
+	DotToken            = SymbolId(265)
+	CommaToken          = SymbolId(266)
+	ArrowToken          = SymbolId(267)
+	LparenToken         = SymbolId(268)
+	RparenToken         = SymbolId(269)
+	LbracketToken       = SymbolId(270)
+	RbracketToken       = SymbolId(271)
+	QuestionToken       = SymbolId(272)
+	ColonToken          = SymbolId(273)
 )
 
+type ConditionalExprReducer interface {
+	// 17:27: conditional_expr -> ...
+	ToConditionalExpr(ConditionalFalseBranch_ *TypedData, Expression_ *TypedData) (*TypedData, error)
+}
+
+type ConditionalTrueBranchReducer interface {
+	// 19:34: conditional_true_branch -> ...
+	ToConditionalTrueBranch(AccessibleExpr_ *TypedData, Question_ *TokenValue) (*TypedData, error)
+}
+
+type ConditionalFalseBranchReducer interface {
+	// 21:35: conditional_false_branch -> ...
+	ToConditionalFalseBranch(ConditionalTrueBranch_ *TypedData, Expression_ *TypedData, Colon_ *TokenValue) (*TypedData, error)
+}
+
 type LiteralExprReducer interface {
-	// 26:2: literal_expr -> TRUE: ...
+	// 38:2: literal_expr -> TRUE: ...
 	TrueToLiteralExpr(True_ *TokenValue) (*TypedData, error)
 
-	// 27:2: literal_expr -> FALSE: ...
+	// 39:2: literal_expr -> FALSE: ...
 	FalseToLiteralExpr(False_ *TokenValue) (*TypedData, error)
 
-	// 28:2: literal_expr -> INTEGER_LITERAL: ...
+	// 40:2: literal_expr -> INTEGER_LITERAL: ...
 	IntegerLiteralToLiteralExpr(IntegerLiteral_ *TokenValue) (*TypedData, error)
 
-	// 29:2: literal_expr -> FLOAT_LITERAL: ...
+	// 41:2: literal_expr -> FLOAT_LITERAL: ...
 	FloatLiteralToLiteralExpr(FloatLiteral_ *TokenValue) (*TypedData, error)
 
-	// 30:2: literal_expr -> RUNE_LITERAL: ...
+	// 42:2: literal_expr -> RUNE_LITERAL: ...
 	RuneLiteralToLiteralExpr(RuneLiteral_ *TokenValue) (*TypedData, error)
 
-	// 31:2: literal_expr -> STRING_LITERAL: ...
+	// 43:2: literal_expr -> STRING_LITERAL: ...
 	StringLiteralToLiteralExpr(StringLiteral_ *TokenValue) (*TypedData, error)
 }
 
 type NamedExprReducer interface {
-	// 33:21: named_expr -> ...
+	// 45:21: named_expr -> ...
 	ToNamedExpr(Identifier_ *TokenValue) (*TypedData, error)
 }
 
 type PreviousResultExprReducer interface {
-	// 35:31: previous_result_expr -> ...
+	// 47:31: previous_result_expr -> ...
 	ToPreviousResultExpr(DollarInteger_ *TokenValue) (*TypedData, error)
 }
 
 type GroupedExprReducer interface {
-	// 37:23: grouped_expr -> ...
+	// 49:23: grouped_expr -> ...
 	ToGroupedExpr(Lparen_ *TokenValue, Expression_ *TypedData, Rparen_ *TokenValue) (*TypedData, error)
 }
 
+type DynamicCastExprReducer interface {
+	// 53:28: dynamic_cast_expr -> ...
+	ToDynamicCastExpr(DynamicCast_ *TokenValue, Lparen_ *TokenValue, Expression_ *TypedData, Rparen_ *TokenValue) (*TypedData, error)
+}
+
 type DirectAccessExprReducer interface {
-	// 39:29: direct_access_expr -> ...
+	// 55:29: direct_access_expr -> ...
 	ToDirectAccessExpr(AccessibleExpr_ *TypedData, Dot_ *TokenValue, Identifier_ *TokenValue) (*TypedData, error)
 }
 
 type IndirectAccessExprReducer interface {
-	// 41:31: indirect_access_expr -> ...
+	// 57:31: indirect_access_expr -> ...
 	ToIndirectAccessExpr(AccessibleExpr_ *TypedData, Arrow_ *TokenValue, Identifier_ *TokenValue) (*TypedData, error)
 }
 
 type IndexExprReducer interface {
-	// 43:21: index_expr -> ...
+	// 59:21: index_expr -> ...
 	ToIndexExpr(AccessibleExpr_ *TypedData, Lbracket_ *TokenValue, Expression_ *TypedData, Rbracket_ *TokenValue) (*TypedData, error)
 }
 
 type CallExprReducer interface {
-	// 45:20: call_expr -> ...
+	// 61:20: call_expr -> ...
 	ToCallExpr(AccessibleExpr_ *TypedData, Lparen_ *TokenValue, Arguments_ []*TypedData, Rparen_ *TokenValue) (*TypedData, error)
 }
 
 type ArgumentsReducer interface {
-	// 48:2: arguments -> empty_list: ...
+	// 64:2: arguments -> empty_list: ...
 	EmptyListToArguments() ([]*TypedData, error)
 
-	// 49:2: arguments -> improper_list: ...
+	// 65:2: arguments -> improper_list: ...
 	ImproperListToArguments(NonEmptyArguments_ []*TypedData, Comma_ *TokenValue) ([]*TypedData, error)
 }
 
 type NonEmptyArgumentsReducer interface {
-	// 53:2: non_empty_arguments -> new: ...
+	// 69:2: non_empty_arguments -> new: ...
 	NewToNonEmptyArguments(Expression_ *TypedData) ([]*TypedData, error)
 
-	// 54:2: non_empty_arguments -> append: ...
+	// 70:2: non_empty_arguments -> append: ...
 	AppendToNonEmptyArguments(NonEmptyArguments_ []*TypedData, Comma_ *TokenValue, Expression_ *TypedData) ([]*TypedData, error)
 }
 
 type Reducer interface {
+	ConditionalExprReducer
+	ConditionalTrueBranchReducer
+	ConditionalFalseBranchReducer
 	LiteralExprReducer
 	NamedExprReducer
 	PreviousResultExprReducer
 	GroupedExprReducer
+	DynamicCastExprReducer
 	DirectAccessExprReducer
 	IndirectAccessExprReducer
 	IndexExprReducer
@@ -129,22 +156,34 @@ func (DefaultParseErrorHandler) Error(nextToken parseutil.Token[SymbolId], stack
 func ExpectedTerminals(id _StateId) []SymbolId {
 	switch id {
 	case _State1:
-		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, LparenToken}
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
 	case _State2:
 		return []SymbolId{_EndMarker}
 	case _State3:
-		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, LparenToken}
-	case _State5:
-		return []SymbolId{RparenToken}
+		return []SymbolId{LparenToken}
+	case _State4:
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
 	case _State6:
-		return []SymbolId{IdentifierToken}
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
 	case _State7:
-		return []SymbolId{IdentifierToken}
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
 	case _State8:
-		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, LparenToken}
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
+	case _State9:
+		return []SymbolId{RparenToken}
 	case _State10:
-		return []SymbolId{RbracketToken}
+		return []SymbolId{IdentifierToken}
 	case _State11:
+		return []SymbolId{IdentifierToken}
+	case _State12:
+		return []SymbolId{IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, LparenToken}
+	case _State14:
+		return []SymbolId{ColonToken}
+	case _State15:
+		return []SymbolId{RparenToken}
+	case _State16:
+		return []SymbolId{RbracketToken}
+	case _State17:
 		return []SymbolId{RparenToken}
 	}
 
@@ -273,6 +312,8 @@ func (i SymbolId) String() string {
 		return "TRUE"
 	case FalseToken:
 		return "FALSE"
+	case DynamicCastToken:
+		return "DYNAMIC_CAST"
 	case IdentifierToken:
 		return "IDENTIFIER"
 	case DollarIntegerToken:
@@ -291,8 +332,18 @@ func (i SymbolId) String() string {
 		return "LBRACKET"
 	case RbracketToken:
 		return "RBRACKET"
+	case QuestionToken:
+		return "QUESTION"
+	case ColonToken:
+		return "COLON"
 	case ExpressionType:
 		return "expression"
+	case ConditionalExprType:
+		return "conditional_expr"
+	case ConditionalTrueBranchType:
+		return "conditional_true_branch"
+	case ConditionalFalseBranchType:
+		return "conditional_false_branch"
 	case AccessibleExprType:
 		return "accessible_expr"
 	case AtomExprType:
@@ -305,6 +356,8 @@ func (i SymbolId) String() string {
 		return "previous_result_expr"
 	case GroupedExprType:
 		return "grouped_expr"
+	case DynamicCastExprType:
+		return "dynamic_cast_expr"
 	case DirectAccessExprType:
 		return "direct_access_expr"
 	case IndirectAccessExprType:
@@ -326,19 +379,23 @@ const (
 	_EndMarker      = SymbolId(0)
 	_WildcardMarker = SymbolId(-1)
 
-	ExpressionType         = SymbolId(271)
-	AccessibleExprType     = SymbolId(272)
-	AtomExprType           = SymbolId(273)
-	LiteralExprType        = SymbolId(274)
-	NamedExprType          = SymbolId(275)
-	PreviousResultExprType = SymbolId(276)
-	GroupedExprType        = SymbolId(277)
-	DirectAccessExprType   = SymbolId(278)
-	IndirectAccessExprType = SymbolId(279)
-	IndexExprType          = SymbolId(280)
-	CallExprType           = SymbolId(281)
-	ArgumentsType          = SymbolId(282)
-	NonEmptyArgumentsType  = SymbolId(283)
+	ExpressionType             = SymbolId(274)
+	ConditionalExprType        = SymbolId(275)
+	ConditionalTrueBranchType  = SymbolId(276)
+	ConditionalFalseBranchType = SymbolId(277)
+	AccessibleExprType         = SymbolId(278)
+	AtomExprType               = SymbolId(279)
+	LiteralExprType            = SymbolId(280)
+	NamedExprType              = SymbolId(281)
+	PreviousResultExprType     = SymbolId(282)
+	GroupedExprType            = SymbolId(283)
+	DynamicCastExprType        = SymbolId(284)
+	DirectAccessExprType       = SymbolId(285)
+	IndirectAccessExprType     = SymbolId(286)
+	IndexExprType              = SymbolId(287)
+	CallExprType               = SymbolId(288)
+	ArgumentsType              = SymbolId(289)
+	NonEmptyArgumentsType      = SymbolId(290)
 )
 
 type _ActionType int
@@ -370,39 +427,53 @@ type _ReduceType int
 
 const (
 	_ReduceAccessibleExprToExpression         = _ReduceType(1)
-	_ReduceAtomExprToAccessibleExpr           = _ReduceType(2)
-	_ReduceDirectAccessExprToAccessibleExpr   = _ReduceType(3)
-	_ReduceIndirectAccessExprToAccessibleExpr = _ReduceType(4)
-	_ReduceIndexExprToAccessibleExpr          = _ReduceType(5)
-	_ReduceCallExprToAccessibleExpr           = _ReduceType(6)
-	_ReduceLiteralExprToAtomExpr              = _ReduceType(7)
-	_ReduceNamedExprToAtomExpr                = _ReduceType(8)
-	_ReducePreviousResultExprToAtomExpr       = _ReduceType(9)
-	_ReduceGroupedExprToAtomExpr              = _ReduceType(10)
-	_ReduceTrueToLiteralExpr                  = _ReduceType(11)
-	_ReduceFalseToLiteralExpr                 = _ReduceType(12)
-	_ReduceIntegerLiteralToLiteralExpr        = _ReduceType(13)
-	_ReduceFloatLiteralToLiteralExpr          = _ReduceType(14)
-	_ReduceRuneLiteralToLiteralExpr           = _ReduceType(15)
-	_ReduceStringLiteralToLiteralExpr         = _ReduceType(16)
-	_ReduceToNamedExpr                        = _ReduceType(17)
-	_ReduceToPreviousResultExpr               = _ReduceType(18)
-	_ReduceToGroupedExpr                      = _ReduceType(19)
-	_ReduceToDirectAccessExpr                 = _ReduceType(20)
-	_ReduceToIndirectAccessExpr               = _ReduceType(21)
-	_ReduceToIndexExpr                        = _ReduceType(22)
-	_ReduceToCallExpr                         = _ReduceType(23)
-	_ReduceEmptyListToArguments               = _ReduceType(24)
-	_ReduceImproperListToArguments            = _ReduceType(25)
-	_ReduceNonEmptyArgumentsToArguments       = _ReduceType(26)
-	_ReduceNewToNonEmptyArguments             = _ReduceType(27)
-	_ReduceAppendToNonEmptyArguments          = _ReduceType(28)
+	_ReduceConditionalExprToExpression        = _ReduceType(2)
+	_ReduceToConditionalExpr                  = _ReduceType(3)
+	_ReduceToConditionalTrueBranch            = _ReduceType(4)
+	_ReduceToConditionalFalseBranch           = _ReduceType(5)
+	_ReduceAtomExprToAccessibleExpr           = _ReduceType(6)
+	_ReduceDirectAccessExprToAccessibleExpr   = _ReduceType(7)
+	_ReduceIndirectAccessExprToAccessibleExpr = _ReduceType(8)
+	_ReduceIndexExprToAccessibleExpr          = _ReduceType(9)
+	_ReduceCallExprToAccessibleExpr           = _ReduceType(10)
+	_ReduceLiteralExprToAtomExpr              = _ReduceType(11)
+	_ReduceNamedExprToAtomExpr                = _ReduceType(12)
+	_ReducePreviousResultExprToAtomExpr       = _ReduceType(13)
+	_ReduceGroupedExprToAtomExpr              = _ReduceType(14)
+	_ReduceDynamicCastExprToAtomExpr          = _ReduceType(15)
+	_ReduceTrueToLiteralExpr                  = _ReduceType(16)
+	_ReduceFalseToLiteralExpr                 = _ReduceType(17)
+	_ReduceIntegerLiteralToLiteralExpr        = _ReduceType(18)
+	_ReduceFloatLiteralToLiteralExpr          = _ReduceType(19)
+	_ReduceRuneLiteralToLiteralExpr           = _ReduceType(20)
+	_ReduceStringLiteralToLiteralExpr         = _ReduceType(21)
+	_ReduceToNamedExpr                        = _ReduceType(22)
+	_ReduceToPreviousResultExpr               = _ReduceType(23)
+	_ReduceToGroupedExpr                      = _ReduceType(24)
+	_ReduceToDynamicCastExpr                  = _ReduceType(25)
+	_ReduceToDirectAccessExpr                 = _ReduceType(26)
+	_ReduceToIndirectAccessExpr               = _ReduceType(27)
+	_ReduceToIndexExpr                        = _ReduceType(28)
+	_ReduceToCallExpr                         = _ReduceType(29)
+	_ReduceEmptyListToArguments               = _ReduceType(30)
+	_ReduceImproperListToArguments            = _ReduceType(31)
+	_ReduceNonEmptyArgumentsToArguments       = _ReduceType(32)
+	_ReduceNewToNonEmptyArguments             = _ReduceType(33)
+	_ReduceAppendToNonEmptyArguments          = _ReduceType(34)
 )
 
 func (i _ReduceType) String() string {
 	switch i {
 	case _ReduceAccessibleExprToExpression:
 		return "AccessibleExprToExpression"
+	case _ReduceConditionalExprToExpression:
+		return "ConditionalExprToExpression"
+	case _ReduceToConditionalExpr:
+		return "ToConditionalExpr"
+	case _ReduceToConditionalTrueBranch:
+		return "ToConditionalTrueBranch"
+	case _ReduceToConditionalFalseBranch:
+		return "ToConditionalFalseBranch"
 	case _ReduceAtomExprToAccessibleExpr:
 		return "AtomExprToAccessibleExpr"
 	case _ReduceDirectAccessExprToAccessibleExpr:
@@ -421,6 +492,8 @@ func (i _ReduceType) String() string {
 		return "PreviousResultExprToAtomExpr"
 	case _ReduceGroupedExprToAtomExpr:
 		return "GroupedExprToAtomExpr"
+	case _ReduceDynamicCastExprToAtomExpr:
+		return "DynamicCastExprToAtomExpr"
 	case _ReduceTrueToLiteralExpr:
 		return "TrueToLiteralExpr"
 	case _ReduceFalseToLiteralExpr:
@@ -439,6 +512,8 @@ func (i _ReduceType) String() string {
 		return "ToPreviousResultExpr"
 	case _ReduceToGroupedExpr:
 		return "ToGroupedExpr"
+	case _ReduceToDynamicCastExpr:
+		return "ToDynamicCastExpr"
 	case _ReduceToDirectAccessExpr:
 		return "ToDirectAccessExpr"
 	case _ReduceToIndirectAccessExpr:
@@ -482,6 +557,12 @@ const (
 	_State11 = _StateId(11)
 	_State12 = _StateId(12)
 	_State13 = _StateId(13)
+	_State14 = _StateId(14)
+	_State15 = _StateId(15)
+	_State16 = _StateId(16)
+	_State17 = _StateId(17)
+	_State18 = _StateId(18)
+	_State19 = _StateId(19)
 )
 
 type Symbol struct {
@@ -512,7 +593,7 @@ func NewSymbol(token parseutil.Token[SymbolId]) (*Symbol, error) {
 				token.Id())
 		}
 		symbol.Generic_ = val
-	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken:
+	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken, QuestionToken, ColonToken:
 		val, ok := token.(*TokenValue)
 		if !ok {
 			return nil, parseutil.NewLocationError(
@@ -538,12 +619,12 @@ func (s *Symbol) Id() SymbolId {
 func (s *Symbol) StartEnd() parseutil.StartEndPos {
 	type locator interface{ StartEnd() parseutil.StartEndPos }
 	switch s.SymbolId_ {
-	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken:
+	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken, QuestionToken, ColonToken:
 		loc, ok := interface{}(s.Token).(locator)
 		if ok {
 			return loc.StartEnd()
 		}
-	case ExpressionType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
+	case ExpressionType, ConditionalExprType, ConditionalTrueBranchType, ConditionalFalseBranchType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DynamicCastExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
 		loc, ok := interface{}(s.Value).(locator)
 		if ok {
 			return loc.StartEnd()
@@ -560,12 +641,12 @@ func (s *Symbol) StartEnd() parseutil.StartEndPos {
 func (s *Symbol) Loc() parseutil.Location {
 	type locator interface{ Loc() parseutil.Location }
 	switch s.SymbolId_ {
-	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken:
+	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken, QuestionToken, ColonToken:
 		loc, ok := interface{}(s.Token).(locator)
 		if ok {
 			return loc.Loc()
 		}
-	case ExpressionType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
+	case ExpressionType, ConditionalExprType, ConditionalTrueBranchType, ConditionalFalseBranchType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DynamicCastExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
 		loc, ok := interface{}(s.Value).(locator)
 		if ok {
 			return loc.Loc()
@@ -582,12 +663,12 @@ func (s *Symbol) Loc() parseutil.Location {
 func (s *Symbol) End() parseutil.Location {
 	type locator interface{ End() parseutil.Location }
 	switch s.SymbolId_ {
-	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken:
+	case IntegerLiteralToken, FloatLiteralToken, RuneLiteralToken, StringLiteralToken, TrueToken, FalseToken, DynamicCastToken, IdentifierToken, DollarIntegerToken, DotToken, CommaToken, ArrowToken, LparenToken, RparenToken, LbracketToken, RbracketToken, QuestionToken, ColonToken:
 		loc, ok := interface{}(s.Token).(locator)
 		if ok {
 			return loc.End()
 		}
-	case ExpressionType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
+	case ExpressionType, ConditionalExprType, ConditionalTrueBranchType, ConditionalFalseBranchType, AccessibleExprType, AtomExprType, LiteralExprType, NamedExprType, PreviousResultExprType, GroupedExprType, DynamicCastExprType, DirectAccessExprType, IndirectAccessExprType, IndexExprType, CallExprType:
 		loc, ok := interface{}(s.Value).(locator)
 		if ok {
 			return loc.End()
@@ -680,70 +761,99 @@ func (act *_Action) ReduceSymbol(
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = ExpressionType
-		//line grammar.lr:10:4
+		//line grammar.lr:11:4
 		symbol.Value = args[0].Value
 		err = nil
+	case _ReduceConditionalExprToExpression:
+		args := stack[len(stack)-1:]
+		stack = stack[:len(stack)-1]
+		symbol.SymbolId_ = ExpressionType
+		//line grammar.lr:12:4
+		symbol.Value = args[0].Value
+		err = nil
+	case _ReduceToConditionalExpr:
+		args := stack[len(stack)-2:]
+		stack = stack[:len(stack)-2]
+		symbol.SymbolId_ = ConditionalExprType
+		symbol.Value, err = reducer.ToConditionalExpr(args[0].Value, args[1].Value)
+	case _ReduceToConditionalTrueBranch:
+		args := stack[len(stack)-2:]
+		stack = stack[:len(stack)-2]
+		symbol.SymbolId_ = ConditionalTrueBranchType
+		symbol.Value, err = reducer.ToConditionalTrueBranch(args[0].Value, args[1].Token)
+	case _ReduceToConditionalFalseBranch:
+		args := stack[len(stack)-3:]
+		stack = stack[:len(stack)-3]
+		symbol.SymbolId_ = ConditionalFalseBranchType
+		symbol.Value, err = reducer.ToConditionalFalseBranch(args[0].Value, args[1].Value, args[2].Token)
 	case _ReduceAtomExprToAccessibleExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AccessibleExprType
-		//line grammar.lr:13:4
+		//line grammar.lr:24:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceDirectAccessExprToAccessibleExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AccessibleExprType
-		//line grammar.lr:14:4
+		//line grammar.lr:25:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceIndirectAccessExprToAccessibleExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AccessibleExprType
-		//line grammar.lr:15:4
+		//line grammar.lr:26:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceIndexExprToAccessibleExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AccessibleExprType
-		//line grammar.lr:16:4
+		//line grammar.lr:27:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceCallExprToAccessibleExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AccessibleExprType
-		//line grammar.lr:17:4
+		//line grammar.lr:28:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceLiteralExprToAtomExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AtomExprType
-		//line grammar.lr:20:4
+		//line grammar.lr:31:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceNamedExprToAtomExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AtomExprType
-		//line grammar.lr:21:4
+		//line grammar.lr:32:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReducePreviousResultExprToAtomExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AtomExprType
-		//line grammar.lr:22:4
+		//line grammar.lr:33:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceGroupedExprToAtomExpr:
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = AtomExprType
-		//line grammar.lr:23:4
+		//line grammar.lr:34:4
+		symbol.Value = args[0].Value
+		err = nil
+	case _ReduceDynamicCastExprToAtomExpr:
+		args := stack[len(stack)-1:]
+		stack = stack[:len(stack)-1]
+		symbol.SymbolId_ = AtomExprType
+		//line grammar.lr:35:4
 		symbol.Value = args[0].Value
 		err = nil
 	case _ReduceTrueToLiteralExpr:
@@ -791,6 +901,11 @@ func (act *_Action) ReduceSymbol(
 		stack = stack[:len(stack)-3]
 		symbol.SymbolId_ = GroupedExprType
 		symbol.Value, err = reducer.ToGroupedExpr(args[0].Token, args[1].Value, args[2].Token)
+	case _ReduceToDynamicCastExpr:
+		args := stack[len(stack)-4:]
+		stack = stack[:len(stack)-4]
+		symbol.SymbolId_ = DynamicCastExprType
+		symbol.Value, err = reducer.ToDynamicCastExpr(args[0].Token, args[1].Token, args[2].Value, args[3].Token)
 	case _ReduceToDirectAccessExpr:
 		args := stack[len(stack)-3:]
 		stack = stack[:len(stack)-3]
@@ -823,7 +938,7 @@ func (act *_Action) ReduceSymbol(
 		args := stack[len(stack)-1:]
 		stack = stack[:len(stack)-1]
 		symbol.SymbolId_ = ArgumentsType
-		//line grammar.lr:50:4
+		//line grammar.lr:66:4
 		symbol.Values = args[0].Values
 		err = nil
 	case _ReduceNewToNonEmptyArguments:
@@ -864,12 +979,18 @@ func (_ActionTableType) Get(
 	switch stateId {
 	case _State1:
 		switch symbolId {
-		case LparenToken:
+		case DynamicCastToken:
 			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
 		case ExpressionType:
 			return _Action{_ShiftAction, _State2, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
 		case AccessibleExprType:
-			return _Action{_ShiftAction, _State4, 0}, true
+			return _Action{_ShiftAction, _State5, 0}, true
 		case IntegerLiteralToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
 		case FloatLiteralToken:
@@ -886,6 +1007,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
 		case DollarIntegerToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
 		case AtomExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
 		case LiteralExprType:
@@ -896,6 +1019,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
 		case GroupedExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
 		case DirectAccessExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
 		case IndirectAccessExprType:
@@ -913,11 +1038,22 @@ func (_ActionTableType) Get(
 	case _State3:
 		switch symbolId {
 		case LparenToken:
+			return _Action{_ShiftAction, _State8, 0}, true
+		}
+	case _State4:
+		switch symbolId {
+		case DynamicCastToken:
 			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
 		case ExpressionType:
-			return _Action{_ShiftAction, _State5, 0}, true
+			return _Action{_ShiftAction, _State9, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
 		case AccessibleExprType:
-			return _Action{_ShiftAction, _State4, 0}, true
+			return _Action{_ShiftAction, _State5, 0}, true
 		case IntegerLiteralToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
 		case FloatLiteralToken:
@@ -934,6 +1070,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
 		case DollarIntegerToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
 		case AtomExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
 		case LiteralExprType:
@@ -944,6 +1082,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
 		case GroupedExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
 		case DirectAccessExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
 		case IndirectAccessExprType:
@@ -953,43 +1093,210 @@ func (_ActionTableType) Get(
 		case CallExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceCallExprToAccessibleExpr}, true
 		}
-	case _State4:
+	case _State5:
 		switch symbolId {
 		case DotToken:
-			return _Action{_ShiftAction, _State7, 0}, true
+			return _Action{_ShiftAction, _State11, 0}, true
 		case ArrowToken:
-			return _Action{_ShiftAction, _State6, 0}, true
+			return _Action{_ShiftAction, _State10, 0}, true
 		case LparenToken:
-			return _Action{_ShiftAction, _State9, 0}, true
+			return _Action{_ShiftAction, _State13, 0}, true
 		case LbracketToken:
-			return _Action{_ShiftAction, _State8, 0}, true
+			return _Action{_ShiftAction, _State12, 0}, true
+		case QuestionToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToConditionalTrueBranch}, true
 
 		default:
 			return _Action{_ReduceAction, 0, _ReduceAccessibleExprToExpression}, true
 		}
-	case _State5:
+	case _State6:
+		switch symbolId {
+		case DynamicCastToken:
+			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
+		case AccessibleExprType:
+			return _Action{_ShiftAction, _State5, 0}, true
+		case IntegerLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
+		case FloatLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFloatLiteralToLiteralExpr}, true
+		case RuneLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceRuneLiteralToLiteralExpr}, true
+		case StringLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceStringLiteralToLiteralExpr}, true
+		case TrueToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceTrueToLiteralExpr}, true
+		case FalseToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFalseToLiteralExpr}, true
+		case IdentifierToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
+		case DollarIntegerToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ExpressionType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToConditionalExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
+		case AtomExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
+		case LiteralExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceLiteralExprToAtomExpr}, true
+		case NamedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceNamedExprToAtomExpr}, true
+		case PreviousResultExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
+		case GroupedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
+		case DirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
+		case IndirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndirectAccessExprToAccessibleExpr}, true
+		case IndexExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndexExprToAccessibleExpr}, true
+		case CallExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceCallExprToAccessibleExpr}, true
+		}
+	case _State7:
+		switch symbolId {
+		case DynamicCastToken:
+			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
+		case ExpressionType:
+			return _Action{_ShiftAction, _State14, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
+		case AccessibleExprType:
+			return _Action{_ShiftAction, _State5, 0}, true
+		case IntegerLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
+		case FloatLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFloatLiteralToLiteralExpr}, true
+		case RuneLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceRuneLiteralToLiteralExpr}, true
+		case StringLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceStringLiteralToLiteralExpr}, true
+		case TrueToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceTrueToLiteralExpr}, true
+		case FalseToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFalseToLiteralExpr}, true
+		case IdentifierToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
+		case DollarIntegerToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
+		case AtomExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
+		case LiteralExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceLiteralExprToAtomExpr}, true
+		case NamedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceNamedExprToAtomExpr}, true
+		case PreviousResultExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
+		case GroupedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
+		case DirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
+		case IndirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndirectAccessExprToAccessibleExpr}, true
+		case IndexExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndexExprToAccessibleExpr}, true
+		case CallExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceCallExprToAccessibleExpr}, true
+		}
+	case _State8:
+		switch symbolId {
+		case DynamicCastToken:
+			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
+		case ExpressionType:
+			return _Action{_ShiftAction, _State15, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
+		case AccessibleExprType:
+			return _Action{_ShiftAction, _State5, 0}, true
+		case IntegerLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
+		case FloatLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFloatLiteralToLiteralExpr}, true
+		case RuneLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceRuneLiteralToLiteralExpr}, true
+		case StringLiteralToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceStringLiteralToLiteralExpr}, true
+		case TrueToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceTrueToLiteralExpr}, true
+		case FalseToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceFalseToLiteralExpr}, true
+		case IdentifierToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
+		case DollarIntegerToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
+		case AtomExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
+		case LiteralExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceLiteralExprToAtomExpr}, true
+		case NamedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceNamedExprToAtomExpr}, true
+		case PreviousResultExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
+		case GroupedExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
+		case DirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
+		case IndirectAccessExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndirectAccessExprToAccessibleExpr}, true
+		case IndexExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceIndexExprToAccessibleExpr}, true
+		case CallExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceCallExprToAccessibleExpr}, true
+		}
+	case _State9:
 		switch symbolId {
 		case RparenToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToGroupedExpr}, true
 		}
-	case _State6:
+	case _State10:
 		switch symbolId {
 		case IdentifierToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToIndirectAccessExpr}, true
 		}
-	case _State7:
+	case _State11:
 		switch symbolId {
 		case IdentifierToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToDirectAccessExpr}, true
 		}
-	case _State8:
+	case _State12:
 		switch symbolId {
-		case LparenToken:
+		case DynamicCastToken:
 			return _Action{_ShiftAction, _State3, 0}, true
+		case LparenToken:
+			return _Action{_ShiftAction, _State4, 0}, true
 		case ExpressionType:
-			return _Action{_ShiftAction, _State10, 0}, true
+			return _Action{_ShiftAction, _State16, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
 		case AccessibleExprType:
-			return _Action{_ShiftAction, _State4, 0}, true
+			return _Action{_ShiftAction, _State5, 0}, true
 		case IntegerLiteralToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
 		case FloatLiteralToken:
@@ -1006,6 +1313,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToNamedExpr}, true
 		case DollarIntegerToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
 		case AtomExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
 		case LiteralExprType:
@@ -1016,6 +1325,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
 		case GroupedExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
 		case DirectAccessExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
 		case IndirectAccessExprType:
@@ -1025,16 +1336,22 @@ func (_ActionTableType) Get(
 		case CallExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceCallExprToAccessibleExpr}, true
 		}
-	case _State9:
+	case _State13:
 		switch symbolId {
-		case LparenToken:
+		case DynamicCastToken:
 			return _Action{_ShiftAction, _State3, 0}, true
-		case AccessibleExprType:
+		case LparenToken:
 			return _Action{_ShiftAction, _State4, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
+		case AccessibleExprType:
+			return _Action{_ShiftAction, _State5, 0}, true
 		case ArgumentsType:
-			return _Action{_ShiftAction, _State11, 0}, true
+			return _Action{_ShiftAction, _State17, 0}, true
 		case NonEmptyArgumentsType:
-			return _Action{_ShiftAction, _State12, 0}, true
+			return _Action{_ShiftAction, _State18, 0}, true
 		case IntegerLiteralToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
 		case FloatLiteralToken:
@@ -1053,6 +1370,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
 		case ExpressionType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceNewToNonEmptyArguments}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
 		case AtomExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
 		case LiteralExprType:
@@ -1063,6 +1382,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
 		case GroupedExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
 		case DirectAccessExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
 		case IndirectAccessExprType:
@@ -1075,30 +1396,46 @@ func (_ActionTableType) Get(
 		default:
 			return _Action{_ReduceAction, 0, _ReduceEmptyListToArguments}, true
 		}
-	case _State10:
+	case _State14:
+		switch symbolId {
+		case ColonToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToConditionalFalseBranch}, true
+		}
+	case _State15:
+		switch symbolId {
+		case RparenToken:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceToDynamicCastExpr}, true
+		}
+	case _State16:
 		switch symbolId {
 		case RbracketToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToIndexExpr}, true
 		}
-	case _State11:
+	case _State17:
 		switch symbolId {
 		case RparenToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToCallExpr}, true
 		}
-	case _State12:
+	case _State18:
 		switch symbolId {
 		case CommaToken:
-			return _Action{_ShiftAction, _State13, 0}, true
+			return _Action{_ShiftAction, _State19, 0}, true
 
 		default:
 			return _Action{_ReduceAction, 0, _ReduceNonEmptyArgumentsToArguments}, true
 		}
-	case _State13:
+	case _State19:
 		switch symbolId {
-		case LparenToken:
+		case DynamicCastToken:
 			return _Action{_ShiftAction, _State3, 0}, true
-		case AccessibleExprType:
+		case LparenToken:
 			return _Action{_ShiftAction, _State4, 0}, true
+		case ConditionalTrueBranchType:
+			return _Action{_ShiftAction, _State7, 0}, true
+		case ConditionalFalseBranchType:
+			return _Action{_ShiftAction, _State6, 0}, true
+		case AccessibleExprType:
+			return _Action{_ShiftAction, _State5, 0}, true
 		case IntegerLiteralToken:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceIntegerLiteralToLiteralExpr}, true
 		case FloatLiteralToken:
@@ -1117,6 +1454,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReduceToPreviousResultExpr}, true
 		case ExpressionType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAppendToNonEmptyArguments}, true
+		case ConditionalExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceConditionalExprToExpression}, true
 		case AtomExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceAtomExprToAccessibleExpr}, true
 		case LiteralExprType:
@@ -1127,6 +1466,8 @@ func (_ActionTableType) Get(
 			return _Action{_ShiftAndReduceAction, 0, _ReducePreviousResultExprToAtomExpr}, true
 		case GroupedExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceGroupedExprToAtomExpr}, true
+		case DynamicCastExprType:
+			return _Action{_ShiftAndReduceAction, 0, _ReduceDynamicCastExprToAtomExpr}, true
 		case DirectAccessExprType:
 			return _Action{_ShiftAndReduceAction, 0, _ReduceDirectAccessExprToAccessibleExpr}, true
 		case IndirectAccessExprType:
@@ -1162,19 +1503,24 @@ Parser Debug States:
       FALSE -> [literal_expr]
       IDENTIFIER -> [named_expr]
       DOLLAR_INTEGER -> [previous_result_expr]
+      conditional_expr -> [expression]
       atom_expr -> [accessible_expr]
       literal_expr -> [atom_expr]
       named_expr -> [atom_expr]
       previous_result_expr -> [atom_expr]
       grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
       direct_access_expr -> [accessible_expr]
       indirect_access_expr -> [accessible_expr]
       index_expr -> [accessible_expr]
       call_expr -> [accessible_expr]
     Goto:
-      LPAREN -> State 3
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
       expression -> State 2
-      accessible_expr -> State 4
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
 
   State 2:
     Kernel Items:
@@ -1187,6 +1533,16 @@ Parser Debug States:
       (nil)
 
   State 3:
+    Kernel Items:
+      dynamic_cast_expr: DYNAMIC_CAST.LPAREN expression RPAREN
+    Reduce:
+      (nil)
+    ShiftAndReduce:
+      (nil)
+    Goto:
+      LPAREN -> State 8
+
+  State 4:
     Kernel Items:
       grouped_expr: LPAREN.expression RPAREN
     Reduce:
@@ -1200,23 +1556,29 @@ Parser Debug States:
       FALSE -> [literal_expr]
       IDENTIFIER -> [named_expr]
       DOLLAR_INTEGER -> [previous_result_expr]
+      conditional_expr -> [expression]
       atom_expr -> [accessible_expr]
       literal_expr -> [atom_expr]
       named_expr -> [atom_expr]
       previous_result_expr -> [atom_expr]
       grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
       direct_access_expr -> [accessible_expr]
       indirect_access_expr -> [accessible_expr]
       index_expr -> [accessible_expr]
       call_expr -> [accessible_expr]
     Goto:
-      LPAREN -> State 3
-      expression -> State 5
-      accessible_expr -> State 4
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      expression -> State 9
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
 
-  State 4:
+  State 5:
     Kernel Items:
       expression: accessible_expr., *
+      conditional_true_branch: accessible_expr.QUESTION
       direct_access_expr: accessible_expr.DOT IDENTIFIER
       indirect_access_expr: accessible_expr.ARROW IDENTIFIER
       index_expr: accessible_expr.LBRACKET expression RBRACKET
@@ -1224,14 +1586,113 @@ Parser Debug States:
     Reduce:
       * -> [expression]
     ShiftAndReduce:
+      QUESTION -> [conditional_true_branch]
+    Goto:
+      DOT -> State 11
+      ARROW -> State 10
+      LPAREN -> State 13
+      LBRACKET -> State 12
+
+  State 6:
+    Kernel Items:
+      conditional_expr: conditional_false_branch.expression
+    Reduce:
+      (nil)
+    ShiftAndReduce:
+      INTEGER_LITERAL -> [literal_expr]
+      FLOAT_LITERAL -> [literal_expr]
+      RUNE_LITERAL -> [literal_expr]
+      STRING_LITERAL -> [literal_expr]
+      TRUE -> [literal_expr]
+      FALSE -> [literal_expr]
+      IDENTIFIER -> [named_expr]
+      DOLLAR_INTEGER -> [previous_result_expr]
+      expression -> [conditional_expr]
+      conditional_expr -> [expression]
+      atom_expr -> [accessible_expr]
+      literal_expr -> [atom_expr]
+      named_expr -> [atom_expr]
+      previous_result_expr -> [atom_expr]
+      grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
+      direct_access_expr -> [accessible_expr]
+      indirect_access_expr -> [accessible_expr]
+      index_expr -> [accessible_expr]
+      call_expr -> [accessible_expr]
+    Goto:
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
+
+  State 7:
+    Kernel Items:
+      conditional_false_branch: conditional_true_branch.expression COLON
+    Reduce:
+      (nil)
+    ShiftAndReduce:
+      INTEGER_LITERAL -> [literal_expr]
+      FLOAT_LITERAL -> [literal_expr]
+      RUNE_LITERAL -> [literal_expr]
+      STRING_LITERAL -> [literal_expr]
+      TRUE -> [literal_expr]
+      FALSE -> [literal_expr]
+      IDENTIFIER -> [named_expr]
+      DOLLAR_INTEGER -> [previous_result_expr]
+      conditional_expr -> [expression]
+      atom_expr -> [accessible_expr]
+      literal_expr -> [atom_expr]
+      named_expr -> [atom_expr]
+      previous_result_expr -> [atom_expr]
+      grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
+      direct_access_expr -> [accessible_expr]
+      indirect_access_expr -> [accessible_expr]
+      index_expr -> [accessible_expr]
+      call_expr -> [accessible_expr]
+    Goto:
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      expression -> State 14
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
+
+  State 8:
+    Kernel Items:
+      dynamic_cast_expr: DYNAMIC_CAST LPAREN.expression RPAREN
+    Reduce:
       (nil)
+    ShiftAndReduce:
+      INTEGER_LITERAL -> [literal_expr]
+      FLOAT_LITERAL -> [literal_expr]
+      RUNE_LITERAL -> [literal_expr]
+      STRING_LITERAL -> [literal_expr]
+      TRUE -> [literal_expr]
+      FALSE -> [literal_expr]
+      IDENTIFIER -> [named_expr]
+      DOLLAR_INTEGER -> [previous_result_expr]
+      conditional_expr -> [expression]
+      atom_expr -> [accessible_expr]
+      literal_expr -> [atom_expr]
+      named_expr -> [atom_expr]
+      previous_result_expr -> [atom_expr]
+      grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
+      direct_access_expr -> [accessible_expr]
+      indirect_access_expr -> [accessible_expr]
+      index_expr -> [accessible_expr]
+      call_expr -> [accessible_expr]
     Goto:
-      DOT -> State 7
-      ARROW -> State 6
-      LPAREN -> State 9
-      LBRACKET -> State 8
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      expression -> State 15
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
 
-  State 5:
+  State 9:
     Kernel Items:
       grouped_expr: LPAREN expression.RPAREN
     Reduce:
@@ -1241,7 +1702,7 @@ Parser Debug States:
     Goto:
       (nil)
 
-  State 6:
+  State 10:
     Kernel Items:
       indirect_access_expr: accessible_expr ARROW.IDENTIFIER
     Reduce:
@@ -1251,7 +1712,7 @@ Parser Debug States:
     Goto:
       (nil)
 
-  State 7:
+  State 11:
     Kernel Items:
       direct_access_expr: accessible_expr DOT.IDENTIFIER
     Reduce:
@@ -1261,7 +1722,7 @@ Parser Debug States:
     Goto:
       (nil)
 
-  State 8:
+  State 12:
     Kernel Items:
       index_expr: accessible_expr LBRACKET.expression RBRACKET
     Reduce:
@@ -1275,21 +1736,26 @@ Parser Debug States:
       FALSE -> [literal_expr]
       IDENTIFIER -> [named_expr]
       DOLLAR_INTEGER -> [previous_result_expr]
+      conditional_expr -> [expression]
       atom_expr -> [accessible_expr]
       literal_expr -> [atom_expr]
       named_expr -> [atom_expr]
       previous_result_expr -> [atom_expr]
       grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
       direct_access_expr -> [accessible_expr]
       indirect_access_expr -> [accessible_expr]
       index_expr -> [accessible_expr]
       call_expr -> [accessible_expr]
     Goto:
-      LPAREN -> State 3
-      expression -> State 10
-      accessible_expr -> State 4
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      expression -> State 16
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
 
-  State 9:
+  State 13:
     Kernel Items:
       call_expr: accessible_expr LPAREN.arguments RPAREN
     Reduce:
@@ -1304,22 +1770,47 @@ Parser Debug States:
       IDENTIFIER -> [named_expr]
       DOLLAR_INTEGER -> [previous_result_expr]
       expression -> [non_empty_arguments]
+      conditional_expr -> [expression]
       atom_expr -> [accessible_expr]
       literal_expr -> [atom_expr]
       named_expr -> [atom_expr]
       previous_result_expr -> [atom_expr]
       grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
       direct_access_expr -> [accessible_expr]
       indirect_access_expr -> [accessible_expr]
       index_expr -> [accessible_expr]
       call_expr -> [accessible_expr]
     Goto:
-      LPAREN -> State 3
-      accessible_expr -> State 4
-      arguments -> State 11
-      non_empty_arguments -> State 12
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
+      arguments -> State 17
+      non_empty_arguments -> State 18
+
+  State 14:
+    Kernel Items:
+      conditional_false_branch: conditional_true_branch expression.COLON
+    Reduce:
+      (nil)
+    ShiftAndReduce:
+      COLON -> [conditional_false_branch]
+    Goto:
+      (nil)
 
-  State 10:
+  State 15:
+    Kernel Items:
+      dynamic_cast_expr: DYNAMIC_CAST LPAREN expression.RPAREN
+    Reduce:
+      (nil)
+    ShiftAndReduce:
+      RPAREN -> [dynamic_cast_expr]
+    Goto:
+      (nil)
+
+  State 16:
     Kernel Items:
       index_expr: accessible_expr LBRACKET expression.RBRACKET
     Reduce:
@@ -1329,7 +1820,7 @@ Parser Debug States:
     Goto:
       (nil)
 
-  State 11:
+  State 17:
     Kernel Items:
       call_expr: accessible_expr LPAREN arguments.RPAREN
     Reduce:
@@ -1339,7 +1830,7 @@ Parser Debug States:
     Goto:
       (nil)
 
-  State 12:
+  State 18:
     Kernel Items:
       arguments: non_empty_arguments.COMMA
       arguments: non_empty_arguments., *
@@ -1349,9 +1840,9 @@ Parser Debug States:
     ShiftAndReduce:
       (nil)
     Goto:
-      COMMA -> State 13
+      COMMA -> State 19
 
-  State 13:
+  State 19:
     Kernel Items:
       arguments: non_empty_arguments COMMA., *
       non_empty_arguments: non_empty_arguments COMMA.expression
@@ -1367,26 +1858,31 @@ Parser Debug States:
       IDENTIFIER -> [named_expr]
       DOLLAR_INTEGER -> [previous_result_expr]
       expression -> [non_empty_arguments]
+      conditional_expr -> [expression]
       atom_expr -> [accessible_expr]
       literal_expr -> [atom_expr]
       named_expr -> [atom_expr]
       previous_result_expr -> [atom_expr]
       grouped_expr -> [atom_expr]
+      dynamic_cast_expr -> [atom_expr]
       direct_access_expr -> [accessible_expr]
       indirect_access_expr -> [accessible_expr]
       index_expr -> [accessible_expr]
       call_expr -> [accessible_expr]
     Goto:
-      LPAREN -> State 3
-      accessible_expr -> State 4
-
-Number of states: 13
-Number of shift actions: 20
+      DYNAMIC_CAST -> State 3
+      LPAREN -> State 4
+      conditional_true_branch -> State 7
+      conditional_false_branch -> State 6
+      accessible_expr -> State 5
+
+Number of states: 19
+Number of shift actions: 53
 Number of reduce actions: 5
-Number of shift-and-reduce actions: 92
+Number of shift-and-reduce actions: 163
 Number of shift/reduce conflicts: 0
 Number of reduce/reduce conflicts: 0
-Number of unoptimized states: 130
-Number of unoptimized shift actions: 325
-Number of unoptimized reduce actions: 478
+Number of unoptimized states: 205
+Number of unoptimized shift actions: 772
+Number of unoptimized reduce actions: 794
 */
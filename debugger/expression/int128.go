@@ -0,0 +1,95 @@
+package expression
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Decoded __int128 value (two's complement).
+//
+// NOTE: 128-bit integers are only supported for reading / printing.  SYS V
+// ABI parameter passing of __int128 is not supported.
+type Int128 struct {
+	High uint64
+	Low  uint64
+}
+
+func decodeInt128(data []byte) (Int128, int) {
+	if len(data) < 16 {
+		return Int128{}, 0
+	}
+
+	return Int128{
+		High: binary.LittleEndian.Uint64(data[8:16]),
+		Low:  binary.LittleEndian.Uint64(data[:8]),
+	}, 16
+}
+
+func (i Int128) IsNegative() bool {
+	return int64(i.High) < 0
+}
+
+// Returns the value as int64.  The bool is false if the value does not fit.
+func (i Int128) Int64() (int64, bool) {
+	if i.IsNegative() {
+		return int64(i.Low), i.High == math.MaxUint64 && int64(i.Low) < 0
+	}
+
+	return int64(i.Low), i.High == 0 && i.Low <= math.MaxInt64
+}
+
+func (i Int128) BigInt() *big.Int {
+	result := Uint128(i).BigInt()
+	if i.IsNegative() {
+		result.Sub(result, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+
+	return result
+}
+
+// Formatted as a decimal number.
+func (i Int128) String() string {
+	return i.BigInt().String()
+}
+
+// Supports the same verbs as big.Int (e.g., %d, %x, %o, %b).
+func (i Int128) Format(state fmt.State, verb rune) {
+	i.BigInt().Format(state, verb)
+}
+
+// Decoded unsigned __int128 value.
+//
+// NOTE: 128-bit integers are only supported for reading / printing.  SYS V
+// ABI parameter passing of unsigned __int128 is not supported.
+type Uint128 struct {
+	High uint64
+	Low  uint64
+}
+
+func decodeUint128(data []byte) (Uint128, int) {
+	value, n := decodeInt128(data)
+	return Uint128(value), n
+}
+
+// Returns the value as uint64.  The bool is false if the value does not fit.
+func (u Uint128) Uint64() (uint64, bool) {
+	return u.Low, u.High == 0
+}
+
+func (u Uint128) BigInt() *big.Int {
+	result := new(big.Int).SetUint64(u.High)
+	result.Lsh(result, 64)
+	return result.Or(result, new(big.Int).SetUint64(u.Low))
+}
+
+// Formatted as a decimal number.
+func (u Uint128) String() string {
+	return u.BigInt().String()
+}
+
+// Supports the same verbs as big.Int (e.g., %d, %x, %o, %b).
+func (u Uint128) Format(state fmt.State, verb rune) {
+	u.BigInt().Format(state, verb)
+}
@@ -16,8 +16,9 @@ type Lexer = parseutil.Lexer[Token]
 
 var (
 	keywords = map[string]SymbolId{
-		"true":  TrueToken,
-		"false": FalseToken,
+		"true":         TrueToken,
+		"false":        FalseToken,
+		"dynamic_cast": DynamicCastToken,
 	}
 )
 
@@ -78,6 +79,10 @@ func (lexer *lexerImpl) peekNextToken() (SymbolId, string, error) {
 
 	case ',':
 		return CommaToken, ",", nil
+	case '?':
+		return QuestionToken, "?", nil
+	case ':':
+		return ColonToken, ":", nil
 	case '\'':
 		return RuneLiteralToken, "", nil
 	case '"':
@@ -227,6 +232,39 @@ func (lexer *lexerImpl) lexIdentifierOrKeyword() (Token, error) {
 		panic("Should never hapapen")
 	}
 
+	// NOTE: qualified names (e.g., Foo::create) are lexed as a single
+	// identifier.
+	for {
+		peeked, _ := lexer.Peek(3)
+		if len(peeked) < 3 || string(peeked[:2]) != "::" {
+			break
+		}
+
+		char := peeked[2]
+		if !('a' <= char && char <= 'z') &&
+			!('A' <= char && char <= 'Z') &&
+			char != '_' {
+			break
+		}
+
+		_, err := lexer.Discard(2)
+		if err != nil {
+			panic("should never happen")
+		}
+
+		component, err := parseutil.MaybeTokenizeIdentifier(
+			lexer.BufferedByteLocationReader,
+			64,
+			lexer.InternPool,
+			IdentifierToken)
+		if err != nil {
+			return nil, err
+		}
+
+		token.Value = lexer.Intern(token.Value + "::" + component.Value)
+		token.EndPos = component.EndPos
+	}
+
 	kwSymbolId, ok := keywords[token.Value]
 	if ok {
 		token.SymbolId = kwSymbolId
@@ -0,0 +1,69 @@
+package expression
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/dwarf"
+)
+
+// memberLocationContext is a minimal dwarf.ExpressionContext for evaluating
+// DW_AT_data_member_location expressions, which only operate on the object
+// address and memory.
+type memberLocationContext struct {
+	*memory.VirtualMemory
+}
+
+func (memberLocationContext) ByteOrder() binary.ByteOrder {
+	return binary.LittleEndian
+}
+
+func (memberLocationContext) LoadBias() uint64 {
+	return 0
+}
+
+func (memberLocationContext) BaseFrameFunctionEntry() *dwarf.DebugInfoEntry {
+	return nil
+}
+
+func (memberLocationContext) ProgramCounter() uint64 {
+	return 0
+}
+
+func (memberLocationContext) RegisterValue(
+	id dwarf.RegisterId,
+) (
+	uint64,
+	error,
+) {
+	return 0, fmt.Errorf(
+		"register %d unavailable for data member location",
+		id)
+}
+
+func (ctx memberLocationContext) ReadMemory(
+	virtualAddress uint64,
+	out []byte,
+) (
+	int,
+	error,
+) {
+	return ctx.Read(VirtualAddress(virtualAddress), out)
+}
+
+func (memberLocationContext) CanonicalFrameAddress() (uint64, error) {
+	return 0, fmt.Errorf("cfa unavailable for data member location")
+}
+
+func (memberLocationContext) EntryRegisterValue(
+	id dwarf.RegisterId,
+) (
+	uint64,
+	error,
+) {
+	return 0, fmt.Errorf(
+		"register %d entry value unavailable for data member location",
+		id)
+}
@@ -6,10 +6,25 @@ import (
 	"strconv"
 
 	"github.com/pattyshack/gt/parseutil"
+
+	. "github.com/pattyshack/bad/debugger/common"
 )
 
 type reducerImpl struct {
 	EvaluationContext
+
+	// The conditional expressions whose branches are being parsed (innermost
+	// last).
+	conditionals []*conditionalFrame
+}
+
+type conditionalFrame struct {
+	// True when the entire conditional expression is within an unselected
+	// branch of an enclosing conditional expression.
+	isSkipped bool
+
+	condition     bool
+	inFalseBranch bool
 }
 
 func newReducer(ctx EvaluationContext) Reducer {
@@ -18,6 +33,34 @@ func newReducer(ctx EvaluationContext) Reducer {
 	}
 }
 
+// True when the reducer is reducing an unselected conditional branch.
+//
+// The unselected branch is only evaluated for type unification.  Operations
+// with side effects (function calls, string literal allocations) are not
+// performed, and evaluation errors are ignored.  In both cases, the value
+// (and its type) is unknown, and is represented by nil.
+func (reducer *reducerImpl) isSkipping() bool {
+	if len(reducer.conditionals) == 0 {
+		return false
+	}
+
+	frame := reducer.conditionals[len(reducer.conditionals)-1]
+	return frame.isSkipped || frame.condition == frame.inFalseBranch
+}
+
+func (reducer *reducerImpl) maybeSkipError(
+	value *TypedData,
+	err error,
+) (
+	*TypedData,
+	error,
+) {
+	if err != nil && reducer.isSkipping() {
+		return nil, nil
+	}
+	return value, err
+}
+
 func (reducer *reducerImpl) TrueToLiteralExpr(
 	true_ *TokenValue,
 ) (
@@ -100,6 +143,10 @@ func (reducer *reducerImpl) StringLiteralToLiteralExpr(
 	*TypedData,
 	error,
 ) {
+	if reducer.isSkipping() {
+		return nil, nil
+	}
+
 	return reducer.DescriptorPool().NewCString(
 		reducer,
 		stringLiteral.Value,
@@ -107,7 +154,8 @@ func (reducer *reducerImpl) StringLiteralToLiteralExpr(
 }
 
 func (reducer *reducerImpl) ToNamedExpr(name *TokenValue) (*TypedData, error) {
-	return reducer.ReadInspectFrameVariableOrFunction(name.Value)
+	return reducer.maybeSkipError(
+		reducer.ReadInspectFrameVariableOrFunction(name.Value))
 }
 
 func (reducer *reducerImpl) ToPreviousResultExpr(
@@ -126,7 +174,7 @@ func (reducer *reducerImpl) ToPreviousResultExpr(
 
 	result, err := reducer.GetEvaluatedResult(int(idx))
 	if err != nil {
-		return nil, err
+		return reducer.maybeSkipError(nil, err)
 	}
 
 	return result.TypedData, nil
@@ -143,7 +191,23 @@ func (reducerImpl) ToGroupedExpr(
 	return expr, nil
 }
 
-func (reducerImpl) ToDirectAccessExpr(
+func (reducer *reducerImpl) ToDynamicCastExpr(
+	dynamicCast *TokenValue,
+	lparen *TokenValue,
+	expr *TypedData,
+	rparen *TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	if expr == nil { // unknown skipped value
+		return nil, nil
+	}
+
+	return reducer.maybeSkipError(expr.DynamicType())
+}
+
+func (reducer *reducerImpl) ToDirectAccessExpr(
 	accessible *TypedData,
 	dot *TokenValue,
 	name *TokenValue,
@@ -151,10 +215,14 @@ func (reducerImpl) ToDirectAccessExpr(
 	*TypedData,
 	error,
 ) {
-	return accessible.FieldOrMethodByName(name.Value)
+	if accessible == nil { // unknown skipped value
+		return nil, nil
+	}
+
+	return reducer.maybeSkipError(accessible.FieldOrMethodByName(name.Value))
 }
 
-func (reducerImpl) ToIndirectAccessExpr(
+func (reducer *reducerImpl) ToIndirectAccessExpr(
 	accessible *TypedData,
 	arrow *TokenValue,
 	name *TokenValue,
@@ -162,15 +230,19 @@ func (reducerImpl) ToIndirectAccessExpr(
 	*TypedData,
 	error,
 ) {
+	if accessible == nil { // unknown skipped value
+		return nil, nil
+	}
+
 	deref, err := accessible.Dereference()
 	if err != nil {
-		return nil, err
+		return reducer.maybeSkipError(nil, err)
 	}
 
-	return deref.FieldOrMethodByName(name.Value)
+	return reducer.maybeSkipError(deref.FieldOrMethodByName(name.Value))
 }
 
-func (reducerImpl) ToIndexExpr(
+func (reducer *reducerImpl) ToIndexExpr(
 	accessible *TypedData,
 	lbracket *TokenValue,
 	idxExpr *TypedData,
@@ -179,9 +251,17 @@ func (reducerImpl) ToIndexExpr(
 	*TypedData,
 	error,
 ) {
-	if idxExpr.Kind != IntKind || idxExpr.ByteSize != 4 {
+	if accessible == nil || idxExpr == nil { // unknown skipped value
+		return nil, nil
+	}
+
+	return reducer.maybeSkipError(index(accessible, idxExpr))
+}
+
+func index(accessible *TypedData, idxExpr *TypedData) (*TypedData, error) {
+	if idxExpr.Kind != IntKind && idxExpr.Kind != UintKind {
 		return nil, fmt.Errorf(
-			"invalid index value type (%s). expected int32",
+			"invalid index value type (%s). expected integer",
 			idxExpr.TypeName())
 	}
 
@@ -190,7 +270,58 @@ func (reducerImpl) ToIndexExpr(
 		return nil, err
 	}
 
-	return accessible.Index(int(value.(int32)))
+	var idx int64
+	switch value := value.(type) {
+	case int8:
+		idx = int64(value)
+	case int16:
+		idx = int64(value)
+	case int32:
+		idx = int64(value)
+	case int64:
+		idx = value
+	case uint8:
+		idx = int64(value)
+	case uint16:
+		idx = int64(value)
+	case uint32:
+		idx = int64(value)
+	case uint64:
+		// NOTE: pointer arithmetic wraps around, but the value is never a valid
+		// array index.
+		if value > math.MaxInt64 && accessible.Kind != PointerKind {
+			return nil, fmt.Errorf(
+				"%w. index %d out of bounds for %s",
+				ErrInvalidInput,
+				value,
+				accessible.TypeName())
+		}
+		idx = int64(value)
+	case Int128:
+		var ok bool
+		idx, ok = value.Int64()
+		if !ok {
+			return nil, fmt.Errorf(
+				"%w. index %s out of bounds for %s",
+				ErrInvalidInput,
+				value,
+				accessible.TypeName())
+		}
+	case Uint128:
+		unsigned, ok := value.Uint64()
+		if !ok || (unsigned > math.MaxInt64 && accessible.Kind != PointerKind) {
+			return nil, fmt.Errorf(
+				"%w. index %s out of bounds for %s",
+				ErrInvalidInput,
+				value,
+				accessible.TypeName())
+		}
+		idx = int64(unsigned)
+	default:
+		panic("should never happen")
+	}
+
+	return accessible.Index(int(idx))
 }
 
 func (reducer *reducerImpl) ToCallExpr(
@@ -202,9 +333,89 @@ func (reducer *reducerImpl) ToCallExpr(
 	*TypedData,
 	error,
 ) {
+	if reducer.isSkipping() {
+		return nil, nil
+	}
+
 	return reducer.InvokeInCurrentThread(accessible, arguments)
 }
 
+// NOTE: the condition is evaluated before the branches are parsed.
+func (reducer *reducerImpl) ToConditionalTrueBranch(
+	condition *TypedData,
+	question *TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	frame := &conditionalFrame{
+		isSkipped: reducer.isSkipping(),
+	}
+
+	if !frame.isSkipped {
+		value, err := isTrue(condition)
+		if err != nil {
+			return nil, err
+		}
+		frame.condition = value
+	}
+
+	reducer.conditionals = append(reducer.conditionals, frame)
+	return condition, nil
+}
+
+func (reducer *reducerImpl) ToConditionalFalseBranch(
+	condition *TypedData,
+	trueBranch *TypedData,
+	colon *TokenValue,
+) (
+	*TypedData,
+	error,
+) {
+	reducer.conditionals[len(reducer.conditionals)-1].inFalseBranch = true
+	return trueBranch, nil
+}
+
+func (reducer *reducerImpl) ToConditionalExpr(
+	trueBranch *TypedData,
+	falseBranch *TypedData,
+) (
+	*TypedData,
+	error,
+) {
+	frame := reducer.conditionals[len(reducer.conditionals)-1]
+	reducer.conditionals = reducer.conditionals[:len(reducer.conditionals)-1]
+
+	if frame.isSkipped {
+		return nil, nil
+	}
+
+	selected := trueBranch
+	unselected := falseBranch
+	if !frame.condition {
+		selected = falseBranch
+		unselected = trueBranch
+	}
+
+	if unselected == nil {
+		return selected, nil
+	}
+
+	unified := unifiedScalarType(
+		selected.DataDescriptor,
+		unselected.DataDescriptor)
+	if unified == nil {
+		return selected, nil
+	}
+
+	bytes, err := selected.EncodeAs(unified)
+	if err != nil {
+		return nil, err
+	}
+
+	return unified.NewImplicitData(selected.FormatPrefix, bytes)
+}
+
 func (reducerImpl) EmptyListToArguments() ([]*TypedData, error) {
 	return nil, nil
 }
@@ -238,3 +449,125 @@ func (reducerImpl) AppendToNonEmptyArguments(
 ) {
 	return append(arguments, expr), nil
 }
+
+// Conditions must evaluate to simple (bool / char / integer / float /
+// pointer) values, which are true when non-zero.
+func isTrue(condition *TypedData) (bool, error) {
+	value, err := condition.DecodeSimpleValue()
+	if err != nil {
+		return false, fmt.Errorf(
+			"%w. invalid condition (%s): %w",
+			ErrInvalidInput,
+			condition.TypeName(),
+			err)
+	}
+
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case int8:
+		return value != 0, nil
+	case int16:
+		return value != 0, nil
+	case int32:
+		return value != 0, nil
+	case int64:
+		return value != 0, nil
+	case Int128:
+		return value.High != 0 || value.Low != 0, nil
+	case uint8:
+		return value != 0, nil
+	case uint16:
+		return value != 0, nil
+	case uint32:
+		return value != 0, nil
+	case uint64:
+		return value != 0, nil
+	case Uint128:
+		return value.High != 0 || value.Low != 0, nil
+	case VirtualAddress:
+		return value != 0, nil
+	case float32:
+		return value != 0, nil // NaN is true
+	case float64:
+		return value != 0, nil // NaN is true
+	default:
+		panic(fmt.Sprintf("unexpected simple value: %#v", value)) // should never happen
+	}
+}
+
+// Returns the type which both scalar branches are converted to (using C's
+// usual arithmetic conversions), or nil if no conversion is necessary (or
+// applicable).
+func unifiedScalarType(
+	selected *DataDescriptor,
+	unselected *DataDescriptor,
+) *DataDescriptor {
+	if selected.Equals(unselected) {
+		return nil
+	}
+
+	isScalar := func(descriptor *DataDescriptor) bool {
+		switch descriptor.Kind {
+		case BoolKind, CharKind, IntKind, UintKind, FloatKind:
+			return true
+		default:
+			return false
+		}
+	}
+
+	if !isScalar(selected) || !isScalar(unselected) {
+		return nil
+	}
+
+	if selected.Kind == FloatKind || unselected.Kind == FloatKind {
+		byteSize := 0
+		for _, descriptor := range []*DataDescriptor{selected, unselected} {
+			if descriptor.Kind == FloatKind && descriptor.ByteSize > byteSize {
+				byteSize = descriptor.ByteSize
+			}
+		}
+
+		// NOTE: long double is not supported.
+		if byteSize != 4 && byteSize != 8 {
+			return nil
+		}
+
+		return &DataDescriptor{
+			Pool:     selected.Pool,
+			Kind:     FloatKind,
+			ByteSize: byteSize,
+		}
+	}
+
+	// Integer promotion: bool, char and integers smaller than int are promoted
+	// to int.
+	promote := func(descriptor *DataDescriptor) (DataKind, int) {
+		if descriptor.ByteSize < 4 ||
+			descriptor.Kind == BoolKind ||
+			descriptor.Kind == CharKind {
+
+			return IntKind, 4
+		}
+		return descriptor.Kind, descriptor.ByteSize
+	}
+
+	kind, byteSize := promote(selected)
+	otherKind, otherByteSize := promote(unselected)
+	if otherByteSize > byteSize {
+		kind = otherKind
+		byteSize = otherByteSize
+	} else if otherByteSize == byteSize && otherKind == UintKind {
+		kind = UintKind
+	}
+
+	if kind == selected.Kind && byteSize == selected.ByteSize {
+		return nil
+	}
+
+	return &DataDescriptor{
+		Pool:     selected.Pool,
+		Kind:     kind,
+		ByteSize: byteSize,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
+	"strings"
 
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/memory"
@@ -25,6 +27,19 @@ type EvaluationContext interface {
 	GetEvaluatedResult(idx int) (*EvaluatedResult, error)
 }
 
+// The reason a variable's value cannot be read.
+type UnavailableReason string
+
+const (
+	Available = UnavailableReason("")
+
+	// The variable has no storage (e.g., the compiler optimized it away).
+	OptimizedOut = UnavailableReason("optimized out")
+
+	// The variable's location list does not cover the current pc.
+	NotAvailableAtCurrentPC = UnavailableReason("not available at current PC")
+)
+
 type TypedData struct {
 	*memory.VirtualMemory
 
@@ -45,6 +60,14 @@ type TypedData struct {
 
 	// NOTE: Only populate by ReadVariable
 	dwarf.Location
+
+	// When set, the data has no readable value.  Address / ImplicitValue are
+	// not applicable.
+	Unavailable UnavailableReason
+}
+
+func (data *TypedData) unavailableError() error {
+	return fmt.Errorf("%s is %s", data.FormatPrefix, data.Unavailable)
 }
 
 func (data *TypedData) Dereference() (*TypedData, error) {
@@ -90,7 +113,11 @@ func (data *TypedData) Index(idx int) (*TypedData, error) {
 	}
 
 	if idx < 0 || data.NumElements <= idx {
-		return nil, fmt.Errorf("%w. index out of bound", ErrInvalidInput)
+		return nil, fmt.Errorf(
+			"%w. index %d out of bounds for %s",
+			ErrInvalidInput,
+			idx,
+			data.TypeName())
 	}
 
 	start := idx * data.Value.ByteSize
@@ -103,7 +130,8 @@ func (data *TypedData) Index(idx int) (*TypedData, error) {
 		DataDescriptor: data.Value,
 		Address:        address,
 		BitOffset:      0,
-		BitSize:        8 * data.ByteSize,
+		BitSize:        8 * data.Value.ByteSize,
+		Unavailable:    data.Unavailable,
 	}, nil
 }
 
@@ -115,18 +143,26 @@ func (data *TypedData) FieldOrMethodByName(name string) (*TypedData, error) {
 			data.Kind)
 	}
 
-	var match *FieldDescriptor
+	result, err := data.fieldOrMethodByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("field/method (%s) not found", name)
+	}
+
+	return result, nil
+}
+
+// This returns nil if the field / method is not found.
+func (data *TypedData) fieldOrMethodByName(name string) (*TypedData, error) {
 	for _, field := range data.Fields {
 		if field.Name == name {
-			match = field
-			break
+			return data.fieldData(field)
 		}
 	}
 
-	if match != nil {
-		return data.fieldData(match)
-	}
-
 	descriptor, addresses, err := data.DataDescriptor.Pool.GetMethod(
 		data.DIE,
 		name)
@@ -134,27 +170,62 @@ func (data *TypedData) FieldOrMethodByName(name string) (*TypedData, error) {
 		return nil, err
 	}
 
-	if descriptor == nil {
-		return nil, fmt.Errorf("field/method (%s) not found", name)
-	}
+	if descriptor != nil {
+		if data.BitOffset != 0 ||
+			data.BitSize%8 != 0 ||
+			data.BitSize/8 != data.ByteSize {
+			panic("should never happen")
+		}
 
-	if data.BitOffset != 0 ||
-		data.BitSize%8 != 0 ||
-		data.BitSize/8 != data.ByteSize {
-		panic("should never happen")
+		return &TypedData{
+			VirtualMemory:  data.VirtualMemory,
+			FormatPrefix:   "." + name,
+			DataDescriptor: descriptor,
+
+			Address:   data.Address,
+			BitOffset: data.BitOffset,
+			BitSize:   data.BitSize,
+
+			FunctionAddresses: addresses,
+		}, nil
 	}
 
-	return &TypedData{
-		VirtualMemory:  data.VirtualMemory,
-		FormatPrefix:   "." + name,
-		DataDescriptor: descriptor,
+	// Search the inherited members.  The base class subobject's address (and
+	// hence the method receiver / this pointer) is adjusted by the base class'
+	// offset within the derived object.
+	var match *TypedData
+	for _, field := range data.Fields {
+		if !field.IsBaseClass {
+			continue
+		}
+
+		baseClass, err := data.fieldData(field)
+		if err != nil { // e.g., unreadable virtual base class pointer
+			return nil, err
+		}
 
-		Address:   data.Address,
-		BitOffset: data.BitOffset,
-		BitSize:   data.BitSize,
+		member, err := baseClass.fieldOrMethodByName(name)
+		if err != nil {
+			return nil, err
+		}
 
-		FunctionAddresses: addresses,
-	}, nil
+		if member == nil {
+			continue
+		}
+
+		// NOTE: members of a shared virtual base class are reachable via
+		// multiple paths, but refer to the same subobject.
+		if match != nil && match.Address != member.Address {
+			return nil, fmt.Errorf(
+				"%w. ambiguous field/method (%s)",
+				ErrInvalidInput,
+				name)
+		}
+
+		match = member
+	}
+
+	return match, nil
 }
 
 func (data *TypedData) fieldData(match *FieldDescriptor) (*TypedData, error) {
@@ -164,6 +235,24 @@ func (data *TypedData) fieldData(match *FieldDescriptor) (*TypedData, error) {
 	}
 
 	address := data.Address + VirtualAddress(match.ByteOffset)
+	if match.LocationExpression != nil && data.Unavailable == Available {
+		if data.ImplicitValue != nil {
+			return nil, fmt.Errorf(
+				"cannot locate field (%s) in implicit value",
+				name)
+		}
+
+		addr, err := dwarf.EvaluateDataMemberLocation(
+			memberLocationContext{data.VirtualMemory},
+			match.LocationExpression,
+			uint64(data.Address))
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate field (%s): %w", name, err)
+		}
+
+		address = VirtualAddress(addr)
+	}
+
 	return &TypedData{
 		VirtualMemory:  data.VirtualMemory,
 		FormatPrefix:   "." + name,
@@ -171,10 +260,15 @@ func (data *TypedData) fieldData(match *FieldDescriptor) (*TypedData, error) {
 		Address:        address,
 		BitOffset:      match.BitOffset,
 		BitSize:        match.BitSize,
+		Unavailable:    data.Unavailable,
 	}, nil
 }
 
 func (data *TypedData) Bytes() ([]byte, error) {
+	if data.Unavailable != Available {
+		return nil, data.unavailableError()
+	}
+
 	if data.ImplicitValue != nil {
 		bytes := make([]byte, data.ByteSize)
 		n, err := binary.Encode(bytes, binary.LittleEndian, data.ImplicitValue)
@@ -207,9 +301,22 @@ func (data *TypedData) Bytes() ([]byte, error) {
 		data.BitSize)
 	materializedData := appender.Finalize()
 
-	// Pad bit-packed fields to the expected size
+	// Pad bit-packed fields to the expected size.  Signed bit-packed fields are
+	// sign extended.
+	padding := byte(0)
+	if data.BitSize > 0 &&
+		data.BitSize < 8*data.ByteSize &&
+		data.isSignedInteger() {
+
+		signBit := data.BitSize - 1
+		if materializedData[signBit/8]&(1<<(signBit%8)) != 0 {
+			materializedData[signBit/8] |= ^byte(0) << (signBit%8 + 1)
+			padding = 0xff
+		}
+	}
+
 	for len(materializedData) < data.ByteSize {
-		materializedData = append(materializedData, 0)
+		materializedData = append(materializedData, padding)
 	}
 
 	return materializedData, nil
@@ -220,10 +327,14 @@ func decodeSimpleValue[T any](data []byte, value T) (interface{}, int, error) {
 	return value, n, err
 }
 
-// This returns a correctly sized golang value for base types, or a
-// VirtualAddress for pointer / member pointer.  This returns error for
-// array / struct / union.
+// This returns a correctly sized golang value for base types (Int128 /
+// Uint128 for 128-bit integers), or a VirtualAddress for pointer / member
+// pointer.  This returns error for array / struct / union.
 func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
+	if data.Unavailable != Available {
+		return nil, data.unavailableError()
+	}
+
 	if data.ImplicitValue != nil {
 		return data.ImplicitValue, nil
 	}
@@ -238,11 +349,23 @@ func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
 		return nil, err
 	}
 
+	return data.DataDescriptor.decodeSimpleBytes(materializedData)
+}
+
+func (descriptor *DataDescriptor) decodeSimpleBytes(
+	materializedData []byte,
+) (
+	interface{},
+	error,
+) {
+	var err error
 	var value interface{}
 	var n int
-	switch data.Kind {
-	case ArrayKind, StructKind, UnionKind, FunctionKind, MethodKind:
-		return nil, fmt.Errorf("cannot decode %s into simple value", data.Kind)
+	switch descriptor.Kind {
+	case ArrayKind, StructKind, UnionKind, FunctionKind, MethodKind, VoidKind:
+		return nil, fmt.Errorf(
+			"cannot decode %s into simple value",
+			descriptor.Kind)
 	case PointerKind, MemberPointerKind:
 		// NOTE: We'll ignore the second pointer in method members
 		value, n, err = decodeSimpleValue(materializedData, VirtualAddress(0))
@@ -251,7 +374,7 @@ func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
 	case CharKind:
 		value, n, err = decodeSimpleValue(materializedData, byte(0))
 	case IntKind:
-		switch data.ByteSize {
+		switch descriptor.ByteSize {
 		case 1:
 			value, n, err = decodeSimpleValue(materializedData, int8(0))
 		case 2:
@@ -260,11 +383,15 @@ func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
 			value, n, err = decodeSimpleValue(materializedData, int32(0))
 		case 8:
 			value, n, err = decodeSimpleValue(materializedData, int64(0))
+		case 16:
+			value, n = decodeInt128(materializedData)
 		default:
-			panic("should never happen")
+			return nil, fmt.Errorf(
+				"unsupported int size (%d)",
+				descriptor.ByteSize)
 		}
 	case UintKind:
-		switch data.ByteSize {
+		switch descriptor.ByteSize {
 		case 1:
 			value, n, err = decodeSimpleValue(materializedData, uint8(0))
 		case 2:
@@ -273,17 +400,23 @@ func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
 			value, n, err = decodeSimpleValue(materializedData, uint32(0))
 		case 8:
 			value, n, err = decodeSimpleValue(materializedData, uint64(0))
+		case 16:
+			value, n = decodeUint128(materializedData)
 		default:
-			panic("should never happen")
+			return nil, fmt.Errorf(
+				"unsupported uint size (%d)",
+				descriptor.ByteSize)
 		}
 	case FloatKind:
-		switch data.ByteSize {
+		switch descriptor.ByteSize {
 		case 4:
 			value, n, err = decodeSimpleValue(materializedData, float32(0))
 		case 8:
 			value, n, err = decodeSimpleValue(materializedData, float64(0))
 		default:
-			panic("should never happen")
+			return nil, fmt.Errorf(
+				"unsupported float size (%d)",
+				descriptor.ByteSize)
 		}
 	}
 
@@ -291,22 +424,48 @@ func (data *TypedData) DecodeSimpleValue() (interface{}, error) {
 		return nil, fmt.Errorf("failed to decode simple value: %w", err)
 	}
 
-	if data.Kind == MemberPointerKind {
+	if descriptor.Kind == MemberPointerKind {
 		if n != 8 {
 			return nil, fmt.Errorf(
 				"failed to decode simple value. incorrect size (%d != 8)",
 				n)
 		}
-	} else if n != data.ByteSize {
+	} else if n != descriptor.ByteSize {
 		return nil, fmt.Errorf(
 			"failed to decode simple value. incorrect size (%d != %d)",
 			n,
-			data.ByteSize)
+			descriptor.ByteSize)
 	}
 
 	return value, nil
 }
 
+// Returns an implicit (read only) value of the given simple type, decoded
+// from the raw bytes.
+func (descriptor *DataDescriptor) NewImplicitData(
+	prefix string,
+	bytes []byte,
+) (
+	*TypedData,
+	error,
+) {
+	if len(bytes) > descriptor.ByteSize {
+		bytes = bytes[:descriptor.ByteSize]
+	}
+
+	value, err := descriptor.decodeSimpleBytes(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedData{
+		VirtualMemory:  descriptor.Pool.memory,
+		FormatPrefix:   prefix,
+		DataDescriptor: descriptor,
+		ImplicitValue:  value,
+	}, nil
+}
+
 func (data *TypedData) ReadCString() (string, error) {
 	if !data.IsCharPointer() {
 		return "", fmt.Errorf("cannot read c string. not char pointer")
@@ -348,6 +507,135 @@ func (data *TypedData) ReadCString() (string, error) {
 	}
 }
 
+// This converts the data into the descriptor's data representation (e.g.,
+// int literal to float).  Non-simple values are not converted, and must have
+// matching types.
+func (data *TypedData) EncodeAs(descriptor *DataDescriptor) ([]byte, error) {
+	if !data.IsSimpleValue() || !descriptor.IsSimpleValue() {
+		if !data.DataDescriptor.Equals(descriptor) {
+			return nil, fmt.Errorf(
+				"%w. cannot convert %s to %s",
+				ErrInvalidInput,
+				data.TypeName(),
+				descriptor.TypeName())
+		}
+
+		return data.Bytes()
+	}
+
+	value, err := data.DecodeSimpleValue()
+	if err != nil {
+		return nil, err
+	}
+
+	intValue := int64(0)
+	highBits := uint64(0) // only applicable to 128-bit integers
+	isSigned := false
+	floatValue := float64(0)
+	isFloat := false
+	switch v := value.(type) {
+	case bool:
+		if v {
+			intValue = 1
+		}
+	case int8:
+		intValue = int64(v)
+		isSigned = true
+	case int16:
+		intValue = int64(v)
+		isSigned = true
+	case int32:
+		intValue = int64(v)
+		isSigned = true
+	case int64:
+		intValue = v
+		isSigned = true
+	case Int128:
+		intValue = int64(v.Low)
+		highBits = v.High
+	case uint8:
+		intValue = int64(v)
+	case uint16:
+		intValue = int64(v)
+	case uint32:
+		intValue = int64(v)
+	case uint64:
+		intValue = int64(v)
+	case Uint128:
+		intValue = int64(v.Low)
+		highBits = v.High
+	case VirtualAddress:
+		intValue = int64(v)
+	case float32:
+		floatValue = float64(v)
+		isFloat = true
+	case float64:
+		floatValue = v
+		isFloat = true
+	default:
+		panic(fmt.Sprintf("unexpected simple value: %#v", value)) // should never happen
+	}
+
+	var bits uint64
+	switch descriptor.Kind {
+	case BoolKind:
+		if intValue != 0 || floatValue != 0 {
+			bits = 1
+		}
+	case CharKind, IntKind, UintKind:
+		if isFloat {
+			intValue = int64(floatValue)
+			isSigned = true
+		}
+		bits = uint64(intValue)
+
+		if isSigned && intValue < 0 {
+			highBits = math.MaxUint64
+		}
+	case PointerKind:
+		if isFloat {
+			return nil, fmt.Errorf(
+				"%w. cannot convert %s to %s",
+				ErrInvalidInput,
+				data.TypeName(),
+				descriptor.TypeName())
+		}
+		bits = uint64(intValue)
+	case FloatKind:
+		if !isFloat {
+			floatValue = float64(intValue)
+		}
+
+		switch descriptor.ByteSize {
+		case 4:
+			bits = uint64(math.Float32bits(float32(floatValue)))
+		case 8:
+			bits = math.Float64bits(floatValue)
+		default:
+			return nil, fmt.Errorf(
+				"%w. cannot convert to %d-byte float",
+				ErrInvalidInput,
+				descriptor.ByteSize)
+		}
+	default:
+		panic("should never happen")
+	}
+
+	result := binary.LittleEndian.AppendUint64(nil, bits)
+	if descriptor.ByteSize == 16 && descriptor.Kind != FloatKind {
+		result = binary.LittleEndian.AppendUint64(result, highBits)
+	}
+
+	if descriptor.ByteSize > len(result) {
+		return nil, fmt.Errorf(
+			"%w. cannot convert to %s",
+			ErrInvalidInput,
+			descriptor.TypeName())
+	}
+
+	return result[:descriptor.ByteSize], nil
+}
+
 func (data *TypedData) MethodReceiverPointer(
 	signature *SignatureDescriptor,
 ) *TypedData {
@@ -391,7 +679,98 @@ func (data *TypedData) SelectMatchingSignature(
 	return data.Signatures[idx], data.FunctionAddresses[idx], nil
 }
 
+// Format char as '<char>' (<numeric value>), where non-printable chars are
+// escaped.
+func formatChar(value byte, isSigned bool) string {
+	char := ""
+	switch value {
+	case 0:
+		char = "\\0"
+	case '\a':
+		char = "\\a"
+	case '\b':
+		char = "\\b"
+	case '\f':
+		char = "\\f"
+	case '\n':
+		char = "\\n"
+	case '\r':
+		char = "\\r"
+	case '\t':
+		char = "\\t"
+	case '\v':
+		char = "\\v"
+	case '\'':
+		char = "\\'"
+	case '\\':
+		char = "\\\\"
+	default:
+		if 0x20 <= value && value < 0x7f {
+			char = string([]byte{value})
+		} else {
+			char = fmt.Sprintf("\\x%02x", value)
+		}
+	}
+
+	if isSigned {
+		return fmt.Sprintf("'%s' (%d)", char, int8(value))
+	}
+	return fmt.Sprintf("'%s' (%d)", char, value)
+}
+
+type formatState struct {
+	maxDepth int
+
+	integerFormat IntegerFormat
+
+	// pointee address / type name -> struct{}
+	visited map[string]struct{}
+}
+
 func (data *TypedData) Format(indent string) string {
+	return data.FormatWithDepth(indent, 0)
+}
+
+// This is similar to Format, but pointers are dereferenced (up to maxDepth
+// levels) and their pointees are printed.  Each pointee is printed at most
+// once; pointers to revisited pointees (cycles / shared substructures) and
+// pointers beyond the depth limit are printed as bare addresses.
+func (data *TypedData) FormatWithDepth(indent string, maxDepth int) string {
+	return data.FormatWithIntegerFormat(indent, maxDepth, NaturalFormat)
+}
+
+// Similar to FormatWithDepth, but integer-like scalars (integers, bools,
+// chars, enums and pointers) are printed in the specified format, zero padded
+// to the scalar's byte size.
+func (data *TypedData) FormatWithIntegerFormat(
+	indent string,
+	maxDepth int,
+	integerFormat IntegerFormat,
+) string {
+	return data.format(
+		indent,
+		&formatState{
+			maxDepth:      maxDepth,
+			integerFormat: integerFormat,
+			visited:       map[string]struct{}{},
+		},
+		0)
+}
+
+func (data *TypedData) format(
+	indent string,
+	state *formatState,
+	depth int,
+) string {
+	if data.Unavailable != Available && data.Kind != VoidKind {
+		return fmt.Sprintf(
+			"%s%s (%s): <%s>",
+			indent,
+			data.FormatPrefix,
+			data.TypeName(),
+			data.Unavailable)
+	}
+
 	switch data.Kind {
 	case VoidKind:
 		return indent + "(void)"
@@ -401,27 +780,44 @@ func (data *TypedData) Format(indent string) string {
 		nextIndent := indent + "  "
 		for _, field := range data.Fields {
 			element, err := data.fieldData(field)
-			if err != nil {
-				panic(err) // should never happen
+			if err != nil { // e.g., unreadable virtual base class pointer
+				result += fmt.Sprintf("%s.%s: <%s>,\n", nextIndent, field.Name, err)
+				continue
 			}
 
-			result += element.Format(nextIndent) + ",\n"
+			result += element.format(nextIndent, state, depth) + ",\n"
 		}
 
 		result += fmt.Sprintf("%s}", indent)
 		return result
 
 	case ArrayKind:
+		if data.IsWideCharArray() {
+			units, err := data.wideCharArrayUnits()
+			if err == nil {
+				return fmt.Sprintf(
+					"%s%s (%s): %s",
+					indent,
+					data.FormatPrefix,
+					data.TypeName(),
+					formatWideString(
+						data.Value.wideCharPrefix(),
+						units,
+						data.Value.ByteSize))
+			}
+		}
+
 		result := fmt.Sprintf("%s%s: [\n", indent, data.FormatPrefix)
 
 		nextIndent := indent + "  "
 		for i := 0; i < data.NumElements; i++ {
 			element, err := data.Index(i)
 			if err != nil {
-				panic(err)
+				result += fmt.Sprintf("%s<%s>,\n", nextIndent, err)
+				continue
 			}
 
-			result += element.Format(nextIndent) + ",\n"
+			result += element.format(nextIndent, state, depth) + ",\n"
 		}
 
 		result += fmt.Sprintf("%s]", indent)
@@ -471,29 +867,273 @@ func (data *TypedData) Format(indent string) string {
 	default:
 		value, err := data.DecodeSimpleValue()
 		if err != nil {
-			panic(err) // should never happen
+			return fmt.Sprintf(
+				"%s%s (%s): <%s>",
+				indent,
+				data.FormatPrefix,
+				data.TypeName(),
+				err)
 		}
 
-		detail := ""
-		if data.Kind == CharKind {
-			detail = fmt.Sprintf(" (%s)", string([]byte{value.(byte)}))
-		} else if data.IsCharPointer() {
-			str, err := data.ReadCString()
-			if err == nil {
-				detail = " (" + str + ")"
+		valueStr := ""
+		if state.integerFormat != NaturalFormat && data.isIntegerLike() {
+			valueStr = data.formatInteger(state.integerFormat)
+		} else {
+			valueStr = data.formatSimpleValue(value)
+		}
+
+		typeName := data.TypeName()
+		if data.Kind == PointerKind {
+			dynamicTypeName := data.dynamicPointeeTypeName()
+			if dynamicTypeName != "" {
+				typeName += " (dynamic " + dynamicTypeName + ")"
 			}
 		}
 
-		return fmt.Sprintf(
-			"%s%s (%s): %v%s",
+		result := fmt.Sprintf(
+			"%s%s (%s): %s",
 			indent,
 			data.FormatPrefix,
-			data.TypeName(),
-			value,
-			detail)
+			typeName,
+			valueStr)
+
+		if data.Kind == PointerKind &&
+			!data.IsCharPointer() &&
+			!data.IsWideCharPointer() &&
+			depth < state.maxDepth {
+
+			result += data.formatPointee(indent+"  ", state, depth+1)
+		}
+
+		return result
+	}
+}
+
+// Formats a scalar (leaf) value.  This is shared by top-level and nested
+// (struct field / array element) formatting: bools are printed as
+// true/false, chars are quoted, enums are printed as enumerator names, and
+// char pointers include the pointed-to string.
+func (data *TypedData) formatSimpleValue(value interface{}) string {
+	if data.Kind == CharKind {
+		return formatChar(value.(byte), !data.IsUnsignedChar())
+	}
+
+	if data.Enumerators != nil {
+		return data.formatEnumValue(value)
+	}
+
+	unitSize := data.WideCharSize()
+	if unitSize != 0 {
+		var unit uint32
+		switch value := value.(type) {
+		case int16:
+			unit = uint32(uint16(value))
+		case int32:
+			unit = uint32(value)
+		case uint16:
+			unit = uint32(value)
+		case uint32:
+			unit = value
+		}
+
+		return formatWideChar(data.wideCharPrefix(), unit, unitSize)
+	}
+
+	if data.IsCharPointer() {
+		str, err := data.ReadCString()
+		if err == nil {
+			return fmt.Sprintf("%v (%s)", value, str)
+		}
+	}
+
+	if data.IsWideCharPointer() {
+		units, err := data.readWideStringUnits()
+		if err == nil {
+			return fmt.Sprintf(
+				"%v (%s)",
+				value,
+				formatWideString(
+					data.Value.wideCharPrefix(),
+					units,
+					data.Value.ByteSize))
+		}
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func (data *TypedData) isIntegerLike() bool {
+	switch data.Kind {
+	case BoolKind, CharKind, IntKind, UintKind, PointerKind:
+		return true
+	default:
+		return false
 	}
 }
 
+func (data *TypedData) formatInteger(integerFormat IntegerFormat) string {
+	content, err := data.Bytes()
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+
+	return integerFormat.FormatBytes(content, data.isSignedInteger())
+}
+
+// Unmatched values are printed as (<type name>)<numeric value>.
+func (data *TypedData) formatEnumValue(value interface{}) string {
+	var numeric int64
+	switch value := value.(type) {
+	case int8:
+		numeric = int64(value)
+	case int16:
+		numeric = int64(value)
+	case int32:
+		numeric = int64(value)
+	case int64:
+		numeric = value
+	case uint8:
+		numeric = int64(value)
+	case uint16:
+		numeric = int64(value)
+	case uint32:
+		numeric = int64(value)
+	case uint64:
+		numeric = int64(value)
+	default: // 128-bit enums
+		return fmt.Sprintf("%v", value)
+	}
+
+	for _, enumerator := range data.Enumerators {
+		if enumerator.Value == numeric {
+			return enumerator.Name
+		}
+	}
+
+	return fmt.Sprintf("(%s)%v", data.TypeName(), value)
+}
+
+// Returns a compact single line representation of the data's value (without
+// prefix / type name).  Aggregates are elided.
+func (data *TypedData) FormatValue() string {
+	if data.Unavailable != Available {
+		return "<" + string(data.Unavailable) + ">"
+	}
+
+	switch data.Kind {
+	case VoidKind:
+		return "(void)"
+	case StructKind, UnionKind, ArrayKind:
+		return "..."
+	case FunctionKind, MethodKind:
+		return fmt.Sprintf("%v", data.FunctionAddresses)
+	}
+
+	value, err := data.DecodeSimpleValue()
+	if err != nil {
+		return "<unreadable>"
+	}
+
+	return data.formatSimpleValue(value)
+}
+
+// Similar to FormatValue, but aggregates are fully expanded on a single line
+// (e.g., {x=1, y={2, 3}}).
+func (data *TypedData) FormatExpandedValue() string {
+	if data.Unavailable != Available {
+		return "<" + string(data.Unavailable) + ">"
+	}
+
+	switch data.Kind {
+	case StructKind, UnionKind:
+		fields := make([]string, 0, len(data.Fields))
+		for _, field := range data.Fields {
+			element, err := data.fieldData(field)
+			if err != nil { // e.g., unreadable virtual base class pointer
+				fields = append(fields, fmt.Sprintf("%s=<%s>", field.Name, err))
+				continue
+			}
+
+			fields = append(fields, field.Name+"="+element.FormatExpandedValue())
+		}
+
+		return "{" + strings.Join(fields, ", ") + "}"
+
+	case ArrayKind:
+		if data.IsWideCharArray() {
+			units, err := data.wideCharArrayUnits()
+			if err == nil {
+				return formatWideString(
+					data.Value.wideCharPrefix(),
+					units,
+					data.Value.ByteSize)
+			}
+		}
+
+		elements := make([]string, 0, data.NumElements)
+		for i := 0; i < data.NumElements; i++ {
+			element, err := data.Index(i)
+			if err != nil {
+				elements = append(elements, fmt.Sprintf("<%s>", err))
+				continue
+			}
+
+			elements = append(elements, element.FormatExpandedValue())
+		}
+
+		return "{" + strings.Join(elements, ", ") + "}"
+	}
+
+	return data.FormatValue()
+}
+
+func (data *TypedData) formatPointee(
+	indent string,
+	state *formatState,
+	depth int,
+) string {
+	switch data.Value.Kind {
+	case VoidKind, FunctionKind, MethodKind:
+		return ""
+	}
+
+	pointee, err := data.Dereference()
+	if err != nil || pointee.Address == 0 {
+		return ""
+	}
+
+	// Print polymorphic pointees as their dynamic (most-derived) types.
+	if data.Value.IsPolymorphic() {
+		object, err := pointee.dynamicObject()
+		if err == nil {
+			pointee = object
+		}
+	}
+
+	key := fmt.Sprintf("%s %s", pointee.Address, pointee.TypeName())
+	_, ok := state.visited[key]
+	if ok {
+		return " (<visited>)"
+	}
+
+	// Ensure the pointee is readable before formatting.
+	buffer := make([]byte, pointee.ByteSize)
+	n, err := data.Read(pointee.Address, buffer)
+	if err != nil || n != len(buffer) {
+		return " (<unreadable>)"
+	}
+
+	state.visited[key] = struct{}{}
+	return "\n" + pointee.format(indent, state, depth)
+}
+
 func Evaluate(ctx EvaluationContext, expression string) (*TypedData, error) {
 	return Parse(newLexer(expression), newReducer(ctx))
 }
+
+// Checks the expression's syntax without evaluating it, i.e., names are not
+// resolved and functions are not called.
+func CheckSyntax(expression string) error {
+	_, err := Parse(newLexer(expression), syntaxChecker{})
+	return err
+}
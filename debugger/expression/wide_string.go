@@ -0,0 +1,252 @@
+package expression
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/dwarf"
+)
+
+// Maximum number of code units read from a wide string pointer.
+const maxWideStringLength = 4096
+
+// Returns the code unit byte size (2 or 4) if the descriptor is a wide
+// character type (char16_t, char32_t or wchar_t), and zero otherwise.
+//
+// char16_t / char32_t are identified by their DW_ATE_UTF encoding, while
+// wchar_t is encoded as a plain integer and is identified by its name.
+func (descriptor *DataDescriptor) WideCharSize() int {
+	if descriptor.Kind != IntKind && descriptor.Kind != UintKind {
+		return 0
+	}
+
+	if descriptor.ByteSize != 2 && descriptor.ByteSize != 4 {
+		return 0
+	}
+
+	if descriptor.DIE == nil || descriptor.DIE.Tag != dwarf.DW_TAG_base_type {
+		return 0
+	}
+
+	encoding, _ := descriptor.DIE.Uint(dwarf.DW_AT_encoding)
+	if encoding == dwarf.DW_ATE_UTF {
+		return descriptor.ByteSize
+	}
+
+	name, _, _ := descriptor.DIE.Name()
+	if name == "wchar_t" {
+		return descriptor.ByteSize
+	}
+
+	return 0
+}
+
+func (descriptor *DataDescriptor) IsWideCharPointer() bool {
+	return descriptor.Kind == PointerKind && descriptor.Value.WideCharSize() != 0
+}
+
+func (descriptor *DataDescriptor) IsWideCharArray() bool {
+	return descriptor.Kind == ArrayKind && descriptor.Value.WideCharSize() != 0
+}
+
+// The c++ literal prefix for the wide character type (u, U or L).
+func (descriptor *DataDescriptor) wideCharPrefix() string {
+	name, _, _ := descriptor.DIE.Name()
+	if name == "wchar_t" {
+		return "L"
+	}
+
+	if descriptor.ByteSize == 2 {
+		return "u"
+	}
+	return "U"
+}
+
+// Reads the null terminated wide string pointed to by the wide char pointer.
+// Invalid code units are decoded as utf8.RuneError.
+func (data *TypedData) ReadWideString() (string, error) {
+	units, err := data.readWideStringUnits()
+	if err != nil {
+		return "", err
+	}
+
+	return decodeWideString(units, data.Value.ByteSize), nil
+}
+
+func (data *TypedData) readWideStringUnits() ([]uint32, error) {
+	if !data.IsWideCharPointer() {
+		return nil, fmt.Errorf("cannot read wide string. not wide char pointer")
+	}
+
+	addr, err := data.DecodeSimpleValue()
+	if err != nil {
+		return nil, err
+	}
+
+	address := addr.(VirtualAddress)
+	unitSize := data.Value.ByteSize
+
+	units := []uint32{}
+	buffer := make([]byte, 64*unitSize)
+	for len(units) < maxWideStringLength {
+		n, err := data.Read(address, buffer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read wide string: %w", err)
+		}
+		if n < unitSize {
+			return nil, fmt.Errorf(
+				"failed to read wide string. read %d bytes",
+				n)
+		}
+
+		chunk := decodeWideCharUnits(buffer[:n-n%unitSize], unitSize)
+		for idx, unit := range chunk {
+			if unit == 0 {
+				return append(units, chunk[:idx]...), nil
+			}
+		}
+
+		units = append(units, chunk...)
+		address += VirtualAddress(len(chunk) * unitSize)
+	}
+
+	return nil, fmt.Errorf(
+		"wide string too long (> %d code units)",
+		maxWideStringLength)
+}
+
+// Returns the array's code units up to (excluding) the first null
+// terminator.
+func (data *TypedData) wideCharArrayUnits() ([]uint32, error) {
+	content, err := data.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	units := decodeWideCharUnits(content, data.Value.ByteSize)
+	for idx, unit := range units {
+		if unit == 0 {
+			return units[:idx], nil
+		}
+	}
+
+	return units, nil
+}
+
+func decodeWideCharUnits(content []byte, unitSize int) []uint32 {
+	units := make([]uint32, 0, len(content)/unitSize)
+	for idx := 0; idx+unitSize <= len(content); idx += unitSize {
+		if unitSize == 2 {
+			units = append(
+				units,
+				uint32(binary.LittleEndian.Uint16(content[idx:])))
+		} else {
+			units = append(units, binary.LittleEndian.Uint32(content[idx:]))
+		}
+	}
+
+	return units
+}
+
+// A decoded code point.  Invalid is set for unpaired utf-16 surrogates and
+// out of range utf-32 values, in which case Unit holds the raw code unit.
+type wideRune struct {
+	Rune    rune
+	Invalid bool
+	Unit    uint32
+}
+
+// NOTE: utf-16 surrogate pairs are only combined for 2-byte code units.
+func decodeWideRunes(units []uint32, unitSize int) []wideRune {
+	result := make([]wideRune, 0, len(units))
+	for idx := 0; idx < len(units); idx++ {
+		unit := units[idx]
+		if unitSize == 2 && utf16.IsSurrogate(rune(unit)) {
+			if idx+1 < len(units) {
+				r := utf16.DecodeRune(rune(unit), rune(units[idx+1]))
+				if r != utf8.RuneError {
+					result = append(result, wideRune{Rune: r})
+					idx++
+					continue
+				}
+			}
+
+			result = append(result, wideRune{Invalid: true, Unit: unit})
+			continue
+		}
+
+		if unit > utf8.MaxRune || utf16.IsSurrogate(rune(unit)) {
+			result = append(result, wideRune{Invalid: true, Unit: unit})
+			continue
+		}
+
+		result = append(result, wideRune{Rune: rune(unit)})
+	}
+
+	return result
+}
+
+func decodeWideString(units []uint32, unitSize int) string {
+	result := make([]rune, 0, len(units))
+	for _, r := range decodeWideRunes(units, unitSize) {
+		if r.Invalid {
+			result = append(result, utf8.RuneError)
+		} else {
+			result = append(result, r.Rune)
+		}
+	}
+	return string(result)
+}
+
+// Formats the code units as a quoted c++ literal (e.g., u"text").  Non-
+// printable characters and invalid code units are escaped.
+func formatWideString(prefix string, units []uint32, unitSize int) string {
+	builder := strings.Builder{}
+	builder.WriteString(prefix)
+	builder.WriteByte('"')
+	for _, r := range decodeWideRunes(units, unitSize) {
+		builder.WriteString(escapeWideRune(r, '"', unitSize))
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+func formatWideChar(prefix string, value uint32, unitSize int) string {
+	runes := decodeWideRunes([]uint32{value}, unitSize)
+	return fmt.Sprintf(
+		"%s'%s' (%d)",
+		prefix,
+		escapeWideRune(runes[0], '\'', unitSize),
+		value)
+}
+
+func escapeWideRune(r wideRune, quote rune, unitSize int) string {
+	if r.Invalid {
+		if unitSize == 2 {
+			return fmt.Sprintf("\\u%04x", r.Unit)
+		}
+		return fmt.Sprintf("\\U%08x", r.Unit)
+	}
+
+	switch r.Rune {
+	case 0:
+		return "\\0"
+	case '\\':
+		return "\\\\"
+	case quote:
+		return "\\" + string(quote)
+	}
+
+	if strconv.IsPrint(r.Rune) {
+		return string(r.Rune)
+	}
+
+	// Non-printable characters use go's escape sequences (e.g., \n, \x1b).
+	quoted := strconv.QuoteRune(r.Rune)
+	return quoted[1 : len(quoted)-1]
+}
@@ -0,0 +1,258 @@
+package debugger
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+	"github.com/pattyshack/bad/procfs"
+)
+
+// Controls how user break points / watch points are handled when the process
+// executes a new program (execve).
+type FollowExecMode string
+
+const (
+	// Keep the stop points and re-resolve them against the new program.
+	FollowExecSame = FollowExecMode("same")
+
+	// Discard the stop points (i.e., treat the new program as a new debugging
+	// session).
+	FollowExecNew = FollowExecMode("new")
+)
+
+var FollowExecModes = []FollowExecMode{FollowExecSame, FollowExecNew}
+
+const maxSyscallReadAttempts = 3
+
+// When a thread executes a new program, execve destroys all other threads in
+// the process, and blocks until the destroyed threads are gone.  Since the
+// destroyed threads stop at their PTRACE_EVENT_EXIT stops, the debugger must
+// release the threads (rather than holding them stopped) for execve to make
+// progress.  When the executing thread is not the thread group leader, the
+// thread takes over the leader's tid (i.e., the pid), and the thread's old tid
+// is gone.
+//
+// preprocessExecEvent handles the thread events caused by execve, before the
+// events are processed as regular stop / exit events:
+//   - a destroyed thread's exit event is consumed, and the thread is released.
+//     The destroyed thread is dropped, unless the thread is the thread group
+//     leader, whose next report is the exec event.
+//   - a dropped thread's (delayed) exit report is consumed.
+//   - on exec event, the executing thread's state is transferred into the
+//     thread group leader's thread state, and the executing thread's old tid
+//     is dropped.
+//
+// This returns true if the event is consumed, along with the dropped tids.
+func (db *Debugger) preprocessExecEvent(
+	tid int,
+	waitStatus syscall.WaitStatus,
+) (
+	bool,
+	[]int,
+	error,
+) {
+	if isExecEvent(waitStatus) {
+		dropped, err := db.adoptExecutingThread()
+		return false, dropped, err
+	}
+
+	thread, ok := db.threads[tid]
+	if !ok && !waitStatus.Stopped() {
+		return true, nil, nil
+	}
+
+	if !isExitEvent(waitStatus) ||
+		(!db.isExecInProgress && !db.hasExecutingThread(tid)) {
+
+		return false, nil, nil
+	}
+
+	db.isExecInProgress = true
+
+	var threadTracer ThreadTracer
+	if ok {
+		threadTracer = thread.threadTracer
+	} else {
+		threadTracer = db.processTracer.TraceThread(tid)
+	}
+
+	err := threadTracer.Resume(0)
+	if err != nil {
+		return false, nil, fmt.Errorf(
+			"failed to release thread %d destroyed by execve: %w",
+			tid,
+			err)
+	}
+
+	if tid == db.Pid {
+		return true, nil, nil
+	}
+
+	db.dropThread(tid)
+	return true, []int{tid}, nil
+}
+
+// Returns true if a running thread (other than the excluded thread) is in
+// execve.
+//
+// NOTE: the executing thread is blocked in execve while the other threads are
+// being destroyed, but the thread may briefly run (i.e., its system call is
+// unavailable) in between waits.
+func (db *Debugger) hasExecutingThread(excludedTid int) bool {
+	for tid, thread := range db.threads {
+		if tid == excludedTid || !thread.status.Running() {
+			continue
+		}
+
+		for attempt := 0; attempt < maxSyscallReadAttempts; attempt++ {
+			nr, ok, err := procfs.GetTaskSyscall(db.Pid, tid)
+			if err != nil {
+				break
+			}
+
+			if ok {
+				if nr == unix.SYS_EXECVE || nr == unix.SYS_EXECVEAT {
+					return true
+				}
+				break
+			}
+
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	return false
+}
+
+// Transfers the executing thread's state into the thread group leader's
+// thread state (the leader's tracer is already bound to the pid), and drops
+// the executing thread's old tid.  Returns the dropped tids.
+func (db *Debugger) adoptExecutingThread() ([]int, error) {
+	leader := db.threads[db.Pid]
+
+	msg, err := leader.threadTracer.GetEventMsg()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exec event message: %w", err)
+	}
+
+	db.isExecInProgress = false
+
+	oldTid := int(msg)
+	if oldTid == db.Pid {
+		return nil, nil
+	}
+
+	executing, ok := db.threads[oldTid]
+	if ok {
+		leader.status = executing.status
+		leader.status.Tid = db.Pid
+		leader.expectsSyscallExit = executing.expectsSyscallExit
+		leader.hasPendingSigStop = executing.hasPendingSigStop
+		leader.hasPendingSingleStepTrap = executing.hasPendingSingleStepTrap
+		leader.exitEventStatus = nil
+		leader.isForking = executing.isForking
+		leader.isCatchingSyscall = executing.isCatchingSyscall
+		leader.heldSignals = executing.heldSignals
+
+		executing.status = newRunningStatus(oldTid)
+	}
+
+	db.dropThread(oldTid)
+	return []int{oldTid}, nil
+}
+
+// handleExec resets the debugger's process image specific states after the
+// thread's execve replaced the process' address space.  This must be called
+// before the thread's status is updated.
+func (db *Debugger) handleExec(thread *ThreadState) error {
+	// NOTE: execve destroyed all other threads (see preprocessExecEvent).
+	for tid := range db.threads {
+		if tid != thread.Tid {
+			db.dropThread(tid)
+		}
+	}
+
+	_, err := db.LoadedElves.ReloadExecutable(db.Pid)
+	if err != nil {
+		return fmt.Errorf("failed to reload executable after exec: %w", err)
+	}
+
+	// NOTE: execve discards the software stop sites (the old program's
+	// memory), but we'll explicitly disable the hardware stop sites to be safe.
+	state, err := thread.Registers.GetState()
+	if err != nil {
+		return err
+	}
+
+	state, err = state.WithValue(registers.DebugControl, registers.U64(0))
+	if err != nil {
+		return err
+	}
+
+	err = thread.Registers.SetState(state)
+	if err != nil {
+		return fmt.Errorf("failed to reset debug registers after exec: %w", err)
+	}
+
+	stopSites := stoppoint.NewStopSitePool(db, db.Arch)
+	db.stopSites = stopSites
+	db.Disassembler = memory.NewDisassembler(db.VirtualMemory, stopSites, db.Arch)
+
+	db.descriptorPool = expression.NewDataDescriptorPool(
+		db.LoadedElves,
+		db.VirtualMemory,
+		db.Arch)
+	db.EvaluatedResults = &expression.EvaluatedResultPool{}
+
+	db.rendezvousNotifySite = nil
+	db.rendezvousAddresses = map[VirtualAddress]struct{}{}
+
+	entryPointSite, err := stopSites.Allocate(
+		db.LoadedElves.EntryPoint(),
+		stoppoint.NewBreakSiteType(false))
+	if err != nil {
+		return err
+	}
+
+	err = entryPointSite.Enable()
+	if err != nil {
+		return err
+	}
+
+	db.entryPointRendezvousSite = entryPointSite
+	db.rendezvousAddresses[db.LoadedElves.EntryPoint()] = struct{}{}
+
+	// NOTE: the sets are reset in place (rather than replaced) since the sets
+	// may be referenced elsewhere (e.g., the cli's commands).
+	if db.FollowExecMode == FollowExecNew {
+		db.BreakPoints.Reset(stopSites)
+		db.WatchPoints.Reset(stopSites)
+	} else {
+		err = db.BreakPoints.Rebind(stopSites)
+		if err != nil {
+			return err
+		}
+
+		err = db.WatchPoints.Rebind(stopSites)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = db.exceptionCatchPoints.Rebind(stopSites)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: execve discards the scoped watch points' frames, as well as their
+	// return sites.
+	return db.exitAllWatchPointScopes(false)
+}
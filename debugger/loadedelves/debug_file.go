@@ -0,0 +1,177 @@
+package loadedelves
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+
+	"github.com/pattyshack/bad/dwarf"
+	"github.com/pattyshack/bad/elf"
+)
+
+const (
+	DefaultDebugFileDirectory = "/usr/lib/debug"
+
+	debugLinkSectionName = ".gnu_debuglink"
+	buildIdDirectory     = ".build-id"
+	debugFileSuffix      = ".debug"
+	debugLinkDirectory   = ".debug"
+)
+
+// Locates and attaches the elf file's separate debug file.  The debug file is
+// located by build id (<dir>/.build-id/xx/yyyy.debug) and then by
+// .gnu_debuglink (<elf dir>/<link>, <elf dir>/.debug/<link>, and
+// <dir>/<elf dir>/<link>).  This is a no-op if the file already has debug
+// info, or if no matching debug file is found.
+func (file *File) loadSeparateDebugInfo(directories []string) error {
+	if file.Dwarf != nil {
+		return nil
+	}
+
+	path, content := file.locateDebugFileByBuildId(directories)
+	if path == "" {
+		path, content = file.locateDebugFileByDebugLink(directories)
+		if path == "" {
+			return nil
+		}
+	}
+
+	// NOTE: the debug elf file shares the elf file's name since the debug
+	// file's symbols and compile units describe the elf file.
+	debugElf, err := elf.ParseBytes(file.FileName, content)
+	if err != nil {
+		return fmt.Errorf("failed to parse debug elf (%s): %w", path, err)
+	}
+
+	dwarfFile, err := dwarf.NewFileWithSeparateDebugInfo(file.File, debugElf)
+	if err != nil {
+		return fmt.Errorf("failed to parse dwarf (%s): %w", path, err)
+	}
+
+	file.Dwarf = dwarfFile
+	file.DebugFilePath = path
+	file.debugElf = debugElf
+
+	// Stripped elf files only retain the dynamic symbol table.
+	if file.GetSection(symbolTableName) == nil {
+		section := debugElf.GetSection(symbolTableName)
+		if section != nil {
+			file.symbolTables = append(
+				file.symbolTables,
+				section.(*elf.SymbolTableSection))
+		}
+	}
+
+	return nil
+}
+
+func (file *File) locateDebugFileByBuildId(
+	directories []string,
+) (
+	string,
+	[]byte,
+) {
+	buildId := file.BuildId()
+	if len(buildId) < 3 {
+		return "", nil
+	}
+
+	for _, dir := range directories {
+		path := filepath.Join(
+			dir,
+			buildIdDirectory,
+			buildId[:2],
+			buildId[2:]+debugFileSuffix)
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		debugElf, err := elf.ParseBytes(path, content)
+		if err != nil || debugElf.BuildId() != buildId {
+			continue
+		}
+
+		return path, content
+	}
+
+	return "", nil
+}
+
+func (file *File) locateDebugFileByDebugLink(
+	directories []string,
+) (
+	string,
+	[]byte,
+) {
+	name, checksum, ok := file.debugLink()
+	if !ok {
+		return "", nil
+	}
+
+	elfDir := filepath.Dir(file.FileName)
+	absElfDir, err := filepath.Abs(elfDir)
+	if err != nil {
+		absElfDir = elfDir
+	}
+
+	candidates := []string{
+		filepath.Join(elfDir, name),
+		filepath.Join(elfDir, debugLinkDirectory, name),
+	}
+	for _, dir := range directories {
+		candidates = append(candidates, filepath.Join(dir, absElfDir, name))
+	}
+
+	for _, path := range candidates {
+		if path == file.FileName {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		if crc32.ChecksumIEEE(content) != checksum {
+			continue
+		}
+
+		return path, content
+	}
+
+	return "", nil
+}
+
+// The .gnu_debuglink section contains the zero-terminated debug file name,
+// padded to 4 byte alignment, followed by the debug file's 4 bytes crc32
+// checksum.
+func (file *File) debugLink() (string, uint32, bool) {
+	section := file.GetSection(debugLinkSectionName)
+	if section == nil {
+		return "", 0, false
+	}
+
+	content, err := section.RawContent()
+	if err != nil {
+		return "", 0, false
+	}
+
+	end := bytes.IndexByte(content, 0)
+	if end <= 0 {
+		return "", 0, false
+	}
+
+	checksumOffset := (end + 4) &^ 3
+	if checksumOffset+4 > len(content) {
+		return "", 0, false
+	}
+
+	checksum := file.DataEncoding.ByteOrder().Uint32(
+		content[checksumOffset : checksumOffset+4])
+
+	return string(content[:end]), checksum, true
+}
@@ -136,6 +136,72 @@ func (DwarfSuite) TestAddressRanges(t *testing.T) {
 		addressRanges[3])
 }
 
+func (DwarfSuite) TestRangeLists(t *testing.T) {
+	// NOTE: the data is big endian encoded
+	content := []byte{
+		// junk
+		0x0b, 0x0a, 0x0d, 0x00,
+
+		// offset pair (uleb128 encoded)
+		dwarf.DW_RLE_offset_pair, 0x10, 0x90, 0x01,
+
+		// start end
+		dwarf.DW_RLE_start_end,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x20,
+
+		// new base address
+		dwarf.DW_RLE_base_address,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00,
+
+		// offset pair (uleb128 encoded)
+		dwarf.DW_RLE_offset_pair, 0x04, 0x08,
+
+		// start length (uleb128 encoded)
+		dwarf.DW_RLE_start_length,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x00,
+		0x80, 0x01,
+
+		dwarf.DW_RLE_end_of_list,
+
+		// junk
+		0x0b, 0x0a, 0x0d, 0x00,
+	}
+
+	rl := dwarf.NewRangeListsSectionFromBytes(binary.BigEndian, content)
+
+	addressRanges, err := rl.RangeListAt(4, 0x5000)
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		dwarf.AddressRanges{
+			{Low: 0x5010, High: 0x5090},
+			{Low: 0x1000, High: 0x1020},
+			{Low: 0x30004, High: 0x30008},
+			{Low: 0x2000, High: 0x2080},
+		},
+		addressRanges)
+
+	// base address changes only apply to subsequent entries
+	addressRanges, err = rl.RangeListAt(25, 0x5000)
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		dwarf.AddressRanges{
+			{Low: 0x30004, High: 0x30008},
+			{Low: 0x2000, High: 0x2080},
+		},
+		addressRanges)
+
+	// unterminated list
+	rl = dwarf.NewRangeListsSectionFromBytes(
+		binary.BigEndian,
+		content[:len(content)-5])
+
+	_, err = rl.RangeListAt(4, 0x5000)
+	expect.NotNil(t, err)
+}
+
 func (s DwarfSuite) TestLineTable(t *testing.T) {
 	file := s.newFile(t, "../test_targets/hello_world")
 
@@ -227,3 +293,190 @@ func (s DwarfSuite) TestLineTable(t *testing.T) {
 		expect.Nil(t, iter)
 	}
 }
+
+// Parses hello_world with the named section truncated to length and the byte
+// at offset xor-ed with garble.  Malformed dwarf must result in errors rather
+// than panics.
+func parseMalformedSection(
+	t *testing.T,
+	content []byte,
+	sectionName string,
+	length int,
+	offset int,
+	garble byte,
+) error {
+	elfFile, err := elf.ParseBytes("", content)
+	expect.Nil(t, err)
+
+	section, ok := elfFile.GetSection(sectionName).(*elf.RawSection)
+	expect.True(t, ok)
+
+	if length >= 0 && length < len(section.Content) {
+		section.Content = section.Content[:length]
+	}
+
+	if offset >= 0 && offset < len(section.Content) {
+		section.Content[offset] ^= garble
+	}
+
+	file, err := dwarf.NewFile(elfFile)
+	if err != nil {
+		return err
+	}
+
+	for _, unit := range file.CompileUnits {
+		root, err := unit.Root()
+		if err != nil {
+			return err
+		}
+
+		err = root.Visit(
+			func(entry *dwarf.DebugInfoEntry) error {
+				_, err := entry.AddressRanges()
+				if err != nil {
+					return err
+				}
+
+				_, _, err = entry.Name()
+				if err != nil {
+					return err
+				}
+
+				_, _, err = entry.DeclarationLocation()
+				if err != nil {
+					return err
+				}
+
+				hasType, err := entry.HasTypeEntry()
+				if err != nil || !hasType {
+					return err
+				}
+
+				_, err = entry.TypeEntry()
+				return err
+			},
+			nil)
+		if err != nil {
+			return err
+		}
+
+		iter, err := unit.LineIterator()
+		for iter != nil && err == nil {
+			_, err = file.ComputeUnwindRulesAt(iter.FileAddress)
+			if err != nil {
+				return err
+			}
+
+			iter, err = iter.Next()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = file.FunctionDefinitionEntriesWithName("main")
+	return err
+}
+
+func parseMalformedDebugInfo(
+	t *testing.T,
+	content []byte,
+	length int,
+	offset int,
+	garble byte,
+) error {
+	return parseMalformedSection(
+		t,
+		content,
+		dwarf.ElfDebugInformationSection,
+		length,
+		offset,
+		garble)
+}
+
+func (DwarfSuite) TestMalformedDebugInfo(t *testing.T) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	expect.Nil(t, err)
+
+	err = parseMalformedDebugInfo(t, content, -1, -1, 0)
+	expect.Nil(t, err)
+
+	// truncated compile unit header / content
+	for _, length := range []int{0, 1, 5, 11, 12, 100} {
+		err = parseMalformedDebugInfo(t, content, length, -1, 0)
+		if length == 0 {
+			expect.Nil(t, err)
+		} else {
+			expect.NotNil(t, err)
+		}
+	}
+
+	// garbled unit length, version, abbreviation offset and address size
+	for _, offset := range []int{3, 4, 8, 10} {
+		err = parseMalformedDebugInfo(t, content, -1, offset, 0xff)
+		expect.NotNil(t, err)
+	}
+}
+
+func FuzzMalformedDebugInfo(f *testing.F) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(-1, 11, byte(0x01))
+	f.Add(100, 20, byte(0xff))
+	f.Add(-1, 30, byte(0x80))
+
+	f.Fuzz(func(t *testing.T, length int, offset int, garble byte) {
+		// NOTE: the returned error is irrelevant.  The fuzzer checks for panics.
+		_ = parseMalformedDebugInfo(t, content, length, offset, garble)
+	})
+}
+
+func (DwarfSuite) TestMalformedSections(t *testing.T) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	expect.Nil(t, err)
+
+	// NOTE: the returned errors are irrelevant for most truncation lengths /
+	// garbled offsets.  The test checks for panics.
+	for _, name := range []string{
+		dwarf.ElfDebugAbbreviationSection,
+		dwarf.ElfDebugInformationSection,
+		dwarf.ElfDebugLineSection,
+		dwarf.ElfDebugStringSection,
+		dwarf.ElfEhFrameSection,
+	} {
+		for idx := 0; idx < 256; idx++ {
+			_ = parseMalformedSection(t, content, name, idx, -1, 0)
+
+			for _, garble := range []byte{0x01, 0x0d, 0x0e, 0x80, 0xff} {
+				_ = parseMalformedSection(t, content, name, -1, idx, garble)
+			}
+		}
+	}
+
+	// truncated line table
+	err = parseMalformedSection(t, content, dwarf.ElfDebugLineSection, 20, -1, 0)
+	expect.Error(t, err, "need")
+
+	// zeroed line range (line base at offset 14 is -5, line range is 14)
+	err = parseMalformedSection(
+		t,
+		content,
+		dwarf.ElfDebugLineSection,
+		-1,
+		14,
+		14)
+	expect.Error(t, err, "invalid line table line range (0)")
+
+	// zeroed op code base (13)
+	err = parseMalformedSection(
+		t,
+		content,
+		dwarf.ElfDebugLineSection,
+		-1,
+		15,
+		13)
+	expect.Error(t, err, "invalid line table op code base (0)")
+}
@@ -1,7 +1,9 @@
 package loadedelves
 
 import (
+	"encoding/binary"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/pattyshack/gt/testing/expect"
@@ -55,3 +57,136 @@ func (ElfSuite) TestParse(t *testing.T) {
 	expect.Equal(t, 1, len(symbols))
 	expect.Equal(t, "_start", symbols[0].Name)
 }
+
+func (ElfSuite) TestBuildInfo(t *testing.T) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	expect.Nil(t, err)
+
+	file, err := elf.ParseBytes("", content)
+	expect.Nil(t, err)
+
+	comments, err := file.Comments()
+	expect.Nil(t, err)
+	expect.True(t, len(comments) > 0)
+	expect.True(t, strings.HasPrefix(comments[0], "GCC: "))
+
+	seen := map[string]struct{}{}
+	for _, comment := range comments {
+		_, ok := seen[comment]
+		expect.False(t, ok)
+		seen[comment] = struct{}{}
+	}
+
+	expect.True(t, len(file.NoteSections()) > 1)
+
+	abiTag, err := file.ABITag()
+	expect.Nil(t, err)
+	expect.NotNil(t, abiTag)
+	expect.Equal(t, "Linux", abiTag.OperatingSystem)
+	expect.True(t, abiTag.Major >= 2)
+	expect.True(t, strings.HasPrefix(abiTag.String(), "Linux ABI "))
+
+	expect.Equal(t, 40, len(file.BuildId()))
+
+	abiTag, err = (&elf.File{}).ABITag()
+	expect.Nil(t, err)
+	expect.Nil(t, abiTag)
+
+	comments, err = (&elf.File{}).Comments()
+	expect.Nil(t, err)
+	expect.Equal(t, 0, len(comments))
+}
+
+func (ElfSuite) TestParseMalformed(t *testing.T) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	expect.Nil(t, err)
+
+	_, err = elf.ParseBytes("", nil)
+	expect.Error(t, err, "failed to parse identifier")
+
+	_, err = elf.ParseBytes("", content[:10])
+	expect.Error(t, err, "need 16 bytes, 10 available")
+
+	_, err = elf.ParseBytes("", content[:32])
+	expect.Error(t, err, "failed to parse header")
+
+	_, err = elf.ParseBytes("", content[:40])
+	expect.Error(t, err, "need 64 bytes, 40 available")
+
+	// Truncated section header table.
+	_, err = elf.ParseBytes("", content[:len(content)-1])
+	expect.Error(t, err, "truncated section header table")
+	expect.Error(t, err, "available")
+
+	file, err := elf.ParseBytes("", content)
+	expect.Nil(t, err)
+
+	// Section header offsets / sizes are relative to e_shoff.
+	shOffset := int(file.SectionHeaderOffset)
+
+	modified := func(
+		sectionIdx int,
+		fieldOffset int,
+		value uint64,
+	) []byte {
+		buffer := make([]byte, len(content))
+		copy(buffer, content)
+
+		start := shOffset + sectionIdx*elf.Elf64SectionHeaderEntrySize
+		binary.LittleEndian.PutUint64(buffer[start+fieldOffset:], value)
+		return buffer
+	}
+
+	// Section content offset (sh_offset) overflows sh_offset + sh_size.
+	_, err = elf.ParseBytes("", modified(1, 24, 0xffffffffffffff00))
+	expect.Error(t, err, "out of bound section 1")
+
+	// Section content size (sh_size) is absurdly large.
+	_, err = elf.ParseBytes("", modified(1, 32, 0xffffffffffffff00))
+	expect.Error(t, err, "out of bound section")
+
+	// Section name table index (e_shstrndx) is out of bound.
+	buffer := make([]byte, len(content))
+	copy(buffer, content)
+	binary.LittleEndian.PutUint16(buffer[62:], file.NumSectionHeaderEntries)
+	_, err = elf.ParseBytes("", buffer)
+	expect.Error(t, err, "section name index out of bound")
+}
+
+func FuzzParseBytes(f *testing.F) {
+	content, err := os.ReadFile("../test_targets/hello_world")
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(content)
+	f.Add(content[:64])
+	f.Add(content[:len(content)/2])
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		file, err := elf.ParseBytes("", content)
+		if err != nil {
+			return
+		}
+
+		for _, section := range file.Sections {
+			_ = section.Name()
+
+			table, ok := section.(*elf.StringTableSection)
+			if ok {
+				_ = table.NumEntries()
+			}
+
+			symbols, ok := section.(*elf.SymbolTableSection)
+			if ok {
+				for _, symbol := range symbols.Symbols {
+					_ = symbols.SymbolSpans(elf.FileAddress(symbol.Value))
+				}
+			}
+		}
+
+		_, _ = file.ABITag()
+		_ = file.BuildId()
+		_, _ = file.Comments()
+	})
+}
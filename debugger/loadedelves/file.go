@@ -26,6 +26,11 @@ type File struct {
 
 	Dwarf *dwarf.File // optional
 
+	// The separate debug file's path, or empty string if the debug info (if
+	// any) is embedded in the elf file.
+	DebugFilePath string
+	debugElf      *elf.File
+
 	symbolTables []*elf.SymbolTableSection
 }
 
@@ -204,6 +209,72 @@ func (file *File) SymbolSpans(address VirtualAddress) *elf.Symbol {
 	return nil
 }
 
+// Returns the index of the memory occupying section containing the address,
+// or -1 if no section contains the address.  NOTE: tls sections are skipped
+// since their addresses refer to the tls initialization image, which overlaps
+// other sections.
+func (file *File) SectionIndexContaining(address VirtualAddress) int {
+	fileAddr := uint64(file.ToFileAddress(address))
+	for idx, section := range file.Sections {
+		header := section.Header()
+		if header.SectionFlags&elf.SectionOccupiesMemory == 0 ||
+			header.SectionFlags&elf.SectionContainsTLSData != 0 {
+
+			continue
+		}
+
+		if header.Address <= fileAddr && fileAddr-header.Address < header.Size {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// Returns the symbol spanning the address.  If no symbol spans the address,
+// this returns the nearest function / object symbol preceding the address
+// within the same section (e.g., the address is in a function's alignment
+// padding), or nil if there is no such symbol.
+func (file *File) NearestSymbol(address VirtualAddress) *elf.Symbol {
+	symbol := file.SymbolSpans(address)
+	if symbol != nil {
+		return symbol
+	}
+
+	sectionIdx := file.SectionIndexContaining(address)
+	if sectionIdx == -1 {
+		return nil
+	}
+
+	fileAddr := file.ToFileAddress(address)
+
+	var nearest *elf.Symbol
+	for _, table := range file.symbolTables {
+		for _, candidate := range table.Symbols {
+			if int(candidate.SectionIndex) != sectionIdx {
+				continue
+			}
+
+			switch candidate.Type() {
+			case elf.SymbolTypeFunction, elf.SymbolTypeObject:
+			default:
+				continue
+			}
+
+			low, _, ok := candidate.AddressRange()
+			if !ok || low > fileAddr {
+				continue
+			}
+
+			if nearest == nil || uint64(low) > nearest.Value {
+				nearest = candidate
+			}
+		}
+	}
+
+	return nearest
+}
+
 func (file *File) FunctionDefinitionEntryContainingAddress(
 	address VirtualAddress,
 ) (
@@ -234,7 +305,7 @@ func (file *File) FunctionDefinitionEntriesWithName(
 func (file *File) LocalVariableEntries(
 	pc VirtualAddress,
 ) (
-	map[string]*dwarf.DebugInfoEntry,
+	[]*dwarf.DebugInfoEntry,
 	error,
 ) {
 	if file.Dwarf == nil {
@@ -258,6 +329,52 @@ func (file *File) VariableEntryWithName(
 	return file.Dwarf.VariableEntryWithName(file.ToFileAddress(pc), name)
 }
 
+func (file *File) TypeEntryWithName(
+	name string,
+) (
+	*dwarf.DebugInfoEntry,
+	error,
+) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	return file.Dwarf.TypeEntryWithName(name)
+}
+
+func (file *File) FunctionDefinitionEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	return file.Dwarf.FunctionDefinitionEntries()
+}
+
+func (file *File) GlobalVariableEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	return file.Dwarf.GlobalVariableEntries()
+}
+
+func (file *File) TypeEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	return file.Dwarf.TypeEntries()
+}
+
 func (file *File) LineEntryAt(
 	address VirtualAddress,
 ) (
@@ -285,6 +402,35 @@ func (file *File) LineEntriesByLine(
 	return file.Dwarf.GetLineEntriesByLine(pathName, int64(line))
 }
 
+func (file *File) InlinedCallSitesByLine(
+	pathName string,
+	line int,
+) (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	if file.Dwarf == nil {
+		return nil, nil
+	}
+
+	return file.Dwarf.InlinedCallSitesByLine(pathName, int64(line))
+}
+
+func (file *File) NextLineWithEntries(
+	pathName string,
+	line int,
+) (
+	int,
+	error,
+) {
+	if file.Dwarf == nil {
+		return 0, nil
+	}
+
+	next, err := file.Dwarf.GetNextLineWithEntries(pathName, int64(line))
+	return int(next), err
+}
+
 func (file *File) ComputeUnwindRulesAt(
 	pc VirtualAddress,
 ) (
@@ -63,12 +63,21 @@ type Files struct {
 
 	Executable *File
 	loaded     map[string]*File
+
+	// When true, separate debug info is loaded for newly loaded shared
+	// libraries without embedded debug info.  Enabled by default.
+	LoadSeparateDebugInfo bool
+
+	// The global directories searched for separate debug files.
+	DebugFileDirectories []string
 }
 
 func NewFiles(mem *memory.VirtualMemory) *Files {
 	return &Files{
-		memory: mem,
-		loaded: map[string]*File{},
+		memory:                mem,
+		loaded:                map[string]*File{},
+		LoadSeparateDebugInfo: true,
+		DebugFileDirectories:  []string{DefaultDebugFileDirectory},
 	}
 }
 
@@ -102,14 +111,31 @@ func (files *Files) LoadExecutable(pid int) (*File, error) {
 	return file, nil
 }
 
-func (files *Files) UpdateFiles() (VirtualAddress, bool, error) {
-	notifyAddress, loadedLibs, err := files.ReadRendezvousInfo()
+// Discard all loaded files and reload the executable.  This is used when the
+// process executed a new program (execve), which replaced the process'
+// address space.
+func (files *Files) ReloadExecutable(pid int) (*File, error) {
+	files.Executable = nil
+	files.loaded = map[string]*File{}
+
+	return files.LoadExecutable(pid)
+}
+
+// Returns the notify function address, and the newly loaded / unloaded
+// files.  Newly loaded files are in link map (i.e., load) order.
+func (files *Files) UpdateFiles() (
+	VirtualAddress,
+	[]*File, // loaded
+	[]*File, // unloaded
+	error,
+) {
+	notifyAddress, loadedLibs, names, err := files._readRendezvousInfo()
 	if err != nil {
-		return 0, false, err
+		return 0, nil, nil, fmt.Errorf("failed to read rendezvous info: %w", err)
 	}
 
-	modified := false
-	for name, _ := range files.loaded {
+	unloaded := []*File{}
+	for name, file := range files.loaded {
 		if name == "" {
 			continue
 		}
@@ -117,16 +143,25 @@ func (files *Files) UpdateFiles() (VirtualAddress, bool, error) {
 		_, ok := loadedLibs[name]
 		if !ok {
 			delete(files.loaded, name)
-			modified = true
+			unloaded = append(unloaded, file)
 		}
 	}
 
-	for name, address := range loadedLibs {
+	sort.Slice(
+		unloaded,
+		func(i int, j int) bool {
+			return unloaded[i].LoadBias < unloaded[j].LoadBias
+		})
+
+	loaded := []*File{}
+	for _, name := range names {
 		_, ok := files.loaded[name]
 		if ok {
 			continue
 		}
 
+		address := loadedLibs[name]
+
 		var file *File
 		var err error
 		if name == VDSOFileName {
@@ -136,14 +171,27 @@ func (files *Files) UpdateFiles() (VirtualAddress, bool, error) {
 		}
 
 		if err != nil {
-			return 0, false, fmt.Errorf("failed to load elf file (%s): %w", name, err)
+			return 0, nil, nil, fmt.Errorf(
+				"failed to load elf file (%s): %w",
+				name,
+				err)
 		}
 
-		modified = true
+		if files.LoadSeparateDebugInfo && name != VDSOFileName {
+			err = file.loadSeparateDebugInfo(files.DebugFileDirectories)
+			if err != nil {
+				return 0, nil, nil, fmt.Errorf(
+					"failed to load separate debug info (%s): %w",
+					name,
+					err)
+			}
+		}
+
+		loaded = append(loaded, file)
 		files.loaded[name] = file
 	}
 
-	return notifyAddress, modified, nil
+	return notifyAddress, loaded, unloaded, nil
 }
 
 // NOTE: the dynamic linker's rendezvous information is only valid after the
@@ -153,7 +201,7 @@ func (files *Files) ReadRendezvousInfo() (
 	map[string]VirtualAddress, // loaded libraries
 	error,
 ) {
-	addr, libs, err := files._readRendezvousInfo()
+	addr, libs, _, err := files._readRendezvousInfo()
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to read rendezvous info: %w", err)
 	}
@@ -163,17 +211,18 @@ func (files *Files) ReadRendezvousInfo() (
 func (files *Files) _readRendezvousInfo() (
 	VirtualAddress, // notify function address
 	map[string]VirtualAddress, // loaded libraries
+	[]string, // loaded library names, in link map order
 	error,
 ) {
 	rendezvousAddress, err := files.LocateRendezvousAddress()
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, nil, err
 	}
 
 	rendezvousBytes := make([]byte, debugRendezvousSize)
 	n, err := files.memory.Read(rendezvousAddress, rendezvousBytes)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to read debug rendezvous: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to read debug rendezvous: %w", err)
 	}
 	if n != debugRendezvousSize {
 		panic("should never happen")
@@ -182,25 +231,26 @@ func (files *Files) _readRendezvousInfo() (
 	rendezvous := &debugRendezvous{}
 	n, err = binary.Decode(rendezvousBytes, binary.LittleEndian, rendezvous)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to decode debug rendezvous: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to decode debug rendezvous: %w", err)
 	}
 	if n != debugRendezvousSize {
 		panic("should never happen")
 	}
 
 	if rendezvous.Version < 1 || 2 < rendezvous.Version {
-		return 0, nil, fmt.Errorf(
+		return 0, nil, nil, fmt.Errorf(
 			"invalid debug rendezvous version (%d)",
 			rendezvous.Version)
 	}
 
 	if rendezvous.State < 0 || 2 < rendezvous.State {
-		return 0, nil, fmt.Errorf(
+		return 0, nil, nil, fmt.Errorf(
 			"invalid debug rendezvous state (%d)",
 			rendezvous.State)
 	}
 
 	loadedLibs := map[string]VirtualAddress{}
+	names := []string{}
 
 	linkMapBytes := make([]byte, linkMapEntrySize)
 	linkMap := &linkMapEntry{}
@@ -211,7 +261,7 @@ func (files *Files) _readRendezvousInfo() (
 	for address != 0 {
 		n, err := files.memory.Read(address, linkMapBytes)
 		if err != nil {
-			return 0, nil, fmt.Errorf("failed to read link map entry: %w", err)
+			return 0, nil, nil, fmt.Errorf("failed to read link map entry: %w", err)
 		}
 		if n != linkMapEntrySize {
 			panic("should never happen")
@@ -219,7 +269,7 @@ func (files *Files) _readRendezvousInfo() (
 
 		n, err = binary.Decode(linkMapBytes, binary.LittleEndian, linkMap)
 		if err != nil {
-			return 0, nil, fmt.Errorf("failed to decode link map entry: %w", err)
+			return 0, nil, nil, fmt.Errorf("failed to decode link map entry: %w", err)
 		}
 		if n != linkMapEntrySize {
 			panic("should never happen")
@@ -228,20 +278,25 @@ func (files *Files) _readRendezvousInfo() (
 		// NOTE: number of bytes read could be less than the full buffer size
 		n, err = files.memory.Read(linkMap.NameString, nameBytes)
 		if err != nil {
-			return 0, nil, fmt.Errorf("failed to read link map entry name: %w", err)
+			return 0, nil, nil, fmt.Errorf("failed to read link map entry name: %w", err)
 		}
 
 		end := bytes.IndexByte(nameBytes[:n], 0)
 		if end == -1 {
-			return 0, nil, fmt.Errorf("link map entry name not zero terminated")
+			return 0, nil, nil, fmt.Errorf("link map entry name not zero terminated")
 		}
 
-		loadedLibs[string(nameBytes[:end])] = linkMap.Location
+		name := string(nameBytes[:end])
+		_, ok := loadedLibs[name]
+		if !ok {
+			names = append(names, name)
+		}
+		loadedLibs[name] = linkMap.Location
 
 		address = linkMap.NextEntry
 	}
 
-	return rendezvous.NotifyFunction, loadedLibs, nil
+	return rendezvous.NotifyFunction, loadedLibs, names, nil
 }
 
 func (files *Files) LocateRendezvousAddress() (VirtualAddress, error) {
@@ -286,7 +341,12 @@ func (files *Files) LocateRendezvousAddress() (VirtualAddress, error) {
 	return 0, ErrRendezvousAddressNotFound
 }
 
+// Returns zero if the executable is not loaded.
 func (files *Files) EntryPoint() VirtualAddress {
+	if files.Executable == nil {
+		return 0
+	}
+
 	return files.Executable.ToVirtualAddress(
 		files.Executable.EntryPointFileAddress())
 }
@@ -340,7 +400,7 @@ func (files *Files) ToVirtualAddress(
 	error,
 ) {
 	for _, file := range files.loaded {
-		if file.File == elfFile {
+		if file.File == elfFile || file.debugElf == elfFile {
 			return file.ToVirtualAddress(fileAddress), nil
 		}
 	}
@@ -464,7 +524,7 @@ func (files *Files) FunctionDefinitionEntriesWithName(
 func (files *Files) LocalVariableEntries(
 	pc VirtualAddress,
 ) (
-	map[string]*dwarf.DebugInfoEntry,
+	[]*dwarf.DebugInfoEntry,
 	error,
 ) {
 	for _, file := range files.loaded {
@@ -499,6 +559,73 @@ func (files *Files) VariableEntryWithName(
 	return nil, nil
 }
 
+func (files *Files) TypeEntryWithName(
+	name string,
+) (
+	*dwarf.DebugInfoEntry,
+	error,
+) {
+	for _, file := range files.loaded {
+		entry, err := file.TypeEntryWithName(name)
+		if entry != nil || err != nil {
+			return entry, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (files *Files) FunctionDefinitionEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	result := []*dwarf.DebugInfoEntry{}
+	for _, file := range files.loaded {
+		entries, err := file.FunctionDefinitionEntries()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entries...)
+	}
+
+	return result, nil
+}
+
+func (files *Files) GlobalVariableEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	result := []*dwarf.DebugInfoEntry{}
+	for _, file := range files.loaded {
+		entries, err := file.GlobalVariableEntries()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entries...)
+	}
+
+	return result, nil
+}
+
+func (files *Files) TypeEntries() (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	result := []*dwarf.DebugInfoEntry{}
+	for _, file := range files.loaded {
+		entries, err := file.TypeEntries()
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entries...)
+	}
+
+	return result, nil
+}
+
 func (files *Files) LineEntryAt(
 	address VirtualAddress,
 ) (
@@ -534,6 +661,49 @@ func (files *Files) LineEntriesByLine(
 	return result, nil
 }
 
+func (files *Files) InlinedCallSitesByLine(
+	pathName string,
+	line int,
+) (
+	[]*dwarf.DebugInfoEntry,
+	error,
+) {
+	result := []*dwarf.DebugInfoEntry{}
+	for _, file := range files.loaded {
+		entries, err := file.InlinedCallSitesByLine(pathName, line)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+	}
+
+	return result, nil
+}
+
+// Returns the smallest line number greater than the specified line that has
+// line entries, or zero if no such line exists.
+func (files *Files) NextLineWithEntries(
+	pathName string,
+	line int,
+) (
+	int,
+	error,
+) {
+	result := 0
+	for _, file := range files.loaded {
+		next, err := file.NextLineWithEntries(pathName, line)
+		if err != nil {
+			return 0, err
+		}
+
+		if next != 0 && (result == 0 || next < result) {
+			result = next
+		}
+	}
+
+	return result, nil
+}
+
 func (files *Files) ComputeUnwindRulesAt(
 	pc VirtualAddress,
 ) (
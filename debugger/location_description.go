@@ -0,0 +1,89 @@
+package debugger
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/dwarf"
+)
+
+// Returns a human readable description of the dwarf evaluated location.  The
+// first line is a summary, followed by one labeled line per location chunk.
+// For composite (DW_OP_piece / DW_OP_bit_piece) locations, each chunk line
+// also describes which bits of the variable the chunk provides.
+func DescribeLocation(location dwarf.Location) []string {
+	switch len(location) {
+	case 0:
+		return []string{"location: none (address and contents are unknown)"}
+	case 1:
+		chunk := location[0]
+		if chunk.BitSize == 0 && chunk.BitOffset == 0 {
+			return []string{
+				"location: simple",
+				"  " + describeLocationChunk(chunk),
+			}
+		}
+	}
+
+	result := []string{
+		fmt.Sprintf("location: composite (%d chunks)", len(location)),
+	}
+
+	variableBitOffset := uint64(0)
+	for idx, chunk := range location {
+		piece := "entire value"
+		if chunk.BitSize != 0 {
+			piece = fmt.Sprintf(
+				"variable bits [%d, %d)",
+				variableBitOffset,
+				variableBitOffset+chunk.BitSize)
+			variableBitOffset += chunk.BitSize
+		}
+
+		result = append(
+			result,
+			fmt.Sprintf(
+				"  chunk %d: %s (%s, bit size: %d, bit offset: %d)",
+				idx,
+				describeLocationChunk(chunk),
+				piece,
+				chunk.BitSize,
+				chunk.BitOffset))
+	}
+
+	return result
+}
+
+func describeLocationChunk(chunk dwarf.LocationChunk) string {
+	switch chunk.Kind {
+	case dwarf.UnavailableLocation:
+		return "unavailable (optimized out)"
+	case dwarf.AddressLocation:
+		return "memory address: " + VirtualAddress(chunk.Value).String()
+	case dwarf.RegisterLocation:
+		name := "<unknown>"
+		spec, ok := registers.ById(dwarf.RegisterId(chunk.Value))
+		if ok {
+			name = spec.Name
+		}
+		return fmt.Sprintf("register: %s (dwarf register %d)", name, chunk.Value)
+	case dwarf.ImplicitLiteralLocation:
+		return fmt.Sprintf(
+			"implicit value: 0x%x (%d)",
+			chunk.Value,
+			chunk.Value)
+	case dwarf.ImplicitDataLocation:
+		bytes := make([]string, 0, len(chunk.Data))
+		for _, b := range chunk.Data {
+			bytes = append(bytes, fmt.Sprintf("%02x", b))
+		}
+		return fmt.Sprintf(
+			"implicit data: [%s] (%d bytes)",
+			strings.Join(bytes, " "),
+			len(chunk.Data))
+	default:
+		return fmt.Sprintf("<unknown location kind %s>", chunk.Kind)
+	}
+}
@@ -1,45 +1,55 @@
 package memory
 
 import (
-	"bytes"
 	"fmt"
 
-	"golang.org/x/arch/x86/x86asm"
-
 	. "github.com/pattyshack/bad/debugger/common"
 )
 
-const (
-	maxX64InstructionLength = 15
-)
+// NOTE: The architecture (see arch package) implements this interface.
+type InstructionDecoder interface {
+	MaxInstructionLength() int
 
-var (
-	endbr64 = []byte{0xf3, 0x0f, 0x01e, 0xfa}
-	endbr32 = []byte{0xf3, 0x0f, 0x01e, 0xfb}
-)
+	// Decodes the instruction at the beginning of data.
+	DecodeInstruction(data []byte) (Instruction, error)
+}
+
+type Instruction interface {
+	Length() int
+
+	IsCall() bool
+
+	// True if the instruction may transfer control to an instruction other
+	// than the next sequential instruction (e.g., jumps, calls, returns and
+	// syscalls).
+	IsBranch() bool
+
+	// Formats the instruction in assembly syntax.  The address is used for
+	// resolving relative addresses.
+	Format(address VirtualAddress) string
+}
 
 type DisassembledInstruction struct {
 	Address VirtualAddress
 
-	IsEndbr64 bool
-	IsEndbr32 bool
-
-	x86asm.Inst
+	Instruction
 }
 
 func (inst DisassembledInstruction) String() string {
-	if inst.IsEndbr64 {
-		return fmt.Sprintf("0x%016x: endbr64", uint64(inst.Address))
-	} else if inst.IsEndbr32 {
-		return fmt.Sprintf("0x%016x: endbr32", uint64(inst.Address))
-	}
-
 	return fmt.Sprintf(
 		"0x%016x: %s",
 		uint64(inst.Address),
-		x86asm.GNUSyntax(inst.Inst, uint64(inst.Address), nil))
+		inst.Format(inst.Address))
 }
 
+const (
+	// The maximum size of an address range disassembled by DisassembleRange.
+	MaxDisassembleRangeSize = 1 << 20
+
+	// Memory is read (and decoded) in chunks of this size.
+	disassembleChunkSize = 4096
+)
+
 type StopSiteBytes interface {
 	// If an enabled stop site is in the range
 	//    [startAddr, startAddr + len(memorySlice))
@@ -51,15 +61,18 @@ type StopSiteBytes interface {
 type Disassembler struct {
 	memory    *VirtualMemory
 	stopSites StopSiteBytes
+	decoder   InstructionDecoder
 }
 
 func NewDisassembler(
 	memory *VirtualMemory,
 	stopSites StopSiteBytes,
+	decoder InstructionDecoder,
 ) *Disassembler {
 	return &Disassembler{
 		memory:    memory,
 		stopSites: stopSites,
+		decoder:   decoder,
 	}
 }
 
@@ -78,50 +91,115 @@ func (disassembler *Disassembler) Disassemble(
 		return nil, nil
 	}
 
-	data := make([]byte, numInstructions*maxX64InstructionLength)
-	_, err := disassembler.memory.Read(startAddress, data)
-	if err != nil {
-		return nil, err
+	maxInstructionLength := disassembler.decoder.MaxInstructionLength()
+	return disassembler.disassemble(
+		startAddress,
+		numInstructions*maxInstructionLength,
+		func(address VirtualAddress, numDecoded int) bool {
+			return numDecoded < numInstructions
+		})
+}
+
+// Disassemble instructions in [startAddress, endAddress).  Note that the last
+// instruction may extend past the end address.
+func (disassembler *Disassembler) DisassembleRange(
+	startAddress VirtualAddress,
+	endAddress VirtualAddress,
+) (
+	[]DisassembledInstruction,
+	error,
+) {
+	if endAddress <= startAddress {
+		return nil, fmt.Errorf(
+			"Invalid address range to disassemble: [%s, %s)",
+			startAddress,
+			endAddress)
+	}
+
+	if endAddress-startAddress > MaxDisassembleRangeSize {
+		return nil, fmt.Errorf(
+			"Address range to disassemble [%s, %s) is too large. "+
+				"expected at most %d bytes",
+			startAddress,
+			endAddress,
+			MaxDisassembleRangeSize)
 	}
 
-	disassembler.stopSites.ReplaceStopSiteBytes(startAddress, data)
+	maxInstructionLength := disassembler.decoder.MaxInstructionLength()
+	return disassembler.disassemble(
+		startAddress,
+		int(endAddress-startAddress)+maxInstructionLength-1,
+		func(address VirtualAddress, numDecoded int) bool {
+			return address < endAddress
+		})
+}
+
+// Memory in [startAddress, startAddress + maxNumBytes) is read in bounded
+// chunks, and decoding stops at the first unreadable (e.g., unmapped) byte.
+// This only returns an error if the start address is unreadable.
+func (disassembler *Disassembler) disassemble(
+	startAddress VirtualAddress,
+	maxNumBytes int,
+	shouldContinue func(VirtualAddress, int) bool,
+) (
+	[]DisassembledInstruction,
+	error,
+) {
+	maxInstructionLength := disassembler.decoder.MaxInstructionLength()
+	buffer := make([]byte, min(maxNumBytes, disassembleChunkSize))
+
+	data := buffer[:0] // read, but not yet decoded, bytes starting at address
+	readAddress := startAddress
+	numUnread := maxNumBytes
 
 	address := startAddress
-	result := make([]DisassembledInstruction, 0, numInstructions)
-	for len(data) > 0 && len(result) < numInstructions {
-		var inst x86asm.Inst
-		isEndbr64 := false
-		isEndbr32 := false
-		length := 0
-		if len(data) >= len(endbr64) &&
-			bytes.Equal(data[:len(endbr64)], endbr64) {
-
-			isEndbr64 = true
-			length = len(endbr64)
-		} else if len(data) >= len(endbr32) &&
-			bytes.Equal(data[:len(endbr32)], endbr32) {
-
-			isEndbr32 = true
-			length = len(endbr32)
-		} else {
-			var err error
-			inst, err = x86asm.Decode(data, 64)
+	result := []DisassembledInstruction{}
+	for shouldContinue(address, len(result)) {
+		if len(data) < maxInstructionLength && numUnread > 0 {
+			numBuffered := copy(buffer, data)
+			chunk := buffer[numBuffered:min(len(buffer), numBuffered+numUnread)]
+
+			count, err := disassembler.memory.Read(readAddress, chunk)
 			if err != nil {
-				break
+				if len(result) == 0 && numBuffered == 0 {
+					return nil, err
+				}
+				count = 0
 			}
 
-			length = inst.Len
+			disassembler.stopSites.ReplaceStopSiteBytes(
+				readAddress,
+				chunk[:count])
+
+			data = buffer[:numBuffered+count]
+			readAddress += VirtualAddress(count)
+			numUnread -= count
+
+			// The rest of the range is unreadable.
+			if count < len(chunk) {
+				numUnread = 0
+			}
+		}
+
+		if len(data) == 0 {
+			break
+		}
+
+		// NOTE: the instruction is truncated if it extends past the readable
+		// bytes.
+		inst, err := disassembler.decoder.DecodeInstruction(data)
+		if err != nil || inst.Length() > len(data) {
+			break
 		}
 
 		result = append(
 			result,
 			DisassembledInstruction{
-				Address:   address,
-				IsEndbr64: isEndbr64,
-				IsEndbr32: isEndbr32,
-				Inst:      inst,
+				Address:     address,
+				Instruction: inst,
 			})
 
+		length := inst.Length()
 		data = data[length:]
 		address += VirtualAddress(length)
 	}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/ptrace/ptracetest"
+)
+
+// Each instruction's length is (first byte % 4) + 1.  Note that zero bytes
+// decode into valid (1 byte) instructions.
+type fakeDecoder struct{}
+
+func (fakeDecoder) MaxInstructionLength() int {
+	return 4
+}
+
+func (fakeDecoder) DecodeInstruction(data []byte) (Instruction, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no data")
+	}
+
+	inst := fakeInstruction{
+		first:  data[0],
+		length: int(data[0]%4) + 1,
+	}
+
+	if inst.length > len(data) {
+		return nil, fmt.Errorf("truncated instruction")
+	}
+
+	return inst, nil
+}
+
+type fakeInstruction struct {
+	first  byte
+	length int
+}
+
+func (inst fakeInstruction) Length() int {
+	return inst.length
+}
+
+func (fakeInstruction) IsCall() bool {
+	return false
+}
+
+func (fakeInstruction) IsBranch() bool {
+	return false
+}
+
+func (inst fakeInstruction) Format(address VirtualAddress) string {
+	return fmt.Sprintf("inst%d 0x%02x", inst.length, inst.first)
+}
+
+type noStopSites struct{}
+
+func (noStopSites) ReplaceStopSiteBytes(VirtualAddress, []byte) {}
+
+func newFakeDisassembler(content []byte) *Disassembler {
+	tracer := ptracetest.NewFakeTracer(1)
+	tracer.MapMemory(0x10000, content)
+
+	return NewDisassembler(
+		New(tracer.Pid, tracer),
+		noStopSites{},
+		fakeDecoder{})
+}
+
+// Checks that the instructions are contiguous, starting at the start
+// address, and are decoded from the correct bytes (content[idx] = idx % 256).
+func checkContiguous(
+	t *testing.T,
+	start VirtualAddress,
+	instructions []DisassembledInstruction,
+) VirtualAddress {
+	address := start
+	for _, inst := range instructions {
+		expect.Equal(t, address, inst.Address)
+		expect.Equal(
+			t,
+			byte(address-0x10000),
+			inst.Instruction.(fakeInstruction).first)
+
+		address += VirtualAddress(inst.Length())
+	}
+
+	return address
+}
+
+func (MemorySuite) TestDisassembleRange(t *testing.T) {
+	content := make([]byte, 3*disassembleChunkSize)
+	for idx := range content {
+		content[idx] = byte(idx)
+	}
+	disassembler := newFakeDisassembler(content)
+
+	// 0x00, 0x01, 0x03, 0x07 (0x04 - 0x06 are part of 0x03)
+	instructions, err := disassembler.DisassembleRange(0x10000, 0x10004)
+	expect.Nil(t, err)
+	expect.Equal(t, 3, len(instructions))
+	end := checkContiguous(t, 0x10000, instructions)
+	expect.Equal(t, 0x10007, end)
+
+	// The range spans multiple chunks, with instructions straddling the chunk
+	// boundaries.
+	instructions, err = disassembler.DisassembleRange(
+		0x10001,
+		0x10000+2*disassembleChunkSize+10)
+	expect.Nil(t, err)
+	end = checkContiguous(t, 0x10001, instructions)
+	expect.True(t, end >= 0x10000+2*disassembleChunkSize+10)
+	expect.True(t, end < 0x10000+2*disassembleChunkSize+10+4)
+}
+
+func (MemorySuite) TestDisassembleInvalidRange(t *testing.T) {
+	disassembler := newFakeDisassembler(make([]byte, 16))
+
+	_, err := disassembler.DisassembleRange(0x10008, 0x10000)
+	expect.Error(t, err, "Invalid address range")
+
+	_, err = disassembler.DisassembleRange(0x10000, 0x10000)
+	expect.Error(t, err, "Invalid address range")
+
+	_, err = disassembler.DisassembleRange(0, 0xffffffffffff)
+	expect.Error(t, err, "too large")
+
+	_, err = disassembler.DisassembleRange(
+		0x10000,
+		0x10000+MaxDisassembleRangeSize+1)
+	expect.Error(t, err, "too large")
+
+	// Unreadable start address
+	_, err = disassembler.DisassembleRange(0x20000, 0x20010)
+	expect.NotNil(t, err)
+}
+
+func (MemorySuite) TestDisassemblePartiallyUnmappedRange(t *testing.T) {
+	// 0x02 (3 bytes) straddles the end of the mapped region.
+	content := []byte{0x00, 0x01, 0xff, 0x00, 0x00, 0x00, 0x02, 0x00}
+	disassembler := newFakeDisassembler(content)
+
+	instructions, err := disassembler.DisassembleRange(
+		0x10000,
+		0x10000+MaxDisassembleRangeSize)
+	expect.Nil(t, err)
+
+	// The unmapped tail is not decoded (as zero filled 1 byte instructions),
+	// and the truncated 0x02 instruction is dropped.
+	expected := []VirtualAddress{0x10000, 0x10001, 0x10003, 0x10004, 0x10005}
+	expect.Equal(t, len(expected), len(instructions))
+	for idx, inst := range instructions {
+		expect.Equal(t, expected[idx], inst.Address)
+	}
+
+	instructions, err = disassembler.Disassemble(0x10004, 100)
+	expect.Nil(t, err)
+	expect.Equal(t, 2, len(instructions))
+	expect.Equal(t, 0x10004, instructions[0].Address)
+	expect.Equal(t, 0x10005, instructions[1].Address)
+}
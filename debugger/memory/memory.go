@@ -4,27 +4,135 @@ import (
 	"fmt"
 
 	. "github.com/pattyshack/bad/debugger/common"
-	"github.com/pattyshack/bad/ptrace"
 )
 
+const cachePageSize = 4096
+
+// The tracer operations used for accessing the process' virtual memory.
+// This is implemented by ptrace.Tracer (and ptracetest.FakeTracer for testing).
+type Tracer interface {
+	ReadFromVirtualMemory(addr uintptr, data []byte) (int, error)
+	PokeData(addr uintptr, data []byte) (int, error)
+}
+
 type VirtualMemory struct {
-	processTracer *ptrace.Tracer
+	pid           int
+	processTracer Tracer
+
+	// Pages read while the process is stopped, keyed by page address.  nil
+	// when caching is disabled.
+	//
+	// NOTE: the cache is only valid for the current stop, and must be
+	// invalidated whenever any thread is resumed / stepped.
+	cachedPages map[VirtualAddress][]byte
 }
 
-func New(processTracer *ptrace.Tracer) *VirtualMemory {
+func New(pid int, processTracer Tracer) *VirtualMemory {
 	return &VirtualMemory{
+		pid:           pid,
 		processTracer: processTracer,
+		cachedPages:   map[VirtualAddress][]byte{},
 	}
 }
 
+// Rebinds the virtual memory to a different process, e.g., a forked copy of
+// the process which shares the same address space layout.
+func (vm *VirtualMemory) Rebind(pid int, processTracer Tracer) {
+	vm.pid = pid
+	vm.processTracer = processTracer
+	vm.InvalidateCache()
+}
+
+func (vm *VirtualMemory) IsCacheEnabled() bool {
+	return vm.cachedPages != nil
+}
+
+func (vm *VirtualMemory) SetCacheEnabled(enabled bool) {
+	if enabled == vm.IsCacheEnabled() {
+		return
+	}
+
+	if enabled {
+		vm.cachedPages = map[VirtualAddress][]byte{}
+	} else {
+		vm.cachedPages = nil
+	}
+}
+
+// Discard all cached pages.  This must be called before resuming the process.
+func (vm *VirtualMemory) InvalidateCache() {
+	if len(vm.cachedPages) == 0 {
+		return
+	}
+
+	vm.cachedPages = map[VirtualAddress][]byte{}
+}
+
+func (vm *VirtualMemory) invalidateCachedPages(
+	addr VirtualAddress,
+	size int,
+) {
+	if len(vm.cachedPages) == 0 || size == 0 {
+		return
+	}
+
+	end := addr + VirtualAddress(size)
+	for page := addr &^ (cachePageSize - 1); page < end; page += cachePageSize {
+		delete(vm.cachedPages, page)
+	}
+}
+
+// Returns nil if the page is not fully readable.
+func (vm *VirtualMemory) cachedPage(pageAddr VirtualAddress) []byte {
+	page, ok := vm.cachedPages[pageAddr]
+	if ok {
+		return page
+	}
+
+	page = make([]byte, cachePageSize)
+	count, err := vm.processTracer.ReadFromVirtualMemory(uintptr(pageAddr), page)
+	if err != nil || count != cachePageSize {
+		return nil
+	}
+
+	vm.cachedPages[pageAddr] = page
+	return page
+}
+
 func (vm *VirtualMemory) Read(addr VirtualAddress, out []byte) (int, error) {
+	if vm.cachedPages == nil {
+		return vm.read(addr, out)
+	}
+
+	count := 0
+	for count < len(out) {
+		current := addr + VirtualAddress(count)
+		page := vm.cachedPage(current &^ (cachePageSize - 1))
+		if page == nil {
+			// NOTE: fallback to uncached read for the remaining (possibly
+			// partially readable) range.  Similar to process_vm_readv, reading
+			// past the readable range is a partial read rather than an error.
+			n, err := vm.read(current, out[count:])
+			if err != nil && count > 0 {
+				return count, nil
+			}
+			return count + n, err
+		}
+
+		count += copy(out[count:], page[current%cachePageSize:])
+	}
+
+	return count, nil
+}
+
+func (vm *VirtualMemory) read(addr VirtualAddress, out []byte) (int, error) {
 	count, err := vm.processTracer.ReadFromVirtualMemory(uintptr(addr), out)
 	if err != nil {
 		return 0, fmt.Errorf(
 			"failed to read from virtual memory at %s (%d) for process %d: %w",
 			addr,
 			len(out),
-			vm.processTracer.Pid,
+			vm.pid,
 			err)
 	}
 
@@ -32,13 +140,16 @@ func (vm *VirtualMemory) Read(addr VirtualAddress, out []byte) (int, error) {
 }
 
 func (vm *VirtualMemory) Write(addr VirtualAddress, data []byte) (int, error) {
+	// NOTE: the write may partially succeed.
+	vm.invalidateCachedPages(addr, len(data))
+
 	count, err := vm.processTracer.PokeData(uintptr(addr), data)
 	if err != nil {
 		return 0, fmt.Errorf(
 			"failed to write to virtual memory at %s (%d) for process %d: %w",
 			addr,
 			len(data),
-			vm.processTracer.Pid,
+			vm.pid,
 			err)
 	}
 
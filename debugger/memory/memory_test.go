@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+
+	"github.com/pattyshack/bad/ptrace/ptracetest"
+)
+
+type MemorySuite struct{}
+
+func TestMemory(t *testing.T) {
+	suite.RunTests(t, &MemorySuite{})
+}
+
+func newFakeMemory(cacheEnabled bool) (*ptracetest.FakeTracer, *VirtualMemory) {
+	tracer := ptracetest.NewFakeTracer(1)
+
+	content := make([]byte, 2*cachePageSize)
+	for idx := range content {
+		content[idx] = byte(idx)
+	}
+	tracer.MapMemory(0x10000, content)
+
+	vm := New(tracer.Pid, tracer)
+	vm.SetCacheEnabled(cacheEnabled)
+	return tracer, vm
+}
+
+func (MemorySuite) TestReadWrite(t *testing.T) {
+	for _, cacheEnabled := range []bool{false, true} {
+		_, vm := newFakeMemory(cacheEnabled)
+
+		out := make([]byte, 4)
+		n, err := vm.Read(0x10ffe, out)
+		expect.Nil(t, err)
+		expect.Equal(t, 4, n)
+		expect.Equal(t, []byte{0xfe, 0xff, 0x00, 0x01}, out)
+
+		n, err = vm.Write(0x10fff, []byte{0xaa, 0xbb})
+		expect.Nil(t, err)
+		expect.Equal(t, 2, n)
+
+		n, err = vm.Read(0x10ffe, out)
+		expect.Nil(t, err)
+		expect.Equal(t, 4, n)
+		expect.Equal(t, []byte{0xfe, 0xaa, 0xbb, 0x01}, out)
+	}
+}
+
+func (MemorySuite) TestPartialRead(t *testing.T) {
+	for _, cacheEnabled := range []bool{false, true} {
+		_, vm := newFakeMemory(cacheEnabled)
+
+		out := make([]byte, 8)
+		n, err := vm.Read(0x11ffc, out)
+		expect.Nil(t, err)
+		expect.Equal(t, 4, n)
+		expect.Equal(t, []byte{0xfc, 0xfd, 0xfe, 0xff}, out[:n])
+
+		_, err = vm.Read(0x20000, out)
+		expect.Error(t, err, "failed to read from virtual memory at 0x0000000000020000")
+
+		_, err = vm.Write(0x20000, []byte{1})
+		expect.Error(t, err, "failed to write to virtual memory at 0x0000000000020000")
+	}
+}
+
+func (MemorySuite) TestCache(t *testing.T) {
+	tracer, vm := newFakeMemory(true)
+
+	out := make([]byte, 2)
+	_, err := vm.Read(0x10010, out)
+	expect.Nil(t, err)
+	expect.Equal(t, []byte{0x10, 0x11}, out)
+
+	// Modify the tracee's memory behind the debugger's back.
+	_, err = tracer.PokeData(0x10010, []byte{0xca, 0xfe})
+	expect.Nil(t, err)
+
+	_, err = vm.Read(0x10010, out)
+	expect.Nil(t, err)
+	expect.Equal(t, []byte{0x10, 0x11}, out)
+
+	vm.InvalidateCache()
+
+	_, err = vm.Read(0x10010, out)
+	expect.Nil(t, err)
+	expect.Equal(t, []byte{0xca, 0xfe}, out)
+}
@@ -0,0 +1,155 @@
+package debugger
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const (
+	MaxMemoryCompareSize = 16 * 1024 * 1024
+
+	DefaultMemoryCompareLimit = 32
+)
+
+type MemoryCompareDiff struct {
+	Offset int
+	Left   byte
+	Right  byte
+}
+
+type MemoryComparison struct {
+	Address VirtualAddress
+	Size    int
+
+	// The total number of differing bytes, which may exceed len(Diffs).
+	NumDiffs int
+
+	// The first (up to MemoryCompareLimit) differing bytes, in offset order.
+	Diffs []MemoryCompareDiff
+}
+
+// Compares the size bytes regions at address (left) and other (right).
+func (db *Debugger) CompareMemory(
+	address VirtualAddress,
+	other VirtualAddress,
+	size int,
+) (
+	*MemoryComparison,
+	error,
+) {
+	err := checkMemoryCompareSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	left, err := db.readMemoryCompareRegion(address, size)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := db.readMemoryCompareRegion(other, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.compareBytes(address, left, right), nil
+}
+
+// Compares the region at address (left) against the expected content (right),
+// e.g., a file's content.
+func (db *Debugger) CompareMemoryWithBytes(
+	address VirtualAddress,
+	expected []byte,
+) (
+	*MemoryComparison,
+	error,
+) {
+	err := checkMemoryCompareSize(len(expected))
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := db.readMemoryCompareRegion(address, len(expected))
+	if err != nil {
+		return nil, err
+	}
+
+	return db.compareBytes(address, content, expected), nil
+}
+
+func checkMemoryCompareSize(size int) error {
+	if size < 1 || size > MaxMemoryCompareSize {
+		return fmt.Errorf(
+			"%w. invalid memory compare size (%d). expected 1 to %d bytes",
+			ErrInvalidInput,
+			size,
+			MaxMemoryCompareSize)
+	}
+
+	return nil
+}
+
+func (db *Debugger) readMemoryCompareRegion(
+	address VirtualAddress,
+	size int,
+) (
+	[]byte,
+	error,
+) {
+	out := make([]byte, size)
+	n, err := db.VirtualMemory.Read(address, out)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w. failed to read memory at %s: %w",
+			ErrInvalidInput,
+			address,
+			err)
+	}
+	if n != size {
+		return nil, fmt.Errorf(
+			"%w. failed to read memory at %s. read %d out of %d bytes",
+			ErrInvalidInput,
+			address,
+			n,
+			size)
+	}
+
+	// NOTE: hide software break point instructions from the comparison.
+	db.stopSites.ReplaceStopSiteBytes(address, out)
+	return out, nil
+}
+
+func (db *Debugger) compareBytes(
+	address VirtualAddress,
+	left []byte,
+	right []byte,
+) *MemoryComparison {
+	result := &MemoryComparison{
+		Address: address,
+		Size:    len(left),
+	}
+
+	for idx, value := range left {
+		if value == right[idx] {
+			continue
+		}
+
+		result.NumDiffs++
+		if db.MemoryCompareLimit > 0 &&
+			len(result.Diffs) >= db.MemoryCompareLimit {
+
+			continue
+		}
+
+		result.Diffs = append(
+			result.Diffs,
+			MemoryCompareDiff{
+				Offset: idx,
+				Left:   value,
+				Right:  right[idx],
+			})
+	}
+
+	return result
+}
@@ -0,0 +1,180 @@
+package debugger
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const (
+	MaxMemoryWatchSize = 4096
+	MaxMemoryWatches   = 16
+)
+
+// MemoryWatch is a memory region which is re-read every time the process
+// stops.  Unlike watch points, changes to the region do not stop the process.
+type MemoryWatch struct {
+	Id      int64
+	Address VirtualAddress
+	Size    int
+
+	// The region's content as of the last read.  nil if the region was not
+	// readable.
+	snapshot []byte
+}
+
+func (watch *MemoryWatch) String() string {
+	return fmt.Sprintf("%d: %s (%d bytes)", watch.Id, watch.Address, watch.Size)
+}
+
+type MemoryByteDiff struct {
+	Offset int
+	Old    byte
+	New    byte
+}
+
+type MemoryWatchDiff struct {
+	*MemoryWatch
+
+	// Set when the region is no longer readable, in which case Bytes is empty.
+	Err error
+
+	Bytes []MemoryByteDiff
+}
+
+type MemoryWatches struct {
+	nextId  int64
+	watches []*MemoryWatch
+}
+
+func (watches *MemoryWatches) List() []*MemoryWatch {
+	return append([]*MemoryWatch{}, watches.watches...)
+}
+
+func (watches *MemoryWatches) Remove(id int64) bool {
+	for idx, watch := range watches.watches {
+		if watch.Id == id {
+			watches.watches = append(
+				watches.watches[:idx],
+				watches.watches[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Registers the region for diffing and takes its initial snapshot.
+func (db *Debugger) AddMemoryWatch(
+	address VirtualAddress,
+	size int,
+) (
+	*MemoryWatch,
+	error,
+) {
+	if size < 1 || size > MaxMemoryWatchSize {
+		return nil, fmt.Errorf(
+			"%w. invalid memory watch size (%d). expected 1 to %d bytes",
+			ErrInvalidInput,
+			size,
+			MaxMemoryWatchSize)
+	}
+
+	if len(db.MemoryWatches.watches) >= MaxMemoryWatches {
+		return nil, fmt.Errorf(
+			"%w. too many memory watches (max %d)",
+			ErrInvalidInput,
+			MaxMemoryWatches)
+	}
+
+	snapshot, err := db.readMemoryWatchRegion(address, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w. %w", ErrInvalidInput, err)
+	}
+
+	db.MemoryWatches.nextId++
+	watch := &MemoryWatch{
+		Id:       db.MemoryWatches.nextId,
+		Address:  address,
+		Size:     size,
+		snapshot: snapshot,
+	}
+
+	db.MemoryWatches.watches = append(db.MemoryWatches.watches, watch)
+	return watch, nil
+}
+
+// Re-reads all watched regions and returns the regions which changed since
+// the last read.
+func (db *Debugger) DiffMemoryWatches() []MemoryWatchDiff {
+	result := []MemoryWatchDiff{}
+	for _, watch := range db.MemoryWatches.watches {
+		current, err := db.readMemoryWatchRegion(watch.Address, watch.Size)
+		if err != nil {
+			if watch.snapshot != nil {
+				result = append(
+					result,
+					MemoryWatchDiff{
+						MemoryWatch: watch,
+						Err:         err,
+					})
+			}
+			watch.snapshot = nil
+			continue
+		}
+
+		// NOTE: there's nothing to diff against when the region becomes readable
+		// again.
+		previous := watch.snapshot
+		watch.snapshot = current
+		if previous == nil {
+			continue
+		}
+
+		diff := MemoryWatchDiff{
+			MemoryWatch: watch,
+		}
+		for idx, value := range current {
+			if previous[idx] != value {
+				diff.Bytes = append(
+					diff.Bytes,
+					MemoryByteDiff{
+						Offset: idx,
+						Old:    previous[idx],
+						New:    value,
+					})
+			}
+		}
+
+		if len(diff.Bytes) > 0 {
+			result = append(result, diff)
+		}
+	}
+
+	return result
+}
+
+func (db *Debugger) readMemoryWatchRegion(
+	address VirtualAddress,
+	size int,
+) (
+	[]byte,
+	error,
+) {
+	out := make([]byte, size)
+	n, err := db.VirtualMemory.Read(address, out)
+	if err != nil {
+		return nil, err
+	}
+	if n != size {
+		return nil, fmt.Errorf(
+			"failed to read memory watch region at %s. read %d out of %d bytes",
+			address,
+			n,
+			size)
+	}
+
+	// NOTE: hide software break point instructions from the snapshot.
+	db.stopSites.ReplaceStopSiteBytes(address, out)
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package registers
+
+import (
+	"bytes"
+	"strings"
+)
+
+// eflags status / control bits.  See Intel SDM volume 1, section 3.4.3.
+var eflagsBits = []struct {
+	name string
+	bit  uint
+}{
+	{"CF", 0},
+	{"PF", 2},
+	{"AF", 4},
+	{"ZF", 6},
+	{"SF", 7},
+	{"TF", 8},
+	{"IF", 9},
+	{"DF", 10},
+	{"OF", 11},
+	{"NT", 14},
+	{"RF", 16},
+	{"VM", 17},
+	{"AC", 18},
+	{"VIF", 19},
+	{"VIP", 20},
+	{"ID", 21},
+}
+
+// A register whose value differs between two register states.  Old / New is
+// nil if the value is undefined in the corresponding state.
+type Difference struct {
+	Spec
+	Old Value
+	New Value
+}
+
+// Returns the registers whose values differ between the (old) state and the
+// other (new) state, in register order.  Sub-registers (e.g., eax / ax / al)
+// and mm registers are omitted since their changes are implied by the full
+// width registers (rax and st registers respectively).
+func (state State) Diff(other State) []Difference {
+	result := []Difference{}
+	for _, reg := range OrderedSpecs {
+		if reg.Class == GeneralClass && reg.Size < 8 {
+			continue
+		}
+
+		if reg.Class == FloatingPointClass && strings.HasPrefix(reg.Name, "mm") {
+			continue
+		}
+
+		oldValue := state.Value(reg)
+		newValue := other.Value(reg)
+
+		if oldValue == nil && newValue == nil {
+			continue
+		}
+
+		if oldValue != nil &&
+			newValue != nil &&
+			bytes.Equal(oldValue.ToBytes(), newValue.ToBytes()) {
+
+			continue
+		}
+
+		result = append(
+			result,
+			Difference{
+				Spec: reg,
+				Old:  oldValue,
+				New:  newValue,
+			})
+	}
+
+	return result
+}
+
+// Returns the names of the set eflags bits, e.g., "[ PF ZF IF ]".
+func FormatEflags(eflags uint64) string {
+	names := []string{}
+	for _, flag := range eflagsBits {
+		if eflags&(1<<flag.bit) != 0 {
+			names = append(names, flag.name)
+		}
+	}
+
+	return "[ " + strings.Join(append(names, "]"), " ")
+}
+
+// Returns the set (+) / cleared (-) eflags bits, e.g., "+ZF -CF".
+func FormatEflagsChanges(oldEflags uint64, newEflags uint64) string {
+	changes := []string{}
+	for _, flag := range eflagsBits {
+		mask := uint64(1) << flag.bit
+		if oldEflags&mask == newEflags&mask {
+			continue
+		}
+
+		if newEflags&mask != 0 {
+			changes = append(changes, "+"+flag.name)
+		} else {
+			changes = append(changes, "-"+flag.name)
+		}
+	}
+
+	return strings.Join(changes, " ")
+}
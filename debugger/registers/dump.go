@@ -0,0 +1,144 @@
+package registers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/ptrace"
+)
+
+const (
+	DumpFormatVersion = 1
+
+	dumpMagic = "BADREGS\x00"
+)
+
+// The dump format (little endian) is:
+//
+//	header
+//	gpr (user_regs_struct)
+//	fpr (user_fpregs_struct)
+//	dr  (8 uint64)
+type dumpHeader struct {
+	Magic   [8]byte
+	Version uint32
+	GPRSize uint32
+	FPRSize uint32
+	DRSize  uint32
+}
+
+func newDumpHeader() dumpHeader {
+	header := dumpHeader{
+		Version: DumpFormatVersion,
+		GPRSize: uint32(binary.Size(ptrace.UserRegs{})),
+		FPRSize: uint32(binary.Size(ptrace.UserFPRegs{})),
+		DRSize:  uint32(binary.Size([8]uint64{})),
+	}
+	copy(header.Magic[:], dumpMagic)
+	return header
+}
+
+// Dump writes the raw gpr / fpr / debug register structures to the writer.
+func (state State) Dump(writer io.Writer) error {
+	if len(state.undefined) > 0 {
+		return fmt.Errorf(
+			"%w. cannot dump register state with undefined values",
+			ErrInvalidInput)
+	}
+
+	dr := [8]uint64{}
+	for idx, value := range state.dr {
+		dr[idx] = uint64(value)
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, data := range []interface{}{newDumpHeader(), state.gpr, state.fpr, dr} {
+		err := binary.Write(buffer, binary.LittleEndian, data)
+		if err != nil {
+			return fmt.Errorf("failed to encode register state: %w", err)
+		}
+	}
+
+	_, err := writer.Write(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write register state: %w", err)
+	}
+
+	return nil
+}
+
+// LoadDump reads a register state written by Dump.
+func LoadDump(reader io.Reader) (State, error) {
+	expected := newDumpHeader()
+
+	header := dumpHeader{}
+	err := binary.Read(reader, binary.LittleEndian, &header)
+	if err != nil {
+		return State{}, fmt.Errorf(
+			"%w. failed to read register dump header: %w",
+			ErrInvalidInput,
+			err)
+	}
+
+	if header.Magic != expected.Magic {
+		return State{}, fmt.Errorf(
+			"%w. not a register dump (invalid magic)",
+			ErrInvalidInput)
+	}
+
+	if header.Version != expected.Version {
+		return State{}, fmt.Errorf(
+			"%w. unsupported register dump version (%d != %d)",
+			ErrInvalidInput,
+			header.Version,
+			expected.Version)
+	}
+
+	if header.GPRSize != expected.GPRSize ||
+		header.FPRSize != expected.FPRSize ||
+		header.DRSize != expected.DRSize {
+
+		return State{}, fmt.Errorf(
+			"%w. register dump size mismatch "+
+				"(gpr: %d != %d, fpr: %d != %d, dr: %d != %d)",
+			ErrInvalidInput,
+			header.GPRSize,
+			expected.GPRSize,
+			header.FPRSize,
+			expected.FPRSize,
+			header.DRSize,
+			expected.DRSize)
+	}
+
+	state := State{}
+	dr := [8]uint64{}
+	for _, data := range []interface{}{&state.gpr, &state.fpr, &dr} {
+		err := binary.Read(reader, binary.LittleEndian, data)
+		if err != nil {
+			return State{}, fmt.Errorf(
+				"%w. failed to read register dump content: %w",
+				ErrInvalidInput,
+				err)
+		}
+	}
+
+	for idx, value := range dr {
+		state.dr[idx] = uintptr(value)
+	}
+
+	n, err := reader.Read(make([]byte, 1))
+	if n > 0 {
+		return State{}, fmt.Errorf(
+			"%w. unexpected trailing data in register dump",
+			ErrInvalidInput)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return State{}, fmt.Errorf("failed to read register dump: %w", err)
+	}
+
+	return state, nil
+}
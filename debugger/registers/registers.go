@@ -12,11 +12,24 @@ var (
 	userDebugRegistersOffset = uintptr(0) // initialized by init()
 )
 
+// The tracer operations used for accessing the thread's registers.  This is
+// implemented by ptrace.Tracer (and ptracetest.FakeTracer for testing).
+type Tracer interface {
+	GetGeneralRegisters() (*ptrace.UserRegs, error)
+	SetGeneralRegisters(in *ptrace.UserRegs) error
+	GetFloatingPointRegisters() (*ptrace.UserFPRegs, error)
+	SetFloatingPointRegisters(in *ptrace.UserFPRegs) error
+	GetRegset(regset ptrace.Regset, out []byte) (int, error)
+	SetRegset(regset ptrace.Regset, in []byte) (int, error)
+	PeekUserArea(offset uintptr) (uintptr, error)
+	PokeUserArea(offset uintptr, data uintptr) error
+}
+
 type Registers struct {
-	threadTracer *ptrace.Tracer
+	threadTracer Tracer
 }
 
-func New(tracer *ptrace.Tracer) *Registers {
+func New(tracer Tracer) *Registers {
 	return &Registers{
 		threadTracer: tracer,
 	}
@@ -1,12 +1,16 @@
 package registers
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"testing"
 
 	"github.com/pattyshack/gt/testing/expect"
 	"github.com/pattyshack/gt/testing/suite"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/ptrace/ptracetest"
 )
 
 type RegistersSuite struct{}
@@ -1243,6 +1247,52 @@ func (RegistersSuite) TestGs(t *testing.T) {
 	expect.Equal(t, 0x1020304050607080, newState.gpr.Gs)
 }
 
+func (RegistersSuite) TestFsBase(t *testing.T) {
+	fsBase, ok := ByName("fs_base")
+	expect.True(t, ok)
+	expect.Equal(t, 58, fsBase.RegisterId)
+	expect.Equal(t, 8, fsBase.Size)
+
+	state := State{}
+	state.gpr.Fs_base = 0x00007ffff7d8a740
+
+	val := state.Value(fsBase)
+	expect.NotNil(t, val)
+	u64, ok := val.(Uint64)
+	expect.True(t, ok)
+	expect.Equal(t, 0x00007ffff7d8a740, u64.Value)
+
+	newState, err := state.WithValue(
+		fsBase,
+		U64(0x00007ffff7d8b740))
+	expect.Nil(t, err)
+	expect.Equal(t, 0x00007ffff7d8a740, state.gpr.Fs_base)
+	expect.Equal(t, 0x00007ffff7d8b740, newState.gpr.Fs_base)
+}
+
+func (RegistersSuite) TestGsBase(t *testing.T) {
+	gsBase, ok := ByName("gs_base")
+	expect.True(t, ok)
+	expect.Equal(t, 59, gsBase.RegisterId)
+	expect.Equal(t, 8, gsBase.Size)
+
+	state := State{}
+	state.gpr.Gs_base = 0x00007ffff7d8a740
+
+	val := state.Value(gsBase)
+	expect.NotNil(t, val)
+	u64, ok := val.(Uint64)
+	expect.True(t, ok)
+	expect.Equal(t, 0x00007ffff7d8a740, u64.Value)
+
+	newState, err := state.WithValue(
+		gsBase,
+		U64(0x00007ffff7d8b740))
+	expect.Nil(t, err)
+	expect.Equal(t, 0x00007ffff7d8a740, state.gpr.Gs_base)
+	expect.Equal(t, 0x00007ffff7d8b740, newState.gpr.Gs_base)
+}
+
 func (RegistersSuite) TestSs(t *testing.T) {
 	ss, ok := ByName("ss")
 	expect.True(t, ok)
@@ -1553,6 +1603,35 @@ func TestStMm(t *testing.T) {
 	}
 }
 
+func (RegistersSuite) TestLongDouble(t *testing.T) {
+	// 64.125
+	expect.Equal(t, 64.125, U128(0x4005, 0x80_40_00_00_00_00_00_00).ToLongDouble())
+	expect.Equal(
+		t,
+		-64.125,
+		U128(0xc005, 0x80_40_00_00_00_00_00_00).ToLongDouble())
+
+	// 42.24 (lossy)
+	expect.Equal(t, 42.24, U128(0x4004, 0xa8_f5_c2_8f_5c_28_f5_c3).ToLongDouble())
+
+	expect.Equal(t, 0, U128(0, 0).ToLongDouble())
+	expect.True(t, math.Signbit(U128(0x8000, 0).ToLongDouble()))
+	expect.Equal(t, 1, U128(0x3fff, 0x80_00_00_00_00_00_00_00).ToLongDouble())
+
+	expect.True(
+		t,
+		math.IsInf(U128(0x7fff, 0x80_00_00_00_00_00_00_00).ToLongDouble(), 1))
+	expect.True(
+		t,
+		math.IsInf(U128(0xffff, 0x80_00_00_00_00_00_00_00).ToLongDouble(), -1))
+	expect.True(
+		t,
+		math.IsNaN(U128(0x7fff, 0xc0_00_00_00_00_00_00_00).ToLongDouble()))
+
+	// Smaller than float64's smallest denormal
+	expect.Equal(t, 0, U128(0x0001, 0x80_00_00_00_00_00_00_00).ToLongDouble())
+}
+
 func TestXmm(t *testing.T) {
 	for i := 0; i < 16; i++ {
 		name := fmt.Sprintf("xmm%d", i)
@@ -1838,3 +1917,139 @@ func (RegistersSuite) TestParseU128(t *testing.T) {
 	_, err = reg8.ParseValue("0x0102030405060708:-2")
 	expect.Error(t, err, "failed to parse uint128 low word (-2)")
 }
+
+func (RegistersSuite) TestDumpAndLoad(t *testing.T) {
+	state := State{}
+	state.gpr.Rax = 0x0102030405060708
+	state.gpr.Rip = 0xcafe
+	state.fpr.Mxcsr = 0x1f80
+	state.fpr.XmmSpace[3] = 0xba5eba11
+	state.dr[7] = 0x1
+
+	buffer := &bytes.Buffer{}
+	err := state.Dump(buffer)
+	expect.Nil(t, err)
+
+	content := buffer.Bytes()
+
+	loaded, err := LoadDump(bytes.NewReader(content))
+	expect.Nil(t, err)
+	expect.Equal(t, state, loaded)
+
+	_, err = LoadDump(bytes.NewReader(content[:len(content)-1]))
+	expect.Error(t, err, "failed to read register dump content")
+
+	_, err = LoadDump(bytes.NewReader(append(content, 0)))
+	expect.Error(t, err, "unexpected trailing data")
+
+	modified := append([]byte{}, content...)
+	modified[8] = DumpFormatVersion + 1
+	_, err = LoadDump(bytes.NewReader(modified))
+	expect.Error(t, err, "unsupported register dump version (2 != 1)")
+
+	modified = append([]byte{}, content...)
+	modified[12] += 8
+	_, err = LoadDump(bytes.NewReader(modified))
+	expect.Error(t, err, "register dump size mismatch")
+
+	modified = append([]byte{}, content...)
+	modified[0] = 'X'
+	_, err = LoadDump(bytes.NewReader(modified))
+	expect.Error(t, err, "invalid magic")
+
+	rax, ok := ByName("rax")
+	expect.True(t, ok)
+
+	err = state.WithUndefined(rax).Dump(&bytes.Buffer{})
+	expect.Error(t, err, "undefined values")
+}
+
+func (RegistersSuite) TestFakeTracer(t *testing.T) {
+	tracer := ptracetest.NewFakeTracer(1)
+	tracer.Regs.Rip = 0x401000
+	tracer.Regs.Rax = 42
+	tracer.FPRegs.Mxcsr = 0x1f80
+
+	registers := New(tracer)
+
+	state, pc, err := registers.GetProgramCounter()
+	expect.Nil(t, err)
+	expect.Equal(t, VirtualAddress(0x401000), pc)
+
+	rax, ok := ByName("rax")
+	expect.True(t, ok)
+	expect.Equal(t, U64(42), state.Value(rax))
+
+	mxcsr, ok := ByName("mxcsr")
+	expect.True(t, ok)
+	expect.Equal(t, U32(0x1f80), state.Value(mxcsr))
+
+	err = registers.SetProgramCounter(0x402000)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x402000, tracer.Regs.Rip)
+	expect.Equal(t, 42, tracer.Regs.Rax)
+
+	dr0, ok := ByName("dr0")
+	expect.True(t, ok)
+
+	state, err = state.WithValue(dr0, U64(0x403000))
+	expect.Nil(t, err)
+
+	err = registers.SetState(state)
+	expect.Nil(t, err)
+	expect.Equal(t, 0x401000, tracer.Regs.Rip)
+	expect.Equal(t, 0x403000, tracer.UserArea[userDebugRegistersOffset])
+
+	state, err = registers.GetState()
+	expect.Nil(t, err)
+	expect.Equal(t, U64(0x403000), state.Value(dr0))
+
+	err = tracer.Detach()
+	expect.Nil(t, err)
+	expect.Equal(t, []string{"detach"}, tracer.Operations)
+
+	_, err = registers.GetState()
+	expect.Error(t, err, "tracer has detached")
+}
+
+func (RegistersSuite) TestDiff(t *testing.T) {
+	rax, _ := ByName("rax")
+	eax, _ := ByName("eax")
+	eflags, _ := ByName("eflags")
+	st0, _ := ByName("st0")
+	rbx, _ := ByName("rbx")
+
+	before := State{}
+	before.gpr.Rax = 1
+	before.gpr.Eflags = 0x246 // PF ZF IF
+	before.gpr.Rcx = 5
+
+	expect.Equal(t, 0, len(before.Diff(before)))
+
+	after, err := before.WithValue(eax, U32(2))
+	expect.Nil(t, err)
+
+	after, err = after.WithValue(eflags, U64(0x203)) // CF IF
+	expect.Nil(t, err)
+
+	after, err = after.WithValue(st0, U128(1, 2))
+	expect.Nil(t, err)
+
+	after = after.WithUndefined(rbx)
+
+	diffs := before.Diff(after)
+	expect.Equal(
+		t,
+		[]Difference{
+			{Spec: rax, Old: U64(1), New: U64(2)},
+			{Spec: rbx, Old: U64(0), New: nil},
+			{Spec: eflags, Old: U64(0x246), New: U64(0x203)},
+			{Spec: st0, Old: U128(0, 0), New: U128(1, 2)},
+		},
+		diffs)
+
+	expect.Equal(t, "[ PF ZF IF ]", FormatEflags(0x246))
+	expect.Equal(t, "[ CF IF ]", FormatEflags(0x203))
+	expect.Equal(t, "[ ]", FormatEflags(0))
+	expect.Equal(t, "+CF -PF -ZF", FormatEflagsChanges(0x246, 0x203))
+}
@@ -199,6 +199,9 @@ var (
 	SyscallNum  Spec
 	SyscallArgs []Spec
 	SyscallRet  Spec
+
+	// Integer / pointer function call arguments (System V calling convention)
+	FunctionArgs []Spec
 )
 
 func ByName(name string) (Spec, bool) {
@@ -355,6 +358,11 @@ func init() {
 
 	addGpr64("orig_rax", -1, "Orig_rax")
 
+	// The fs / gs segment base addresses.  fs_base is the thread pointer (i.e.,
+	// the thread control block address used for TLS resolution).
+	addGpr64("fs_base", 58, "Fs_base")
+	addGpr64("gs_base", 59, "Gs_base")
+
 	addFpr16("fcw", 65, "Cwd")
 	addFpr16("fsw", 66, "Swd")
 	addFpr16("ftw", -1, "Ftw")
@@ -395,4 +403,9 @@ func init() {
 		reg, _ := ByName(arg)
 		SyscallArgs = append(SyscallArgs, reg)
 	}
+
+	for _, arg := range []string{"rdi", "rsi", "rdx", "rcx", "r8", "r9"} {
+		reg, _ := ByName(arg)
+		FunctionArgs = append(FunctionArgs, reg)
+	}
 }
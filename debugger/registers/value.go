@@ -74,6 +74,42 @@ func (u Uint128) String() string {
 	return fmt.Sprintf("0x%016x:0x%016x", u.High, u.Low)
 }
 
+// Interpret the value as an x87 80-bit extended precision (long double) value,
+// i.e., a 64-bit mantissa (with explicit integer bit) in Low and a 16-bit
+// sign/exponent in High.  NOTE: the result is only an approximation since
+// float64 has a much smaller mantissa / exponent range than long double.
+func (u Uint128) ToLongDouble() float64 {
+	signExponent := uint16(u.High)
+	isNegative := signExponent&0x8000 != 0
+	exponent := int(signExponent & 0x7fff)
+	mantissa := u.Low
+
+	sign := 1
+	if isNegative {
+		sign = -1
+	}
+
+	var result float64
+	if exponent == 0x7fff {
+		// NOTE: the integer bit is ignored
+		if mantissa<<1 == 0 {
+			return math.Inf(sign)
+		}
+		return math.NaN()
+	} else if exponent == 0 {
+		// denormal
+		result = math.Ldexp(float64(mantissa), 1-16383-63)
+	} else {
+		result = math.Ldexp(float64(mantissa), exponent-16383-63)
+	}
+
+	if isNegative {
+		result = -result
+	}
+
+	return result
+}
+
 func U128(high uint64, low uint64) Uint128 {
 	return Uint128{
 		High: high,
@@ -81,6 +117,27 @@ func U128(high uint64, low uint64) Uint128 {
 	}
 }
 
+// This returns the unsigned value (of the matching size) for the little
+// endian encoded data.
+func FromBytes(data []byte) Value {
+	switch len(data) {
+	case 1:
+		return U8(data[0])
+	case 2:
+		return U16(binary.LittleEndian.Uint16(data))
+	case 4:
+		return U32(binary.LittleEndian.Uint32(data))
+	case 8:
+		return U64(binary.LittleEndian.Uint64(data))
+	case 16:
+		return U128(
+			binary.LittleEndian.Uint64(data[8:]),
+			binary.LittleEndian.Uint64(data[:8]))
+	default:
+		panic(fmt.Sprintf("invalid register data size: %d", len(data)))
+	}
+}
+
 type Uint[T uint8 | uint16 | uint32 | uint64] struct {
 	Value T
 }
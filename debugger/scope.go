@@ -0,0 +1,148 @@
+package debugger
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/dwarf"
+)
+
+// A parameter / local variable visible at a program location.
+type ScopeVariable struct {
+	Name        string
+	TypeName    string
+	IsParameter bool
+
+	// A static description of the variable's dwarf location.  Unlike
+	// DescribeLocation, the location expression is described rather than
+	// evaluated since the location need not be in an active frame.
+	Location string
+
+	Entry *dwarf.DebugInfoEntry
+}
+
+func (variable ScopeVariable) String() string {
+	kind := "local"
+	if variable.IsParameter {
+		kind = "parameter"
+	}
+
+	return fmt.Sprintf(
+		"%s (%s): %s, %s",
+		variable.Name,
+		variable.TypeName,
+		kind,
+		variable.Location)
+}
+
+// Returns the parameters / local variables which would be visible if the
+// program stopped at the address, with the innermost block's variables first
+// (see dwarf.InformationSection.LocalVariableEntries).  The program need not
+// be stopped at (or even near) the address.
+func (db *Debugger) ListScopeVariables(
+	address VirtualAddress,
+) (
+	[]ScopeVariable,
+	error,
+) {
+	entries, err := db.LoadedElves.LocalVariableEntries(address)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ScopeVariable, 0, len(entries))
+	for _, entry := range entries {
+		name, _, err := entry.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		typeName := "<unknown>"
+		typeDie, err := entry.TypeEntry()
+		if err == nil {
+			descriptor, err := db.descriptorPool.GetVariableDescriptor(typeDie)
+			if err == nil {
+				typeName = descriptor.TypeName()
+			}
+		}
+
+		result = append(
+			result,
+			ScopeVariable{
+				Name:        name,
+				TypeName:    typeName,
+				IsParameter: entry.Tag == dwarf.DW_TAG_formal_parameter,
+				Location:    describeStaticLocation(entry),
+				Entry:       entry,
+			})
+	}
+
+	return result, nil
+}
+
+// NOTE: only single operation location expressions (which covers unoptimized
+// code) are described in detail.
+func describeStaticLocation(entry *dwarf.DebugInfoEntry) string {
+	value, ok := entry.Any(dwarf.DW_AT_location)
+	if !ok {
+		_, ok := entry.Any(dwarf.DW_AT_const_value)
+		if ok {
+			return "constant value"
+		}
+		return "optimized out"
+	}
+
+	if entry.IsLocationList(dwarf.DW_AT_location) {
+		return "location list (location depends on pc)"
+	}
+
+	expression, ok := value.([]byte)
+	if !ok || len(expression) == 0 {
+		return "optimized out"
+	}
+
+	decode := dwarf.NewCursor(entry.CompileUnit.ByteOrder(), expression[1:])
+	opCode := dwarf.Operation(expression[0])
+
+	description := ""
+	switch {
+	case opCode == dwarf.DW_OP_fbreg:
+		offset, err := decode.SLEB128(64)
+		if err == nil {
+			description = fmt.Sprintf("frame base offset %d", offset)
+		}
+	case opCode == dwarf.DW_OP_addr:
+		address, err := decode.U64()
+		if err == nil {
+			description = fmt.Sprintf(
+				"static storage at file address 0x%x",
+				address)
+		}
+	case dwarf.DW_OP_reg0 <= opCode && opCode <= dwarf.DW_OP_reg31:
+		description = "register " + dwarfRegisterName(
+			dwarf.RegisterId(opCode-dwarf.DW_OP_reg0))
+	case dwarf.DW_OP_breg0 <= opCode && opCode <= dwarf.DW_OP_breg31:
+		offset, err := decode.SLEB128(64)
+		if err == nil {
+			description = fmt.Sprintf(
+				"register %s offset %d",
+				dwarfRegisterName(dwarf.RegisterId(opCode-dwarf.DW_OP_breg0)),
+				offset)
+		}
+	}
+
+	if description == "" || !decode.HasReachedEnd() {
+		return fmt.Sprintf("dwarf expression (%d bytes)", len(expression))
+	}
+
+	return description
+}
+
+func dwarfRegisterName(id dwarf.RegisterId) string {
+	spec, ok := registers.ById(id)
+	if !ok {
+		return fmt.Sprintf("<dwarf register %d>", id)
+	}
+	return spec.Name
+}
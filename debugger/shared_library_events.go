@@ -0,0 +1,84 @@
+package debugger
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/loadedelves"
+)
+
+// A shared library load / unload event detected via the dynamic linker's
+// rendezvous mechanism.
+type SharedLibraryEvent struct {
+	// Monotonically increasing, starting from 1.
+	Sequence int
+
+	// The time at which the debugger detected the event (not necessarily the
+	// time at which the library was loaded / unloaded).
+	Time time.Time
+
+	Loaded bool // false for unload
+
+	Name        string
+	LoadAddress VirtualAddress
+}
+
+func (event SharedLibraryEvent) String() string {
+	action := "unloaded"
+	if event.Loaded {
+		action = "loaded"
+	}
+
+	return fmt.Sprintf(
+		"#%d [%s] %s %s at %s",
+		event.Sequence,
+		event.Time.Format("15:04:05.000000"),
+		action,
+		event.Name,
+		event.LoadAddress)
+}
+
+// Notify is called on every shared library load / unload event.
+func (db *Debugger) WatchSharedLibraryEvents(
+	notify func(SharedLibraryEvent),
+) {
+	db.sharedLibraryWatchers = append(db.sharedLibraryWatchers, notify)
+}
+
+// Returns all recorded shared library events, in detection order.  The
+// returned list is owned by the caller.
+func (db *Debugger) SharedLibraryEvents() []SharedLibraryEvent {
+	return append([]SharedLibraryEvent{}, db.sharedLibraryEvents...)
+}
+
+func (db *Debugger) recordSharedLibraryEvents(
+	loaded []*loadedelves.File,
+	unloaded []*loadedelves.File,
+) {
+	now := time.Now()
+
+	record := func(file *loadedelves.File, isLoad bool) {
+		event := SharedLibraryEvent{
+			Sequence:    len(db.sharedLibraryEvents) + 1,
+			Time:        now,
+			Loaded:      isLoad,
+			Name:        file.FileName,
+			LoadAddress: VirtualAddress(file.LoadBias),
+		}
+
+		db.sharedLibraryEvents = append(db.sharedLibraryEvents, event)
+
+		for _, notify := range db.sharedLibraryWatchers {
+			notify(event)
+		}
+	}
+
+	for _, file := range unloaded {
+		record(file, false)
+	}
+
+	for _, file := range loaded {
+		record(file, true)
+	}
+}
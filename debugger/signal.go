@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	osSignal "os/signal"
+	"sync/atomic"
 	"syscall"
 )
 
@@ -14,11 +15,31 @@ const (
 	WaitForAllChildren = 0x40000000
 )
 
+// The signal / wait operations used for controlling the process and its
+// threads.  This is implemented by Signaler (and fakes for testing).
+type processSignaler interface {
+	Close() error
+
+	ForwardInterruptToProcess()
+	setResumed(isResumed bool) bool
+
+	ContinueToProcess() error
+	StopToProcess() error
+	KillToProcess() error
+	StopToThread(tid int) error
+	ToThread(tid int, signal syscall.Signal) error
+
+	FromProcessThreads() (int, syscall.WaitStatus, error)
+	FromThread(tid int) (syscall.WaitStatus, error)
+}
+
 type Signaler struct {
 	pid int
 
 	ctx    context.Context
 	cancel func()
+
+	isResumed atomic.Bool
 }
 
 func NewSignaler(pid int) *Signaler {
@@ -36,15 +57,32 @@ func (signaler *Signaler) Close() error {
 }
 
 func (signaler *Signaler) ForwardToProcess(signal syscall.Signal) {
-	signalChan := make(chan os.Signal)
+	signaler.forwardToProcess(
+		signal,
+		func() bool {
+			return true
+		})
+}
+
+func (signaler *Signaler) forwardToProcess(
+	signal syscall.Signal,
+	shouldForward func() bool,
+) {
+	signalChan := make(chan os.Signal, 1)
 	osSignal.Notify(signalChan, signal)
 
 	go func() {
+		defer osSignal.Stop(signalChan)
+
 		for {
 			select {
 			case <-signaler.ctx.Done():
 				return
 			case <-signalChan:
+				if !shouldForward() {
+					continue
+				}
+
 				err := signaler.ToProcess(signal)
 				if err != nil {
 					panic(err)
@@ -54,8 +92,32 @@ func (signaler *Signaler) ForwardToProcess(signal syscall.Signal) {
 	}()
 }
 
+// The interrupt is only forwarded while the process is resumed.  The stopped
+// process is then reported to the debugger as a SIGINT signal stop.
+//
+// NOTE: a process in the debugger's process group receives the terminal's
+// interrupt directly, in which case the interrupt is not forwarded (to avoid
+// reporting the same interrupt twice).
 func (signaler *Signaler) ForwardInterruptToProcess() {
-	signaler.ForwardToProcess(syscall.SIGINT)
+	signaler.forwardToProcess(
+		syscall.SIGINT,
+		func() bool {
+			if !signaler.isResumed.Load() {
+				return false
+			}
+
+			pgid, err := syscall.Getpgid(signaler.pid)
+			if err != nil {
+				return false
+			}
+
+			return pgid != syscall.Getpgrp()
+		})
+}
+
+// Returns the previous resumed state.
+func (signaler *Signaler) setResumed(isResumed bool) bool {
+	return signaler.isResumed.Swap(isResumed)
 }
 
 func (signaler *Signaler) ToProcess(signal syscall.Signal) error {
@@ -79,7 +141,19 @@ func (signaler *Signaler) StopToProcess() error {
 }
 
 func (signaler *Signaler) StopToThread(tid int) error {
-	return syscall.Tgkill(signaler.pid, tid, syscall.SIGSTOP)
+	return signaler.ToThread(tid, syscall.SIGSTOP)
+}
+
+func (signaler *Signaler) ToThread(tid int, signal syscall.Signal) error {
+	err := syscall.Tgkill(signaler.pid, tid, signal)
+	if err != nil {
+		return fmt.Errorf("failed to signal to thread %d (%v): %w",
+			tid,
+			signal,
+			err)
+	}
+
+	return nil
 }
 
 func (signaler *Signaler) KillToProcess() error {
@@ -0,0 +1,101 @@
+package debugger
+
+import (
+	"fmt"
+	"sort"
+	"syscall"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+// Controls which (non-internal) signals received by the threads are delivered
+// to the process when the threads resume.  Signals that are not passed are
+// discarded.
+type SignalPassPolicy struct {
+	noPass map[syscall.Signal]struct{}
+}
+
+func NewSignalPassPolicy() *SignalPassPolicy {
+	return &SignalPassPolicy{
+		// NOTE: SIGTRAP is used by the debugger, SIGINT is used for interrupting
+		// the process, and delivering SIGSTOP would put the traced process into
+		// group stop.
+		noPass: map[syscall.Signal]struct{}{
+			syscall.SIGINT:  struct{}{},
+			syscall.SIGSTOP: struct{}{},
+			syscall.SIGTRAP: struct{}{},
+		},
+	}
+}
+
+func (policy *SignalPassPolicy) ShouldPass(signal syscall.Signal) bool {
+	_, ok := policy.noPass[signal]
+	return !ok
+}
+
+func (policy *SignalPassPolicy) Pass(signal syscall.Signal) {
+	delete(policy.noPass, signal)
+}
+
+func (policy *SignalPassPolicy) NoPass(signal syscall.Signal) {
+	policy.noPass[signal] = struct{}{}
+}
+
+// Returns the sorted list of signals which are not passed to the process.
+func (policy *SignalPassPolicy) NoPassSignals() []syscall.Signal {
+	return sortedSignals(policy.noPass)
+}
+
+func sortedSignals(signals map[syscall.Signal]struct{}) []syscall.Signal {
+	result := make([]syscall.Signal, 0, len(signals))
+	for signal := range signals {
+		result = append(result, signal)
+	}
+
+	sort.Slice(
+		result,
+		func(i int, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Controls which (non-internal) signals received by the threads stop the
+// process and are reported to the user.  Ignored signals are still delivered
+// according to the SignalPassPolicy.
+type SignalCatchPolicy struct {
+	ignored map[syscall.Signal]struct{}
+}
+
+func NewSignalCatchPolicy() *SignalCatchPolicy {
+	return &SignalCatchPolicy{
+		ignored: map[syscall.Signal]struct{}{},
+	}
+}
+
+func (policy *SignalCatchPolicy) ShouldStop(signal syscall.Signal) bool {
+	_, ok := policy.ignored[signal]
+	return !ok
+}
+
+func (policy *SignalCatchPolicy) Catch(signal syscall.Signal) {
+	delete(policy.ignored, signal)
+}
+
+func (policy *SignalCatchPolicy) CatchAll() {
+	policy.ignored = map[syscall.Signal]struct{}{}
+}
+
+func (policy *SignalCatchPolicy) Ignore(signal syscall.Signal) error {
+	switch signal {
+	case syscall.SIGINT, syscall.SIGSTOP, syscall.SIGTRAP:
+		// NOTE: these signals are used by the debugger to interrupt the process.
+		return fmt.Errorf("%w. cannot ignore %v", ErrInvalidInput, signal)
+	}
+
+	policy.ignored[signal] = struct{}{}
+	return nil
+}
+
+// Returns the sorted list of signals which do not stop the process.
+func (policy *SignalCatchPolicy) IgnoredSignals() []syscall.Signal {
+	return sortedSignals(policy.ignored)
+}
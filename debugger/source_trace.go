@@ -0,0 +1,121 @@
+package debugger
+
+import (
+	"fmt"
+
+	. "github.com/pattyshack/bad/debugger/common"
+)
+
+const DefaultSourceTraceCapacity = 64
+
+type SourceTraceEntry struct {
+	Tid     int
+	Address VirtualAddress
+	Path    string
+	Line    int64
+}
+
+func (entry SourceTraceEntry) String() string {
+	return fmt.Sprintf(
+		"thread %d: %s:%d (%s)",
+		entry.Tid,
+		entry.Path,
+		entry.Line,
+		entry.Address)
+}
+
+// SourceTrace records the distinct source lines visited by the step in / step
+// over commands.  Only the most recent entries (up to capacity) are retained.
+// The trace is disabled by default.
+type SourceTrace struct {
+	enabled  bool
+	capacity int
+
+	// A ring buffer.  entries[start] is the oldest entry once the buffer is
+	// full.
+	entries []SourceTraceEntry
+	start   int
+}
+
+func (trace *SourceTrace) IsEnabled() bool {
+	return trace.enabled
+}
+
+// Enable (re)starts tracing with a new (empty) buffer.
+func (trace *SourceTrace) Enable(capacity int) error {
+	if capacity <= 0 {
+		return fmt.Errorf(
+			"%w. invalid source trace capacity (%d)",
+			ErrInvalidInput,
+			capacity)
+	}
+
+	trace.enabled = true
+	trace.capacity = capacity
+	trace.Clear()
+	return nil
+}
+
+// Disable stops tracing.  Recorded entries are still viewable.
+func (trace *SourceTrace) Disable() {
+	trace.enabled = false
+}
+
+func (trace *SourceTrace) Clear() {
+	trace.entries = make([]SourceTraceEntry, 0, trace.capacity)
+	trace.start = 0
+}
+
+func (trace *SourceTrace) Capacity() int {
+	return trace.capacity
+}
+
+// Returns the recorded entries, from oldest to newest.
+func (trace *SourceTrace) Entries() []SourceTraceEntry {
+	result := make([]SourceTraceEntry, 0, len(trace.entries))
+	result = append(result, trace.entries[trace.start:]...)
+	result = append(result, trace.entries[:trace.start]...)
+	return result
+}
+
+func (trace *SourceTrace) last() *SourceTraceEntry {
+	if len(trace.entries) == 0 {
+		return nil
+	}
+
+	idx := trace.start - 1
+	if idx < 0 {
+		idx = len(trace.entries) - 1
+	}
+	return &trace.entries[idx]
+}
+
+func (trace *SourceTrace) record(status *ThreadStatus) {
+	if !trace.enabled || !status.Stopped || status.FileEntry == nil {
+		return
+	}
+
+	entry := SourceTraceEntry{
+		Tid:     status.Tid,
+		Address: status.NextInstructionAddress,
+		Path:    status.FileEntry.Path(),
+		Line:    status.Line,
+	}
+
+	last := trace.last()
+	if last != nil &&
+		last.Tid == entry.Tid &&
+		last.Path == entry.Path &&
+		last.Line == entry.Line {
+
+		return
+	}
+
+	if len(trace.entries) < trace.capacity {
+		trace.entries = append(trace.entries, entry)
+		return
+	}
+
+	trace.entries[trace.start] = entry
+	trace.start = (trace.start + 1) % trace.capacity
+}
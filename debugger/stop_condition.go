@@ -0,0 +1,290 @@
+package debugger
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+// A condition is either a single expression, which is satisfied when the
+// expression's value is non-zero, or a comparison of the form
+//
+//	<expression> <op> <expression>
+//
+// where op is one of ==, !=, <, <=, >, >= (see expression.ParseCondition).
+// The expressions must evaluate to simple (bool / char / integer / float /
+// pointer) values.
+//
+// The condition is evaluated in the current thread's inspect frame.
+// Expressions may call functions (e.g., strcmp(name, "x") == 0), which are
+// invoked in the current thread.  Calls in an unselected conditional branch
+// are not performed, hence calls can be gated by a cheaper check (e.g.,
+// name ? strcmp(name, "x") : 1).
+func (db *Debugger) EvaluateCondition(condition string) (bool, error) {
+	parsed, err := expression.ParseCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	lhs, isNaN, err := db.evaluateConditionOperand(parsed.Lhs)
+	if err != nil {
+		return false, err
+	}
+
+	if parsed.Op == "" {
+		return isNaN || lhs.Sign() != 0, nil
+	}
+
+	rhs, rhsIsNaN, err := db.evaluateConditionOperand(parsed.Rhs)
+	if err != nil {
+		return false, err
+	}
+
+	if isNaN || rhsIsNaN {
+		return parsed.Op == "!=", nil
+	}
+
+	cmp := lhs.Cmp(rhs)
+	switch parsed.Op {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	default:
+		panic("should never happen")
+	}
+}
+
+// Returns the operand's numeric value.  The bool is true if the value is a
+// NaN float, in which case the numeric value is nil.
+func (db *Debugger) evaluateConditionOperand(
+	operand string,
+) (
+	*big.Float,
+	bool,
+	error,
+) {
+	data, err := expression.Evaluate(db, strings.TrimSpace(operand))
+	if err != nil {
+		return nil, false, err
+	}
+
+	value, err := data.DecodeSimpleValue()
+	if err != nil {
+		return nil, false, fmt.Errorf(
+			"%w. invalid condition operand (%s): %w",
+			ErrInvalidInput,
+			strings.TrimSpace(operand),
+			err)
+	}
+
+	// NOTE: 128 bits of precision is sufficient to represent all (up to
+	// 128-bit) integers and float64 values exactly.
+	result := new(big.Float).SetPrec(128)
+	switch value := value.(type) {
+	case bool:
+		if value {
+			result.SetInt64(1)
+		}
+	case byte:
+		result.SetUint64(uint64(value))
+	case int8:
+		result.SetInt64(int64(value))
+	case int16:
+		result.SetInt64(int64(value))
+	case int32:
+		result.SetInt64(int64(value))
+	case int64:
+		result.SetInt64(value)
+	case uint16:
+		result.SetUint64(uint64(value))
+	case uint32:
+		result.SetUint64(uint64(value))
+	case uint64:
+		result.SetUint64(value)
+	case expression.Int128:
+		result.SetInt(value.BigInt())
+	case expression.Uint128:
+		result.SetInt(value.BigInt())
+	case VirtualAddress:
+		result.SetUint64(uint64(value))
+	case float32:
+		if math.IsNaN(float64(value)) {
+			return nil, true, nil
+		}
+		result.SetFloat64(float64(value))
+	case float64:
+		if math.IsNaN(value) {
+			return nil, true, nil
+		}
+		result.SetFloat64(value)
+	default:
+		return nil, false, fmt.Errorf(
+			"%w. unsupported condition operand (%s) type (%s)",
+			ErrInvalidInput,
+			strings.TrimSpace(operand),
+			data.TypeName())
+	}
+
+	return result, false, nil
+}
+
+// Remove triggered stop points whose conditions are not satisfied, or whose
+// hits are ignored, from the thread's status.  This returns true if the thread
+// stopped only because of skipped stop points, in which case the thread should
+// be silently resumed.
+//
+// NOTE: hardware watch points trap after the write, hence the condition
+// observes the watched location's new value.
+func (db *Debugger) filterSkippedStopPoints(thread *ThreadState) bool {
+	status := thread.status
+	if len(status.StopPoints) == 0 {
+		return false
+	}
+
+	// NOTE: stop points triggered by function calls made while evaluating a
+	// condition are silently skipped, without evaluating their conditions or
+	// consuming their ignore counts.  This guards against unbounded recursion
+	// (e.g., the condition calls the function containing the break point).
+	if db.conditionStopPoint != nil {
+		for _, triggered := range status.StopPoints {
+			if !triggered.StopPoint.Type().IsWatchPoint {
+				status.shouldBypassBreakSite = true
+			}
+		}
+
+		status.StopPoints = nil
+		return true
+	}
+
+	shouldFilter := false
+	for _, triggered := range status.StopPoints {
+		if triggered.Condition() != "" || triggered.IgnoreCount() > 0 {
+			shouldFilter = true
+			break
+		}
+	}
+
+	if !shouldFilter {
+		return false
+	}
+
+	// The condition is evaluated in the context of the stopped thread.
+	originalTid := db.currentTid
+	db.currentTid = thread.Tid
+	defer func() {
+		db.currentTid = originalTid
+	}()
+
+	skippedBreakPoint := false
+	satisfied := status.StopPoints[:0]
+	for _, triggered := range status.StopPoints {
+		if triggered.Condition() != "" {
+			ok, err := db.evaluateStopPointCondition(triggered.StopPoint)
+			// NOTE: stop on evaluation error to give user a chance to fix the
+			// condition.
+			if err == nil && !ok {
+				skippedBreakPoint = skippedBreakPoint ||
+					!triggered.StopPoint.Type().IsWatchPoint
+				continue
+			}
+		}
+
+		// NOTE: only hits with satisfied conditions count towards the ignore
+		// count.
+		if triggered.ConsumeIgnoreCount() {
+			skippedBreakPoint = skippedBreakPoint ||
+				!triggered.StopPoint.Type().IsWatchPoint
+			continue
+		}
+
+		satisfied = append(satisfied, triggered)
+	}
+
+	status.StopPoints = satisfied
+	if len(satisfied) > 0 {
+		return false
+	}
+
+	// NOTE: break points trap before the instruction is executed.
+	status.shouldBypassBreakSite = skippedBreakPoint
+	return true
+}
+
+func (db *Debugger) evaluateStopPointCondition(
+	point *stoppoint.StopPoint,
+) (
+	bool,
+	error,
+) {
+	db.conditionStopPoint = point
+	defer func() {
+		db.conditionStopPoint = nil
+	}()
+
+	return db.EvaluateCondition(point.Condition())
+}
+
+// Disable the stop point whose condition is being evaluated (or all enabled
+// break points, see DisableAllBreakPointsInConditionCalls) for the duration
+// of a function call made by the condition.  This returns the disabled stop
+// points, which must be re-enabled once the call returns.  This is a no-op
+// when no condition is being evaluated.
+//
+// NOTE: stop points already disabled by an outer call (e.g., the malloc call
+// which allocates a string literal argument) are not returned.
+func (db *Debugger) disableStopPointsForConditionCall() (
+	[]*stoppoint.StopPoint,
+	error,
+) {
+	if db.conditionStopPoint == nil {
+		return nil, nil
+	}
+
+	candidates := []*stoppoint.StopPoint{db.conditionStopPoint}
+	if db.DisableAllBreakPointsInConditionCalls {
+		candidates = append(candidates, db.BreakPoints.List()...)
+	}
+
+	disabled := []*stoppoint.StopPoint{}
+	for _, point := range candidates {
+		if !point.IsEnabled() {
+			continue
+		}
+
+		err := point.Disable()
+		if err != nil {
+			_ = db.enableStopPoints(disabled)
+			return nil, err
+		}
+
+		disabled = append(disabled, point)
+	}
+
+	return disabled, nil
+}
+
+func (db *Debugger) enableStopPoints(points []*stoppoint.StopPoint) error {
+	var result error
+	for _, point := range points {
+		err := point.Enable()
+		if err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,100 @@
+package stoppoint
+
+import (
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
+)
+
+const (
+	// Stops on I/O port reads and writes.  Only used for decoding the debug
+	// control register (not supported by linux).
+	IOReadWriteMode = StopSiteMode("io read/write")
+
+	// Debug status (dr6) bits, beyond the per slot triggered bits (0-3).
+	debugStatusAccessDetected = 1 << 13 // BD
+	debugStatusSingleStep     = 1 << 14 // BS
+	debugStatusTaskSwitch     = 1 << 15 // BT
+)
+
+// The decoded state of a single debug address register (dr0 - dr3).  See
+// hardwareStopSitePool.controlBytes for the debug control register's layout.
+type DebugRegisterSlot struct {
+	Index   int
+	Address VirtualAddress
+
+	IsLocallyEnabled  bool
+	IsGloballyEnabled bool
+
+	Mode      StopSiteMode
+	WatchSize int
+
+	// True when the debug status register indicates the slot's condition was
+	// met (i.e., the slot triggered the last hardware trap).
+	Triggered bool
+}
+
+func (slot DebugRegisterSlot) IsEnabled() bool {
+	return slot.IsLocallyEnabled || slot.IsGloballyEnabled
+}
+
+type DebugRegisters struct {
+	Control uint64 // dr7
+	Status  uint64 // dr6
+
+	Slots [NumHardwareStopSites]DebugRegisterSlot
+
+	// Decoded debug status bits.
+	AccessDetected bool // debug register access detected
+	SingleStepped  bool // single step trap
+	TaskSwitched   bool // task switch trap
+}
+
+// Decodes the debug registers in the thread's register state.
+func DecodeDebugRegisters(state registers.State) DebugRegisters {
+	control := state.Value(registers.DebugControl).ToUint64()
+	status := state.Value(registers.DebugStatus).ToUint64()
+
+	result := DebugRegisters{
+		Control:        control,
+		Status:         status,
+		AccessDetected: status&debugStatusAccessDetected != 0,
+		SingleStepped:  status&debugStatusSingleStep != 0,
+		TaskSwitched:   status&debugStatusTaskSwitch != 0,
+	}
+
+	for idx := range result.Slots {
+		address := state.Value(registers.DebugAddresses[idx]).ToUint64()
+
+		mode := ExecuteMode
+		switch (control >> (16 + 4*idx)) & 0b11 {
+		case 0b01:
+			mode = WriteMode
+		case 0b10:
+			mode = IOReadWriteMode
+		case 0b11:
+			mode = ReadWriteMode
+		}
+
+		watchSize := 1
+		switch (control >> (18 + 4*idx)) & 0b11 {
+		case 0b01:
+			watchSize = 2
+		case 0b10:
+			watchSize = 8
+		case 0b11:
+			watchSize = 4
+		}
+
+		result.Slots[idx] = DebugRegisterSlot{
+			Index:             idx,
+			Address:           VirtualAddress(address),
+			IsLocallyEnabled:  control&(0b01<<(2*idx)) != 0,
+			IsGloballyEnabled: control&(0b10<<(2*idx)) != 0,
+			Mode:              mode,
+			WatchSize:         watchSize,
+			Triggered:         status&(1<<idx) != 0,
+		}
+	}
+
+	return result
+}
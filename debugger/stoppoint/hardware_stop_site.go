@@ -2,6 +2,7 @@ package stoppoint
 
 import (
 	"fmt"
+	"sort"
 
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/registers"
@@ -10,11 +11,15 @@ import (
 const (
 	debugStatusRegister  = "dr6"
 	debugControlRegister = "dr7"
+
+	// The number of debug address registers (dr0 - dr3), shared by hardware
+	// break points and watch points.
+	NumHardwareStopSites = 4
 )
 
 type hardwareStopSitePool struct {
 	process   Process
-	stopSites [4]*hardwareStopSite
+	stopSites [NumHardwareStopSites]*hardwareStopSite
 }
 
 func newHardwareStopSitePool(process Process) StopSitePool {
@@ -65,6 +70,46 @@ func (pool *hardwareStopSitePool) Allocate(
 		ErrInvalidInput)
 }
 
+func (pool *hardwareStopSitePool) AvailableHardwareStopSites() int {
+	count := 0
+	for _, site := range pool.stopSites {
+		if site == nil {
+			count += 1
+		}
+	}
+	return count
+}
+
+func (pool *hardwareStopSitePool) HardwareStopSiteAt(slot int) StopSite {
+	if slot < 0 || slot >= NumHardwareStopSites {
+		return nil
+	}
+
+	site := pool.stopSites[slot]
+	if site == nil { // avoid returning a non-nil interface wrapping nil
+		return nil
+	}
+
+	return site
+}
+
+func (pool *hardwareStopSitePool) AllocatedSites() StopSites {
+	result := StopSites{}
+	for _, site := range pool.stopSites {
+		if site != nil {
+			result = append(result, site)
+		}
+	}
+
+	sort.Slice(
+		result,
+		func(i int, j int) bool {
+			return result[i].Address() < result[j].Address()
+		})
+
+	return result
+}
+
 func (pool *hardwareStopSitePool) deallocate(
 	site *hardwareStopSite,
 ) error {
@@ -325,6 +370,15 @@ func (hardwareStopSite) RefCount() int {
 	return 1
 }
 
+func (site *hardwareStopSite) Mechanism() string {
+	for idx, allocated := range site.pool.stopSites {
+		if allocated == site {
+			return fmt.Sprintf("debug register dr%d", idx)
+		}
+	}
+	return "debug register (deallocated)"
+}
+
 func (site *hardwareStopSite) Deallocate() error {
 	return site.pool.deallocate(site)
 }
@@ -2,6 +2,7 @@ package stoppoint
 
 import (
 	"fmt"
+	"sort"
 
 	. "github.com/pattyshack/bad/debugger/common"
 )
@@ -29,9 +30,10 @@ type refCountStopSitePool struct {
 
 func NewStopSitePool(
 	process Process,
+	breakInstruction SoftwareBreakInstruction,
 ) StopSitePool {
 	return &refCountStopSitePool{
-		software:  newSoftwareStopSitePool(process.Memory()),
+		software:  newSoftwareStopSitePool(process.Memory(), breakInstruction),
 		hardware:  newHardwareStopSitePool(process),
 		allocated: map[StopSiteKey]*refCountStopSite{},
 	}
@@ -103,6 +105,24 @@ func (pool *refCountStopSitePool) GetEnabledAt(
 	return result
 }
 
+func (pool *refCountStopSitePool) AllocatedSites() StopSites {
+	result := StopSites{}
+	for _, site := range pool.allocated {
+		result = append(result, site)
+	}
+
+	sort.Slice(
+		result,
+		func(i int, j int) bool {
+			if result[i].Address() != result[j].Address() {
+				return result[i].Address() < result[j].Address()
+			}
+			return result[i].Type().String() < result[j].Type().String()
+		})
+
+	return result
+}
+
 func (pool *refCountStopSitePool) ReplaceStopSiteBytes(
 	startAddr VirtualAddress,
 	memorySlice []byte,
@@ -124,6 +144,24 @@ func (pool *refCountStopSitePool) ListTriggered(
 	return pool.software.ListTriggered(pc, kind)
 }
 
+func (pool *refCountStopSitePool) AvailableHardwareStopSites() int {
+	return pool.hardware.AvailableHardwareStopSites()
+}
+
+func (pool *refCountStopSitePool) HardwareStopSiteAt(slot int) StopSite {
+	base := pool.hardware.HardwareStopSiteAt(slot)
+	if base == nil {
+		return nil
+	}
+
+	site, ok := pool.allocated[base.Key()]
+	if !ok {
+		panic("should never happen")
+	}
+
+	return site
+}
+
 func (pool *refCountStopSitePool) RefreshSites() error {
 	err := pool.software.RefreshSites()
 	if err != nil {
@@ -52,6 +52,20 @@ func (factory StopSiteResolverFactory) NewLineResolver(
 	}
 }
 
+// Similar to NewLineResolver, but if the line has no code, the resolver
+// advances to the next line with code (in the same file).
+func (factory StopSiteResolverFactory) NewAutoAdvanceLineResolver(
+	path string,
+	line int,
+) StopSiteResolver {
+	return &LineStopSiteResolver{
+		LoadedElves: factory.loadedElves,
+		Path:        path,
+		Line:        line,
+		AutoAdvance: true,
+	}
+}
+
 func (factory StopSiteResolverFactory) NewFunctionResolver(
 	name string,
 ) StopSiteResolver {
@@ -61,6 +75,19 @@ func (factory StopSiteResolverFactory) NewFunctionResolver(
 	}
 }
 
+// Unlike NewFunctionResolver, the resolved addresses are the functions' entry
+// points (i.e., the prologues are not skipped).  This is useful for
+// inspecting the function's arguments as passed in by the caller.
+func (factory StopSiteResolverFactory) NewFunctionEntryResolver(
+	name string,
+) StopSiteResolver {
+	return &FunctionStopSiteResolver{
+		LoadedElves: factory.loadedElves,
+		Name:        name,
+		AtEntry:     true,
+	}
+}
+
 type StopSiteResolver interface {
 	String() string
 	ResolveAddresses() (VirtualAddresses, error)
@@ -84,9 +111,14 @@ func (resolver *AddressStopSiteResolver) ResolveAddresses() (
 type FunctionStopSiteResolver struct {
 	LoadedElves *loadedelves.Files
 	Name        string
+
+	AtEntry bool
 }
 
 func (resolver *FunctionStopSiteResolver) String() string {
+	if resolver.AtEntry {
+		return fmt.Sprintf("function-entry@%s", resolver.Name)
+	}
 	return fmt.Sprintf("function@%s", resolver.Name)
 }
 
@@ -117,6 +149,18 @@ func (resolver *FunctionStopSiteResolver) resolveAddresses() (
 		return nil, err
 	}
 
+	// NOTE: elf symbol tables are keyed by linkage (mangled) names.
+	symbolNames := []string{resolver.Name}
+	for _, funcDef := range funcDefs {
+		symbolName, ok, err := funcDef.SymbolName()
+		if err != nil {
+			return nil, err
+		}
+		if ok && symbolName != resolver.Name {
+			symbolNames = append(symbolNames, symbolName)
+		}
+	}
+
 	for _, funcDef := range funcDefs {
 		addressRanges, err := funcDef.AddressRanges()
 		if err != nil {
@@ -134,7 +178,7 @@ func (resolver *FunctionStopSiteResolver) resolveAddresses() (
 			return nil, err
 		}
 
-		if funcDef.Tag == dwarf.DW_TAG_inlined_subroutine {
+		if funcDef.Tag == dwarf.DW_TAG_inlined_subroutine || resolver.AtEntry {
 			// Inlined function have no prologue.
 			prologueBodies[lowPC] = lowPC
 		} else {
@@ -171,17 +215,24 @@ func (resolver *FunctionStopSiteResolver) resolveAddresses() (
 	}
 
 	// Fallback to elf symbol for prologue address
-	for _, symbol := range resolver.LoadedElves.SymbolsByName(resolver.Name) {
-		prologueAddr, err := resolver.LoadedElves.SymbolToVirtualAddress(symbol)
-		if err != nil {
-			return nil, err
-		}
+	for _, symbolName := range symbolNames {
+		for _, symbol := range resolver.LoadedElves.SymbolsByName(symbolName) {
+			if symbol.Value == 0 { // undefined (imported) symbol
+				continue
+			}
 
-		_, ok := prologueBodies[prologueAddr]
-		if ok {
-			continue
+			prologueAddr, err := resolver.LoadedElves.SymbolToVirtualAddress(
+				symbol)
+			if err != nil {
+				return nil, err
+			}
+
+			_, ok := prologueBodies[prologueAddr]
+			if ok {
+				continue
+			}
+			prologueBodies[prologueAddr] = prologueAddr
 		}
-		prologueBodies[prologueAddr] = prologueAddr
 	}
 
 	set := map[VirtualAddress]struct{}{}
@@ -202,12 +253,29 @@ type LineStopSiteResolver struct {
 	LoadedElves *loadedelves.Files
 	Path        string
 	Line        int
+
+	AutoAdvance bool
+
+	// The line the addresses were resolved from.  This differs from Line only
+	// when AutoAdvance moved the stop point to the next line with code.
+	ResolvedLine int
 }
 
 func (resolver *LineStopSiteResolver) String() string {
+	if resolver.IsAdvanced() {
+		return fmt.Sprintf(
+			"line@%s:%d (moved to line %d)",
+			resolver.Path,
+			resolver.Line,
+			resolver.ResolvedLine)
+	}
 	return fmt.Sprintf("line@%s:%d", resolver.Path, resolver.Line)
 }
 
+func (resolver *LineStopSiteResolver) IsAdvanced() bool {
+	return resolver.ResolvedLine != 0 && resolver.ResolvedLine != resolver.Line
+}
+
 func (resolver *LineStopSiteResolver) ResolveAddresses() (
 	VirtualAddresses,
 	error,
@@ -227,13 +295,34 @@ func (resolver *LineStopSiteResolver) resolveAddresses() (
 	VirtualAddresses,
 	error,
 ) {
-	lineEntries, err := resolver.LoadedElves.LineEntriesByLine(
-		resolver.Path,
-		resolver.Line)
+	line := resolver.Line
+	lineEntries, callSites, err := resolver.lineLocations(line)
 	if err != nil {
 		return nil, err
 	}
 
+	if len(lineEntries) == 0 && len(callSites) == 0 && resolver.AutoAdvance {
+		next, err := resolver.LoadedElves.NextLineWithEntries(
+			resolver.Path,
+			line)
+		if err != nil {
+			return nil, err
+		}
+
+		if next != 0 {
+			line = next
+			lineEntries, callSites, err = resolver.lineLocations(line)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resolver.ResolvedLine = 0
+	if len(lineEntries) > 0 || len(callSites) > 0 {
+		resolver.ResolvedLine = line
+	}
+
 	result := VirtualAddresses{}
 	for _, lineEntry := range lineEntries {
 		lineAddress, err := resolver.LoadedElves.LineEntryToVirtualAddress(lineEntry)
@@ -280,5 +369,54 @@ func (resolver *LineStopSiteResolver) resolveAddresses() (
 		result = append(result, lineAddress)
 	}
 
+	if len(result) > 0 {
+		return result, nil
+	}
+
+	// NOTE: a line may only exist as inlined call sites (i.e., the line has no
+	// line entries of its own).  Stop at the entry of each inlined instance.
+	for _, callSite := range callSites {
+		entryAddress, ok, err := callSite.EntryAddress()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		entryPC, err := resolver.LoadedElves.ToVirtualAddress(
+			callSite.File.File,
+			entryAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entryPC)
+	}
+
 	return result, nil
 }
+
+func (resolver *LineStopSiteResolver) lineLocations(
+	line int,
+) (
+	[]*dwarf.LineEntry,
+	[]*dwarf.DebugInfoEntry, // inlined call sites
+	error,
+) {
+	lineEntries, err := resolver.LoadedElves.LineEntriesByLine(
+		resolver.Path,
+		line)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	callSites, err := resolver.LoadedElves.InlinedCallSitesByLine(
+		resolver.Path,
+		line)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return lineEntries, callSites, nil
+}
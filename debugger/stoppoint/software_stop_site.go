@@ -2,27 +2,28 @@ package stoppoint
 
 import (
 	"fmt"
+	"sort"
 
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/memory"
 )
 
-const (
-	int3Instruction = byte(0xcc)
-)
-
 type softwareStopSitePool struct {
 	memory *memory.VirtualMemory
 
+	breakInstruction SoftwareBreakInstruction
+
 	allocated map[VirtualAddress]*softwareStopSite
 }
 
 func newSoftwareStopSitePool(
 	mem *memory.VirtualMemory,
+	breakInstruction SoftwareBreakInstruction,
 ) StopSitePool {
 	return &softwareStopSitePool{
-		memory:    mem,
-		allocated: map[VirtualAddress]*softwareStopSite{},
+		memory:           mem,
+		breakInstruction: breakInstruction,
+		allocated:        map[VirtualAddress]*softwareStopSite{},
 	}
 }
 
@@ -54,7 +55,7 @@ func (pool *softwareStopSitePool) Allocate(
 		siteType:     siteType,
 		address:      address,
 		isEnabled:    false,
-		originalData: 0,
+		originalData: nil,
 	}
 	pool.allocated[address] = site
 	return site, nil
@@ -100,6 +101,21 @@ func (pool *softwareStopSitePool) GetEnabledAt(
 	return result
 }
 
+func (pool *softwareStopSitePool) AllocatedSites() StopSites {
+	result := StopSites{}
+	for _, site := range pool.allocated {
+		result = append(result, site)
+	}
+
+	sort.Slice(
+		result,
+		func(i int, j int) bool {
+			return result[i].Address() < result[j].Address()
+		})
+
+	return result
+}
+
 func (pool *softwareStopSitePool) ListTriggered(
 	pc VirtualAddress,
 	kind TrapKind,
@@ -112,11 +128,10 @@ func (pool *softwareStopSitePool) ListTriggered(
 		return pc, nil, nil
 	}
 
-	// NOTE: stopSiteAddress may not be a valid instruction address since x64
-	// instruction could span multiple bytes. However, since software stop sites
-	// are implemented using int3 (0xcc), we know for sure the address is valid
-	// if the current instruction is a stop site.
-	stopSiteAddress := pc - 1
+	// NOTE: stopSiteAddress may not be a valid instruction address since
+	// instruction could span multiple bytes. However, we know for sure the
+	// address is valid if the current instruction is a stop site.
+	stopSiteAddress := pool.breakInstruction.SoftwareBreakSiteAddress(pc)
 
 	site, ok := pool.allocated[stopSiteAddress]
 	if ok && site.IsEnabled() {
@@ -129,6 +144,14 @@ func (pool *softwareStopSitePool) ListTriggered(
 	return pc, nil, nil
 }
 
+func (softwareStopSitePool) AvailableHardwareStopSites() int {
+	return 0
+}
+
+func (softwareStopSitePool) HardwareStopSiteAt(slot int) StopSite {
+	return nil
+}
+
 func (softwareStopSitePool) RefreshSites() error {
 	return nil
 }
@@ -140,7 +163,7 @@ type softwareStopSite struct {
 
 	address      VirtualAddress
 	isEnabled    bool
-	originalData byte
+	originalData []byte
 }
 
 func (site *softwareStopSite) Type() StopSiteType {
@@ -162,6 +185,10 @@ func (softwareStopSite) RefCount() int {
 	return 1
 }
 
+func (softwareStopSite) Mechanism() string {
+	return "int3"
+}
+
 func (site *softwareStopSite) Deallocate() error {
 	return site.pool.deallocate(site)
 }
@@ -175,7 +202,8 @@ func (site *softwareStopSite) Enable() error {
 		return nil
 	}
 
-	originalData, err := site.swapData(int3Instruction)
+	originalData, err := site.swapData(
+		site.pool.breakInstruction.SoftwareBreakInstruction())
 	if err != nil {
 		return fmt.Errorf("failed to enable software stop site: %w", err)
 	}
@@ -199,32 +227,31 @@ func (site *softwareStopSite) Disable() error {
 	return nil
 }
 
-func (site *softwareStopSite) swapData(newData byte) (byte, error) {
-	buffer := make([]byte, 1)
+func (site *softwareStopSite) swapData(newData []byte) ([]byte, error) {
+	originalData := make([]byte, len(newData))
 
-	count, err := site.pool.memory.Read(site.address, buffer)
+	count, err := site.pool.memory.Read(site.address, originalData)
 	if err != nil {
-		return 0, err
-	} else if count != 1 {
-		return 0, fmt.Errorf(
+		return nil, err
+	} else if count != len(originalData) {
+		return nil, fmt.Errorf(
 			"failed to read from memory at %s. "+
-				"incorrect number of bytes read (%d != 1)",
+				"incorrect number of bytes read (%d != %d)",
 			site.address,
-			count)
+			count,
+			len(originalData))
 	}
 
-	originalData := buffer[0]
-	buffer[0] = newData
-
-	count, err = site.pool.memory.Write(site.address, buffer)
+	count, err = site.pool.memory.Write(site.address, newData)
 	if err != nil {
-		return 0, err
-	} else if count != 1 {
-		return 0, fmt.Errorf(
+		return nil, err
+	} else if count != len(newData) {
+		return nil, fmt.Errorf(
 			"failed to write to memory at %s. "+
-				"incorrect number of bytes written (%d != 1)",
+				"incorrect number of bytes written (%d != %d)",
 			site.address,
-			count)
+			count,
+			len(newData))
 	}
 
 	return originalData, nil
@@ -239,8 +266,11 @@ func (site *softwareStopSite) ReplaceStopSiteBytes(
 	}
 
 	endAddr := startAddr + VirtualAddress(len(memorySlice))
-	if startAddr <= site.address && site.address < endAddr {
-		memorySlice[int(site.address-startAddr)] = site.originalData
+	for idx, original := range site.originalData {
+		address := site.address + VirtualAddress(idx)
+		if startAddr <= address && address < endAddr {
+			memorySlice[int(address-startAddr)] = original
+		}
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
 )
 
 type StopPointType struct {
@@ -62,6 +63,18 @@ func (set *StopPointSet) IsWatchPoints() bool {
 	return set.isWatchPoints
 }
 
+// Optional stop point attributes.  Stop points with different attributes are
+// not duplicates of each other (see SetWithOptions).
+type StopPointOptions struct {
+	// See StopPoint.SetCondition.
+	Condition string
+
+	// Identifies the scope (e.g., a scoped watch point's frame) the stop point
+	// is limited to.  Empty if the stop point is not scoped.
+	Scope string
+}
+
+// Sets an unconditional, unscoped stop point.
 func (set *StopPointSet) Set(
 	resolver StopSiteResolver,
 	siteType StopSiteType,
@@ -70,21 +83,65 @@ func (set *StopPointSet) Set(
 	*StopPoint,
 	error,
 ) {
+	return set.SetWithOptions(
+		resolver,
+		siteType,
+		enableOnCreation,
+		StopPointOptions{})
+}
+
+// Sets a stop point.  This returns an error if an identical stop point (same
+// type, location, condition and scope, without pending ignored hits) already
+// exists.  Stop points which only share locations are allowed (see
+// OverlappingPoints), e.g., break points at the same function with different
+// conditions.
+func (set *StopPointSet) SetWithOptions(
+	resolver StopSiteResolver,
+	siteType StopSiteType,
+	enableOnCreation bool,
+	options StopPointOptions,
+) (
+	*StopPoint,
+	error,
+) {
+	err := validateCondition(options.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	pointType := StopPointType{
+		IsWatchPoint: set.isWatchPoints,
+		StopSiteType: siteType,
+	}
+
+	// NOTE: resolution error is reported by ResolveStopSites below.
+	addresses, err := resolver.ResolveAddresses()
+	if err == nil {
+		existing := set.findDuplicate(resolver, pointType, options, addresses)
+		if existing != nil {
+			return nil, fmt.Errorf(
+				"%w. %s (id=%d) already set at %s",
+				ErrInvalidInput,
+				existing.Type(),
+				existing.Id(),
+				existing.Resolver())
+		}
+	}
+
 	id := set.nextId
 	set.nextId += 1
 
 	point := &StopPoint{
-		set:      set,
-		id:       id,
-		resolver: resolver,
-		pointType: StopPointType{
-			IsWatchPoint: set.isWatchPoints,
-			StopSiteType: siteType,
-		},
+		set:       set,
+		id:        id,
+		resolver:  resolver,
+		pointType: pointType,
 		isEnabled: enableOnCreation,
+		condition: options.Condition,
+		scope:     options.Scope,
 	}
 
-	err := point.ResolveStopSites()
+	err = point.ResolveStopSites()
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +150,80 @@ func (set *StopPointSet) Set(
 	return point, nil
 }
 
+// Returns an existing stop point of the same type, condition and scope (and
+// no pending ignored hits) that either has the same resolver or resolves to
+// the same (non-empty) set of addresses.
+func (set *StopPointSet) findDuplicate(
+	resolver StopSiteResolver,
+	pointType StopPointType,
+	options StopPointOptions,
+	addresses VirtualAddresses,
+) *StopPoint {
+	addressSet := map[VirtualAddress]struct{}{}
+	for _, addr := range addresses {
+		addressSet[addr] = struct{}{}
+	}
+
+	for _, point := range set.List() {
+		if point.pointType != pointType ||
+			point.condition != options.Condition ||
+			point.scope != options.Scope ||
+			point.ignoreCount != 0 {
+
+			continue
+		}
+
+		if point.resolver.String() == resolver.String() {
+			return point
+		}
+
+		if len(addressSet) == 0 || len(point.sites) != len(addressSet) {
+			continue
+		}
+
+		isDuplicate := true
+		for _, site := range point.sites {
+			_, ok := addressSet[site.Address()]
+			if !ok {
+				isDuplicate = false
+				break
+			}
+		}
+
+		if isDuplicate {
+			return point
+		}
+	}
+
+	return nil
+}
+
+// Returns other stop points (sorted by id) in the same set that share at
+// least one stop site address with this stop point.
+func (point *StopPoint) OverlappingPoints() []*StopPoint {
+	addresses := map[VirtualAddress]struct{}{}
+	for _, site := range point.sites {
+		addresses[site.Address()] = struct{}{}
+	}
+
+	result := []*StopPoint{}
+	for _, other := range point.set.List() {
+		if other == point {
+			continue
+		}
+
+		for _, site := range other.sites {
+			_, ok := addresses[site.Address()]
+			if ok {
+				result = append(result, other)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
 func (set *StopPointSet) Remove(id int64) error {
 	point, ok := set.allocated[id]
 	if !ok {
@@ -135,6 +266,9 @@ func (set *StopPointSet) List() []*StopPoint {
 type Triggered struct {
 	*StopPoint
 	StopSite
+
+	// The triggered site's index in the stop point's Sites().
+	SiteIndex int
 }
 
 func (set *StopPointSet) Match(
@@ -146,7 +280,7 @@ func (set *StopPointSet) Match(
 
 	result := []Triggered{}
 	for _, point := range set.allocated {
-		for _, site := range point.sites {
+		for idx, site := range point.sites {
 			_, ok := triggeredKeys[site.Key()]
 			if ok {
 				result = append(
@@ -154,6 +288,7 @@ func (set *StopPointSet) Match(
 					Triggered{
 						StopPoint: point,
 						StopSite:  site,
+						SiteIndex: idx,
 					})
 				break
 			}
@@ -166,6 +301,46 @@ func (set *StopPointSet) Match(
 	return result
 }
 
+// Rebind discards the stop points' sites (without deallocating / restoring
+// them) and re-resolves the stop points using the new allocator.  This is used
+// when the process' address space has been replaced (e.g., after execve), in
+// which case the old sites are no longer valid.
+func (set *StopPointSet) Rebind(allocator StopSiteAllocator) error {
+	set.bind(allocator)
+
+	for _, point := range set.allocated {
+		point.sites = nil
+	}
+
+	return set.ResolveStopSites()
+}
+
+// Reset discards all stop points (without deallocating / restoring their
+// sites), and binds the set to the new allocator.  Like Rebind, this is used
+// when the process' address space has been replaced.
+func (set *StopPointSet) Reset(allocator StopSiteAllocator) {
+	set.bind(allocator)
+
+	for _, point := range set.allocated {
+		point.sites = nil
+	}
+
+	set.nextId = 0
+	set.allocated = map[int64]*StopPoint{}
+}
+
+func (set *StopPointSet) bind(allocator StopSiteAllocator) {
+	if set.isWatchPoints {
+		set.siteAllocator = watchSiteAllocator{
+			base: allocator,
+		}
+	} else {
+		set.siteAllocator = breakSiteAllocator{
+			base: allocator,
+		}
+	}
+}
+
 func (set *StopPointSet) ResolveStopSites() error {
 	for _, point := range set.allocated {
 		err := point.ResolveStopSites()
@@ -186,6 +361,18 @@ type StopPoint struct {
 
 	isEnabled bool
 
+	// When non-empty, the stop point only stops the thread when the condition
+	// expression is satisfied.
+	condition string
+
+	// The number of upcoming (condition satisfied) hits to skip before the
+	// stop point stops the thread again.  This is decremented on every skipped
+	// hit.
+	ignoreCount int
+
+	// See StopPointOptions.Scope.
+	scope string
+
 	sites []StopSite
 }
 
@@ -205,6 +392,66 @@ func (point *StopPoint) IsEnabled() bool {
 	return point.isEnabled
 }
 
+func (point *StopPoint) Scope() string {
+	return point.scope
+}
+
+func (point *StopPoint) Condition() string {
+	return point.condition
+}
+
+// Set to empty string to clear the condition.  The condition's syntax is
+// checked when set, but the condition is only evaluated when the stop point
+// is hit (see Debugger.EvaluateCondition).
+func (point *StopPoint) SetCondition(condition string) error {
+	err := validateCondition(condition)
+	if err != nil {
+		return err
+	}
+
+	point.condition = condition
+	return nil
+}
+
+func validateCondition(condition string) error {
+	if condition == "" {
+		return nil
+	}
+
+	_, err := expression.ParseCondition(condition)
+	return err
+}
+
+func (point *StopPoint) IgnoreCount() int {
+	return point.ignoreCount
+}
+
+// Skip the next count hits.  Set to zero to clear the ignore count.
+func (point *StopPoint) SetIgnoreCount(count int) error {
+	if count < 0 {
+		return fmt.Errorf(
+			"%w. invalid ignore count (%d) for %s (id=%d)",
+			ErrInvalidInput,
+			count,
+			point.Type(),
+			point.Id())
+	}
+
+	point.ignoreCount = count
+	return nil
+}
+
+// Returns true (and decrements the ignore count) if the current hit should be
+// skipped.
+func (point *StopPoint) ConsumeIgnoreCount() bool {
+	if point.ignoreCount == 0 {
+		return false
+	}
+
+	point.ignoreCount -= 1
+	return true
+}
+
 func (point *StopPoint) Sites() []StopSite {
 	return point.sites
 }
@@ -13,12 +13,32 @@ type Process interface {
 	Memory() *memory.VirtualMemory
 }
 
+// NOTE: The architecture (see arch package) implements this interface.
+type SoftwareBreakInstruction interface {
+	// The trap instruction written to memory by software stop sites.
+	SoftwareBreakInstruction() []byte
+
+	// Returns the stop site address given the program counter reported by a
+	// software trap (e.g., x64's pc is one byte past the int3 instruction).
+	SoftwareBreakSiteAddress(pc VirtualAddress) VirtualAddress
+}
+
 type StopSiteMode string
 
 const (
-	WriteMode     = StopSiteMode("write")
+	// Stops after the watched address is written to.  Hardware only.
+	WriteMode = StopSiteMode("write")
+
+	// Stops after the watched address is read from or written to.  Hardware
+	// only.
 	ReadWriteMode = StopSiteMode("read/write")
-	ExecuteMode   = StopSiteMode("execute")
+
+	// Stops before the instruction at the address executes.  Software execute
+	// sites patch the instruction with a trap instruction (int3).  Hardware
+	// execute sites trigger on instruction fetch via a debug register, and do
+	// not modify memory; hence, they can stop in read-only / shared code, and
+	// are invisible to self-checksumming code.
+	ExecuteMode = StopSiteMode("execute")
 )
 
 type StopSiteType struct {
@@ -27,6 +47,8 @@ type StopSiteType struct {
 	WatchSize  int // 1, 2, 4, 8
 }
 
+// Returns an execute stop site type.  When isHardware is true, the site uses
+// one of the (four) debug registers instead of an int3 instruction.
 func NewBreakSiteType(isHardware bool) StopSiteType {
 	return StopSiteType{
 		IsHardware: isHardware,
@@ -84,6 +106,15 @@ func (t StopSiteType) Validate(address VirtualAddress) error {
 			t.WatchSize)
 	}
 
+	// NOTE: x64's debug control register requires the length bits to be
+	// 0b00 (1 byte) for instruction breakpoints.
+	if t.Mode == ExecuteMode && t.WatchSize != 1 {
+		return fmt.Errorf(
+			"%w. invalid execute stop site size (%d). expected 1",
+			ErrInvalidInput,
+			t.WatchSize)
+	}
+
 	if !t.IsHardware {
 		if t.Mode != ExecuteMode {
 			return fmt.Errorf(
@@ -124,6 +155,10 @@ type StopSite interface {
 
 	RefCount() int
 
+	// Describes how the stop site is implemented (e.g., "int3" or "debug
+	// register dr0").
+	Mechanism() string
+
 	// Deallocate disables the stop site and perform necessary cleanup.
 	Deallocate() error
 
@@ -170,6 +205,10 @@ type StopSitePool interface {
 
 	GetEnabledAt(address VirtualAddress) StopSites
 
+	// Returns all allocated (enabled and disabled) stop sites sorted by
+	// address.
+	AllocatedSites() StopSites
+
 	ListTriggered(
 		pc VirtualAddress,
 		kind TrapKind,
@@ -179,6 +218,15 @@ type StopSitePool interface {
 		error,
 	)
 
+	// Returns the number of unoccupied hardware stop sites (i.e., debug
+	// registers).  A hardware stop site is only released on deallocation;
+	// disabled sites remain occupied.
+	AvailableHardwareStopSites() int
+
+	// Returns the hardware stop site occupying the debug address register slot
+	// (i.e., dr<slot>), or nil if the slot is unoccupied.
+	HardwareStopSiteAt(slot int) StopSite
+
 	// Called when the debugger finds new threads.
 	RefreshSites() error
 }
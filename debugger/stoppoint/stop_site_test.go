@@ -0,0 +1,331 @@
+package stoppoint
+
+import (
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/ptrace/ptracetest"
+)
+
+type fakeProcess struct {
+	tracer *ptracetest.FakeTracer
+	memory *memory.VirtualMemory
+	regs   *registers.Registers
+}
+
+func newFakeProcess() *fakeProcess {
+	tracer := ptracetest.NewFakeTracer(1)
+	tracer.MapMemory(0x1000, []byte{0x55, 0x48, 0x89, 0xe5, 0x90, 0x90, 0x90, 0x90})
+
+	return &fakeProcess{
+		tracer: tracer,
+		memory: memory.New(tracer.Pid, tracer),
+		regs:   registers.New(tracer),
+	}
+}
+
+func (process *fakeProcess) AllRegisters() []*registers.Registers {
+	return []*registers.Registers{process.regs}
+}
+
+func (process *fakeProcess) Memory() *memory.VirtualMemory {
+	return process.memory
+}
+
+func (process *fakeProcess) registerValue(t *testing.T, name string) uint64 {
+	spec, ok := registers.ByName(name)
+	expect.True(t, ok)
+
+	state, err := process.regs.GetState()
+	expect.Nil(t, err)
+
+	return state.Value(spec).ToUint64()
+}
+
+type fakeBreakInstruction struct{}
+
+func (fakeBreakInstruction) SoftwareBreakInstruction() []byte {
+	return []byte{0xcc}
+}
+
+func (fakeBreakInstruction) SoftwareBreakSiteAddress(
+	pc VirtualAddress,
+) VirtualAddress {
+	return pc - 1
+}
+
+type StopSiteSuite struct{}
+
+func TestStopSite(t *testing.T) {
+	suite.RunTests(t, &StopSiteSuite{})
+}
+
+func (StopSiteSuite) TestSoftwareStopSite(t *testing.T) {
+	process := newFakeProcess()
+	pool := NewStopSitePool(process, fakeBreakInstruction{})
+
+	site, err := pool.Allocate(0x1001, NewBreakSiteType(false))
+	expect.Nil(t, err)
+
+	expect.Equal(t, "int3", site.Mechanism())
+
+	err = site.Enable()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		[]byte{0x55, 0xcc, 0x89, 0xe5},
+		process.tracer.Memory[0].Data[:4])
+
+	// The original instruction bytes are visible to the debugger.
+	content := make([]byte, 4)
+	_, err = process.memory.Read(0x1000, content)
+	expect.Nil(t, err)
+	pool.ReplaceStopSiteBytes(0x1000, content)
+	expect.Equal(t, []byte{0x55, 0x48, 0x89, 0xe5}, content)
+
+	addr, triggered, err := pool.ListTriggered(0x1002, SoftwareTrap)
+	expect.Nil(t, err)
+	expect.Equal(t, VirtualAddress(0x1001), addr)
+	expect.Equal(t, 1, len(triggered))
+
+	err = site.Deallocate()
+	expect.Nil(t, err)
+	expect.Equal(
+		t,
+		[]byte{0x55, 0x48, 0x89, 0xe5},
+		process.tracer.Memory[0].Data[:4])
+}
+
+func (StopSiteSuite) TestHardwareStopSite(t *testing.T) {
+	process := newFakeProcess()
+	pool := NewStopSitePool(process, fakeBreakInstruction{})
+
+	site, err := pool.Allocate(0x1004, NewWatchSiteType(WriteMode, 4))
+	expect.Nil(t, err)
+
+	err = site.Enable()
+	expect.Nil(t, err)
+
+	// Hardware stop sites don't modify memory.
+	expect.Equal(t, []byte{0x90, 0x90, 0x90, 0x90}, site.Data())
+	expect.Equal(t, 0x90, process.tracer.Memory[0].Data[4])
+
+	expect.Equal(t, 0x1004, process.registerValue(t, "dr0"))
+	// dr0 local enable, write only, 4 bytes
+	expect.Equal(t, 0b1101<<16|0b01, process.registerValue(t, "dr7"))
+
+	err = site.Deallocate()
+	expect.Nil(t, err)
+	expect.Equal(t, 0, process.registerValue(t, "dr0"))
+	expect.Equal(t, 0, process.registerValue(t, "dr7"))
+}
+
+func (StopSiteSuite) TestHardwareExecuteStopSite(t *testing.T) {
+	process := newFakeProcess()
+	pool := NewStopSitePool(process, fakeBreakInstruction{})
+
+	_, err := pool.Allocate(0x1004, NewWatchSiteType(ExecuteMode, 4))
+	expect.Error(t, err, "invalid execute stop site size (4)")
+
+	// Occupy dr0 so that the execute site is assigned dr1.
+	watch, err := pool.Allocate(0x1004, NewWatchSiteType(ReadWriteMode, 4))
+	expect.Nil(t, err)
+
+	site, err := pool.Allocate(0x1001, NewBreakSiteType(true))
+	expect.Nil(t, err)
+	expect.Equal(t, "debug register dr1", site.Mechanism())
+
+	err = site.Enable()
+	expect.Nil(t, err)
+
+	// Hardware execute sites don't patch the code.
+	expect.Equal(
+		t,
+		[]byte{0x55, 0x48, 0x89, 0xe5},
+		process.tracer.Memory[0].Data[:4])
+
+	expect.Equal(t, 0x1001, process.registerValue(t, "dr1"))
+	// dr1 local enable, execute, 1 byte
+	expect.Equal(t, 0b0100, process.registerValue(t, "dr7"))
+
+	err = watch.Enable()
+	expect.Nil(t, err)
+	expect.Equal(t, 0b1111<<16|0b0101, process.registerValue(t, "dr7"))
+
+	addr, triggered, err := pool.ListTriggered(0x1001, SoftwareTrap)
+	expect.Nil(t, err)
+	expect.Equal(t, VirtualAddress(0x1001), addr)
+	expect.Equal(t, 0, len(triggered))
+
+	// dr6 reports dr1 as triggered.
+	dr6, ok := registers.ByName("dr6")
+	expect.True(t, ok)
+
+	state, err := process.regs.GetState()
+	expect.Nil(t, err)
+	state, err = state.WithValue(dr6, registers.U64(0b10))
+	expect.Nil(t, err)
+	err = process.regs.SetState(state)
+	expect.Nil(t, err)
+
+	addr, triggered, err = pool.ListTriggered(0x1001, HardwareTrap)
+	expect.Nil(t, err)
+	expect.Equal(t, VirtualAddress(0x1001), addr)
+	expect.Equal(t, 1, len(triggered))
+
+	_, ok = triggered[site.Key()]
+	expect.True(t, ok)
+
+	err = site.Deallocate()
+	expect.Nil(t, err)
+	expect.Equal(t, 0b1111<<16|0b01, process.registerValue(t, "dr7"))
+}
+
+func (StopSiteSuite) TestDecodeDebugRegisters(t *testing.T) {
+	process := newFakeProcess()
+	pool := NewStopSitePool(process, fakeBreakInstruction{})
+
+	watch, err := pool.Allocate(0x1004, NewWatchSiteType(ReadWriteMode, 4))
+	expect.Nil(t, err)
+
+	err = watch.Enable()
+	expect.Nil(t, err)
+
+	site, err := pool.Allocate(0x1001, NewBreakSiteType(true))
+	expect.Nil(t, err)
+
+	err = site.Enable()
+	expect.Nil(t, err)
+
+	// Disabled sites still occupy their slots.
+	disabled, err := pool.Allocate(0x1002, NewWatchSiteType(WriteMode, 2))
+	expect.Nil(t, err)
+
+	expect.Equal(t, watch, pool.HardwareStopSiteAt(0))
+	expect.Equal(t, site, pool.HardwareStopSiteAt(1))
+	expect.Equal(t, disabled, pool.HardwareStopSiteAt(2))
+	expect.Nil(t, pool.HardwareStopSiteAt(3))
+	expect.Nil(t, pool.HardwareStopSiteAt(-1))
+	expect.Nil(t, pool.HardwareStopSiteAt(NumHardwareStopSites))
+
+	// dr6 reports dr1 as triggered (and single step).
+	state, err := process.regs.GetState()
+	expect.Nil(t, err)
+	state, err = state.WithValue(registers.DebugStatus, registers.U64(1<<14|0b10))
+	expect.Nil(t, err)
+
+	decoded := DecodeDebugRegisters(state)
+	expect.Equal(t, 0b1111<<16|0b0101, decoded.Control)
+	expect.Equal(t, 1<<14|0b10, decoded.Status)
+	expect.True(t, decoded.SingleStepped)
+	expect.False(t, decoded.AccessDetected)
+	expect.False(t, decoded.TaskSwitched)
+
+	expect.Equal(
+		t,
+		DebugRegisterSlot{
+			Index:            0,
+			Address:          0x1004,
+			IsLocallyEnabled: true,
+			Mode:             ReadWriteMode,
+			WatchSize:        4,
+		},
+		decoded.Slots[0])
+	expect.Equal(
+		t,
+		DebugRegisterSlot{
+			Index:            1,
+			Address:          0x1001,
+			IsLocallyEnabled: true,
+			Mode:             ExecuteMode,
+			WatchSize:        1,
+			Triggered:        true,
+		},
+		decoded.Slots[1])
+
+	// The disabled site's address is not loaded into its debug register.
+	expect.Equal(t, VirtualAddress(0), decoded.Slots[2].Address)
+	expect.False(t, decoded.Slots[2].IsEnabled())
+	expect.False(t, decoded.Slots[3].IsEnabled())
+
+	// dr3 globally enabled, I/O reads and writes, 8 bytes
+	state, err = state.WithValue(
+		registers.DebugControl,
+		registers.U64(0b1010<<28|0b10<<6))
+	expect.Nil(t, err)
+
+	decoded = DecodeDebugRegisters(state)
+	expect.False(t, decoded.Slots[0].IsEnabled())
+	expect.True(t, decoded.Slots[3].IsGloballyEnabled)
+	expect.False(t, decoded.Slots[3].IsLocallyEnabled)
+	expect.Equal(t, IOReadWriteMode, decoded.Slots[3].Mode)
+	expect.Equal(t, 8, decoded.Slots[3].WatchSize)
+}
+
+func (StopSiteSuite) TestHardwareStopSiteReclamation(t *testing.T) {
+	process := newFakeProcess()
+	pool := NewStopSitePool(process, fakeBreakInstruction{})
+	watchPoints := NewWatchPointSet(pool)
+	factory := StopSiteResolverFactory{}
+
+	process.tracer.MapMemory(0x2000, make([]byte, 0x200))
+
+	expect.Equal(t, NumHardwareStopSites, pool.AvailableHardwareStopSites())
+
+	// Cycle through many more watch points than there are debug registers.
+	// Each removed watch point must release its debug register.
+	for i := 0; i < 5*NumHardwareStopSites; i++ {
+		point, err := watchPoints.Set(
+			factory.NewAddressResolver(VirtualAddress(0x2000+8*i)),
+			NewWatchSiteType(WriteMode, 8),
+			true)
+		expect.Nil(t, err)
+		expect.Equal(t, NumHardwareStopSites-1, pool.AvailableHardwareStopSites())
+		expect.Equal(t, uint64(0x2000+8*i), process.registerValue(t, "dr0"))
+
+		err = watchPoints.Remove(point.Id())
+		expect.Nil(t, err)
+		expect.Equal(t, NumHardwareStopSites, pool.AvailableHardwareStopSites())
+		expect.Equal(t, 0, process.registerValue(t, "dr7"))
+	}
+
+	points := []*StopPoint{}
+	for i := 0; i < NumHardwareStopSites; i++ {
+		point, err := watchPoints.Set(
+			factory.NewAddressResolver(VirtualAddress(0x2100+8*i)),
+			NewWatchSiteType(WriteMode, 8),
+			true)
+		expect.Nil(t, err)
+		points = append(points, point)
+	}
+	expect.Equal(t, 0, pool.AvailableHardwareStopSites())
+
+	// Disabled watch points still occupy their debug registers.
+	err := points[1].Disable()
+	expect.Nil(t, err)
+	expect.Equal(t, 0, pool.AvailableHardwareStopSites())
+
+	_, err = watchPoints.Set(
+		factory.NewAddressResolver(0x2180),
+		NewWatchSiteType(WriteMode, 8),
+		true)
+	expect.Error(t, err, "all available hardware stop sites occupied")
+
+	err = watchPoints.Remove(points[1].Id())
+	expect.Nil(t, err)
+	expect.Equal(t, 1, pool.AvailableHardwareStopSites())
+
+	// The released debug register (dr1) is reused.
+	point, err := watchPoints.Set(
+		factory.NewAddressResolver(0x2180),
+		NewWatchSiteType(WriteMode, 8),
+		true)
+	expect.Nil(t, err)
+	expect.Equal(t, 0, pool.AvailableHardwareStopSites())
+	expect.Equal(t, "debug register dr1", point.Sites()[0].Mechanism())
+}
@@ -0,0 +1,318 @@
+package debugger
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/loadedelves"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/elf"
+	"github.com/pattyshack/bad/procfs"
+)
+
+// Matches a trailing "+ <offset>" / "- <offset>" integer literal.
+var addressOffsetPattern = regexp.MustCompile(
+	`^(.*[^\s])\s*([+-])\s*(0[xX][0-9a-fA-F]+|[0-9]+)\s*$`)
+
+// A symbolic description of a virtual address, i.e., the reverse of resolving
+// a symbol name to an address.
+type AddressSymbol struct {
+	Address VirtualAddress
+
+	// The loaded elf file containing the address, and the containing section's
+	// name.  File is nil if the address is not in any loaded elf file's memory
+	// occupying section.
+	File        *loadedelves.File
+	SectionName string
+
+	// The symbol spanning (or nearest preceding) the address, and the address'
+	// offset from the symbol.  nil if no symbol matches the address.
+	Symbol *elf.Symbol
+	Offset uint64
+
+	// The memory mapping containing the address when the address is not in any
+	// loaded elf file (e.g., heap / stack addresses).  nil if the address is
+	// not mapped.
+	Mapping *procfs.MappedMemoryRegion
+}
+
+func (symbol AddressSymbol) String() string {
+	if symbol.File != nil {
+		module := symbol.File.FileName
+		if module == "" {
+			module = "(executable)"
+		}
+
+		location := fmt.Sprintf("in section %s of %s", symbol.SectionName, module)
+		if symbol.Symbol == nil {
+			return fmt.Sprintf(
+				"%s: no symbol matches address; %s",
+				symbol.Address,
+				location)
+		}
+
+		name := symbol.Symbol.PrettyName()
+		if symbol.Offset > 0 {
+			name = fmt.Sprintf("%s + %d", name, symbol.Offset)
+		}
+
+		return fmt.Sprintf("%s: %s %s", symbol.Address, name, location)
+	}
+
+	if symbol.Mapping == nil {
+		return fmt.Sprintf("%s: address is not mapped", symbol.Address)
+	}
+
+	name := symbol.Mapping.Pathname
+	if name == "" {
+		name = "(anonymous)"
+	}
+
+	return fmt.Sprintf(
+		"%s: no symbol matches address; in mapping %s-%s %s %s",
+		symbol.Address,
+		VirtualAddress(symbol.Mapping.LowAddress),
+		VirtualAddress(symbol.Mapping.HighAddress),
+		mappingPermissions(symbol.Mapping),
+		name)
+}
+
+func mappingPermissions(mapping *procfs.MappedMemoryRegion) string {
+	permissions := []byte("---s")
+	if mapping.Read {
+		permissions[0] = 'r'
+	}
+	if mapping.Write {
+		permissions[1] = 'w'
+	}
+	if mapping.Execute {
+		permissions[2] = 'x'
+	}
+	if mapping.Private {
+		permissions[3] = 'p'
+	}
+	return string(permissions)
+}
+
+// Returns the symbol / section / module containing the address.  If the
+// address is not in any loaded elf file, the containing memory mapping is
+// returned instead.
+func (db *Debugger) SymbolizeAddress(
+	address VirtualAddress,
+) (
+	AddressSymbol,
+	error,
+) {
+	result := AddressSymbol{
+		Address: address,
+	}
+
+	for _, file := range db.LoadedElves.Files() {
+		sectionIdx := file.SectionIndexContaining(address)
+		if sectionIdx == -1 {
+			continue
+		}
+
+		result.File = file
+		result.SectionName = file.Sections[sectionIdx].Name()
+
+		symbol := file.NearestSymbol(address)
+		if symbol != nil {
+			result.Symbol = symbol
+			result.Offset = uint64(file.ToFileAddress(address)) - symbol.Value
+		}
+
+		return result, nil
+	}
+
+	if db.processTerminated() {
+		return result, nil
+	}
+
+	regions, err := procfs.GetMappedMemoryRegions(db.Pid)
+	if err != nil {
+		return AddressSymbol{}, err
+	}
+
+	for _, region := range regions {
+		if region.LowAddress <= uint64(address) &&
+			uint64(address) < region.HighAddress {
+
+			result.Mapping = &region
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Evaluates the address expression, which is of the form
+//
+//	<base> [(+|-) <offset>]
+//
+// where base is one of:
+//   - an integer (e.g., 0x401000) or an elf file address (see
+//     loadedelves.Files.ParseAddress)
+//   - $<register> (e.g., $pc, $sp or $rax), read from the current thread's
+//     inspect frame
+//   - an expression which evaluates to a pointer / integer value (e.g., a
+//     pointer variable)
+//   - a symbol name (e.g., a function or array name), which evaluates to the
+//     symbol's address
+func (db *Debugger) EvaluateAddress(
+	addressExpression string,
+) (
+	VirtualAddress,
+	error,
+) {
+	base := strings.TrimSpace(addressExpression)
+	if base == "" {
+		return 0, fmt.Errorf("%w. address not specified", ErrInvalidInput)
+	}
+
+	offset := int64(0)
+	match := addressOffsetPattern.FindStringSubmatch(base)
+	if match != nil {
+		value, err := strconv.ParseUint(match[3], 0, 63)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"%w. invalid address offset (%s): %w",
+				ErrInvalidInput,
+				match[3],
+				err)
+		}
+
+		base = match[1]
+		offset = int64(value)
+		if match[2] == "-" {
+			offset = -offset
+		}
+	}
+
+	address, err := db.evaluateBaseAddress(base)
+	if err != nil {
+		return 0, err
+	}
+
+	return address + VirtualAddress(offset), nil
+}
+
+func (db *Debugger) evaluateBaseAddress(
+	base string,
+) (
+	VirtualAddress,
+	error,
+) {
+	if strings.HasPrefix(base, "$") {
+		return db.evaluateRegisterAddress(base[1:])
+	}
+
+	address, err := db.LoadedElves.ParseAddress(base)
+	if err == nil {
+		return address, nil
+	}
+
+	// NOTE: the symbol lookup is a fallback since a variable's value takes
+	// precedence over the variable's address.  Function / array values are
+	// not simple values, and are resolved to their symbols' addresses.
+	address, evalErr := db.evaluateAddressValue(base)
+	if evalErr == nil {
+		return address, nil
+	}
+
+	for _, symbol := range db.LoadedElves.SymbolsByName(base) {
+		_, _, ok := symbol.AddressRange()
+		if !ok {
+			continue
+		}
+
+		return db.LoadedElves.SymbolToVirtualAddress(symbol)
+	}
+
+	return 0, evalErr
+}
+
+func (db *Debugger) evaluateRegisterAddress(
+	name string,
+) (
+	VirtualAddress,
+	error,
+) {
+	var spec registers.Spec
+	switch name {
+	case "pc":
+		spec = registers.ProgramCounter
+	case "sp":
+		spec = registers.StackPointer
+	case "fp":
+		spec = registers.FramePointer
+	default:
+		var ok bool
+		spec, ok = registers.ByName(name)
+		if !ok || spec.Class != registers.GeneralClass {
+			return 0, fmt.Errorf(
+				"%w. invalid general register ($%s)",
+				ErrInvalidInput,
+				name)
+		}
+	}
+
+	state, err := db.GetInspectFrameRegisterState()
+	if err != nil {
+		return 0, err
+	}
+
+	value := state.Value(spec)
+	if value == nil {
+		return 0, fmt.Errorf("register %s is undefined in frame", spec.Name)
+	}
+
+	return VirtualAddress(value.ToUint64()), nil
+}
+
+func (db *Debugger) evaluateAddressValue(
+	valueExpression string,
+) (
+	VirtualAddress,
+	error,
+) {
+	data, err := expression.Evaluate(db, valueExpression)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := data.DecodeSimpleValue()
+	if err != nil {
+		return 0, err
+	}
+
+	switch value := value.(type) {
+	case VirtualAddress:
+		return value, nil
+	case byte:
+		return VirtualAddress(value), nil
+	case int8:
+		return VirtualAddress(value), nil
+	case int16:
+		return VirtualAddress(value), nil
+	case uint16:
+		return VirtualAddress(value), nil
+	case int32:
+		return VirtualAddress(value), nil
+	case uint32:
+		return VirtualAddress(value), nil
+	case int64:
+		return VirtualAddress(value), nil
+	case uint64:
+		return VirtualAddress(value), nil
+	}
+
+	return 0, fmt.Errorf(
+		"%w. %s is not a pointer / integer value",
+		ErrInvalidInput,
+		valueExpression)
+}
@@ -0,0 +1,31 @@
+struct bits {
+  unsigned int u1 : 1;
+  unsigned int u3 : 3;
+  int s5 : 5;
+  unsigned int u7 : 7;
+  int s12 : 12;
+  unsigned int u4 : 4;
+  long long s33 : 33;
+  unsigned long long u31 : 31;
+  signed char s3 : 3;
+  unsigned short u9 : 9;
+  int s1 : 1;
+  bool b : 1;
+};
+
+struct __attribute__((packed)) packed_bits {
+  unsigned char u5 : 5;
+  int s30 : 30;
+  unsigned int u29 : 29;
+};
+
+bits g_min = { 0, 0, -16, 0, -2048, 0, -4294967296LL, 0, -4, 0, -1, false };
+bits g_max = { 1, 7, 15, 127, 2047, 15, 4294967295LL, 2147483647, 3, 511, 0, true };
+bits g_mixed = { 1, 5, -11, 85, -1234, 9, -123456789LL, 1234567, -3, 300, -1, true };
+
+packed_bits g_packed_min = { 0, -536870912, 0 };
+packed_bits g_packed_mixed = { 21, -12345678, 305419896 };
+
+int main() {
+  return g_mixed.b ? 0 : 1;
+}
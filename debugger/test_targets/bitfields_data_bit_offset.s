@@ -0,0 +1,623 @@
+# Generated by `g++ -S -g -O0 -gdwarf-4 -dA bitfields.cpp` (see bitfields.cpp)
+#
+# NOTE: gcc only emits dwarf4's deprecated bit-packed field encoding
+# (DW_AT_bit_offset, relative to the storage unit's most significant bit, and
+# DW_AT_data_member_location).  To simulate other producers (e.g., clang), the
+# bit-packed members are manually patched to use dwarf4's preferred encoding
+# (DW_AT_data_bit_offset, relative to the beginning of the struct).  The
+# patched entries have the same sizes as the original entries:
+#   - DW_AT_bit_offset (data1 / sdata) and DW_AT_data_member_location (data1)
+#     are replaced by a single DW_AT_data_bit_offset (data2)
+#   - DW_AT_byte_size is left as is
+
+	.file	"bitfields.cpp"
+	.text
+.Ltext0:
+	.file 1 "bitfields.cpp"
+	.globl	g_min
+	.data
+	.align 16
+	.type	g_min, @object
+	.size	g_min, 24
+g_min:
+	.byte	0
+	.byte	1
+	.byte	0
+	.byte	8
+	.zero	4
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	1
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	4
+	.byte	16
+	.zero	6
+	.globl	g_max
+	.align 16
+	.type	g_max, @object
+	.size	g_max, 24
+g_max:
+	.byte	255
+	.byte	254
+	.byte	255
+	.byte	247
+	.zero	4
+	.byte	255
+	.byte	255
+	.byte	255
+	.byte	255
+	.byte	254
+	.byte	255
+	.byte	255
+	.byte	255
+	.byte	251
+	.byte	47
+	.zero	6
+	.globl	g_mixed
+	.align 16
+	.type	g_mixed, @object
+	.size	g_mixed, 24
+g_mixed:
+	.byte	91
+	.byte	171
+	.byte	46
+	.byte	155
+	.zero	4
+	.byte	235
+	.byte	50
+	.byte	164
+	.byte	248
+	.byte	15
+	.byte	173
+	.byte	37
+	.byte	0
+	.byte	101
+	.byte	57
+	.zero	6
+	.globl	g_packed_min
+	.align 8
+	.type	g_packed_min, @object
+	.size	g_packed_min, 8
+g_packed_min:
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	0
+	.byte	4
+	.byte	0
+	.byte	0
+	.byte	0
+	.globl	g_packed_mixed
+	.align 8
+	.type	g_packed_mixed, @object
+	.size	g_packed_mixed, 8
+g_packed_mixed:
+	.byte	85
+	.byte	214
+	.byte	115
+	.byte	232
+	.byte	199
+	.byte	179
+	.byte	162
+	.byte	145
+	.text
+	.globl	main
+	.type	main, @function
+main:
+.LFB0:
+	# bitfields.cpp:29:12
+	.loc 1 29 12
+	.cfi_startproc
+# BLOCK 2 seq:0
+# PRED: ENTRY (FALLTHRU)
+	pushq	%rbp
+	.cfi_def_cfa_offset 16
+	.cfi_offset 6, -16
+	movq	%rsp, %rbp
+	.cfi_def_cfa_register 6
+	# bitfields.cpp:30:18
+	.loc 1 30 18
+	movzbl	17+g_mixed(%rip), %eax
+	shrb	$5, %al
+	andl	$1, %eax
+	# bitfields.cpp:30:20
+	.loc 1 30 20
+	testb	%al, %al
+# SUCC: 3 (FALLTHRU) 4
+	je	.L2
+# BLOCK 3 seq:1
+# PRED: 2 (FALLTHRU)
+	# bitfields.cpp:30:20
+	.loc 1 30 20 is_stmt 0 discriminator 1
+	movl	$0, %eax
+# SUCC: 5 [always]  bitfields.cpp:30:26
+	# bitfields.cpp:30:26
+	.loc 1 30 26 is_stmt 1 discriminator 1
+	jmp	.L5
+# BLOCK 4 seq:2
+# PRED: 2
+.L2:
+# SUCC: 5 (FALLTHRU) bitfields.cpp:30:26
+	# bitfields.cpp:30:20
+	.loc 1 30 20 discriminator 2
+	movl	$1, %eax
+# BLOCK 5 seq:3
+# PRED: 4 (FALLTHRU) bitfields.cpp:30:26 3 [always]  bitfields.cpp:30:26
+.L5:
+	# bitfields.cpp:30:26
+	.loc 1 30 26
+	nop
+	# bitfields.cpp:31:1
+	.loc 1 31 1
+	popq	%rbp
+	.cfi_def_cfa 7, 8
+# SUCC: EXIT [always] 
+	ret
+	.cfi_endproc
+.LFE0:
+	.size	main, .-main
+.Letext0:
+	.section	.debug_info,"",@progbits
+.Ldebug_info0:
+	.long	0x1ef	# Length of Compilation Unit Info
+	.value	0x4	# DWARF version number
+	.long	.Ldebug_abbrev0	# Offset Into Abbrev. Section
+	.byte	0x8	# Pointer Size (in bytes)
+	.uleb128 0x1	# (DIE (0xb) DW_TAG_compile_unit)
+	.long	.LASF14	# DW_AT_producer: "GNU C++17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+	.byte	0x4	# DW_AT_language
+	.long	.LASF15	# DW_AT_name: "bitfields.cpp"
+	.long	.LASF16	# DW_AT_comp_dir: "/tmp/bf"
+	.quad	.Ltext0	# DW_AT_low_pc
+	.quad	.Letext0-.Ltext0	# DW_AT_high_pc
+	.long	.Ldebug_line0	# DW_AT_stmt_list
+	.uleb128 0x2	# (DIE (0x2d) DW_TAG_structure_type)
+	.long	.LASF6	# DW_AT_name: "bits"
+	.byte	0x18	# DW_AT_byte_size
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x1	# DW_AT_decl_line
+	.byte	0x8	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_sibling
+	.uleb128 0x3	# (DIE (0x3a) DW_TAG_member)
+	.ascii "u1\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x2	# DW_AT_decl_line
+	.byte	0x10	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x1	# DW_AT_bit_size
+	.value	0x0	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x49) DW_TAG_member)
+	.ascii "u3\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x3	# DW_AT_decl_line
+	.byte	0x10	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x3	# DW_AT_bit_size
+	.value	0x1	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x58) DW_TAG_member)
+	.ascii "s5\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x4	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0xf8	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_bit_size
+	.value	0x4	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x67) DW_TAG_member)
+	.ascii "u7\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x5	# DW_AT_decl_line
+	.byte	0x10	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_bit_size
+	.value	0x9	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x76) DW_TAG_member)
+	.ascii "s12\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x6	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0xf8	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0xc	# DW_AT_bit_size
+	.value	0x10	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x86) DW_TAG_member)
+	.ascii "u4\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x7	# DW_AT_decl_line
+	.byte	0x10	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x4	# DW_AT_bit_size
+	.value	0x1c	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x95) DW_TAG_member)
+	.ascii "s33\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x8	# DW_AT_decl_line
+	.byte	0xd	# DW_AT_decl_column
+	.long	0xff	# DW_AT_type
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x21	# DW_AT_bit_size
+	.value	0x40	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0xa5) DW_TAG_member)
+	.ascii "u31\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x9	# DW_AT_decl_line
+	.byte	0x16	# DW_AT_decl_column
+	.long	0x106	# DW_AT_type
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x1f	# DW_AT_bit_size
+	.value	0x61	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0xb5) DW_TAG_member)
+	.ascii "s3\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0xa	# DW_AT_decl_line
+	.byte	0xf	# DW_AT_decl_column
+	.long	0x10d	# DW_AT_type
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x3	# DW_AT_bit_size
+	.value	0x80	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0xc4) DW_TAG_member)
+	.ascii "u9\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0xb	# DW_AT_decl_line
+	.byte	0x12	# DW_AT_decl_column
+	.long	0x114	# DW_AT_type
+	.byte	0x2	# DW_AT_byte_size
+	.byte	0x9	# DW_AT_bit_size
+	.value	0x83	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0xd3) DW_TAG_member)
+	.ascii "s1\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0xc	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0xf8	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x1	# DW_AT_bit_size
+	.value	0x8c	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0xe2) DW_TAG_member)
+	.ascii "b\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0xd	# DW_AT_decl_line
+	.byte	0x8	# DW_AT_decl_column
+	.long	0x11b	# DW_AT_type
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x1	# DW_AT_bit_size
+	.value	0x8d	# DW_AT_data_bit_offset
+	.byte	0	# end of children of DIE 0x2d
+	.uleb128 0x4	# (DIE (0xf1) DW_TAG_base_type)
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF0	# DW_AT_name: "unsigned int"
+	.uleb128 0x5	# (DIE (0xf8) DW_TAG_base_type)
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.ascii "int\0"	# DW_AT_name
+	.uleb128 0x4	# (DIE (0xff) DW_TAG_base_type)
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.long	.LASF1	# DW_AT_name: "long long int"
+	.uleb128 0x4	# (DIE (0x106) DW_TAG_base_type)
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF2	# DW_AT_name: "long long unsigned int"
+	.uleb128 0x4	# (DIE (0x10d) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x6	# DW_AT_encoding
+	.long	.LASF3	# DW_AT_name: "signed char"
+	.uleb128 0x4	# (DIE (0x114) DW_TAG_base_type)
+	.byte	0x2	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF4	# DW_AT_name: "short unsigned int"
+	.uleb128 0x4	# (DIE (0x11b) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x2	# DW_AT_encoding
+	.long	.LASF5	# DW_AT_name: "bool"
+	.uleb128 0x2	# (DIE (0x122) DW_TAG_structure_type)
+	.long	.LASF7	# DW_AT_name: "packed_bits"
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x10	# DW_AT_decl_line
+	.byte	0x20	# DW_AT_decl_column
+	.long	0x15f	# DW_AT_sibling
+	.uleb128 0x3	# (DIE (0x12f) DW_TAG_member)
+	.ascii "u5\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x11	# DW_AT_decl_line
+	.byte	0x11	# DW_AT_decl_column
+	.long	0x15f	# DW_AT_type
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_bit_size
+	.value	0x0	# DW_AT_data_bit_offset
+	.uleb128 0x6	# (DIE (0x13e) DW_TAG_member)
+	.ascii "s30\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x12	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0xf8	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x1e	# DW_AT_bit_size
+	.value	0x5	# DW_AT_data_bit_offset
+	.uleb128 0x3	# (DIE (0x14e) DW_TAG_member)
+	.ascii "u29\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x13	# DW_AT_decl_line
+	.byte	0x10	# DW_AT_decl_column
+	.long	0xf1	# DW_AT_type
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x1d	# DW_AT_bit_size
+	.value	0x23	# DW_AT_data_bit_offset
+	.byte	0	# end of children of DIE 0x122
+	.uleb128 0x4	# (DIE (0x15f) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x8	# DW_AT_encoding
+	.long	.LASF8	# DW_AT_name: "unsigned char"
+	.uleb128 0x7	# (DIE (0x166) DW_TAG_variable)
+	.long	.LASF9	# DW_AT_name: "g_min"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x16	# DW_AT_decl_line
+	.byte	0x6	# DW_AT_decl_column
+	.long	0x2d	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	g_min
+	.uleb128 0x7	# (DIE (0x17c) DW_TAG_variable)
+	.long	.LASF10	# DW_AT_name: "g_max"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x17	# DW_AT_decl_line
+	.byte	0x6	# DW_AT_decl_column
+	.long	0x2d	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	g_max
+	.uleb128 0x7	# (DIE (0x192) DW_TAG_variable)
+	.long	.LASF11	# DW_AT_name: "g_mixed"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x18	# DW_AT_decl_line
+	.byte	0x6	# DW_AT_decl_column
+	.long	0x2d	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	g_mixed
+	.uleb128 0x7	# (DIE (0x1a8) DW_TAG_variable)
+	.long	.LASF12	# DW_AT_name: "g_packed_min"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x1a	# DW_AT_decl_line
+	.byte	0xd	# DW_AT_decl_column
+	.long	0x122	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	g_packed_min
+	.uleb128 0x7	# (DIE (0x1be) DW_TAG_variable)
+	.long	.LASF13	# DW_AT_name: "g_packed_mixed"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x1b	# DW_AT_decl_line
+	.byte	0xd	# DW_AT_decl_column
+	.long	0x122	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	g_packed_mixed
+	.uleb128 0x8	# (DIE (0x1d4) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF17	# DW_AT_name: "main"
+	.byte	0x1	# DW_AT_decl_file (bitfields.cpp)
+	.byte	0x1d	# DW_AT_decl_line
+	.byte	0x5	# DW_AT_decl_column
+	.long	0xf8	# DW_AT_type
+	.quad	.LFB0	# DW_AT_low_pc
+	.quad	.LFE0-.LFB0	# DW_AT_high_pc
+	.uleb128 0x1	# DW_AT_frame_base
+	.byte	0x9c	# DW_OP_call_frame_cfa
+			# DW_AT_GNU_all_call_sites
+	.byte	0	# end of children of DIE 0xb
+	.section	.debug_abbrev,"",@progbits
+.Ldebug_abbrev0:
+	.uleb128 0x1	# (abbrev code)
+	.uleb128 0x11	# (TAG: DW_TAG_compile_unit)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x25	# (DW_AT_producer)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x13	# (DW_AT_language)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x1b	# (DW_AT_comp_dir)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x10	# (DW_AT_stmt_list)
+	.uleb128 0x17	# (DW_FORM_sec_offset)
+	.byte	0
+	.byte	0
+	.uleb128 0x2	# (abbrev code)
+	.uleb128 0x13	# (TAG: DW_TAG_structure_type)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x1	# (DW_AT_sibling)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x3	# (abbrev code)
+	.uleb128 0xd	# (TAG: DW_TAG_member)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0xd	# (DW_AT_bit_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x6b	# (DW_AT_data_bit_offset)
+	.uleb128 0x5	# (DW_FORM_data2)
+	.byte	0
+	.byte	0
+	.uleb128 0x4	# (abbrev code)
+	.uleb128 0x24	# (TAG: DW_TAG_base_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3e	# (DW_AT_encoding)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.byte	0
+	.byte	0
+	.uleb128 0x5	# (abbrev code)
+	.uleb128 0x24	# (TAG: DW_TAG_base_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3e	# (DW_AT_encoding)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.byte	0
+	.byte	0
+	.uleb128 0x6	# (abbrev code)
+	.uleb128 0xd	# (TAG: DW_TAG_member)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0xd	# (DW_AT_bit_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x6b	# (DW_AT_data_bit_offset)
+	.uleb128 0x5	# (DW_FORM_data2)
+	.byte	0
+	.byte	0
+	.uleb128 0x7	# (abbrev code)
+	.uleb128 0x34	# (TAG: DW_TAG_variable)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x2	# (DW_AT_location)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.uleb128 0x8	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0	# DW_children_no
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x40	# (DW_AT_frame_base)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.uleb128 0x2117	# (DW_AT_GNU_all_call_sites)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.byte	0
+	.byte	0
+	.byte	0
+	.section	.debug_aranges,"",@progbits
+	.long	0x2c	# Length of Address Ranges Info
+	.value	0x2	# DWARF aranges version
+	.long	.Ldebug_info0	# Offset of Compilation Unit Info
+	.byte	0x8	# Size of Address
+	.byte	0	# Size of Segment Descriptor
+	.value	0	# Pad to 16 byte boundary
+	.value	0
+	.quad	.Ltext0	# Address
+	.quad	.Letext0-.Ltext0	# Length
+	.quad	0
+	.quad	0
+	.section	.debug_line,"",@progbits
+.Ldebug_line0:
+	.section	.debug_str,"MS",@progbits,1
+.LASF1:
+	.string	"long long int"
+.LASF8:
+	.string	"unsigned char"
+.LASF11:
+	.string	"g_mixed"
+.LASF12:
+	.string	"g_packed_min"
+.LASF15:
+	.string	"bitfields.cpp"
+.LASF2:
+	.string	"long long unsigned int"
+.LASF5:
+	.string	"bool"
+.LASF6:
+	.string	"bits"
+.LASF14:
+	.string	"GNU C++17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+.LASF17:
+	.string	"main"
+.LASF9:
+	.string	"g_min"
+.LASF13:
+	.string	"g_packed_mixed"
+.LASF7:
+	.string	"packed_bits"
+.LASF4:
+	.string	"short unsigned int"
+.LASF3:
+	.string	"signed char"
+.LASF16:
+	.string	"/tmp/bf"
+.LASF0:
+	.string	"unsigned int"
+.LASF10:
+	.string	"g_max"
+	.ident	"GCC: (Debian 12.2.0-14+deb12u1) 12.2.0"
+	.section	.note.GNU-stack,"",@progbits
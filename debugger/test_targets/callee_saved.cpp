@@ -0,0 +1,41 @@
+#include <cstdio>
+
+// NOTE: clobbers the caller's callee-saved rbx after saving it on the stack.
+// The caller's rbx value is only recoverable via the call frame information.
+__attribute__((noipa)) int clobber(int value) {
+  asm volatile("mov $0xdead, %%ebx" : : : "rbx");
+  return value + 1;
+}
+
+__attribute__((noipa)) int accumulate(int seed) {
+  int total = seed * 3;
+  asm volatile("" : "+r"(total));
+  int result = clobber(seed);
+  return total + result;
+}
+
+extern "C" __attribute__((noinline)) void marker() {
+  asm volatile("" : : : "memory");
+}
+
+// NOTE: saves the caller's xmm0 on the stack (described by DW_CFA_offset for
+// dwarf register 17) and zeros xmm0 before calling marker.
+extern "C" __attribute__((naked)) void spill_xmm0(double value) {
+  asm volatile(
+      "sub $24, %rsp\n"
+      ".cfi_adjust_cfa_offset 24\n"
+      "movdqu %xmm0, (%rsp)\n"
+      ".cfi_offset 17, -32\n"
+      "pxor %xmm0, %xmm0\n"
+      "call marker\n"
+      "movdqu (%rsp), %xmm0\n"
+      ".cfi_restore 17\n"
+      "add $24, %rsp\n"
+      ".cfi_adjust_cfa_offset -24\n"
+      "ret\n");
+}
+
+int main() {
+  spill_xmm0(1.5);
+  std::printf("%d\n", accumulate(14));
+}
@@ -0,0 +1,21 @@
+#include <cstdio>
+
+int inc(int x) {
+  return x + 1;
+}
+
+int dec(int x) {
+  return x - 1;
+}
+
+int twice(int x) {
+  return x * 2;
+}
+
+int main(int argc, char** argv) {
+  bool selected = argc > 1;
+  int a = selected ? inc(1) : dec(2);
+  int b = twice(inc(3)) + dec(4);
+  std::printf("%d %d\n", a, b);
+  return 0;
+}
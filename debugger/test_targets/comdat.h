@@ -0,0 +1,20 @@
+#pragma once
+
+enum class level { low = 1, mid = 5, high = 9 };
+
+// C++17 inline variables are emitted into every translation unit which uses
+// them (COMDAT), and folded into a single definition by the linker.
+inline int g_shared_counter = 7;
+
+// Internal linkage constant (one copy per translation unit).
+constexpr int g_header_limit = 42;
+
+struct settings {
+  static inline int instances = 3;
+
+  // Not odr-used, i.e., only the value is emitted (no storage).
+  static constexpr long max_size = 1024;
+  static constexpr level default_level = level::mid;
+};
+
+int bump_from_other();
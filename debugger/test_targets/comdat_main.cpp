@@ -0,0 +1,10 @@
+#include <cstdio>
+
+#include "comdat.h"
+
+int main() {
+  g_shared_counter += settings::instances;
+  int total = bump_from_other() + g_header_limit + (int)settings::max_size;
+  std::printf("%d\n", total);
+  return 0;
+}
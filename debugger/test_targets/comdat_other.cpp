@@ -0,0 +1,7 @@
+#include "comdat.h"
+
+int bump_from_other() {
+  settings::instances += 1;
+  g_shared_counter += 1;
+  return g_shared_counter + g_header_limit + (int)settings::default_level;
+}
@@ -0,0 +1,8 @@
+int g_counter = 0;
+
+int main() {
+  for (int i = 0; i < 10; ++i) {
+    g_counter += 1;
+  }
+  return 0;
+}
@@ -0,0 +1,37 @@
+#include <cstdio>
+
+struct shape {
+  virtual ~shape() {}
+  virtual int sides() const { return 0; }
+  int id = 1;
+};
+
+struct named {
+  virtual const char* name() const { return "named"; }
+  int length = 5;
+};
+
+struct square : shape, named {
+  int sides() const override { return 4; }
+  const char* name() const override { return "square"; }
+  int width = 7;
+};
+
+struct plain {
+  int value = 3;
+};
+
+square g_square;
+shape g_base_shape;
+plain g_plain;
+
+shape* g_shape = &g_square;
+named* g_named = &g_square;  // non-primary base (non-zero offset to top)
+shape* g_base = &g_base_shape;
+shape* g_null = nullptr;
+plain* g_plain_ptr = &g_plain;
+
+int main() {
+  std::printf("%d %s\n", g_shape->sides(), g_named->name());
+  return 0;
+}
@@ -0,0 +1,17 @@
+#include <cstdio>
+
+__attribute__((noinline)) void sink(int value) {
+  asm volatile("" : : "r"(value) : "memory");
+}
+
+// NOTE: base is dead after entry.  Its register is reused for passing
+// sink's argument, and base's value is only recoverable via the entry value.
+__attribute__((noinline)) int compute(int base, int scale) {
+  sink(scale);
+  sink(scale * 2);
+  return scale;
+}
+
+int main() {
+  std::printf("%d\n", compute(42, 7));
+}
@@ -0,0 +1,11 @@
+#include <stdexcept>
+
+int main() {
+  try {
+    throw std::runtime_error("meow");
+  } catch (const std::runtime_error& e) {
+    return 0;
+  }
+
+  return 1;
+}
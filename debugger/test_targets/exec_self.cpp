@@ -0,0 +1,20 @@
+#include <cstdio>
+#include <unistd.h>
+
+void run_helper() {
+  std::puts("helper running");
+}
+
+void before_exec() {
+}
+
+int main(int argc, char** argv) {
+  if (argc > 1) {
+    run_helper();
+    return 0;
+  }
+
+  before_exec();
+  execl("/proc/self/exe", argv[0], "helper", nullptr);
+  return 1;
+}
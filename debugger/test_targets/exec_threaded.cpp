@@ -0,0 +1,45 @@
+#include <cstdio>
+#include <cstring>
+#include <pthread.h>
+#include <unistd.h>
+
+char* program;
+
+void run_helper() {
+  std::puts("helper running");
+}
+
+void* exec_helper(void*) {
+  execl("/proc/self/exe", program, "helper", nullptr);
+  return nullptr;
+}
+
+void* idle(void*) {
+  while (true) {
+    sleep(1);
+  }
+  return nullptr;
+}
+
+// Executes the helper from a non-leader thread (or from the leader thread
+// when the "leader" argument is given) while other threads are running.
+int main(int argc, char** argv) {
+  if (argc > 1 && strcmp(argv[1], "helper") == 0) {
+    run_helper();
+    return 0;
+  }
+
+  program = argv[0];
+
+  pthread_t idler;
+  pthread_create(&idler, nullptr, idle, nullptr);
+
+  if (argc > 1 && strcmp(argv[1], "leader") == 0) {
+    exec_helper(nullptr);
+    return 1;
+  }
+
+  pthread_t execer;
+  pthread_create(&execer, nullptr, exec_helper, nullptr);
+  idle(nullptr);
+}
@@ -0,0 +1,23 @@
+#include <cstdlib>
+#include <stdexcept>
+
+// Never returns to its caller.
+void leave(int code) {
+  std::exit(code);
+}
+
+int compute(int x) {
+  if (x > 0) {
+    leave(x);
+  }
+  return x;
+}
+
+// The exception is never caught.
+int explode() {
+  throw std::runtime_error("boom");
+}
+
+int main() {
+  return compute(3);
+}
@@ -8,6 +8,8 @@ struct cat {
   int age;
   void give_command(const char* command);
   int increase_age();
+
+  static int add_ages(int a, int b);
 };
 
 void cat::give_command(const char* command) {
@@ -18,6 +20,10 @@ int cat::increase_age() {
   return ++age;
 }
 
+int cat::add_ages(int a, int b) {
+  return a + b;
+}
+
 cat marshmallow{ "Marshmallow", 4 };
 cat milkshake{ "Milkshake", 4 };
 cat lexa{ "Lexa", 8 };
@@ -78,6 +84,13 @@ big print_type(big b) {
   return b;
 }
 
+volatile bool g_spin = true;
+
+void spin() {
+  while (g_spin) {
+  }
+}
+
 small s = { 1, 2 };
 two_eightbyte t = { 3, 4 };
 big b = { 5, 6, 7 };
@@ -0,0 +1,20 @@
+#include <cstdio>
+
+struct point {
+  int x;
+  int y;
+};
+
+int leaf(int depth, const char* name, point p) {
+  std::puts(name);
+  return depth + p.x + p.y;
+}
+
+int middle(int depth, double scale, point* p) {
+  return leaf(depth + 1, "leaf", *p) * scale;
+}
+
+int main() {
+  point p = {1, 2};
+  return middle(1, 2.5, &p) != 10;
+}
@@ -0,0 +1,20 @@
+#include <cstdio>
+
+__attribute__((always_inline))
+inline int twice(int x) {
+  return 2 * x;
+}
+
+__attribute__((noinline)) int bump(int x) {
+  return x + 1;
+}
+
+int compute(int x) {
+  int y = bump(x) + twice(x);
+  return y;
+}
+
+int main() {
+  std::printf("%d\n", compute(3));
+  return 0;
+}
@@ -0,0 +1,13 @@
+__int128 g_int128 = -((static_cast<__int128>(1) << 100) + 42);
+unsigned __int128 g_uint128 = ~static_cast<unsigned __int128>(0);
+__int128 g_small_int128 = 2;
+
+int g_values[3] = {10, 20, 30};
+
+__int128 add_one(__int128 value) {
+  return value + 1;
+}
+
+int main() {
+  return static_cast<int>(add_one(g_small_int128));
+}
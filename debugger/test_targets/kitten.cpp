@@ -0,0 +1,6 @@
+#include <iostream>
+int libpurr_volume(int loudness);
+
+int main() {
+  std::cout << "Purr volume: " << libpurr_volume(21) << '\n';
+}
@@ -0,0 +1,24 @@
+struct element {
+  int id;
+  double weight;
+  char tag;
+};
+
+struct large {
+  int num_elements;
+  element elements[1024];
+};
+
+large g_large;
+
+int main() {
+  g_large.num_elements = 1024;
+  for (int i = 0; i < 1024; ++i) {
+    g_large.elements[i].id = i;
+    g_large.elements[i].weight = i * 0.5;
+    g_large.elements[i].tag = 'a' + (i % 26);
+  }
+
+  g_large.elements[0].id = -1;
+  return 0;
+}
@@ -0,0 +1,4 @@
+int libpurr_volume(int loudness) {
+  int volume = loudness * 2;
+  return volume;
+}
@@ -0,0 +1,17 @@
+struct node {
+  int value;
+  node* next;
+};
+
+// The list is circular: nodes[0] -> nodes[1] -> nodes[2] -> nodes[0]
+node nodes[3] = {
+  { 1, &nodes[1] },
+  { 2, &nodes[2] },
+  { 3, &nodes[0] },
+};
+
+node* head = &nodes[0];
+
+int main() {
+  return head->next->next->value;
+}
@@ -0,0 +1,29 @@
+#include <pthread.h>
+#include <unistd.h>
+
+const int kNumThreads = 64;
+
+pthread_barrier_t barrier;
+
+void* wait_forever(void*) {
+  pthread_barrier_wait(&barrier);
+  while (true) {
+    pause();
+  }
+  return nullptr;
+}
+
+void all_started() {
+}
+
+int main() {
+  pthread_barrier_init(&barrier, nullptr, kNumThreads + 1);
+
+  pthread_t threads[kNumThreads];
+  for (auto& thread: threads) {
+    pthread_create(&thread, nullptr, wait_forever, nullptr);
+  }
+
+  pthread_barrier_wait(&barrier);
+  all_started();
+}
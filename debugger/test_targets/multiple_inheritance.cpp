@@ -0,0 +1,28 @@
+#include <cstdio>
+
+struct first {
+  int a = 1;
+
+  int get_a() {
+    return a;
+  }
+};
+
+struct second {
+  int b = 2;
+
+  int get_b() {
+    return b;
+  }
+};
+
+struct derived : first, second {
+  int c = 3;
+};
+
+derived g_derived;
+
+int main() {
+  std::printf("%d %d %d\n", g_derived.get_a(), g_derived.get_b(), g_derived.c);
+  return 0;
+}
@@ -0,0 +1,18 @@
+namespace outer {
+namespace inner {
+
+int value = 42;
+
+int twice(int x) {
+  return 2 * x;
+}
+
+}  // namespace inner
+
+int value = 7;
+
+}  // namespace outer
+
+int main() {
+  return outer::inner::twice(outer::inner::value) + outer::value;
+}
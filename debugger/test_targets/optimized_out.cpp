@@ -0,0 +1,35 @@
+#include <cstdio>
+
+int counter = 0;
+
+struct Pair {
+  int a;
+  int b;
+};
+
+__attribute__((noinline)) int next() {
+  asm volatile("" : : : "memory");
+  return ++counter;
+}
+
+__attribute__((noinline)) void sink(int value) {
+  asm volatile("" : : "r"(value) : "memory");
+}
+
+// NOTE: first is dead after the first sink call (its location list does not
+// cover the rest of the function), second has no location at all, and only
+// pair.a has a location (pair.b is an empty piece).
+__attribute__((noinline)) int compute(int input) {
+  int first = next();
+  sink(first);
+
+  int second = next();
+  Pair pair = {next(), second};
+  sink(pair.a);
+  sink(input);
+  return input;
+}
+
+int main() {
+  std::printf("%d\n", compute(21));
+}
@@ -0,0 +1,36 @@
+#include <pthread.h>
+
+const int kNumThreads = 3;
+
+// NOTE: use builtins / volatile (instead of std::atomic, which is not inlined
+// at -O0) to ensure the threads spin inside spin's frame.
+volatile int num_started = 0;
+volatile bool done = false;
+
+void all_started() {
+}
+
+void* spin(void* arg) {
+  long id = (long)arg * 10 + 1;
+  __sync_fetch_and_add(&num_started, 1);
+  while (!done) {
+  }
+  return (void*)id;
+}
+
+int main() {
+  pthread_t threads[kNumThreads];
+  for (long i = 0; i < kNumThreads; i++) {
+    pthread_create(&threads[i], nullptr, spin, (void*)i);
+  }
+
+  while (num_started < kNumThreads) {
+  }
+
+  all_started();
+  done = true;
+
+  for (auto& thread: threads) {
+    pthread_join(thread, nullptr);
+  }
+}
@@ -0,0 +1,21 @@
+#include <cstdio>
+
+struct cat {
+  const char* name;
+  int age;
+};
+
+cat marshmallow{"Marshmallow", 4};
+cat lexa{"Lexa", 8};
+
+int first = 1;
+int second = 2;
+
+const char* names[3] = {"Marshmallow", "Milkshake", nullptr};
+int* values[3] = {&first, &second, nullptr};
+cat* cats[3] = {&marshmallow, &lexa, &marshmallow};
+
+int main() {
+  std::printf("%s %d %s\n", names[0], *values[1], cats[1]->name);
+  return 0;
+}
@@ -0,0 +1,278 @@
+# Generated by `gcc -S -g -O0 -gdwarf-4 -dA` from:
+#
+#   volatile int counter = 42;
+#   int value = 7;
+#   int* restrict restricted = &value;
+#
+#   int main() {
+#     counter += 1;
+#     return *restricted + counter - 50;
+#   }
+#
+# NOTE: gcc only emits DW_TAG_atomic_type for dwarf5.  To simulate an
+# `_Atomic int counter` variable, counter's DW_TAG_volatile_type entry is
+# manually patched into DW_TAG_atomic_type (both entries have identical
+# layouts).
+
+	.file	"qualifiers.c"
+	.text
+.Ltext0:
+	.file 1 "qualifiers.c"
+	.globl	counter
+	.data
+	.align 4
+	.type	counter, @object
+	.size	counter, 4
+counter:
+	.long	42
+	.globl	value
+	.align 4
+	.type	value, @object
+	.size	value, 4
+value:
+	.long	7
+	.globl	restricted
+	.section	.data.rel.local,"aw"
+	.align 8
+	.type	restricted, @object
+	.size	restricted, 8
+restricted:
+	.quad	value
+	.text
+	.globl	main
+	.type	main, @function
+main:
+.LFB0:
+	# qualifiers.c:5:12
+	.loc 1 5 12
+	.cfi_startproc
+# BLOCK 2 seq:0
+# PRED: ENTRY (FALLTHRU)
+	pushq	%rbp
+	.cfi_def_cfa_offset 16
+	.cfi_offset 6, -16
+	movq	%rsp, %rbp
+	.cfi_def_cfa_register 6
+	# qualifiers.c:6:11
+	.loc 1 6 11
+	movl	counter(%rip), %eax
+	addl	$1, %eax
+	movl	%eax, counter(%rip)
+	# qualifiers.c:7:10
+	.loc 1 7 10
+	movq	restricted(%rip), %rax
+	movl	(%rax), %edx
+	# qualifiers.c:7:22
+	.loc 1 7 22
+	movl	counter(%rip), %eax
+	addl	%edx, %eax
+	# qualifiers.c:7:32
+	.loc 1 7 32
+	subl	$50, %eax
+	# qualifiers.c:8:1
+	.loc 1 8 1
+	popq	%rbp
+	.cfi_def_cfa 7, 8
+# SUCC: EXIT [always] 
+	ret
+	.cfi_endproc
+.LFE0:
+	.size	main, .-main
+.Letext0:
+	.section	.debug_info,"",@progbits
+.Ldebug_info0:
+	.long	0x9f	# Length of Compilation Unit Info
+	.value	0x4	# DWARF version number
+	.long	.Ldebug_abbrev0	# Offset Into Abbrev. Section
+	.byte	0x8	# Pointer Size (in bytes)
+	.uleb128 0x1	# (DIE (0xb) DW_TAG_compile_unit)
+	.long	.LASF3	# DW_AT_producer: "GNU C17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+	.byte	0xc	# DW_AT_language
+	.long	.LASF4	# DW_AT_name: "qualifiers.c"
+	.ascii ".\0"	# DW_AT_comp_dir
+	.quad	.Ltext0	# DW_AT_low_pc
+	.quad	.Letext0-.Ltext0	# DW_AT_high_pc
+	.long	.Ldebug_line0	# DW_AT_stmt_list
+	.uleb128 0x2	# (DIE (0x2b) DW_TAG_variable)
+	.long	.LASF0	# DW_AT_name: "counter"
+	.byte	0x1	# DW_AT_decl_file (qualifiers.c)
+	.byte	0x1	# DW_AT_decl_line
+	.byte	0xe	# DW_AT_decl_column
+	.long	0x48	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	counter
+	.uleb128 0x3	# (DIE (0x41) DW_TAG_base_type)
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.ascii "int\0"	# DW_AT_name
+	.uleb128 0x4	# (DIE (0x48) DW_TAG_atomic_type)
+	.long	0x41	# DW_AT_type
+	.uleb128 0x2	# (DIE (0x4d) DW_TAG_variable)
+	.long	.LASF1	# DW_AT_name: "value"
+	.byte	0x1	# DW_AT_decl_file (qualifiers.c)
+	.byte	0x2	# DW_AT_decl_line
+	.byte	0x5	# DW_AT_decl_column
+	.long	0x41	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	value
+	.uleb128 0x2	# (DIE (0x63) DW_TAG_variable)
+	.long	.LASF2	# DW_AT_name: "restricted"
+	.byte	0x1	# DW_AT_decl_file (qualifiers.c)
+	.byte	0x3	# DW_AT_decl_line
+	.byte	0xf	# DW_AT_decl_column
+	.long	0x7f	# DW_AT_type
+			# DW_AT_external
+	.uleb128 0x9	# DW_AT_location
+	.byte	0x3	# DW_OP_addr
+	.quad	restricted
+	.uleb128 0x5	# (DIE (0x79) DW_TAG_pointer_type)
+	.byte	0x8	# DW_AT_byte_size
+	.long	0x41	# DW_AT_type
+	.uleb128 0x6	# (DIE (0x7f) DW_TAG_restrict_type)
+	.long	0x79	# DW_AT_type
+	.uleb128 0x7	# (DIE (0x84) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF5	# DW_AT_name: "main"
+	.byte	0x1	# DW_AT_decl_file (qualifiers.c)
+	.byte	0x5	# DW_AT_decl_line
+	.byte	0x5	# DW_AT_decl_column
+	.long	0x41	# DW_AT_type
+	.quad	.LFB0	# DW_AT_low_pc
+	.quad	.LFE0-.LFB0	# DW_AT_high_pc
+	.uleb128 0x1	# DW_AT_frame_base
+	.byte	0x9c	# DW_OP_call_frame_cfa
+			# DW_AT_GNU_all_call_sites
+	.byte	0	# end of children of DIE 0xb
+	.section	.debug_abbrev,"",@progbits
+.Ldebug_abbrev0:
+	.uleb128 0x1	# (abbrev code)
+	.uleb128 0x11	# (TAG: DW_TAG_compile_unit)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x25	# (DW_AT_producer)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x13	# (DW_AT_language)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x1b	# (DW_AT_comp_dir)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x10	# (DW_AT_stmt_list)
+	.uleb128 0x17	# (DW_FORM_sec_offset)
+	.byte	0
+	.byte	0
+	.uleb128 0x2	# (abbrev code)
+	.uleb128 0x34	# (TAG: DW_TAG_variable)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x2	# (DW_AT_location)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.uleb128 0x3	# (abbrev code)
+	.uleb128 0x24	# (TAG: DW_TAG_base_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3e	# (DW_AT_encoding)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.byte	0
+	.byte	0
+	.uleb128 0x4	# (abbrev code)
+	.uleb128 0x47	# (TAG: DW_TAG_atomic_type)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x5	# (abbrev code)
+	.uleb128 0xf	# (TAG: DW_TAG_pointer_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x6	# (abbrev code)
+	.uleb128 0x37	# (TAG: DW_TAG_restrict_type)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x7	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0	# DW_children_no
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x40	# (DW_AT_frame_base)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.uleb128 0x2117	# (DW_AT_GNU_all_call_sites)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.byte	0
+	.byte	0
+	.byte	0
+	.section	.debug_aranges,"",@progbits
+	.long	0x2c	# Length of Address Ranges Info
+	.value	0x2	# DWARF aranges version
+	.long	.Ldebug_info0	# Offset of Compilation Unit Info
+	.byte	0x8	# Size of Address
+	.byte	0	# Size of Segment Descriptor
+	.value	0	# Pad to 16 byte boundary
+	.value	0
+	.quad	.Ltext0	# Address
+	.quad	.Letext0-.Ltext0	# Length
+	.quad	0
+	.quad	0
+	.section	.debug_line,"",@progbits
+.Ldebug_line0:
+	.section	.debug_str,"MS",@progbits,1
+.LASF1:
+	.string	"value"
+.LASF3:
+	.string	"GNU C17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+.LASF5:
+	.string	"main"
+.LASF4:
+	.string	"qualifiers.c"
+.LASF2:
+	.string	"restricted"
+.LASF0:
+	.string	"counter"
+	.ident	"GCC: (Debian 12.2.0-14+deb12u1) 12.2.0"
+	.section	.note.GNU-stack,"",@progbits
@@ -0,0 +1,28 @@
+#include <signal.h>
+
+volatile sig_atomic_t g_handled = 0;
+
+void handler(int) {
+  g_handled += 1;
+}
+
+void wait_for_signal() {
+}
+
+void checkpoint() {
+}
+
+int main() {
+  signal(SIGUSR1, handler);
+
+  wait_for_signal();
+  checkpoint();
+
+  wait_for_signal();
+  checkpoint();
+
+  wait_for_signal();
+  checkpoint();
+
+  return g_handled;
+}
@@ -0,0 +1,23 @@
+#include <cstdio>
+
+int reached_bottom = 0;
+
+int descend(int depth) {
+  if (depth == 0) {
+    reached_bottom = 1;
+    return 0;
+  }
+
+  int result = descend(depth - 1);
+  return result + depth;
+}
+
+int start(int depth) {
+  int total = descend(depth);
+  return total * 2;
+}
+
+int main() {
+  std::printf("%d\n", start(5));
+  return 0;
+}
@@ -0,0 +1,10 @@
+#include <cstdio>
+
+__attribute__((noinline)) int identity(int value) {
+  asm volatile("" : "+r"(value));
+  return value;
+}
+
+int main() {
+  std::printf("%d\n", identity(7));
+}
@@ -0,0 +1,21 @@
+enum color { red, green, blue };
+
+enum class level : unsigned char { low = 1, high = 200 };
+
+struct settings {
+  bool enabled;
+  char initial;
+  color background;
+  level volume;
+  color palette[3];
+};
+
+settings g_settings = { true, 'x', blue, level::high, { green, red, blue } };
+
+color g_color = green;
+
+color g_unnamed_color = static_cast<color>(7);
+
+int main() {
+  return g_settings.enabled ? 0 : 1;
+}
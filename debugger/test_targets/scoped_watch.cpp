@@ -0,0 +1,14 @@
+int g_sum = 0;
+
+void __attribute__((noinline)) accumulate(int value) {
+  int local = value;
+  local = local * 2;
+  g_sum += local;
+}
+
+int main() {
+  for (int i = 0; i < 10; ++i) {
+    accumulate(i);
+  }
+  return 0;
+}
@@ -0,0 +1,8 @@
+#include <cstdio>
+
+int main() {
+  int total = 0;
+  for (int i = 0; i < 2000; i++) total += i;
+  std::printf("%d\n", total);
+  return 0;
+}
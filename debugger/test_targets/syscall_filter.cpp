@@ -0,0 +1,14 @@
+#include <fcntl.h>
+#include <unistd.h>
+
+int main() {
+  int fd = open("/dev/null", O_RDONLY);
+  close(fd);
+
+  open("/nonexistent/secret", O_RDONLY);
+  close(-1);
+
+  write(STDOUT_FILENO, "out\n", 4);
+  write(STDERR_FILENO, "err\n", 4);
+  return 0;
+}
@@ -0,0 +1,24 @@
+#include <cstdio>
+
+struct animal {
+  int legs = 4;
+};
+
+struct mammal : virtual animal {
+  int fur = 1;
+};
+
+struct bird : virtual animal {
+  int feathers = 2;
+};
+
+struct platypus : mammal, bird {
+  int eggs = 3;
+};
+
+platypus g_platypus;
+
+int main() {
+  std::printf("%d\n", g_platypus.legs);
+  return 0;
+}
@@ -0,0 +1,51 @@
+#include <cstdio>
+
+__attribute__((noinline)) void checkpoint() {
+  std::puts("checkpoint");
+}
+
+int sum(int rows, int cols) {
+  int values[rows];
+  int matrix[rows][cols];
+  for (int r = 0; r < rows; ++r) {
+    values[r] = r * 100;
+    for (int c = 0; c < cols; ++c) {
+      matrix[r][c] = r * 10 + c;
+    }
+  }
+
+  checkpoint();
+
+  int total = 0;
+  for (int r = 0; r < rows; ++r) {
+    total += values[r];
+    for (int c = 0; c < cols; ++c) {
+      total += matrix[r][c];
+    }
+  }
+  return total;
+}
+
+// Fills the stack with garbage, such that first's variable length array
+// bound is garbage until the bound is assigned.
+__attribute__((noinline)) void scribble() {
+  volatile unsigned long garbage[64];
+  for (int i = 0; i < 64; ++i) {
+    garbage[i] = 0x2000000000000001;
+  }
+}
+
+__attribute__((noinline)) int first(int count) {
+  int items[count];
+  for (int i = 0; i < count; ++i) {
+    items[i] = i + 1;
+  }
+  return items[0];
+}
+
+int main() {
+  std::printf("%d\n", sum(3, 4));
+  scribble();
+  std::printf("%d\n", first(2));
+  return 0;
+}
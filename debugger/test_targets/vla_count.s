@@ -0,0 +1,863 @@
+# Generated by `gcc -S -g -O0 -gdwarf-4 -dA` from:
+#
+#   #include <stdio.h>
+#
+#   __attribute__((noinline)) void checkpoint(void) {
+#     puts("checkpoint");
+#   }
+#
+#   int sum(int n) {
+#     int squares[n];
+#     int cubes[n];
+#     for (int i = 0; i < n; ++i) {
+#       squares[i] = i * i;
+#       cubes[i] = i * i * i;
+#     }
+#
+#     checkpoint();
+#
+#     int total = 0;
+#     for (int i = 0; i < n; ++i) {
+#       total += squares[i] + cubes[i];
+#     }
+#     return total;
+#   }
+#
+#   int main(void) {
+#     printf("%d\n", sum(5));
+#     return 0;
+#   }
+#
+# NOTE: gcc only emits DW_AT_upper_bound for variable length arrays.  To
+# simulate other producers (e.g., clang), the subranges are manually patched
+# to use DW_AT_count (the patched entries have the same sizes as the original
+# entries):
+#   - squares' count is a reference to the n parameter (a zero
+#     DW_AT_lower_bound pads the entry)
+#   - cubes' count is an expression which reads from address 0, i.e., the
+#     count cannot be read.
+
+	.file	"vla_count.c"
+	.text
+.Ltext0:
+	.file 1 "vla_count.c"
+	.section	.rodata
+.LC0:
+	.string	"checkpoint"
+	.text
+	.globl	checkpoint
+	.type	checkpoint, @function
+checkpoint:
+.LFB0:
+	# vla_count.c:3:49
+	.loc 1 3 49
+	.cfi_startproc
+# BLOCK 2 seq:0
+# PRED: ENTRY (FALLTHRU)
+	pushq	%rbp
+	.cfi_def_cfa_offset 16
+	.cfi_offset 6, -16
+	movq	%rsp, %rbp
+	.cfi_def_cfa_register 6
+	# vla_count.c:4:3
+	.loc 1 4 3
+	leaq	.LC0(%rip), %rax
+	movq	%rax, %rdi
+	call	puts@PLT
+	# vla_count.c:5:1
+	.loc 1 5 1
+	nop
+	popq	%rbp
+	.cfi_def_cfa 7, 8
+# SUCC: EXIT [always] 
+	ret
+	.cfi_endproc
+.LFE0:
+	.size	checkpoint, .-checkpoint
+	.globl	sum
+	.type	sum, @function
+sum:
+.LFB1:
+	# vla_count.c:7:16
+	.loc 1 7 16
+	.cfi_startproc
+# BLOCK 2 seq:0
+# PRED: ENTRY (FALLTHRU)
+	pushq	%rbp
+	.cfi_def_cfa_offset 16
+	.cfi_offset 6, -16
+	movq	%rsp, %rbp
+	.cfi_def_cfa_register 6
+	pushq	%r15
+	pushq	%r14
+	pushq	%r13
+	pushq	%r12
+	pushq	%rbx
+	subq	$72, %rsp
+	.cfi_offset 15, -24
+	.cfi_offset 14, -32
+	.cfi_offset 13, -40
+	.cfi_offset 12, -48
+	.cfi_offset 3, -56
+	movl	%edi, -100(%rbp)
+	# vla_count.c:7:16
+	.loc 1 7 16
+	movq	%rsp, %rax
+	movq	%rax, %rbx
+	# vla_count.c:8:3
+	.loc 1 8 3
+	movl	-100(%rbp), %eax
+	# vla_count.c:8:7
+	.loc 1 8 7
+	movslq	%eax, %rdx
+	subq	$1, %rdx
+	movq	%rdx, -72(%rbp)
+	movslq	%eax, %rdx
+	movq	%rdx, %r14
+	movl	$0, %r15d
+	movslq	%eax, %rdx
+	movq	%rdx, %r12
+	movl	$0, %r13d
+	cltq
+	leaq	0(,%rax,4), %rdx
+	movl	$16, %eax
+	subq	$1, %rax
+	addq	%rdx, %rax
+	movl	$16, %esi
+	movl	$0, %edx
+	divq	%rsi
+	imulq	$16, %rax, %rax
+	subq	%rax, %rsp
+	movq	%rsp, %rax
+	addq	$3, %rax
+	shrq	$2, %rax
+	salq	$2, %rax
+	movq	%rax, -80(%rbp)
+	# vla_count.c:9:3
+	.loc 1 9 3
+	movl	-100(%rbp), %eax
+	# vla_count.c:9:7
+	.loc 1 9 7
+	movslq	%eax, %rdx
+	subq	$1, %rdx
+	movq	%rdx, -88(%rbp)
+	movslq	%eax, %rdx
+	movq	%rdx, %r10
+	movl	$0, %r11d
+	movslq	%eax, %rdx
+	movq	%rdx, %r8
+	movl	$0, %r9d
+	cltq
+	leaq	0(,%rax,4), %rdx
+	movl	$16, %eax
+	subq	$1, %rax
+	addq	%rdx, %rax
+	movl	$16, %edi
+	movl	$0, %edx
+	divq	%rdi
+	imulq	$16, %rax, %rax
+	subq	%rax, %rsp
+	movq	%rsp, %rax
+	addq	$3, %rax
+	shrq	$2, %rax
+	salq	$2, %rax
+	movq	%rax, -96(%rbp)
+.LBB2:
+	# vla_count.c:10:12
+	.loc 1 10 12
+	movl	$0, -52(%rbp)
+# SUCC: 4 [always]  vla_count.c:10:3
+	# vla_count.c:10:3
+	.loc 1 10 3
+	jmp	.L3
+# BLOCK 3 seq:1
+# PRED: 4
+.L4:
+	# vla_count.c:11:20
+	.loc 1 11 20 discriminator 3
+	movl	-52(%rbp), %eax
+	imull	%eax, %eax
+	movl	%eax, %ecx
+	# vla_count.c:11:16
+	.loc 1 11 16 discriminator 3
+	movq	-80(%rbp), %rax
+	movl	-52(%rbp), %edx
+	movslq	%edx, %rdx
+	movl	%ecx, (%rax,%rdx,4)
+	# vla_count.c:12:18
+	.loc 1 12 18 discriminator 3
+	movl	-52(%rbp), %eax
+	imull	%eax, %eax
+	# vla_count.c:12:22
+	.loc 1 12 22 discriminator 3
+	imull	-52(%rbp), %eax
+	movl	%eax, %ecx
+	# vla_count.c:12:14
+	.loc 1 12 14 discriminator 3
+	movq	-96(%rbp), %rax
+	movl	-52(%rbp), %edx
+	movslq	%edx, %rdx
+	movl	%ecx, (%rax,%rdx,4)
+# SUCC: 4 (FALLTHRU,DFS_BACK)
+	# vla_count.c:10:26
+	.loc 1 10 26 discriminator 3
+	addl	$1, -52(%rbp)
+# BLOCK 4 seq:2
+# PRED: 3 (FALLTHRU,DFS_BACK) 2 [always]  vla_count.c:10:3
+.L3:
+	# vla_count.c:10:21
+	.loc 1 10 21 discriminator 1
+	movl	-52(%rbp), %eax
+	cmpl	-100(%rbp), %eax
+# SUCC: 3 5 (FALLTHRU)
+	jl	.L4
+# BLOCK 5 seq:3
+# PRED: 4 (FALLTHRU)
+.LBE2:
+	# vla_count.c:15:3
+	.loc 1 15 3
+	call	checkpoint
+	# vla_count.c:17:7
+	.loc 1 17 7
+	movl	$0, -56(%rbp)
+.LBB3:
+	# vla_count.c:18:12
+	.loc 1 18 12
+	movl	$0, -60(%rbp)
+# SUCC: 7 [always]  vla_count.c:18:3
+	# vla_count.c:18:3
+	.loc 1 18 3
+	jmp	.L5
+# BLOCK 6 seq:4
+# PRED: 7
+.L6:
+	# vla_count.c:19:21
+	.loc 1 19 21 discriminator 3
+	movq	-80(%rbp), %rax
+	movl	-60(%rbp), %edx
+	movslq	%edx, %rdx
+	movl	(%rax,%rdx,4), %ecx
+	# vla_count.c:19:32
+	.loc 1 19 32 discriminator 3
+	movq	-96(%rbp), %rax
+	movl	-60(%rbp), %edx
+	movslq	%edx, %rdx
+	movl	(%rax,%rdx,4), %eax
+	# vla_count.c:19:25
+	.loc 1 19 25 discriminator 3
+	addl	%ecx, %eax
+	# vla_count.c:19:11
+	.loc 1 19 11 discriminator 3
+	addl	%eax, -56(%rbp)
+# SUCC: 7 (FALLTHRU,DFS_BACK)
+	# vla_count.c:18:26
+	.loc 1 18 26 discriminator 3
+	addl	$1, -60(%rbp)
+# BLOCK 7 seq:5
+# PRED: 6 (FALLTHRU,DFS_BACK) 5 [always]  vla_count.c:18:3
+.L5:
+	# vla_count.c:18:21
+	.loc 1 18 21 discriminator 1
+	movl	-60(%rbp), %eax
+	cmpl	-100(%rbp), %eax
+# SUCC: 6 8 (FALLTHRU)
+	jl	.L6
+# BLOCK 8 seq:6
+# PRED: 7 (FALLTHRU)
+.LBE3:
+	# vla_count.c:21:10
+	.loc 1 21 10
+	movl	-56(%rbp), %eax
+	movq	%rbx, %rsp
+	# vla_count.c:22:1
+	.loc 1 22 1
+	leaq	-40(%rbp), %rsp
+	popq	%rbx
+	popq	%r12
+	popq	%r13
+	popq	%r14
+	popq	%r15
+	popq	%rbp
+	.cfi_def_cfa 7, 8
+# SUCC: EXIT [always] 
+	ret
+	.cfi_endproc
+.LFE1:
+	.size	sum, .-sum
+	.section	.rodata
+.LC1:
+	.string	"%d\n"
+	.text
+	.globl	main
+	.type	main, @function
+main:
+.LFB2:
+	# vla_count.c:24:16
+	.loc 1 24 16
+	.cfi_startproc
+# BLOCK 2 seq:0
+# PRED: ENTRY (FALLTHRU)
+	pushq	%rbp
+	.cfi_def_cfa_offset 16
+	.cfi_offset 6, -16
+	movq	%rsp, %rbp
+	.cfi_def_cfa_register 6
+	# vla_count.c:25:3
+	.loc 1 25 3
+	movl	$5, %edi
+	call	sum
+	movl	%eax, %esi
+	leaq	.LC1(%rip), %rax
+	movq	%rax, %rdi
+	movl	$0, %eax
+	call	printf@PLT
+	# vla_count.c:26:10
+	.loc 1 26 10
+	movl	$0, %eax
+	# vla_count.c:27:1
+	.loc 1 27 1
+	popq	%rbp
+	.cfi_def_cfa 7, 8
+# SUCC: EXIT [always] 
+	ret
+	.cfi_endproc
+.LFE2:
+	.size	main, .-main
+.Letext0:
+	.file 2 "/usr/include/stdio.h"
+	.section	.debug_info,"",@progbits
+.Ldebug_info0:
+	.long	0x1aa	# Length of Compilation Unit Info
+	.value	0x4	# DWARF version number
+	.long	.Ldebug_abbrev0	# Offset Into Abbrev. Section
+	.byte	0x8	# Pointer Size (in bytes)
+	.uleb128 0x1	# (DIE (0xb) DW_TAG_compile_unit)
+	.long	.LASF13	# DW_AT_producer: "GNU C17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+	.byte	0xc	# DW_AT_language
+	.long	.LASF14	# DW_AT_name: "vla_count.c"
+	.long	.LASF15	# DW_AT_comp_dir: "/tmp/vlac"
+	.quad	.Ltext0	# DW_AT_low_pc
+	.quad	.Letext0-.Ltext0	# DW_AT_high_pc
+	.long	.Ldebug_line0	# DW_AT_stmt_list
+	.uleb128 0x2	# (DIE (0x2d) DW_TAG_base_type)
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF0	# DW_AT_name: "long unsigned int"
+	.uleb128 0x2	# (DIE (0x34) DW_TAG_base_type)
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF1	# DW_AT_name: "unsigned int"
+	.uleb128 0x2	# (DIE (0x3b) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x8	# DW_AT_encoding
+	.long	.LASF2	# DW_AT_name: "unsigned char"
+	.uleb128 0x2	# (DIE (0x42) DW_TAG_base_type)
+	.byte	0x2	# DW_AT_byte_size
+	.byte	0x7	# DW_AT_encoding
+	.long	.LASF3	# DW_AT_name: "short unsigned int"
+	.uleb128 0x2	# (DIE (0x49) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x6	# DW_AT_encoding
+	.long	.LASF4	# DW_AT_name: "signed char"
+	.uleb128 0x2	# (DIE (0x50) DW_TAG_base_type)
+	.byte	0x2	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.long	.LASF5	# DW_AT_name: "short int"
+	.uleb128 0x3	# (DIE (0x57) DW_TAG_base_type)
+	.byte	0x4	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.ascii "int\0"	# DW_AT_name
+	.uleb128 0x2	# (DIE (0x5e) DW_TAG_base_type)
+	.byte	0x8	# DW_AT_byte_size
+	.byte	0x5	# DW_AT_encoding
+	.long	.LASF6	# DW_AT_name: "long int"
+	.uleb128 0x2	# (DIE (0x65) DW_TAG_base_type)
+	.byte	0x1	# DW_AT_byte_size
+	.byte	0x6	# DW_AT_encoding
+	.long	.LASF7	# DW_AT_name: "char"
+	.uleb128 0x4	# (DIE (0x6c) DW_TAG_const_type)
+	.long	0x65	# DW_AT_type
+	.uleb128 0x5	# (DIE (0x71) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF8	# DW_AT_name: "printf"
+	.byte	0x2	# DW_AT_decl_file (/usr/include/stdio.h)
+	.value	0x164	# DW_AT_decl_line
+	.byte	0xc	# DW_AT_decl_column
+			# DW_AT_prototyped
+	.long	0x57	# DW_AT_type
+			# DW_AT_declaration
+	.long	0x89	# DW_AT_sibling
+	.uleb128 0x6	# (DIE (0x82) DW_TAG_formal_parameter)
+	.long	0x89	# DW_AT_type
+	.uleb128 0x7	# (DIE (0x87) DW_TAG_unspecified_parameters)
+	.byte	0	# end of children of DIE 0x71
+	.uleb128 0x8	# (DIE (0x89) DW_TAG_pointer_type)
+	.byte	0x8	# DW_AT_byte_size
+	.long	0x6c	# DW_AT_type
+	.uleb128 0x5	# (DIE (0x8f) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF9	# DW_AT_name: "puts"
+	.byte	0x2	# DW_AT_decl_file (/usr/include/stdio.h)
+	.value	0x295	# DW_AT_decl_line
+	.byte	0xc	# DW_AT_decl_column
+			# DW_AT_prototyped
+	.long	0x57	# DW_AT_type
+			# DW_AT_declaration
+	.long	0xa6	# DW_AT_sibling
+	.uleb128 0x6	# (DIE (0xa0) DW_TAG_formal_parameter)
+	.long	0x89	# DW_AT_type
+	.byte	0	# end of children of DIE 0x8f
+	.uleb128 0x9	# (DIE (0xa6) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF16	# DW_AT_name: "main"
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x18	# DW_AT_decl_line
+	.byte	0x5	# DW_AT_decl_column
+			# DW_AT_prototyped
+	.long	0x57	# DW_AT_type
+	.quad	.LFB2	# DW_AT_low_pc
+	.quad	.LFE2-.LFB2	# DW_AT_high_pc
+	.uleb128 0x1	# DW_AT_frame_base
+	.byte	0x9c	# DW_OP_call_frame_cfa
+			# DW_AT_GNU_all_tail_call_sites
+	.uleb128 0xa	# (DIE (0xc4) DW_TAG_subprogram)
+			# DW_AT_external
+	.ascii "sum\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x7	# DW_AT_decl_line
+	.byte	0x5	# DW_AT_decl_column
+			# DW_AT_prototyped
+	.long	0x57	# DW_AT_type
+	.quad	.LFB1	# DW_AT_low_pc
+	.quad	.LFE1-.LFB1	# DW_AT_high_pc
+	.uleb128 0x1	# DW_AT_frame_base
+	.byte	0x9c	# DW_OP_call_frame_cfa
+			# DW_AT_GNU_all_tail_call_sites
+	.long	0x16b	# DW_AT_sibling
+	.uleb128 0xb	# (DIE (0xe6) DW_TAG_formal_parameter)
+	.ascii "n\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x7	# DW_AT_decl_line
+	.byte	0xd	# DW_AT_decl_column
+	.long	0x57	# DW_AT_type
+	.uleb128 0x3	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -116
+	.uleb128 0xc	# (DIE (0xf4) DW_TAG_variable)
+	.long	.LASF10	# DW_AT_name: "squares"
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x8	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0x16b	# DW_AT_type
+	.uleb128 0x4	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -96
+	.byte	0x6	# DW_OP_deref
+	.uleb128 0xc	# (DIE (0x105) DW_TAG_variable)
+	.long	.LASF11	# DW_AT_name: "cubes"
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x9	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0x17f	# DW_AT_type
+	.uleb128 0x4	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -112
+	.byte	0x6	# DW_OP_deref
+	.uleb128 0xc	# (DIE (0x116) DW_TAG_variable)
+	.long	.LASF12	# DW_AT_name: "total"
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x11	# DW_AT_decl_line
+	.byte	0x7	# DW_AT_decl_column
+	.long	0x57	# DW_AT_type
+	.uleb128 0x3	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -72
+	.uleb128 0xd	# (DIE (0x126) DW_TAG_lexical_block)
+	.quad	.LBB2	# DW_AT_low_pc
+	.quad	.LBE2-.LBB2	# DW_AT_high_pc
+	.long	0x14a	# DW_AT_sibling
+	.uleb128 0xe	# (DIE (0x13b) DW_TAG_variable)
+	.ascii "i\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0xa	# DW_AT_decl_line
+	.byte	0xc	# DW_AT_decl_column
+	.long	0x57	# DW_AT_type
+	.uleb128 0x3	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -68
+	.byte	0	# end of children of DIE 0x126
+	.uleb128 0xf	# (DIE (0x14a) DW_TAG_lexical_block)
+	.quad	.LBB3	# DW_AT_low_pc
+	.quad	.LBE3-.LBB3	# DW_AT_high_pc
+	.uleb128 0xe	# (DIE (0x15b) DW_TAG_variable)
+	.ascii "i\0"	# DW_AT_name
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x12	# DW_AT_decl_line
+	.byte	0xc	# DW_AT_decl_column
+	.long	0x57	# DW_AT_type
+	.uleb128 0x3	# DW_AT_location
+	.byte	0x91	# DW_OP_fbreg
+	.sleb128 -76
+	.byte	0	# end of children of DIE 0x14a
+	.byte	0	# end of children of DIE 0xc4
+	.uleb128 0x10	# (DIE (0x16b) DW_TAG_array_type)
+	.long	0x57	# DW_AT_type
+	.long	0x17f	# DW_AT_sibling
+	.uleb128 0x11	# (DIE (0x174) DW_TAG_subrange_type)
+	.long	0x2d	# DW_AT_type
+	.byte	0	# DW_AT_lower_bound
+	.long	0xe6	# DW_AT_count
+	.byte	0	# end of children of DIE 0x16b
+	.uleb128 0x10	# (DIE (0x17f) DW_TAG_array_type)
+	.long	0x57	# DW_AT_type
+	.long	0x193	# DW_AT_sibling
+	.uleb128 0x13	# (DIE (0x188) DW_TAG_subrange_type)
+	.long	0x2d	# DW_AT_type
+	.uleb128 0x4	# DW_AT_count
+	.byte	0x30	# DW_OP_lit0
+	.byte	0x6	# DW_OP_deref
+	.byte	0x96	# DW_OP_nop
+	.byte	0x96	# DW_OP_nop
+	.byte	0	# end of children of DIE 0x17f
+	.uleb128 0x12	# (DIE (0x193) DW_TAG_subprogram)
+			# DW_AT_external
+	.long	.LASF17	# DW_AT_name: "checkpoint"
+	.byte	0x1	# DW_AT_decl_file (vla_count.c)
+	.byte	0x3	# DW_AT_decl_line
+	.byte	0x20	# DW_AT_decl_column
+			# DW_AT_prototyped
+	.quad	.LFB0	# DW_AT_low_pc
+	.quad	.LFE0-.LFB0	# DW_AT_high_pc
+	.uleb128 0x1	# DW_AT_frame_base
+	.byte	0x9c	# DW_OP_call_frame_cfa
+			# DW_AT_GNU_all_tail_call_sites
+	.byte	0	# end of children of DIE 0xb
+	.section	.debug_abbrev,"",@progbits
+.Ldebug_abbrev0:
+	.uleb128 0x1	# (abbrev code)
+	.uleb128 0x11	# (TAG: DW_TAG_compile_unit)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x25	# (DW_AT_producer)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x13	# (DW_AT_language)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x1b	# (DW_AT_comp_dir)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x10	# (DW_AT_stmt_list)
+	.uleb128 0x17	# (DW_FORM_sec_offset)
+	.byte	0
+	.byte	0
+	.uleb128 0x2	# (abbrev code)
+	.uleb128 0x24	# (TAG: DW_TAG_base_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3e	# (DW_AT_encoding)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.byte	0
+	.byte	0
+	.uleb128 0x3	# (abbrev code)
+	.uleb128 0x24	# (TAG: DW_TAG_base_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3e	# (DW_AT_encoding)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.byte	0
+	.byte	0
+	.uleb128 0x4	# (abbrev code)
+	.uleb128 0x26	# (TAG: DW_TAG_const_type)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x5	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0x5	# (DW_FORM_data2)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x27	# (DW_AT_prototyped)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x3c	# (DW_AT_declaration)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x1	# (DW_AT_sibling)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x6	# (abbrev code)
+	.uleb128 0x5	# (TAG: DW_TAG_formal_parameter)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x7	# (abbrev code)
+	.uleb128 0x18	# (TAG: DW_TAG_unspecified_parameters)
+	.byte	0	# DW_children_no
+	.byte	0
+	.byte	0
+	.uleb128 0x8	# (abbrev code)
+	.uleb128 0xf	# (TAG: DW_TAG_pointer_type)
+	.byte	0	# DW_children_no
+	.uleb128 0xb	# (DW_AT_byte_size)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x9	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0	# DW_children_no
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x27	# (DW_AT_prototyped)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x40	# (DW_AT_frame_base)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.uleb128 0x2116	# (DW_AT_GNU_all_tail_call_sites)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.byte	0
+	.byte	0
+	.uleb128 0xa	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x27	# (DW_AT_prototyped)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x40	# (DW_AT_frame_base)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.uleb128 0x2116	# (DW_AT_GNU_all_tail_call_sites)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x1	# (DW_AT_sibling)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0xb	# (abbrev code)
+	.uleb128 0x5	# (TAG: DW_TAG_formal_parameter)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x2	# (DW_AT_location)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.uleb128 0xc	# (abbrev code)
+	.uleb128 0x34	# (TAG: DW_TAG_variable)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x2	# (DW_AT_location)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.uleb128 0xd	# (abbrev code)
+	.uleb128 0xb	# (TAG: DW_TAG_lexical_block)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x1	# (DW_AT_sibling)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0xe	# (abbrev code)
+	.uleb128 0x34	# (TAG: DW_TAG_variable)
+	.byte	0	# DW_children_no
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0x8	# (DW_FORM_string)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x2	# (DW_AT_location)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.uleb128 0xf	# (abbrev code)
+	.uleb128 0xb	# (TAG: DW_TAG_lexical_block)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.byte	0
+	.byte	0
+	.uleb128 0x10	# (abbrev code)
+	.uleb128 0x1	# (TAG: DW_TAG_array_type)
+	.byte	0x1	# DW_children_yes
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x1	# (DW_AT_sibling)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x11	# (abbrev code)
+	.uleb128 0x21	# (TAG: DW_TAG_subrange_type)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x22	# (DW_AT_lower_bound)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x37	# (DW_AT_count)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.byte	0
+	.byte	0
+	.uleb128 0x12	# (abbrev code)
+	.uleb128 0x2e	# (TAG: DW_TAG_subprogram)
+	.byte	0	# DW_children_no
+	.uleb128 0x3f	# (DW_AT_external)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x3	# (DW_AT_name)
+	.uleb128 0xe	# (DW_FORM_strp)
+	.uleb128 0x3a	# (DW_AT_decl_file)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x3b	# (DW_AT_decl_line)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x39	# (DW_AT_decl_column)
+	.uleb128 0xb	# (DW_FORM_data1)
+	.uleb128 0x27	# (DW_AT_prototyped)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.uleb128 0x11	# (DW_AT_low_pc)
+	.uleb128 0x1	# (DW_FORM_addr)
+	.uleb128 0x12	# (DW_AT_high_pc)
+	.uleb128 0x7	# (DW_FORM_data8)
+	.uleb128 0x40	# (DW_AT_frame_base)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.uleb128 0x2116	# (DW_AT_GNU_all_tail_call_sites)
+	.uleb128 0x19	# (DW_FORM_flag_present)
+	.byte	0
+	.byte	0
+	.uleb128 0x13	# (abbrev code)
+	.uleb128 0x21	# (TAG: DW_TAG_subrange_type)
+	.byte	0	# DW_children_no
+	.uleb128 0x49	# (DW_AT_type)
+	.uleb128 0x13	# (DW_FORM_ref4)
+	.uleb128 0x37	# (DW_AT_count)
+	.uleb128 0x18	# (DW_FORM_exprloc)
+	.byte	0
+	.byte	0
+	.byte	0
+	.section	.debug_aranges,"",@progbits
+	.long	0x2c	# Length of Address Ranges Info
+	.value	0x2	# DWARF aranges version
+	.long	.Ldebug_info0	# Offset of Compilation Unit Info
+	.byte	0x8	# Size of Address
+	.byte	0	# Size of Segment Descriptor
+	.value	0	# Pad to 16 byte boundary
+	.value	0
+	.quad	.Ltext0	# Address
+	.quad	.Letext0-.Ltext0	# Length
+	.quad	0
+	.quad	0
+	.section	.debug_line,"",@progbits
+.Ldebug_line0:
+	.section	.debug_str,"MS",@progbits,1
+.LASF1:
+	.string	"unsigned int"
+.LASF14:
+	.string	"vla_count.c"
+.LASF9:
+	.string	"puts"
+.LASF17:
+	.string	"checkpoint"
+.LASF13:
+	.string	"GNU C17 12.2.0 -mtune=generic -march=x86-64 -g -gdwarf-4 -O0 -fasynchronous-unwind-tables"
+.LASF10:
+	.string	"squares"
+.LASF0:
+	.string	"long unsigned int"
+.LASF15:
+	.string	"/tmp/vlac"
+.LASF7:
+	.string	"char"
+.LASF2:
+	.string	"unsigned char"
+.LASF16:
+	.string	"main"
+.LASF6:
+	.string	"long int"
+.LASF11:
+	.string	"cubes"
+.LASF3:
+	.string	"short unsigned int"
+.LASF8:
+	.string	"printf"
+.LASF12:
+	.string	"total"
+.LASF5:
+	.string	"short int"
+.LASF4:
+	.string	"signed char"
+	.ident	"GCC: (Debian 12.2.0-14+deb12u1) 12.2.0"
+	.section	.note.GNU-stack,"",@progbits
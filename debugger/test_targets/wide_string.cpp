@@ -0,0 +1,20 @@
+#include <cstdio>
+
+const char16_t* utf16_str = u"café \U0001F431";
+const char32_t* utf32_str = U"naïve \U0001F431";
+const wchar_t* wide_str = L"wide\tstr";
+
+char16_t utf16_array[8] = u"meow";
+char16_t invalid_utf16[3] = {0xd800, u'x', 0}; // unpaired surrogate
+char32_t utf32_char = U'é';
+
+int main() {
+  std::printf(
+      "%p %p %p %p %p %d\n",
+      utf16_str,
+      utf32_str,
+      wide_str,
+      utf16_array,
+      invalid_utf16,
+      (int)utf32_char);
+}
@@ -5,19 +5,34 @@ import (
 	"fmt"
 	"math"
 	"syscall"
-
-	"golang.org/x/arch/x86/x86asm"
+	"time"
 
 	. "github.com/pattyshack/bad/debugger/common"
 	"github.com/pattyshack/bad/debugger/expression"
+	"github.com/pattyshack/bad/debugger/memory"
 	"github.com/pattyshack/bad/debugger/registers"
 	"github.com/pattyshack/bad/debugger/stoppoint"
+	"github.com/pattyshack/bad/elf"
 	"github.com/pattyshack/bad/ptrace"
 )
 
+// The tracer operations used for controlling a thread.  This is implemented
+// by ptrace.Tracer (and ptracetest.FakeTracer for testing).
+type ThreadTracer interface {
+	registers.Tracer
+
+	Resume(signal int) error
+	SyscallTrappedResume(signal int) error
+	SingleStep() error
+	SetOptions(options ptrace.Options) error
+	GetSigInfo() (*ptrace.SigInfo, error)
+	GetEventMsg() (uint, error)
+	Detach() error
+}
+
 type ThreadState struct {
 	Tid          int
-	threadTracer *ptrace.Tracer
+	threadTracer ThreadTracer
 
 	Registers *registers.Registers
 
@@ -28,6 +43,20 @@ type ThreadState struct {
 	hasPendingSigStop        bool
 	hasPendingSingleStepTrap bool // toggled within step instruction only
 
+	// Populated by the exit ptrace event.
+	exitEventStatus *syscall.WaitStatus
+
+	// True when the thread is in a fork syscall caught by CatchFork.
+	isForking bool
+
+	// True if the current syscall's entry satisfied the syscall catch policy's
+	// argument condition.
+	isCatchingSyscall bool
+
+	// Signals received by the thread which are delivered on the next resume
+	// (in order of arrival).
+	heldSignals []syscall.Signal
+
 	*Debugger
 }
 
@@ -66,6 +95,21 @@ func (thread *ThreadState) updateStatus(
 	if status.Stopped {
 		if status.IsInternalSigStop {
 			thread.hasPendingSigStop = false
+		} else if status.StopSignal != syscall.SIGTRAP &&
+			thread.SignalPassPolicy.ShouldPass(status.StopSignal) {
+
+			thread.heldSignals = append(thread.heldSignals, status.StopSignal)
+		}
+
+		if len(thread.heldSignals) > 0 {
+			status.PendingSignal = thread.heldSignals[0]
+		}
+
+		if status.PendingExitStatus != nil {
+			thread.exitEventStatus = status.PendingExitStatus
+
+			// The exiting thread will never receive the pending sig stop.
+			thread.hasPendingSigStop = false
 		}
 
 		if thread.shouldUpdateSharedLibraries(status) {
@@ -86,9 +130,10 @@ func (thread *ThreadState) updateStatus(
 		if status.StopSignal == syscall.SIGTRAP {
 			if status.TrapKind == SyscallTrap {
 				thread.expectsSyscallExit = !thread.expectsSyscallExit
-			} else {
+			} else if status.TrapKind != ExecTrap {
 				// In case syscall catch point got disabled after syscall entry, but
-				// before syscall exit.
+				// before syscall exit.  NOTE: the exec event is reported in between
+				// execve's syscall entry and syscall exit.
 				thread.expectsSyscallExit = false
 			}
 		}
@@ -135,7 +180,7 @@ func (thread *ThreadState) stepInstruction(
 			err)
 	}
 
-	var stepOverAddress *VirtualAddress
+	var stepped *memory.DisassembledInstruction
 	if stepOverCall {
 		instructions, err := thread.Disassemble(
 			thread.status.NextInstructionAddress,
@@ -144,18 +189,15 @@ func (thread *ThreadState) stepInstruction(
 			return fmt.Errorf("failed to determine instruction type: %w", err)
 		}
 
-		// NOTE: golang's x86asm package is unable to disassemble all x64
-		// instructions. When that happens, we'll simply assume the instruction is
-		// not a call instruction.
+		// NOTE: the disassembler may be unable to disassemble all instructions.
+		// When that happens, we'll simply assume the instruction is not a call
+		// instruction.
 		if len(instructions) == 1 {
-			inst := instructions[0]
-			if inst.Op == x86asm.CALL {
-				addr := thread.status.NextInstructionAddress + VirtualAddress(inst.Len)
-				stepOverAddress = &addr
-			}
+			stepped = &instructions[0]
 		}
 	}
 
+	thread.VirtualMemory.InvalidateCache()
 	err = thread.threadTracer.SingleStep()
 	if err != nil {
 		return fmt.Errorf(
@@ -181,13 +223,99 @@ func (thread *ThreadState) stepInstruction(
 			err)
 	}
 
-	if stepOverAddress == nil ||
-		*stepOverAddress == thread.status.NextInstructionAddress {
+	if stepped == nil {
+		return nil
+	}
+
+	stepOverAddress, err := thread.checkSteppedInstruction(*stepped)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to step instruction for thread %d: %w",
+			thread.Tid,
+			err)
+	}
+
+	if stepOverAddress == 0 ||
+		stepOverAddress == thread.status.NextInstructionAddress {
 
 		return nil
 	}
 
-	return thread.resumeUntilAddressOrSignal(*stepOverAddress)
+	return thread.resumeUntilAddressOrSignal(stepOverAddress)
+}
+
+// Validates the stepped instruction's decoded length against the thread's
+// state after the step, and returns the call instruction's return address
+// (i.e., the step over address), or zero if the instruction is not a call.
+//
+// NOTE: the disassembler may mis-decode the length of unsupported / exotic
+// encodings.  On mismatch, a warning is recorded (see NewStepWarnings), and
+// the thread's actual state is trusted instead of the decoded length.
+func (thread *ThreadState) checkSteppedInstruction(
+	inst memory.DisassembledInstruction,
+) (
+	VirtualAddress,
+	error,
+) {
+	expected := inst.Address + VirtualAddress(inst.Length())
+
+	// The step did not complete when the thread stopped for other reasons
+	// (e.g., a signal), or when the thread did not advance (e.g., a rep
+	// prefixed instruction only completed one iteration).
+	status := thread.status
+	if !status.Stopped ||
+		status.TrapKind != SingleStepTrap ||
+		status.NextInstructionAddress == inst.Address {
+
+		if inst.IsCall() {
+			return expected, nil
+		}
+		return 0, nil
+	}
+
+	actual := status.NextInstructionAddress
+	if inst.IsCall() {
+		// The call pushed the actual return address onto the stack.
+		state, err := thread.Registers.GetState()
+		if err != nil {
+			return 0, err
+		}
+
+		out := make([]byte, 8)
+		n, err := thread.VirtualMemory.Read(
+			VirtualAddress(state.Value(registers.StackPointer).ToUint64()),
+			out)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read return address: %w", err)
+		}
+		if n != len(out) {
+			return 0, fmt.Errorf(
+				"failed to read return address. read %d out of %d bytes",
+				n,
+				len(out))
+		}
+
+		actual = VirtualAddress(binary.LittleEndian.Uint64(out))
+	} else if inst.IsBranch() {
+		return 0, nil
+	}
+
+	if actual != expected {
+		thread.stepWarnings = append(
+			thread.stepWarnings,
+			fmt.Sprintf(
+				"decoded instruction length (%d) at %s does not match the "+
+					"actual length (%d): %s",
+				inst.Length(),
+				inst.Address,
+				int64(actual-inst.Address),
+				inst.Format(inst.Address)))
+	}
+
+	if inst.IsCall() {
+		return actual, nil
+	}
+	return 0, nil
 }
 
 func (thread *ThreadState) maybeSwallowInternalSigStop() error {
@@ -200,6 +328,7 @@ func (thread *ThreadState) maybeSwallowInternalSigStop() error {
 	// In theory, multiple signals could be queued up.  We'll keep resuming until
 	// we hit a sig stop.
 	for thread.status.Stopped {
+		thread.VirtualMemory.InvalidateCache()
 		err := thread.threadTracer.Resume(0)
 		if err != nil {
 			return fmt.Errorf("failed to resume thread %d: %w", thread.Tid, err)
@@ -238,24 +367,67 @@ func (thread *ThreadState) maybeBypassCurrentPCBreakSite() error {
 		return err
 	}
 
-	enabledSites := thread.stopSites.GetEnabledAt(
-		thread.status.NextInstructionAddress)
-	if len(enabledSites) > 0 {
+	pc := thread.status.NextInstructionAddress
+	enabledSites := thread.stopSites.GetEnabledAt(pc)
+	if len(enabledSites) == 0 {
+		return nil
+	}
+
+	for {
 		err = thread.stepInstruction(true, false)
 		if err != nil {
 			return fmt.Errorf("failed to resume thread %d: %w", thread.Tid, err)
 		}
+
+		// NOTE: a signal queued alongside the break point trap is reported
+		// before the instruction is stepped.  The signal is held for delivery
+		// on resume, and we'll retry the step.
+		status := thread.status
+		if !status.Stopped ||
+			status.StopSignal == syscall.SIGTRAP ||
+			status.NextInstructionAddress != pc {
+
+			return nil
+		}
 	}
+}
 
-	return nil
+// Returns the held signal to deliver on resume, or zero if there's none.
+// Additional held signals are re-queued to the thread, which will be
+// re-reported by the kernel after the thread resumes.
+func (thread *ThreadState) releaseHeldSignals() (syscall.Signal, error) {
+	if len(thread.heldSignals) == 0 {
+		return 0, nil
+	}
+
+	signal := thread.heldSignals[0]
+	for _, other := range thread.heldSignals[1:] {
+		if other == signal {
+			continue
+		}
+
+		err := thread.signal.ToThread(thread.Tid, other)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	thread.heldSignals = nil
+	return signal, nil
 }
 
 func (thread *ThreadState) resume() error {
-	var err error
-	if thread.SyscallCatchPolicy.IsEnabled() {
-		err = thread.threadTracer.SyscallTrappedResume(0)
+	thread.VirtualMemory.InvalidateCache()
+
+	signal, err := thread.releaseHeldSignals()
+	if err != nil {
+		return fmt.Errorf("failed to resume thread %d: %w", thread.Tid, err)
+	}
+
+	if thread.SyscallCatchPolicy.IsEnabled() || thread.CatchFork {
+		err = thread.threadTracer.SyscallTrappedResume(int(signal))
 	} else {
-		err = thread.threadTracer.Resume(0)
+		err = thread.threadTracer.Resume(int(signal))
 	}
 
 	if err != nil {
@@ -289,6 +461,18 @@ func (thread *ThreadState) resumeUntilAddressOrSignal(
 		return fmt.Errorf("failed to resume until address %s: %w", address, err)
 	}
 
+	if !thread.status.Stopped && thread.processTerminated() {
+		// NOTE: the process' memory is gone.  The internal break site cannot
+		// (and need not) be disabled / deallocated.
+		thread.CallStack.reset()
+
+		return fmt.Errorf(
+			"%w before reaching %s:\n%v",
+			ErrProcessExited,
+			address,
+			thread.status)
+	}
+
 	if isInternalOnly {
 		if thread.status.Stopped &&
 			thread.status.StopSignal == syscall.SIGTRAP &&
@@ -381,8 +565,15 @@ func (thread *ThreadState) stepUntilDifferentLine(stepOver bool) error {
 		return err
 	}
 
+	thread.SourceTrace.record(thread.status)
+
 	mustAdvance := true
-	for {
+	for numSteps := 0; ; numSteps++ {
+		if thread.StepLimit > 0 && numSteps >= thread.StepLimit {
+			thread.status.ExhaustedStepLimit = thread.StepLimit
+			return nil
+		}
+
 		codeRanges := thread.CallStack.UnexecutedInlinedFunctionCodeRanges()
 		var endAddress *VirtualAddress
 		if stepOver && len(codeRanges) > 0 {
@@ -426,6 +617,7 @@ func (thread *ThreadState) stepUntilDifferentLine(stepOver bool) error {
 
 			continue
 		} else {
+			thread.SourceTrace.record(thread.status)
 			return nil
 		}
 	}
@@ -515,6 +707,176 @@ func (thread *ThreadState) StepIn() (*ThreadStatus, error) {
 	return thread.status, nil
 }
 
+// Returns the addresses of the call instructions on the current line, starting
+// from the current pc.
+func (thread *ThreadState) callInstructionsOnCurrentLine() (
+	[]VirtualAddress,
+	error,
+) {
+	origLine, err := thread.LoadedElves.LineEntryAt(
+		thread.status.NextInstructionAddress)
+	if err != nil {
+		return nil, err
+	} else if origLine == nil {
+		return nil, fmt.Errorf(
+			"%w. no line information for %s",
+			ErrInvalidInput,
+			thread.status.NextInstructionAddress)
+	}
+
+	calls := []VirtualAddress{}
+	address := thread.status.NextInstructionAddress
+	for {
+		line, err := thread.LoadedElves.LineEntryAt(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if line == nil ||
+			line.EndSequence ||
+			origLine.FileEntry.Name != line.FileEntry.Name ||
+			origLine.Line != line.Line {
+
+			return calls, nil
+		}
+
+		instructions, err := thread.Disassemble(address, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine instruction type: %w", err)
+		}
+
+		// NOTE: the disassembler may be unable to disassemble all instructions.
+		// We'll stop scanning the line when that happens.
+		if len(instructions) != 1 {
+			return calls, nil
+		}
+
+		inst := instructions[0]
+		if inst.IsCall() {
+			calls = append(calls, address)
+		}
+
+		address += VirtualAddress(inst.Length())
+	}
+}
+
+// Steps (over calls) until the thread reaches the address, or until the
+// thread leaves the current line.  Returns true if the address is reached.
+//
+// NOTE: we can't simply resume until the address since control flow may skip
+// over the address (e.g., the unselected branch of c ? f() : g()), in which
+// case the thread would run freely.
+func (thread *ThreadState) stepOverUntilAddressOnCurrentLine(
+	address VirtualAddress,
+) (
+	bool,
+	error,
+) {
+	origLine, err := thread.LoadedElves.LineEntryAt(
+		thread.status.NextInstructionAddress)
+	if err != nil {
+		return false, err
+	} else if origLine == nil {
+		return false, fmt.Errorf(
+			"%w. no line information for %s",
+			ErrInvalidInput,
+			thread.status.NextInstructionAddress)
+	}
+
+	for numSteps := 0; ; numSteps++ {
+		if address == thread.status.NextInstructionAddress {
+			return true, nil
+		}
+
+		if thread.StepLimit > 0 && numSteps >= thread.StepLimit {
+			thread.status.ExhaustedStepLimit = thread.StepLimit
+			return false, nil
+		}
+
+		err := thread.stepInstruction(true, true)
+		if err != nil {
+			return false, err
+		}
+
+		if thread.status.TrapKind != SingleStepTrap {
+			return false, nil
+		}
+
+		line, err := thread.LoadedElves.LineEntryAt(
+			thread.status.NextInstructionAddress)
+		if err != nil {
+			return false, err
+		}
+
+		if line == nil ||
+			line.EndSequence ||
+			origLine.FileEntry.Name != line.FileEntry.Name ||
+			origLine.Line != line.Line {
+
+			return false, nil
+		}
+	}
+}
+
+// Step into the n-th (1-based) call instruction on the current line, counting
+// from the current pc.
+func (thread *ThreadState) StepIntoCall(callIndex int) (*ThreadStatus, error) {
+	if thread.Exited() {
+		return nil, fmt.Errorf(
+			"failed to step into call for thread %d: %w",
+			thread.Tid,
+			ErrProcessExited)
+	}
+
+	err := thread.maybeSwallowInternalSigStop()
+	if err != nil {
+		return nil, err
+	}
+
+	calls, err := thread.callInstructionsOnCurrentLine()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to step into call for thread %d: %w",
+			thread.Tid,
+			err)
+	}
+
+	if callIndex < 1 || callIndex > len(calls) {
+		return nil, fmt.Errorf(
+			"%w. call index (%d) out of bound. current line has %d call(s)",
+			ErrInvalidInput,
+			callIndex,
+			len(calls))
+	}
+
+	reachedCall, err := thread.stepOverUntilAddressOnCurrentLine(
+		calls[callIndex-1])
+	if err != nil {
+		return nil, err
+	}
+
+	if reachedCall {
+		err = thread.stepInstruction(true, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if thread.status.TrapKind == SingleStepTrap {
+			err = thread.maybeStepOverFunctionPrologue()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	reportStatus := thread.focusOnImportantStatus(thread, nil)
+	if reportStatus != nil {
+		return reportStatus, nil
+	}
+
+	return thread.status, nil
+}
+
 func (thread *ThreadState) StepOver() (*ThreadStatus, error) {
 	if thread.Exited() {
 		return nil, fmt.Errorf(
@@ -619,6 +981,136 @@ func (thread *ThreadState) StepOut() (*ThreadStatus, error) {
 	return thread.status, nil
 }
 
+// Step out of the executing stack's innermost numFrames frames, i.e., resume
+// until control returns to the numFrames-th caller frame.
+func (thread *ThreadState) StepOutFrames(numFrames int) (*ThreadStatus, error) {
+	if thread.Exited() {
+		return nil, fmt.Errorf(
+			"failed to step out for thread %d: %w",
+			thread.Tid,
+			ErrProcessExited)
+	}
+
+	frames := thread.CallStack.ExecutingStack()
+	if numFrames < 1 || numFrames >= len(frames) {
+		return nil, fmt.Errorf(
+			"%w. number of frames (%d) out of bound [1, %d)",
+			ErrInvalidInput,
+			numFrames,
+			len(frames))
+	}
+
+	return thread.stepOutTo(frames[numFrames-1], frames[numFrames])
+}
+
+// Resume until control returns to the innermost caller frame (excluding the
+// executing frame) with the given function name.
+func (thread *ThreadState) StepOutToCaller(name string) (*ThreadStatus, error) {
+	if thread.Exited() {
+		return nil, fmt.Errorf(
+			"failed to step out for thread %d: %w",
+			thread.Tid,
+			ErrProcessExited)
+	}
+
+	frames := thread.CallStack.ExecutingStack()
+	for idx := 1; idx < len(frames); idx++ {
+		if frames[idx].Name == name {
+			return thread.stepOutTo(frames[idx-1], frames[idx])
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w. caller frame (%s) not found in backtrace",
+		ErrInvalidInput,
+		name)
+}
+
+// Resume until the callee frame returns to the caller frame.  The thread
+// may pass through the callee frame's return address multiple times (e.g.,
+// recursive calls).  The callee frame has returned only when the stack
+// pointer is restored to the caller frame's stack pointer.
+func (thread *ThreadState) stepOutTo(
+	callee *CallFrame,
+	caller *CallFrame,
+) (
+	*ThreadStatus,
+	error,
+) {
+	err := thread.maybeSwallowInternalSigStop()
+	if err != nil {
+		return nil, err
+	}
+
+	var returnAddress VirtualAddress
+	if callee.IsInlined() {
+		// XXX: See StepOut.
+		returnAddress = callee.CodeRanges[len(callee.CodeRanges)-1].High
+	} else {
+		returnAddress = callee.ReturnAddress
+	}
+
+	callerSP := caller.Registers.Value(registers.StackPointer)
+	if returnAddress == 0 || callerSP == nil {
+		return nil, fmt.Errorf(
+			"failed to step out for thread %d. "+
+				"cannot determine %s's return location",
+			thread.Tid,
+			callee.Name)
+	}
+
+	for {
+		err = thread.stepInstruction(true, false)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to step out for thread %d: %w",
+				thread.Tid,
+				err)
+		}
+
+		if thread.status.Stopped &&
+			thread.status.TrapKind == SingleStepTrap &&
+			thread.status.NextInstructionAddress != returnAddress {
+
+			err = thread.resumeUntilAddressOrSignal(returnAddress)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to step out for thread %d: %w",
+					thread.Tid,
+					err)
+			}
+		}
+
+		if !thread.status.Stopped ||
+			thread.status.TrapKind != SingleStepTrap ||
+			thread.status.NextInstructionAddress != returnAddress {
+
+			break
+		}
+
+		state, err := thread.Registers.GetState()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to step out for thread %d: %w",
+				thread.Tid,
+				err)
+		}
+
+		if state.Value(registers.StackPointer).ToUint64() >=
+			callerSP.ToUint64() {
+
+			break
+		}
+	}
+
+	reportStatus := thread.focusOnImportantStatus(thread, nil)
+	if reportStatus != nil {
+		return reportStatus, nil
+	}
+
+	return thread.status, nil
+}
+
 func (thread *ThreadState) InvokeMalloc(size int) (VirtualAddress, error) {
 	malloc, err := thread.descriptorPool.GetMalloc()
 	if err != nil {
@@ -642,12 +1134,40 @@ func (thread *ThreadState) InvokeMalloc(size int) (VirtualAddress, error) {
 	return address.(VirtualAddress), nil
 }
 
+// NOTE: when the call is made by a stop point's condition, the stop point
+// (or all break points, see DisableAllBreakPointsInConditionCalls) is
+// disabled for the duration of the call.
 func (thread *ThreadState) Invoke(
 	functionOrMethod *expression.TypedData,
 	arguments []*expression.TypedData,
 ) (
 	*expression.TypedData,
 	error,
+) {
+	disabled, err := thread.disableStopPointsForConditionCall()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := thread.invoke(functionOrMethod, arguments)
+
+	enableErr := thread.enableStopPoints(disabled)
+	if err != nil {
+		return nil, err
+	}
+	if enableErr != nil {
+		return nil, enableErr
+	}
+
+	return result, nil
+}
+
+func (thread *ThreadState) invoke(
+	functionOrMethod *expression.TypedData,
+	arguments []*expression.TypedData,
+) (
+	*expression.TypedData,
+	error,
 ) {
 	signature, funcAddr, err := functionOrMethod.SelectMatchingSignature(
 		arguments)
@@ -671,11 +1191,36 @@ func (thread *ThreadState) Invoke(
 		return nil, err
 	}
 
+	// The internal sites are released by restore, or on early return.
+	var terminate *terminateSite
+	sitesReleased := false
+	releaseSites := func() error {
+		if sitesReleased {
+			return nil
+		}
+		sitesReleased = true
+
+		err := terminate.deallocate()
+		if err != nil {
+			return err
+		}
+
+		return entryPointSite.Deallocate()
+	}
+	defer func() {
+		_ = releaseSites()
+	}()
+
 	err = entryPointSite.Enable()
 	if err != nil {
 		return nil, err
 	}
 
+	terminate, err = thread.allocateTerminateSite()
+	if err != nil {
+		return nil, err
+	}
+
 	originalStatus := thread.status
 	originalCallStack := *thread.CallStack
 	originalState, err := thread.setupRegistersAndStackForCall(
@@ -689,15 +1234,30 @@ func (thread *ThreadState) Invoke(
 		return nil, err
 	}
 
+	restore := func() error {
+		err := thread.Registers.SetState(originalState)
+		if err != nil {
+			return err
+		}
+		thread.status = originalStatus
+		thread.CallStack = &originalCallStack
+
+		return releaseSites()
+	}
+
+	watchdog := thread.startCallWatchdog()
+
 	// NOTE: for simplicity, we assume that invoke is not interruptible by
 	// breakpoints, etc.
 	for {
 		_, err = thread.ResumeUntilSignal()
 		if err != nil {
+			watchdog.stop()
 			return nil, err
 		}
 
 		if !thread.status.Stopped {
+			watchdog.stop()
 			return nil, fmt.Errorf(
 				"thread unexpectedly exited during function invocation:\n%v",
 				thread.status)
@@ -706,21 +1266,48 @@ func (thread *ThreadState) Invoke(
 		if thread.status.NextInstructionAddress == entryPointSite.Address() {
 			break
 		}
+
+		if terminate.isReachedBy(thread.status) {
+			if watchdog.stop() {
+				thread.hasPendingSigStop = true
+			}
+
+			err = restore()
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf(
+				"%w. %s terminated with an uncaught exception",
+				ErrCallUnwound,
+				functionOrMethod.FormatPrefix)
+		}
+
+		if thread.status.StopSignal == syscall.SIGSTOP && watchdog.fired() {
+			err = restore()
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf(
+				"%w. %s did not return within %v",
+				ErrCallTimedOut,
+				functionOrMethod.FormatPrefix,
+				thread.CallTimeout)
+		}
 	}
 
-	returnValue, err := thread.readReturnValueForCall(signature, retValAddr)
-	if err != nil {
-		return nil, err
+	if watchdog.stop() {
+		// The watchdog's sig stop is delivered on the next resume.
+		thread.hasPendingSigStop = true
 	}
 
-	err = thread.Registers.SetState(originalState)
+	returnValue, err := thread.readReturnValueForCall(signature, retValAddr)
 	if err != nil {
 		return nil, err
 	}
-	thread.status = originalStatus
-	thread.CallStack = &originalCallStack
 
-	err = entryPointSite.Deallocate()
+	err = restore()
 	if err != nil {
 		return nil, err
 	}
@@ -728,6 +1315,134 @@ func (thread *ThreadState) Invoke(
 	return returnValue, nil
 }
 
+// The mangled name of std::terminate, which is called by the c++ runtime
+// when an exception is not caught.
+const stdTerminateSymbol = "_ZSt9terminatev"
+
+// An internal break site at std::terminate (see UnwindOnTerminate).
+type terminateSite struct {
+	stoppoint.StopSite
+
+	isInternalOnly bool
+}
+
+// Returns nil when UnwindOnTerminate is disabled, or when the c++
+// runtime library is not loaded.
+func (thread *ThreadState) allocateTerminateSite() (*terminateSite, error) {
+	if !thread.UnwindOnTerminate {
+		return nil, nil
+	}
+
+	for _, symbol := range thread.LoadedElves.SymbolsByName(stdTerminateSymbol) {
+		if symbol.Type() != elf.SymbolTypeFunction || symbol.Value == 0 {
+			continue
+		}
+
+		address, err := thread.LoadedElves.SymbolToVirtualAddress(symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		site, err := thread.stopSites.Allocate(
+			address,
+			stoppoint.NewBreakSiteType(false))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to allocate std::terminate break site: %w",
+				err)
+		}
+
+		isInternalOnly := !site.IsEnabled()
+		if isInternalOnly {
+			err = site.Enable()
+			if err != nil {
+				_ = site.Deallocate()
+				return nil, fmt.Errorf(
+					"failed to enable std::terminate break site: %w",
+					err)
+			}
+		}
+
+		return &terminateSite{
+			StopSite:       site,
+			isInternalOnly: isInternalOnly,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (site *terminateSite) isReachedBy(status *ThreadStatus) bool {
+	return site != nil &&
+		status.Stopped &&
+		status.StopSignal == syscall.SIGTRAP &&
+		status.NextInstructionAddress == site.Address()
+}
+
+func (site *terminateSite) deallocate() error {
+	if site == nil {
+		return nil
+	}
+
+	if site.isInternalOnly {
+		err := site.Disable()
+		if err != nil {
+			return fmt.Errorf("failed to disable std::terminate break site: %w", err)
+		}
+	}
+
+	return site.Deallocate()
+}
+
+// Stops the thread (via SIGSTOP) when the inferior function call exceeds the
+// call timeout.
+type callWatchdog struct {
+	timer *time.Timer
+
+	// closed once the sig stop is sent.
+	sent chan struct{}
+}
+
+func (thread *ThreadState) startCallWatchdog() *callWatchdog {
+	watchdog := &callWatchdog{
+		sent: make(chan struct{}),
+	}
+
+	if thread.CallTimeout <= 0 {
+		return watchdog
+	}
+
+	watchdog.timer = time.AfterFunc(
+		thread.CallTimeout,
+		func() {
+			// NOTE: the error is ignored since the thread may have exited.
+			_ = thread.signal.StopToThread(thread.Tid)
+			close(watchdog.sent)
+		})
+
+	return watchdog
+}
+
+func (watchdog *callWatchdog) fired() bool {
+	select {
+	case <-watchdog.sent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns true if the watchdog has (or is about to) sent the sig stop.  This
+// blocks until the sig stop is sent.
+func (watchdog *callWatchdog) stop() bool {
+	if watchdog.timer == nil || watchdog.timer.Stop() {
+		return false
+	}
+
+	<-watchdog.sent
+	return true
+}
+
 func (thread *ThreadState) setupRegistersAndStackForCall(
 	signature *expression.SignatureDescriptor,
 	funcAddr VirtualAddress,
@@ -809,7 +1524,7 @@ func (thread *ThreadState) setupRegistersAndStackForCall(
 	}
 
 	arguments := explicitArgs
-	if functionOrMethod.Kind == expression.MethodKind {
+	if signature.IsMethod {
 		receiver := functionOrMethod.MethodReceiverPointer(signature)
 		arguments = append([]*expression.TypedData{receiver}, explicitArgs...)
 	}
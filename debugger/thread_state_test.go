@@ -0,0 +1,241 @@
+package debugger
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/memory"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+	"github.com/pattyshack/bad/ptrace/ptracetest"
+)
+
+const (
+	fakePid = 1000
+
+	// si_code values (see TrapCodeToKind)
+	fakeSoftwareTrapCode   = 0x80
+	fakeSingleStepTrapCode = 2
+)
+
+func stoppedWaitStatus(signal syscall.Signal) syscall.WaitStatus {
+	return syscall.WaitStatus(int(signal)<<8 | 0x7f)
+}
+
+func exitedWaitStatus(exitStatus int) syscall.WaitStatus {
+	return syscall.WaitStatus(exitStatus << 8)
+}
+
+type fakeWaitEvent struct {
+	tid        int
+	waitStatus syscall.WaitStatus
+}
+
+// A fake process signaler, which reports queued wait events in order.
+type fakeSignaler struct {
+	isResumed bool
+
+	// Signals sent to threads, formatted as "<tid> <signal>".
+	sent []string
+
+	events []fakeWaitEvent
+}
+
+func (signaler *fakeSignaler) queue(tid int, waitStatus syscall.WaitStatus) {
+	signaler.events = append(
+		signaler.events,
+		fakeWaitEvent{
+			tid:        tid,
+			waitStatus: waitStatus,
+		})
+}
+
+func (signaler *fakeSignaler) Close() error {
+	return nil
+}
+
+func (signaler *fakeSignaler) ForwardInterruptToProcess() {
+}
+
+func (signaler *fakeSignaler) setResumed(isResumed bool) bool {
+	wasResumed := signaler.isResumed
+	signaler.isResumed = isResumed
+	return wasResumed
+}
+
+func (signaler *fakeSignaler) ContinueToProcess() error {
+	return signaler.ToThread(fakePid, syscall.SIGCONT)
+}
+
+func (signaler *fakeSignaler) StopToProcess() error {
+	return signaler.ToThread(fakePid, syscall.SIGSTOP)
+}
+
+func (signaler *fakeSignaler) KillToProcess() error {
+	return signaler.ToThread(fakePid, syscall.SIGKILL)
+}
+
+func (signaler *fakeSignaler) StopToThread(tid int) error {
+	return signaler.ToThread(tid, syscall.SIGSTOP)
+}
+
+func (signaler *fakeSignaler) ToThread(tid int, signal syscall.Signal) error {
+	signaler.sent = append(signaler.sent, fmt.Sprintf("%d %v", tid, signal))
+	return nil
+}
+
+func (signaler *fakeSignaler) FromProcessThreads() (
+	int,
+	syscall.WaitStatus,
+	error,
+) {
+	if len(signaler.events) == 0 {
+		return 0, 0, fmt.Errorf("no pending wait event")
+	}
+
+	event := signaler.events[0]
+	signaler.events = signaler.events[1:]
+	return event.tid, event.waitStatus, nil
+}
+
+func (signaler *fakeSignaler) FromThread(tid int) (syscall.WaitStatus, error) {
+	for idx, event := range signaler.events {
+		if event.tid == tid {
+			signaler.events = append(
+				signaler.events[:idx],
+				signaler.events[idx+1:]...)
+			return event.waitStatus, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no pending wait event for thread %d", tid)
+}
+
+// Returns a debugger for a fake single threaded process, stopped at the
+// start address.  The fake process executes one byte instructions (e.g.,
+// nop), starting from the start address.
+func newFakeProcess(
+	t *testing.T,
+	start VirtualAddress,
+	code []byte,
+) (
+	*Debugger,
+	*ptracetest.FakeTracer,
+	*fakeSignaler,
+) {
+	tracer := ptracetest.NewFakeTracer(fakePid)
+	tracer.MapMemory(uintptr(start), code)
+	tracer.Regs.Rip = uint64(start)
+
+	signaler := &fakeSignaler{}
+
+	db := newDebuggerState(
+		fakePid,
+		nil,
+		memory.New(fakePid, tracer),
+		signaler,
+		true)
+
+	_, err := db.addThread(fakePid, tracer, stoppedWaitStatus(syscall.SIGTRAP))
+	expect.Nil(t, err)
+
+	return db, tracer, signaler
+}
+
+func (DebuggerSuite) TestFakeStepInstruction(t *testing.T) {
+	db, tracer, signaler := newFakeProcess(
+		t,
+		0x1000,
+		[]byte{0x90, 0x90, 0x90, 0x90})
+
+	tracer.OnSingleStep = func(tracer *ptracetest.FakeTracer) error {
+		tracer.Regs.Rip++
+		tracer.SigInfo.Code = fakeSingleStepTrapCode
+		signaler.queue(fakePid, stoppedWaitStatus(syscall.SIGTRAP))
+		return nil
+	}
+
+	thread := db.currentThread()
+
+	status, err := thread.StepInstruction()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SingleStepTrap, status.TrapKind)
+	expect.Equal(t, 0x1001, status.NextInstructionAddress)
+
+	status, err = thread.StepInstruction()
+	expect.Nil(t, err)
+	expect.Equal(t, 0x1002, status.NextInstructionAddress)
+
+	expect.Equal(t, []string{"single step", "single step"}, tracer.Operations)
+	expect.Equal(t, 0, len(signaler.events))
+}
+
+func (DebuggerSuite) TestFakeResumeUntilBreakPoint(t *testing.T) {
+	db, tracer, signaler := newFakeProcess(
+		t,
+		0x1000,
+		[]byte{0x90, 0x90, 0x90, 0x90})
+
+	// Simulates execution until the next int3 (or the end of the code, in
+	// which case the process exits).
+	tracer.OnResume = func(tracer *ptracetest.FakeTracer, signal int) error {
+		region := tracer.Memory[0]
+		end := uint64(region.Address) + uint64(len(region.Data))
+		for ; tracer.Regs.Rip < end; tracer.Regs.Rip++ {
+			if region.Data[tracer.Regs.Rip-uint64(region.Address)] == 0xcc {
+				// The trap is reported after the int3 is executed.
+				tracer.Regs.Rip++
+				tracer.SigInfo.Code = fakeSoftwareTrapCode
+				signaler.queue(fakePid, stoppedWaitStatus(syscall.SIGTRAP))
+				return nil
+			}
+		}
+
+		signaler.queue(fakePid, exitedWaitStatus(0))
+		return nil
+	}
+	tracer.OnSingleStep = func(tracer *ptracetest.FakeTracer) error {
+		tracer.Regs.Rip++
+		tracer.SigInfo.Code = fakeSingleStepTrapCode
+		signaler.queue(fakePid, stoppedWaitStatus(syscall.SIGTRAP))
+		return nil
+	}
+
+	point, err := db.BreakPoints.Set(
+		db.NewAddressResolver(0x1002),
+		stoppoint.NewBreakSiteType(false),
+		true)
+	expect.Nil(t, err)
+
+	// The int3 is patched into the fake process' memory.
+	expect.Equal(t, []byte{0x90, 0x90, 0xcc, 0x90}, tracer.Memory[0].Data)
+
+	thread := db.currentThread()
+
+	status, err := thread.ResumeUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Stopped)
+	expect.Equal(t, SoftwareTrap, status.TrapKind)
+	expect.Equal(t, 0x1002, status.NextInstructionAddress)
+	expect.Equal(t, 1, len(status.StopPoints))
+	expect.Equal(t, point.Id(), status.StopPoints[0].Id())
+
+	// The program counter is reset to the break point's address.
+	expect.Equal(t, uint64(0x1002), tracer.Regs.Rip)
+
+	// The break site is bypassed (by single stepping the original
+	// instruction with the site disabled) before resuming.
+	status, err = thread.ResumeUntilSignal()
+	expect.Nil(t, err)
+	expect.True(t, status.Exited)
+	expect.Equal(t, 0, status.ExitStatus)
+
+	expect.Equal(
+		t,
+		[]string{"resume 0", "single step", "resume 0"},
+		tracer.Operations)
+}
@@ -3,6 +3,7 @@ package debugger
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path"
 	"syscall"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/pattyshack/bad/debugger/stoppoint"
 	"github.com/pattyshack/bad/dwarf"
 	"github.com/pattyshack/bad/elf"
+	"github.com/pattyshack/bad/procfs"
 	"github.com/pattyshack/bad/ptrace"
 )
 
@@ -21,8 +23,24 @@ const (
 	// The event is triggered on the clone caller thread.  A corresponding
 	// sig stop is trigger by the newly thread.
 	cloneTrapExtendedSignal = int(syscall.SIGTRAP) | int(ptrace.EVENT_CLONE<<8)
+
+	// NOTE: the exit event is triggered by the exiting thread right before it
+	// exits.  The thread's exit status is retrievable via the event message.
+	exitTrapExtendedSignal = int(syscall.SIGTRAP) | int(ptrace.EVENT_EXIT<<8)
+
+	// NOTE: the exec event is triggered after execve successfully replaced the
+	// process' address space, but before the new program starts running.
+	execTrapExtendedSignal = int(syscall.SIGTRAP) | int(ptrace.EVENT_EXEC<<8)
 )
 
+func isExecEvent(waitStatus syscall.WaitStatus) bool {
+	return waitStatus.Stopped() && int(waitStatus>>8) == execTrapExtendedSignal
+}
+
+func isExitEvent(waitStatus syscall.WaitStatus) bool {
+	return waitStatus.Stopped() && int(waitStatus>>8) == exitTrapExtendedSignal
+}
+
 type ThreadStatus struct {
 	Tid int
 
@@ -55,8 +73,38 @@ type ThreadStatus struct {
 	// Only populated when thread is stopped by break points / watch points
 	StopPoints []stoppoint.Triggered
 
+	// The stopped function's name and parameter types (e.g.,
+	// "print_type(int32)"), which identifies the overload that triggered a
+	// break point.  Only populated when thread is stopped by break points.
+	FunctionSignature string
+
 	// Only populated when thread is stopped by SyscallTrap
 	SyscallTrapInfo *catchpoint.SyscallTrapInfo
+
+	// Only populated when thread is stopped by an exception catch point
+	ExceptionTrapInfo *catchpoint.ExceptionTrapInfo
+
+	// Only populated when thread is stopped by ExitTrap
+	PendingExitStatus *syscall.WaitStatus
+
+	// The signal held for delivery on the thread's next resume.  This is zero
+	// when no signal is held.  A (non-trap) signal is held when it's received
+	// by the thread and the signal pass policy allows its delivery.  Note that
+	// the signal may have been received in an earlier stop, e.g., a signal
+	// queued alongside a break point trap.
+	PendingSignal syscall.Signal
+
+	// True when all triggered stop points were skipped and at least one of
+	// them is a break point, in which case the break site at the current
+	// program counter must be bypassed before silently resuming the thread.
+	shouldBypassBreakSite bool
+
+	// Only populated when thread is stopped by ExecTrap
+	ExecutedProgram string
+
+	// The exhausted step limit when a step in / step over stopped before
+	// reaching a different line (see Debugger.StepLimit).  Zero otherwise.
+	ExhaustedStepLimit int
 }
 
 func (status ThreadStatus) Running() bool {
@@ -96,12 +144,52 @@ func (status ThreadStatus) String() string {
 
 				reason += fmt.Sprintf("\n    %s (id=%d)", point.Type(), point.Id())
 				reason += fmt.Sprintf("\n      resolver: %s", point.Resolver())
+				if point.Condition() != "" {
+					reason += fmt.Sprintf("\n      condition: %s", point.Condition())
+				}
 				reason += fmt.Sprintf("\n      triggered: %s%s", site.Key(), dataStr)
+				if len(point.Sites()) > 1 {
+					reason += fmt.Sprintf(
+						" (site %d of %d)",
+						triggered.SiteIndex,
+						len(point.Sites()))
+				}
+				if !point.Type().IsWatchPoint && status.FunctionSignature != "" {
+					reason += "\n      function: " + status.FunctionSignature
+				}
 			}
 
 			if status.SyscallTrapInfo != nil {
 				reason += "\n" + status.SyscallTrapInfo.String()
 			}
+
+			if status.ExceptionTrapInfo != nil {
+				reason += "\n    " + status.ExceptionTrapInfo.String()
+			}
+
+			if status.PendingExitStatus != nil {
+				exitStatus := *status.PendingExitStatus
+				if exitStatus.Signaled() {
+					reason += fmt.Sprintf(
+						"\n    terminating with signal: %v",
+						exitStatus.Signal())
+				} else {
+					reason += fmt.Sprintf(
+						"\n    exiting with status: %d",
+						exitStatus.ExitStatus())
+				}
+			}
+
+			if status.ExecutedProgram != "" {
+				reason += "\n    executing new program: " + status.ExecutedProgram
+			}
+
+			if status.ExhaustedStepLimit > 0 {
+				reason += fmt.Sprintf(
+					"\n    step limit (%d single steps) reached before leaving the "+
+						"line",
+					status.ExhaustedStepLimit)
+			}
 		}
 
 		onLine := ""
@@ -114,6 +202,12 @@ func (status ThreadStatus) String() string {
 			inFunc = " (" + status.FunctionName + ")"
 		}
 
+		if status.PendingSignal != 0 {
+			reason += fmt.Sprintf(
+				"\n  pending signal (delivered on resume): %v",
+				status.PendingSignal)
+		}
+
 		return fmt.Sprintf(
 			"thread %d stopped\n  at: %s%s%s\n  with signal: %v%s",
 			status.Tid,
@@ -137,6 +231,103 @@ func (status ThreadStatus) String() string {
 	}
 }
 
+// Returns a concise summary of why the thread stopped: the trap kind (the
+// triggered break / watch points, signal, step, syscall entry / exit, etc.),
+// the stop location, and any watched value change.  Unlike String, details
+// such as stop point resolvers and pending signals are omitted.
+func (status ThreadStatus) StopSummary() string {
+	if status.Running() {
+		return fmt.Sprintf("thread %d running", status.Tid)
+	} else if status.Signaled {
+		return fmt.Sprintf(
+			"thread %d\n  reason: terminated with signal: %v",
+			status.Tid,
+			status.Signal)
+	} else if status.Exited {
+		return fmt.Sprintf(
+			"thread %d\n  reason: exited with status: %d",
+			status.Tid,
+			status.ExitStatus)
+	}
+
+	reason := fmt.Sprintf("signal %v", status.StopSignal)
+	changes := ""
+	if status.StopSignal == syscall.SIGTRAP {
+		switch {
+		case len(status.StopPoints) > 0:
+			reason = ""
+			for idx, triggered := range status.StopPoints {
+				point := triggered.StopPoint
+				if idx > 0 {
+					reason += ", "
+				}
+
+				kind := "break point"
+				if point.Type().IsWatchPoint {
+					kind = "watch point"
+				}
+				reason += fmt.Sprintf("%s (id=%d)", kind, point.Id())
+
+				site := triggered.StopSite
+				if !point.Type().IsWatchPoint ||
+					bytes.Equal(site.PreviousData(), site.Data()) {
+
+					continue
+				}
+
+				changes += fmt.Sprintf("\n  %s (id=%d) changed:", kind, point.Id())
+				for _, b := range site.PreviousData() {
+					changes += fmt.Sprintf(" 0x%02x", b)
+				}
+				changes += " ->"
+				for _, b := range site.Data() {
+					changes += fmt.Sprintf(" 0x%02x", b)
+				}
+			}
+		case status.SyscallTrapInfo != nil:
+			reason = status.SyscallTrapInfo.String()
+		case status.ExceptionTrapInfo != nil:
+			reason = status.ExceptionTrapInfo.String()
+		case status.PendingExitStatus != nil:
+			exitStatus := *status.PendingExitStatus
+			if exitStatus.Signaled() {
+				reason = fmt.Sprintf(
+					"terminating with signal: %v",
+					exitStatus.Signal())
+			} else {
+				reason = fmt.Sprintf(
+					"exiting with status: %d",
+					exitStatus.ExitStatus())
+			}
+		case status.ExecutedProgram != "":
+			reason = "executing new program: " + status.ExecutedProgram
+		case status.ExhaustedStepLimit > 0:
+			reason = fmt.Sprintf(
+				"step (limit of %d single steps reached)",
+				status.ExhaustedStepLimit)
+		case status.TrapKind == SingleStepTrap:
+			reason = "step"
+		case status.TrapKind != UnknownTrap:
+			reason = string(status.TrapKind)
+		}
+	}
+
+	at := status.NextInstructionAddress.String()
+	if status.FileEntry != nil {
+		at += fmt.Sprintf(" %s:%d", status.FileEntry.Path(), status.Line)
+	}
+	if status.FunctionName != "" {
+		at += " (" + status.FunctionName + ")"
+	}
+
+	return fmt.Sprintf(
+		"thread %d\n  reason: %s\n  at: %s%s",
+		status.Tid,
+		reason,
+		at,
+		changes)
+}
+
 func newRunningStatus(tid int) *ThreadStatus {
 	return &ThreadStatus{
 		Tid: tid,
@@ -153,6 +344,7 @@ func newInlinedStepInStatus(
 		NextInstructionAddress: status.NextInstructionAddress,
 		FunctionName:           status.FunctionName,
 		TrapKind:               SingleStepTrap,
+		PendingSignal:          status.PendingSignal,
 	}
 }
 
@@ -184,6 +376,16 @@ func newDetailedWaitingStatus(
 	status := newSimpleWaitingStatus(thread.Tid, waitStatus)
 
 	if !status.Stopped {
+		// The exit event's status is authoritative since the final wait status
+		// may be unreliable for the last exiting thread.
+		if thread.exitEventStatus != nil {
+			exitStatus := *thread.exitEventStatus
+			status.Signaled = exitStatus.Signaled()
+			status.Signal = exitStatus.Signal()
+			status.Exited = exitStatus.Exited()
+			status.ExitStatus = exitStatus.ExitStatus()
+		}
+
 		return status, false, nil
 	}
 
@@ -205,12 +407,35 @@ func newDetailedWaitingStatus(
 		if thread.expectsSyscallExit { // syscall returned
 			status.SyscallTrapInfo = catchpoint.NewSyscallTrapExitInfo(registerState)
 		} else { // syscall entry
-			status.SyscallTrapInfo = catchpoint.NewSyscallTrapEntryInfo(registerState)
+			status.SyscallTrapInfo = catchpoint.NewSyscallTrapEntryInfo(
+				registerState,
+				thread.VirtualMemory)
 		}
 	} else if status.StopSignal == syscall.SIGTRAP {
-		// NOTE: clone ptrace event use bits aren't part of the stop signal.
+		// NOTE: clone / exit ptrace event use bits aren't part of the stop
+		// signal.
 		if int(waitStatus>>8) == cloneTrapExtendedSignal {
 			status.TrapKind = CloneTrap
+		} else if int(waitStatus>>8) == execTrapExtendedSignal {
+			status.TrapKind = ExecTrap
+
+			program, err := os.Readlink(procfs.GetExecutableSymlinkPath(thread.Pid))
+			if err != nil {
+				return nil, false, fmt.Errorf(
+					"failed to read executed program path: %w",
+					err)
+			}
+			status.ExecutedProgram = program
+		} else if int(waitStatus>>8) == exitTrapExtendedSignal {
+			status.TrapKind = ExitTrap
+
+			msg, err := thread.threadTracer.GetEventMsg()
+			if err != nil {
+				return nil, false, err
+			}
+
+			exitStatus := syscall.WaitStatus(msg)
+			status.PendingExitStatus = &exitStatus
 		} else {
 			sigInfo, err := thread.threadTracer.GetSigInfo()
 			if err != nil {
@@ -234,10 +459,29 @@ func newDetailedWaitingStatus(
 		triggered = append(triggered, thread.WatchPoints.Match(siteKeys)...)
 		status.StopPoints = triggered
 
+		event, ok := thread.matchExceptionCatchPoint(siteKeys)
+		if ok {
+			// NOTE: the program counter is at the event function's entry point.
+			info, err := catchpoint.NewExceptionTrapInfo(
+				event,
+				registerState,
+				thread.VirtualMemory)
+			if err != nil {
+				return nil, false, err
+			}
+
+			status.ExceptionTrapInfo = info
+			if status.TrapKind == SoftwareTrap && len(status.StopPoints) == 0 {
+				status.TrapKind = ExceptionTrap
+			}
+		}
+
 		if status.TrapKind == SoftwareTrap && len(status.StopPoints) == 0 {
 			_, ok := thread.rendezvousAddresses[pc]
 			if ok {
 				status.TrapKind = RendezvousTrap
+			} else if thread.isWatchPointScopeReturnAddress(pc) {
+				status.TrapKind = WatchPointScopeTrap
 			}
 		}
 	}
@@ -261,6 +505,19 @@ func newDetailedWaitingStatus(
 			prefix = path.Base(funcEntry.CompileUnit.FileName) + "|"
 		}
 		status.FunctionName = prefix + name
+
+		for _, triggered := range status.StopPoints {
+			if triggered.StopPoint.Type().IsWatchPoint {
+				continue
+			}
+
+			// NOTE: the signature is best effort, and is only used for reporting.
+			signature, err := thread.descriptorPool.FunctionSignatureName(funcEntry)
+			if err == nil {
+				status.FunctionSignature = signature
+			}
+			break
+		}
 	}
 
 	if status.FunctionName == "" {
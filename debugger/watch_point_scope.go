@@ -0,0 +1,387 @@
+package debugger
+
+import (
+	"fmt"
+	"sort"
+
+	. "github.com/pattyshack/bad/debugger/common"
+	"github.com/pattyshack/bad/debugger/registers"
+	"github.com/pattyshack/bad/debugger/stoppoint"
+)
+
+// Controls what happens to a scoped watch point once its frame returns.
+type WatchPointScopeMode string
+
+const (
+	// The out of scope watch point is removed, which releases its hardware
+	// stop site (debug register) for reuse.
+	WatchPointScopeAuto = WatchPointScopeMode("auto")
+
+	// The out of scope watch point is kept, and continues to occupy its debug
+	// register, until the user removes it.
+	WatchPointScopeManual = WatchPointScopeMode("manual")
+)
+
+var WatchPointScopeModes = []WatchPointScopeMode{
+	WatchPointScopeAuto,
+	WatchPointScopeManual,
+}
+
+// A scoped watch point's frame.  The watch point goes out of scope once the
+// frame returns, i.e., the thread stops with its stack pointer above the
+// frame's canonical frame address, or the thread exits.
+type WatchPointScope struct {
+	Tid          int
+	FunctionName string
+
+	CanonicalFrameAddress VirtualAddress
+	ReturnAddress         VirtualAddress
+}
+
+func (scope WatchPointScope) String() string {
+	return fmt.Sprintf(
+		"thread %d frame %s (cfa=%s)",
+		scope.Tid,
+		scope.FunctionName,
+		scope.CanonicalFrameAddress)
+}
+
+type WatchPointScopeExit struct {
+	WatchPoint *stoppoint.StopPoint
+	WatchPointScope
+
+	// True when the watch point was removed (see WatchPointScopeAuto).
+	Removed bool
+}
+
+func (exit WatchPointScopeExit) String() string {
+	action := "kept"
+	if exit.Removed {
+		action = "removed"
+	}
+
+	return fmt.Sprintf(
+		"watch point (id=%d) out of scope (%s). %s",
+		exit.WatchPoint.Id(),
+		exit.WatchPointScope,
+		action)
+}
+
+type watchPointScope struct {
+	WatchPointScope
+
+	point *stoppoint.StopPoint
+
+	// An internal break site at the frame's return address, which stops the
+	// thread right after the frame returns.
+	returnSite stoppoint.StopSite
+}
+
+// Sets a watch point which is scoped to the current thread's inspect frame
+// (e.g., a watch point on one of the frame's local variables).  The condition
+// may be empty (see StopPoint.SetCondition).
+func (db *Debugger) SetScopedWatchPoint(
+	resolver stoppoint.StopSiteResolver,
+	siteType stoppoint.StopSiteType,
+	enableOnCreation bool,
+	condition string,
+) (
+	*stoppoint.StopPoint,
+	error,
+) {
+	thread := db.currentThread()
+
+	frame := thread.CallStack.CurrentInspectFrame()
+	if frame == nil {
+		return nil, fmt.Errorf(
+			"%w. cannot set scoped watch point. no inspect frame",
+			ErrInvalidInput)
+	}
+
+	cfa, err := frame.CanonicalFrameAddress()
+	if err != nil {
+		return nil, fmt.Errorf("cannot set scoped watch point: %w", err)
+	}
+
+	baseFrame := frame
+	if frame.BaseFrame != nil {
+		baseFrame = frame.BaseFrame
+	}
+
+	if baseFrame.ReturnAddress == 0 {
+		return nil, fmt.Errorf(
+			"%w. cannot set scoped watch point. frame %s has no caller",
+			ErrInvalidInput,
+			frame.Name)
+	}
+
+	scope := WatchPointScope{
+		Tid:                   thread.Tid,
+		FunctionName:          frame.Name,
+		CanonicalFrameAddress: VirtualAddress(cfa),
+		ReturnAddress:         baseFrame.ReturnAddress,
+	}
+
+	point, err := db.WatchPoints.SetWithOptions(
+		resolver,
+		siteType,
+		enableOnCreation,
+		stoppoint.StopPointOptions{
+			Condition: condition,
+			Scope:     scope.String(),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: the return site may be shared with user break points.
+	returnSite, err := db.stopSites.Allocate(
+		baseFrame.ReturnAddress,
+		stoppoint.NewBreakSiteType(false))
+	if err == nil && !returnSite.IsEnabled() {
+		err = returnSite.Enable()
+		if err != nil {
+			_ = returnSite.Deallocate()
+		}
+	}
+
+	if err != nil {
+		_ = db.WatchPoints.Remove(point.Id())
+		return nil, fmt.Errorf(
+			"cannot set scoped watch point. "+
+				"failed to allocate return address break site: %w",
+			err)
+	}
+
+	db.watchPointScopes[point.Id()] = &watchPointScope{
+		WatchPointScope: scope,
+		point:           point,
+		returnSite:      returnSite,
+	}
+
+	return point, nil
+}
+
+// Returns the watch point's scope.  ok is false for unscoped watch points.
+func (db *Debugger) WatchPointScope(id int64) (WatchPointScope, bool) {
+	scope, ok := db.watchPointScopes[id]
+	if !ok {
+		return WatchPointScope{}, false
+	}
+	return scope.WatchPointScope, true
+}
+
+// Notify is called whenever a scoped watch point goes out of scope.
+func (db *Debugger) WatchWatchPointScopeExits(
+	notify func(WatchPointScopeExit),
+) {
+	db.watchPointScopeWatchers = append(db.watchPointScopeWatchers, notify)
+}
+
+// Returns the number of unoccupied debug registers.
+func (db *Debugger) AvailableHardwareStopSites() int {
+	return db.stopSites.AvailableHardwareStopSites()
+}
+
+func (db *Debugger) isWatchPointScopeReturnAddress(pc VirtualAddress) bool {
+	for _, scope := range db.watchPointScopes {
+		if scope.ReturnAddress == pc {
+			return true
+		}
+	}
+	return false
+}
+
+func (db *Debugger) sortedWatchPointScopes() []*watchPointScope {
+	result := make([]*watchPointScope, 0, len(db.watchPointScopes))
+	for _, scope := range db.watchPointScopes {
+		result = append(result, scope)
+	}
+
+	sort.Slice(
+		result,
+		func(i int, j int) bool {
+			return result[i].point.Id() < result[j].point.Id()
+		})
+	return result
+}
+
+func (db *Debugger) isOutOfScope(scope *watchPointScope) (bool, error) {
+	thread, ok := db.threads[scope.Tid]
+	if !ok {
+		return true, nil
+	}
+
+	if !thread.status.Stopped {
+		return !thread.status.Running(), nil
+	}
+
+	if thread.status.TrapKind == ExitTrap {
+		return true, nil
+	}
+
+	state, err := thread.Registers.GetState()
+	if err != nil {
+		return false, err
+	}
+
+	stackPointer := VirtualAddress(
+		state.Value(registers.StackPointer).ToUint64())
+	return stackPointer >= scope.CanonicalFrameAddress, nil
+}
+
+// Handles scoped watch points whose frames have returned (as specified by
+// WatchPointScopeMode).  Triggered watch points which are removed as a result
+// are dropped from the stopped threads' statuses.
+func (db *Debugger) updateWatchPointScopes(
+	stoppedThreads map[int]*ThreadState,
+) error {
+	if len(db.watchPointScopes) == 0 {
+		return nil
+	}
+
+	hasRemoved := false
+	for _, scope := range db.sortedWatchPointScopes() {
+		point, ok := db.WatchPoints.Get(scope.point.Id())
+		if !ok || point != scope.point { // removed by the user
+			err := db.releaseWatchPointScope(scope, true)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		outOfScope, err := db.isOutOfScope(scope)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to check watch point (id=%d) scope: %w",
+				point.Id(),
+				err)
+		}
+
+		if !outOfScope {
+			continue
+		}
+
+		removed, err := db.exitWatchPointScope(scope, true)
+		if err != nil {
+			return err
+		}
+
+		hasRemoved = hasRemoved || removed
+	}
+
+	if !hasRemoved {
+		return nil
+	}
+
+	for _, thread := range stoppedThreads {
+		status := thread.status
+		if len(status.StopPoints) == 0 {
+			continue
+		}
+
+		remaining := status.StopPoints[:0]
+		for _, triggered := range status.StopPoints {
+			if triggered.StopPoint.Type().IsWatchPoint {
+				point, ok := db.WatchPoints.Get(triggered.Id())
+				if !ok || point != triggered.StopPoint {
+					continue
+				}
+			}
+
+			remaining = append(remaining, triggered)
+		}
+
+		status.StopPoints = remaining
+		if len(remaining) == 0 {
+			// The thread only stopped because of watch points which are no
+			// longer in scope.
+			status.TrapKind = WatchPointScopeTrap
+		}
+	}
+
+	return nil
+}
+
+// Exits all scopes.  This is used when the process' address space has been
+// replaced (e.g., after execve), in which case the return sites are no longer
+// valid and must not be deallocated, or when the process' threads have been
+// replaced (e.g., after restarting a checkpoint).
+func (db *Debugger) exitAllWatchPointScopes(deallocateReturnSites bool) error {
+	for _, scope := range db.sortedWatchPointScopes() {
+		point, ok := db.WatchPoints.Get(scope.point.Id())
+		if !ok || point != scope.point {
+			delete(db.watchPointScopes, scope.point.Id())
+			continue
+		}
+
+		_, err := db.exitWatchPointScope(scope, deallocateReturnSites)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *Debugger) exitWatchPointScope(
+	scope *watchPointScope,
+	deallocateReturnSite bool,
+) (
+	bool,
+	error,
+) {
+	err := db.releaseWatchPointScope(scope, deallocateReturnSite)
+	if err != nil {
+		return false, err
+	}
+
+	removed := false
+	if db.WatchPointScopeMode == WatchPointScopeAuto {
+		// NOTE: removing the watch point deallocates its hardware stop sites,
+		// which releases the debug registers for reuse.  Disabling the watch
+		// point is insufficient since disabled sites remain allocated.
+		err = db.WatchPoints.Remove(scope.point.Id())
+		if err != nil {
+			return false, fmt.Errorf(
+				"failed to remove out of scope watch point (id=%d): %w",
+				scope.point.Id(),
+				err)
+		}
+		removed = true
+	}
+
+	exit := WatchPointScopeExit{
+		WatchPoint:      scope.point,
+		WatchPointScope: scope.WatchPointScope,
+		Removed:         removed,
+	}
+
+	for _, notify := range db.watchPointScopeWatchers {
+		notify(exit)
+	}
+
+	return removed, nil
+}
+
+func (db *Debugger) releaseWatchPointScope(
+	scope *watchPointScope,
+	deallocateReturnSite bool,
+) error {
+	delete(db.watchPointScopes, scope.point.Id())
+
+	if !deallocateReturnSite {
+		return nil
+	}
+
+	err := scope.returnSite.Deallocate()
+	if err != nil {
+		return fmt.Errorf(
+			"failed to deallocate watch point (id=%d) return site: %w",
+			scope.point.Id(),
+			err)
+	}
+
+	return nil
+}
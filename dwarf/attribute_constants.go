@@ -103,9 +103,15 @@ const (
 	DW_AT_enum_class           = Attribute(0x6d)
 	DW_AT_linkage_name         = Attribute(0x6e)
 
+	DW_AT_call_return_pc = Attribute(0x7d) // dwarf5
+	DW_AT_call_value     = Attribute(0x7e) // dwarf5
+
 	DW_AT_defaulted = Attribute(0x8b)
 
 	DW_AT_lo_user = Attribute(0x2000)
+
+	DW_AT_GNU_call_site_value = Attribute(0x2111) // dwarf4 gnu extension
+
 	DW_AT_hi_user = Attribute(0x3fff)
 )
 
@@ -295,10 +301,16 @@ func (attribute Attribute) String() string {
 		return "DW_AT_enum_class"
 	case DW_AT_linkage_name:
 		return "DW_AT_linkage_name"
+	case DW_AT_call_return_pc:
+		return "DW_AT_call_return_pc"
+	case DW_AT_call_value:
+		return "DW_AT_call_value"
 	case DW_AT_defaulted:
 		return "DW_AT_defaulted"
 	case DW_AT_lo_user:
 		return "DW_AT_lo_user"
+	case DW_AT_GNU_call_site_value:
+		return "DW_AT_GNU_call_site_value"
 	case DW_AT_hi_user:
 		return "DW_AT_hi_user"
 	default:
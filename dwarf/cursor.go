@@ -69,11 +69,11 @@ func (cursor *Cursor) Bytes(size int) ([]byte, error) {
 	content := cursor.remaining()
 	if size < 0 || len(content) < size {
 		return nil, fmt.Errorf(
-			"out of bound slice %d [%d:%d+%d]",
-			len(content),
-			cursor.Position,
+			"out of bound slice at offset %d (need %d bytes, %d available): %w",
 			cursor.Position,
-			size)
+			size,
+			len(content),
+			io.ErrUnexpectedEOF)
 	}
 
 	content = content[:size]
@@ -106,6 +106,17 @@ func (cursor *Cursor) String() (string, error) {
 }
 
 func (cursor *Cursor) decode(out interface{}, name string) error {
+	size := binary.Size(out)
+	if size > len(cursor.remaining()) {
+		return fmt.Errorf(
+			"failed to decode %s at offset %d (need %d bytes, %d available): %w",
+			name,
+			cursor.Position,
+			size,
+			len(cursor.remaining()),
+			io.ErrUnexpectedEOF)
+	}
+
 	n, err := binary.Decode(cursor.remaining(), cursor.ByteOrder, out)
 	if err != nil {
 		return fmt.Errorf(
@@ -111,6 +111,9 @@ func (entry *DebugInfoEntry) Any(attr Attribute) (interface{}, bool) {
 	return entry.Values[idx], true
 }
 
+// NOTE: The typed accessors return false if the attribute is not found, or
+// if the attribute's value is of a different type (i.e., the attribute's
+// format is malformed).
 func (entry *DebugInfoEntry) Address(
 	attr Attribute,
 ) (
@@ -121,7 +124,8 @@ func (entry *DebugInfoEntry) Address(
 	if !ok {
 		return 0, false
 	}
-	return val.(elf.FileAddress), true
+	result, ok := val.(elf.FileAddress)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Offset(attr Attribute) (SectionOffset, bool) {
@@ -129,7 +133,8 @@ func (entry *DebugInfoEntry) Offset(attr Attribute) (SectionOffset, bool) {
 	if !ok {
 		return 0, false
 	}
-	return val.(SectionOffset), true
+	result, ok := val.(SectionOffset)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Bool(attr Attribute) (bool, bool) {
@@ -137,7 +142,8 @@ func (entry *DebugInfoEntry) Bool(attr Attribute) (bool, bool) {
 	if !ok {
 		return false, false
 	}
-	return val.(bool), true
+	result, ok := val.(bool)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Uint(attr Attribute) (uint64, bool) {
@@ -145,7 +151,8 @@ func (entry *DebugInfoEntry) Uint(attr Attribute) (uint64, bool) {
 	if !ok {
 		return 0, false
 	}
-	return val.(uint64), true
+	result, ok := val.(uint64)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Int(attr Attribute) (int64, bool) {
@@ -153,7 +160,8 @@ func (entry *DebugInfoEntry) Int(attr Attribute) (int64, bool) {
 	if !ok {
 		return 0, false
 	}
-	return val.(int64), true
+	result, ok := val.(int64)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Bytes(attr Attribute) ([]byte, bool) {
@@ -161,7 +169,8 @@ func (entry *DebugInfoEntry) Bytes(attr Attribute) ([]byte, bool) {
 	if !ok {
 		return nil, false
 	}
-	return val.([]byte), true
+	result, ok := val.([]byte)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) String(attr Attribute) (string, bool) {
@@ -169,7 +178,8 @@ func (entry *DebugInfoEntry) String(attr Attribute) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	return val.(string), true
+	result, ok := val.(string)
+	return result, ok
 }
 
 func (entry *DebugInfoEntry) Reference(
@@ -182,7 +192,19 @@ func (entry *DebugInfoEntry) Reference(
 	if !ok {
 		return nil, false
 	}
-	return val.(*DebugInfoEntryReference), true
+	result, ok := val.(*DebugInfoEntryReference)
+	return result, ok
+}
+
+// Returns true if the attribute's location is described by a location list
+// (i.e., the location depends on the program counter).
+func (entry *DebugInfoEntry) IsLocationList(attr Attribute) bool {
+	idx := entry.SpecIndex(attr)
+	if idx == -1 {
+		return false
+	}
+
+	return entry.AttributeSpecs[idx].Format == DW_FORM_sec_offset
 }
 
 func (entry *DebugInfoEntry) EvaluateLocation(
@@ -210,17 +232,23 @@ func (entry *DebugInfoEntry) EvaluateLocation(
 			return nil, err
 		}
 
-		addressRanges, err := root.AddressRanges()
-		if err != nil {
-			return nil, err
-		}
-		if len(addressRanges) == 0 {
-			return nil, fmt.Errorf("compile unit has invalid address ranges")
+		// NOTE: the base address is the compile unit's low pc (zero when the
+		// unit uses DW_AT_ranges), not the lowest address of the unit's ranges.
+		baseAddress, ok := root.Address(DW_AT_low_pc)
+		if !ok {
+			addressRanges, err := root.AddressRanges()
+			if err != nil {
+				return nil, err
+			}
+			if len(addressRanges) == 0 {
+				return nil, fmt.Errorf("compile unit has invalid address ranges")
+			}
+			baseAddress = addressRanges[0].Low
 		}
 
 		return entry.CompileUnit.File.LocationSection.EvaluateLocation(
 			value.(SectionOffset),
-			addressRanges[0].Low,
+			baseAddress,
 			context,
 			inFrameInfo)
 	default:
@@ -232,11 +260,55 @@ func (entry *DebugInfoEntry) Name() (
 	string,
 	bool, // false if not found
 	error,
+) {
+	return entry.referencedString(DW_AT_name)
+}
+
+// Returns the entry's mangled (DW_AT_linkage_name) name.
+func (entry *DebugInfoEntry) LinkageName() (
+	string,
+	bool, // false if not found
+	error,
+) {
+	return entry.referencedString(DW_AT_linkage_name)
+}
+
+// Returns the name used by the elf symbol table, i.e., the linkage name if
+// available, otherwise the source name.
+func (entry *DebugInfoEntry) SymbolName() (
+	string,
+	bool, // false if not found
+	error,
+) {
+	linkageName, ok, err := entry.LinkageName()
+	if err != nil || ok {
+		return linkageName, ok, err
+	}
+
+	return entry.Name()
+}
+
+// Returns the string attribute's value, either from the current entry or from
+// the specification / abstract origin entry.
+func (entry *DebugInfoEntry) referencedString(
+	attr Attribute,
+) (
+	string,
+	bool, // false if not found
+	error,
 ) {
 	refIdx := -1
 	for idx, spec := range entry.AttributeSpecs {
-		if spec.Attribute == DW_AT_name {
-			return entry.Values[idx].(string), true, nil
+		if spec.Attribute == attr {
+			value, ok := entry.Values[idx].(string)
+			if !ok {
+				return "", false, fmt.Errorf(
+					"malformed %s attribute (%s) in DIE at offset %d",
+					attr,
+					spec.Format,
+					entry.SectionOffset)
+			}
+			return value, true, nil
 		} else if spec.Attribute == DW_AT_specification {
 			// Current entry is a function declaration. The real definition is in the
 			// referenced entry.
@@ -252,16 +324,50 @@ func (entry *DebugInfoEntry) Name() (
 		return "", false, nil
 	}
 
-	ref := entry.Values[refIdx].(*DebugInfoEntryReference)
+	ref, ok := entry.Values[refIdx].(*DebugInfoEntryReference)
+	if !ok {
+		return "", false, fmt.Errorf(
+			"malformed %s attribute (%s) in DIE at offset %d",
+			entry.AttributeSpecs[refIdx].Attribute,
+			entry.AttributeSpecs[refIdx].Format,
+			entry.SectionOffset)
+	}
+
 	refEntry, err := ref.Get()
 	if err != nil {
 		return "", false, err
 	}
 
-	return refEntry.Name()
+	return refEntry.referencedString(attr)
 }
 
-func (entry *DebugInfoEntry) TypeEntry() (*DebugInfoEntry, error) {
+// Returns true if either the entry's source name or linkage (mangled) name
+// matches.
+func (entry *DebugInfoEntry) matchesNameOrLinkageName(
+	name string,
+) (
+	bool,
+	error,
+) {
+	entryName, ok, err := entry.Name()
+	if err != nil {
+		return false, err
+	}
+	if ok && entryName == name {
+		return true, nil
+	}
+
+	linkageName, ok, err := entry.LinkageName()
+	if err != nil {
+		return false, err
+	}
+
+	return ok && linkageName == name, nil
+}
+
+// Returns the entry which holds the type attribute, following the abstract
+// origin / specification references.
+func (entry *DebugInfoEntry) typeDefinitionEntry() (*DebugInfoEntry, error) {
 	defEntry := entry
 
 	ref, ok := entry.Reference(DW_AT_abstract_origin)
@@ -275,7 +381,40 @@ func (entry *DebugInfoEntry) TypeEntry() (*DebugInfoEntry, error) {
 		}
 	}
 
-	ref, ok = defEntry.Reference(DW_AT_type)
+	if defEntry.SpecIndex(DW_AT_type) == -1 {
+		ref, ok = defEntry.Reference(DW_AT_specification)
+		if ok {
+			// Current entry is an out-of-line definition (e.g., namespaced
+			// global variable / function), the referenced entry is the
+			// declaration.
+			var err error
+			defEntry, err = ref.Get()
+			if err != nil {
+				return nil, fmt.Errorf("cannot get declaration: %w", err)
+			}
+		}
+	}
+
+	return defEntry, nil
+}
+
+// Returns false if the entry has no type (e.g., void function).
+func (entry *DebugInfoEntry) HasTypeEntry() (bool, error) {
+	defEntry, err := entry.typeDefinitionEntry()
+	if err != nil {
+		return false, err
+	}
+
+	return defEntry.SpecIndex(DW_AT_type) != -1, nil
+}
+
+func (entry *DebugInfoEntry) TypeEntry() (*DebugInfoEntry, error) {
+	defEntry, err := entry.typeDefinitionEntry()
+	if err != nil {
+		return nil, err
+	}
+
+	ref, ok := defEntry.Reference(DW_AT_type)
 	if !ok {
 		return nil, fmt.Errorf("type entry not found")
 	}
@@ -300,11 +439,7 @@ func (entry *DebugInfoEntry) FileEntry() (*FileEntry, error) {
 		return nil, fmt.Errorf("compile unit has no line table")
 	}
 
-	if idx == 0 || idx-1 >= uint64(len(entry.lineTable.FileEntries)) {
-		return nil, fmt.Errorf("out of bound line table file index")
-	}
-
-	return entry.lineTable.FileEntries[idx-1], nil
+	return entry.lineTable.FileEntryAt(idx)
 }
 
 func (entry *DebugInfoEntry) Line() (int64, bool) {
@@ -317,6 +452,41 @@ func (entry *DebugInfoEntry) Line() (int64, bool) {
 	return int64(val), ok
 }
 
+// Returns the entry's declaration file and line (DW_AT_decl_file /
+// DW_AT_decl_line).  When the entry itself has no declaration location (e.g.,
+// out-of-line definition), the declaration location is taken from the
+// specification / abstract origin entry.  This returns a nil file entry if
+// the location is unavailable.
+func (entry *DebugInfoEntry) DeclarationLocation() (*FileEntry, int64, error) {
+	if entry.Tag != DW_TAG_inlined_subroutine {
+		fileEntry, err := entry.FileEntry()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if fileEntry != nil {
+			line, _ := entry.Line()
+			return fileEntry, line, nil
+		}
+	}
+
+	for _, attr := range []Attribute{DW_AT_specification, DW_AT_abstract_origin} {
+		ref, ok := entry.Reference(attr)
+		if !ok {
+			continue
+		}
+
+		refEntry, err := ref.Get()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return refEntry.DeclarationLocation()
+	}
+
+	return nil, 0, nil
+}
+
 func (entry *DebugInfoEntry) AddressRanges() (AddressRanges, error) {
 	lowAddr, lowOk := entry.Address(DW_AT_low_pc)
 	high, highOk := entry.Any(DW_AT_high_pc)
@@ -338,7 +508,10 @@ func (entry *DebugInfoEntry) AddressRanges() (AddressRanges, error) {
 				},
 			}, nil
 		default:
-			panic("should never happen")
+			return nil, fmt.Errorf(
+				"unsupported high pc value (%T) for entry at %d",
+				high,
+				entry.SectionOffset)
 		}
 	}
 
@@ -348,7 +521,60 @@ func (entry *DebugInfoEntry) AddressRanges() (AddressRanges, error) {
 		return nil, nil
 	}
 
-	return entry.AddressRangesAt(index, lowAddr)
+	// NOTE: the range list's initial base address is the compile unit's base
+	// address (i.e., the root's low pc), not the entry's low pc.  Base address
+	// selection entries within the list override the initial base address.
+	root, err := entry.CompileUnit.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	baseAddress, _ := root.Address(DW_AT_low_pc)
+
+	if entry.CompileUnit.Version >= 5 {
+		return entry.RangeListAt(index, baseAddress)
+	}
+
+	return entry.AddressRangesAt(index, baseAddress)
+}
+
+// Returns the entry's entry address (i.e., DW_AT_entry_pc, or its lowest
+// address when DW_AT_entry_pc is not specified).  Returns false if the entry
+// has no address.
+func (entry *DebugInfoEntry) EntryAddress() (elf.FileAddress, bool, error) {
+	addressRanges, err := entry.AddressRanges()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(addressRanges) == 0 {
+		return 0, false, nil
+	}
+
+	lowAddr, ok := entry.Address(DW_AT_low_pc)
+	if !ok {
+		lowAddr = addressRanges[0].Low
+		for _, addressRange := range addressRanges[1:] {
+			lowAddr = min(lowAddr, addressRange.Low)
+		}
+	}
+
+	entryPC, ok := entry.Any(DW_AT_entry_pc)
+	if !ok {
+		return lowAddr, true, nil
+	}
+
+	switch val := entryPC.(type) {
+	case elf.FileAddress:
+		return val, true, nil
+	case uint64: // dwarf 5 offset relative to the entry's base address
+		return lowAddr + elf.FileAddress(val), true, nil
+	default:
+		return 0, false, fmt.Errorf(
+			"unsupported entry pc value (%T) for entry at %d",
+			entryPC,
+			entry.SectionOffset)
+	}
 }
 
 func (entry *DebugInfoEntry) ContainsAddress(
@@ -56,6 +56,11 @@ type ExpressionContext interface {
 	ReadMemory(virtualAddress uint64, out []byte) (int, error)
 
 	CanonicalFrameAddress() (uint64, error) // virtual address
+
+	// Returns the register's value at the current function's entry point
+	// (i.e., the value passed in by the caller).  This is used by
+	// DW_OP_entry_value.
+	EntryRegisterValue(id RegisterId) (uint64, error)
 }
 
 func EvaluateExpression(
@@ -67,11 +72,7 @@ func EvaluateExpression(
 	Location,
 	error,
 ) {
-	state := &expressionState{
-		context:     context,
-		Cursor:      NewCursor(context.ByteOrder(), instructions),
-		inFrameInfo: inFrameInfo,
-	}
+	state := newExpressionState(context, inFrameInfo, instructions)
 
 	if initializeStackWithCFA {
 		cfa, err := context.CanonicalFrameAddress()
@@ -81,6 +82,98 @@ func EvaluateExpression(
 		state.push(cfa)
 	}
 
+	return state.evaluate()
+}
+
+// Returns the register id if the expression is a single DW_OP_reg* operation.
+func SingleRegisterExpression(
+	byteOrder binary.ByteOrder,
+	instructions []byte,
+) (
+	RegisterId,
+	bool,
+) {
+	cursor := NewCursor(byteOrder, instructions)
+	_opCode, err := cursor.U8()
+	if err != nil {
+		return 0, false
+	}
+	opCode := Operation(_opCode)
+
+	var regId RegisterId
+	if DW_OP_reg0 <= opCode && opCode <= DW_OP_reg31 {
+		regId = RegisterId(opCode - DW_OP_reg0)
+	} else if opCode == DW_OP_regx {
+		id, err := cursor.ULEB128(64)
+		if err != nil {
+			return 0, false
+		}
+		regId = RegisterId(id)
+	} else {
+		return 0, false
+	}
+
+	if !cursor.HasReachedEnd() {
+		return 0, false
+	}
+
+	return regId, true
+}
+
+// This evaluates a DW_AT_data_member_location expression (e.g., a virtual
+// base class' location), which expects the containing object's address to be
+// pushed onto the stack prior to evaluation.  This returns the member's
+// address.
+func EvaluateDataMemberLocation(
+	context ExpressionContext,
+	instructions []byte,
+	objectAddress uint64,
+) (
+	uint64,
+	error,
+) {
+	state := newExpressionState(context, false, instructions)
+	state.push(objectAddress)
+
+	location, err := state.evaluate()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(location) != 1 || location[0].Kind != AddressLocation {
+		return 0, fmt.Errorf("unsupported data member location")
+	}
+
+	return location[0].Value, nil
+}
+
+type expressionState struct {
+	context ExpressionContext
+	*Cursor
+
+	inFrameInfo bool
+
+	stack               []uint64
+	stackValueIsLiteral bool // false for address
+
+	currentChunk *LocationChunk
+
+	result Location
+}
+
+func newExpressionState(
+	context ExpressionContext,
+	inFrameInfo bool,
+	instructions []byte,
+) *expressionState {
+	return &expressionState{
+		context:     context,
+		Cursor:      NewCursor(context.ByteOrder(), instructions),
+		inFrameInfo: inFrameInfo,
+	}
+}
+
+func (state *expressionState) evaluate() (Location, error) {
 	for !state.HasReachedEnd() {
 		err := state.executeInstruction()
 		if err != nil {
@@ -115,20 +208,6 @@ func EvaluateExpression(
 	return state.result, nil
 }
 
-type expressionState struct {
-	context ExpressionContext
-	*Cursor
-
-	inFrameInfo bool
-
-	stack               []uint64
-	stackValueIsLiteral bool // false for address
-
-	currentChunk *LocationChunk
-
-	result Location
-}
-
 func (state *expressionState) executeInstruction() error {
 	_opCode, err := state.U8()
 	if err != nil {
@@ -217,6 +296,8 @@ func (state *expressionState) executeInstruction() error {
 	case DW_OP_stack_value:
 		state.stackValueIsLiteral = true
 		return nil
+	case DW_OP_entry_value, DW_OP_GNU_entry_value:
+		return state.entryValue()
 	case DW_OP_implicit_value:
 		return state.implicitData()
 	case DW_OP_piece, DW_OP_bit_piece:
@@ -340,7 +421,7 @@ func (state *expressionState) deref(opCode Operation) error {
 		if err != nil {
 			return err
 		}
-		if n > 8 {
+		if n == 0 || n > 8 {
 			return fmt.Errorf("invalid deref size %d", n)
 		}
 
@@ -349,12 +430,22 @@ func (state *expressionState) deref(opCode Operation) error {
 
 	bytes := make([]byte, 8)
 
-	n, err := state.context.ReadMemory(addr, bytes[:size])
+	// NOTE: the value is zero-extended to the full address size.
+	start := 0
+	if state.ByteOrder == binary.BigEndian {
+		start = 8 - size
+	}
+
+	n, err := state.context.ReadMemory(addr, bytes[start:start+size])
 	if err != nil {
 		return err
 	}
 	if n != size {
-		panic("should never happen")
+		return fmt.Errorf(
+			"failed to deref 0x%x. read %d out of %d bytes",
+			addr,
+			n,
+			size)
 	}
 
 	value := uint64(0)
@@ -485,6 +576,36 @@ func (state *expressionState) fbreg() error {
 	return nil
 }
 
+// NOTE: only register entry values (i.e., the sub-expression is a single
+// DW_OP_reg* operation) are supported.  This is what compilers emit for
+// parameters passed in registers.
+func (state *expressionState) entryValue() error {
+	length, err := state.ULEB128(32)
+	if err != nil {
+		return err
+	}
+
+	subExpression, err := state.Bytes(int(length))
+	if err != nil {
+		return err
+	}
+
+	regId, ok := SingleRegisterExpression(
+		state.context.ByteOrder(),
+		subExpression)
+	if !ok {
+		return fmt.Errorf("unsupported entry value expression %v", subExpression)
+	}
+
+	value, err := state.context.EntryRegisterValue(regId)
+	if err != nil {
+		return fmt.Errorf("entry value unavailable: %w", err)
+	}
+
+	state.push(value)
+	return nil
+}
+
 func (state *expressionState) reg(opCode Operation) error {
 	var regId RegisterId
 	if opCode == DW_OP_regx {
@@ -649,6 +770,10 @@ func (state *expressionState) div() error {
 		return err
 	}
 
+	if rhs == 0 {
+		return fmt.Errorf("failed to evaluate DW_OP_div. division by zero")
+	}
+
 	lhs, err := state.pop()
 	if err != nil {
 		return err
@@ -664,6 +789,10 @@ func (state *expressionState) mod() error {
 		return err
 	}
 
+	if rhs == 0 {
+		return fmt.Errorf("failed to evaluate DW_OP_mod. division by zero")
+	}
+
 	lhs, err := state.pop()
 	if err != nil {
 		return err
@@ -11,6 +11,7 @@ var (
 
 	ElfDebugAbbreviationSection = ".debug_abbrev"
 	ElfDebugRangesSection       = ".debug_ranges"
+	ElfDebugRangeListsSection   = ".debug_rnglists"
 	ElfDebugInformationSection  = ".debug_info"
 	ElfDebugLineSection         = ".debug_line"
 	ElfDebugStringSection       = ".debug_str"
@@ -36,21 +37,40 @@ type File struct {
 	// Optional
 	*StringSection
 	*AddressRangesSection
+	*RangeListsSection
 	*LocationSection
 }
 
 func NewFile(elfFile *elf.File) (*File, error) {
-	abbrevSection, err := NewAbbreviationSection(elfFile)
+	return newFile(elfFile, elfFile)
+}
+
+// Parses the debug sections from a separate debug file (located via build id
+// or .gnu_debuglink).  The call frame information, which is stripped from the
+// debug file, is parsed from the elf file.  The resulting file is associated
+// with the elf file (i.e., the file's addresses are the elf file's addresses).
+func NewFileWithSeparateDebugInfo(
+	elfFile *elf.File,
+	debugElfFile *elf.File,
+) (
+	*File,
+	error,
+) {
+	return newFile(elfFile, debugElfFile)
+}
+
+func newFile(elfFile *elf.File, debugElfFile *elf.File) (*File, error) {
+	abbrevSection, err := NewAbbreviationSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
 
-	infoSection, err := NewInformationSection(elfFile)
+	infoSection, err := NewInformationSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
 
-	lineSection, err := NewLineSection(elfFile)
+	lineSection, err := NewLineSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
@@ -60,17 +80,22 @@ func NewFile(elfFile *elf.File) (*File, error) {
 		return nil, err
 	}
 
-	stringSection, err := NewStringSection(elfFile)
+	stringSection, err := NewStringSection(debugElfFile)
+	if err != nil {
+		return nil, err
+	}
+
+	addressRangesSection, err := NewAddressRangesSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
 
-	addressRangesSection, err := NewAddressRangesSection(elfFile)
+	rangeListsSection, err := NewRangeListsSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
 
-	locationSection, err := NewLocationSection(elfFile)
+	locationSection, err := NewLocationSection(debugElfFile)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +108,7 @@ func NewFile(elfFile *elf.File) (*File, error) {
 		FrameSection:         ehFrameSection,
 		StringSection:        stringSection,
 		AddressRangesSection: addressRangesSection,
+		RangeListsSection:    rangeListsSection,
 		LocationSection:      locationSection,
 	}
 	infoSection.SetParent(file)
@@ -66,7 +66,8 @@ func (section *FrameSection) FDEContainingAddress(
 		} else if address == mid.Low {
 			return mid
 		} else {
-			fdes = fdes[midIdx+1:]
+			// NOTE: mid may still contain the address.
+			fdes = fdes[midIdx:]
 		}
 	}
 
@@ -140,9 +141,13 @@ func NewFrameSection(file *elf.File) (*FrameSection, error) {
 	}
 
 	for !parse.HasReachedEnd() {
+		start := parse.Position
 		err := parse.frameEntry()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf(
+				"failed to parse elf .eh_frame section at offset %d: %w",
+				start,
+				err)
 		}
 	}
 
@@ -0,0 +1,55 @@
+package dwarf
+
+import (
+	"testing"
+
+	"github.com/pattyshack/gt/testing/expect"
+	"github.com/pattyshack/gt/testing/suite"
+
+	"github.com/pattyshack/bad/elf"
+)
+
+type FrameSectionSuite struct{}
+
+func TestFrameSection(t *testing.T) {
+	suite.RunTests(t, &FrameSectionSuite{})
+}
+
+func (FrameSectionSuite) newSection(numFdes int) *FrameSection {
+	section := &FrameSection{}
+	for idx := 0; idx < numFdes; idx++ {
+		low := elf.FileAddress(0x1000 + idx*0x100)
+		section.fdes = append(
+			section.fdes,
+			&FrameDescriptionEntry{
+				AddressRange: AddressRange{
+					Low:  low,
+					High: low + 0x80,
+				},
+			})
+	}
+
+	return section
+}
+
+func (s FrameSectionSuite) TestFDEContainingAddress(t *testing.T) {
+	for numFdes := 1; numFdes < 10; numFdes++ {
+		section := s.newSection(numFdes)
+
+		for _, fde := range section.fdes {
+			expect.Equal(t, fde, section.FDEContainingAddress(fde.Low))
+			// NOTE: addresses past the mid fde's low address must still find
+			// the mid fde.
+			expect.Equal(t, fde, section.FDEContainingAddress(fde.Low+1))
+			expect.Equal(t, fde, section.FDEContainingAddress(fde.High-1))
+			expect.Nil(t, section.FDEContainingAddress(fde.High))
+		}
+
+		expect.Nil(t, section.FDEContainingAddress(0xfff))
+	}
+}
+
+func (s FrameSectionSuite) TestFDEContainingAddressEmpty(t *testing.T) {
+	section := s.newSection(0)
+	expect.Nil(t, section.FDEContainingAddress(0x1000))
+}
@@ -1,9 +1,10 @@
 package dwarf
 
 import (
+	"cmp"
 	"fmt"
 	"path"
-	"strings"
+	"slices"
 
 	"github.com/pattyshack/bad/elf"
 )
@@ -18,6 +19,7 @@ type ProcessFunc func(*DebugInfoEntry) error
 
 type CompileUnit struct {
 	*File
+	Version      uint16
 	Start        SectionOffset
 	ContentStart SectionOffset
 	End          SectionOffset
@@ -70,6 +72,11 @@ func parseCompileUnit(
 	}
 
 	addrSize, err := decode.U8()
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse compile unit. invalid address size: %w",
+			err)
+	}
 	if addrSize != 8 {
 		return nil, fmt.Errorf(
 			"failed to parse compile unit. address size %d not supported",
@@ -97,6 +104,7 @@ func parseCompileUnit(
 	}
 
 	return &CompileUnit{
+		Version:           version,
 		Start:             start,
 		ContentStart:      contentStart,
 		End:               SectionOffset(decode.Position),
@@ -118,6 +126,18 @@ func (unit *CompileUnit) Root() (*DebugInfoEntry, error) {
 	return unit.root, nil
 }
 
+// Returns the compile unit's DW_AT_producer (i.e., the compiler's
+// identification string), or empty string if the attribute is absent.
+func (unit *CompileUnit) Producer() (string, error) {
+	root, err := unit.Root()
+	if err != nil {
+		return "", err
+	}
+
+	producer, _ := root.String(DW_AT_producer)
+	return producer, nil
+}
+
 func (unit *CompileUnit) DebugInfoEntries() ([]*DebugInfoEntry, error) {
 	err := unit.maybeParseDebugInfoEntries()
 	if err != nil {
@@ -277,17 +297,41 @@ func (unit *CompileUnit) GetLineEntriesByLine(
 			return result, nil
 		}
 
-		if iter.Line == line {
-			matches := false
-			if path.IsAbs(pathName) {
-				matches = iter.Path() == pathName
-			} else {
-				matches = strings.HasSuffix(iter.Path(), pathName)
-			}
+		if iter.Line == line && iter.matchesPath(pathName) {
+			result = append(result, iter)
+		}
 
-			if matches {
-				result = append(result, iter)
-			}
+		iter, err = iter.Next()
+	}
+}
+
+// Returns the smallest line number greater than the specified line that has
+// line entries, or zero if no such line exists.
+func (unit *CompileUnit) GetNextLineWithEntries(
+	pathName string,
+	line int64,
+) (
+	int64,
+	error,
+) {
+	pathName = path.Clean(pathName)
+	result := int64(0)
+
+	iter, err := unit.LineIterator()
+	for {
+		if err != nil {
+			return 0, err
+		}
+		if iter == nil {
+			return result, nil
+		}
+
+		if !iter.EndSequence &&
+			iter.Line > line &&
+			(result == 0 || iter.Line < result) &&
+			iter.matchesPath(pathName) {
+
+			result = iter.Line
 		}
 
 		iter, err = iter.Next()
@@ -391,9 +435,13 @@ func NewInformationSection(file *elf.File) (*InformationSection, error) {
 
 	decode := NewCursor(file.ByteOrder(), content)
 	for !decode.HasReachedEnd() {
+		start := decode.Position
 		unit, err := parseCompileUnit(decode)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf(
+				"failed to parse elf .debug_info section at offset %d: %w",
+				start,
+				err)
 		}
 
 		units = append(units, unit)
@@ -558,6 +606,77 @@ func (section *InformationSection) GetLineEntriesByLine(
 	return result, nil
 }
 
+func (section *InformationSection) GetNextLineWithEntries(
+	pathName string,
+	line int64,
+) (
+	int64,
+	error,
+) {
+	result := int64(0)
+	for _, unit := range section.CompileUnits {
+		next, err := unit.GetNextLineWithEntries(pathName, line)
+		if err != nil {
+			return 0, err
+		}
+
+		if next != 0 && (result == 0 || next < result) {
+			result = next
+		}
+	}
+	return result, nil
+}
+
+// Returns the inlined subroutine entries (with code) whose call site is at
+// the specified line.
+func (section *InformationSection) InlinedCallSitesByLine(
+	pathName string,
+	line int64,
+) (
+	[]*DebugInfoEntry,
+	error,
+) {
+	pathName = path.Clean(pathName)
+
+	result := []*DebugInfoEntry{}
+	retErr := section.ForEach(
+		func(entry *DebugInfoEntry) error {
+			if entry.Tag != DW_TAG_inlined_subroutine {
+				return nil
+			}
+
+			callLine, ok := entry.Line()
+			if !ok || callLine != line {
+				return nil
+			}
+
+			fileEntry, err := entry.FileEntry()
+			if err != nil {
+				return err
+			}
+			if fileEntry == nil || !fileEntry.matchesPath(pathName) {
+				return nil
+			}
+
+			addrRanges, err := entry.AddressRanges()
+			if err != nil {
+				return err
+			}
+			if len(addrRanges) == 0 {
+				return nil
+			}
+
+			result = append(result, entry)
+			return nil
+		})
+
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return result, nil
+}
+
 func (section *InformationSection) FunctionDefinitionEntriesWithName(
 	name string,
 ) (
@@ -573,11 +692,11 @@ func (section *InformationSection) FunctionDefinitionEntriesWithName(
 				return nil
 			}
 
-			entryName, ok, err := entry.Name()
+			matched, err := entry.matchesNameOrLinkageName(name)
 			if err != nil {
 				return err
 			}
-			if !ok || name != entryName {
+			if !matched {
 				return nil
 			}
 
@@ -602,7 +721,66 @@ func (section *InformationSection) FunctionDefinitionEntriesWithName(
 
 func (section *InformationSection) GlobalVariableEntryWithName(
 	name string,
-) *DebugInfoEntry {
+) (
+	*DebugInfoEntry,
+	error,
+) {
+	var result *DebugInfoEntry
+	var constant *DebugInfoEntry
+	earlyExitErr := fmt.Errorf("early exit")
+	retErr := section.Visit(
+		func(entry *DebugInfoEntry) error {
+			if entry.Tag == DW_TAG_subprogram {
+				return ErrSkipVisitingChildren
+			}
+
+			if !isGlobalVariableEntry(entry) {
+				return nil
+			}
+
+			matched, err := entry.matchesNameOrLinkageName(name)
+			if err != nil {
+				return err
+			}
+
+			if !matched {
+				return nil
+			}
+
+			// NOTE: prefer the definition with storage over the constant
+			// declaration (e.g., odr-used constexpr static data member).
+			if entry.SpecIndex(DW_AT_location) != -1 {
+				result = entry
+				return earlyExitErr
+			}
+
+			if constant == nil {
+				constant = entry
+			}
+
+			return nil
+		},
+		nil)
+
+	if retErr == earlyExitErr {
+		return result, nil
+	}
+
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return constant, nil
+}
+
+// Returns the first (non-declaration) named type definition entry matching
+// the name.
+func (section *InformationSection) TypeEntryWithName(
+	name string,
+) (
+	*DebugInfoEntry,
+	error,
+) {
 	var result *DebugInfoEntry
 	earlyExitErr := fmt.Errorf("early exit")
 	retErr := section.Visit(
@@ -611,11 +789,19 @@ func (section *InformationSection) GlobalVariableEntryWithName(
 				return ErrSkipVisitingChildren
 			}
 
-			if entry.Tag != DW_TAG_variable {
+			switch entry.Tag {
+			case DW_TAG_base_type,
+				DW_TAG_typedef,
+				DW_TAG_class_type,
+				DW_TAG_structure_type,
+				DW_TAG_union_type,
+				DW_TAG_enumeration_type:
+			default:
 				return nil
 			}
 
-			if entry.SpecIndex(DW_AT_location) == -1 { // doesn't have location
+			isDeclaration, ok := entry.Bool(DW_AT_declaration)
+			if ok && isDeclaration {
 				return nil
 			}
 
@@ -634,14 +820,132 @@ func (section *InformationSection) GlobalVariableEntryWithName(
 		nil)
 
 	if retErr == earlyExitErr {
-		return result
+		return result, nil
 	}
 
+	return nil, retErr
+}
+
+// Returns all named function definition entries (with code).  Inlined
+// instances are excluded.
+func (section *InformationSection) FunctionDefinitionEntries() (
+	[]*DebugInfoEntry,
+	error,
+) {
+	result := []*DebugInfoEntry{}
+	retErr := section.ForEach(
+		func(entry *DebugInfoEntry) error {
+			if entry.Tag != DW_TAG_subprogram {
+				return nil
+			}
+
+			_, ok, err := entry.Name()
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+
+			addrRanges, err := entry.AddressRanges()
+			if err != nil {
+				return err
+			}
+			if len(addrRanges) == 0 {
+				return nil
+			}
+
+			result = append(result, entry)
+			return nil
+		})
+
 	if retErr != nil {
-		panic(retErr)
+		return nil, retErr
 	}
 
-	return nil
+	return result, nil
+}
+
+// Returns all named global variable entries (with location or constant
+// value).
+func (section *InformationSection) GlobalVariableEntries() (
+	[]*DebugInfoEntry,
+	error,
+) {
+	result := []*DebugInfoEntry{}
+	retErr := section.Visit(
+		func(entry *DebugInfoEntry) error {
+			if entry.Tag == DW_TAG_subprogram {
+				return ErrSkipVisitingChildren
+			}
+
+			if !isGlobalVariableEntry(entry) {
+				return nil
+			}
+
+			_, ok, err := entry.Name()
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				result = append(result, entry)
+			}
+
+			return nil
+		},
+		nil)
+
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return result, nil
+}
+
+// Returns all (non-declaration) named type definition entries.
+func (section *InformationSection) TypeEntries() ([]*DebugInfoEntry, error) {
+	result := []*DebugInfoEntry{}
+	retErr := section.Visit(
+		func(entry *DebugInfoEntry) error {
+			if entry.Tag == DW_TAG_subprogram {
+				return ErrSkipVisitingChildren
+			}
+
+			switch entry.Tag {
+			case DW_TAG_base_type,
+				DW_TAG_typedef,
+				DW_TAG_class_type,
+				DW_TAG_structure_type,
+				DW_TAG_union_type,
+				DW_TAG_enumeration_type:
+			default:
+				return nil
+			}
+
+			isDeclaration, ok := entry.Bool(DW_AT_declaration)
+			if ok && isDeclaration {
+				return nil
+			}
+
+			_, ok, err := entry.Name()
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				result = append(result, entry)
+			}
+
+			return nil
+		},
+		nil)
+
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	return result, nil
 }
 
 func (section *InformationSection) LocalVariableEntryWithName(
@@ -656,13 +960,28 @@ func (section *InformationSection) LocalVariableEntryWithName(
 		return nil, err
 	}
 
-	return localVariables[name], nil
+	for _, entry := range localVariables {
+		entryName, _, err := entry.Name()
+		if err != nil {
+			return nil, err
+		}
+
+		if entryName == name {
+			return entry, nil
+		}
+	}
+
+	return nil, nil
 }
 
+// Returns the variables / parameters visible at pc, grouped by lexical block
+// with the innermost block first.  Within a block, the entries are ordered by
+// declaration line, falling back to DIE order when the declaration line is
+// unavailable.  Variables shadowed by an inner block's variable are excluded.
 func (section *InformationSection) LocalVariableEntries(
 	pc elf.FileAddress,
 ) (
-	map[string]*DebugInfoEntry,
+	[]*DebugInfoEntry,
 	error,
 ) {
 	funcEntry, err := section.FunctionDefinitionEntryContainingAddress(pc)
@@ -673,8 +992,9 @@ func (section *InformationSection) LocalVariableEntries(
 		return nil, nil
 	}
 
-	result := map[string]*DebugInfoEntry{}
-	funcEntry.Visit(
+	// outermost block first
+	blocks := [][]*DebugInfoEntry{}
+	retErr := funcEntry.Visit(
 		func(entry *DebugInfoEntry) error {
 			ranges, err := entry.AddressRanges()
 			if err != nil {
@@ -684,28 +1004,92 @@ func (section *InformationSection) LocalVariableEntries(
 				return ErrSkipVisitingChildren
 			}
 
+			block := []*DebugInfoEntry{}
 			for _, child := range entry.Children {
 				if child.Tag == DW_TAG_variable ||
 					child.Tag == DW_TAG_formal_parameter {
 
-					name, ok, err := child.Name()
+					_, ok, err := child.Name()
 					if err != nil {
 						return err
 					}
 
 					if ok {
-						result[name] = child
+						block = append(block, child)
 					}
 				}
 			}
 
+			if len(block) > 0 {
+				blocks = append(blocks, block)
+			}
 			return nil
 		},
 		nil)
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	result := []*DebugInfoEntry{}
+	visible := map[string]struct{}{}
+	for idx := len(blocks) - 1; idx >= 0; idx-- {
+		block, err := sortByDeclarationLine(blocks[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range block {
+			name, _, err := entry.Name()
+			if err != nil {
+				return nil, err
+			}
+
+			_, ok := visible[name]
+			if ok { // shadowed by an inner block's variable
+				continue
+			}
+
+			visible[name] = struct{}{}
+			result = append(result, entry)
+		}
+	}
 
 	return result, nil
 }
 
+// NOTE: entries without declaration line are kept in DIE order relative to
+// the entries preceding them.
+func sortByDeclarationLine(
+	entries []*DebugInfoEntry,
+) (
+	[]*DebugInfoEntry,
+	error,
+) {
+	lines := make(map[*DebugInfoEntry]int64, len(entries))
+	prevLine := int64(0)
+	for _, entry := range entries {
+		fileEntry, line, err := entry.DeclarationLocation()
+		if err != nil {
+			return nil, err
+		}
+
+		if fileEntry == nil || line == 0 {
+			line = prevLine
+		}
+
+		lines[entry] = line
+		prevLine = line
+	}
+
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(
+		sorted,
+		func(a *DebugInfoEntry, b *DebugInfoEntry) int {
+			return cmp.Compare(lines[a], lines[b])
+		})
+	return sorted, nil
+}
+
 func (section *InformationSection) VariableEntryWithName(
 	pc elf.FileAddress,
 	name string,
@@ -721,5 +1105,22 @@ func (section *InformationSection) VariableEntryWithName(
 		return entry, nil
 	}
 
-	return section.GlobalVariableEntryWithName(name), nil
+	return section.GlobalVariableEntryWithName(name)
+}
+
+// Global variables either have storage (DW_AT_location), or are constants
+// whose values are folded into DW_AT_const_value (e.g., constexpr variables
+// and static const data members declared in header files, which may not have
+// any storage).  Note that gcc (dwarf4) declares static data members as
+// DW_TAG_member rather than DW_TAG_variable.
+func isGlobalVariableEntry(entry *DebugInfoEntry) bool {
+	switch entry.Tag {
+	case DW_TAG_variable:
+		return entry.SpecIndex(DW_AT_location) != -1 ||
+			entry.SpecIndex(DW_AT_const_value) != -1
+	case DW_TAG_member:
+		return entry.SpecIndex(DW_AT_const_value) != -1
+	default:
+		return false
+	}
 }
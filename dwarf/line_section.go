@@ -50,9 +50,13 @@ func NewLineSection(file *elf.File) (*LineSection, error) {
 
 	decode := NewCursor(file.ByteOrder(), content)
 	for !decode.HasReachedEnd() {
+		start := decode.Position
 		table, err := parseLineTable(decode)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf(
+				"failed to parse elf .debug_line section at offset %d: %w",
+				start,
+				err)
 		}
 
 		tables[table.SectionOffset] = table
@@ -86,6 +90,8 @@ type LineTable struct {
 
 	SectionOffset
 
+	Version uint16
+
 	DefaultIsStatement bool
 	LineBase           int8
 	LineRange          uint8
@@ -111,6 +117,12 @@ func parseLineTable(
 	}
 
 	end := decode.Position + int(length)
+	if end > len(decode.Content) {
+		return nil, fmt.Errorf(
+			"truncated line table (need %d bytes, %d available)",
+			length,
+			len(decode.Content)-decode.Position)
+	}
 
 	version, err := decode.U16()
 	if err != nil {
@@ -172,12 +184,15 @@ func parseLineTable(
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode line table line range: %w", err)
 	}
+	if lineRange == 0 {
+		return nil, fmt.Errorf("invalid line table line range (0)")
+	}
 
 	opCodeBase, err := decode.U8()
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode line table op code base: %w", err)
 	}
-	if opCodeBase > 13 {
+	if opCodeBase == 0 || opCodeBase > 13 {
 		return nil, fmt.Errorf("invalid line table op code base (%d)", opCodeBase)
 	}
 
@@ -218,6 +233,7 @@ func parseLineTable(
 	table := &LineTable{
 		byteOrder:           decode.ByteOrder,
 		SectionOffset:       SectionOffset(start),
+		Version:             version,
 		DefaultIsStatement:  defaultIsStatement != 0,
 		LineBase:            lineBase,
 		LineRange:           lineRange,
@@ -252,6 +268,24 @@ func parseLineTable(
 	return table, nil
 }
 
+// Returns the file entry referenced by a line program / debug info entry
+// file index.  NOTE: file indices are 1-based prior to dwarf 5, and 0-based
+// starting from dwarf 5.
+func (table *LineTable) FileEntryAt(index uint64) (*FileEntry, error) {
+	if table.Version < 5 {
+		if index == 0 {
+			return nil, fmt.Errorf("invalid line table file index (0)")
+		}
+		index--
+	}
+
+	if index >= uint64(len(table.FileEntries)) {
+		return nil, fmt.Errorf("out of bound line table file index")
+	}
+
+	return table.FileEntries[index], nil
+}
+
 func (table *LineTable) parseAndAddFileEntry(
 	decode *Cursor,
 	expectsTerminalMarker bool,
@@ -367,6 +401,14 @@ func (entry *LineEntry) String() string {
 	return fmt.Sprintf("%s:%d:%d", entry.Path(), entry.Line, entry.Column)
 }
 
+// NOTE: relative path name matches any path with the same suffix.
+func (entry *FileEntry) matchesPath(pathName string) bool {
+	if path.IsAbs(pathName) {
+		return entry.Path() == pathName
+	}
+	return strings.HasSuffix(entry.Path(), pathName)
+}
+
 func newLineIterator(table *LineTable, cursor *Cursor) (*LineEntry, error) {
 	entry := &LineEntry{
 		table:        table,
@@ -429,12 +471,12 @@ func (entry *LineEntry) advance() (*LineEntry, error) {
 		}
 
 		if shouldEmitted {
-			idx := entry.FileIndex - 1
-			if idx >= uint64(len(entry.table.FileEntries)) {
-				return nil, fmt.Errorf("out of bound line entry file index")
+			fileEntry, err := entry.table.FileEntryAt(entry.FileIndex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid line entry: %w", err)
 			}
 
-			entry.FileEntry = entry.table.FileEntries[idx]
+			entry.FileEntry = fileEntry
 			return entry, nil
 		}
 	}
@@ -164,7 +164,9 @@ const (
 	DW_OP_bit_piece           = Operation(0x9d)
 	DW_OP_implicit_value      = Operation(0x9e)
 	DW_OP_stack_value         = Operation(0x9f)
+	DW_OP_entry_value         = Operation(0xa3) // dwarf5
 	DW_OP_lo_user             = Operation(0xe0)
+	DW_OP_GNU_entry_value     = Operation(0xf3) // dwarf4 gnu extension
 	DW_OP_hi_user             = Operation(0xff)
 )
 
@@ -478,6 +480,10 @@ func (operation Operation) String() string {
 		return "DW_OP_implicit_value"
 	case DW_OP_stack_value:
 		return "DW_OP_stack_value"
+	case DW_OP_entry_value:
+		return "DW_OP_entry_value"
+	case DW_OP_GNU_entry_value:
+		return "DW_OP_GNU_entry_value"
 	case DW_OP_lo_user:
 		return "DW_OP_lo_user"
 	case DW_OP_hi_user:
@@ -0,0 +1,167 @@
+package dwarf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/pattyshack/bad/elf"
+)
+
+// The dwarf5 .debug_rnglists section.  Unlike dwarf4's .debug_ranges (see
+// AddressRangesSection), each range list entry is prefixed by a DW_RLE_* kind
+// byte, and base address changes are explicit DW_RLE_base_address entries.
+//
+// NOTE: entries which index into .debug_addr (DW_RLE_base_addressx,
+// DW_RLE_startx_endx and DW_RLE_startx_length) are not supported.
+type RangeListsSection struct {
+	byteOrder binary.ByteOrder
+	found     bool
+	content   []byte
+}
+
+func NewRangeListsSectionFromBytes(
+	byteOrder binary.ByteOrder,
+	content []byte,
+) *RangeListsSection {
+	return &RangeListsSection{
+		byteOrder: byteOrder,
+		found:     true,
+		content:   content,
+	}
+}
+
+func NewRangeListsSection(file *elf.File) (*RangeListsSection, error) {
+	section := file.GetSection(ElfDebugRangeListsSection)
+
+	var content []byte
+	if section != nil {
+		var err error
+		content, err = section.RawContent()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to read elf .debug_rnglists section: %w",
+				err)
+		}
+	}
+
+	return &RangeListsSection{
+		byteOrder: file.ByteOrder(),
+		found:     section != nil,
+		content:   content,
+	}, nil
+}
+
+// The base address is the compile unit's base address.  The base address
+// applies to DW_RLE_offset_pair entries until it is replaced by a
+// DW_RLE_base_address entry.
+func (section *RangeListsSection) RangeListAt(
+	index SectionOffset,
+	baseAddress elf.FileAddress,
+) (
+	AddressRanges,
+	error,
+) {
+	if !section.found {
+		return nil, fmt.Errorf("elf .debug_rnglists section not found")
+	}
+
+	decode := NewCursor(section.byteOrder, section.content)
+	_, err := decode.Seek(int(index), io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range list index (%d): %w", index, err)
+	}
+
+	result := AddressRanges{}
+	for !decode.HasReachedEnd() {
+		kind, err := decode.U8()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse range list. cannot decode entry kind: %w",
+				err)
+		}
+
+		switch kind {
+		case DW_RLE_end_of_list:
+			return result, nil
+		case DW_RLE_base_address:
+			address, err := decode.U64()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode base address: %w",
+					err)
+			}
+
+			baseAddress = elf.FileAddress(address)
+		case DW_RLE_offset_pair:
+			low, err := decode.ULEB128(64)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode start offset: %w",
+					err)
+			}
+
+			high, err := decode.ULEB128(64)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode end offset: %w",
+					err)
+			}
+
+			result = append(
+				result,
+				AddressRange{
+					Low:  baseAddress + elf.FileAddress(low),
+					High: baseAddress + elf.FileAddress(high),
+				})
+		case DW_RLE_start_end:
+			low, err := decode.U64()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode start: %w",
+					err)
+			}
+
+			high, err := decode.U64()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode end: %w",
+					err)
+			}
+
+			result = append(
+				result,
+				AddressRange{
+					Low:  elf.FileAddress(low),
+					High: elf.FileAddress(high),
+				})
+		case DW_RLE_start_length:
+			low, err := decode.U64()
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode start: %w",
+					err)
+			}
+
+			length, err := decode.ULEB128(64)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to parse range list. cannot decode length: %w",
+					err)
+			}
+
+			result = append(
+				result,
+				AddressRange{
+					Low:  elf.FileAddress(low),
+					High: elf.FileAddress(low + length),
+				})
+		default:
+			return nil, fmt.Errorf(
+				"failed to parse range list. unsupported entry kind (0x%x)",
+				kind)
+		}
+	}
+
+	return nil, fmt.Errorf("range list (%d) not terminated", index)
+}
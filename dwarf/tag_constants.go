@@ -70,7 +70,13 @@ const (
 	DW_TAG_type_unit                = Tag(0x41)
 	DW_TAG_rvalue_reference_type    = Tag(0x42)
 	DW_TAG_template_alias           = Tag(0x43)
+	DW_TAG_atomic_type              = Tag(0x47) // dwarf5
+	DW_TAG_call_site                = Tag(0x48) // dwarf5
+	DW_TAG_call_site_parameter      = Tag(0x49) // dwarf5
+	DW_TAG_immutable_type           = Tag(0x4b) // dwarf5
 	DW_TAG_lo_user                  = Tag(0x4080)
+	DW_TAG_GNU_call_site            = Tag(0x4109) // dwarf4 gnu extension
+	DW_TAG_GNU_call_site_parameter  = Tag(0x410a) // dwarf4 gnu extension
 	DW_TAG_hi_user                  = Tag(0xffff)
 )
 
@@ -196,8 +202,20 @@ func (tag Tag) String() string {
 		return "DW_TAG_rvalue_reference_type"
 	case DW_TAG_template_alias:
 		return "DW_TAG_template_alias"
+	case DW_TAG_atomic_type:
+		return "DW_TAG_atomic_type"
+	case DW_TAG_call_site:
+		return "DW_TAG_call_site"
+	case DW_TAG_call_site_parameter:
+		return "DW_TAG_call_site_parameter"
+	case DW_TAG_immutable_type:
+		return "DW_TAG_immutable_type"
 	case DW_TAG_lo_user:
 		return "DW_TAG_lo_user"
+	case DW_TAG_GNU_call_site:
+		return "DW_TAG_GNU_call_site"
+	case DW_TAG_GNU_call_site_parameter:
+		return "DW_TAG_GNU_call_site_parameter"
 	case DW_TAG_hi_user:
 		return "DW_TAG_hi_user"
 	default:
@@ -47,6 +47,88 @@ func (file *File) GetSection(name string) Section {
 	return nil
 }
 
+// Returns all note sections (e.g., .note.ABI-tag, .note.gnu.build-id).
+func (file *File) NoteSections() []*NoteSection {
+	result := []*NoteSection{}
+	for _, section := range file.Sections {
+		note, ok := section.(*NoteSection)
+		if ok {
+			result = append(result, note)
+		}
+	}
+
+	return result
+}
+
+// Returns the first GNU note entry of the given type, searched across all
+// note sections.
+func (file *File) GNUNote(noteType uint32) (NoteEntry, bool) {
+	for _, section := range file.NoteSections() {
+		for _, entry := range section.Entries {
+			if entry.OwnerName() == "GNU" && entry.Type == noteType {
+				return entry, true
+			}
+		}
+	}
+
+	return NoteEntry{}, false
+}
+
+// Returns nil if the file does not have an abi tag note.
+func (file *File) ABITag() (*ABITag, error) {
+	entry, ok := file.GNUNote(NoteTypeGNUABITag)
+	if !ok {
+		return nil, nil
+	}
+
+	return parseABITag(entry, file.DataEncoding.ByteOrder())
+}
+
+// Returns the hex encoded build id, or empty string if the file does not
+// have a build id note.
+func (file *File) BuildId() string {
+	entry, ok := file.GNUNote(NoteTypeGNUBuildId)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", entry.Description)
+}
+
+// Returns the unique compiler / linker version strings in the .comment
+// section, in order of appearance.  This returns nil if the file does not
+// have a .comment section.
+func (file *File) Comments() ([]string, error) {
+	section := file.GetSection(".comment")
+	if section == nil {
+		return nil, nil
+	}
+
+	content, err := section.RawContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read elf .comment section: %w", err)
+	}
+
+	result := []string{}
+	seen := map[string]struct{}{}
+	for _, chunk := range bytes.Split(content, []byte{0}) {
+		comment := string(chunk)
+		if comment == "" {
+			continue
+		}
+
+		_, ok := seen[comment]
+		if ok {
+			continue
+		}
+		seen[comment] = struct{}{}
+
+		result = append(result, comment)
+	}
+
+	return result, nil
+}
+
 type parser struct {
 	content []byte
 
@@ -109,6 +191,13 @@ func (p *parser) parse() error {
 func (p *parser) parseIdentifier() error {
 	id := &Identifier{}
 
+	if len(p.content) < ElfIdentifierSize {
+		return fmt.Errorf(
+			"failed to parse identifier. truncated (need %d bytes, %d available)",
+			ElfIdentifierSize,
+			len(p.content))
+	}
+
 	n, err := binary.Decode(p.content, binary.NativeEndian, id)
 	if err != nil {
 		return fmt.Errorf("failed to parse identifier: %w", err)
@@ -161,6 +250,13 @@ func (p *parser) parseIdentifier() error {
 }
 
 func (p *parser) parseHeader() error {
+	if len(p.content) < Elf64HeaderSize {
+		return fmt.Errorf(
+			"failed to parse header. truncated (need %d bytes, %d available)",
+			Elf64HeaderSize,
+			len(p.content))
+	}
+
 	n, err := binary.Decode(p.content, p.ByteOrder, &p.ElfHeader)
 	if err != nil {
 		return fmt.Errorf("failed to parse header: %w", err)
@@ -238,6 +334,17 @@ func (p *parser) parseSectionHeaders() error {
 			p.SectionHeaderOffset)
 	}
 
+	tableSize := uint64(p.NumSectionHeaderEntries) * Elf64SectionHeaderEntrySize
+	if tableSize > uint64(len(p.content))-p.SectionHeaderOffset {
+		return fmt.Errorf(
+			"truncated section header table "+
+				"(%d entries at offset %d, need %d bytes, %d available)",
+			p.NumSectionHeaderEntries,
+			p.SectionHeaderOffset,
+			tableSize,
+			uint64(len(p.content))-p.SectionHeaderOffset)
+	}
+
 	sectionHeaders := make([]SectionHeaderEntry, p.NumSectionHeaderEntries)
 	n, err := binary.Decode(
 		p.content[p.SectionHeaderOffset:],
@@ -250,13 +357,20 @@ func (p *parser) parseSectionHeaders() error {
 		panic("should never happen")
 	}
 
-	for _, header := range sectionHeaders {
+	for idx, header := range sectionHeaders {
 		var sectionContent []byte
 		if header.SectionType != SectionTypeNoSpace {
 			start := header.Offset
 			end := start + header.Size
-			if end > uint64(len(p.content)) {
-				return fmt.Errorf("out of bound section (%d > %d)", end, len(p.content))
+			if start > uint64(len(p.content)) ||
+				header.Size > uint64(len(p.content))-start {
+
+				return fmt.Errorf(
+					"out of bound section %d (offset: %d, size: %d, file size: %d)",
+					idx,
+					start,
+					header.Size,
+					len(p.content))
 			}
 
 			sectionContent = p.content[start:end]
@@ -292,9 +406,9 @@ func (p *parser) parseSectionHeaders() error {
 	// Bind section names
 	if p.SectionStringTableIndex != SectionIndexUndefined {
 		idx := int(p.SectionStringTableIndex)
-		if idx > len(p.Sections) {
+		if idx >= len(p.Sections) {
 			return fmt.Errorf(
-				"section name index out of bound (%d > %d)",
+				"section name index out of bound (%d >= %d)",
 				idx,
 				len(p.Sections))
 		}
@@ -322,9 +436,9 @@ func (p *parser) parseSectionHeaders() error {
 		case SectionTypeDynamic,
 			SectionTypeSymbolTable,
 			SectionTypeDynamicSymbolTable:
-			if hdr.Link > uint32(len(p.Sections)) {
+			if hdr.Link >= uint32(len(p.Sections)) {
 				return fmt.Errorf(
-					"string table index out of bound (%d > %d)",
+					"string table index out of bound (%d >= %d)",
 					hdr.Link,
 					len(p.Sections))
 			}
@@ -339,9 +453,9 @@ func (p *parser) parseSectionHeaders() error {
 			SectionTypeRelocationWithAddends,
 			SectionTypeRelocationNoAddends:
 
-			if hdr.Link > uint32(len(p.Sections)) {
+			if hdr.Link >= uint32(len(p.Sections)) {
 				return fmt.Errorf(
-					"symbol table index out of bound (%d > %d)",
+					"symbol table index out of bound (%d >= %d)",
 					hdr.Link,
 					len(p.Sections))
 			}
@@ -368,9 +482,9 @@ func (p *parser) parseSectionHeaders() error {
 
 		switch hdr.SectionType {
 		case SectionTypeRelocationWithAddends, SectionTypeRelocationNoAddends:
-			if hdr.Info > uint32(len(p.Sections)) {
+			if hdr.Info >= uint32(len(p.Sections)) {
 				return fmt.Errorf(
-					"relocations index out of bound (%d > %d)",
+					"relocations index out of bound (%d >= %d)",
 					hdr.Info,
 					len(p.Sections))
 			}
@@ -438,6 +552,17 @@ func (p *parser) parseProgramHeaders() error {
 			p.ProgramHeaderOffset)
 	}
 
+	tableSize := uint64(p.NumProgramHeaderEntries) * Elf64ProgramHeaderEntrySize
+	if tableSize > uint64(len(p.content))-p.ProgramHeaderOffset {
+		return fmt.Errorf(
+			"truncated program header table "+
+				"(%d entries at offset %d, need %d bytes, %d available)",
+			p.NumProgramHeaderEntries,
+			p.ProgramHeaderOffset,
+			tableSize,
+			uint64(len(p.content))-p.ProgramHeaderOffset)
+	}
+
 	programHeaders := make([]ProgramHeaderEntry, p.NumProgramHeaderEntries)
 	n, err := binary.Decode(
 		p.content[p.ProgramHeaderOffset:],
@@ -470,6 +595,13 @@ func (p *parser) parseNote(
 			return nil, fmt.Errorf("failed to parse note section. not 4-byte aligned")
 		}
 
+		if len(content) < NoteHeaderSize {
+			return nil, fmt.Errorf(
+				"truncated note header (need %d bytes, %d available)",
+				NoteHeaderSize,
+				len(content))
+		}
+
 		noteHdr := &NoteHeader{}
 		n, err := binary.Decode(content, p.ByteOrder, noteHdr)
 		if err != nil {
@@ -491,7 +623,7 @@ func (p *parser) parseNote(
 		name := string(content[:noteHdr.NameSize])
 
 		// make descStart 4 byte aligned.
-		descStart := ((noteHdr.NameSize + 3) / 4) * 4
+		descStart := ((int(noteHdr.NameSize) + 3) / 4) * 4
 
 		content = content[descStart:]
 
@@ -514,7 +646,7 @@ func (p *parser) parseNote(
 			})
 
 		// make nextEntryStart 4 byte aligned.
-		nextEntryStart := ((noteHdr.DescriptionSize + 3) / 4) * 4
+		nextEntryStart := ((int(noteHdr.DescriptionSize) + 3) / 4) * 4
 		content = content[nextEntryStart:]
 	}
 
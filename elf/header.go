@@ -505,6 +505,11 @@ type NoteHeader struct {
 	Type            uint32
 }
 
+const (
+	NoteTypeGNUABITag  = uint32(1) // NT_GNU_ABI_TAG
+	NoteTypeGNUBuildId = uint32(3) // NT_GNU_BUILD_ID
+)
+
 type DynamicTag int64
 
 // see debug/elf for a more complete list
@@ -2,7 +2,9 @@ package elf
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"strings"
 
 	"github.com/ianlancetaylor/demangle"
 )
@@ -136,6 +138,10 @@ func (table *StringTableSection) Get(index uint32) string {
 }
 
 func (table *StringTableSection) NumEntries() int {
+	if len(table.Content) == 0 {
+		return 0
+	}
+
 	count := 0
 	for _, b := range table.Content[1:] {
 		if b == 0 {
@@ -239,6 +245,60 @@ type NoteEntry struct {
 	Type        uint32
 }
 
+// Returns the note's name without the null terminator.
+func (entry NoteEntry) OwnerName() string {
+	return strings.TrimRight(entry.Name, "\x00")
+}
+
+// The minimum kernel ABI required by the binary (.note.ABI-tag).
+type ABITag struct {
+	OperatingSystem string
+
+	Major    uint32
+	Minor    uint32
+	Subminor uint32
+}
+
+func (tag ABITag) String() string {
+	return fmt.Sprintf(
+		"%s ABI %d.%d.%d",
+		tag.OperatingSystem,
+		tag.Major,
+		tag.Minor,
+		tag.Subminor)
+}
+
+// See NT_GNU_ABI_TAG's description format.
+func parseABITag(entry NoteEntry, byteOrder binary.ByteOrder) (*ABITag, error) {
+	if len(entry.Description) < 16 {
+		return nil, fmt.Errorf(
+			"invalid abi tag description size (%d)",
+			len(entry.Description))
+	}
+
+	desc := []byte(entry.Description)
+
+	osId := byteOrder.Uint32(desc)
+	osName := fmt.Sprintf("unknown os (%d)", osId)
+	switch osId {
+	case 0:
+		osName = "Linux"
+	case 1:
+		osName = "GNU"
+	case 2:
+		osName = "Solaris2"
+	case 3:
+		osName = "FreeBSD"
+	}
+
+	return &ABITag{
+		OperatingSystem: osName,
+		Major:           byteOrder.Uint32(desc[4:]),
+		Minor:           byteOrder.Uint32(desc[8:]),
+		Subminor:        byteOrder.Uint32(desc[12:]),
+	}, nil
+}
+
 type NoteSection struct {
 	BaseSection
 
@@ -1,6 +1,7 @@
 package procfs
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"os"
@@ -287,6 +288,36 @@ func GetMappedMemoryRegions(pid int) ([]MappedMemoryRegion, error) {
 	return result, nil
 }
 
+// Returns the process' command line arguments (argv).  Note that the process
+// may modify its argv after it started (e.g., via prctl).  The result is empty
+// for zombie processes.
+func GetCommandLine(pid int) ([]string, error) {
+	path := fmt.Sprintf("/proc/%d/cmdline", pid)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if len(content) == 0 {
+		return []string{}, nil
+	}
+
+	// Each argument is NUL terminated.  NOTE: the last argument may not be
+	// terminated if the process overwrote its argv.
+	content = bytes.TrimSuffix(content, []byte{0})
+	return strings.Split(string(content), "\x00"), nil
+}
+
+func GetCurrentWorkingDirectory(pid int) (string, error) {
+	path := fmt.Sprintf("/proc/%d/cwd", pid)
+	cwd, err := os.Readlink(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return cwd, nil
+}
+
 func GetExecutableSymlinkPath(pid int) string {
 	return fmt.Sprintf("/proc/%d/exe", pid)
 }
@@ -310,3 +341,34 @@ func ListTasks(pid int) ([]int, error) {
 
 	return result, nil
 }
+
+// Returns the system call number the task (thread) is blocked in (or stopped
+// in).  The bool is false if the task is running, or is not in a system call.
+func GetTaskSyscall(pid int, tid int) (int, bool, error) {
+	path := fmt.Sprintf("/proc/%d/task/%d/syscall", pid, tid)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// The content is either "running", "-1 <sp> <pc>" (not in a system call),
+	// or "<nr> <args>... <sp> <pc>".
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 || fields[0] == "running" {
+		return 0, false, nil
+	}
+
+	nr, err := strconv.ParseInt(fields[0], 10, 32)
+	if err != nil {
+		return 0, false, fmt.Errorf(
+			"failed to parse system call number (%s): %w",
+			fields[0],
+			err)
+	}
+
+	if nr < 0 {
+		return 0, false, nil
+	}
+
+	return int(nr), true, nil
+}
@@ -22,6 +22,28 @@ type Tracer struct {
 }
 
 func StartAndAttachToProcess(cmd *exec.Cmd) (*Tracer, error) {
+	return startAndAttachToProcess(cmd, false)
+}
+
+// Similar to StartAndAttachToProcess, but the process is started with address
+// space layout randomization disabled (ADDR_NO_RANDOMIZE personality), i.e.,
+// the process' addresses are stable across runs.
+func StartAndAttachToProcessWithoutRandomization(
+	cmd *exec.Cmd,
+) (
+	*Tracer,
+	error,
+) {
+	return startAndAttachToProcess(cmd, true)
+}
+
+func startAndAttachToProcess(
+	cmd *exec.Cmd,
+	disableRandomization bool,
+) (
+	*Tracer,
+	error,
+) {
 	if cmd.SysProcAttr == nil {
 		cmd.SysProcAttr = &syscall.SysProcAttr{}
 	}
@@ -40,8 +62,9 @@ func StartAndAttachToProcess(cmd *exec.Cmd) (*Tracer, error) {
 	}
 
 	_, err := tracer.send(request{
-		opType: startOp,
-		cmd:    cmd,
+		opType:               startOp,
+		cmd:                  cmd,
+		disableRandomization: disableRandomization,
 	})
 	if err != nil {
 		close(server.requestChan) // shutdown server
@@ -89,6 +112,17 @@ func (tracer *Tracer) TraceThread(tid int) *Tracer {
 	}
 }
 
+// Returns a tracer for the process forked by the tracer's process, which was
+// automatically attached via O_TRACEFORK.  The forked process is traced by the
+// same os thread as the tracer's process; hence, the returned tracer shares
+// the tracer's server.
+func (tracer *Tracer) TraceForkedProcess(pid int) *Tracer {
+	return &Tracer{
+		Pid:    pid,
+		server: tracer.server,
+	}
+}
+
 func (tracer *Tracer) send(req request) (response, error) {
 	respChan := make(chan response, 1)
 	req.pid = tracer.Pid
@@ -182,6 +216,30 @@ func (tracer *Tracer) SetFloatingPointRegisters(in *UserFPRegs) error {
 	return err
 }
 
+// Reads the raw register set (PTRACE_GETREGSET) into out, and returns the
+// number of bytes read.  The read is truncated to the regset's size.
+func (tracer *Tracer) GetRegset(regset Regset, out []byte) (int, error) {
+	resp, err := tracer.send(request{
+		opType: getRegsetOp,
+		regset: regset,
+		data:   out,
+	})
+
+	return resp.count, err
+}
+
+// Writes the raw register set (PTRACE_SETREGSET) from in, and returns the
+// number of bytes written.  The write is truncated to the regset's size.
+func (tracer *Tracer) SetRegset(regset Regset, in []byte) (int, error) {
+	resp, err := tracer.send(request{
+		opType: setRegsetOp,
+		regset: regset,
+		data:   in,
+	})
+
+	return resp.count, err
+}
+
 func (tracer *Tracer) PeekUserArea(offset uintptr) (uintptr, error) {
 	resp, err := tracer.send(request{
 		opType: peekUserOp,
@@ -256,3 +314,12 @@ func (tracer *Tracer) GetSigInfo() (*SigInfo, error) {
 	})
 	return resp.sigInfo, err
 }
+
+// The returned message's meaning depends on the ptrace event.  For
+// EVENT_EXIT, the message is the thread's exit (wait) status.
+func (tracer *Tracer) GetEventMsg() (uint, error) {
+	resp, err := tracer.send(request{
+		opType: getEventMsgOp,
+	})
+	return resp.eventMsg, err
+}
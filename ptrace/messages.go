@@ -18,18 +18,23 @@ const (
 	setRegsOp    = opType("setRegs")
 	getFPRegsOp  = opType("getFPRegs")
 	setFPRegsOp  = opType("setFPRegs")
+	getRegsetOp  = opType("getRegset")
+	setRegsetOp  = opType("setRegset")
 	peekUserOp   = opType("peekUser")
 	pokeUserOp   = opType("pokeUser")
 	peekDataOp   = opType("peekData")
 	pokeDataOp   = opType("pokeData")
 	readMemoryOp = opType("readMemory")
 	getSigInfoOp = opType("getSigInfo")
+
+	getEventMsgOp = opType("getEventMsg")
 )
 
 type request struct {
 	opType
 
-	cmd *exec.Cmd // only used by start
+	cmd                  *exec.Cmd // only used by start
+	disableRandomization bool      // only used by start
 
 	pid int // used by all except start
 
@@ -41,11 +46,13 @@ type request struct {
 
 	fpRegs *UserFPRegs // get/set fp regs
 
+	regset Regset // get/set regset
+
 	offset       uintptr // peek/poke user area
 	registerData uintptr // poke user area
 
 	addr uintptr // peek/poke data
-	data []byte  // peek/poke data
+	data []byte  // peek/poke data, get/set regset
 
 	responseChan chan response
 }
@@ -53,9 +60,11 @@ type request struct {
 type response struct {
 	registerData uintptr // peek user area
 
-	count int // peek/poke data
+	count int // peek/poke data, get/set regset
 
 	sigInfo *SigInfo // get sig info
 
+	eventMsg uint // get event msg
+
 	err error
 }
@@ -0,0 +1,352 @@
+package ptracetest
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/pattyshack/bad/ptrace"
+)
+
+// A memory region mapped into the fake tracee's address space.
+type FakeMemoryRegion struct {
+	Address uintptr
+	Data    []byte
+}
+
+// A deterministic in-memory tracer for unit tests.  The tracee is modelled by
+// a register file (general / floating point registers and the user area's
+// debug registers) and a set of mapped memory regions.  Execution control
+// operations (resume / single step / detach) are recorded in Operations, and
+// may optionally simulate execution via the OnResume / OnSingleStep hooks.
+//
+// FakeTracer implements the same methods as Tracer (except for process
+// start / attach and thread tracing).
+type FakeTracer struct {
+	Pid int
+
+	Regs     ptrace.UserRegs
+	FPRegs   ptrace.UserFPRegs
+	UserArea map[uintptr]uintptr // offset -> value
+
+	// Raw register sets, keyed by note type.  The content is independent of
+	// Regs / FPRegs.
+	Regsets map[ptrace.Regset][]byte
+
+	Memory []*FakeMemoryRegion
+
+	Options  ptrace.Options
+	SigInfo  ptrace.SigInfo
+	EventMsg uint
+
+	// Recorded execution control operations, in call order (e.g.,
+	// "resume 0", "syscall resume 5", "single step", "detach").
+	Operations []string
+
+	// Optional execution simulation hooks.  The returned error is returned by
+	// the corresponding operation.
+	OnResume     func(tracer *FakeTracer, signal int) error
+	OnSingleStep func(tracer *FakeTracer) error
+
+	Detached bool
+}
+
+func NewFakeTracer(pid int) *FakeTracer {
+	return &FakeTracer{
+		Pid:      pid,
+		UserArea: map[uintptr]uintptr{},
+		Regsets:  map[ptrace.Regset][]byte{},
+	}
+}
+
+// Maps a copy of the data into the fake tracee's address space.
+func (tracer *FakeTracer) MapMemory(addr uintptr, data []byte) {
+	tracer.Memory = append(
+		tracer.Memory,
+		&FakeMemoryRegion{
+			Address: addr,
+			Data:    append([]byte{}, data...),
+		})
+}
+
+func (tracer *FakeTracer) region(addr uintptr) (*FakeMemoryRegion, int) {
+	for _, region := range tracer.Memory {
+		if region.Address <= addr &&
+			addr < region.Address+uintptr(len(region.Data)) {
+
+			return region, int(addr - region.Address)
+		}
+	}
+
+	return nil, 0
+}
+
+func (tracer *FakeTracer) checkAttached() error {
+	if tracer.Detached {
+		return fmt.Errorf(
+			"invalid operation. tracer has detached from process %d",
+			tracer.Pid)
+	}
+	return nil
+}
+
+func (tracer *FakeTracer) Close() error {
+	if tracer.Detached {
+		return nil
+	}
+	return tracer.Detach()
+}
+
+func (tracer *FakeTracer) Detach() error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Operations = append(tracer.Operations, "detach")
+	tracer.Detached = true
+	return nil
+}
+
+func (tracer *FakeTracer) Resume(signal int) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Operations = append(
+		tracer.Operations,
+		fmt.Sprintf("resume %d", signal))
+
+	if tracer.OnResume != nil {
+		return tracer.OnResume(tracer, signal)
+	}
+	return nil
+}
+
+func (tracer *FakeTracer) SyscallTrappedResume(signal int) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Operations = append(
+		tracer.Operations,
+		fmt.Sprintf("syscall resume %d", signal))
+
+	if tracer.OnResume != nil {
+		return tracer.OnResume(tracer, signal)
+	}
+	return nil
+}
+
+func (tracer *FakeTracer) SingleStep() error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Operations = append(tracer.Operations, "single step")
+
+	if tracer.OnSingleStep != nil {
+		return tracer.OnSingleStep(tracer)
+	}
+	return nil
+}
+
+func (tracer *FakeTracer) SetOptions(options ptrace.Options) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Options = options
+	return nil
+}
+
+func (tracer *FakeTracer) GetGeneralRegisters() (*ptrace.UserRegs, error) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return nil, err
+	}
+
+	out := tracer.Regs
+	return &out, nil
+}
+
+func (tracer *FakeTracer) SetGeneralRegisters(in *ptrace.UserRegs) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.Regs = *in
+	return nil
+}
+
+func (tracer *FakeTracer) GetFloatingPointRegisters() (
+	*ptrace.UserFPRegs,
+	error,
+) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return nil, err
+	}
+
+	out := tracer.FPRegs
+	return &out, nil
+}
+
+func (tracer *FakeTracer) SetFloatingPointRegisters(
+	in *ptrace.UserFPRegs,
+) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.FPRegs = *in
+	return nil
+}
+
+// Similar to PTRACE_GETREGSET, the read is truncated to the regset's size, and
+// reading an unknown regset is an error.
+func (tracer *FakeTracer) GetRegset(
+	regset ptrace.Regset,
+	out []byte,
+) (
+	int,
+	error,
+) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	content, ok := tracer.Regsets[regset]
+	if !ok {
+		return 0, syscall.EINVAL
+	}
+
+	return copy(out, content), nil
+}
+
+// Similar to PTRACE_SETREGSET, the write is truncated to the regset's size,
+// and writing an unknown regset is an error.
+func (tracer *FakeTracer) SetRegset(
+	regset ptrace.Regset,
+	in []byte,
+) (
+	int,
+	error,
+) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	content, ok := tracer.Regsets[regset]
+	if !ok {
+		return 0, syscall.EINVAL
+	}
+
+	return copy(content, in), nil
+}
+
+func (tracer *FakeTracer) PeekUserArea(offset uintptr) (uintptr, error) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	return tracer.UserArea[offset], nil
+}
+
+func (tracer *FakeTracer) PokeUserArea(offset uintptr, data uintptr) error {
+	err := tracer.checkAttached()
+	if err != nil {
+		return err
+	}
+
+	tracer.UserArea[offset] = data
+	return nil
+}
+
+// Similar to process_vm_readv, reading past the mapped range is a partial
+// read, and reading from an unmapped address is an error.
+func (tracer *FakeTracer) ReadFromVirtualMemory(
+	addr uintptr,
+	data []byte,
+) (
+	int,
+	error,
+) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for count < len(data) {
+		region, offset := tracer.region(addr + uintptr(count))
+		if region == nil {
+			break
+		}
+
+		count += copy(data[count:], region.Data[offset:])
+	}
+
+	if count == 0 && len(data) > 0 {
+		return 0, syscall.EFAULT
+	}
+
+	return count, nil
+}
+
+func (tracer *FakeTracer) PeekData(addr uintptr, data []byte) (int, error) {
+	return tracer.ReadFromVirtualMemory(addr, data)
+}
+
+// Unlike PTRACE_POKEDATA, the write is byte granular.  Writing past the
+// mapped range is a partial write, and writing to an unmapped address is an
+// error.
+func (tracer *FakeTracer) PokeData(addr uintptr, data []byte) (int, error) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for count < len(data) {
+		region, offset := tracer.region(addr + uintptr(count))
+		if region == nil {
+			break
+		}
+
+		count += copy(region.Data[offset:], data[count:])
+	}
+
+	if count == 0 && len(data) > 0 {
+		return 0, syscall.EIO
+	}
+
+	return count, nil
+}
+
+func (tracer *FakeTracer) GetSigInfo() (*ptrace.SigInfo, error) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return nil, err
+	}
+
+	out := tracer.SigInfo
+	return &out, nil
+}
+
+func (tracer *FakeTracer) GetEventMsg() (uint, error) {
+	err := tracer.checkAttached()
+	if err != nil {
+		return 0, err
+	}
+
+	return tracer.EventMsg, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"runtime"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 type traceServer struct {
@@ -60,6 +62,10 @@ func (server *traceServer) processRequests() {
 			req.responseChan <- server.getFPRegs(req)
 		case setFPRegsOp:
 			req.responseChan <- server.setFPRegs(req)
+		case getRegsetOp:
+			req.responseChan <- server.getRegset(req)
+		case setRegsetOp:
+			req.responseChan <- server.setRegset(req)
 		case peekUserOp:
 			req.responseChan <- server.peekUser(req)
 		case pokeUserOp:
@@ -72,11 +78,44 @@ func (server *traceServer) processRequests() {
 			req.responseChan <- server.readMemory(req)
 		case getSigInfoOp:
 			req.responseChan <- server.getSigInfo(req)
+		case getEventMsgOp:
+			req.responseChan <- server.getEventMsg(req)
 		}
 	}
 }
 
 func (server *traceServer) start(req request) response {
+	if req.disableRandomization {
+		// NOTE: the personality is per-thread, and is inherited by the forked
+		// child process (and preserved across execve).  The server's thread
+		// personality is restored once the child has started.
+		persona, _, errno := syscall.RawSyscall(
+			unix.SYS_PERSONALITY,
+			queryPersonality,
+			0,
+			0)
+		if errno != 0 {
+			return response{
+				err: fmt.Errorf("failed to query personality: %w", errno),
+			}
+		}
+
+		_, _, errno = syscall.RawSyscall(
+			unix.SYS_PERSONALITY,
+			persona|addrNoRandomize,
+			0,
+			0)
+		if errno != 0 {
+			return response{
+				err: fmt.Errorf(
+					"failed to disable address space randomization: %w",
+					errno),
+			}
+		}
+
+		defer syscall.RawSyscall(unix.SYS_PERSONALITY, persona, 0, 0)
+	}
+
 	err := req.cmd.Start()
 	if err != nil {
 		err = fmt.Errorf("failed to start process: %w", err)
@@ -201,6 +240,40 @@ func (server *traceServer) setFPRegs(req request) response {
 	}
 }
 
+func (server *traceServer) getRegset(req request) response {
+	count, err := getRegset(req.pid, req.regset, req.data)
+	if err != nil {
+		err = fmt.Errorf(
+			"failed to get regset (%d ; %d) from process %d: %w",
+			req.regset,
+			len(req.data),
+			req.pid,
+			err)
+	}
+
+	return response{
+		count: count,
+		err:   err,
+	}
+}
+
+func (server *traceServer) setRegset(req request) response {
+	count, err := setRegset(req.pid, req.regset, req.data)
+	if err != nil {
+		err = fmt.Errorf(
+			"failed to set regset (%d ; %d) for process %d: %w",
+			req.regset,
+			len(req.data),
+			req.pid,
+			err)
+	}
+
+	return response{
+		count: count,
+		err:   err,
+	}
+}
+
 func (server *traceServer) peekUser(req request) response {
 	data, err := peekUserArea(req.pid, req.offset)
 
@@ -318,3 +391,18 @@ func (server *traceServer) getSigInfo(req request) response {
 		err:     err,
 	}
 }
+
+func (server *traceServer) getEventMsg(req request) response {
+	msg, err := syscall.PtraceGetEventMsg(req.pid)
+	if err != nil {
+		err = fmt.Errorf(
+			"failed to get event message from process %d: %w",
+			req.pid,
+			err)
+	}
+
+	return response{
+		eventMsg: msg,
+		err:      err,
+	}
+}
@@ -10,14 +10,31 @@ type Options int
 
 type Event int
 
+// The register set's ELF note type.  See <elf.h>
+type Regset int
+
 const (
 	vmPageSize = 0x1000
 
+	// See <sys/personality.h>
+	addrNoRandomize  = 0x0040000
+	queryPersonality = 0xffffffff
+
 	O_EXITKILL     = Options(unix.PTRACE_O_EXITKILL)
 	O_TRACESYSGOOD = Options(unix.PTRACE_O_TRACESYSGOOD)
 	O_TRACECLONE   = Options(unix.PTRACE_O_TRACECLONE)
+	O_TRACEFORK    = Options(unix.PTRACE_O_TRACEFORK)
+	O_TRACEEXIT    = Options(unix.PTRACE_O_TRACEEXIT)
+	O_TRACEEXEC    = Options(unix.PTRACE_O_TRACEEXEC)
 
 	EVENT_CLONE = Event(unix.PTRACE_EVENT_CLONE)
+	EVENT_FORK  = Event(unix.PTRACE_EVENT_FORK)
+	EVENT_EXIT  = Event(unix.PTRACE_EVENT_EXIT)
+	EVENT_EXEC  = Event(unix.PTRACE_EVENT_EXEC)
+
+	NT_PRSTATUS   = Regset(1)     // UserRegs
+	NT_PRFPREG    = Regset(2)     // UserFPRegs
+	NT_X86_XSTATE = Regset(0x202) // XSAVE area (AVX, MPX, PKRU, etc.)
 )
 
 // This matches user_regs_struct (64bit variant) defined in <sys/user.h>
@@ -90,6 +107,30 @@ func setFPRegs(pid int, in *UserFPRegs) error {
 	return ptracePtr(syscall.PTRACE_SETFPREGS, pid, 0, unsafe.Pointer(in))
 }
 
+// The kernel truncates the iovec's length to the regset's actual size (in
+// bytes).
+func getRegset(pid int, regset Regset, out []byte) (int, error) {
+	iov := unix.Iovec{Base: unsafe.SliceData(out)}
+	iov.SetLen(len(out))
+	err := ptracePtr(
+		unix.PTRACE_GETREGSET,
+		pid,
+		uintptr(regset),
+		unsafe.Pointer(&iov))
+	return int(iov.Len), err
+}
+
+func setRegset(pid int, regset Regset, in []byte) (int, error) {
+	iov := unix.Iovec{Base: unsafe.SliceData(in)}
+	iov.SetLen(len(in))
+	err := ptracePtr(
+		unix.PTRACE_SETREGSET,
+		pid,
+		uintptr(regset),
+		unsafe.Pointer(&iov))
+	return int(iov.Len), err
+}
+
 func peekUserArea(pid int, offset uintptr) (uintptr, error) {
 	// Since we're issuing Syscall6 directly, we need to pass in a valid output
 	// pointer.  See "C library/kernel differences" in ptrace man(2) page for